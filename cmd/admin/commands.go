@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// generatedPasswordBytes is the entropy of generated passwords
+const generatedPasswordBytes = 18
+
+// createAdmin creates an admin account
+func createAdmin(args []string) error {
+	fs := newFlagSet("create-admin", "-email EMAIL [-name NAME] [-password PASSWORD]")
+	email := fs.String("email", "", "Email of the admin (required)")
+	name := fs.String("name", "Administrator", "Name of the admin")
+	password := fs.String("password", "", "Password of the admin; generated and printed when empty")
+	fs.Parse(args)
+
+	if *email == "" {
+		fs.Usage()
+		return errors.New("-email is required")
+	}
+	generated, err := passwordOrGenerate(password)
+	if err != nil {
+		return err
+	}
+
+	return withServices(func(ctx context.Context, s services) error {
+		user, err := s.Users.CreateUser(ctx, &domain.UserCreateRequest{
+			Email:    *email,
+			Password: *password,
+			Name:     *name,
+			Role:     domain.RoleAdmin,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Created admin %s (ID %d)\n", user.Email, user.ID)
+		if generated {
+			fmt.Printf("🔑 Password: %s\n", *password)
+		}
+		return nil
+	})
+}
+
+// resetPassword sets a user's password
+func resetPassword(args []string) error {
+	fs := newFlagSet("reset-password", "-email EMAIL [-password PASSWORD]")
+	email := fs.String("email", "", "Email of the user (required)")
+	password := fs.String("password", "", "New password; generated and printed when empty")
+	fs.Parse(args)
+
+	if *email == "" {
+		fs.Usage()
+		return errors.New("-email is required")
+	}
+	generated, err := passwordOrGenerate(password)
+	if err != nil {
+		return err
+	}
+
+	return withServices(func(ctx context.Context, s services) error {
+		user, err := s.UserRepo.GetByEmail(ctx, normalizeEmail(*email))
+		if err != nil {
+			return err
+		}
+		if err := s.Users.ResetPassword(ctx, user.ID, *password); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Reset the password of %s; every session was signed out\n", user.Email)
+		if generated {
+			fmt.Printf("🔑 Password: %s\n", *password)
+		}
+		return nil
+	})
+}
+
+// deactivateUser deactivates a user and revokes their sessions
+func deactivateUser(args []string) error {
+	fs := newFlagSet("deactivate-user", "-email EMAIL")
+	email := fs.String("email", "", "Email of the user (required)")
+	fs.Parse(args)
+
+	if *email == "" {
+		fs.Usage()
+		return errors.New("-email is required")
+	}
+
+	return withServices(func(ctx context.Context, s services) error {
+		user, err := s.UserRepo.GetByEmail(ctx, normalizeEmail(*email))
+		if err != nil {
+			return err
+		}
+
+		active := false
+		if _, err := s.Users.UpdateUser(ctx, user.ID, &domain.UserUpdateRequest{Active: &active}); err != nil {
+			return err
+		}
+		// Access tokens already issued stay valid until they expire
+		if err := s.Auth.RevokeAllRefreshTokens(ctx, user.ID); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Deactivated %s; every session was signed out\n", user.Email)
+		return nil
+	})
+}
+
+// listUsers prints a page of users
+func listUsers(args []string) error {
+	fs := newFlagSet("list-users", "[-role ROLE] [-include-inactive] [-sort FIELDS] [-offset N] [-limit N]")
+	role := fs.String("role", "", "Only list users with this role")
+	includeInactive := fs.Bool("include-inactive", false, "Also list deactivated users")
+	sort := fs.String("sort", "-created_at", "Sort fields, comma-separated, - for descending")
+	offset := fs.Int("offset", 0, "Number of users to skip")
+	limit := fs.Int("limit", 50, "Maximum number of users to list")
+	fs.Parse(args)
+
+	filter := domain.UserListFilter{Role: *role, IncludeInactive: *includeInactive, Sort: *sort}
+	query, err := filter.Query()
+	if err != nil {
+		return err
+	}
+
+	return withServices(func(ctx context.Context, s services) error {
+		users, total, err := s.Users.ListUsers(ctx, query, filter.Options(), *offset, *limit)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tEMAIL\tNAME\tROLE\tACTIVE\tCREATED")
+		for _, user := range users {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%t\t%s\n",
+				user.ID, user.Email, user.Name, user.Role, user.Active, user.CreatedAt.Format("2006-01-02 15:04"))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		fmt.Printf("\n%d of %d users\n", len(users), total)
+		return nil
+	})
+}
+
+// passwordOrGenerate generates a password when none was given, reporting
+// whether it did
+func passwordOrGenerate(password *string) (bool, error) {
+	if *password != "" {
+		return false, nil
+	}
+	generated, err := randomString(generatedPasswordBytes)
+	if err != nil {
+		return false, err
+	}
+	*password = generated
+	return true, nil
+}
+
+// randomString returns n random bytes encoded as URL-safe base64
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// normalizeEmail returns the email as users store it
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
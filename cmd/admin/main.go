@@ -0,0 +1,125 @@
+// Command admin runs operational tasks against the configured backend
+// without going through the HTTP API:
+//
+//	go run ./cmd/admin <command> [flags]
+//
+// Commands go through the service layer, so they validate input, record
+// audit log entries and publish domain events as the API does.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/bootstrap"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/buildinfo"
+	"go.uber.org/fx"
+)
+
+// commandTimeout bounds starting the services, running a command and
+// stopping the services
+const commandTimeout = time.Minute
+
+// command is a subcommand of the tool
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// commands lists the subcommands in the order usage shows them
+var commands = []command{
+	{"create-admin", "Create an active admin account, whatever the registration mode", createAdmin},
+	{"reset-password", "Set a user's password and sign out every session", resetPassword},
+	{"deactivate-user", "Deactivate a user and sign out every session", deactivateUser},
+	{"list-users", "List users", listUsers},
+	{"rotate-jwt-secret", "Generate a new JWT_SECRET and write it to the .env file", rotateJWTSecret},
+}
+
+func main() {
+	version := flag.Bool("version", false, "Print the version and exit")
+	flag.Usage = usage
+	flag.Parse()
+
+	if *version {
+		fmt.Println("admin", buildinfo.Get())
+		return
+	}
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(flag.Args()[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "❌ Unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// usage prints the available commands
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: admin [--version] <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-18s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun admin <command> -h for the flags of a command.")
+}
+
+// services are the dependencies of the commands
+type services struct {
+	fx.In
+	Users    domain.UserService
+	UserRepo domain.UserRepository
+	Auth     domain.AuthService
+}
+
+// withServices starts the services of the application, runs fn and stops
+// them, which waits for the domain event handlers fn triggered
+func withServices(fn func(ctx context.Context, s services) error) error {
+	var s services
+	app := fx.New(
+		fx.NopLogger,
+		bootstrap.GetCLIModule(),
+		fx.Invoke(func(deps services) { s = deps }),
+	)
+	if err := app.Err(); err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	if err := app.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start: %w", err)
+	}
+	err := fn(ctx, s)
+	if stopErr := app.Stop(ctx); stopErr != nil && err == nil {
+		err = fmt.Errorf("failed to stop: %w", stopErr)
+	}
+	return err
+}
+
+// newFlagSet creates the flag set of a command
+func newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: admin %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// jwtSecretBytes is the entropy of generated JWT secrets
+const jwtSecretBytes = 48
+
+// rotateJWTSecret generates a new JWT_SECRET. Access tokens signed with
+// HS256 and pending email change links are signed with it, so they stop
+// verifying once the servers restart; refresh tokens are stored, not
+// signed, and keep working.
+func rotateJWTSecret(args []string) error {
+	fs := newFlagSet("rotate-jwt-secret", "[-env-file PATH] [-print]")
+	envFile := fs.String("env-file", ".env", "File to write the new secret to")
+	printOnly := fs.Bool("print", false, "Print the new secret instead of writing it, e.g. to store it in a secrets manager")
+	fs.Parse(args)
+
+	secret, err := randomString(jwtSecretBytes)
+	if err != nil {
+		return err
+	}
+
+	if *printOnly {
+		fmt.Println(secret)
+		return nil
+	}
+
+	if err := setEnvVar(*envFile, "JWT_SECRET", secret); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Wrote a new JWT_SECRET to %s\n", *envFile)
+	fmt.Println("⚠️  Restart every server: access tokens and email change links signed with the old secret stop verifying")
+	return nil
+}
+
+// setEnvVar sets a variable of a dotenv file, replacing its assignments or
+// appending one. Comments, other variables and the file mode are kept.
+func setEnvVar(path, name, value string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s does not exist; use -print when the configuration is not read from a file", path)
+		}
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	assignment := name + "=" + value
+	replaced := false
+	for i, line := range lines {
+		key, _, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "export "), "=")
+		if ok && strings.TrimSpace(key) == name {
+			lines[i] = assignment
+			replaced = true
+		}
+	}
+	if !replaced {
+		lines = append(lines, assignment)
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), info.Mode().Perm())
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetEnvVar(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	read := func(t *testing.T, path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("replaces the assignment and keeps the rest", func(t *testing.T) {
+		path := write(t, "# JWT\nJWT_SECRET=old\nJWT_SECRET_FILE=keep\nexport JWT_SECRET = older\nPORT=8080")
+
+		require.NoError(t, setEnvVar(path, "JWT_SECRET", "new"))
+		assert.Equal(t, "# JWT\nJWT_SECRET=new\nJWT_SECRET_FILE=keep\nJWT_SECRET=new\nPORT=8080\n", read(t, path))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+
+	t.Run("appends a missing variable", func(t *testing.T) {
+		path := write(t, "PORT=8080\n")
+
+		require.NoError(t, setEnvVar(path, "JWT_SECRET", "new"))
+		assert.Equal(t, "PORT=8080\nJWT_SECRET=new\n", read(t, path))
+	})
+
+	t.Run("requires the file", func(t *testing.T) {
+		assert.Error(t, setEnvVar(filepath.Join(t.TempDir(), ".env"), "JWT_SECRET", "new"))
+	})
+}
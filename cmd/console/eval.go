@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// readOnlyPrefixes are the prefixes of methods that only read data, which
+// the console calls without -write
+var readOnlyPrefixes = []string{"Get", "List", "Search", "Count", "UnreadCount", "Has", "RoleExists", "Enabled", "Readiness", "Overview", "Authorize", "Validate"}
+
+// contextType is the type of the context.Context parameter the console
+// passes itself
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// errorType is the type of error results
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// errNotAllowed is returned when calling a method that may modify data
+// without -write
+var errNotAllowed = errors.New("may modify data; restart the console with -write to call it")
+
+// evaluator evaluates expressions calling service methods:
+//
+//	users.GetUser(7)
+//	users.UpdateUser(7, {"active": false})
+//
+// Arguments are JSON values decoded into the parameter types; the context
+// is passed by the evaluator.
+type evaluator struct {
+	services map[string]reflect.Value
+	writes   bool
+	out      io.Writer
+}
+
+// newEvaluator creates an evaluator of the fields of services tagged with
+// console:"<name>"
+func newEvaluator(services interface{}, writes bool, out io.Writer) *evaluator {
+	e := &evaluator{services: make(map[string]reflect.Value), writes: writes, out: out}
+
+	v := reflect.ValueOf(services)
+	for i := 0; i < v.NumField(); i++ {
+		if name := v.Type().Field(i).Tag.Get("console"); name != "" {
+			e.services[name] = v.Field(i)
+		}
+	}
+	return e
+}
+
+// Eval evaluates an expression and prints its results as JSON
+func (e *evaluator) Eval(ctx context.Context, expr string) error {
+	expr = strings.TrimSpace(expr)
+	if expr == "help" || strings.HasPrefix(expr, "help ") {
+		return e.help(strings.TrimSpace(strings.TrimPrefix(expr, "help")))
+	}
+
+	service, method, rawArgs, err := parseCall(expr)
+	if err != nil {
+		return err
+	}
+
+	target, ok := e.services[service]
+	if !ok {
+		return fmt.Errorf("unknown service %q; type help to list services", service)
+	}
+	fn := target.MethodByName(method)
+	if !fn.IsValid() {
+		return fmt.Errorf("%s has no method %s; type help %s to list its methods", service, method, service)
+	}
+	if !e.writes && !isReadOnly(method) {
+		return fmt.Errorf("%s.%s %w", service, method, errNotAllowed)
+	}
+
+	args, err := buildArgs(ctx, fn.Type(), rawArgs)
+	if err != nil {
+		return fmt.Errorf("%s.%s: %w", service, method, err)
+	}
+
+	out, err := call(fn, args)
+	if err != nil {
+		return fmt.Errorf("%s.%s: %w", service, method, err)
+	}
+
+	var results []interface{}
+	for _, result := range out {
+		if result.Type() == errorType {
+			if !result.IsNil() {
+				return result.Interface().(error)
+			}
+			continue
+		}
+		results = append(results, result.Interface())
+	}
+
+	for _, result := range results {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.out, string(data))
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(e.out, "ok")
+	}
+	return nil
+}
+
+// call calls fn, turning panics, e.g. on null arguments, into errors so
+// that they don't end the session
+func call(fn reflect.Value, args []reflect.Value) (results []reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn.Call(args), nil
+}
+
+// parseCall splits service.Method(args) into its parts, the arguments as
+// raw JSON values
+func parseCall(expr string) (service, method string, args []json.RawMessage, err error) {
+	open := strings.Index(expr, "(")
+	if open < 0 || !strings.HasSuffix(expr, ")") {
+		return "", "", nil, fmt.Errorf("expected service.Method(args...), got %q", expr)
+	}
+	service, method, ok := strings.Cut(strings.TrimSpace(expr[:open]), ".")
+	if !ok || service == "" || method == "" {
+		return "", "", nil, fmt.Errorf("expected service.Method(args...), got %q", expr)
+	}
+
+	if err := json.Unmarshal([]byte("["+expr[open+1:len(expr)-1]+"]"), &args); err != nil {
+		return "", "", nil, fmt.Errorf("arguments must be JSON values separated by commas: %w", err)
+	}
+	return service, method, args, nil
+}
+
+// buildArgs decodes the arguments of a method, passing ctx first when the
+// method takes a context
+func buildArgs(ctx context.Context, fnType reflect.Type, rawArgs []json.RawMessage) ([]reflect.Value, error) {
+	if fnType.IsVariadic() {
+		return nil, errors.New("variadic methods are not supported")
+	}
+
+	var args []reflect.Value
+	first := 0
+	if fnType.NumIn() > 0 && fnType.In(0) == contextType {
+		args = append(args, reflect.ValueOf(ctx))
+		first = 1
+	}
+
+	if want := fnType.NumIn() - first; len(rawArgs) != want {
+		return nil, fmt.Errorf("takes %d arguments, got %d", want, len(rawArgs))
+	}
+	for i, raw := range rawArgs {
+		arg := reflect.New(fnType.In(first + i))
+		if err := json.Unmarshal(raw, arg.Interface()); err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		args = append(args, arg.Elem())
+	}
+	return args, nil
+}
+
+// isReadOnly reports whether a method only reads data
+func isReadOnly(method string) bool {
+	for _, prefix := range readOnlyPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// help lists the services, or the methods of a service
+func (e *evaluator) help(service string) error {
+	if service == "" {
+		names := make([]string, 0, len(e.services))
+		for name := range e.services {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Fprintln(e.out, "Services:", strings.Join(names, ", "))
+		fmt.Fprintln(e.out, "Call a method with JSON arguments, e.g. users.GetUser(7); the context is passed for you.")
+		fmt.Fprintln(e.out, "Type help <service> to list its methods, exit to quit.")
+		return nil
+	}
+
+	target, ok := e.services[service]
+	if !ok {
+		return fmt.Errorf("unknown service %q", service)
+	}
+	t := target.Type()
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		signature := strings.TrimPrefix(target.Method(i).Type().String(), "func")
+		signature = strings.Replace(signature, "(context.Context, ", "(", 1)
+		signature = strings.Replace(signature, "(context.Context)", "()", 1)
+
+		marker := ""
+		if !isReadOnly(m.Name) {
+			marker = "  [write]"
+		}
+		fmt.Fprintf(e.out, "  %s.%s%s%s\n", service, m.Name, signature, marker)
+	}
+	return nil
+}
+
+// formatError formats errors of evaluated expressions, with the code of
+// domain errors
+func formatError(err error) string {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return fmt.Sprintf("%s: %s", domainErr.Code, domainErr.Message)
+	}
+	return err.Error()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeService records the calls of the evaluator
+type fakeService struct {
+	ctx     context.Context
+	deleted []uint
+}
+
+type fakeItem struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func (s *fakeService) GetItem(ctx context.Context, id uint) (*fakeItem, error) {
+	s.ctx = ctx
+	if id == 0 {
+		return nil, domain.ErrUserNotFound
+	}
+	return &fakeItem{ID: id, Name: "item"}, nil
+}
+
+func (s *fakeService) ListItems(ctx context.Context, filter fakeItem, offset, limit int) ([]fakeItem, int64, error) {
+	return []fakeItem{filter}, int64(offset + limit), nil
+}
+
+func (s *fakeService) GetName(ctx context.Context, item *fakeItem) (string, error) {
+	return item.Name, nil
+}
+
+func (s *fakeService) DeleteItem(ctx context.Context, id uint) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+func newFakeEvaluator(writes bool) (*evaluator, *fakeService, *bytes.Buffer) {
+	service := &fakeService{}
+	out := &bytes.Buffer{}
+	e := newEvaluator(struct {
+		Items  *fakeService `console:"items"`
+		Hidden *fakeService
+	}{service, service}, writes, out)
+	return e, service, out
+}
+
+func TestEvaluatorEval(t *testing.T) {
+	ctx := context.WithValue(context.Background(), struct{}{}, "console")
+
+	t.Run("calls the method with the context and decoded arguments", func(t *testing.T) {
+		e, service, out := newFakeEvaluator(false)
+
+		require.NoError(t, e.Eval(ctx, "items.GetItem(7)"))
+		assert.Equal(t, ctx, service.ctx)
+		assert.JSONEq(t, `{"id": 7, "name": "item"}`, out.String())
+	})
+
+	t.Run("prints every result but the error", func(t *testing.T) {
+		e, _, out := newFakeEvaluator(false)
+
+		require.NoError(t, e.Eval(ctx, `items.ListItems({"name": "a, b"}, 10, 5)`))
+		assert.Equal(t, "[\n  {\n    \"id\": 0,\n    \"name\": \"a, b\"\n  }\n]\n15\n", out.String())
+	})
+
+	t.Run("returns the error of the call", func(t *testing.T) {
+		e, _, _ := newFakeEvaluator(false)
+
+		assert.Equal(t, domain.ErrUserNotFound, e.Eval(ctx, "items.GetItem(0)"))
+	})
+
+	t.Run("only calls methods that read data without writes", func(t *testing.T) {
+		e, service, _ := newFakeEvaluator(false)
+
+		err := e.Eval(ctx, "items.DeleteItem(7)")
+		assert.True(t, errors.Is(err, errNotAllowed))
+		assert.Empty(t, service.deleted)
+
+		e, service, out := newFakeEvaluator(true)
+		require.NoError(t, e.Eval(ctx, "items.DeleteItem(7)"))
+		assert.Equal(t, []uint{7}, service.deleted)
+		assert.Equal(t, "ok\n", out.String())
+	})
+
+	t.Run("rejects invalid calls", func(t *testing.T) {
+		e, _, _ := newFakeEvaluator(true)
+
+		for _, expr := range []string{
+			"items",
+			"items.GetItem",
+			"GetItem(7)",
+			"Hidden.GetItem(7)",
+			"items.Missing()",
+			"items.GetItem()",
+			"items.GetItem(7, 8)",
+			`items.GetItem("7")`,
+			"items.GetItem(7",
+			"items.GetName(null)",
+		} {
+			assert.Error(t, e.Eval(ctx, expr), expr)
+		}
+	})
+
+	t.Run("lists services and methods", func(t *testing.T) {
+		e, _, out := newFakeEvaluator(false)
+
+		require.NoError(t, e.Eval(ctx, "help"))
+		assert.Contains(t, out.String(), "Services: items\n")
+
+		out.Reset()
+		require.NoError(t, e.Eval(ctx, "help items"))
+		assert.Contains(t, out.String(), "  items.GetItem(uint) (*main.fakeItem, error)\n")
+		assert.Contains(t, out.String(), "  items.DeleteItem(uint) error  [write]\n")
+	})
+}
@@ -0,0 +1,154 @@
+// Command console boots the application services against the configured
+// backend and evaluates calls to them, interactively or with -e, to inspect
+// data while debugging:
+//
+//	go run ./cmd/console
+//	console> users.SearchUsers("alice", 0, 10)
+//	go run ./cmd/console -e 'users.GetUser(7)'
+//
+// Only methods that read data can be called unless -write is given. Calls go
+// through the service layer, so writes are validated and audited as they are
+// through the API; with -as they are made, and audited, on behalf of a user.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/bootstrap"
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/buildinfo"
+	"go.uber.org/fx"
+)
+
+// startTimeout bounds starting and stopping the services
+const startTimeout = time.Minute
+
+// services are the services available in the console, under the name of
+// their console tag
+type services struct {
+	fx.In
+	Config   *config.Config
+	UserRepo domain.UserRepository
+
+	Users         domain.UserService         `console:"users"`
+	Auth          domain.AuthService         `console:"auth"`
+	Permissions   domain.PermissionService   `console:"permissions"`
+	Audit         domain.AuditService        `console:"audit"`
+	Settings      domain.UserSettingsService `console:"settings"`
+	Notifications domain.NotificationService `console:"notifications"`
+	Invites       domain.InviteService       `console:"invites"`
+	Projects      domain.ProjectService      `console:"projects"`
+	Orgs          domain.OrganizationService `console:"orgs"`
+	Webhooks      domain.WebhookService      `console:"webhooks"`
+	Search        domain.SearchService       `console:"search"`
+	Stats         domain.StatsService        `console:"stats"`
+	Health        domain.HealthService       `console:"health"`
+	DataExport    domain.DataExportService   `console:"data_export"`
+}
+
+func main() {
+	var (
+		expr    = flag.String("e", "", "Evaluate the expression and exit")
+		writes  = flag.Bool("write", false, "Allow calling methods that may modify data")
+		as      = flag.String("as", "", "Email of the user to act on behalf of, for services checking the caller")
+		timeout = flag.Duration("timeout", 30*time.Second, "Timeout of each call")
+		version = flag.Bool("version", false, "Print the version and exit")
+	)
+	flag.Parse()
+
+	if *version {
+		fmt.Println("console", buildinfo.Get())
+		return
+	}
+
+	var s services
+	app := fx.New(
+		fx.NopLogger,
+		bootstrap.GetCLIModule(),
+		fx.Invoke(func(deps services) { s = deps }),
+	)
+	if err := app.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to initialize: %v\n", err)
+		os.Exit(1)
+	}
+
+	startCtx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	if err := app.Start(startCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to start: %v\n", err)
+		os.Exit(1)
+	}
+
+	base := context.Background()
+	if *as != "" {
+		user, err := s.UserRepo.GetByEmail(startCtx, strings.ToLower(strings.TrimSpace(*as)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to find %s: %s\n", *as, formatError(err))
+			app.Stop(startCtx)
+			os.Exit(1)
+		}
+		base = domain.WithActor(base, domain.Actor{UserID: user.ID, Role: user.Role, UserAgent: "console"})
+	}
+
+	e := newEvaluator(s, *writes, os.Stdout)
+	eval := func(line string) bool {
+		ctx, cancel := context.WithTimeout(base, *timeout)
+		defer cancel()
+		if err := e.Eval(ctx, line); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s\n", formatError(err))
+			return false
+		}
+		return true
+	}
+
+	ok := true
+	if *expr != "" {
+		ok = eval(*expr)
+	} else {
+		repl(s.Config, *writes, eval)
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	if err := app.Stop(stopCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to stop: %v\n", err)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+// repl evaluates the lines read from stdin until exit or end of input
+func repl(cfg *config.Config, writes bool, eval func(line string) bool) {
+	mode, prompt := "read-only", "console> "
+	if writes {
+		mode, prompt = "writes allowed", "console(write)> "
+	}
+	fmt.Printf("🔧 Console connected to the %s database of the %s environment (%s)\n", cfg.Database.Driver, cfg.App.Env, mode)
+	fmt.Println("Type help to list services, exit to quit.")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for {
+		fmt.Print(prompt)
+		if !scanner.Scan() {
+			fmt.Println()
+			return
+		}
+
+		switch line := scanner.Text(); line {
+		case "":
+		case "exit", "quit":
+			return
+		default:
+			eval(line)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+)
+
+// backup backs up the database and applies the retention rules
+func backup(args []string) error {
+	fs, target := newFlagSet("backup", "[-dir DIR] [-keep N] [-max-age DURATION] [-secondary]")
+	keep := fs.Int("keep", 0, "Number of newest backups to keep; 0 keeps every backup")
+	maxAge := fs.Duration("max-age", 0, "Delete backups older than this, e.g. 720h; the newest backup is always kept")
+	fs.Parse(args)
+
+	if *keep < 0 || *maxAge < 0 {
+		return errors.New("-keep and -max-age cannot be negative")
+	}
+	d, s, err := target.target()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	now := time.Now()
+	name := backupName(d.Driver(), now, d.Ext())
+	fmt.Printf("💾 Backing up the %s database to %s...\n", d.Driver(), name)
+
+	size, err := putBackup(ctx, d, s, name)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Wrote %s (%s) in %s\n", name, formatSize(size), time.Since(now).Round(time.Millisecond))
+
+	deleted, err := prune(ctx, s, d.Driver(), retention{Keep: *keep, MaxAge: *maxAge}, now)
+	for _, name := range deleted {
+		fmt.Printf("🗑️  Deleted %s\n", name)
+	}
+	return err
+}
+
+// putBackup streams a backup of the database into the store
+func putBackup(ctx context.Context, d dumper, s store, name string) (int64, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(d.Backup(ctx, pw))
+	}()
+	size, err := s.Put(ctx, name, pr)
+	pr.CloseWithError(err)
+	return size, err
+}
+
+// restore replaces the database with a backup
+func restore(args []string) error {
+	fs, target := newFlagSet("restore", "-force [-name NAME] [-dir DIR] [-secondary]")
+	name := fs.String("name", "", "Backup to restore; the newest backup of the driver by default")
+	force := fs.Bool("force", false, "Confirm replacing the current database (required)")
+	fs.Parse(args)
+
+	d, s, err := target.target()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *name == "" {
+		files, err := listDriver(ctx, s, d.Driver())
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("%w for %s in %s", errNoBackups, d.Driver(), *target.dir)
+		}
+		*name = files[0].Name
+	} else if driver, _, ok := parseBackupName(*name); !ok || driver != d.Driver() {
+		return fmt.Errorf("%s is not a backup of the %s database", *name, d.Driver())
+	}
+
+	if !*force {
+		fs.Usage()
+		return fmt.Errorf("restoring %s replaces the current %s database; stop the servers and pass -force", *name, d.Driver())
+	}
+
+	r, err := s.Open(ctx, *name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	fmt.Printf("♻️  Restoring the %s database from %s...\n", d.Driver(), *name)
+	if err := d.Restore(ctx, r); err != nil {
+		return err
+	}
+	fmt.Println("✅ Restore completed; run cmd/migrate if the backup predates the latest migrations")
+	return nil
+}
+
+// list prints the backups of the database
+func list(args []string) error {
+	fs, target := newFlagSet("list", "[-dir DIR] [-secondary]")
+	fs.Parse(args)
+
+	d, s, err := target.target()
+	if err != nil {
+		return err
+	}
+	files, err := listDriver(context.Background(), s, d.Driver())
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Printf("No %s backups in %s\n", d.Driver(), *target.dir)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCREATED\tSIZE")
+	for _, f := range files {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.Name, formatTime(f.CreatedAt), formatSize(f.Size))
+	}
+	return w.Flush()
+}
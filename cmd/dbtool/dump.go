@@ -0,0 +1,208 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dumper backs up and restores one database
+type dumper interface {
+	// Driver names the database driver, the prefix of backup names
+	Driver() string
+	// Ext is the extension of backup names
+	Ext() string
+	Backup(ctx context.Context, w io.Writer) error
+	// Restore replaces the database with a backup
+	Restore(ctx context.Context, r io.Reader) error
+}
+
+// sqliteDumper backs up a SQLite database file as a gzipped copy
+type sqliteDumper struct {
+	path string
+}
+
+func (d sqliteDumper) Driver() string { return "sqlite" }
+
+func (d sqliteDumper) Ext() string { return "db.gz" }
+
+// Backup checkpoints the WAL into the database file, then copies a
+// consistent snapshot with VACUUM INTO, which servers can keep writing
+// during
+func (d sqliteDumper) Backup(ctx context.Context, w io.Writer) error {
+	if _, err := os.Stat(d.path); err != nil {
+		return fmt.Errorf("database %s: %w", d.path, err)
+	}
+	db, err := openSQLite(d.path)
+	if err != nil {
+		return err
+	}
+	defer closeSQLite(db)
+
+	if err := db.WithContext(ctx).Exec("PRAGMA wal_checkpoint(TRUNCATE)").Error; err != nil {
+		return fmt.Errorf("failed to checkpoint the WAL: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "dbtool-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	snapshot := filepath.Join(dir, "snapshot.db")
+	if err := db.WithContext(ctx).Exec("VACUUM INTO ?", snapshot).Error; err != nil {
+		return fmt.Errorf("failed to copy the database: %w", err)
+	}
+
+	f, err := os.Open(snapshot)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := gzip.NewWriter(w)
+	if _, err := io.Copy(zw, f); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Restore checks the backup, then moves it into place and removes the WAL
+// of the replaced database. Servers must be stopped first, as they keep the
+// replaced file open.
+func (d sqliteDumper) Restore(ctx context.Context, r io.Reader) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("backup is not gzipped: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o755); err != nil {
+		return err
+	}
+
+	// Write next to the database so the rename cannot cross file systems
+	tmp, err := os.CreateTemp(filepath.Dir(d.path), "."+filepath.Base(d.path)+".restore-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, zr)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the backup: %w", err)
+	}
+	if err := checkSQLite(ctx, tmp.Name()); err != nil {
+		return err
+	}
+
+	// A WAL left behind would be replayed onto the restored file
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(d.path + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), d.path)
+}
+
+// checkSQLite runs a quick integrity check of a database file
+func checkSQLite(ctx context.Context, path string) error {
+	db, err := openSQLite(path)
+	if err != nil {
+		return err
+	}
+	defer closeSQLite(db)
+
+	var result string
+	if err := db.WithContext(ctx).Raw("PRAGMA quick_check").Scan(&result).Error; err != nil {
+		return fmt.Errorf("backup is not a SQLite database: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("backup failed the integrity check: %s", result)
+	}
+	return nil
+}
+
+// openSQLite opens a database file without the logging and pool settings of
+// the application
+func openSQLite(path string) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// closeSQLite closes a database opened by openSQLite
+func closeSQLite(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
+
+// postgresDumper backs up a PostgreSQL database with pg_dump in its custom
+// format, which is compressed and restored with pg_restore
+type postgresDumper struct {
+	cfg database.PostgresConfig
+}
+
+func (d postgresDumper) Driver() string { return "postgres" }
+
+func (d postgresDumper) Ext() string { return "dump" }
+
+func (d postgresDumper) Backup(ctx context.Context, w io.Writer) error {
+	cmd, err := d.command(ctx, "pg_dump", "--format=custom", "--no-owner", "--no-privileges")
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = w
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pg_dump failed: %w", err)
+	}
+	return nil
+}
+
+// Restore drops the objects of the backup before recreating them, in a
+// single transaction so a failed restore leaves the database as it was
+func (d postgresDumper) Restore(ctx context.Context, r io.Reader) error {
+	cmd, err := d.command(ctx, "pg_restore", "--clean", "--if-exists", "--no-owner", "--no-privileges",
+		"--single-transaction", "--exit-on-error", "--dbname", d.cfg.DB)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = r
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pg_restore failed: %w", err)
+	}
+	return nil
+}
+
+// command prepares a PostgreSQL client command. The connection settings are
+// passed in the environment, which keeps the password out of the process
+// list.
+func (d postgresDumper) command(ctx context.Context, name string, args ...string) (*exec.Cmd, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s is required to back up PostgreSQL: %w", name, err)
+	}
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"PGHOST="+d.cfg.Host,
+		"PGPORT="+d.cfg.Port,
+		"PGUSER="+d.cfg.User,
+		"PGPASSWORD="+d.cfg.Pass,
+		"PGDATABASE="+d.cfg.DB,
+		"PGSSLMODE="+d.cfg.SSL,
+	)
+	return cmd, nil
+}
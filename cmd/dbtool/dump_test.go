@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteDumper(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "app.db")
+
+	db, err := openSQLite(path)
+	require.NoError(t, err)
+	require.NoError(t, db.Exec("PRAGMA journal_mode=WAL").Error)
+	require.NoError(t, db.Exec("CREATE TABLE items (name TEXT)").Error)
+	require.NoError(t, db.Exec("INSERT INTO items VALUES ('before')").Error)
+
+	// The database stays open, as a running server would keep it
+	d := sqliteDumper{path: path}
+	var backup bytes.Buffer
+	require.NoError(t, d.Backup(ctx, &backup))
+
+	require.NoError(t, db.Exec("INSERT INTO items VALUES ('after')").Error)
+	closeSQLite(db)
+
+	require.NoError(t, d.Restore(ctx, bytes.NewReader(backup.Bytes())))
+	_, err = os.Stat(path + "-wal")
+	assert.True(t, os.IsNotExist(err))
+
+	db, err = openSQLite(path)
+	require.NoError(t, err)
+	defer closeSQLite(db)
+	var names []string
+	require.NoError(t, db.Raw("SELECT name FROM items").Scan(&names).Error)
+	assert.Equal(t, []string{"before"}, names)
+}
+
+func TestSQLiteDumperRejectsInvalidBackups(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "app.db")
+	require.NoError(t, os.WriteFile(path, []byte("current"), 0o600))
+	d := sqliteDumper{path: path}
+
+	assert.Error(t, d.Backup(ctx, &bytes.Buffer{}), "not a database")
+	assert.Error(t, sqliteDumper{path: path + ".missing"}.Backup(ctx, &bytes.Buffer{}))
+	assert.Error(t, d.Restore(ctx, strings.NewReader("plain")))
+
+	// The current database is kept when the backup does not check out
+	var garbage bytes.Buffer
+	zw := gzip.NewWriter(&garbage)
+	zw.Write(bytes.Repeat([]byte("x"), 4096))
+	zw.Close()
+	assert.Error(t, d.Restore(ctx, &garbage))
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "current", string(current))
+}
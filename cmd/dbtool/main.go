@@ -0,0 +1,150 @@
+// Command dbtool backs up and restores the configured SQL database:
+//
+//	go run ./cmd/dbtool <command> [flags]
+//
+// SQLite databases are copied after a WAL checkpoint; PostgreSQL databases
+// are dumped and restored with pg_dump and pg_restore, which must be on the
+// PATH. Backups are written to a local directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/pkg/buildinfo"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+)
+
+// defaultBackupDir is where backups are stored unless -dir is set
+const defaultBackupDir = "./data/backups"
+
+// command is a subcommand of the tool
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// commands lists the subcommands in the order usage shows them
+var commands = []command{
+	{"backup", "Back up the database and prune old backups", backup},
+	{"restore", "Replace the database with a backup", restore},
+	{"list", "List the backups of the database, newest first", list},
+}
+
+func main() {
+	version := flag.Bool("version", false, "Print the version and exit")
+	flag.Usage = usage
+	flag.Parse()
+
+	if *version {
+		fmt.Println("dbtool", buildinfo.Get())
+		return
+	}
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(flag.Args()[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "❌ Unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// usage prints the available commands
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: dbtool [--version] <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun dbtool <command> -h for the flags of a command.")
+}
+
+// targetFlags are the flags every command shares
+type targetFlags struct {
+	dir       *string
+	secondary *bool
+}
+
+// newFlagSet creates the flag set of a command with the shared flags
+func newFlagSet(name, usage string) (*flag.FlagSet, targetFlags) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: dbtool %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs, targetFlags{
+		dir:       fs.String("dir", defaultBackupDir, "Directory of the backups"),
+		secondary: fs.Bool("secondary", false, "Use the DB_SECONDARY_DRIVER database instead of DB_DRIVER"),
+	}
+}
+
+// target resolves the database and the backup store of the shared flags
+func (f targetFlags) target() (dumper, store, error) {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dbConfig := cfg.DatabaseConfig()
+	if *f.secondary {
+		var ok bool
+		if dbConfig, ok = cfg.SecondaryDatabaseConfig(); !ok {
+			return nil, nil, fmt.Errorf("-secondary requires DB_SECONDARY_DRIVER")
+		}
+	}
+
+	d, err := newDumper(dbConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, newLocalStore(*f.dir), nil
+}
+
+// newDumper returns the dumper of a database driver
+func newDumper(cfg database.Config) (dumper, error) {
+	switch cfg.Driver {
+	case "sqlite":
+		return sqliteDumper{path: cfg.SQLite.Path}, nil
+	case "postgres":
+		return postgresDumper{cfg: cfg.Postgres}, nil
+	case "mongo":
+		return nil, fmt.Errorf("dbtool does not back up MongoDB; use mongodump and mongorestore")
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+}
+
+// formatSize prints a byte count for humans
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatTime prints the time of a backup in local time
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupTimeLayout is the time in backup names, which sorts by time
+const backupTimeLayout = "20060102T150405Z"
+
+// errNoBackups is returned when a store holds no backup of a driver
+var errNoBackups = errors.New("no backups found")
+
+// backupFile describes a stored backup
+type backupFile struct {
+	Name      string
+	Driver    string
+	CreatedAt time.Time
+	Size      int64
+}
+
+// store keeps backups. Only a local directory is implemented; a store on
+// object storage would implement the same methods.
+type store interface {
+	// Put stores a backup; a backup is only listed once r is fully written
+	Put(ctx context.Context, name string, r io.Reader) (int64, error)
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	List(ctx context.Context) ([]backupFile, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// backupName returns the name of a backup of driver taken at t
+func backupName(driver string, t time.Time, ext string) string {
+	return fmt.Sprintf("%s-%s.%s", driver, t.UTC().Format(backupTimeLayout), ext)
+}
+
+// parseBackupName returns the driver and time of a backup name; ok is false
+// for files that are not backups, including the temporary files of Put
+func parseBackupName(name string) (driver string, createdAt time.Time, ok bool) {
+	if strings.HasPrefix(name, ".") {
+		return "", time.Time{}, false
+	}
+	driver, rest, found := strings.Cut(name, "-")
+	if !found || len(rest) < len(backupTimeLayout) {
+		return "", time.Time{}, false
+	}
+	createdAt, err := time.Parse(backupTimeLayout, rest[:len(backupTimeLayout)])
+	if err != nil || !strings.HasPrefix(rest[len(backupTimeLayout):], ".") {
+		return "", time.Time{}, false
+	}
+	return driver, createdAt, true
+}
+
+// localStore keeps backups in a directory
+type localStore struct {
+	dir string
+}
+
+// newLocalStore creates a store in dir, which is created on the first backup
+func newLocalStore(dir string) *localStore {
+	return &localStore{dir: dir}
+}
+
+// Put writes to a temporary file renamed into place once complete
+func (s *localStore) Put(ctx context.Context, name string, r io.Reader) (int64, error) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", s.dir, err)
+	}
+	tmp, err := os.CreateTemp(s.dir, "."+name+".*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, name)); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (s *localStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	if name != filepath.Base(name) {
+		return nil, fmt.Errorf("invalid backup name %q", name)
+	}
+	return os.Open(filepath.Join(s.dir, name))
+}
+
+// List returns the backups in the directory, newest first
+func (s *localStore) List(ctx context.Context) ([]backupFile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []backupFile
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		driver, createdAt, ok := parseBackupName(entry.Name())
+		if !ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, backupFile{Name: entry.Name(), Driver: driver, CreatedAt: createdAt, Size: info.Size()})
+	}
+	sortNewestFirst(files)
+	return files, nil
+}
+
+func (s *localStore) Delete(ctx context.Context, name string) error {
+	if name != filepath.Base(name) {
+		return fmt.Errorf("invalid backup name %q", name)
+	}
+	return os.Remove(filepath.Join(s.dir, name))
+}
+
+// sortNewestFirst sorts backups by time, newest first
+func sortNewestFirst(files []backupFile) {
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].CreatedAt.After(files[j].CreatedAt)
+	})
+}
+
+// listDriver returns the backups of a driver, newest first
+func listDriver(ctx context.Context, s store, driver string) ([]backupFile, error) {
+	files, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matching := files[:0]
+	for _, f := range files {
+		if f.Driver == driver {
+			matching = append(matching, f)
+		}
+	}
+	return matching, nil
+}
+
+// retention chooses which backups prune deletes. Zero values disable a rule;
+// the newest backup is always kept.
+type retention struct {
+	// Keep is the number of newest backups kept
+	Keep int
+	// MaxAge deletes backups older than it
+	MaxAge time.Duration
+}
+
+// prune deletes the backups of a driver the retention rules do not keep and
+// returns their names
+func prune(ctx context.Context, s store, driver string, r retention, now time.Time) ([]string, error) {
+	files, err := listDriver(ctx, s, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for i, f := range files {
+		if i == 0 {
+			continue
+		}
+		expired := r.MaxAge > 0 && now.Sub(f.CreatedAt) > r.MaxAge
+		if (r.Keep > 0 && i >= r.Keep) || expired {
+			if err := s.Delete(ctx, f.Name); err != nil {
+				return deleted, fmt.Errorf("failed to delete %s: %w", f.Name, err)
+			}
+			deleted = append(deleted, f.Name)
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackupName(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	name := backupName("sqlite", created, "db.gz")
+	assert.Equal(t, "sqlite-20240301T123000Z.db.gz", name)
+
+	driver, createdAt, ok := parseBackupName(name)
+	require.True(t, ok)
+	assert.Equal(t, "sqlite", driver)
+	assert.True(t, created.Equal(createdAt))
+
+	for _, name := range []string{"notes.txt", "sqlite-latest.db.gz", "sqlite-20240301T123000Z", ".sqlite-20240301T123000Z.db.gz.123"} {
+		_, _, ok := parseBackupName(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "backups")
+	s := newLocalStore(dir)
+
+	files, err := s.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	older := backupName("sqlite", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "db.gz")
+	newer := backupName("sqlite", time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), "db.gz")
+	for _, name := range []string{older, newer} {
+		n, err := s.Put(ctx, name, strings.NewReader("backup"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), n)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), nil, 0o600))
+
+	files, err = s.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, newer, files[0].Name)
+	assert.Equal(t, older, files[1].Name)
+
+	_, err = s.Open(ctx, "../"+older)
+	assert.Error(t, err)
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T) *localStore {
+		s := newLocalStore(t.TempDir())
+		for day := 1; day <= 5; day++ {
+			name := backupName("sqlite", now.AddDate(0, 0, -day), "db.gz")
+			_, err := s.Put(ctx, name, strings.NewReader("backup"))
+			require.NoError(t, err)
+		}
+		_, err := s.Put(ctx, backupName("postgres", now.AddDate(0, 0, -30), "dump"), strings.NewReader("backup"))
+		require.NoError(t, err)
+		return s
+	}
+	remaining := func(t *testing.T, s *localStore) []string {
+		files, err := listDriver(ctx, s, "sqlite")
+		require.NoError(t, err)
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Name)
+		}
+		return names
+	}
+
+	t.Run("keeps the newest backups", func(t *testing.T) {
+		s := setup(t)
+		deleted, err := prune(ctx, s, "sqlite", retention{Keep: 2}, now)
+		require.NoError(t, err)
+		assert.Len(t, deleted, 3)
+		assert.Equal(t, []string{
+			backupName("sqlite", now.AddDate(0, 0, -1), "db.gz"),
+			backupName("sqlite", now.AddDate(0, 0, -2), "db.gz"),
+		}, remaining(t, s))
+	})
+
+	t.Run("deletes backups older than the max age", func(t *testing.T) {
+		s := setup(t)
+		deleted, err := prune(ctx, s, "sqlite", retention{MaxAge: 72 * time.Hour}, now)
+		require.NoError(t, err)
+		assert.Len(t, deleted, 2)
+		assert.Len(t, remaining(t, s), 3)
+	})
+
+	t.Run("always keeps the newest backup", func(t *testing.T) {
+		s := setup(t)
+		_, err := prune(ctx, s, "sqlite", retention{MaxAge: time.Hour}, now)
+		require.NoError(t, err)
+		assert.Equal(t, []string{backupName("sqlite", now.AddDate(0, 0, -1), "db.gz")}, remaining(t, s))
+	})
+
+	t.Run("only prunes the backups of the driver", func(t *testing.T) {
+		s := setup(t)
+		_, err := prune(ctx, s, "sqlite", retention{Keep: 1}, now)
+		require.NoError(t, err)
+		files, err := listDriver(ctx, s, "postgres")
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+	})
+}
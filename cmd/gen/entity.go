@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// fieldType describes how a field type maps to Go, GORM and validation
+type fieldType struct {
+	GoType   string
+	GormTag  string
+	Validate string
+}
+
+// fieldTypes lists the supported field types
+var fieldTypes = map[string]fieldType{
+	"string":  {GoType: "string", GormTag: "size:255", Validate: "max=255"},
+	"text":    {GoType: "string", GormTag: "type:text"},
+	"int":     {GoType: "int"},
+	"int64":   {GoType: "int64"},
+	"uint":    {GoType: "uint"},
+	"float64": {GoType: "float64"},
+	"bool":    {GoType: "bool"},
+	"time":    {GoType: "time.Time"},
+}
+
+// Field is a generated entity field
+type Field struct {
+	Name     string // Go name, e.g. UnitPrice
+	Column   string // column, JSON and bson name, e.g. unit_price
+	GoType   string
+	Required bool
+	Unique   bool
+	Index    bool
+
+	gormTag  string
+	validate string
+}
+
+// Tags returns the struct tags of the model field
+func (f Field) Tags() string {
+	gorm := []string{}
+	if f.gormTag != "" {
+		gorm = append(gorm, f.gormTag)
+	}
+	if f.Required {
+		gorm = append(gorm, "not null")
+	}
+	if f.Unique {
+		gorm = append(gorm, "uniqueIndex")
+	} else if f.Index {
+		gorm = append(gorm, "index")
+	}
+
+	tags := fmt.Sprintf(`json:"%s"`, f.Column)
+	if len(gorm) > 0 {
+		tags += fmt.Sprintf(` gorm:"%s"`, strings.Join(gorm, ";"))
+	}
+	return fmt.Sprintf("`%s bson:\"%s\"`", tags, f.Column)
+}
+
+// CreateTags returns the struct tags of the create request field
+func (f Field) CreateTags() string {
+	rules := []string{}
+	if f.Required {
+		rules = append(rules, "required")
+	}
+	if f.validate != "" {
+		rules = append(rules, f.validate)
+	}
+	if len(rules) == 0 {
+		return fmt.Sprintf("`json:\"%s\"`", f.Column)
+	}
+	return fmt.Sprintf("`json:\"%s\" validate:\"%s\"`", f.Column, strings.Join(rules, ","))
+}
+
+// UpdateTags returns the struct tags of the update request field
+func (f Field) UpdateTags() string {
+	if f.validate == "" {
+		return fmt.Sprintf("`json:\"%s,omitempty\"`", f.Column)
+	}
+	return fmt.Sprintf("`json:\"%s,omitempty\" validate:\"omitempty,%s\"`", f.Column, f.validate)
+}
+
+// Sample returns a Go expression producing a distinct value for the n-th test record
+func (f Field) Sample(n string) string {
+	switch f.GoType {
+	case "string":
+		return fmt.Sprintf(`"%s-" + strconv.Itoa(%s)`, f.Column, n)
+	case "int":
+		return n
+	case "bool":
+		return n + "%2 == 0"
+	case "time.Time":
+		return fmt.Sprintf("time.Unix(int64(%s), 0)", n)
+	default:
+		return fmt.Sprintf("%s(%s)", f.GoType, n)
+	}
+}
+
+// Entity holds the names and fields used by the templates
+type Entity struct {
+	Module string
+
+	Name        string // Product
+	Var         string // product
+	Snake       string // product
+	Plural      string // Products
+	PluralVar   string // products
+	PluralSnake string // products
+	PluralKebab string // products
+	Label       string // product, used in messages
+	PluralLabel string // products
+
+	Fields  []Field
+	Version string // migration version
+}
+
+// HasUnique reports whether any field has a unique constraint
+func (e Entity) HasUnique() bool {
+	for _, f := range e.Fields {
+		if f.Unique {
+			return true
+		}
+	}
+	return false
+}
+
+// TestImports returns the standard packages needed by the generated sample values
+func (e Entity) TestImports() []string {
+	var imports []string
+	for _, pkg := range []struct{ name, goType string }{{"strconv", "string"}, {"time", "time.Time"}} {
+		for _, f := range e.Fields {
+			if f.GoType == pkg.goType {
+				imports = append(imports, pkg.name)
+				break
+			}
+		}
+	}
+	return imports
+}
+
+// QueryFields returns the columns that may be filtered or sorted on
+func (e Entity) QueryFields() string {
+	columns := make([]string, 0, len(e.Fields)+2)
+	for _, f := range e.Fields {
+		columns = append(columns, fmt.Sprintf("%q", f.Column))
+	}
+	columns = append(columns, `"created_at"`, `"updated_at"`)
+	return strings.Join(columns, ", ")
+}
+
+// Indexed returns the fields that need a MongoDB index
+func (e Entity) Indexed() []Field {
+	var fields []Field
+	for _, f := range e.Fields {
+		if f.Unique || f.Index {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// newEntity builds an entity from its name and field specs
+func newEntity(module, name, fields, version string) (*Entity, error) {
+	if !identifierPattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid entity name %q", name)
+	}
+
+	words := splitWords(name)
+	pluralWords := append(append([]string{}, words[:len(words)-1]...), pluralize(words[len(words)-1]))
+
+	e := &Entity{
+		Module:      module,
+		Name:        pascal(words),
+		Var:         camel(words),
+		Snake:       strings.Join(words, "_"),
+		Plural:      pascal(pluralWords),
+		PluralVar:   camel(pluralWords),
+		PluralSnake: strings.Join(pluralWords, "_"),
+		PluralKebab: strings.Join(pluralWords, "-"),
+		Label:       strings.Join(words, " "),
+		PluralLabel: strings.Join(pluralWords, " "),
+		Version:     version,
+	}
+
+	parsed, err := parseFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	e.Fields = parsed
+	return e, nil
+}
+
+// reservedColumns are always generated and cannot be declared as fields
+var reservedColumns = map[string]bool{"id": true, "created_at": true, "updated_at": true}
+
+// parseFields parses a comma separated list of name:type[:option...] specs,
+// where options are required, unique and index
+func parseFields(spec string) ([]Field, error) {
+	var fields []Field
+	seen := map[string]bool{}
+
+	for _, raw := range strings.Split(spec, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		parts := strings.Split(raw, ":")
+		if len(parts) < 2 || !identifierPattern.MatchString(parts[0]) {
+			return nil, fmt.Errorf("invalid field %q (expected name:type[:option...])", raw)
+		}
+
+		typ, ok := fieldTypes[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf("field %q: unsupported type %q", parts[0], parts[1])
+		}
+
+		words := splitWords(parts[0])
+		f := Field{
+			Name:     pascal(words),
+			Column:   strings.Join(words, "_"),
+			GoType:   typ.GoType,
+			gormTag:  typ.GormTag,
+			validate: typ.Validate,
+		}
+		if reservedColumns[f.Column] {
+			return nil, fmt.Errorf("field %q is generated automatically", parts[0])
+		}
+		if seen[f.Column] {
+			return nil, fmt.Errorf("duplicate field %q", parts[0])
+		}
+		seen[f.Column] = true
+
+		for _, option := range parts[2:] {
+			switch option {
+			case "required":
+				f.Required = true
+			case "unique":
+				f.Unique = true
+			case "index":
+				f.Index = true
+			default:
+				return nil, fmt.Errorf("field %q: unknown option %q", parts[0], option)
+			}
+		}
+		fields = append(fields, f)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("at least one field is required")
+	}
+	return fields, nil
+}
+
+// splitWords splits PascalCase, camelCase and snake_case names into lower case words
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(name)
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '_':
+			flush()
+		case unicode.IsUpper(r) && i > 0 &&
+			(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// initialisms are kept upper case in Go names
+var initialisms = map[string]bool{"id": true, "url": true, "api": true, "ip": true, "sku": true, "uuid": true, "http": true}
+
+// pascal joins words into a PascalCase Go name
+func pascal(words []string) string {
+	var b strings.Builder
+	for _, w := range words {
+		if initialisms[w] {
+			b.WriteString(strings.ToUpper(w))
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// camel joins words into a camelCase Go name
+func camel(words []string) string {
+	first := words[0]
+	return first + pascal(words[1:])
+}
+
+// pluralize returns the English plural of a lower case word
+func pluralize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return word[:len(word)-1] + "ies"
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "z"),
+		strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return word + "es"
+	default:
+		return word + "s"
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEntityNames(t *testing.T) {
+	tests := []struct {
+		name                         string
+		wantName, wantVar, wantSnake string
+		wantPlural, wantPluralKebab  string
+	}{
+		{"Product", "Product", "product", "product", "Products", "products"},
+		{"ProductItem", "ProductItem", "productItem", "product_item", "ProductItems", "product-items"},
+		{"order_address", "OrderAddress", "orderAddress", "order_address", "OrderAddresses", "order-addresses"},
+		{"category", "Category", "category", "category", "Categories", "categories"},
+		{"APIKey", "APIKey", "apiKey", "api_key", "APIKeys", "api-keys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := newEntity("example.com/app", tt.name, "title:string", "20240101000000")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, e.Name)
+			assert.Equal(t, tt.wantVar, e.Var)
+			assert.Equal(t, tt.wantSnake, e.Snake)
+			assert.Equal(t, tt.wantPlural, e.Plural)
+			assert.Equal(t, tt.wantPluralKebab, e.PluralKebab)
+		})
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := parseFields("name:string:required, unitPrice:float64, sku:string:unique, published_at:time:index")
+	require.NoError(t, err)
+	require.Len(t, fields, 4)
+
+	assert.Equal(t, "UnitPrice", fields[1].Name)
+	assert.Equal(t, "unit_price", fields[1].Column)
+	assert.Equal(t, "SKU", fields[2].Name)
+	assert.True(t, fields[2].Unique)
+	assert.Equal(t, "time.Time", fields[3].GoType)
+	assert.Equal(t, "`json:\"name\" gorm:\"size:255;not null\" bson:\"name\"`", fields[0].Tags())
+	assert.Equal(t, "`json:\"name\" validate:\"required,max=255\"`", fields[0].CreateTags())
+
+	for _, spec := range []string{"", "name", "name:decimal", "name:string:primary", "id:uint", "a:int,a:int"} {
+		_, err := parseFields(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestRender(t *testing.T) {
+	e, err := newEntity("example.com/app", "Product", "name:string:required,sku:string:unique,price:float64,stock:int,active:bool,released_at:time", "20240101000000")
+	require.NoError(t, err)
+
+	files, err := render(e)
+	require.NoError(t, err)
+	assert.Len(t, files, 9)
+	assert.Contains(t, files, "internal/migration/migrations/20240101000000_create_products_table.go")
+	assert.Contains(t, string(files["internal/domain/product.go"]), "ErrProductExists")
+}
+
+func TestInsertBeforeMarker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bootstrap.go")
+	require.NoError(t, os.WriteFile(path, []byte("\tfx.Options(\n\t\t// gen:modules\n\t)\n"), 0o644))
+
+	for i := 0; i < 2; i++ {
+		inserted, err := insertBeforeMarker(path, moduleMarker, "productModule(),")
+		require.NoError(t, err)
+		assert.True(t, inserted)
+	}
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "\tfx.Options(\n\t\tproductModule(),\n\t\t// gen:modules\n\t)\n", string(content))
+
+	inserted, err := insertBeforeMarker(path, migrationMarker, "x")
+	require.NoError(t, err)
+	assert.False(t, inserted)
+}
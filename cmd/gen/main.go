@@ -0,0 +1,210 @@
+// Command gen scaffolds a CRUD resource following the project layering:
+// domain model, GORM and MongoDB repositories, service, handler, routes,
+// migration and repository tests.
+//
+// Usage:
+//
+//	go run ./cmd/gen -name Product -fields "name:string:required,sku:string:unique,price:float64,active:bool"
+//
+// Field types: string, text, int, int64, uint, float64, bool, time.
+// Field options: required, unique, index.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// Markers in existing files where generated registrations are inserted
+const (
+	moduleMarker    = "// gen:modules"
+	migrationMarker = "// gen:migrations"
+)
+
+// output maps a template to the file it generates
+type output struct {
+	template string
+	path     string
+}
+
+func main() {
+	name := flag.String("name", "", "Entity name, e.g. Product or order_item (required)")
+	fields := flag.String("fields", "", "Comma separated name:type[:option...] field specs (required)")
+	dir := flag.String("dir", ".", "Project root containing go.mod")
+	force := flag.Bool("force", false, "Overwrite existing files")
+	dryRun := flag.Bool("dry-run", false, "Print the files that would be generated without writing them")
+	flag.Parse()
+
+	if *name == "" || *fields == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*dir, *name, *fields, *force, *dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the entity's files under root and registers them
+func run(root, name, fields string, force, dryRun bool) error {
+	module, err := readModulePath(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return err
+	}
+
+	entity, err := newEntity(module, name, fields, time.Now().UTC().Format("20060102150405"))
+	if err != nil {
+		return err
+	}
+
+	files, err := render(entity)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range sortedKeys(files) {
+		target := filepath.Join(root, path)
+		if _, err := os.Stat(target); err == nil && !force {
+			return fmt.Errorf("%s already exists (use -force to overwrite)", path)
+		}
+	}
+
+	if dryRun {
+		for _, path := range sortedKeys(files) {
+			fmt.Printf("📄 %s\n", path)
+		}
+		return nil
+	}
+
+	for _, path := range sortedKeys(files) {
+		target := filepath.Join(root, path)
+		if err := os.WriteFile(target, files[path], 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Created %s\n", path)
+	}
+
+	registrations := []struct {
+		path, marker, line string
+	}{
+		{"internal/bootstrap/bootstrap.go", moduleMarker, entity.Var + "Module(),"},
+		{"internal/migration/registry.go", migrationMarker, "migrator.AddMigration(&migrations.Create" + entity.Plural + "Table{})"},
+	}
+	for _, r := range registrations {
+		inserted, err := insertBeforeMarker(filepath.Join(root, r.path), r.marker, r.line)
+		if err != nil {
+			return err
+		}
+		if inserted {
+			fmt.Printf("✅ Registered in %s\n", r.path)
+		} else {
+			fmt.Printf("⚠️  Marker %q not found in %s, add manually: %s\n", r.marker, r.path, r.line)
+		}
+	}
+
+	fmt.Println("\nNext steps:")
+	fmt.Println("  make migrate   # create the table and permissions")
+	fmt.Println("  make swagger   # document the new endpoints")
+	return nil
+}
+
+// render executes every template for entity and returns gofmt'ed sources keyed by path
+func render(entity *Entity) (map[string][]byte, error) {
+	outputs := []output{
+		{"domain.go.tmpl", "internal/domain/" + entity.Snake + ".go"},
+		{"repo.go.tmpl", "internal/repo/" + entity.Snake + ".go"},
+		{"repo_gorm.go.tmpl", "internal/repo/" + entity.Snake + "_gorm.go"},
+		{"repo_gorm_test.go.tmpl", "internal/repo/" + entity.Snake + "_gorm_test.go"},
+		{"repo_mongo.go.tmpl", "internal/repo/" + entity.Snake + "_mongo.go"},
+		{"service.go.tmpl", "internal/service/" + entity.Snake + ".go"},
+		{"handler.go.tmpl", "internal/http/handler/" + entity.Snake + ".go"},
+		{"module.go.tmpl", "internal/bootstrap/" + entity.Snake + ".go"},
+		{"migration.go.tmpl", "internal/migration/migrations/" + entity.Version + "_create_" + entity.PluralSnake + "_table.go"},
+	}
+
+	funcs := template.FuncMap{
+		"title": func(s string) string { return strings.ToUpper(s[:1]) + s[1:] },
+	}
+	tmpl, err := template.New("gen").Funcs(funcs).ParseFS(templates, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(outputs))
+	for _, o := range outputs {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, o.template, entity); err != nil {
+			return nil, fmt.Errorf("render %s: %w", o.template, err)
+		}
+		source, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("format %s: %w", o.path, err)
+		}
+		files[o.path] = source
+	}
+	return files, nil
+}
+
+// insertBeforeMarker inserts line above the marker comment, matching its indentation.
+// It reports false when the marker is missing and does nothing if line is already present.
+func insertBeforeMarker(path, marker, line string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	text := string(content)
+	idx := strings.Index(text, marker)
+	if idx < 0 {
+		return false, nil
+	}
+	if strings.Contains(text, line) {
+		return true, nil
+	}
+
+	lineStart := strings.LastIndex(text[:idx], "\n") + 1
+	indent := text[lineStart:idx]
+	text = text[:lineStart] + indent + line + "\n" + text[lineStart:]
+	return true, os.WriteFile(path, []byte(text), 0o644)
+}
+
+// readModulePath returns the module path declared in go.mod
+func readModulePath(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("read go.mod: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.TrimSpace(module), nil
+		}
+	}
+	return "", fmt.Errorf("no module directive in %s", path)
+}
+
+// sortedKeys returns the file paths in a stable order
+func sortedKeys(files map[string][]byte) []string {
+	keys := make([]string, 0, len(files))
+	for k := range files {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
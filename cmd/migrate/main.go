@@ -5,11 +5,17 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/luxixing/fx-gin-scaffold/internal/config"
 	"github.com/luxixing/fx-gin-scaffold/internal/domain"
 	"github.com/luxixing/fx-gin-scaffold/internal/migration"
+	"github.com/luxixing/fx-gin-scaffold/internal/migration/seeders"
+	"github.com/luxixing/fx-gin-scaffold/pkg/buildinfo"
 	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/fieldcrypt"
 	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
 )
 
@@ -18,9 +24,29 @@ func main() {
 	var (
 		checkOnly = flag.Bool("check", false, "Check pending migrations without running them")
 		dryRun    = flag.Bool("dry-run", false, "Show what migrations would be executed")
+		status    = flag.Bool("status", false, "Show the status of all registered migrations")
+		force     = flag.Bool("force", false, "Run migrations even if applied ones were modified, with a warning; unverified ones (see -status) can't be checked either way")
+		seed      = flag.Bool("seed", false, "Run the seeders of the environment without migrating")
+		seedOnly  = flag.String("seed-only", "", "Run only the named seeders (comma-separated), in any environment")
+		reseed    = flag.Bool("reseed", false, "Run seeders again even if they already ran")
+		fixtures  = flag.String("fixtures", "", "Load a YAML or JSON fixtures file")
+		fakeUsers = flag.Int("fake-users", 0, "Generate N fake users for load testing")
+		fakeSeed  = flag.Int64("fake-seed", 1, "Random seed of the fake users; the same seed generates the same users")
+		reencrypt = flag.Bool("reencrypt", false, "Re-encrypt encrypted user fields with the current encryption key")
+		secondary = flag.Bool("secondary", false, "Migrate the DB_SECONDARY_DRIVER database, without seeders")
+		copyData  = flag.Bool("copy-data", false, "Copy every row from the -from database to the -to database, migrating the target first")
+		from      = flag.String("from", "", "Driver of the database -copy-data reads (sqlite, postgres, mongo); DB_DRIVER by default")
+		to        = flag.String("to", "", "Driver of the database -copy-data writes (sqlite, postgres, mongo)")
+		batchSize = flag.Int("batch-size", migration.DefaultCopyBatchSize, "Number of rows -copy-data reads at once")
+		version   = flag.Bool("version", false, "Print the version and exit")
 	)
 	flag.Parse()
 
+	if *version {
+		fmt.Println("migrate", buildinfo.Get())
+		return
+	}
+
 	fmt.Println("🔄 Loading configuration...")
 	cfg, err := config.NewConfig()
 	if err != nil {
@@ -39,29 +65,37 @@ func main() {
 		// Continue anyway
 	}
 
+	// Load field encryption keys (duplicated from bootstrap)
+	keyring, err := fieldcrypt.NewKeyring(cfg.FieldEncryptionConfig())
+	if err != nil {
+		fmt.Printf("❌ Failed to load encryption keys: %v\n", err)
+		os.Exit(1)
+	}
+	fieldcrypt.SetDefault(keyring)
+
+	if *copyData {
+		if *from == "" {
+			*from = cfg.Database.Driver
+		}
+		if err := runCopyData(context.Background(), cfg, *from, *to, *batchSize, *dryRun); err != nil {
+			fmt.Printf("❌ Copy failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fmt.Println("🔗 Connecting to database...")
 	
-	// Set table prefix for domain models (duplicated from bootstrap)
-	domain.SetTablePrefix(cfg.Database.TablePrefix)
-	
-	dbConfig := database.Config{
-		Driver: cfg.Database.Driver,
-		SQLite: database.SQLiteConfig{
-			Path: cfg.Database.SQLitePath,
-		},
-		// Add other database configs when needed (commented out for now)
-		// Postgres: database.PostgresConfig{
-		//     Host: cfg.Database.PostgresHost,
-		//     Port: fmt.Sprintf("%d", cfg.Database.PostgresPort),
-		//     User: cfg.Database.PostgresUser,
-		//     Pass: cfg.Database.PostgresPassword,
-		//     DB:   cfg.Database.PostgresDatabase,
-		//     SSL:  cfg.Database.PostgresSSLMode,
-		// },
-		// Mongo: database.MongoConfig{
-		//     URI: cfg.Database.MongoURI,
-		// },
+	// Name domain models as the connection does (duplicated from bootstrap)
+	dbConfig := cfg.DatabaseConfig()
+	if *secondary {
+		var ok bool
+		if dbConfig, ok = cfg.SecondaryDatabaseConfig(); !ok {
+			fmt.Println("❌ -secondary requires DB_SECONDARY_DRIVER")
+			os.Exit(1)
+		}
 	}
+	domain.SetTableNamer(database.NewNamingStrategy(dbConfig))
 	
 	db, err := database.NewConnection(dbConfig)
 	if err != nil {
@@ -81,17 +115,55 @@ func main() {
 		return
 	}
 
+	if *status {
+		if err := showMigrationStatus(ctx, db); err != nil {
+			fmt.Printf("❌ Status failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *dryRun {
 		fmt.Println("🧪 Dry run - showing what would be executed...")
-		if err := showPendingMigrations(ctx, db); err != nil {
+		if err := showPendingMigrations(ctx, db, cfg); err != nil {
 			fmt.Printf("❌ Dry run failed: %v\n", err)
 			os.Exit(1)
 		}
 		return
 	}
 
+	if *reencrypt {
+		if !keyring.Enabled() {
+			fmt.Println("❌ Re-encryption requires ENCRYPTION_KEYS or ENCRYPTION_KEY_FILE")
+			os.Exit(1)
+		}
+		fmt.Printf("🔐 Re-encrypting user fields with key %s...\n", keyring.KeyID())
+		count, err := migration.ReencryptUsers(ctx, db)
+		if err != nil {
+			fmt.Printf("❌ Re-encryption failed after %d user(s): %v\n", count, err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Re-encrypted %d user(s)\n", count)
+		return
+	}
+
+	opts := seedOptions{all: *seed, only: *seedOnly, reseed: *reseed, fixtures: *fixtures, fakeUsers: *fakeUsers, fakeSeed: *fakeSeed}
+	if opts.any() {
+		fmt.Println("🌱 Running seeders...")
+		if err := runSeeders(ctx, db, cfg, opts); err != nil {
+			fmt.Printf("❌ Seeding failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✅ Seeders completed successfully")
+		return
+	}
+
 	fmt.Println("🚀 Running migrations...")
-	if err := migration.RunMigrations(ctx, db, cfg.App.Env); err != nil {
+	run := func() error { return migration.RunMigrations(ctx, db, cfg, *force, *reseed) }
+	if *secondary {
+		run = func() error { return migration.RunSchemaMigrations(ctx, db, *force) }
+	}
+	if err := run(); err != nil {
 		fmt.Printf("❌ Migration failed: %v\n", err)
 		os.Exit(1)
 	}
@@ -130,24 +202,128 @@ func checkPendingMigrations(ctx context.Context, db *database.Connection) error
 	return nil
 }
 
+// showMigrationStatus prints every registered migration with its applied state
+func showMigrationStatus(ctx context.Context, db *database.Connection) error {
+	migrator := migration.NewMigrator(db)
+	migration.RegisterMigrations(migrator)
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tDESCRIPTION\tSTATUS\tEXECUTED AT")
+	fmt.Fprintln(w, "-------\t-----------\t------\t-----------")
+
+	applied, modified, unverified := 0, 0, 0
+	for _, s := range statuses {
+		state, executedAt := "pending", "-"
+		if s.Applied {
+			applied++
+			state = "applied"
+			executedAt = s.ExecutedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		if s.Modified {
+			modified++
+			state = "modified"
+		}
+		if s.Unverified {
+			unverified++
+			state += " (unverified)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Version, s.Description, state, executedAt)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n📊 %d applied, %d pending, %d total\n", applied, len(statuses)-applied, len(statuses))
+	if modified > 0 {
+		fmt.Printf("⚠️  %d applied migration(s) were modified since they ran\n", modified)
+	}
+	if unverified > 0 {
+		fmt.Printf("ℹ️  %d migration(s) are unverified: they have no checksum or were applied before checksums were recorded, so changes to them aren't detected\n", unverified)
+	}
+	return nil
+}
+
+// seedOptions selects the seeders run without migrating
+type seedOptions struct {
+	all       bool
+	only      string
+	reseed    bool
+	fixtures  string
+	fakeUsers int
+	fakeSeed  int64
+}
+
+// any reports whether any seeder was selected
+func (o seedOptions) any() bool {
+	return o.all || o.only != "" || o.fixtures != "" || o.fakeUsers > 0
+}
+
+// runSeeders runs the seeders of the environment, the named seeders, the
+// fixtures file and the fake users, in that order, without running migrations
+func runSeeders(ctx context.Context, db *database.Connection, cfg *config.Config, opts seedOptions) error {
+	migrator := migration.NewMigrator(db)
+	migrator.SetReseed(opts.reseed)
+	migration.RegisterSeeders(migrator, cfg)
+
+	if opts.all {
+		if err := migrator.Seed(ctx, cfg.App.Env); err != nil {
+			return err
+		}
+	}
+
+	var names []string
+	if opts.only != "" {
+		for _, name := range strings.Split(opts.only, ",") {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	if opts.fixtures != "" {
+		seeder, err := seeders.NewFixturesSeeder(opts.fixtures)
+		if err != nil {
+			return err
+		}
+		migrator.AddSeeder(seeder)
+		names = append(names, seeder.Name())
+	}
+	if opts.fakeUsers > 0 {
+		seeder := seeders.NewFakeUsersSeeder(opts.fakeUsers, opts.fakeSeed)
+		migrator.AddSeeder(seeder)
+		names = append(names, seeder.Name())
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+	return migrator.SeedOnly(ctx, names...)
+}
+
 // showPendingMigrations shows what migrations would be executed
-func showPendingMigrations(ctx context.Context, db *database.Connection) error {
+func showPendingMigrations(ctx context.Context, db *database.Connection, cfg *config.Config) error {
 	migrator := migration.NewMigrator(db)
 	migration.RegisterMigrations(migrator)
-	migration.RegisterSeeders(migrator)
+	migration.RegisterSeeders(migrator, cfg)
 
 	fmt.Println("📋 Migrations that would be executed:")
 	if err := checkPendingMigrations(ctx, db); err != nil {
 		return err
 	}
 
+	statuses, err := migrator.SeedStatus(ctx)
+	if err != nil {
+		return err
+	}
+
 	fmt.Println("\n🌱 Seeders that would be executed:")
-	for _, seeder := range migrator.GetSeeders() {
-		env := os.Getenv("APP_ENV")
-		if env == "" {
-			env = "development"
-		}
-		if seeder.ShouldRun(env) {
+	env := cfg.App.Env
+	for i, seeder := range migrator.GetSeeders() {
+		if statuses[i].Executed {
+			fmt.Printf("✔️  Already ran: %s (%s; -reseed runs it again)\n", seeder.Name(), statuses[i].ExecutedAt.Local().Format("2006-01-02 15:04:05"))
+		} else if seeder.ShouldRun(env) {
 			fmt.Printf("🌱 Would run: %s\n", seeder.Name())
 		} else {
 			fmt.Printf("⏭️  Would skip: %s (not for %s environment)\n", seeder.Name(), env)
@@ -155,4 +331,75 @@ func showPendingMigrations(ctx context.Context, db *database.Connection) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// runCopyData copies every row from the database of one driver to the
+// database of another, e.g. to switch from sqlite to postgres. The target
+// is migrated first; a dry run only reads the source.
+func runCopyData(ctx context.Context, cfg *config.Config, from, to string, batchSize int, dryRun bool) error {
+	drivers := []string{"sqlite", "postgres", "mongo"}
+	if !slices.Contains(drivers, from) || !slices.Contains(drivers, to) {
+		return fmt.Errorf("-from and -to must be one of %s", strings.Join(drivers, ", "))
+	}
+	if from == to {
+		return fmt.Errorf("-from and -to are both %s", from)
+	}
+
+	// Name domain models as the connection does (duplicated from bootstrap)
+	domain.SetTableNamer(database.NewNamingStrategy(cfg.DatabaseConfig()))
+
+	fmt.Printf("🔗 Connecting to the %s source and %s target databases...\n", from, to)
+	source, err := database.NewConnection(cfg.DriverDatabaseConfig(from))
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", from, err)
+	}
+	defer source.Close()
+	target, err := database.NewConnection(cfg.DriverDatabaseConfig(to))
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", to, err)
+	}
+	defer target.Close()
+
+	if dryRun {
+		fmt.Println("🧪 Dry run - counting the rows that would be copied...")
+	} else {
+		fmt.Printf("🚀 Migrating the %s database...\n", to)
+		if err := migration.RunSchemaMigrations(ctx, target, false); err != nil {
+			return err
+		}
+		fmt.Printf("📦 Copying data from %s to %s...\n", from, to)
+	}
+
+	table := ""
+	stats, err := migration.CopyData(ctx, migration.NewRepositories(cfg, from, source, logger.Root()), migration.NewRepositories(cfg, to, target, logger.Root()), migration.CopyOptions{
+		BatchSize: batchSize,
+		DryRun:    dryRun,
+		Progress: func(p migration.CopyProgress) {
+			if table != "" && table != p.Table {
+				fmt.Println()
+			}
+			table = p.Table
+			if p.Total > 0 {
+				fmt.Printf("\r   %-20s %d/%d", p.Table, p.Copied, p.Total)
+			} else {
+				fmt.Printf("\r   %-20s %d", p.Table, p.Copied)
+			}
+		},
+	})
+	if table != "" {
+		fmt.Println()
+	}
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, s := range stats {
+		total += s.Copied
+	}
+	if dryRun {
+		fmt.Printf("✅ Would copy %d row(s) from %s to %s\n", total, from, to)
+	} else {
+		fmt.Printf("✅ Copied %d row(s) from %s to %s\n", total, from, to)
+	}
+	return nil
+}
@@ -1,16 +1,44 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
+	"fmt"
 	"os"
 
 	_ "github.com/luxixing/fx-gin-scaffold/docs/swagger" // swagger docs
 	"github.com/luxixing/fx-gin-scaffold/internal/bootstrap"
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/pkg/buildinfo"
 	"go.uber.org/fx"
+	"gopkg.in/yaml.v3"
 )
 
 func main() {
+	var (
+		printConfig  = flag.Bool("print-config", false, "Print the effective configuration with secrets redacted and exit")
+		configFormat = flag.String("config-format", "json", "Output format of -print-config (json, yaml)")
+		version      = flag.Bool("version", false, "Print the version and exit")
+	)
+	flag.Parse()
+
+	if *version {
+		fmt.Println("fx-gin-scaffold", buildinfo.Get())
+		return
+	}
+
+	if *printConfig {
+		if err := printEffectiveConfig(*configFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Build FX options
 	options := []fx.Option{
+		fx.StartTimeout(bootstrap.StartTimeout),
+		fx.StopTimeout(bootstrap.StopTimeout),
 		bootstrap.GetModule(),
 		fx.Invoke(bootstrap.RegisterHooks),
 	}
@@ -24,3 +52,28 @@ func main() {
 	app := fx.New(options...)
 	app.Run()
 }
+
+// printEffectiveConfig loads and validates the configuration and prints it
+func printEffectiveConfig(format string) error {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var out []byte
+	switch format {
+	case "json":
+		out, err = json.MarshalIndent(cfg.Effective(), "", "  ")
+		out = append(out, '\n')
+	case "yaml":
+		out, err = yaml.Marshal(cfg.Effective())
+	default:
+		return fmt.Errorf("unsupported config format: %s (supported: json, yaml)", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(out)
+	return err
+}
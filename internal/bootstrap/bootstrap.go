@@ -2,60 +2,100 @@ package bootstrap
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/luxixing/fx-gin-scaffold/internal/config"
 	"github.com/luxixing/fx-gin-scaffold/internal/domain"
-	"github.com/luxixing/fx-gin-scaffold/internal/http/handler"
-	"github.com/luxixing/fx-gin-scaffold/internal/http/middleware"
-	"github.com/luxixing/fx-gin-scaffold/internal/repo"
-	"github.com/luxixing/fx-gin-scaffold/internal/service"
+	"github.com/luxixing/fx-gin-scaffold/internal/migration"
+	"github.com/luxixing/fx-gin-scaffold/pkg/buildinfo"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
 	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/fieldcrypt"
+	"github.com/luxixing/fx-gin-scaffold/pkg/events"
+	"github.com/luxixing/fx-gin-scaffold/pkg/httpclient"
+	"github.com/luxixing/fx-gin-scaffold/pkg/jwtkeys"
 	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/mailer"
+	"github.com/luxixing/fx-gin-scaffold/pkg/password"
+	"github.com/luxixing/fx-gin-scaffold/pkg/resilience"
+	"github.com/luxixing/fx-gin-scaffold/pkg/search"
+	"github.com/luxixing/fx-gin-scaffold/pkg/signing"
+	"github.com/luxixing/fx-gin-scaffold/pkg/webhook"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
 
 
+// StartTimeout bounds application startup. It leaves time for migrations
+// run on startup, including waiting for another instance to finish them.
+const StartTimeout = 5 * time.Minute
+
+// StopTimeout bounds application shutdown. It leaves time for SHUTDOWN_DELAY
+// and for in-flight requests to finish.
+const StopTimeout = config.MaxShutdownDelay + 30*time.Second
+
 // GetModule returns the complete fx.Option for the entire application
 func GetModule() fx.Option {
 	return fx.Options(
-		// Configuration and Infrastructure
-		fx.Provide(config.NewConfig),
-		fx.Provide(initializeLogger),
-		fx.Provide(initializeDatabase),
-
-		// Repositories
-		fx.Provide(
-			fx.Annotate(
-				repo.NewUserRepository,
-				fx.As(new(domain.UserRepository)),
-			),
-		),
-
-		// Services
-		service.GetModule(),
-
-		// Middleware
-		fx.Provide(middleware.NewJWTMiddleware),
-
-		// Handlers
-		fx.Provide(handler.NewAuthHandler),
-		fx.Provide(handler.NewUserHandler),
-
-		// HTTP server
-		fx.Provide(NewHTTPServer),
+		ConfigModule(),
+		ObservabilityModule(),
+		DatabaseModule(),
+		IntegrationsModule(),
+		RepoModule(),
+		ServiceModule(),
+		TasksModule(),
+
+		// Generated feature modules
+		// gen:modules
+
+		HTTPModule(),
+		decorators(),
+	)
+}
+
+// GetCLIModule returns the services of the application without the HTTP
+// server, scheduled tasks, configuration reloading and startup migrations,
+// for command line tools. Connections are closed when the application stops.
+func GetCLIModule() fx.Option {
+	return fx.Options(
+		ConfigModule(),
+		fx.Module("observability", observabilityProviders()),
+		fx.Module("database", databaseProviders()),
+		IntegrationsModule(),
+		RepoModule(),
+		ServiceModule(),
+		decorators(),
+		fx.Invoke(registerCLIHooks),
 	)
 }
 
+// registerCLIHooks closes the connections of command line tools when they
+// stop
+func registerCLIHooks(lc fx.Lifecycle, db *database.Connection, cacheClient cache.Client, log *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			defer logger.Sync()
+
+			if err := cacheClient.Close(); err != nil {
+				log.Error("error closing cache connections", zap.Error(err))
+			}
+			return db.Close()
+		},
+	})
+}
+
 // RegisterHooks registers application lifecycle hooks
-func RegisterHooks(lc fx.Lifecycle, cfg *config.Config, db *database.Connection, server *http.Server) {
+func RegisterHooks(lc fx.Lifecycle, cfg *config.Config, db *database.Connection, cacheClient cache.Client, server *http.Server, health domain.HealthService, log *zap.Logger) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			return onStart(ctx, cfg, db, server)
+			return onStart(ctx, cfg, db, server, log)
 		},
 		OnStop: func(ctx context.Context) error {
-			return onStop(ctx, db, server)
+			drain(ctx, cfg, health, log)
+			return onStop(ctx, db, cacheClient, server, log)
 		},
 	})
 }
@@ -67,73 +107,402 @@ func initializeLogger(cfg *config.Config) (bool, error) {
 		Level:  cfg.Logger.Level,
 		Format: cfg.Logger.Format,
 		Output: cfg.Logger.Output,
+		Rotation: logger.RotationConfig{
+			MaxSize:    cfg.Logger.MaxSizeMB,
+			MaxBackups: cfg.Logger.MaxBackups,
+			MaxAge:     cfg.Logger.MaxAge,
+		},
+		Sampling: logger.SamplingConfig{
+			Initial:    cfg.Logger.SamplingInitial,
+			Thereafter: cfg.Logger.SamplingThereafter,
+			Tick:       cfg.Logger.SamplingTick,
+		},
+		Levels: cfg.Logger.Levels,
 	})
 	return true, err // Return a dummy bool value for FX
 }
 
-// initializeDatabase creates database connection based on configuration
-func initializeDatabase(cfg *config.Config) (*database.Connection, error) {
-	// Set table prefix for all domain models
-	domain.SetTablePrefix(cfg.Database.TablePrefix)
+// provideLogger provides the global logger once it is initialized, for
+// components taking their logger as a dependency. Code handling a request
+// logs with logger.FromContext instead, to include the request's fields.
+func provideLogger(_ bool) *zap.Logger {
+	return logger.Root()
+}
+
+// watchConfig reloads the configuration while the application runs and
+// applies log level changes, including module levels
+func watchConfig(lc fx.Lifecycle, watcher *config.Watcher, log *zap.Logger) {
+	watcher.Subscribe(func(cfg *config.Config) {
+		if err := logger.SetLevel(cfg.Logger.Level); err != nil {
+			log.Warn("invalid log level", zap.String("level", cfg.Logger.Level), zap.Error(err))
+		}
+		for _, module := range logger.ModuleNames() {
+			if err := logger.SetModuleLevel(module, cfg.Logger.Levels[module]); err != nil {
+				log.Warn("invalid module log level", zap.String("module", module), zap.Error(err))
+			}
+		}
+	})
 
-	dbConfig := database.Config{
-		Driver: cfg.Database.Driver,
-		SQLite: database.SQLiteConfig{
-			Path: cfg.Database.SQLitePath,
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			watcher.Start()
+			return nil
 		},
-		// TODO: Add PostgreSQL configuration when needed
-		// Postgres: database.PostgresConfig{
-		//     Host: cfg.Database.PostgresHost,
-		//     Port: fmt.Sprintf("%d", cfg.Database.PostgresPort),
-		//     User: cfg.Database.PostgresUser,
-		//     Pass: cfg.Database.PostgresPassword,
-		//     DB:   cfg.Database.PostgresDatabase,
-		//     SSL:  cfg.Database.PostgresSSLMode,
-		// },
-		// TODO: Add MongoDB configuration when needed
-		// Mongo: database.MongoConfig{
-		//     URI: cfg.Database.MongoURI,
-		// },
-	}
+		OnStop: func(context.Context) error {
+			watcher.Stop()
+			return nil
+		},
+	})
+}
+
+// initializeEventBus creates the domain event bus. Shutdown waits for
+// asynchronous handlers, such as email sending, to finish.
+func initializeEventBus(lc fx.Lifecycle) *events.Bus {
+	bus := events.NewBus()
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return bus.Wait(ctx)
+		},
+	})
+	return bus
+}
+
+// initializeDatabase creates database connection based on configuration.
+// It depends on the logger so that connection attempts are logged.
+func initializeDatabase(cfg *config.Config, _ bool, _ *fieldcrypt.Keyring) (*database.Connection, error) {
+	// Name Mongo collections and raw SQL tables as GORM does
+	dbConfig := cfg.DatabaseConfig()
+	domain.SetTableNamer(database.NewNamingStrategy(dbConfig))
+
 	return database.NewConnection(dbConfig)
 }
 
+// connections are the connection of each database kind, named so that
+// repositories can be served by either: gorm for sqlite and postgres, mongo
+// for MongoDB. A kind is nil unless DB_DRIVER or DB_SECONDARY_DRIVER uses it;
+// secondary is the DB_SECONDARY_DRIVER one, nil when none is configured.
+type connections struct {
+	fx.Out
+	GORM      *database.Connection `name:"gorm"`
+	Mongo     *database.Connection `name:"mongo"`
+	Secondary *database.Connection `name:"secondary"`
+}
+
+// initializeConnections connects DB_SECONDARY_DRIVER next to the primary
+// database, closing it when the application stops
+func initializeConnections(lc fx.Lifecycle, cfg *config.Config, db *database.Connection, log *zap.Logger) (connections, error) {
+	var conns connections
+	conns.set(cfg.Database.Driver, db)
+
+	dbConfig, ok := cfg.SecondaryDatabaseConfig()
+	if !ok {
+		return conns, nil
+	}
+	secondary, err := database.NewConnection(dbConfig)
+	if err != nil {
+		return connections{}, fmt.Errorf("failed to connect to the secondary database: %w", err)
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return secondary.Close()
+		},
+	})
+	conns.set(dbConfig.Driver, secondary)
+	conns.Secondary = secondary
+
+	repositories := make([]string, 0, len(cfg.Database.RepositoryDrivers))
+	for name, driver := range cfg.Database.RepositoryDrivers {
+		if driver == dbConfig.Driver {
+			repositories = append(repositories, name)
+		}
+	}
+	sort.Strings(repositories)
+	log.Info("secondary database connected", zap.String("driver", dbConfig.Driver), zap.Strings("repositories", repositories))
+	return conns, nil
+}
+
+// set sets the connection of the kind of driver
+func (c *connections) set(driver string, db *database.Connection) {
+	if config.IsGORMDriver(driver) {
+		c.GORM = db
+	} else {
+		c.Mongo = db
+	}
+}
+
+// initializeFieldEncryption loads the keys of encrypted model fields. The
+// database depends on it so no field is read or written before.
+func initializeFieldEncryption(cfg *config.Config, log *zap.Logger) (*fieldcrypt.Keyring, error) {
+	keyring, err := fieldcrypt.NewKeyring(cfg.FieldEncryptionConfig())
+	if err != nil {
+		return nil, err
+	}
+	fieldcrypt.SetDefault(keyring)
+
+	if keyring.Enabled() {
+		log.Info("field encryption enabled", zap.String("key_id", keyring.KeyID()), zap.Strings("keys", keyring.KeyIDs()))
+	}
+	return keyring, nil
+}
+
+// collectDatabaseStats refreshes the connection pool metrics while the
+// application runs, when metrics are enabled
+func collectDatabaseStats(lc fx.Lifecycle, cfg *config.Config, db *database.Connection) {
+	if !cfg.Metrics.Enabled {
+		return
+	}
+
+	collector := database.NewStatsCollector(db, cfg.Metrics.Interval)
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			collector.Start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			collector.Stop()
+			return nil
+		},
+	})
+}
+
+// secondaryDatabase is the DB_SECONDARY_DRIVER connection, nil when none is
+// configured
+type secondaryDatabase struct {
+	fx.In
+	DB *database.Connection `name:"secondary"`
+}
+
+// autoMigrate runs pending migrations and seeders on startup when
+// DB_AUTO_MIGRATE is set, and the migrations on the secondary database. The hook is registered before those starting the
+// server and scheduled tasks, so they only see the migrated schema.
+func autoMigrate(lc fx.Lifecycle, cfg *config.Config, db *database.Connection, secondary secondaryDatabase, log *zap.Logger) {
+	if !cfg.Database.AutoMigrate {
+		return
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			log.Info("running migrations on startup")
+			if err := migration.RunMigrations(logger.ToContext(ctx, log), db, cfg, false, false); err != nil {
+				return fmt.Errorf("auto migration failed: %w", err)
+			}
+			// The secondary database gets the schema, not the seed data, so
+			// repositories can be moved to it
+			if secondary.DB != nil {
+				if err := migration.RunSchemaMigrations(logger.ToContext(ctx, log), secondary.DB, false); err != nil {
+					return fmt.Errorf("auto migration of the secondary database failed: %w", err)
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// initializeCache creates the cache client based on configuration
+func initializeCache(cfg *config.Config) (cache.Client, error) {
+	return cache.NewClient(cache.Config{
+		Addr:         cfg.Redis.Addr,
+		Password:     cfg.Redis.Password,
+		DB:           cfg.Redis.DB,
+		PoolSize:     cfg.Redis.PoolSize,
+		MinIdleConns: cfg.Redis.MinIdleConns,
+		DialTimeout:  cfg.Redis.DialTimeout,
+	})
+}
+
+// initializeMailer creates the mailer based on configuration
+func initializeMailer(cfg *config.Config) (mailer.Mailer, error) {
+	m, err := mailer.NewMailer(mailer.Config{
+		Driver:   cfg.Mail.Driver,
+		From:     cfg.Mail.From,
+		Host:     cfg.Mail.SMTPHost,
+		Port:     cfg.Mail.SMTPPort,
+		Username: cfg.Mail.SMTPUsername,
+		Password: cfg.Mail.SMTPPassword,
+		Timeout:  cfg.Mail.SMTPTimeout,
+	})
+	if err != nil || cfg.Mail.Driver != "smtp" {
+		return m, err
+	}
+
+	// Retry mail the server failed to accept, and fail fast while it is down
+	return mailer.NewResilientMailer(m, resilience.NewPolicy("smtp", cfg.ResilienceConfig())), nil
+}
+
+// initializeHTTPClientFactory creates the connection pool of the HTTP clients
+// of outbound integrations
+func initializeHTTPClientFactory(lc fx.Lifecycle, cfg *config.Config) (*httpclient.Factory, error) {
+	factory, err := httpclient.NewFactory(cfg.HTTPClientConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			factory.CloseIdleConnections()
+			return nil
+		},
+	})
+	return factory, nil
+}
+
+// initializeRequestSigner creates the signer of requests to other services,
+// nil unless SIGNING_KEY_ID is set. Sign a client's requests with
+// httpclient.WithMiddleware(signer.Transport).
+func initializeRequestSigner(cfg *config.Config) *signing.Signer {
+	if cfg.Signing.KeyID == "" {
+		return nil
+	}
+	return signing.NewSigner(cfg.Signing.KeyID, cfg.Signing.Secret)
+}
+
+// initializeRequestVerifier creates the verifier of signed requests from the
+// services in SIGNING_TRUSTED_KEYS, for middleware.RequireSignature. Nonces
+// are kept in the shared cache, so that a request is only accepted once
+// across instances.
+func initializeRequestVerifier(cfg *config.Config, client cache.Client) *signing.Verifier {
+	return signing.NewVerifier(signing.VerifierConfig{
+		Keys:      cfg.Signing.TrustedKeys,
+		Tolerance: cfg.Signing.Tolerance,
+		Nonces:    client,
+	})
+}
+
+// initializeWebhookClient creates the HTTP client that posts webhook
+// deliveries. Failed deliveries are retried by the webhook service, so the
+// client only has circuit breakers.
+func initializeWebhookClient(cfg *config.Config, clients *httpclient.Factory) *webhook.Client {
+	httpClient := clients.Client("webhook", httpclient.WithTimeout(cfg.Webhooks.Timeout), httpclient.WithoutRetry())
+	return webhook.NewClient(httpClient).WithBreakers(cfg.BreakerConfig())
+}
+
+// initializeSearchIndex opens the search index when SEARCH_ENABLED is set.
+// The index is nil otherwise and search falls back to the database.
+func initializeSearchIndex(lc fx.Lifecycle, cfg *config.Config, clients *httpclient.Factory) (search.Index, error) {
+	if !cfg.Search.Enabled {
+		return nil, nil
+	}
+
+	index, err := search.NewIndex(search.Config{
+		Driver:           cfg.Search.Driver,
+		BlevePath:        cfg.Search.BlevePath,
+		ElasticsearchURL: cfg.Search.ElasticsearchURL,
+		Username:         cfg.Search.ElasticsearchUsername,
+		Password:         cfg.Search.ElasticsearchPassword,
+		IndexPrefix:      cfg.Search.IndexPrefix,
+		Timeout:          cfg.Search.Timeout,
+		HTTPClient:       clients.Client("elasticsearch", httpclient.WithTimeout(cfg.Search.Timeout)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return index.Close()
+		},
+	})
+	return index, nil
+}
+
+// initializePasswordHasher creates the password hasher based on configuration
+func initializePasswordHasher(cfg *config.Config) (domain.PasswordHasher, error) {
+	return password.NewHasher(password.Config{
+		Algorithm:  cfg.Password.Algorithm,
+		BcryptCost: cfg.Password.BcryptCost,
+		Argon2: password.Argon2Params{
+			Memory:      cfg.Password.Argon2Memory,
+			Iterations:  cfg.Password.Argon2Iterations,
+			Parallelism: cfg.Password.Argon2Parallelism,
+		},
+	})
+}
+
+// initializeJWTKeys loads the access token signing keys based on configuration
+func initializeJWTKeys(cfg *config.Config) (*jwtkeys.KeySet, error) {
+	return jwtkeys.NewKeySet(jwtkeys.Config{
+		Algorithm:    cfg.JWT.Algorithm,
+		Secret:       cfg.JWT.Secret,
+		KeyFiles:     cfg.JWT.Keys,
+		SigningKeyID: cfg.JWT.SigningKeyID,
+	})
+}
+
 // onStart handles application startup
-func onStart(ctx context.Context, cfg *config.Config, db *database.Connection, server *http.Server) error {
-	zap.L().Info("starting application",
+func onStart(ctx context.Context, cfg *config.Config, db *database.Connection, server *http.Server, log *zap.Logger) error {
+	build := buildinfo.Get()
+	log.Info("starting application",
 		zap.String("env", cfg.App.Env),
 		zap.String("address", cfg.GetAddress()),
+		zap.String("port_source", cfg.Server.PortSource),
+		zap.String("version", build.Version),
+		zap.String("commit", build.Commit),
+		zap.String("build_time", build.BuildTime),
 	)
 
 
 	// Start HTTP server in a goroutine
 	go func() {
-		zap.L().Info("http server starting", zap.String("address", server.Addr))
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			zap.L().Fatal("http server failed to start", zap.Error(err))
+		log.Info("http server starting",
+			zap.String("address", server.Addr),
+			zap.Bool("tls", server.TLSConfig != nil),
+		)
+		var err error
+		if server.TLSConfig != nil {
+			// Certificates come from the TLS config
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal("http server failed to start", zap.Error(err))
 		}
 	}()
 
 	return nil
 }
 
+// drain reports the application as draining and keeps serving for
+// SHUTDOWN_DELAY, so load balancers stop routing requests to it before the
+// servers shut down. RegisterHooks is registered last, so this runs before
+// the other stop hooks.
+func drain(ctx context.Context, cfg *config.Config, health domain.HealthService, log *zap.Logger) {
+	health.Drain()
+	if cfg.Server.ShutdownDelay <= 0 {
+		return
+	}
+
+	log.Info("draining before shutdown", zap.Duration("delay", cfg.Server.ShutdownDelay))
+	select {
+	case <-time.After(cfg.Server.ShutdownDelay):
+	case <-ctx.Done():
+	}
+}
+
 // onStop handles application shutdown
-func onStop(ctx context.Context, db *database.Connection, server *http.Server) error {
-	zap.L().Info("stopping application")
+func onStop(ctx context.Context, db *database.Connection, cacheClient cache.Client, server *http.Server, log *zap.Logger) error {
+	log.Info("stopping application")
 
 	// Shutdown HTTP server gracefully
 	if err := server.Shutdown(ctx); err != nil {
-		zap.L().Error("error shutting down http server", zap.Error(err))
+		log.Error("error shutting down http server", zap.Error(err))
 		return err
 	}
-	zap.L().Info("http server stopped")
+	log.Info("http server stopped")
 
 	// Close database connections
 	if err := db.Close(); err != nil {
-		zap.L().Error("error closing database connections", zap.Error(err))
+		log.Error("error closing database connections", zap.Error(err))
+		return err
+	}
+	log.Info("database connections closed")
+
+	// Close cache connections
+	if err := cacheClient.Close(); err != nil {
+		log.Error("error closing cache connections", zap.Error(err))
 		return err
 	}
-	zap.L().Info("database connections closed")
+	log.Info("cache connections closed")
 
 	// Sync logger before exit
 	logger.Sync()
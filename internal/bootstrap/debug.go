@@ -0,0 +1,42 @@
+package bootstrap
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// newDebugHandler serves the pprof profiles under /debug/pprof/ and the
+// expvar variables at /debug/vars
+func newDebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// serveDebugEndpoints serves the debug endpoints on their own listener when
+// DEBUG_ADDR is set. The address is limited to loopback by the configuration,
+// so the endpoints need no authentication and, unlike on the API server,
+// profiles aren't cut short by a write timeout.
+func serveDebugEndpoints(lc fx.Lifecycle, cfg *config.Config, log *zap.Logger) {
+	if !cfg.Debug.Enabled || cfg.Debug.Addr == "" {
+		return
+	}
+
+	server := &http.Server{
+		Addr:              cfg.Debug.Addr,
+		Handler:           newDebugHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	appendServerHooks(lc, log, "debug server", server)
+}
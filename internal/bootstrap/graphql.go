@@ -0,0 +1,51 @@
+//go:build graphql
+
+package bootstrap
+
+import (
+	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/graphql"
+	"github.com/luxixing/fx-gin-scaffold/internal/http/middleware"
+	"go.uber.org/fx"
+)
+
+// graphqlModule mounts the GraphQL endpoint when GRAPHQL_ENABLED is set
+func graphqlModule() fx.Option {
+	return fx.Options(
+		fx.Provide(graphql.NewResolver),
+		fx.Provide(
+			fx.Annotate(
+				newGraphQLRoutes,
+				fx.ResultTags(`group:"routes"`),
+			),
+		),
+	)
+}
+
+// newGraphQLRoutes registers /graphql, and its playground outside production
+func newGraphQLRoutes(cfg *config.Config, resolver *graphql.Resolver, jwt *middleware.JWTMiddleware) RouteRegistrar {
+	return func(v1 *gin.RouterGroup) {
+		if !cfg.GraphQL.Enabled {
+			return
+		}
+
+		server := graphql.NewServer(cfg, resolver)
+		serve := func(c *gin.Context) {
+			// Anonymous callers, e.g. logging in, are still audited with their address
+			ctx := c.Request.Context()
+			if _, ok := domain.ActorFromContext(ctx); !ok {
+				ctx = domain.WithActor(ctx, domain.Actor{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()})
+			}
+			server.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+		}
+		v1.GET("/graphql", jwt.OptionalAuth(), serve)
+		v1.POST("/graphql", jwt.OptionalAuth(), serve)
+
+		if cfg.GraphQL.Playground && !cfg.IsProduction() {
+			v1.GET("/graphql/playground", gin.WrapH(playground.Handler("GraphQL", v1.BasePath()+"/graphql")))
+		}
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !graphql
+
+package bootstrap
+
+import (
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// graphqlModule reports that GraphQL is unavailable; the server must be built
+// with the graphql tag to serve it
+func graphqlModule() fx.Option {
+	return fx.Invoke(func(cfg *config.Config, log *zap.Logger) {
+		if cfg.GraphQL.Enabled {
+			log.Warn("GRAPHQL_ENABLED is set but the server was built without the graphql tag")
+		}
+	})
+}
@@ -1,29 +1,92 @@
 package bootstrap
 
 import (
+	"context"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
 	"github.com/luxixing/fx-gin-scaffold/internal/http/handler"
 	"github.com/luxixing/fx-gin-scaffold/internal/http/middleware"
+	"github.com/luxixing/fx-gin-scaffold/pkg/buildinfo"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/metrics"
+	"github.com/luxixing/fx-gin-scaffold/pkg/ratelimit"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/swaggo/swag"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme/autocert"
 )
 
+// RouteRegistrar mounts additional routes on the /api/v1 group.
+// Provide one into the "routes" group to add endpoints without editing NewHTTPServer.
+type RouteRegistrar func(v1 *gin.RouterGroup)
+
 // HTTPServerParams holds dependencies for HTTP server
 type HTTPServerParams struct {
 	fx.In
-	Config        *config.Config
-	AuthHandler   *handler.AuthHandler
-	UserHandler   *handler.UserHandler
-	JWTMiddleware *middleware.JWTMiddleware
+	Config          *config.Config
+	ConfigWatcher   *config.Watcher
+	Logger          *zap.Logger
+	Cache           cache.Client
+	AuthHandler     *handler.AuthHandler
+	UserHandler     *handler.UserHandler
+	RoleHandler     *handler.RoleHandler
+	AuditHandler    *handler.AuditHandler
+	WSHandler       *handler.WebSocketHandler
+	EventsHandler   *handler.EventsHandler
+	HealthHandler   *handler.HealthHandler
+	FileHandler     *handler.FileHandler
+	JWKSHandler     *handler.JWKSHandler
+	ProjectHandler  *handler.ProjectHandler
+	OrgHandler      *handler.OrganizationHandler
+	WebhookHandler  *handler.WebhookHandler
+	InviteHandler   *handler.InviteHandler
+	SettingsHandler *handler.SettingsHandler
+	NotifHandler    *handler.NotificationHandler
+	LogLevelHandler *handler.LogLevelHandler
+	StatsHandler    *handler.StatsHandler
+	MetaHandler     *handler.MetaHandler
+	JWTMiddleware   *middleware.JWTMiddleware
+
+	// CertManager provides Let's Encrypt certificates; nil without autocert
+	CertManager *autocert.Manager
+
+	// Routes are registered by feature modules, e.g. those created by cmd/gen
+	Routes []RouteRegistrar `group:"routes"`
+
+	// PanicHook is notified of recovered panics when provided
+	PanicHook middleware.PanicHook `optional:"true"`
+}
+
+// corsConfig returns the default CORS policy from configuration
+func corsConfig(cfg *config.Config) middleware.CORSConfig {
+	return middleware.CORSConfig{
+		AllowedOrigins:   cfg.Server.CORSOrigins,
+		AllowedMethods:   cfg.Server.CORSMethods,
+		AllowedHeaders:   cfg.Server.CORSHeaders,
+		ExposedHeaders:   cfg.Server.CORSExposedHeaders,
+		AllowCredentials: cfg.Server.CORSAllowCredentials,
+		MaxAge:           cfg.Server.CORSMaxAge,
+	}
+}
+
+// bodyLoggerConfig returns the body logging settings from configuration
+func bodyLoggerConfig(cfg *config.Config) middleware.BodyLoggerConfig {
+	return middleware.BodyLoggerConfig{
+		Enabled:  cfg.Logger.Bodies,
+		MaxBytes: cfg.Logger.BodyMaxBytes,
+	}
 }
 
 // NewHTTPServer creates a new HTTP server with Gin
-func NewHTTPServer(p HTTPServerParams) *http.Server {
+func NewHTTPServer(p HTTPServerParams) (*http.Server, error) {
 	cfg := p.Config
 	// Set Gin mode
 	if cfg.IsProduction() {
@@ -31,78 +94,291 @@ func NewHTTPServer(p HTTPServerParams) *http.Server {
 	}
 
 	router := gin.New()
+	// Only trust forwarding headers from the configured proxies, so clients
+	// can't pick the IP they are rate limited by
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, err
+	}
 
 	// Global middleware
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.RequestLogger())
+	router.Use(middleware.Recovery(p.PanicHook))
 
 	// CORS
 	if cfg.Server.EnableCORS {
-		router.Use(corsMiddleware(cfg))
+		cors := middleware.NewCORSMiddleware(corsConfig(cfg))
+		p.ConfigWatcher.Subscribe(func(cfg *config.Config) {
+			cors.Update(corsConfig(cfg))
+		})
+		router.Use(cors.Handler())
 	}
 
-	// Health check
-	router.GET("/health", healthCheck)
+	// Compression; SSE streams are never compressed
+	if cfg.Server.EnableCompression {
+		gz, err := middleware.NewGzipEncoder(cfg.Server.CompressionLevel)
+		if err != nil {
+			return nil, err
+		}
+		router.Use(middleware.Compression(middleware.CompressionConfig{
+			ContentTypes: cfg.Server.CompressionTypes,
+			MinLength:    cfg.Server.CompressionMinLength,
+			Encoders:     []middleware.Encoder{gz},
+		}))
+	}
 
-	// Swagger documentation
-	if cfg.Server.EnableSwagger {
-		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// Timestamps in the requested format and time zone
+	location, err := time.LoadLocation(cfg.Server.ResponseTimezone)
+	if err != nil {
+		return nil, err
+	}
+	router.Use(middleware.TimeFormat(middleware.TimeFormatConfig{
+		Format:   cfg.Server.ResponseTimeFormat,
+		Location: location,
+	}))
+
+	// Enveloped or raw bodies with problem details for errors
+	router.Use(middleware.ResponseFormat(cfg.Server.ResponseFormat))
+
+	// Page links, added before the envelope is converted to the raw format
+	if cfg.Server.PaginationLinks {
+		router.Use(middleware.PaginationLinks())
+	}
+
+	// Request and response bodies in debug logs, toggled by reloading LOG_BODIES
+	bodies := middleware.NewBodyLogger(bodyLoggerConfig(cfg))
+	p.ConfigWatcher.Subscribe(func(cfg *config.Config) {
+		bodies.Update(bodyLoggerConfig(cfg))
+	})
+	router.Use(bodies.Handler())
+
+	// Health checks
+	healthRoutes(router, p.HealthHandler)
+
+	// Metrics, profiling and admin endpoints, unless served by the ops server
+	if cfg.Ops.Addr == "" {
+		opsRoutes(router, cfg, p.JWTMiddleware, p.LogLevelHandler, p.StatsHandler)
+	}
+
+	// Public keys for verifying access tokens in other services
+	router.GET("/.well-known/jwks.json", p.JWKSHandler.Get)
+
+	// API documentation; never served in production and behind basic auth in staging
+	if cfg.SwaggerEnabled() {
+		docs := router.Group("")
+		if cfg.IsStaging() {
+			docs.Use(gin.BasicAuth(gin.Accounts{cfg.Server.SwaggerUsername: cfg.Server.SwaggerPassword}))
+		}
+		docs.GET("/openapi.json", openAPIDoc)
+		docs.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/openapi.json")))
+	}
+
+	// Stored files
+	if cfg.Files.Enabled {
+		router.GET("/files/*filepath", p.JWTMiddleware.OptionalAuth(), p.FileHandler.Serve)
 	}
 
 	// API routes
 	v1 := router.Group("/api/v1")
+
+	// Rate limits and stored responses are kept in the cache, so they hold
+	// across instances when REDIS_ADDR is set
+	if cfg.Server.RateLimitRequests > 0 {
+		v1.Use(middleware.RateLimit(ratelimit.NewLimiter(p.Cache, "rate_limit:", cfg.Server.RateLimitRequests, cfg.Server.RateLimitWindow)))
+	}
+	if cfg.Server.IdempotencyTTL > 0 {
+		v1.Use(middleware.Idempotency(p.Cache, cfg.Server.IdempotencyTTL, p.JWTMiddleware.Subject))
+	}
+
 	{
+		// Server metadata and public configuration for frontends
+		v1.GET("/meta", p.MetaHandler.GetMeta)
+		v1.GET("/meta/config", p.MetaHandler.GetConfig)
+
 		// Auth routes
 		auth := v1.Group("/auth")
 		{
 			auth.POST("/register", p.AuthHandler.Register)
 			auth.POST("/login", p.AuthHandler.Login)
-			auth.POST("/refresh", p.JWTMiddleware.RequireAuth(), p.AuthHandler.RefreshToken)
+			auth.POST("/refresh", p.AuthHandler.RefreshToken)
+			auth.POST("/logout", p.JWTMiddleware.RequireAuth(), p.AuthHandler.Logout)
 			auth.GET("/profile", p.JWTMiddleware.RequireAuth(), p.AuthHandler.GetProfile)
 			auth.PUT("/profile", p.JWTMiddleware.RequireAuth(), p.AuthHandler.UpdateProfile)
+			auth.PATCH("/profile", p.JWTMiddleware.RequireAuth(), p.AuthHandler.PatchProfile)
+			auth.DELETE("/profile", p.JWTMiddleware.RequireAuth(), p.AuthHandler.DeleteAccount)
+			auth.GET("/profile/export", p.JWTMiddleware.RequireAuth(), p.AuthHandler.ExportData)
+			auth.PUT("/password", p.JWTMiddleware.RequireAuth(), p.AuthHandler.ChangePassword)
+			auth.PUT("/email", p.JWTMiddleware.RequireAuth(), p.AuthHandler.RequestEmailChange)
+			auth.GET("/email/confirm", p.AuthHandler.ConfirmEmailChange)
+			auth.GET("/sessions", p.JWTMiddleware.RequireAuth(), p.AuthHandler.ListSessions)
+			auth.DELETE("/sessions/:id", p.JWTMiddleware.RequireAuth(), p.AuthHandler.RevokeSession)
+			auth.GET("/settings", p.JWTMiddleware.RequireAuth(), p.SettingsHandler.GetSettings)
+			auth.PUT("/settings", p.JWTMiddleware.RequireAuth(), p.SettingsHandler.UpdateSettings)
+		}
+
+		// User management routes
+		users := v1.Group("/users")
+		{
+			canRead := p.JWTMiddleware.RequirePermission(domain.PermissionUsersRead)
+			canWrite := p.JWTMiddleware.RequirePermission(domain.PermissionUsersWrite)
+
+			users.GET("", canRead, p.UserHandler.ListUsers)
+			users.GET("/search", canRead, p.UserHandler.SearchUsers)
+			users.GET("/:id", p.JWTMiddleware.RequireOwnerOrPermission(domain.PermissionUsersRead, middleware.ParamOwner("id")), p.UserHandler.GetUser)
+			users.PUT("/:id", canWrite, p.UserHandler.UpdateUser)
+			users.PATCH("/:id", canWrite, p.UserHandler.PatchUser)
+			users.DELETE("/:id", canWrite, p.UserHandler.DeleteUser)
+		}
+
+		// Project routes, limited to the caller's own projects unless granted projects:manage
+		projects := v1.Group("/projects")
+		{
+			canAccess := p.JWTMiddleware.RequireOwnerOrPermission(domain.PermissionProjectsManage, p.ProjectHandler.LoadOwner)
+
+			projects.GET("", p.JWTMiddleware.RequireAuth(), p.ProjectHandler.ListProjects)
+			projects.POST("", p.JWTMiddleware.RequireAuth(), p.ProjectHandler.CreateProject)
+			projects.GET("/:id", canAccess, p.ProjectHandler.GetProject)
+			projects.PUT("/:id", canAccess, p.ProjectHandler.UpdateProject)
+			projects.DELETE("/:id", canAccess, p.ProjectHandler.DeleteProject)
+		}
+
+		// Organization routes; the service checks the caller's role in each organization
+		orgs := v1.Group("/organizations", p.JWTMiddleware.RequireAuth())
+		{
+			orgs.GET("", p.OrgHandler.ListOrganizations)
+			orgs.POST("", p.OrgHandler.CreateOrganization)
+			orgs.GET("/:id", p.OrgHandler.GetOrganization)
+			orgs.PUT("/:id", p.OrgHandler.UpdateOrganization)
+			orgs.DELETE("/:id", p.OrgHandler.DeleteOrganization)
+			orgs.GET("/:id/members", p.OrgHandler.ListMembers)
+			orgs.PUT("/:id/members/:userId", p.OrgHandler.UpdateMemberRole)
+			orgs.DELETE("/:id/members/:userId", p.OrgHandler.RemoveMember)
+			orgs.GET("/:id/invitations", p.OrgHandler.ListInvitations)
+			orgs.POST("/:id/invitations", p.OrgHandler.InviteMember)
+			orgs.DELETE("/:id/invitations/:invitationId", p.OrgHandler.RevokeInvitation)
 		}
+		v1.POST("/invitations/accept", p.JWTMiddleware.RequireAuth(), p.OrgHandler.AcceptInvitation)
 
-		// User management routes (admin only)
-		users := v1.Group("/users", p.JWTMiddleware.RequireAdmin())
+		// Role and permission management routes
+		roles := v1.Group("/roles", p.JWTMiddleware.RequirePermission(domain.PermissionRolesManage))
 		{
-			users.GET("", p.UserHandler.ListUsers)
-			users.GET("/search", p.UserHandler.SearchUsers)
-			users.GET("/:id", p.UserHandler.GetUser)
-			users.PUT("/:id", p.UserHandler.UpdateUser)
-			users.DELETE("/:id", p.UserHandler.DeleteUser)
+			roles.GET("", p.RoleHandler.ListRoles)
+			roles.POST("", p.RoleHandler.CreateRole)
+			roles.PUT("/:name", p.RoleHandler.UpdateRole)
+			roles.DELETE("/:name", p.RoleHandler.DeleteRole)
+		}
+		v1.GET("/permissions", p.JWTMiddleware.RequirePermission(domain.PermissionRolesManage), p.RoleHandler.ListPermissions)
+
+		// Audit log routes
+		v1.GET("/audit-logs", p.JWTMiddleware.RequirePermission(domain.PermissionAuditRead), p.AuditHandler.ListAuditLogs)
+
+		// Webhook routes
+		webhooks := v1.Group("/webhooks", p.JWTMiddleware.RequirePermission(domain.PermissionWebhooksManage))
+		{
+			webhooks.GET("", p.WebhookHandler.ListWebhooks)
+			webhooks.POST("", p.WebhookHandler.CreateWebhook)
+			webhooks.GET("/:id", p.WebhookHandler.GetWebhook)
+			webhooks.PUT("/:id", p.WebhookHandler.UpdateWebhook)
+			webhooks.DELETE("/:id", p.WebhookHandler.DeleteWebhook)
+			webhooks.GET("/:id/deliveries", p.WebhookHandler.ListDeliveries)
+			webhooks.POST("/:id/deliveries/:deliveryId/redeliver", p.WebhookHandler.RedeliverDelivery)
+		}
+
+		// Registration invite routes
+		invites := v1.Group("/invites", p.JWTMiddleware.RequirePermission(domain.PermissionInvitesManage))
+		{
+			invites.GET("", p.InviteHandler.ListInvites)
+			invites.POST("", p.InviteHandler.CreateInvite)
+			invites.POST("/:id/revoke", p.InviteHandler.RevokeInvite)
+		}
+
+		// In-app notifications of the current user
+		notifications := v1.Group("/notifications", p.JWTMiddleware.RequireAuth())
+		{
+			notifications.GET("", p.NotifHandler.ListNotifications)
+			notifications.GET("/unread-count", p.NotifHandler.UnreadCount)
+			notifications.POST("/read-all", p.NotifHandler.MarkAllRead)
+			notifications.POST("/:id/read", p.NotifHandler.MarkRead)
+		}
+
+		// Realtime routes
+		v1.GET("/ws", p.JWTMiddleware.RequireAuthOrQueryToken(), p.WSHandler.Connect)
+		v1.GET("/events", p.JWTMiddleware.RequireAuthOrQueryToken(), p.EventsHandler.Stream)
+
+		// Feature module routes
+		for _, register := range p.Routes {
+			register(v1)
 		}
 	}
 
-	return &http.Server{
+	server := &http.Server{
 		Addr:         cfg.GetAddress(),
 		Handler:      router,
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  60 * time.Second,
+		// Requests log with the application logger plus the fields the
+		// middleware adds to their context
+		BaseContext: func(net.Listener) context.Context {
+			return logger.ToContext(context.Background(), p.Logger)
+		},
+	}
+	if err := configureTLS(server, cfg, p.CertManager); err != nil {
+		return nil, err
 	}
+	return server, nil
 }
 
-// corsMiddleware configures CORS
-func corsMiddleware(cfg *config.Config) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", cfg.Server.CORSOrigins)
-		c.Header("Access-Control-Allow-Methods", cfg.Server.CORSMethods)
-		c.Header("Access-Control-Allow-Headers", cfg.Server.CORSHeaders)
-		c.Header("Access-Control-Allow-Credentials", "true")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusOK)
-			return
-		}
+// healthRoutes mounts the health checks, which both servers serve
+func healthRoutes(router gin.IRouter, health *handler.HealthHandler) {
+	router.GET("/health", healthCheck)
+	router.GET("/health/live", health.Live)
+	router.GET("/health/ready", health.Ready)
+}
 
-		c.Next()
+// opsRoutes mounts the operational endpoints on the ops server, or on the
+// API server when there is none
+func opsRoutes(router gin.IRouter, cfg *config.Config, jwt *middleware.JWTMiddleware, logLevel *handler.LogLevelHandler, stats *handler.StatsHandler) {
+	// Prometheus metrics
+	if cfg.Metrics.Enabled {
+		router.GET(cfg.Metrics.Path, gin.WrapH(metrics.Default.Handler()))
+	}
+
+	// Profiling and expvar endpoints for admins, unless served on DEBUG_ADDR
+	if cfg.Debug.Enabled && cfg.Debug.Addr == "" {
+		router.Any("/debug/*path", jwt.RequireAdmin(), gin.WrapH(newDebugHandler()))
+	}
+
+	// Admin routes
+	admin := router.Group("/api/v1/admin")
+	{
+		admin.GET("/log-level", jwt.RequirePermission(domain.PermissionLogsManage), logLevel.GetLogLevels)
+		admin.PUT("/log-level", jwt.RequirePermission(domain.PermissionLogsManage), logLevel.SetLogLevel)
+		admin.GET("/stats", jwt.RequirePermission(domain.PermissionStatsRead), stats.GetStats)
 	}
 }
 
 // healthCheck provides a simple health check endpoint
 func healthCheck(c *gin.Context) {
+	build := buildinfo.Get()
 	c.JSON(http.StatusOK, gin.H{
-		"status": "ok",
-		"time":   time.Now().UTC(),
+		"status":     "ok",
+		"time":       time.Now().UTC(),
+		"version":    build.Version,
+		"commit":     build.Commit,
+		"build_time": build.BuildTime,
 	})
-}
\ No newline at end of file
+}
+
+// openAPIDoc serves the OpenAPI document generated by `make swagger`
+func openAPIDoc(c *gin.Context) {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(&domain.Error{
+			Code:    domain.ErrCodeNotFound,
+			Message: "API documentation has not been generated",
+		}))
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(doc))
+}
@@ -0,0 +1,262 @@
+package bootstrap
+
+import (
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/http/handler"
+	"github.com/luxixing/fx-gin-scaffold/internal/http/middleware"
+	"github.com/luxixing/fx-gin-scaffold/internal/realtime"
+	"github.com/luxixing/fx-gin-scaffold/internal/repo"
+	"github.com/luxixing/fx-gin-scaffold/internal/service"
+	"github.com/luxixing/fx-gin-scaffold/internal/subscriber"
+	"github.com/luxixing/fx-gin-scaffold/internal/task"
+	"github.com/luxixing/fx-gin-scaffold/internal/validation"
+	"github.com/luxixing/fx-gin-scaffold/pkg/mailer"
+	"go.uber.org/fx"
+)
+
+// The application is assembled from named modules, which appear in fx logs
+// and can be replaced one at a time: an application can list the modules of
+// GetModule with its own module in place of one of them. Invokes run in the
+// order of the modules, so configuration reloading and startup migrations
+// start before the scheduled tasks and the HTTP server.
+
+// ConfigModule provides the configuration and its watcher
+func ConfigModule() fx.Option {
+	return fx.Module("config",
+		fx.Provide(config.NewConfig),
+		fx.Provide(config.NewWatcher),
+	)
+}
+
+// ObservabilityModule provides the logger, and reloads log levels and
+// collects database pool metrics while the application runs
+func ObservabilityModule() fx.Option {
+	return fx.Module("observability",
+		observabilityProviders(),
+		fx.Invoke(watchConfig),
+		fx.Invoke(collectDatabaseStats),
+	)
+}
+
+// observabilityProviders provide the logger
+func observabilityProviders() fx.Option {
+	return fx.Options(
+		fx.Provide(initializeLogger),
+		fx.Provide(provideLogger),
+	)
+}
+
+// DatabaseModule provides the database, cache and search index, and runs
+// migrations on startup when DB_AUTO_MIGRATE is set
+func DatabaseModule() fx.Option {
+	return fx.Module("database",
+		databaseProviders(),
+		fx.Invoke(autoMigrate),
+	)
+}
+
+// databaseProviders provide the database, cache and search index
+func databaseProviders() fx.Option {
+	return fx.Options(
+		fx.Provide(initializeFieldEncryption),
+		fx.Provide(initializeDatabase),
+		fx.Provide(initializeConnections),
+		fx.Provide(initializeCache),
+		fx.Provide(initializeSearchIndex),
+	)
+}
+
+// IntegrationsModule provides the clients of external services: mail,
+// outbound HTTP, request signing and webhooks
+func IntegrationsModule() fx.Option {
+	return fx.Module("integrations",
+		fx.Provide(initializeMailer),
+		fx.Provide(mailer.NewDefaultRenderer),
+		fx.Provide(initializeHTTPClientFactory),
+		fx.Provide(initializeRequestSigner),
+		fx.Provide(initializeRequestVerifier),
+		fx.Provide(initializeWebhookClient),
+	)
+}
+
+// RepoModule provides the repositories, each on the database driver
+// DB_REPOSITORY_DRIVERS sets for it or on DB_DRIVER
+func RepoModule() fx.Option {
+	return fx.Module("repo",
+		fx.Provide(
+			fx.Annotate(
+				repo.NewUserRepository,
+				fx.As(new(domain.UserRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewRefreshTokenRepository,
+				fx.As(new(domain.RefreshTokenRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewRoleRepository,
+				fx.As(new(domain.RoleRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewPermissionRepository,
+				fx.As(new(domain.PermissionRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewAuditLogRepository,
+				fx.As(new(domain.AuditLogRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewProjectRepository,
+				fx.As(new(domain.ProjectRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewOrganizationRepository,
+				fx.As(new(domain.OrganizationRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewMembershipRepository,
+				fx.As(new(domain.MembershipRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewInvitationRepository,
+				fx.As(new(domain.InvitationRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewInviteRepository,
+				fx.As(new(domain.InviteRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewWebhookRepository,
+				fx.As(new(domain.WebhookRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewWebhookDeliveryRepository,
+				fx.As(new(domain.WebhookDeliveryRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewUserSettingRepository,
+				fx.As(new(domain.UserSettingRepository)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewNotificationRepository,
+				fx.As(new(domain.NotificationRepository)),
+			),
+		),
+		fx.Provide(repo.NewTokenBlacklist),
+		fx.Provide(
+			fx.Annotate(
+				repo.NewTxManager,
+				fx.As(new(domain.TxManager)),
+			),
+		),
+	)
+}
+
+// ServiceModule provides the services, with the realtime delivery, domain
+// events, validation and credentials they depend on, and subscribes the
+// domain event subscribers
+func ServiceModule() fx.Option {
+	return fx.Module("service",
+		// Credentials
+		fx.Provide(initializePasswordHasher),
+		fx.Provide(initializeJWTKeys),
+
+		// Realtime
+		fx.Provide(realtime.NewHub),
+		fx.Provide(realtime.NewEventBroker),
+		fx.Provide(realtime.NewNotifier),
+
+		// Domain events
+		fx.Provide(initializeEventBus),
+
+		// Request validation
+		validation.GetModule(),
+
+		// Services
+		service.GetModule(),
+
+		// Domain event subscribers
+		subscriber.GetModule(),
+	)
+}
+
+// TasksModule provides the scheduled tasks and starts the scheduler
+func TasksModule() fx.Option {
+	return fx.Module("tasks", task.GetModule())
+}
+
+// HTTPModule provides the middleware, handlers and the HTTP server, and
+// serves the HTTPS redirect, ops and debug endpoints when configured
+func HTTPModule() fx.Option {
+	return fx.Module("http",
+		// Middleware
+		fx.Provide(middleware.NewJWTMiddleware),
+
+		// Handlers
+		fx.Provide(handler.NewAuthHandler),
+		fx.Provide(handler.NewUserHandler),
+		fx.Provide(handler.NewRoleHandler),
+		fx.Provide(handler.NewAuditHandler),
+		fx.Provide(handler.NewWebSocketHandler),
+		fx.Provide(handler.NewEventsHandler),
+		fx.Provide(handler.NewHealthHandler),
+		fx.Provide(handler.NewFileHandler),
+		fx.Provide(handler.NewJWKSHandler),
+		fx.Provide(handler.NewProjectHandler),
+		fx.Provide(handler.NewOrganizationHandler),
+		fx.Provide(handler.NewWebhookHandler),
+		fx.Provide(handler.NewInviteHandler),
+		fx.Provide(handler.NewSettingsHandler),
+		fx.Provide(handler.NewNotificationHandler),
+		fx.Provide(handler.NewLogLevelHandler),
+		fx.Provide(handler.NewStatsHandler),
+		fx.Provide(handler.NewMetaHandler),
+
+		// GraphQL endpoint (graphql build tag)
+		graphqlModule(),
+
+		// Server
+		fx.Provide(newCertManager),
+		fx.Provide(NewHTTPServer),
+		fx.Invoke(serveHTTPRedirect),
+		fx.Invoke(serveOpsEndpoints),
+		fx.Invoke(serveDebugEndpoints),
+	)
+}
+
+// decorators wrap dependencies for the whole application. A decorator only
+// applies within the module it is declared in, so they are declared at the
+// root rather than in the module providing what they wrap; applications add
+// their own with fx.Decorate next to GetModule the same way.
+func decorators() fx.Option {
+	return fx.Options(
+		// Record latency and error metrics whatever the repository backend
+		fx.Decorate(repo.NewInstrumentedUserRepository),
+	)
+}
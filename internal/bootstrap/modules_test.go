@@ -0,0 +1,15 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+)
+
+// TestModulesValidate tests that the modules provide every dependency of
+// the server and of command line tools
+func TestModulesValidate(t *testing.T) {
+	assert.NoError(t, fx.ValidateApp(GetModule(), fx.Invoke(RegisterHooks)))
+	assert.NoError(t, fx.ValidateApp(GetCLIModule()))
+}
@@ -0,0 +1,80 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/http/handler"
+	"github.com/luxixing/fx-gin-scaffold/internal/http/middleware"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// OpsServerParams holds dependencies for the ops server
+type OpsServerParams struct {
+	fx.In
+	Lifecycle       fx.Lifecycle
+	Config          *config.Config
+	HealthHandler   *handler.HealthHandler
+	LogLevelHandler *handler.LogLevelHandler
+	StatsHandler    *handler.StatsHandler
+	JWTMiddleware   *middleware.JWTMiddleware
+	Logger          *zap.Logger
+
+	// PanicHook is notified of recovered panics when provided
+	PanicHook middleware.PanicHook `optional:"true"`
+}
+
+// serveOpsEndpoints serves the health checks, metrics, profiling and admin
+// endpoints on OPS_ADDR when set, so they can be kept off the public port.
+// The API server then only serves the health checks of these.
+func serveOpsEndpoints(p OpsServerParams) {
+	cfg := p.Config
+	if cfg.Ops.Addr == "" {
+		return
+	}
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.RequestLogger())
+	router.Use(middleware.Recovery(p.PanicHook))
+
+	healthRoutes(router, p.HealthHandler)
+	opsRoutes(router, cfg, p.JWTMiddleware, p.LogLevelHandler, p.StatsHandler)
+
+	appendServerHooks(p.Lifecycle, p.Logger, "ops server", &http.Server{
+		Addr:              cfg.Ops.Addr,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	})
+}
+
+// appendServerHooks starts an auxiliary server with the application and shuts
+// it down on stop. Listening happens on start so a port in use fails startup.
+func appendServerHooks(lc fx.Lifecycle, log *zap.Logger, name string, server *http.Server) {
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			listener, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+
+			go func() {
+				log.Info(name+" starting", zap.String("address", server.Addr))
+				if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+					log.Error(name+" failed", zap.Error(err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return server.Shutdown(ctx)
+		},
+	})
+}
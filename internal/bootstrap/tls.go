@@ -0,0 +1,90 @@
+package bootstrap
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+)
+
+// newCertManager creates the Let's Encrypt certificate manager when
+// TLS_AUTOCERT_DOMAINS is set, and returns nil otherwise. Certificates are
+// cached in TLS_AUTOCERT_CACHE_DIR so restarts don't hit the rate limits.
+func newCertManager(cfg *config.Config) *autocert.Manager {
+	if len(cfg.Server.TLSAutocertDomains) == 0 {
+		return nil
+	}
+
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(cfg.Server.TLSAutocertDomains...),
+		Cache:      autocert.DirCache(cfg.Server.TLSAutocertCacheDir),
+		Email:      cfg.Server.TLSAutocertEmail,
+	}
+}
+
+// configureTLS makes server serve HTTPS and HTTP/2 with the certificate
+// manager or the configured certificate, and leaves it serving plain HTTP
+// when neither is set
+func configureTLS(server *http.Server, cfg *config.Config, certs *autocert.Manager) error {
+	switch {
+	case certs != nil:
+		server.TLSConfig = certs.TLSConfig()
+	case cfg.Server.TLSCertFile != "":
+		cert, err := tls.LoadX509KeyPair(cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	default:
+		return nil
+	}
+
+	server.TLSConfig.MinVersion = tls.VersionTLS12
+	return http2.ConfigureServer(server, nil)
+}
+
+// serveHTTPRedirect redirects plain HTTP requests to HTTPS on
+// HTTP_REDIRECT_ADDR. With autocert it also answers the HTTP-01 challenges,
+// which Let's Encrypt sends to port 80.
+func serveHTTPRedirect(lc fx.Lifecycle, cfg *config.Config, certs *autocert.Manager, log *zap.Logger) {
+	if cfg.Server.HTTPRedirectAddr == "" {
+		return
+	}
+
+	handler := httpsRedirect(cfg.Server.Port)
+	if certs != nil {
+		handler = certs.HTTPHandler(handler)
+	}
+	appendServerHooks(lc, log, "http redirect server", &http.Server{
+		Addr:              cfg.Server.HTTPRedirectAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	})
+}
+
+// httpsRedirect redirects requests to the same URL on the HTTPS port
+func httpsRedirect(port int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = strings.Trim(r.Host, "[]")
+		}
+		if port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(port))
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
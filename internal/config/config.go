@@ -2,33 +2,109 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/caarlos0/env/v10"
 	"github.com/joho/godotenv"
-	"go.uber.org/zap"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/zap/zapcore"
 )
 
 // Config holds all application configuration
 type Config struct {
-	App      AppConfig      `json:"app"`
-	Database DatabaseConfig `json:"database"`
-	JWT      JWTConfig      `json:"jwt"`
-	Logger   LoggerConfig   `json:"logger"`
-	Server   ServerConfig   `json:"server"`
+	Accounts      AccountsConfig      `json:"accounts"`
+	App           AppConfig           `json:"app"`
+	Cache         CacheConfig         `json:"cache"`
+	Database      DatabaseConfig      `json:"database"`
+	Debug         DebugConfig         `json:"debug"`
+	Encryption    EncryptionConfig    `json:"encryption"`
+	Features      FeaturesConfig      `json:"features"`
+	Files         FilesConfig         `json:"files"`
+	GraphQL       GraphQLConfig       `json:"graphql"`
+	HTTPClient    HTTPClientConfig    `json:"http_client"`
+	JWT           JWTConfig           `json:"jwt"`
+	Logger        LoggerConfig        `json:"logger"`
+	Mail          MailConfig          `json:"mail"`
+	Metrics       MetricsConfig       `json:"metrics"`
+	Notifications NotificationsConfig `json:"notifications"`
+	Ops           OpsConfig           `json:"ops"`
+	Orgs          OrgsConfig          `json:"orgs"`
+	Password      PasswordConfig      `json:"password"`
+	Redis         RedisConfig         `json:"redis"`
+	Registration  RegistrationConfig  `json:"registration"`
+	Resilience    ResilienceConfig    `json:"resilience"`
+	Roles         RolesConfig         `json:"roles"`
+	Scheduler     SchedulerConfig     `json:"scheduler"`
+	Search        SearchConfig        `json:"search"`
+	Seed          SeedConfig          `json:"seed"`
+	Server        ServerConfig        `json:"server"`
+	Signing       SigningConfig       `json:"signing"`
+	Webhooks      WebhooksConfig      `json:"webhooks"`
+}
+
+// AccountsConfig contains user account settings
+type AccountsConfig struct {
+	// DeletionGracePeriod is how long a deleted account can still be restored
+	// by logging in before it is purged
+	DeletionGracePeriod time.Duration `json:"deletion_grace_period" env:"ACCOUNT_DELETION_GRACE_PERIOD" envDefault:"720h"`
+	// LoginMaxAttempts failed logins to an account lock it for
+	// LoginLockoutDuration after the first failure; 0 disables the lockout
+	LoginMaxAttempts     int           `json:"login_max_attempts" env:"LOGIN_MAX_ATTEMPTS" envDefault:"5"`
+	LoginLockoutDuration time.Duration `json:"login_lockout_duration" env:"LOGIN_LOCKOUT_DURATION" envDefault:"15m"`
 }
 
 // AppConfig contains general application settings
 type AppConfig struct {
 	Env   string `json:"env" env:"APP_ENV" envDefault:"development"`
 	Debug bool   `json:"debug" env:"APP_DEBUG" envDefault:"false"`
+	// URL is the public base URL used in links sent to users
+	URL string `json:"url" env:"APP_URL" envDefault:"http://localhost:8080"`
+	// ConfigWatchInterval polls .env for changes to reload; 0s only reloads on SIGHUP
+	ConfigWatchInterval time.Duration `json:"config_watch_interval" env:"CONFIG_WATCH_INTERVAL" envDefault:"0s"`
+}
+
+// CacheConfig contains response caching settings. Each TTL enables caching
+// for its endpoint; zero disables it. Without Redis the cache is per process,
+// so other instances only see changes once entries expire.
+type CacheConfig struct {
+	UserTTL         time.Duration `json:"user_ttl" env:"CACHE_USER_TTL" envDefault:"0s"`
+	UserListTTL     time.Duration `json:"user_list_ttl" env:"CACHE_USER_LIST_TTL" envDefault:"0s"`
+	UserSettingsTTL time.Duration `json:"user_settings_ttl" env:"CACHE_USER_SETTINGS_TTL" envDefault:"0s"`
 }
 
 // DatabaseConfig contains database connection settings
 type DatabaseConfig struct {
-	Driver     string `json:"driver" env:"DB_DRIVER" envDefault:"sqlite"`
+	Driver      string `json:"driver" env:"DB_DRIVER" envDefault:"sqlite"`
 	TablePrefix string `json:"table_prefix" env:"DB_TABLE_PREFIX" envDefault:"fx_"`
+	// SecondaryDriver connects a database of the other kind (GORM or MongoDB)
+	// too, e.g. while migrating data stores. RepositoryDrivers moves
+	// repositories to it by name, e.g. audit_logs=mongo; the others use Driver.
+	SecondaryDriver   string            `json:"secondary_driver" env:"DB_SECONDARY_DRIVER"`
+	RepositoryDrivers map[string]string `json:"repository_drivers" env:"DB_REPOSITORY_DRIVERS" envKeyValSeparator:"="`
+	// SingularTables names tables and collections without pluralizing
+	SingularTables bool `json:"singular_tables" env:"DB_SINGULAR_TABLES" envDefault:"false"`
+	// ColumnNames overrides column names, e.g. users.avatar_url:avatar
+	ColumnNames map[string]string `json:"column_names" env:"DB_COLUMN_NAMES"`
+
+	// Connection pool (0 uses the driver default)
+	MaxOpenConns    int           `json:"max_open_conns" env:"DB_MAX_OPEN_CONNS" envDefault:"0"`
+	MaxIdleConns    int           `json:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" envDefault:"0"`
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" envDefault:"0s"`
+	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" envDefault:"0s"`
+
+	// Startup connection retry (0s max wait disables retries)
+	ConnectMaxWait        time.Duration `json:"connect_max_wait" env:"DB_CONNECT_MAX_WAIT" envDefault:"30s"`
+	ConnectInitialBackoff time.Duration `json:"connect_initial_backoff" env:"DB_CONNECT_INITIAL_BACKOFF" envDefault:"500ms"`
+	ConnectMaxBackoff     time.Duration `json:"connect_max_backoff" env:"DB_CONNECT_MAX_BACKOFF" envDefault:"10s"`
+
+	// AutoMigrate runs pending migrations and seeders on startup
+	AutoMigrate bool `json:"auto_migrate" env:"DB_AUTO_MIGRATE" envDefault:"false"`
 
 	// SQLite
 	SQLitePath string `json:"sqlite_path" env:"SQLITE_PATH" envDefault:"./data/app.db"`
@@ -37,20 +113,99 @@ type DatabaseConfig struct {
 	PostgresHost     string `json:"postgres_host" env:"POSTGRES_HOST" envDefault:"localhost"`
 	PostgresPort     int    `json:"postgres_port" env:"POSTGRES_PORT" envDefault:"5432"`
 	PostgresUser     string `json:"postgres_user" env:"POSTGRES_USER" envDefault:"postgres"`
-	PostgresPassword string `json:"postgres_password" env:"POSTGRES_PASSWORD" envDefault:""`
+	PostgresPassword string `json:"postgres_password" env:"POSTGRES_PASSWORD" envDefault:"" redact:"secret"`
 	PostgresDatabase string `json:"postgres_database" env:"POSTGRES_DATABASE" envDefault:"fx_gin_scaffold"`
 	PostgresSSLMode  string `json:"postgres_sslmode" env:"POSTGRES_SSLMODE" envDefault:"disable"`
 	PostgresTimezone string `json:"postgres_timezone" env:"POSTGRES_TIMEZONE" envDefault:"UTC"`
+	// Match whole words with full-text search when searching users
+	PostgresFullTextSearch bool `json:"postgres_full_text_search" env:"POSTGRES_FULL_TEXT_SEARCH" envDefault:"false"`
+
+	// Read replicas (SQLite paths or PostgreSQL DSNs)
+	Replicas      []string `json:"replicas" env:"DB_REPLICAS" envSeparator:"," redact:"dsn"`
+	ReplicaPolicy string   `json:"replica_policy" env:"DB_REPLICA_POLICY" envDefault:"random"`
+
+	// GORM queries slower than this are logged as warnings; 0s disables it
+	SlowQueryThreshold time.Duration `json:"slow_query_threshold" env:"DB_SLOW_QUERY_THRESHOLD" envDefault:"200ms"`
 
 	// MongoDB
-	MongoURI      string `json:"mongo_uri" env:"MONGO_URI" envDefault:"mongodb://localhost:27017"`
-	MongoDatabase string `json:"mongo_database" env:"MONGO_DATABASE" envDefault:"fx_gin_scaffold"`
+	MongoURI            string        `json:"mongo_uri" env:"MONGO_URI" envDefault:"mongodb://localhost:27017" redact:"dsn"`
+	MongoDatabase       string        `json:"mongo_database" env:"MONGO_DATABASE" envDefault:"fx_gin_scaffold"`
+	MongoReadPreference string        `json:"mongo_read_preference" env:"MONGO_READ_PREFERENCE" envDefault:"primary"`
+	MongoMaxStaleness   time.Duration `json:"mongo_max_staleness" env:"MONGO_MAX_STALENESS" envDefault:"0s"`
+}
+
+// DebugConfig contains settings of the pprof and expvar endpoints
+type DebugConfig struct {
+	// Enabled serves /debug/pprof/ and /debug/vars, to admins on the API server
+	Enabled bool `json:"enabled" env:"DEBUG_ENDPOINTS_ENABLED" envDefault:"false"`
+	// Addr serves them without authentication on a loopback listener instead,
+	// e.g. 127.0.0.1:6060
+	Addr string `json:"addr" env:"DEBUG_ADDR"`
+}
+
+// EncryptionConfig contains the keys encrypting personal data fields at
+// rest. Without keys the fields are stored in plaintext.
+type EncryptionConfig struct {
+	// Keys are hex-encoded 32-byte AES keys by key ID (kid=key,...). Keep a
+	// retired key until the values it encrypted have been re-encrypted.
+	Keys map[string]string `json:"keys" env:"ENCRYPTION_KEYS" envKeyValSeparator:"=" redact:"secret"`
+	// KeyFile holds more kid=key lines, e.g. mounted by a secrets manager
+	KeyFile string `json:"key_file" env:"ENCRYPTION_KEY_FILE"`
+	// KeyID is the key encrypting new values; required with several keys
+	KeyID string `json:"key_id" env:"ENCRYPTION_KEY_ID"`
+}
+
+// FeaturesConfig contains feature flags, which can be toggled without a restart
+type FeaturesConfig struct {
+	Enabled []string `json:"enabled" env:"FEATURE_FLAGS" envSeparator:","`
+}
+
+// FilesConfig contains stored file serving settings
+type FilesConfig struct {
+	Enabled     bool          `json:"enabled" env:"FILES_ENABLED" envDefault:"false"`
+	Dir         string        `json:"dir" env:"FILES_DIR" envDefault:"./data/files"`
+	CacheMaxAge time.Duration `json:"cache_max_age" env:"FILES_CACHE_MAX_AGE" envDefault:"24h"`
+}
+
+// GraphQLConfig contains settings of the optional GraphQL endpoint, which is
+// only available in servers built with the graphql tag
+type GraphQLConfig struct {
+	Enabled         bool `json:"enabled" env:"GRAPHQL_ENABLED" envDefault:"false"`
+	Playground      bool `json:"playground" env:"GRAPHQL_PLAYGROUND" envDefault:"true"`
+	ComplexityLimit int  `json:"complexity_limit" env:"GRAPHQL_COMPLEXITY_LIMIT" envDefault:"200"`
+}
+
+// HTTPClientConfig contains settings of the HTTP clients of outbound
+// integrations; they retry idempotent requests with the RETRY_* settings
+type HTTPClientConfig struct {
+	Timeout               time.Duration `json:"timeout" env:"HTTP_CLIENT_TIMEOUT" envDefault:"30s"`
+	DialTimeout           time.Duration `json:"dial_timeout" env:"HTTP_CLIENT_DIAL_TIMEOUT" envDefault:"5s"`
+	TLSHandshakeTimeout   time.Duration `json:"tls_handshake_timeout" env:"HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT" envDefault:"5s"`
+	ResponseHeaderTimeout time.Duration `json:"response_header_timeout" env:"HTTP_CLIENT_RESPONSE_HEADER_TIMEOUT" envDefault:"0s"`
+	IdleConnTimeout       time.Duration `json:"idle_conn_timeout" env:"HTTP_CLIENT_IDLE_CONN_TIMEOUT" envDefault:"90s"`
+	// Connection pool shared by all clients; 0 leaves a limit unset
+	MaxIdleConns        int `json:"max_idle_conns" env:"HTTP_CLIENT_MAX_IDLE_CONNS" envDefault:"100"`
+	MaxIdleConnsPerHost int `json:"max_idle_conns_per_host" env:"HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST" envDefault:"10"`
+	MaxConnsPerHost     int `json:"max_conns_per_host" env:"HTTP_CLIENT_MAX_CONNS_PER_HOST" envDefault:"0"`
+	// ProxyURL overrides HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	ProxyURL string `json:"proxy_url" env:"HTTP_CLIENT_PROXY_URL" redact:"dsn"`
+	// Tracing adds OpenTelemetry client spans, exported by the tracer
+	// provider the application registers
+	Tracing bool `json:"tracing" env:"HTTP_CLIENT_TRACING" envDefault:"false"`
 }
 
 // JWTConfig contains JWT authentication settings
 type JWTConfig struct {
-	Secret     string        `json:"secret" env:"JWT_SECRET"`
-	Expiration time.Duration `json:"expiration" env:"JWT_EXPIRATION" envDefault:"24h"`
+	Secret                string        `json:"secret" env:"JWT_SECRET" redact:"secret"`
+	Expiration            time.Duration `json:"expiration" env:"JWT_EXPIRATION" envDefault:"24h"`
+	RefreshExpiration     time.Duration `json:"refresh_expiration" env:"JWT_REFRESH_EXPIRATION" envDefault:"720h"`
+	EmailChangeExpiration time.Duration `json:"email_change_expiration" env:"EMAIL_CHANGE_EXPIRATION" envDefault:"24h"`
+
+	// Access token signing. RS256 and EdDSA read PEM files keyed by key ID
+	// (kid=path,...); keys that aren't used for signing still verify tokens.
+	Algorithm    string            `json:"algorithm" env:"JWT_ALGORITHM" envDefault:"HS256"`
+	Keys         map[string]string `json:"keys" env:"JWT_KEYS" envKeyValSeparator:"="`
+	SigningKeyID string            `json:"signing_key_id" env:"JWT_SIGNING_KEY_ID"`
 }
 
 // LoggerConfig contains logging configuration
@@ -58,37 +213,303 @@ type LoggerConfig struct {
 	Level  string `json:"level" env:"LOG_LEVEL" envDefault:"info"`
 	Format string `json:"format" env:"LOG_FORMAT" envDefault:"json"`
 	Output string `json:"output" env:"LOG_OUTPUT" envDefault:"stdout"`
+	// Levels overrides the level of the http, db and jobs modules, e.g.
+	// db=warn,jobs=debug
+	Levels map[string]string `json:"levels" env:"LOG_LEVELS" envKeyValSeparator:"="`
+
+	// Bodies logs request and response bodies, with secrets redacted, at
+	// debug level of the http module; bodies over the cap aren't logged
+	Bodies       bool `json:"bodies" env:"LOG_BODIES" envDefault:"false"`
+	BodyMaxBytes int  `json:"body_max_bytes" env:"LOG_BODY_MAX_BYTES" envDefault:"4096"`
+
+	// Rotation of LOG_OUTPUT files; a zero size disables it
+	MaxSizeMB  int           `json:"max_size_mb" env:"LOG_MAX_SIZE_MB" envDefault:"100"`
+	MaxBackups int           `json:"max_backups" env:"LOG_MAX_BACKUPS" envDefault:"7"`
+	MaxAge     time.Duration `json:"max_age" env:"LOG_MAX_AGE" envDefault:"0s"`
+
+	// Sampling of repeated debug and info entries; a zero initial count
+	// disables it
+	SamplingInitial    int           `json:"sampling_initial" env:"LOG_SAMPLING_INITIAL" envDefault:"0"`
+	SamplingThereafter int           `json:"sampling_thereafter" env:"LOG_SAMPLING_THEREAFTER" envDefault:"100"`
+	SamplingTick       time.Duration `json:"sampling_tick" env:"LOG_SAMPLING_TICK" envDefault:"1s"`
+}
+
+// MailConfig contains outgoing email settings
+type MailConfig struct {
+	Driver       string        `json:"driver" env:"MAIL_DRIVER" envDefault:"console"`
+	From         string        `json:"from" env:"MAIL_FROM" envDefault:"Fx Gin Scaffold <no-reply@localhost>"`
+	SMTPHost     string        `json:"smtp_host" env:"SMTP_HOST" envDefault:""`
+	SMTPPort     int           `json:"smtp_port" env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername string        `json:"smtp_username" env:"SMTP_USERNAME" envDefault:""`
+	SMTPPassword string        `json:"smtp_password" env:"SMTP_PASSWORD" envDefault:"" redact:"secret"`
+	SMTPTimeout  time.Duration `json:"smtp_timeout" env:"SMTP_TIMEOUT" envDefault:"10s"`
+}
+
+// MetricsConfig contains Prometheus metrics settings
+type MetricsConfig struct {
+	Enabled bool   `json:"enabled" env:"METRICS_ENABLED" envDefault:"false"`
+	Path    string `json:"path" env:"METRICS_PATH" envDefault:"/metrics"`
+	// Interval refreshes the database connection pool gauges
+	Interval time.Duration `json:"interval" env:"METRICS_INTERVAL" envDefault:"15s"`
+}
+
+// NotificationsConfig contains in-app notification settings
+type NotificationsConfig struct {
+	// Push sends new notifications to the user's WebSocket and SSE connections
+	Push bool `json:"push" env:"NOTIFICATIONS_PUSH" envDefault:"true"`
+	// Welcome stores a welcome notification for every registered user
+	Welcome bool `json:"welcome" env:"NOTIFICATIONS_WELCOME" envDefault:"true"`
+}
+
+// OpsConfig contains settings of the internal ops server
+type OpsConfig struct {
+	// Addr serves health checks, metrics, profiling and admin endpoints on a
+	// second listener, e.g. :9090, instead of the API server
+	Addr string `json:"addr" env:"OPS_ADDR"`
+}
+
+// OrgsConfig contains organization settings
+type OrgsConfig struct {
+	InvitationExpiration time.Duration `json:"invitation_expiration" env:"ORG_INVITATION_EXPIRATION" envDefault:"168h"`
+}
+
+// PasswordConfig contains password hashing settings. Changing them upgrades
+// stored hashes as users log in.
+type PasswordConfig struct {
+	Algorithm         string `json:"algorithm" env:"PASSWORD_HASH_ALGORITHM" envDefault:"bcrypt"`
+	BcryptCost        int    `json:"bcrypt_cost" env:"BCRYPT_COST" envDefault:"10"`
+	Argon2Memory      uint32 `json:"argon2_memory" env:"ARGON2_MEMORY" envDefault:"65536"`
+	Argon2Iterations  uint32 `json:"argon2_iterations" env:"ARGON2_ITERATIONS" envDefault:"3"`
+	Argon2Parallelism uint8  `json:"argon2_parallelism" env:"ARGON2_PARALLELISM" envDefault:"2"`
+}
+
+// RedisConfig contains Redis connection settings
+type RedisConfig struct {
+	Addr         string        `json:"addr" env:"REDIS_ADDR" envDefault:""`
+	Password     string        `json:"password" env:"REDIS_PASSWORD" envDefault:"" redact:"secret"`
+	DB           int           `json:"db" env:"REDIS_DB" envDefault:"0"`
+	PoolSize     int           `json:"pool_size" env:"REDIS_POOL_SIZE" envDefault:"10"`
+	MinIdleConns int           `json:"min_idle_conns" env:"REDIS_MIN_IDLE_CONNS" envDefault:"0"`
+	DialTimeout  time.Duration `json:"dial_timeout" env:"REDIS_DIAL_TIMEOUT" envDefault:"5s"`
+}
+
+// Registration modes
+const (
+	// RegistrationModeOpen lets anyone register
+	RegistrationModeOpen = "open"
+	// RegistrationModeInvite requires an invite code created by an admin
+	RegistrationModeInvite = "invite"
+	// RegistrationModeClosed disables registration
+	RegistrationModeClosed = "closed"
+)
+
+// RegistrationConfig contains self-registration settings
+type RegistrationConfig struct {
+	Mode             string        `json:"mode" env:"REGISTRATION_MODE" envDefault:"open"`
+	InviteExpiration time.Duration `json:"invite_expiration" env:"REGISTRATION_INVITE_EXPIRATION" envDefault:"168h"`
+}
+
+// ResilienceConfig contains circuit breaker and retry settings for calls to
+// external services: SMTP and webhook endpoints
+type ResilienceConfig struct {
+	// BreakerThreshold consecutive failures stop calls to the service for
+	// BreakerOpenTimeout; 0 disables circuit breakers
+	BreakerThreshold   int           `json:"breaker_threshold" env:"CIRCUIT_BREAKER_THRESHOLD" envDefault:"5"`
+	BreakerOpenTimeout time.Duration `json:"breaker_open_timeout" env:"CIRCUIT_BREAKER_OPEN_TIMEOUT" envDefault:"30s"`
+	// RetryMaxAttempts includes the first attempt; 1 disables retries
+	RetryMaxAttempts    int           `json:"retry_max_attempts" env:"RETRY_MAX_ATTEMPTS" envDefault:"3"`
+	RetryInitialBackoff time.Duration `json:"retry_initial_backoff" env:"RETRY_INITIAL_BACKOFF" envDefault:"200ms"`
+	RetryMaxBackoff     time.Duration `json:"retry_max_backoff" env:"RETRY_MAX_BACKOFF" envDefault:"5s"`
+}
+
+// SchedulerConfig contains background task settings
+type SchedulerConfig struct {
+	Enabled       bool     `json:"enabled" env:"SCHEDULER_ENABLED" envDefault:"true"`
+	DisabledTasks []string `json:"disabled_tasks" env:"SCHEDULER_DISABLED_TASKS" envSeparator:","`
+}
+
+// SearchConfig contains settings of the optional full-text search index.
+// The bleve driver is only available in servers built with the bleve tag.
+type SearchConfig struct {
+	Enabled               bool          `json:"enabled" env:"SEARCH_ENABLED" envDefault:"false"`
+	Driver                string        `json:"driver" env:"SEARCH_DRIVER" envDefault:"bleve"`
+	BlevePath             string        `json:"bleve_path" env:"SEARCH_BLEVE_PATH" envDefault:"./data/search"`
+	ElasticsearchURL      string        `json:"elasticsearch_url" env:"ELASTICSEARCH_URL" envDefault:"http://localhost:9200"`
+	ElasticsearchUsername string        `json:"elasticsearch_username" env:"ELASTICSEARCH_USERNAME"`
+	ElasticsearchPassword string        `json:"elasticsearch_password" env:"ELASTICSEARCH_PASSWORD" redact:"secret"`
+	IndexPrefix           string        `json:"index_prefix" env:"SEARCH_INDEX_PREFIX" envDefault:"fx_"`
+	Timeout               time.Duration `json:"timeout" env:"SEARCH_TIMEOUT" envDefault:"5s"`
+	ReindexSchedule       string        `json:"reindex_schedule" env:"SEARCH_REINDEX_SCHEDULE" envDefault:"@daily"`
+}
+
+// RolesConfig is the catalog of roles users can be given. What a role may do
+// is stored with the role; the catalog only limits which roles are assigned.
+type RolesConfig struct {
+	// Catalog lists the roles users can be assigned; it must include the
+	// built-in admin and user roles
+	Catalog []string `json:"catalog" env:"ROLES" envSeparator:"," envDefault:"admin,moderator,user"`
+	// Registration lists the roles users can choose when registering; the
+	// first is given when they choose none
+	Registration []string `json:"registration" env:"REGISTRATION_ROLES" envSeparator:"," envDefault:"user"`
 }
 
+// SeedConfig contains settings of the development and staging seeders
+type SeedConfig struct {
+	AdminEmail string `json:"admin_email" env:"ADMIN_EMAIL" envDefault:"admin@example.com"`
+	AdminName  string `json:"admin_name" env:"ADMIN_NAME" envDefault:"System Administrator"`
+	// AdminPassword is the password of the seeded admin; when empty a random
+	// one is generated and printed once, when the admin is created
+	AdminPassword string `json:"admin_password" env:"ADMIN_PASSWORD" redact:"secret"`
+}
+
+// MaxShutdownDelay bounds SHUTDOWN_DELAY, leaving the rest of the stop
+// timeout to in-flight requests
+const MaxShutdownDelay = 30 * time.Second
+
 // ServerConfig contains HTTP server settings
 type ServerConfig struct {
-	Host string `json:"host" env:"APP_HOST" envDefault:"localhost"`
-	Port int    `json:"port" env:"APP_PORT" envDefault:"8080"`
+	// Host defaults to localhost in development and to 0.0.0.0 elsewhere, so
+	// that the server is reachable from outside its container
+	Host string `json:"host" env:"APP_HOST"`
+	// Port defaults to PORT, which platforms such as Cloud Run and Heroku
+	// inject, and then to 8080
+	Port         int    `json:"port" env:"APP_PORT"`
+	PlatformPort int    `json:"-" env:"PORT"`
+	PortSource   string `json:"-"`
+	// ShutdownDelay keeps serving after SIGTERM while readiness reports
+	// draining, so load balancers stop routing to the instance before its
+	// connections are closed
+	ShutdownDelay time.Duration `json:"shutdown_delay" env:"SHUTDOWN_DELAY" envDefault:"0s"`
 
 	// CORS
-	EnableCORS  bool   `json:"enable_cors" env:"ENABLE_CORS" envDefault:"true"`
-	CORSOrigins string `json:"cors_origins" env:"CORS_ORIGINS" envDefault:"*"`
-	CORSMethods string `json:"cors_methods" env:"CORS_METHODS" envDefault:"GET,POST,PUT,DELETE,OPTIONS"`
-	CORSHeaders string `json:"cors_headers" env:"CORS_HEADERS" envDefault:"Origin,Content-Type,Accept,Authorization,X-Requested-With"`
+	EnableCORS           bool          `json:"enable_cors" env:"ENABLE_CORS" envDefault:"true"`
+	CORSOrigins          []string      `json:"cors_origins" env:"CORS_ORIGINS" envDefault:"*" envSeparator:","`
+	CORSMethods          []string      `json:"cors_methods" env:"CORS_METHODS" envDefault:"GET,POST,PUT,PATCH,DELETE,OPTIONS" envSeparator:","`
+	CORSHeaders          []string      `json:"cors_headers" env:"CORS_HEADERS" envDefault:"Origin,Content-Type,Accept,Authorization,X-Requested-With,Idempotency-Key" envSeparator:","`
+	CORSExposedHeaders   []string      `json:"cors_exposed_headers" env:"CORS_EXPOSED_HEADERS" envSeparator:","`
+	CORSAllowCredentials bool          `json:"cors_allow_credentials" env:"CORS_ALLOW_CREDENTIALS" envDefault:"false"`
+	CORSMaxAge           time.Duration `json:"cors_max_age" env:"CORS_MAX_AGE" envDefault:"12h"`
+
+	// Compression
+	EnableCompression    bool     `json:"enable_compression" env:"ENABLE_COMPRESSION" envDefault:"true"`
+	CompressionLevel     int      `json:"compression_level" env:"COMPRESSION_LEVEL" envDefault:"-1"`
+	CompressionMinLength int      `json:"compression_min_length" env:"COMPRESSION_MIN_LENGTH" envDefault:"1024"`
+	CompressionTypes     []string `json:"compression_types" env:"COMPRESSION_TYPES" envDefault:"application/json,application/javascript,application/xml,image/svg+xml,text/*" envSeparator:","`
+
+	// Documentation; never served in production and behind basic auth in staging
+	EnableSwagger   bool   `json:"enable_swagger" env:"ENABLE_SWAGGER" envDefault:"true"`
+	SwaggerUsername string `json:"swagger_username" env:"SWAGGER_USERNAME" envDefault:""`
+	SwaggerPassword string `json:"swagger_password" env:"SWAGGER_PASSWORD" envDefault:"" redact:"secret"`
+
+	// Response timestamps when the client doesn't ask for a format (time_format,
+	// X-Time-Format) or time zone (tz, X-Timezone); they are stored in UTC
+	ResponseTimeFormat string `json:"response_time_format" env:"RESPONSE_TIME_FORMAT" envDefault:"rfc3339"`
+	ResponseTimezone   string `json:"response_timezone" env:"RESPONSE_TIMEZONE" envDefault:"UTC"`
+
+	// Response body format when the client doesn't ask for one
+	// (X-Response-Format, or Accept: application/problem+json for raw):
+	// envelope wraps resources and errors in domain.Response, raw sends bare
+	// resources and RFC 7807 problem details
+	ResponseFormat string `json:"response_format" env:"RESPONSE_FORMAT" envDefault:"envelope"`
+
+	// Links to the first, previous, next and last pages in the metadata of
+	// paginated responses (a Link header in raw format)
+	PaginationLinks bool `json:"pagination_links" env:"PAGINATION_LINKS" envDefault:"false"`
+
+	// Rate limiting allows RateLimitRequests per RateLimitWindow from each
+	// client IP; 0 disables it
+	RateLimitRequests int           `json:"rate_limit_requests" env:"RATE_LIMIT_REQUESTS" envDefault:"0"`
+	RateLimitWindow   time.Duration `json:"rate_limit_window" env:"RATE_LIMIT_WINDOW" envDefault:"1m"`
 
-	// Documentation
-	EnableSwagger bool `json:"enable_swagger" env:"ENABLE_SWAGGER" envDefault:"true"`
+	// TrustedProxies are the IPs or CIDRs of the reverse proxies whose
+	// X-Forwarded-For and X-Real-IP headers give the client IP; when empty the
+	// client IP is the address of the connection
+	TrustedProxies []string `json:"trusted_proxies" env:"TRUSTED_PROXIES" envSeparator:","`
+
+	// IdempotencyTTL is how long the response to a POST or PATCH request with
+	// an Idempotency-Key header is replayed to retries; 0s disables it
+	IdempotencyTTL time.Duration `json:"idempotency_ttl" env:"IDEMPOTENCY_TTL" envDefault:"24h"`
+
+	// Realtime
+	SSEKeepAlive time.Duration `json:"sse_keep_alive" env:"SSE_KEEP_ALIVE" envDefault:"15s"`
+
+	// TLS from a certificate and key, or from Let's Encrypt certificates for
+	// the autocert domains; HTTP/2 is served over TLS
+	TLSCertFile         string   `json:"tls_cert_file" env:"TLS_CERT_FILE"`
+	TLSKeyFile          string   `json:"tls_key_file" env:"TLS_KEY_FILE"`
+	TLSAutocertDomains  []string `json:"tls_autocert_domains" env:"TLS_AUTOCERT_DOMAINS" envSeparator:","`
+	TLSAutocertEmail    string   `json:"tls_autocert_email" env:"TLS_AUTOCERT_EMAIL"`
+	TLSAutocertCacheDir string   `json:"tls_autocert_cache_dir" env:"TLS_AUTOCERT_CACHE_DIR" envDefault:"./data/autocert"`
+	// HTTPRedirectAddr redirects plain HTTP requests to HTTPS, e.g. :80; with
+	// autocert it also answers ACME HTTP-01 challenges
+	HTTPRedirectAddr string `json:"http_redirect_addr" env:"HTTP_REDIRECT_ADDR"`
+}
+
+// SigningConfig contains settings of HMAC signed service-to-service requests
+type SigningConfig struct {
+	// KeyID and Secret sign outbound requests; the receiving service trusts
+	// the secret under the same key ID
+	KeyID  string `json:"key_id" env:"SIGNING_KEY_ID"`
+	Secret string `json:"secret" env:"SIGNING_SECRET" redact:"secret"`
+	// TrustedKeys are the secrets of services whose requests are accepted,
+	// keyed by key ID (id=secret,...)
+	TrustedKeys map[string]string `json:"trusted_keys" env:"SIGNING_TRUSTED_KEYS" envKeyValSeparator:"=" redact:"secret"`
+	// Tolerance is how far a request timestamp may be from the local clock;
+	// nonces are remembered twice as long to reject replays
+	Tolerance time.Duration `json:"tolerance" env:"SIGNING_TOLERANCE" envDefault:"5m"`
+}
+
+// WebhooksConfig contains outbound webhook delivery settings
+type WebhooksConfig struct {
+	Enabled      bool          `json:"enabled" env:"WEBHOOKS_ENABLED" envDefault:"true"`
+	Timeout      time.Duration `json:"timeout" env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	MaxAttempts  int           `json:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"6"`
+	RetryBackoff time.Duration `json:"retry_backoff" env:"WEBHOOK_RETRY_BACKOFF" envDefault:"30s"`
+	PollInterval time.Duration `json:"poll_interval" env:"WEBHOOK_POLL_INTERVAL" envDefault:"5s"`
 }
 
+// dotenvFile is the optional file configuration is loaded from
+const dotenvFile = ".env"
+
+var (
+	processEnvOnce sync.Once
+	// processEnv is the environment before .env was loaded. Reloads overlay it
+	// on the file so variables set by the process keep precedence.
+	processEnv map[string]string
+)
+
 // NewConfig creates a new configuration instance
 func NewConfig() (*Config, error) {
-	// Load .env file if it exists
-	if err := godotenv.Load(); err != nil {
-		zap.L().Debug("no .env file found, using environment variables only")
+	processEnvOnce.Do(func() {
+		processEnv = environ()
+	})
+
+	// Load .env file if it exists; without one the environment variables are
+	// used alone
+	_ = godotenv.Load()
+
+	return load(dotenvFile)
+}
+
+// load parses and validates the configuration from path and the process environment
+func load(path string) (*Config, error) {
+	environment, err := godotenv.Read(path)
+	if err != nil {
+		environment = make(map[string]string)
+	}
+	for key, value := range processEnv {
+		environment[key] = value
 	}
 
 	config := &Config{}
 
 	// Parse environment variables using caarlos0/env
-	if err := env.Parse(config); err != nil {
+	if err := env.ParseWithOptions(config, env.Options{Environment: environment}); err != nil {
 		return nil, fmt.Errorf("failed to parse environment variables: %w", err)
 	}
 
+	config.resolveAddress()
+
 	// Validate required fields
 	if err := config.validate(); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
@@ -97,12 +518,70 @@ func NewConfig() (*Config, error) {
 	return config, nil
 }
 
+// environ returns the process environment as a map
+func environ() map[string]string {
+	environment := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			environment[key] = value
+		}
+	}
+	return environment
+}
+
+// resolveAddress fills in the listen address settings left unset
+func (c *Config) resolveAddress() {
+	if c.Server.Host == "" {
+		c.Server.Host = "0.0.0.0"
+		if c.IsDevelopment() {
+			c.Server.Host = "localhost"
+		}
+	}
+
+	switch {
+	case c.Server.Port != 0:
+		c.Server.PortSource = "APP_PORT"
+	case c.Server.PlatformPort != 0:
+		c.Server.Port = c.Server.PlatformPort
+		c.Server.PortSource = "PORT"
+	default:
+		c.Server.Port = 8080
+		c.Server.PortSource = "default"
+	}
+}
+
 // validate checks if all required configuration fields are set
 func (c *Config) validate() error {
 	if c.JWT.Secret == "" {
 		return fmt.Errorf("JWT_SECRET is required")
 	}
 
+	switch c.JWT.Algorithm {
+	case "HS256":
+	case "RS256", "EdDSA":
+		if len(c.JWT.Keys) == 0 {
+			return fmt.Errorf("JWT_KEYS is required when using %s", c.JWT.Algorithm)
+		}
+		if c.JWT.SigningKeyID == "" && len(c.JWT.Keys) > 1 {
+			return fmt.Errorf("JWT_SIGNING_KEY_ID is required when JWT_KEYS has several keys")
+		}
+		if _, ok := c.JWT.Keys[c.JWT.SigningKeyID]; c.JWT.SigningKeyID != "" && !ok {
+			return fmt.Errorf("JWT_SIGNING_KEY_ID %s is not in JWT_KEYS", c.JWT.SigningKeyID)
+		}
+	default:
+		return fmt.Errorf("unsupported JWT algorithm: %s (supported: HS256, RS256, EdDSA)", c.JWT.Algorithm)
+	}
+
+	if c.Accounts.DeletionGracePeriod < 0 {
+		return fmt.Errorf("ACCOUNT_DELETION_GRACE_PERIOD cannot be negative")
+	}
+	if c.Accounts.LoginMaxAttempts < 0 {
+		return fmt.Errorf("LOGIN_MAX_ATTEMPTS cannot be negative")
+	}
+	if c.Accounts.LoginMaxAttempts > 0 && c.Accounts.LoginLockoutDuration <= 0 {
+		return fmt.Errorf("LOGIN_LOCKOUT_DURATION must be positive when LOGIN_MAX_ATTEMPTS is set")
+	}
+
 	if c.Database.Driver == "" {
 		return fmt.Errorf("DB_DRIVER is required")
 	}
@@ -119,6 +598,346 @@ func (c *Config) validate() error {
 		return fmt.Errorf("unsupported database driver: %s (supported: sqlite, postgres, mongo)", c.Database.Driver)
 	}
 
+	if c.Database.SecondaryDriver != "" {
+		if !slices.Contains([]string{"sqlite", "postgres", "mongo"}, c.Database.SecondaryDriver) {
+			return fmt.Errorf("unsupported secondary database driver: %s (supported: sqlite, postgres, mongo)", c.Database.SecondaryDriver)
+		}
+		if IsGORMDriver(c.Database.SecondaryDriver) == IsGORMDriver(c.Database.Driver) {
+			return fmt.Errorf("DB_SECONDARY_DRIVER must be mongo when DB_DRIVER is sqlite or postgres, and sqlite or postgres when it is mongo")
+		}
+	}
+
+	for name, driver := range c.Database.RepositoryDrivers {
+		if driver != c.Database.Driver && driver != c.Database.SecondaryDriver {
+			return fmt.Errorf("DB_REPOSITORY_DRIVERS sets %s to %s, which is neither DB_DRIVER nor DB_SECONDARY_DRIVER", name, driver)
+		}
+	}
+
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		return fmt.Errorf("DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS cannot be negative")
+	}
+
+	if c.Database.ConnMaxLifetime < 0 || c.Database.ConnMaxIdleTime < 0 {
+		return fmt.Errorf("DB_CONN_MAX_LIFETIME and DB_CONN_MAX_IDLE_TIME cannot be negative")
+	}
+
+	if c.Database.SlowQueryThreshold < 0 {
+		return fmt.Errorf("DB_SLOW_QUERY_THRESHOLD cannot be negative")
+	}
+
+	if c.Database.ConnectMaxWait < 0 {
+		return fmt.Errorf("DB_CONNECT_MAX_WAIT cannot be negative")
+	}
+
+	if c.Database.ConnectInitialBackoff <= 0 || c.Database.ConnectMaxBackoff < c.Database.ConnectInitialBackoff {
+		return fmt.Errorf("DB_CONNECT_INITIAL_BACKOFF must be positive and not exceed DB_CONNECT_MAX_BACKOFF")
+	}
+
+	switch c.Database.ReplicaPolicy {
+	case "random", "round_robin":
+	default:
+		return fmt.Errorf("unsupported replica policy: %s (supported: random, round_robin)", c.Database.ReplicaPolicy)
+	}
+
+	switch c.Database.MongoReadPreference {
+	case "primary":
+		if c.Database.MongoMaxStaleness != 0 {
+			return fmt.Errorf("MONGO_MAX_STALENESS cannot be used with the primary read preference")
+		}
+	case "primaryPreferred", "secondary", "secondaryPreferred", "nearest":
+		if c.Database.MongoMaxStaleness < 0 {
+			return fmt.Errorf("MONGO_MAX_STALENESS cannot be negative")
+		}
+	default:
+		return fmt.Errorf("unsupported mongo read preference: %s (supported: primary, primaryPreferred, secondary, secondaryPreferred, nearest)", c.Database.MongoReadPreference)
+	}
+
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	if c.Server.TLSCertFile != "" && len(c.Server.TLSAutocertDomains) > 0 {
+		return fmt.Errorf("TLS_CERT_FILE cannot be used with TLS_AUTOCERT_DOMAINS")
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		return fmt.Errorf("%s must be between 1 and 65535, got %d", c.Server.PortSource, c.Server.Port)
+	}
+
+	if host := c.Server.Host; net.ParseIP(host) == nil && (strings.ContainsAny(host, ":/ ") || strings.TrimSpace(host) == "") {
+		return fmt.Errorf("APP_HOST must be a host name or IP address without scheme or port, got %q", host)
+	}
+
+	if c.Server.ShutdownDelay < 0 || c.Server.ShutdownDelay > MaxShutdownDelay {
+		return fmt.Errorf("SHUTDOWN_DELAY must be between 0s and %s", MaxShutdownDelay)
+	}
+
+	if c.Server.RateLimitRequests < 0 {
+		return fmt.Errorf("RATE_LIMIT_REQUESTS cannot be negative")
+	}
+	if c.Server.RateLimitRequests > 0 && c.Server.RateLimitWindow <= 0 {
+		return fmt.Errorf("RATE_LIMIT_WINDOW must be positive when RATE_LIMIT_REQUESTS is set")
+	}
+	if c.Server.IdempotencyTTL < 0 {
+		return fmt.Errorf("IDEMPOTENCY_TTL cannot be negative")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("TRUSTED_PROXIES must be IP addresses or CIDRs, got %q", proxy)
+		}
+	}
+
+	if c.Server.HTTPRedirectAddr != "" {
+		if !c.TLSEnabled() {
+			return fmt.Errorf("HTTP_REDIRECT_ADDR requires TLS_CERT_FILE or TLS_AUTOCERT_DOMAINS")
+		}
+		_, port, err := net.SplitHostPort(c.Server.HTTPRedirectAddr)
+		if err != nil {
+			return fmt.Errorf("invalid HTTP_REDIRECT_ADDR: %w", err)
+		}
+		if port == strconv.Itoa(c.Server.Port) {
+			return fmt.Errorf("HTTP_REDIRECT_ADDR must use a different port than APP_PORT")
+		}
+	}
+
+	if c.Ops.Addr != "" {
+		_, port, err := net.SplitHostPort(c.Ops.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid OPS_ADDR: %w", err)
+		}
+		if port == strconv.Itoa(c.Server.Port) {
+			return fmt.Errorf("OPS_ADDR must use a different port than APP_PORT")
+		}
+	}
+
+	if c.Debug.Enabled && c.Debug.Addr != "" {
+		host, _, err := net.SplitHostPort(c.Debug.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid DEBUG_ADDR: %w", err)
+		}
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return fmt.Errorf("DEBUG_ADDR must be a loopback address, got %s", c.Debug.Addr)
+		}
+	}
+
+	switch c.Mail.Driver {
+	case "console", "mock":
+	case "smtp":
+		if c.Mail.SMTPHost == "" {
+			return fmt.Errorf("SMTP_HOST is required when using smtp mail driver")
+		}
+	default:
+		return fmt.Errorf("unsupported mail driver: %s (supported: smtp, console, mock)", c.Mail.Driver)
+	}
+
+	if c.Resilience.BreakerThreshold < 0 || c.Resilience.BreakerOpenTimeout < 0 {
+		return fmt.Errorf("CIRCUIT_BREAKER_THRESHOLD and CIRCUIT_BREAKER_OPEN_TIMEOUT cannot be negative")
+	}
+
+	if c.Resilience.RetryMaxAttempts < 1 {
+		return fmt.Errorf("RETRY_MAX_ATTEMPTS must be at least 1")
+	}
+
+	if c.Resilience.RetryInitialBackoff <= 0 || c.Resilience.RetryMaxBackoff < c.Resilience.RetryInitialBackoff {
+		return fmt.Errorf("RETRY_INITIAL_BACKOFF must be positive and not exceed RETRY_MAX_BACKOFF")
+	}
+
+	if c.HTTPClient.Timeout < 0 || c.HTTPClient.DialTimeout < 0 || c.HTTPClient.TLSHandshakeTimeout < 0 ||
+		c.HTTPClient.ResponseHeaderTimeout < 0 || c.HTTPClient.IdleConnTimeout < 0 {
+		return fmt.Errorf("HTTP_CLIENT_* timeouts cannot be negative")
+	}
+
+	if c.HTTPClient.MaxIdleConns < 0 || c.HTTPClient.MaxIdleConnsPerHost < 0 || c.HTTPClient.MaxConnsPerHost < 0 {
+		return fmt.Errorf("HTTP_CLIENT_* connection limits cannot be negative")
+	}
+
+	if (c.Signing.KeyID == "") != (c.Signing.Secret == "") {
+		return fmt.Errorf("SIGNING_KEY_ID and SIGNING_SECRET must be set together")
+	}
+
+	for id, secret := range c.Signing.TrustedKeys {
+		if id == "" || secret == "" {
+			return fmt.Errorf("SIGNING_TRUSTED_KEYS must be key ID=secret pairs")
+		}
+	}
+
+	if c.Signing.Tolerance <= 0 {
+		return fmt.Errorf("SIGNING_TOLERANCE must be positive")
+	}
+
+	if c.Metrics.Enabled {
+		if !strings.HasPrefix(c.Metrics.Path, "/") {
+			return fmt.Errorf("METRICS_PATH must start with /")
+		}
+		if c.Metrics.Interval <= 0 {
+			return fmt.Errorf("METRICS_INTERVAL must be positive")
+		}
+	}
+
+	switch c.Password.Algorithm {
+	case "bcrypt":
+		if c.Password.BcryptCost < 4 || c.Password.BcryptCost > 31 {
+			return fmt.Errorf("BCRYPT_COST must be between 4 and 31")
+		}
+	case "argon2id":
+		if c.Password.Argon2Memory == 0 || c.Password.Argon2Iterations == 0 || c.Password.Argon2Parallelism == 0 {
+			return fmt.Errorf("ARGON2_MEMORY, ARGON2_ITERATIONS and ARGON2_PARALLELISM must be positive")
+		}
+	default:
+		return fmt.Errorf("unsupported password hash algorithm: %s (supported: bcrypt, argon2id)", c.Password.Algorithm)
+	}
+
+	if c.Server.CORSAllowCredentials {
+		for _, origin := range c.Server.CORSOrigins {
+			if strings.TrimSpace(origin) == "*" {
+				return fmt.Errorf("CORS_ORIGINS cannot contain * when CORS_ALLOW_CREDENTIALS is enabled")
+			}
+		}
+	}
+
+	for module, level := range c.Logger.Levels {
+		if !slices.Contains(logger.ModuleNames(), module) {
+			return fmt.Errorf("unknown module in LOG_LEVELS: %s (supported: %s)", module, strings.Join(logger.ModuleNames(), ", "))
+		}
+		if _, err := zapcore.ParseLevel(level); err != nil {
+			return fmt.Errorf("invalid level for %s in LOG_LEVELS: %w", module, err)
+		}
+	}
+
+	if c.Logger.MaxSizeMB < 0 || c.Logger.MaxBackups < 0 || c.Logger.MaxAge < 0 {
+		return fmt.Errorf("LOG_MAX_SIZE_MB, LOG_MAX_BACKUPS and LOG_MAX_AGE cannot be negative")
+	}
+
+	if c.Logger.SamplingInitial < 0 || c.Logger.SamplingThereafter < 0 {
+		return fmt.Errorf("LOG_SAMPLING_INITIAL and LOG_SAMPLING_THEREAFTER cannot be negative")
+	}
+
+	if c.Logger.SamplingInitial > 0 && c.Logger.SamplingTick <= 0 {
+		return fmt.Errorf("LOG_SAMPLING_TICK must be positive when sampling is enabled")
+	}
+
+	if c.Logger.BodyMaxBytes <= 0 {
+		return fmt.Errorf("LOG_BODY_MAX_BYTES must be positive")
+	}
+
+	if c.Cache.UserTTL < 0 || c.Cache.UserListTTL < 0 || c.Cache.UserSettingsTTL < 0 {
+		return fmt.Errorf("CACHE_USER_TTL, CACHE_USER_LIST_TTL and CACHE_USER_SETTINGS_TTL cannot be negative")
+	}
+
+	if c.Server.CompressionLevel < -1 || c.Server.CompressionLevel > 9 {
+		return fmt.Errorf("COMPRESSION_LEVEL must be between -1 (default) and 9")
+	}
+
+	if c.SwaggerEnabled() && c.IsStaging() && (c.Server.SwaggerUsername == "" || c.Server.SwaggerPassword == "") {
+		return fmt.Errorf("SWAGGER_USERNAME and SWAGGER_PASSWORD are required to serve Swagger in staging")
+	}
+
+	switch c.Server.ResponseTimeFormat {
+	case "rfc3339", "unix", "unix_ms":
+	default:
+		return fmt.Errorf("unsupported response time format: %s (supported: rfc3339, unix, unix_ms)", c.Server.ResponseTimeFormat)
+	}
+
+	if _, err := time.LoadLocation(c.Server.ResponseTimezone); err != nil {
+		return fmt.Errorf("RESPONSE_TIMEZONE must be an IANA time zone name: %w", err)
+	}
+
+	switch c.Server.ResponseFormat {
+	case "envelope", "raw":
+	default:
+		return fmt.Errorf("unsupported response format: %s (supported: envelope, raw)", c.Server.ResponseFormat)
+	}
+
+	if c.Server.SSEKeepAlive <= 0 {
+		return fmt.Errorf("SSE_KEEP_ALIVE must be positive")
+	}
+
+	if c.Files.Enabled && strings.TrimSpace(c.Files.Dir) == "" {
+		return fmt.Errorf("FILES_DIR is required when FILES_ENABLED is true")
+	}
+
+	if c.Files.CacheMaxAge < 0 {
+		return fmt.Errorf("FILES_CACHE_MAX_AGE cannot be negative")
+	}
+
+	if c.GraphQL.ComplexityLimit < 1 {
+		return fmt.Errorf("GRAPHQL_COMPLEXITY_LIMIT must be at least 1")
+	}
+
+	if c.Orgs.InvitationExpiration <= 0 {
+		return fmt.Errorf("ORG_INVITATION_EXPIRATION must be positive")
+	}
+
+	switch c.Registration.Mode {
+	case RegistrationModeOpen, RegistrationModeInvite, RegistrationModeClosed:
+	default:
+		return fmt.Errorf("unsupported registration mode: %s (supported: open, invite, closed)", c.Registration.Mode)
+	}
+
+	if c.Registration.InviteExpiration <= 0 {
+		return fmt.Errorf("REGISTRATION_INVITE_EXPIRATION must be positive")
+	}
+
+	for _, role := range []string{"admin", "user"} {
+		if !slices.Contains(c.Roles.Catalog, role) {
+			return fmt.Errorf("ROLES must include the built-in %s role", role)
+		}
+	}
+
+	if len(c.Roles.Registration) == 0 {
+		return fmt.Errorf("REGISTRATION_ROLES must list at least one role")
+	}
+	for _, role := range c.Roles.Registration {
+		if !slices.Contains(c.Roles.Catalog, role) {
+			return fmt.Errorf("REGISTRATION_ROLES includes %s, which is not in ROLES", role)
+		}
+		if role == "admin" {
+			return fmt.Errorf("REGISTRATION_ROLES cannot include admin")
+		}
+	}
+
+	if c.Webhooks.Timeout <= 0 {
+		return fmt.Errorf("WEBHOOK_TIMEOUT must be positive")
+	}
+
+	if c.Webhooks.MaxAttempts < 1 {
+		return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be at least 1")
+	}
+
+	if c.Webhooks.RetryBackoff <= 0 || c.Webhooks.PollInterval <= 0 {
+		return fmt.Errorf("WEBHOOK_RETRY_BACKOFF and WEBHOOK_POLL_INTERVAL must be positive")
+	}
+
+	if !strings.Contains(c.Seed.AdminEmail, "@") {
+		return fmt.Errorf("ADMIN_EMAIL must be an email address")
+	}
+
+	if c.Seed.AdminPassword != "" && len(c.Seed.AdminPassword) < 8 {
+		return fmt.Errorf("ADMIN_PASSWORD must be at least 8 characters")
+	}
+
+	if c.Search.Enabled {
+		switch c.Search.Driver {
+		case "bleve":
+			if strings.TrimSpace(c.Search.BlevePath) == "" {
+				return fmt.Errorf("SEARCH_BLEVE_PATH is required when using the bleve search driver")
+			}
+		case "elasticsearch":
+			if strings.TrimSpace(c.Search.ElasticsearchURL) == "" {
+				return fmt.Errorf("ELASTICSEARCH_URL is required when using the elasticsearch search driver")
+			}
+		default:
+			return fmt.Errorf("SEARCH_DRIVER must be bleve or elasticsearch")
+		}
+		if c.Search.Timeout <= 0 {
+			return fmt.Errorf("SEARCH_TIMEOUT must be positive")
+		}
+	}
+
+	if c.IsRedisEnabled() && c.Redis.PoolSize < 1 {
+		return fmt.Errorf("REDIS_POOL_SIZE must be at least 1")
+	}
+
 	// Driver-specific validation
 	switch c.Database.Driver {
 	case "postgres":
@@ -153,7 +972,70 @@ func (c *Config) IsProduction() bool {
 	return c.App.Env == "production"
 }
 
+// IsStaging returns true if the app is running in staging mode
+func (c *Config) IsStaging() bool {
+	return c.App.Env == "staging"
+}
+
+// SwaggerEnabled returns true if API documentation is served; it never is in
+// production, whatever ENABLE_SWAGGER says
+func (c *Config) SwaggerEnabled() bool {
+	return c.Server.EnableSwagger && !c.IsProduction()
+}
+
+// TLSEnabled returns true if the API server serves HTTPS
+func (c *Config) TLSEnabled() bool {
+	return c.Server.TLSCertFile != "" || len(c.Server.TLSAutocertDomains) > 0
+}
+
+// FeatureEnabled returns true if the named feature flag is enabled
+func (c *Config) FeatureEnabled(name string) bool {
+	for _, flag := range c.Features.Enabled {
+		if strings.EqualFold(strings.TrimSpace(flag), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleAssignable reports whether users can be assigned a role
+func (c *Config) RoleAssignable(role string) bool {
+	return slices.Contains(c.Roles.Catalog, role)
+}
+
+// RegistrationRole returns the role of a user registering with the
+// requested role: the first of REGISTRATION_ROLES when none is requested.
+// ok is false when the role cannot be chosen when registering.
+func (c *Config) RegistrationRole(requested string) (role string, ok bool) {
+	if requested == "" {
+		if len(c.Roles.Registration) == 0 {
+			return "user", true
+		}
+		return c.Roles.Registration[0], true
+	}
+	return requested, slices.Contains(c.Roles.Registration, requested)
+}
+
+// RepositoryDriver returns the database driver of the named repository
+func (c *Config) RepositoryDriver(name string) string {
+	if driver, ok := c.Database.RepositoryDrivers[name]; ok {
+		return driver
+	}
+	return c.Database.Driver
+}
+
+// IsGORMDriver returns true if the driver is served through GORM rather than
+// MongoDB
+func IsGORMDriver(driver string) bool {
+	return driver == "sqlite" || driver == "postgres"
+}
+
+// IsRedisEnabled returns true if a Redis address is configured
+func (c *Config) IsRedisEnabled() bool {
+	return c.Redis.Addr != ""
+}
+
 // GetAddress returns the server address in host:port format
 func (c *Config) GetAddress() string {
-	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
+	return net.JoinHostPort(c.Server.Host, strconv.Itoa(c.Server.Port))
 }
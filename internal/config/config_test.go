@@ -0,0 +1,93 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadAddress tests the defaults and validation of the listen address
+func TestLoadAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		address string
+		source  string
+	}{
+		{"development listens on localhost", "APP_ENV=development\n", "localhost:8080", "default"},
+		{"other environments listen on every interface", "APP_ENV=production\n", "0.0.0.0:8080", "default"},
+		{"explicit host", "APP_ENV=production\nAPP_HOST=10.0.0.5\n", "10.0.0.5:8080", "default"},
+		{"IPv6 host", "APP_HOST=::1\n", "[::1]:8080", "default"},
+		{"platform port", "APP_ENV=production\nPORT=3000\n", "0.0.0.0:3000", "PORT"},
+		{"APP_PORT wins over PORT", "APP_ENV=production\nPORT=3000\nAPP_PORT=9090\n", "0.0.0.0:9090", "APP_PORT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			writeEnv(t, path, tt.env)
+
+			cfg, err := load(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.address, cfg.GetAddress())
+			assert.Equal(t, tt.source, cfg.Server.PortSource)
+		})
+	}
+
+	for _, env := range []string{
+		"APP_PORT=70000\n",
+		"PORT=-1\n",
+		"APP_HOST=http://example.com\n",
+		"APP_HOST=0.0.0.0:8080\n",
+	} {
+		path := filepath.Join(t.TempDir(), ".env")
+		writeEnv(t, path, env)
+
+		_, err := load(path)
+		assert.Error(t, err, env)
+	}
+}
+
+// TestLoadTrustedProxies tests that trusted proxies must be IPs or CIDRs
+func TestLoadTrustedProxies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	writeEnv(t, path, "TRUSTED_PROXIES=10.0.0.1,172.16.0.0/12\n")
+
+	cfg, err := load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "172.16.0.0/12"}, cfg.Server.TrustedProxies)
+
+	writeEnv(t, path, "TRUSTED_PROXIES=proxy.internal\n")
+	_, err = load(path)
+	assert.Error(t, err)
+}
+
+// TestLoadRepositoryDrivers tests selecting the database of repositories
+func TestLoadRepositoryDrivers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	writeEnv(t, path, "DB_DRIVER=sqlite\nDB_SECONDARY_DRIVER=mongo\nDB_REPOSITORY_DRIVERS=audit_logs=mongo,users=sqlite\n")
+
+	cfg, err := load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "mongo", cfg.RepositoryDriver("audit_logs"))
+	assert.Equal(t, "sqlite", cfg.RepositoryDriver("users"))
+	assert.Equal(t, "sqlite", cfg.RepositoryDriver("projects"))
+
+	secondary, ok := cfg.SecondaryDatabaseConfig()
+	assert.True(t, ok)
+	assert.Equal(t, "mongo", secondary.Driver)
+
+	for _, env := range []string{
+		"DB_DRIVER=sqlite\nDB_SECONDARY_DRIVER=postgres\n",
+		"DB_DRIVER=mongo\nDB_SECONDARY_DRIVER=mongo\n",
+		"DB_DRIVER=sqlite\nDB_SECONDARY_DRIVER=redis\n",
+		"DB_DRIVER=sqlite\nDB_REPOSITORY_DRIVERS=audit_logs=mongo\n",
+	} {
+		path := filepath.Join(t.TempDir(), ".env")
+		writeEnv(t, path, env)
+
+		_, err := load(path)
+		assert.Error(t, err, env)
+	}
+}
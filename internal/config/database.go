@@ -0,0 +1,68 @@
+package config
+
+import (
+	"strconv"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+)
+
+// DatabaseConfig returns the connection settings of the configured driver,
+// shared by the server and the migrate command
+func (c *Config) DatabaseConfig() database.Config {
+	return c.DriverDatabaseConfig(c.Database.Driver)
+}
+
+// SecondaryDatabaseConfig returns the connection settings of the secondary
+// driver; ok is false when DB_SECONDARY_DRIVER is not set
+func (c *Config) SecondaryDatabaseConfig() (cfg database.Config, ok bool) {
+	if c.Database.SecondaryDriver == "" {
+		return database.Config{}, false
+	}
+	return c.DriverDatabaseConfig(c.Database.SecondaryDriver), true
+}
+
+// DriverDatabaseConfig returns the connection settings of a driver, e.g. the
+// source or target of cmd/migrate -copy-data
+func (c *Config) DriverDatabaseConfig(driver string) database.Config {
+	return database.Config{
+		Driver: driver,
+		SQLite: database.SQLiteConfig{
+			Path: c.Database.SQLitePath,
+		},
+		Postgres: database.PostgresConfig{
+			Host: c.Database.PostgresHost,
+			Port: strconv.Itoa(c.Database.PostgresPort),
+			User: c.Database.PostgresUser,
+			Pass: c.Database.PostgresPassword,
+			DB:   c.Database.PostgresDatabase,
+			SSL:  c.Database.PostgresSSLMode,
+		},
+		Mongo: database.MongoConfig{
+			URI:            c.Database.MongoURI,
+			Database:       c.Database.MongoDatabase,
+			ReadPreference: c.Database.MongoReadPreference,
+			MaxStaleness:   c.Database.MongoMaxStaleness,
+		},
+		Replicas: database.ReplicaConfig{
+			DSNs:   c.Database.Replicas,
+			Policy: c.Database.ReplicaPolicy,
+		},
+		Pool: database.PoolConfig{
+			MaxOpenConns:    c.Database.MaxOpenConns,
+			MaxIdleConns:    c.Database.MaxIdleConns,
+			ConnMaxLifetime: c.Database.ConnMaxLifetime,
+			ConnMaxIdleTime: c.Database.ConnMaxIdleTime,
+		},
+		Retry: database.RetryConfig{
+			MaxWait:        c.Database.ConnectMaxWait,
+			InitialBackoff: c.Database.ConnectInitialBackoff,
+			MaxBackoff:     c.Database.ConnectMaxBackoff,
+		},
+		SlowQueryThreshold: c.Database.SlowQueryThreshold,
+		TablePrefix:        c.Database.TablePrefix,
+		Naming: database.NamingConfig{
+			SingularTables: c.Database.SingularTables,
+			Columns:        c.Database.ColumnNames,
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDatabaseConfig tests that the settings of every driver reach the
+// database configuration
+func TestDatabaseConfig(t *testing.T) {
+	cfg := &Config{}
+	cfg.Database.Driver = "mongo"
+	cfg.Database.SQLitePath = "./data/app.db"
+	cfg.Database.PostgresHost = "db"
+	cfg.Database.PostgresPort = 5433
+	cfg.Database.PostgresDatabase = "app"
+	cfg.Database.MongoURI = "mongodb://db:27017"
+	cfg.Database.MongoDatabase = "app"
+	cfg.Database.MongoReadPreference = "secondaryPreferred"
+	cfg.Database.ConnectMaxWait = time.Minute
+	cfg.Database.SingularTables = true
+	cfg.Database.ColumnNames = map[string]string{"users.avatar_url": "avatar"}
+
+	dbConfig := cfg.DatabaseConfig()
+	assert.Equal(t, "mongo", dbConfig.Driver)
+	assert.Equal(t, "./data/app.db", dbConfig.SQLite.Path)
+	assert.Equal(t, "db", dbConfig.Postgres.Host)
+	assert.Equal(t, "5433", dbConfig.Postgres.Port)
+	assert.Equal(t, "app", dbConfig.Postgres.DB)
+	assert.Equal(t, "mongodb://db:27017", dbConfig.Mongo.URI)
+	assert.Equal(t, "app", dbConfig.Mongo.Database)
+	assert.Equal(t, "secondaryPreferred", dbConfig.Mongo.ReadPreference)
+	assert.Equal(t, time.Minute, dbConfig.Retry.MaxWait)
+	assert.True(t, dbConfig.Naming.SingularTables)
+	assert.Equal(t, "avatar", dbConfig.Naming.Columns["users.avatar_url"])
+}
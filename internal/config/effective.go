@@ -0,0 +1,99 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// redacted replaces secrets in printed configuration
+const redacted = "******"
+
+// dsnPassword matches the password of a key=value connection string
+var dsnPassword = regexp.MustCompile(`(?i)(password\s*=\s*)('[^']*'|\S+)`)
+
+// Effective returns the configuration as nested maps keyed by JSON field name
+// for printing. Durations are formatted like "24h0m0s" and fields tagged
+// redact:"secret" or redact:"dsn" have their secrets replaced.
+func (c *Config) Effective() map[string]any {
+	return effectiveValue(reflect.ValueOf(*c)).(map[string]any)
+}
+
+// effectiveValue converts a configuration value for printing
+func effectiveValue(v reflect.Value) any {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := make(map[string]any)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			fields[name] = redactValue(field.Tag.Get("redact"), effectiveValue(v.Field(i)))
+		}
+		return fields
+	case reflect.Slice:
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = effectiveValue(v.Index(i))
+		}
+		return items
+	}
+	return v.Interface()
+}
+
+// redactValue hides the secrets of a converted value according to its redact tag
+func redactValue(kind string, value any) any {
+	switch value := value.(type) {
+	case string:
+		switch {
+		case value == "":
+			return value
+		case kind == "secret":
+			return redacted
+		case kind == "dsn":
+			return redactDSN(value)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = redactValue(kind, item)
+		}
+	case map[string]string:
+		if kind == "" {
+			return value
+		}
+		// Keep the keys, such as key IDs, and hide the values
+		values := make(map[string]any, len(value))
+		for key, item := range value {
+			values[key] = redactValue(kind, item)
+		}
+		return values
+	}
+	return value
+}
+
+// redactDSN hides the password of a URL or key=value connection string
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "redacted")
+			return strings.Replace(u.String(), ":redacted@", ":"+redacted+"@", 1)
+		}
+		return u.String()
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+redacted)
+}
@@ -0,0 +1,12 @@
+package config
+
+import "github.com/luxixing/fx-gin-scaffold/pkg/fieldcrypt"
+
+// FieldEncryptionConfig returns the keys encrypting personal data fields
+func (c *Config) FieldEncryptionConfig() fieldcrypt.Config {
+	return fieldcrypt.Config{
+		Keys:    c.Encryption.Keys,
+		KeyFile: c.Encryption.KeyFile,
+		KeyID:   c.Encryption.KeyID,
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"github.com/luxixing/fx-gin-scaffold/pkg/httpclient"
+	"github.com/luxixing/fx-gin-scaffold/pkg/resilience"
+)
+
+// BreakerConfig returns the circuit breaker settings for external services
+func (c *Config) BreakerConfig() resilience.BreakerConfig {
+	return resilience.BreakerConfig{
+		FailureThreshold: c.Resilience.BreakerThreshold,
+		OpenTimeout:      c.Resilience.BreakerOpenTimeout,
+	}
+}
+
+// ResilienceConfig returns the retry and circuit breaker settings for
+// external services
+func (c *Config) ResilienceConfig() resilience.Config {
+	return resilience.Config{
+		Retry: resilience.RetryConfig{
+			MaxAttempts:    c.Resilience.RetryMaxAttempts,
+			InitialBackoff: c.Resilience.RetryInitialBackoff,
+			MaxBackoff:     c.Resilience.RetryMaxBackoff,
+		},
+		Breaker: c.BreakerConfig(),
+	}
+}
+
+// HTTPClientConfig returns the settings of the HTTP clients of outbound
+// integrations, retrying with the resilience settings
+func (c *Config) HTTPClientConfig() httpclient.Config {
+	return httpclient.Config{
+		Timeout:               c.HTTPClient.Timeout,
+		DialTimeout:           c.HTTPClient.DialTimeout,
+		TLSHandshakeTimeout:   c.HTTPClient.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: c.HTTPClient.ResponseHeaderTimeout,
+		IdleConnTimeout:       c.HTTPClient.IdleConnTimeout,
+		MaxIdleConns:          c.HTTPClient.MaxIdleConns,
+		MaxIdleConnsPerHost:   c.HTTPClient.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       c.HTTPClient.MaxConnsPerHost,
+		ProxyURL:              c.HTTPClient.ProxyURL,
+		Retry:                 c.ResilienceConfig().Retry,
+		Tracing:               c.HTTPClient.Tracing,
+		UserAgent:             "fx-gin-scaffold/1.0",
+	}
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Watcher reloads the configuration on SIGHUP or when .env changes and
+// notifies subscribers. Only settings that are safe to change while running
+// are reloaded: the log level, CORS origins and feature flags. Changes to any
+// other setting are logged and take effect on the next restart.
+type Watcher struct {
+	path     string
+	interval time.Duration
+	log      *zap.Logger
+
+	mu          sync.RWMutex
+	current     *Config
+	subscribers []func(cfg *Config)
+
+	reloadMu sync.Mutex
+	modTime  time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewWatcher creates a watcher starting from cfg
+func NewWatcher(cfg *Config, log *zap.Logger) *Watcher {
+	return &Watcher{
+		path:     dotenvFile,
+		interval: cfg.App.ConfigWatchInterval,
+		log:      log,
+		current:  cfg,
+		modTime:  fileModTime(dotenvFile),
+	}
+}
+
+// Current returns the latest configuration
+func (w *Watcher) Current() *Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// Subscribe registers fn to be called with the new configuration after each
+// reload that changes a reloadable setting
+func (w *Watcher) Subscribe(fn func(cfg *Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload reads the configuration again and applies the reloadable settings.
+// The current configuration is kept when the new one is invalid.
+func (w *Watcher) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	next, err := load(w.path)
+	if err != nil {
+		return err
+	}
+
+	current := w.Current()
+	updated := current.withReloadable(next)
+	if !reflect.DeepEqual(*next, *next.withReloadable(current)) {
+		w.log.Warn("configuration changes other than log levels, body logging, CORS origins and feature flags require a restart")
+	}
+	if reflect.DeepEqual(*updated, *current) {
+		return nil
+	}
+
+	w.mu.Lock()
+	w.current = updated
+	subscribers := append([]func(cfg *Config){}, w.subscribers...)
+	w.mu.Unlock()
+
+	w.log.Info("configuration reloaded",
+		zap.String("log_level", updated.Logger.Level),
+		zap.Any("log_levels", updated.Logger.Levels),
+		zap.Bool("log_bodies", updated.Logger.Bodies),
+		zap.Strings("cors_origins", updated.Server.CORSOrigins),
+		zap.Strings("feature_flags", updated.Features.Enabled),
+	)
+	for _, fn := range subscribers {
+		fn(updated)
+	}
+	return nil
+}
+
+// Start reloads on SIGHUP and, when CONFIG_WATCH_INTERVAL is set, whenever
+// the .env file is modified
+func (w *Watcher) Start() {
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		defer close(w.done)
+		defer signal.Stop(signals)
+
+		var tick <-chan time.Time
+		if w.interval > 0 {
+			ticker := time.NewTicker(w.interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for {
+			select {
+			case <-signals:
+				w.reload("SIGHUP")
+			case <-tick:
+				modTime := fileModTime(w.path)
+				w.reloadMu.Lock()
+				changed := !modTime.Equal(w.modTime)
+				w.modTime = modTime
+				w.reloadMu.Unlock()
+				if changed {
+					w.reload("file change")
+				}
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops watching for changes
+func (w *Watcher) Stop() {
+	if w.stop == nil {
+		return
+	}
+	close(w.stop)
+	<-w.done
+	w.stop = nil
+}
+
+// reload reloads the configuration, logging failures
+func (w *Watcher) reload(trigger string) {
+	if err := w.Reload(); err != nil {
+		w.log.Error("failed to reload configuration", zap.String("trigger", trigger), zap.Error(err))
+	}
+}
+
+// withReloadable returns a copy of c with the reloadable settings taken from next
+func (c *Config) withReloadable(next *Config) *Config {
+	updated := *c
+	updated.Logger.Level = next.Logger.Level
+	updated.Logger.Levels = next.Logger.Levels
+	updated.Logger.Bodies = next.Logger.Bodies
+	updated.Logger.BodyMaxBytes = next.Logger.BodyMaxBytes
+	updated.Server.CORSOrigins = next.Server.CORSOrigins
+	updated.Features = next.Features
+	return &updated
+}
+
+// fileModTime returns the modification time of path, or the zero time if it
+// does not exist
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
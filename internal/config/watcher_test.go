@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// writeEnv writes a .env file with a JWT secret and the given lines
+func writeEnv(t *testing.T, path, lines string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte("JWT_SECRET=test-secret\n"+lines), 0o600))
+}
+
+// TestWatcherReload tests that reloads apply reloadable settings only and
+// notify subscribers
+func TestWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	writeEnv(t, path, "LOG_LEVEL=info\nCORS_ORIGINS=https://app.example.com\nAPP_PORT=8080\n")
+
+	cfg, err := load(path)
+	require.NoError(t, err)
+
+	w := NewWatcher(cfg, zap.NewNop())
+	w.path = path
+
+	var notified []*Config
+	w.Subscribe(func(cfg *Config) { notified = append(notified, cfg) })
+
+	// Unchanged configuration does not notify
+	require.NoError(t, w.Reload())
+	assert.Empty(t, notified)
+
+	writeEnv(t, path, "LOG_LEVEL=debug\nLOG_LEVELS=db=warn\nLOG_BODIES=true\nCORS_ORIGINS=https://new.example.com\nFEATURE_FLAGS=beta,search\nAPP_PORT=9090\n")
+	require.NoError(t, w.Reload())
+
+	require.Len(t, notified, 1)
+	current := w.Current()
+	assert.Same(t, current, notified[0])
+	assert.Equal(t, "debug", current.Logger.Level)
+	assert.Equal(t, map[string]string{"db": "warn"}, current.Logger.Levels)
+	assert.True(t, current.Logger.Bodies)
+	assert.Equal(t, []string{"https://new.example.com"}, current.Server.CORSOrigins)
+	assert.True(t, current.FeatureEnabled("search"))
+	assert.False(t, current.FeatureEnabled("dark-mode"))
+
+	// Structural settings need a restart
+	assert.Equal(t, 8080, current.Server.Port)
+
+	// The original configuration is not modified
+	assert.Equal(t, "info", cfg.Logger.Level)
+
+	// Invalid configuration keeps the current one
+	require.NoError(t, os.WriteFile(path, []byte("LOG_LEVEL=error\n"), 0o600))
+	assert.Error(t, w.Reload())
+	assert.Equal(t, "debug", w.Current().Logger.Level)
+}
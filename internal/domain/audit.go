@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Audit actions
+const (
+	AuditActionLogin          = "auth.login"
+	AuditActionUserCreate     = "user.create"
+	AuditActionUserUpdate     = "user.update"
+	AuditActionUserDelete     = "user.delete"
+	AuditActionUserRoleChange = "user.role_change"
+	AuditActionPasswordReset  = "user.password_reset"
+	AuditActionEmailChange    = "user.email_change"
+	AuditActionDeleteRequest  = "user.delete_request"
+	AuditActionDeleteCancel   = "user.delete_cancel"
+	AuditActionDataExport     = "user.data_export"
+	AuditActionInviteCreate   = "invite.create"
+	AuditActionInviteRevoke   = "invite.revoke"
+)
+
+// PermissionAuditRead grants access to the audit log
+const PermissionAuditRead = "audit:read"
+
+// AuditLog records an action performed by a user
+type AuditLog struct {
+	ID         uint                   `json:"id" gorm:"primaryKey" bson:"-"`
+	ActorID    uint                   `json:"actor_id" gorm:"index:idx_audit_logs_actor_id" bson:"actor_id"`
+	Action     string                 `json:"action" gorm:"not null;size:50;index:idx_audit_logs_action" bson:"action"`
+	TargetType string                 `json:"target_type" gorm:"size:50" bson:"target_type"`
+	TargetID   uint                   `json:"target_id" bson:"target_id"`
+	Before     map[string]interface{} `json:"before,omitempty" gorm:"serializer:json;type:text" bson:"before,omitempty"`
+	After      map[string]interface{} `json:"after,omitempty" gorm:"serializer:json;type:text" bson:"after,omitempty"`
+	IP         string                 `json:"ip,omitempty" gorm:"size:45" bson:"ip,omitempty"`
+	CreatedAt  time.Time              `json:"created_at" gorm:"autoCreateTime;index:idx_audit_logs_created_at" bson:"created_at"`
+}
+
+// AuditSnapshot converts a value into a generic map for before/after snapshots
+func AuditSnapshot(v interface{}) map[string]interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil
+	}
+	return snapshot
+}
+
+// AuditLogFilter narrows down audit log queries
+type AuditLogFilter struct {
+	ActorID uint   `form:"actor_id"`
+	Action  string `form:"action"`
+}
+
+// Actor identifies who is performing a request
+type Actor struct {
+	UserID    uint
+	Role      string
+	IP        string
+	UserAgent string
+}
+
+type actorContextKey struct{}
+
+// WithActor returns a context carrying the actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in the context, if any
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorContextKey{}).(Actor)
+	return actor, ok
+}
+
+// AuditLogRepository defines the interface for audit log data access
+type AuditLogRepository interface {
+	// Create stores a new audit log entry
+	Create(ctx context.Context, entry *AuditLog) error
+
+	// List retrieves audit log entries matching the filter, newest first
+	List(ctx context.Context, filter AuditLogFilter, offset, limit int) ([]*AuditLog, int64, error)
+
+	// CountByDay counts the entries of an action on each UTC day since
+	// since, oldest first, omitting days without any
+	CountByDay(ctx context.Context, action string, since time.Time) ([]DailyCount, error)
+}
+
+// AuditService defines the interface for recording and querying audit logs
+type AuditService interface {
+	// Record stores an audit log entry, filling actor and IP from the context
+	Record(ctx context.Context, entry *AuditLog) error
+
+	// List retrieves audit log entries with pagination
+	List(ctx context.Context, filter AuditLogFilter, offset, limit int) ([]*AuditLog, int64, error)
+}
@@ -2,22 +2,60 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 // JWTClaims represents JWT claims
 type JWTClaims struct {
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// EmailChangeClaims represents the claims of an email change confirmation token
+type EmailChangeClaims struct {
 	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	Email  string `json:"new_email"`
 	jwt.RegisteredClaims
 }
 
+// TokenPair represents an access token together with its refresh token
+type TokenPair struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
 // AuthResponse represents authentication response
 type AuthResponse struct {
-	Token string        `json:"token"`
-	User  *UserResponse `json:"user"`
+	Token        string        `json:"token"`
+	RefreshToken string        `json:"refresh_token"`
+	ExpiresAt    time.Time     `json:"expires_at"`
+	User         *UserResponse `json:"user"`
+}
+
+// NewAuthResponse creates an authentication response from a token pair
+func NewAuthResponse(pair *TokenPair, user *UserResponse) *AuthResponse {
+	return &AuthResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresAt:    pair.ExpiresAt,
+		User:         user,
+	}
+}
+
+// RefreshTokenRequest represents the request for refreshing or revoking a refresh token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// LogoutRequest represents the logout request
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
 // AuthService defines the interface for authentication operations
@@ -28,8 +66,42 @@ type AuthService interface {
 	// ValidateToken validates a JWT token and returns claims
 	ValidateToken(tokenString string) (*JWTClaims, error)
 	
-	// RefreshToken refreshes an existing token
-	RefreshToken(ctx context.Context, tokenString string) (string, error)
+	// IssueTokenPair starts a session for the device of the context's actor
+	// and issues its access token and persisted refresh token
+	IssueTokenPair(ctx context.Context, user *User) (*TokenPair, error)
+	
+	// RefreshToken rotates a refresh token and returns a new token pair
+	RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error)
+	
+	// RevokeRefreshToken revokes a refresh token
+	RevokeRefreshToken(ctx context.Context, refreshToken string) error
+	
+	// RevokeAccessToken blacklists an access token until it expires
+	RevokeAccessToken(ctx context.Context, tokenString string) error
+	
+	// RevokeAllRefreshTokens revokes every refresh token of a user
+	RevokeAllRefreshTokens(ctx context.Context, userID uint) error
+	
+	// ListSessions retrieves the user's active sessions, flagging the current one
+	ListSessions(ctx context.Context, userID uint, currentSessionID string) ([]*Session, error)
+	
+	// RevokeSession ends a session, rejecting its refresh and access tokens
+	RevokeSession(ctx context.Context, userID uint, sessionID string) error
+	
+	// GenerateEmailChangeToken generates a token confirming the user's pending email
+	GenerateEmailChangeToken(user *User) (string, error)
+	
+	// ValidateEmailChangeToken validates an email change token and returns its claims
+	ValidateEmailChangeToken(tokenString string) (*EmailChangeClaims, error)
+}
+
+// TokenBlacklist defines the interface for tracking revoked access tokens
+type TokenBlacklist interface {
+	// Add blacklists a token ID until the given expiry time
+	Add(ctx context.Context, tokenID string, expiresAt time.Time) error
+	
+	// Contains reports whether a token ID has been blacklisted
+	Contains(ctx context.Context, tokenID string) (bool, error)
 }
 
 // ContextKey represents context keys
@@ -44,4 +116,4 @@ const (
 	
 	// RoleContextKey is the key for user role in context
 	RoleContextKey ContextKey = "role"
-)
\ No newline at end of file
+)
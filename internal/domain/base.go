@@ -1,30 +1,40 @@
 package domain
 
 import (
-	"sync"
-)
+	"reflect"
+	"sync/atomic"
 
-var (
-	tablePrefix string
-	once        sync.Once
+	"gorm.io/gorm/schema"
 )
 
-// SetTablePrefix sets the global table prefix for all models
-func SetTablePrefix(prefix string) {
-	once.Do(func() {
-		tablePrefix = prefix
-	})
+// TableNamer names tables and collections after model type names, as the
+// GORM naming strategy does
+type TableNamer interface {
+	TableName(name string) string
 }
 
-// GetTablePrefix returns the current table prefix
-func GetTablePrefix() string {
-	return tablePrefix
+// tableNamer holds the TableNamer set by SetTableNamer
+var tableNamer atomic.Pointer[TableNamer]
+
+// SetTableNamer sets how TableName names tables and collections. Set the
+// naming strategy of the database connection, so that Mongo collections and
+// raw SQL use the tables GORM does; GORM caches table names, so set it before
+// opening connections.
+func SetTableNamer(namer TableNamer) {
+	tableNamer.Store(&namer)
 }
 
-// GetTableName returns the full table name with prefix
-func GetTableName(tableName string) string {
-	if tablePrefix == "" {
-		return tableName
+// TableName returns the table or collection name of a model, e.g. fx_users
+// for User with the fx_ prefix. Without SetTableNamer, GORM's default naming
+// is used.
+func TableName(model interface{}) string {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
 	}
-	return tablePrefix + tableName
-}
\ No newline at end of file
+
+	if namer := tableNamer.Load(); namer != nil {
+		return (*namer).TableName(t.Name())
+	}
+	return schema.NamingStrategy{}.TableName(t.Name())
+}
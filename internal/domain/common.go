@@ -3,13 +3,37 @@ package domain
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 )
 
 // Error represents a domain error
 type Error struct {
-	Code    string `json:"code"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details string       `json:"details,omitempty"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError describes why a single request field failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
 	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+}
+
+// Validator validates structs against their validate tags
+type Validator interface {
+	// Validate normalizes v, when it is a pointer, and returns a validation
+	// *Error listing every invalid field, or nil if the value is valid
+	Validate(v any) error
+}
+
+// Normalizer is implemented by request DTOs that normalize their input
+// beyond what their sanitize tags express, such as removing duplicates.
+// Validators call Normalize after applying the tags and before validating.
+type Normalizer interface {
+	Normalize()
 }
 
 func (e *Error) Error() string {
@@ -28,10 +52,16 @@ const (
 	ErrCodeInvalidToken    = "INVALID_TOKEN"
 	ErrCodeInvalidPassword = "INVALID_PASSWORD"
 
+	// Registration errors
+	ErrCodeRegistrationClosed = "REGISTRATION_CLOSED"
+
 	// Resource errors
 	ErrCodeNotFound      = "NOT_FOUND"
 	ErrCodeAlreadyExists = "ALREADY_EXISTS"
 
+	// Throttling errors
+	ErrCodeTooManyRequests = "TOO_MANY_REQUESTS"
+
 	// Internal errors
 	ErrCodeInternal = "INTERNAL_ERROR"
 	ErrCodeDatabase = "DATABASE_ERROR"
@@ -45,7 +75,10 @@ var (
 	ErrUnauthorized    = &Error{Code: ErrCodeUnauthorized, Message: "Unauthorized"}
 	ErrForbidden       = &Error{Code: ErrCodeForbidden, Message: "Forbidden"}
 	ErrInvalidToken    = &Error{Code: ErrCodeInvalidToken, Message: "Invalid token"}
+	ErrTokenNotFound   = &Error{Code: ErrCodeNotFound, Message: "Token not found"}
 	ErrValidation      = &Error{Code: ErrCodeValidation, Message: "Validation failed"}
+	ErrTooManyRequests = &Error{Code: ErrCodeTooManyRequests, Message: "Too many requests"}
+	ErrLoginLocked     = &Error{Code: ErrCodeTooManyRequests, Message: "Too many failed login attempts, try again later"}
 	ErrInternalServer  = &Error{Code: ErrCodeInternal, Message: "Internal server error"}
 )
 
@@ -74,6 +107,70 @@ type Meta struct {
 	Limit  int   `json:"limit,omitempty"`
 	Page   int   `json:"page,omitempty"`
 	Pages  int   `json:"pages,omitempty"`
+
+	// Keyset pagination cursors, see CursorPaginationRequest
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+
+	// Links to other pages, when PAGINATION_LINKS is enabled
+	Links *Links `json:"links,omitempty"`
+}
+
+// Links are links to the pages of a paginated response, relative to the
+// server. Links that don't apply, such as prev on the first page, are empty.
+type Links struct {
+	Self  string `json:"self,omitempty"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// PageLinks returns the links of a page requested with u, keeping the
+// query parameters other than the page position. Offset pages link by page
+// number; keyset pages, which have no page number, link by cursor and have
+// no last page. It returns nil when meta does not describe a page.
+func PageLinks(u *url.URL, meta *Meta) *Links {
+	if meta == nil || (meta.Page == 0 && meta.Limit == 0) {
+		return nil
+	}
+
+	link := func(set map[string]string) string {
+		query := u.Query()
+		for _, key := range []string{"page", "after", "before"} {
+			query.Del(key)
+		}
+		for key, value := range set {
+			query.Set(key, value)
+		}
+		return (&url.URL{Path: u.Path, RawQuery: query.Encode()}).RequestURI()
+	}
+
+	links := &Links{Self: u.RequestURI()}
+	if meta.Page > 0 {
+		last := meta.Pages
+		if last < 1 {
+			last = 1
+		}
+		links.First = link(map[string]string{"page": "1"})
+		links.Last = link(map[string]string{"page": strconv.Itoa(last)})
+		if meta.Page > 1 {
+			links.Prev = link(map[string]string{"page": strconv.Itoa(min(meta.Page-1, last))})
+		}
+		if meta.Page < meta.Pages {
+			links.Next = link(map[string]string{"page": strconv.Itoa(meta.Page + 1)})
+		}
+		return links
+	}
+
+	links.First = link(nil)
+	if meta.PrevCursor != "" {
+		links.Prev = link(map[string]string{"before": meta.PrevCursor})
+	}
+	if meta.NextCursor != "" {
+		links.Next = link(map[string]string{"after": meta.NextCursor})
+	}
+	return links
 }
 
 // NewSuccessResponse creates a success response
@@ -101,6 +198,38 @@ func NewErrorResponse(err *Error) *Response {
 	}
 }
 
+// ProblemMediaType is the media type of RFC 7807 problem details
+const ProblemMediaType = "application/problem+json"
+
+// Problem represents an error as RFC 7807 problem details, the error format
+// of raw responses. The code, details and fields of the domain error are
+// kept as extension members.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code,omitempty"`
+	Details  string       `json:"details,omitempty"`
+	Fields   []FieldError `json:"fields,omitempty"`
+}
+
+// NewProblem creates problem details for an error returned with status for
+// the request path instance
+func NewProblem(err *Error, status int, instance string) *Problem {
+	return &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   err.Message,
+		Instance: instance,
+		Code:     err.Code,
+		Details:  err.Details,
+		Fields:   err.Fields,
+	}
+}
+
 // PaginationRequest represents pagination parameters
 type PaginationRequest struct {
 	Page  int `form:"page,default=1" validate:"min=1"`
@@ -132,12 +261,14 @@ func HTTPStatusFromError(err error) int {
 			return http.StatusBadRequest
 		case ErrCodeUnauthorized, ErrCodeInvalidToken, ErrCodeInvalidPassword:
 			return http.StatusUnauthorized
-		case ErrCodeForbidden:
+		case ErrCodeForbidden, ErrCodeRegistrationClosed:
 			return http.StatusForbidden
 		case ErrCodeNotFound:
 			return http.StatusNotFound
 		case ErrCodeAlreadyExists:
 			return http.StatusConflict
+		case ErrCodeTooManyRequests:
+			return http.StatusTooManyRequests
 		default:
 			return http.StatusInternalServerError
 		}
@@ -160,5 +291,15 @@ func ValidationError(field, message string) *Error {
 		Code:    ErrCodeValidation,
 		Message: fmt.Sprintf("Validation failed for field '%s': %s", field, message),
 		Details: fmt.Sprintf("field=%s", field),
+		Fields:  []FieldError{{Field: field, Message: message}},
+	}
+}
+
+// NewValidationError creates a validation error for several invalid fields
+func NewValidationError(fields []FieldError) *Error {
+	return &Error{
+		Code:    ErrCodeValidation,
+		Message: ErrValidation.Message,
+		Fields:  fields,
 	}
 }
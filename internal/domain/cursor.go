@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = &Error{Code: ErrCodeInvalid, Message: "Invalid pagination cursor"}
+
+// Cursor identifies a row position in a created_at DESC, id DESC ordering
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"id"`
+}
+
+// Encode returns the opaque, URL-safe representation of the cursor
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// CursorPage describes a keyset page for repositories. Results are always
+// returned newest first; After walks towards older rows and Before towards
+// newer ones.
+type CursorPage struct {
+	After  *Cursor
+	Before *Cursor
+	Limit  int
+}
+
+// CursorPaginationRequest represents keyset pagination parameters
+type CursorPaginationRequest struct {
+	After  string `form:"after"`
+	Before string `form:"before"`
+	Limit  int    `form:"limit,default=10" validate:"min=1,max=100"`
+}
+
+// IsSet reports whether the request asks for keyset pagination
+func (p *CursorPaginationRequest) IsSet() bool {
+	return p.After != "" || p.Before != ""
+}
+
+// ToPage decodes the cursors into a repository page
+func (p *CursorPaginationRequest) ToPage() (*CursorPage, error) {
+	if p.After != "" && p.Before != "" {
+		return nil, ValidationError("before", "cannot be combined with after")
+	}
+
+	page := &CursorPage{Limit: p.Limit}
+	var err error
+	if p.After != "" {
+		if page.After, err = DecodeCursor(p.After); err != nil {
+			return nil, err
+		}
+	}
+	if p.Before != "" {
+		if page.Before, err = DecodeCursor(p.Before); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// GetMeta creates cursor metadata from the first and last returned rows.
+// hasMore reports whether rows exist past the end of the page in the
+// direction of travel.
+func (p *CursorPaginationRequest) GetMeta(first, last *Cursor, hasMore bool) *Meta {
+	meta := &Meta{Limit: p.Limit}
+	if first == nil || last == nil {
+		return meta
+	}
+
+	if p.Before != "" {
+		meta.NextCursor = last.Encode()
+		if hasMore {
+			meta.PrevCursor = first.Encode()
+		}
+		return meta
+	}
+
+	if hasMore {
+		meta.NextCursor = last.Encode()
+	}
+	if p.After != "" {
+		meta.PrevCursor = first.Encode()
+	}
+	return meta
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Data export formats
+const (
+	DataExportFormatJSON = "json"
+	DataExportFormatZIP  = "zip"
+)
+
+// DataExport bundles the personal data stored about a user
+type DataExport struct {
+	ExportedAt time.Time     `json:"exported_at"`
+	Profile    *UserResponse `json:"profile"`
+	Settings   UserSettings  `json:"settings"`
+	Sessions   []*Session    `json:"sessions"`
+	// AuditLogs are the entries of actions the user performed, newest first
+	AuditLogs []*AuditLog `json:"audit_logs"`
+}
+
+// WriteZIP writes the export as a ZIP archive holding one JSON file per
+// section
+func (e *DataExport) WriteZIP(w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", e.Profile},
+		{"settings.json", e.Settings},
+		{"sessions.json", e.Sessions},
+		{"audit_logs.json", e.AuditLogs},
+	}
+	for _, file := range files {
+		f, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: e.ExportedAt})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// DataExportService defines the interface for exporting a user's personal data
+type DataExportService interface {
+	// Export collects the personal data of a user
+	Export(ctx context.Context, userID uint) (*DataExport, error)
+}
@@ -0,0 +1,37 @@
+package domain
+
+// Domain events are published on the in-process event bus (pkg/events) by
+// services after a change succeeds. Subscribers such as audit logging, email
+// and webhooks react to them without the publisher knowing about them.
+
+// UserRegistered is published after a user account is created
+type UserRegistered struct {
+	User *UserResponse
+}
+
+// EventName returns the event name
+func (UserRegistered) EventName() string { return "user.registered" }
+
+// UserUpdated is published after a user's profile, email, role or status changes
+type UserUpdated struct {
+	User *UserResponse
+}
+
+// EventName returns the event name
+func (UserUpdated) EventName() string { return "user.updated" }
+
+// UserDeleted is published after a user account is deleted
+type UserDeleted struct {
+	User *UserResponse
+}
+
+// EventName returns the event name
+func (UserDeleted) EventName() string { return "user.deleted" }
+
+// LoginSucceeded is published after a user logs in with their password
+type LoginSucceeded struct {
+	User *UserResponse
+}
+
+// EventName returns the event name
+func (LoginSucceeded) EventName() string { return "auth.login_succeeded" }
@@ -0,0 +1,15 @@
+package domain
+
+// PermissionFilesRead grants access to every user's private files
+const PermissionFilesRead = "files:read"
+
+// Stored files are laid out by visibility under the storage root:
+// public/<path> is readable by anyone, users/<user_id>/<path> only by its
+// owner and roles granted PermissionFilesRead.
+const (
+	FilesPublicDir = "public"
+	FilesUserDir   = "users"
+)
+
+// ErrFileNotFound is returned when a stored file does not exist or is hidden
+var ErrFileNotFound = &Error{Code: ErrCodeNotFound, Message: "File not found"}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Health statuses
+const (
+	HealthStatusUp       = "up"
+	HealthStatusDown     = "down"
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded"
+	HealthStatusDraining = "draining"
+)
+
+// HealthCheck is the result of probing a single dependency
+type HealthCheck struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// HealthReport aggregates dependency probes
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+	Time   time.Time              `json:"time"`
+}
+
+// Healthy returns true if every dependency is up
+func (r *HealthReport) Healthy() bool {
+	return r.Status == HealthStatusOK
+}
+
+// HealthService defines the interface for dependency health probes
+type HealthService interface {
+	// Readiness probes every dependency required to serve traffic
+	Readiness(ctx context.Context) *HealthReport
+	// Drain makes readiness report draining from now on, while the
+	// application keeps serving before it stops
+	Drain()
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PermissionInvitesManage grants access to registration invites
+const PermissionInvitesManage = "invites:manage"
+
+// Invite statuses
+const (
+	InviteStatusPending  = "pending"
+	InviteStatusAccepted = "accepted"
+	InviteStatusRevoked  = "revoked"
+	InviteStatusExpired  = "expired"
+)
+
+// Registration and invite errors
+var (
+	ErrRegistrationClosed = &Error{Code: ErrCodeRegistrationClosed, Message: "Registration is closed"}
+	ErrInviteNotFound     = &Error{Code: ErrCodeNotFound, Message: "Invite not found or expired"}
+	ErrInviteRequired     = &Error{Code: ErrCodeForbidden, Message: "Registration requires an invite code"}
+	ErrInviteMismatch     = &Error{Code: ErrCodeForbidden, Message: "Invite was sent to a different email address"}
+)
+
+// Invite allows someone to register when registration is invite-only, with
+// the role chosen by the admin who invited them. Only the hash of the emailed
+// invite code is stored.
+type Invite struct {
+	ID           uint       `json:"id" gorm:"primaryKey" bson:"id"`
+	Email        string     `json:"email" gorm:"not null;size:255;index:idx_invites_email" bson:"email"`
+	Role         string     `json:"role" gorm:"not null;size:50" bson:"role"`
+	TokenHash    string     `json:"-" gorm:"not null;size:64;uniqueIndex:idx_invites_token_hash" bson:"token_hash"`
+	InvitedByID  uint       `json:"invited_by_id" gorm:"not null" bson:"invited_by_id"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null" bson:"expires_at"`
+	AcceptedAt   *time.Time `json:"accepted_at,omitempty" bson:"accepted_at,omitempty"`
+	AcceptedByID *uint      `json:"accepted_by_id,omitempty" bson:"accepted_by_id,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime" bson:"created_at"`
+}
+
+// Status returns whether the invite is pending, accepted, revoked or expired
+func (i *Invite) Status() string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InviteStatusAccepted
+	case i.RevokedAt != nil:
+		return InviteStatusRevoked
+	case !time.Now().Before(i.ExpiresAt):
+		return InviteStatusExpired
+	default:
+		return InviteStatusPending
+	}
+}
+
+// InviteCreateRequest represents the request for inviting someone to register
+type InviteCreateRequest struct {
+	Email string `json:"email" sanitize:"trim,lower" validate:"required,email"`
+	Role  string `json:"role" validate:"required"`
+}
+
+// InviteFilter narrows down invite queries
+type InviteFilter struct {
+	Status string `form:"status" validate:"omitempty,oneof=pending accepted revoked expired"`
+	Email  string `form:"email" sanitize:"trim,lower"`
+}
+
+// InviteResponse represents an invite returned to clients
+type InviteResponse struct {
+	ID           uint       `json:"id"`
+	Email        string     `json:"email"`
+	Role         string     `json:"role"`
+	Status       string     `json:"status"`
+	InvitedByID  uint       `json:"invited_by_id"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	AcceptedAt   *time.Time `json:"accepted_at,omitempty"`
+	AcceptedByID *uint      `json:"accepted_by_id,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// ToResponse converts Invite to InviteResponse
+func (i *Invite) ToResponse() *InviteResponse {
+	return &InviteResponse{
+		ID:           i.ID,
+		Email:        i.Email,
+		Role:         i.Role,
+		Status:       i.Status(),
+		InvitedByID:  i.InvitedByID,
+		ExpiresAt:    i.ExpiresAt,
+		AcceptedAt:   i.AcceptedAt,
+		AcceptedByID: i.AcceptedByID,
+		RevokedAt:    i.RevokedAt,
+		CreatedAt:    i.CreatedAt,
+	}
+}
+
+// InviteRepository defines the interface for invite data access
+type InviteRepository interface {
+	// Create creates a new invite
+	Create(ctx context.Context, invite *Invite) error
+
+	// GetByID retrieves an invite by ID
+	GetByID(ctx context.Context, id uint) (*Invite, error)
+
+	// GetByTokenHash retrieves an invite by the hash of its code
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Invite, error)
+
+	// Update updates an existing invite
+	Update(ctx context.Context, invite *Invite) error
+
+	// List retrieves invites matching the filter with pagination, newest first
+	List(ctx context.Context, filter InviteFilter, offset, limit int) ([]*Invite, int64, error)
+}
+
+// InviteService defines the interface for managing registration invites on
+// behalf of the Actor in ctx. Invite codes are redeemed by
+// UserService.Register.
+type InviteService interface {
+	// CreateInvite stores an invite and emails its code to the invitee
+	CreateInvite(ctx context.Context, req *InviteCreateRequest) (*InviteResponse, error)
+
+	// ListInvites retrieves invites with pagination, newest first
+	ListInvites(ctx context.Context, filter InviteFilter, offset, limit int) ([]*InviteResponse, int64, error)
+
+	// RevokeInvite revokes a pending invite
+	RevokeInvite(ctx context.Context, id uint) (*InviteResponse, error)
+}
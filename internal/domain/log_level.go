@@ -0,0 +1,28 @@
+package domain
+
+import "context"
+
+// PermissionLogsManage grants access to the runtime log levels
+const PermissionLogsManage = "logs:manage"
+
+// LogLevels describes the global log level and the effective level of each
+// module
+type LogLevels struct {
+	Level   string            `json:"level"`
+	Modules map[string]string `json:"modules"`
+}
+
+// LogLevelUpdateRequest represents the request for changing a log level
+type LogLevelUpdateRequest struct {
+	// Module is the module to change, such as db; empty changes the global level
+	Module string `json:"module"`
+	// Level is debug, info, warn or error; empty makes a module follow the
+	// global level again
+	Level string `json:"level"`
+}
+
+// LogLevelService defines the interface for changing log levels at runtime
+type LogLevelService interface {
+	GetLogLevels(ctx context.Context) *LogLevels
+	SetLogLevel(ctx context.Context, req LogLevelUpdateRequest) (*LogLevels, error)
+}
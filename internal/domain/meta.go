@@ -0,0 +1,24 @@
+package domain
+
+// PublicConfig is the server configuration frontends adapt to, served
+// without authentication
+type PublicConfig struct {
+	// RegistrationMode is open, invite or closed
+	RegistrationMode string `json:"registration_mode"`
+}
+
+// ServerMeta describes the running server, served without authentication
+type ServerMeta struct {
+	Version   string       `json:"version"`
+	Commit    string       `json:"commit,omitempty"`
+	BuildTime string       `json:"build_time,omitempty"`
+	Features  MetaFeatures `json:"features"`
+}
+
+// MetaFeatures lists the optional features enabled on the server
+type MetaFeatures struct {
+	Swagger          bool   `json:"swagger"`
+	RegistrationMode string `json:"registration_mode"`
+	// FeatureFlags are the enabled feature flags, as reloaded from FEATURE_FLAGS
+	FeatureFlags []string `json:"feature_flags"`
+}
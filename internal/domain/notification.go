@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Realtime event types
+const (
+	EventProfileUpdated = "profile.updated"
+)
+
+// Event is a realtime notification pushed to connected clients
+type Event struct {
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// NewEvent creates an event stamped with the current time
+func NewEvent(eventType string, data interface{}) *Event {
+	return &Event{
+		Type:      eventType,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// Notifier pushes events to connected clients
+type Notifier interface {
+	// NotifyUser sends an event to every connection of a user
+	NotifyUser(ctx context.Context, userID uint, event *Event) error
+
+	// Broadcast sends an event to every connected user
+	Broadcast(ctx context.Context, event *Event) error
+}
+
+// EventNotificationCreated is pushed to a user when a notification is stored for them
+const EventNotificationCreated = "notification.created"
+
+// Notification types
+const (
+	NotificationTypeWelcome = "welcome"
+)
+
+// ErrNotificationNotFound is returned when a notification doesn't exist or
+// belongs to another user
+var ErrNotificationNotFound = &Error{Code: ErrCodeNotFound, Message: "Notification not found"}
+
+// Notification is an in-app message kept in a user's inbox until deleted
+type Notification struct {
+	ID     uint   `json:"id" gorm:"primaryKey" bson:"id"`
+	UserID uint   `json:"user_id" gorm:"not null;index:idx_notifications_user_id_read_at,priority:1" bson:"user_id"`
+	User   *User  `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" bson:"-"`
+	Type   string `json:"type" gorm:"not null;size:50" bson:"type"`
+	Title  string `json:"title" gorm:"not null;size:255" bson:"title"`
+	Body   string `json:"body,omitempty" gorm:"type:text" bson:"body,omitempty"`
+	// Data carries type specific details for clients, e.g. IDs to link to
+	Data      map[string]interface{} `json:"data,omitempty" gorm:"serializer:json;type:text" bson:"data,omitempty"`
+	ReadAt    *time.Time             `json:"read_at,omitempty" gorm:"index:idx_notifications_user_id_read_at,priority:2" bson:"read_at,omitempty"`
+	CreatedAt time.Time              `json:"created_at" gorm:"autoCreateTime" bson:"created_at"`
+}
+
+// NotificationFilter narrows down notification queries
+type NotificationFilter struct {
+	UnreadOnly bool `form:"unread"`
+}
+
+// NotificationCountResponse represents a number of notifications, e.g. the
+// unread ones or the ones just marked as read
+type NotificationCountResponse struct {
+	Count int64 `json:"count"`
+}
+
+// NotificationRepository defines the interface for notification data access
+type NotificationRepository interface {
+	// Create creates a new notification
+	Create(ctx context.Context, notification *Notification) error
+
+	// ListByUser retrieves a user's notifications with pagination, newest first
+	ListByUser(ctx context.Context, userID uint, filter NotificationFilter, offset, limit int) ([]*Notification, int64, error)
+
+	// CountUnread counts a user's unread notifications
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+
+	// MarkRead marks a notification of the user as read at the given time.
+	// Notifications that are already read keep their time.
+	MarkRead(ctx context.Context, userID, id uint, at time.Time) error
+
+	// MarkAllRead marks every unread notification of the user as read and
+	// returns how many were changed
+	MarkAllRead(ctx context.Context, userID uint, at time.Time) (int64, error)
+}
+
+// NotificationService defines the interface for in-app notifications
+type NotificationService interface {
+	// Notify stores a notification for its user and pushes it to the user's
+	// realtime connections
+	Notify(ctx context.Context, notification *Notification) error
+
+	// ListNotifications retrieves the user's notifications with pagination
+	ListNotifications(ctx context.Context, userID uint, filter NotificationFilter, offset, limit int) ([]*Notification, int64, error)
+
+	// UnreadCount counts the user's unread notifications
+	UnreadCount(ctx context.Context, userID uint) (int64, error)
+
+	// MarkRead marks one of the user's notifications as read
+	MarkRead(ctx context.Context, userID, id uint) error
+
+	// MarkAllRead marks every notification of the user as read and returns
+	// how many were unread
+	MarkAllRead(ctx context.Context, userID uint) (int64, error)
+}
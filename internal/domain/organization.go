@@ -0,0 +1,301 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PermissionOrganizationsManage grants owner access to every organization
+const PermissionOrganizationsManage = "organizations:manage"
+
+// Organization membership roles, from most to least privileged
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
+
+// OrgRoleContextKey is the key for the user's role in the requested organization
+const OrgRoleContextKey ContextKey = "org_role"
+
+// orgRoleRanks orders the membership roles
+var orgRoleRanks = map[string]int{
+	OrgRoleOwner:  3,
+	OrgRoleAdmin:  2,
+	OrgRoleMember: 1,
+}
+
+// OrgRoleAtLeast reports whether role grants at least the access of minRole.
+// Unknown roles grant nothing and are never satisfied.
+func OrgRoleAtLeast(role, minRole string) bool {
+	return orgRoleRanks[minRole] > 0 && orgRoleRanks[role] >= orgRoleRanks[minRole]
+}
+
+// Organization errors
+var (
+	ErrOrganizationNotFound = &Error{Code: ErrCodeNotFound, Message: "Organization not found"}
+	ErrOrganizationExists   = &Error{Code: ErrCodeAlreadyExists, Message: "Organization slug is already taken"}
+	ErrMembershipNotFound   = &Error{Code: ErrCodeNotFound, Message: "Member not found"}
+	ErrAlreadyMember        = &Error{Code: ErrCodeAlreadyExists, Message: "User is already a member of the organization"}
+	ErrInvitationNotFound   = &Error{Code: ErrCodeNotFound, Message: "Invitation not found or expired"}
+	ErrLastOwner            = &Error{Code: ErrCodeInvalid, Message: "An organization must keep at least one owner"}
+)
+
+// Organization represents a group of users sharing resources
+type Organization struct {
+	ID        uint      `json:"id" gorm:"primaryKey" bson:"id"`
+	Name      string    `json:"name" gorm:"not null;size:100" bson:"name"`
+	Slug      string    `json:"slug" gorm:"not null;size:50;uniqueIndex:idx_organizations_slug" bson:"slug"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime" bson:"updated_at"`
+}
+
+// Membership ties a user to an organization with a role
+type Membership struct {
+	ID             uint          `json:"id" gorm:"primaryKey" bson:"id"`
+	OrganizationID uint          `json:"organization_id" gorm:"not null;uniqueIndex:idx_memberships_org_user" bson:"organization_id"`
+	Organization   *Organization `json:"-" gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE" bson:"-"`
+	UserID         uint          `json:"user_id" gorm:"not null;uniqueIndex:idx_memberships_org_user;index:idx_memberships_user_id" bson:"user_id"`
+	User           *User         `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" bson:"-"`
+	Role           string        `json:"role" gorm:"not null;size:20" bson:"role"`
+	CreatedAt      time.Time     `json:"created_at" gorm:"autoCreateTime" bson:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at" gorm:"autoUpdateTime" bson:"updated_at"`
+}
+
+// Invitation is a pending invitation to join an organization. Only the hash
+// of the emailed token is stored.
+type Invitation struct {
+	ID             uint          `json:"id" gorm:"primaryKey" bson:"id"`
+	OrganizationID uint          `json:"organization_id" gorm:"not null;index:idx_invitations_organization_id" bson:"organization_id"`
+	Organization   *Organization `json:"-" gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE" bson:"-"`
+	Email          string        `json:"email" gorm:"not null;size:255" bson:"email"`
+	Role           string        `json:"role" gorm:"not null;size:20" bson:"role"`
+	TokenHash      string        `json:"-" gorm:"not null;size:64;uniqueIndex:idx_invitations_token_hash" bson:"token_hash"`
+	InvitedByID    uint          `json:"invited_by_id" gorm:"not null" bson:"invited_by_id"`
+	ExpiresAt      time.Time     `json:"expires_at" gorm:"not null" bson:"expires_at"`
+	AcceptedAt     *time.Time    `json:"accepted_at,omitempty" bson:"accepted_at,omitempty"`
+	CreatedAt      time.Time     `json:"created_at" gorm:"autoCreateTime" bson:"created_at"`
+}
+
+// IsPending reports whether the invitation can still be accepted
+func (i *Invitation) IsPending() bool {
+	return i.AcceptedAt == nil && time.Now().Before(i.ExpiresAt)
+}
+
+// OrganizationCreateRequest represents the request for creating an organization
+type OrganizationCreateRequest struct {
+	Name string `json:"name" sanitize:"trim" validate:"required,min=2,max=100"`
+	// Slug defaults to one derived from the name
+	Slug string `json:"slug,omitempty" validate:"omitempty,min=2,max=50"`
+}
+
+// OrganizationUpdateRequest represents a partial update of an organization
+type OrganizationUpdateRequest struct {
+	Name *string `json:"name,omitempty" sanitize:"trim" validate:"omitempty,min=2,max=100"`
+	Slug *string `json:"slug,omitempty" validate:"omitempty,min=2,max=50"`
+}
+
+// MembershipUpdateRequest represents the request for changing a member's role
+type MembershipUpdateRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+// InvitationCreateRequest represents the request for inviting a user by email
+type InvitationCreateRequest struct {
+	Email string `json:"email" sanitize:"trim,lower" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+// InvitationAcceptRequest represents the request for accepting an invitation
+type InvitationAcceptRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// OrganizationResponse represents the organization data returned to clients
+type OrganizationResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Role      string    `json:"role,omitempty"` // the current user's role
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ToResponse converts Organization to OrganizationResponse with the caller's role
+func (o *Organization) ToResponse(role string) *OrganizationResponse {
+	return &OrganizationResponse{
+		ID:        o.ID,
+		Name:      o.Name,
+		Slug:      o.Slug,
+		Role:      role,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
+
+// MemberResponse represents an organization member returned to clients
+type MemberResponse struct {
+	UserID   uint      `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// ToMemberResponse converts Membership to MemberResponse
+func (m *Membership) ToMemberResponse() *MemberResponse {
+	resp := &MemberResponse{
+		UserID:   m.UserID,
+		Role:     m.Role,
+		JoinedAt: m.CreatedAt,
+	}
+	if m.User != nil {
+		resp.Name = m.User.Name
+		resp.Email = m.User.Email
+	}
+	return resp
+}
+
+// InvitationResponse represents a pending invitation returned to clients
+type InvitationResponse struct {
+	ID             uint      `json:"id"`
+	OrganizationID uint      `json:"organization_id"`
+	Email          string    `json:"email"`
+	Role           string    `json:"role"`
+	InvitedByID    uint      `json:"invited_by_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ToResponse converts Invitation to InvitationResponse
+func (i *Invitation) ToResponse() *InvitationResponse {
+	return &InvitationResponse{
+		ID:             i.ID,
+		OrganizationID: i.OrganizationID,
+		Email:          i.Email,
+		Role:           i.Role,
+		InvitedByID:    i.InvitedByID,
+		ExpiresAt:      i.ExpiresAt,
+		CreatedAt:      i.CreatedAt,
+	}
+}
+
+// OrganizationRepository defines the interface for organization data access
+type OrganizationRepository interface {
+	// Create creates a new organization
+	Create(ctx context.Context, org *Organization) error
+
+	// GetByID retrieves an organization by ID
+	GetByID(ctx context.Context, id uint) (*Organization, error)
+
+	// Update updates an existing organization
+	Update(ctx context.Context, org *Organization) error
+
+	// Delete deletes an organization
+	Delete(ctx context.Context, id uint) error
+}
+
+// MembershipRepository defines the interface for membership data access
+type MembershipRepository interface {
+	// Create adds a user to an organization; returns ErrAlreadyMember if they belong to it
+	Create(ctx context.Context, membership *Membership) error
+
+	// Get retrieves a user's membership of an organization
+	Get(ctx context.Context, orgID, userID uint) (*Membership, error)
+
+	// Update updates an existing membership
+	Update(ctx context.Context, membership *Membership) error
+
+	// Delete removes a user from an organization
+	Delete(ctx context.Context, orgID, userID uint) error
+
+	// DeleteByOrganization removes every member of an organization
+	DeleteByOrganization(ctx context.Context, orgID uint) error
+
+	// ListByOrganization retrieves the members of an organization with pagination,
+	// oldest first, loading their users where the database supports it
+	ListByOrganization(ctx context.Context, orgID uint, offset, limit int) ([]*Membership, int64, error)
+
+	// ListByUser retrieves a user's memberships with pagination, oldest first,
+	// loading their organizations where the database supports it
+	ListByUser(ctx context.Context, userID uint, offset, limit int) ([]*Membership, int64, error)
+
+	// CountByRole counts the members of an organization with a role
+	CountByRole(ctx context.Context, orgID uint, role string) (int64, error)
+}
+
+// InvitationRepository defines the interface for invitation data access
+type InvitationRepository interface {
+	// Create creates a new invitation
+	Create(ctx context.Context, invitation *Invitation) error
+
+	// GetByID retrieves an invitation by ID
+	GetByID(ctx context.Context, id uint) (*Invitation, error)
+
+	// GetByTokenHash retrieves an invitation by the hash of its token
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Invitation, error)
+
+	// Update updates an existing invitation
+	Update(ctx context.Context, invitation *Invitation) error
+
+	// Delete deletes an invitation
+	Delete(ctx context.Context, id uint) error
+
+	// DeleteByOrganization deletes every invitation to an organization
+	DeleteByOrganization(ctx context.Context, orgID uint) error
+
+	// ListPending retrieves the invitations to an organization that were
+	// neither accepted nor expired, newest first
+	ListPending(ctx context.Context, orgID uint) ([]*Invitation, error)
+}
+
+// OrganizationService defines the interface for organization business logic.
+// Methods act on behalf of the Actor in ctx. Members may view an organization
+// and its members, admins manage members and invitations, and owners manage
+// the organization itself and other owners. Roles granted
+// PermissionOrganizationsManage act as owners of every organization.
+type OrganizationService interface {
+	// CreateOrganization creates an organization owned by the actor
+	CreateOrganization(ctx context.Context, req *OrganizationCreateRequest) (*OrganizationResponse, error)
+
+	// GetOrganization retrieves an organization the actor belongs to
+	GetOrganization(ctx context.Context, id uint) (*OrganizationResponse, error)
+
+	// UpdateOrganization applies a partial update to an organization; requires admin
+	UpdateOrganization(ctx context.Context, id uint, req *OrganizationUpdateRequest) (*OrganizationResponse, error)
+
+	// DeleteOrganization deletes an organization with its members and invitations; requires owner
+	DeleteOrganization(ctx context.Context, id uint) error
+
+	// ListOrganizations retrieves the organizations the actor belongs to with pagination
+	ListOrganizations(ctx context.Context, offset, limit int) ([]*OrganizationResponse, int64, error)
+
+	// ListMembers retrieves the members of an organization with pagination
+	ListMembers(ctx context.Context, orgID uint, offset, limit int) ([]*MemberResponse, int64, error)
+
+	// UpdateMemberRole changes a member's role; requires admin, and owner to
+	// grant or revoke the owner role
+	UpdateMemberRole(ctx context.Context, orgID, userID uint, req *MembershipUpdateRequest) (*MemberResponse, error)
+
+	// RemoveMember removes a member; requires admin, owner to remove an owner,
+	// and any member may remove themselves
+	RemoveMember(ctx context.Context, orgID, userID uint) error
+
+	// InviteMember emails an invitation to join the organization; requires admin,
+	// and owner to invite owners
+	InviteMember(ctx context.Context, orgID uint, req *InvitationCreateRequest) (*InvitationResponse, error)
+
+	// ListInvitations retrieves the pending invitations to an organization; requires admin
+	ListInvitations(ctx context.Context, orgID uint) ([]*InvitationResponse, error)
+
+	// RevokeInvitation deletes a pending invitation; requires admin
+	RevokeInvitation(ctx context.Context, orgID, invitationID uint) error
+
+	// AcceptInvitation adds the actor to the organization of an invitation
+	// sent to their email address
+	AcceptInvitation(ctx context.Context, req *InvitationAcceptRequest) (*OrganizationResponse, error)
+
+	// Authorize returns the actor's role in an organization, failing unless it
+	// grants at least minRole. Use it to guard organization scoped resources.
+	Authorize(ctx context.Context, orgID uint, minRole string) (string, error)
+}
@@ -0,0 +1,20 @@
+package domain
+
+// PatchType identifies the format of a partial update
+type PatchType string
+
+const (
+	// PatchTypeMerge is a JSON Merge Patch (RFC 7396): members replace those
+	// of the resource and null members clear them
+	PatchTypeMerge PatchType = "merge"
+	// PatchTypeJSON is a JSON Patch (RFC 6902): a list of operations
+	PatchTypeJSON PatchType = "json"
+)
+
+// Patch is a partial update of a resource. It applies to the JSON document
+// of the resource's editable fields, so that clients can clear fields and
+// change nested values without sending the whole resource.
+type Patch struct {
+	Type     PatchType
+	Document []byte
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Built-in roles
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// RoleModerator can read users; unlike the built-in roles it can be removed
+// from the role catalog and deleted
+const RoleModerator = "moderator"
+
+// Built-in permissions
+const (
+	PermissionAll         = "*"
+	PermissionUsersRead   = "users:read"
+	PermissionUsersWrite  = "users:write"
+	PermissionRolesManage = "roles:manage"
+)
+
+// Permission represents a named permission in the form "resource:action"
+type Permission struct {
+	ID          uint      `json:"id" gorm:"primaryKey" bson:"-"`
+	Name        string    `json:"name" gorm:"uniqueIndex:idx_permissions_name;not null;size:100" bson:"name"`
+	Description string    `json:"description" gorm:"size:255" bson:"description"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime" bson:"created_at"`
+}
+
+// Role represents a role and the permissions granted to it
+type Role struct {
+	ID          uint      `json:"id" gorm:"primaryKey" bson:"-"`
+	Name        string    `json:"name" gorm:"uniqueIndex:idx_roles_name;not null;size:50" bson:"name"`
+	Description string    `json:"description" gorm:"size:255" bson:"description"`
+	Permissions []string  `json:"permissions" gorm:"serializer:json;type:text" bson:"permissions"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime" bson:"updated_at"`
+}
+
+// HasPermission reports whether the role grants the permission, honoring
+// the global wildcard "*" and resource wildcards such as "users:*"
+func (r *Role) HasPermission(permission string) bool {
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, granted := range r.Permissions {
+		if granted == PermissionAll || granted == permission || granted == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBuiltIn returns true for roles the application relies on
+func (r *Role) IsBuiltIn() bool {
+	return r.Name == RoleAdmin || r.Name == RoleUser
+}
+
+// RoleCreateRequest represents the request for creating a role
+type RoleCreateRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// RoleUpdateRequest represents the request for updating a role
+type RoleUpdateRequest struct {
+	Description *string  `json:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// Predefined RBAC errors
+var (
+	ErrRoleNotFound       = &Error{Code: ErrCodeNotFound, Message: "Role not found"}
+	ErrRoleExists         = &Error{Code: ErrCodeAlreadyExists, Message: "Role already exists"}
+	ErrPermissionNotFound = &Error{Code: ErrCodeNotFound, Message: "Permission not found"}
+)
+
+// RoleRepository defines the interface for role data access
+type RoleRepository interface {
+	// Create creates a new role
+	Create(ctx context.Context, role *Role) error
+
+	// GetByName retrieves a role by name
+	GetByName(ctx context.Context, name string) (*Role, error)
+
+	// List retrieves all roles
+	List(ctx context.Context) ([]*Role, error)
+
+	// Update updates an existing role
+	Update(ctx context.Context, role *Role) error
+
+	// Delete deletes a role by name
+	Delete(ctx context.Context, name string) error
+}
+
+// PermissionRepository defines the interface for permission data access
+type PermissionRepository interface {
+	// Create creates a new permission
+	Create(ctx context.Context, permission *Permission) error
+
+	// GetByName retrieves a permission by name
+	GetByName(ctx context.Context, name string) (*Permission, error)
+
+	// List retrieves all permissions
+	List(ctx context.Context) ([]*Permission, error)
+}
+
+// PermissionService defines the interface for role-based access control
+type PermissionService interface {
+	// HasPermission reports whether the role grants the permission
+	HasPermission(ctx context.Context, role, permission string) (bool, error)
+
+	// RoleExists reports whether a role is defined
+	RoleExists(ctx context.Context, role string) (bool, error)
+
+	// ListRoles retrieves all roles
+	ListRoles(ctx context.Context) ([]*Role, error)
+
+	// CreateRole creates a new role
+	CreateRole(ctx context.Context, req *RoleCreateRequest) (*Role, error)
+
+	// UpdateRole updates a role's description and permissions
+	UpdateRole(ctx context.Context, name string, req *RoleUpdateRequest) (*Role, error)
+
+	// DeleteRole deletes a custom role
+	DeleteRole(ctx context.Context, name string) error
+
+	// ListPermissions retrieves all known permissions
+	ListPermissions(ctx context.Context) ([]*Permission, error)
+}
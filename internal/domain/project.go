@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PermissionProjectsManage grants access to every user's projects
+const PermissionProjectsManage = "projects:manage"
+
+// Project statuses
+const (
+	ProjectStatusActive   = "active"
+	ProjectStatusArchived = "archived"
+)
+
+// ErrProjectNotFound is returned when a project does not exist or belongs to another user
+var ErrProjectNotFound = &Error{Code: ErrCodeNotFound, Message: "Project not found"}
+
+// Project represents a project owned by a user
+type Project struct {
+	ID          uint      `json:"id" gorm:"primaryKey" bson:"id"`
+	OwnerID     uint      `json:"owner_id" gorm:"not null;index:idx_projects_owner_id" bson:"owner_id"`
+	Owner       *User     `json:"-" gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE" bson:"-"`
+	Name        string    `json:"name" gorm:"not null;size:100" bson:"name"`
+	Description string    `json:"description" gorm:"type:text" bson:"description"`
+	Status      string    `json:"status" gorm:"not null;size:20;default:active;index:idx_projects_status" bson:"status"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_projects_created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime" bson:"updated_at"`
+}
+
+// ProjectCreateRequest represents the request for creating a project
+type ProjectCreateRequest struct {
+	Name        string `json:"name" sanitize:"trim" validate:"required,min=2,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+// ProjectUpdateRequest represents a partial update of a project
+type ProjectUpdateRequest struct {
+	Name        *string `json:"name,omitempty" sanitize:"trim" validate:"omitempty,min=2,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Status      *string `json:"status,omitempty" validate:"omitempty,oneof=active archived"`
+}
+
+// Apply copies the fields set in the request onto project
+func (r *ProjectUpdateRequest) Apply(project *Project) {
+	if r.Name != nil {
+		project.Name = *r.Name
+	}
+	if r.Description != nil {
+		project.Description = *r.Description
+	}
+	if r.Status != nil {
+		project.Status = *r.Status
+	}
+}
+
+// ProjectListFilter represents the filter and sort parameters for listing projects
+type ProjectListFilter struct {
+	Sort          string     `form:"sort"`
+	OwnerID       uint       `form:"owner_id"`
+	Status        string     `form:"status"`
+	CreatedAfter  *time.Time `form:"created_after"`
+	CreatedBefore *time.Time `form:"created_before"`
+}
+
+// projectQueryFields lists the project columns that may be filtered or sorted on
+var projectQueryFields = []string{"name", "owner_id", "status", "created_at", "updated_at"}
+
+// Query converts the filter into a validated query
+func (f *ProjectListFilter) Query() (*Query, error) {
+	q := NewQuery(projectQueryFields...).SortBy(f.Sort)
+	if f.OwnerID != 0 {
+		q.Where("owner_id", OpEq, f.OwnerID)
+	}
+	switch f.Status {
+	case "":
+	case ProjectStatusActive, ProjectStatusArchived:
+		q.Where("status", OpEq, f.Status)
+	default:
+		return nil, ValidationError("status", "must be one of: active, archived")
+	}
+	if f.CreatedAfter != nil {
+		q.Where("created_at", OpGt, *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		q.Where("created_at", OpLt, *f.CreatedBefore)
+	}
+	return q, q.Err()
+}
+
+// ProjectOwner is the public summary of a project's owner
+type ProjectOwner struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// ProjectResponse represents the project data returned to clients
+type ProjectResponse struct {
+	ID          uint          `json:"id"`
+	OwnerID     uint          `json:"owner_id"`
+	Owner       *ProjectOwner `json:"owner,omitempty"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Status      string        `json:"status"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// ToResponse converts Project to ProjectResponse
+func (p *Project) ToResponse() *ProjectResponse {
+	resp := &ProjectResponse{
+		ID:          p.ID,
+		OwnerID:     p.OwnerID,
+		Name:        p.Name,
+		Description: p.Description,
+		Status:      p.Status,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+	if p.Owner != nil {
+		resp.Owner = &ProjectOwner{ID: p.Owner.ID, Name: p.Owner.Name}
+	}
+	return resp
+}
+
+// ProjectRepository defines the interface for project data access
+type ProjectRepository interface {
+	// Create creates a new project
+	Create(ctx context.Context, project *Project) error
+
+	// GetByID retrieves a project by ID together with its owner
+	GetByID(ctx context.Context, id uint) (*Project, error)
+
+	// Update updates an existing project
+	Update(ctx context.Context, project *Project) error
+
+	// Delete deletes a project
+	Delete(ctx context.Context, id uint) error
+
+	// List retrieves projects matching the query with pagination; a nil query lists all projects
+	List(ctx context.Context, query *Query, offset, limit int) ([]*Project, int64, error)
+}
+
+// ProjectService defines the interface for project business logic. Every
+// method acts on behalf of the Actor in ctx: users see and change their own
+// projects, roles granted PermissionProjectsManage see and change all of them.
+type ProjectService interface {
+	// CreateProject creates a project owned by the actor
+	CreateProject(ctx context.Context, req *ProjectCreateRequest) (*ProjectResponse, error)
+
+	// GetProject retrieves a project the actor may access
+	GetProject(ctx context.Context, id uint) (*ProjectResponse, error)
+
+	// UpdateProject applies a partial update to a project the actor may access
+	UpdateProject(ctx context.Context, id uint, req *ProjectUpdateRequest) (*ProjectResponse, error)
+
+	// DeleteProject deletes a project the actor may access
+	DeleteProject(ctx context.Context, id uint) error
+
+	// ListProjects retrieves the projects the actor may access matching the query with pagination
+	ListProjects(ctx context.Context, query *Query, offset, limit int) ([]*ProjectResponse, int64, error)
+}
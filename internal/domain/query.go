@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FilterOp is a comparison operator used in query filters
+type FilterOp string
+
+// Supported filter operators
+const (
+	OpEq  FilterOp = "eq"
+	OpGt  FilterOp = "gt"
+	OpGte FilterOp = "gte"
+	OpLt  FilterOp = "lt"
+	OpLte FilterOp = "lte"
+)
+
+// Filter restricts results to rows where Field compares to Value using Op
+type Filter struct {
+	Field string
+	Op    FilterOp
+	Value any
+}
+
+// Sort orders results by Field
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Query is a backend-agnostic set of filters and sort terms. Field names are
+// checked against an allow-list so repositories can use them as column names
+// directly.
+type Query struct {
+	allowed map[string]bool
+	filters []Filter
+	sorts   []Sort
+	err     error
+}
+
+// NewQuery starts a query that may only reference the allowed fields
+func NewQuery(allowed ...string) *Query {
+	q := &Query{allowed: make(map[string]bool, len(allowed))}
+	for _, field := range allowed {
+		q.allowed[field] = true
+	}
+	return q
+}
+
+// Where adds a filter; referencing a field outside the allow-list makes Err non-nil
+func (q *Query) Where(field string, op FilterOp, value any) *Query {
+	if !q.check("filter", field) {
+		return q
+	}
+	q.filters = append(q.filters, Filter{Field: field, Op: op, Value: value})
+	return q
+}
+
+// SortBy parses a comma-separated sort spec such as "name,-created_at",
+// where a leading "-" means descending
+func (q *Query) SortBy(spec string) *Query {
+	for _, term := range strings.Split(spec, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		desc := strings.HasPrefix(term, "-")
+		field := strings.TrimPrefix(term, "-")
+		if !q.check("sort", field) {
+			return q
+		}
+		q.sorts = append(q.sorts, Sort{Field: field, Desc: desc})
+	}
+	return q
+}
+
+// Filters returns the filters in the order they were added
+func (q *Query) Filters() []Filter {
+	if q == nil {
+		return nil
+	}
+	return q.filters
+}
+
+// Sorts returns the sort terms in priority order
+func (q *Query) Sorts() []Sort {
+	if q == nil {
+		return nil
+	}
+	return q.sorts
+}
+
+// Err returns the first validation error encountered while building the query
+func (q *Query) Err() error {
+	if q == nil {
+		return nil
+	}
+	return q.err
+}
+
+func (q *Query) check(param, field string) bool {
+	if q.err != nil {
+		return false
+	}
+	if !q.allowed[field] {
+		q.err = ValidationError(param, fmt.Sprintf("unsupported field %q", field))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RefreshToken represents a persisted refresh token
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey" bson:"-"`
+	UserID    uint       `json:"user_id" gorm:"not null;index:idx_refresh_tokens_user_id" bson:"user_id"`
+	SessionID string     `json:"session_id" gorm:"size:64;index:idx_refresh_tokens_session_id" bson:"session_id"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex:idx_refresh_tokens_token_hash;not null;size:64" bson:"token_hash"`
+	UserAgent string     `json:"user_agent" gorm:"size:255" bson:"user_agent"`
+	IP        string     `json:"ip" gorm:"size:45" bson:"ip"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index:idx_refresh_tokens_expires_at" bson:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime" bson:"created_at"`
+}
+
+// IsExpired returns true if the refresh token has expired
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsRevoked returns true if the refresh token has been revoked
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// RefreshTokenRepository defines the interface for refresh token data access
+type RefreshTokenRepository interface {
+	// Create stores a new refresh token
+	Create(ctx context.Context, token *RefreshToken) error
+
+	// GetByHash retrieves a refresh token by its hash
+	GetByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+
+	// Revoke marks a refresh token as revoked
+	Revoke(ctx context.Context, tokenHash string) error
+
+	// RevokeAllForUser revokes every active refresh token of a user
+	RevokeAllForUser(ctx context.Context, userID uint) error
+
+	// ListActiveForUser retrieves a user's unrevoked, unexpired tokens, newest first
+	ListActiveForUser(ctx context.Context, userID uint) ([]*RefreshToken, error)
+
+	// RevokeSession revokes the active tokens of a user's session, returning
+	// ErrTokenNotFound if the session has none
+	RevokeSession(ctx context.Context, userID uint, sessionID string) error
+
+	// DeleteExpired removes tokens that expired before the given time
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "context"
+
+// SearchCollectionUsers is the search index collection holding users
+const SearchCollectionUsers = "users"
+
+// SearchService defines the interface for keeping the full-text search
+// index in sync with entities and querying it
+type SearchService interface {
+	// Enabled reports whether a search index is configured. When it is not,
+	// every other method is a no-op.
+	Enabled() bool
+
+	// IndexUser adds or replaces the user in the index
+	IndexUser(ctx context.Context, user *UserResponse) error
+
+	// RemoveUser removes the user from the index
+	RemoveUser(ctx context.Context, id uint) error
+
+	// SearchUserIDs returns the IDs of the users matching query, best match
+	// first, and the total number of matches
+	SearchUserIDs(ctx context.Context, query string, offset, limit int) ([]uint, int64, error)
+
+	// ReindexUsers rebuilds the user index from the repository and returns
+	// the number of users indexed
+	ReindexUsers(ctx context.Context) (int, error)
+}
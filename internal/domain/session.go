@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Session describes a signed-in device. Sessions are backed by refresh
+// tokens: every rotation keeps the session ID, so the newest active token of
+// a session records where and when it was last used.
+type Session struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// ErrSessionNotFound is returned when a session does not exist or has ended
+var ErrSessionNotFound = &Error{Code: ErrCodeNotFound, Message: "Session not found"}
+
+// SessionIDContextKey is the key for the session ID in context
+const SessionIDContextKey ContextKey = "session_id"
+
+// SessionBlacklistKey returns the token blacklist key marking a session revoked
+func SessionBlacklistKey(sessionID string) string {
+	return "session:" + sessionID
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Setting value types
+const (
+	SettingTypeBool   = "bool"
+	SettingTypeInt    = "int"
+	SettingTypeString = "string"
+)
+
+// SettingDefinition describes a user setting: its type, default value and
+// the values it accepts
+type SettingDefinition struct {
+	Key     string
+	Type    string
+	Default interface{}
+	// Options restricts a string setting to the listed values
+	Options []string
+	// Min and Max bound an int setting
+	Min, Max int
+}
+
+// UserSettingDefinitions is the schema of user settings. Applications add
+// their own settings by appending to it before the application starts.
+var UserSettingDefinitions = []SettingDefinition{
+	{Key: "theme", Type: SettingTypeString, Default: "system", Options: []string{"system", "light", "dark"}},
+	{Key: "email_notifications", Type: SettingTypeBool, Default: true},
+	{Key: "page_size", Type: SettingTypeInt, Default: 10, Min: 1, Max: 100},
+}
+
+// LookupUserSetting returns the definition of the user setting named key
+func LookupUserSetting(key string) (*SettingDefinition, bool) {
+	for i := range UserSettingDefinitions {
+		if UserSettingDefinitions[i].Key == key {
+			return &UserSettingDefinitions[i], true
+		}
+	}
+	return nil, false
+}
+
+// Normalize checks value against the definition and converts it to the
+// setting's type; JSON numbers arrive as float64
+func (d *SettingDefinition) Normalize(value interface{}) (interface{}, error) {
+	switch d.Type {
+	case SettingTypeBool:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+		return nil, ValidationError(d.Key, "must be a boolean")
+	case SettingTypeInt:
+		var n int
+		switch v := value.(type) {
+		case int:
+			n = v
+		case float64:
+			if v != math.Trunc(v) || v < math.MinInt32 || v > math.MaxInt32 {
+				return nil, ValidationError(d.Key, "must be an integer")
+			}
+			n = int(v)
+		default:
+			return nil, ValidationError(d.Key, "must be an integer")
+		}
+		if n < d.Min || n > d.Max {
+			return nil, ValidationError(d.Key, fmt.Sprintf("must be between %d and %d", d.Min, d.Max))
+		}
+		return n, nil
+	case SettingTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, ValidationError(d.Key, "must be a string")
+		}
+		if len(d.Options) == 0 {
+			return s, nil
+		}
+		for _, option := range d.Options {
+			if s == option {
+				return s, nil
+			}
+		}
+		return nil, ValidationError(d.Key, fmt.Sprintf("must be one of: %v", d.Options))
+	default:
+		return nil, fmt.Errorf("setting %s has unsupported type %s", d.Key, d.Type)
+	}
+}
+
+// UserSetting is a setting a user changed from its default. Value holds the
+// setting encoded as JSON.
+type UserSetting struct {
+	ID        uint      `json:"-" gorm:"primaryKey" bson:"-"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_user_settings_user_key" bson:"user_id"`
+	User      *User     `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" bson:"-"`
+	Key       string    `json:"key" gorm:"not null;size:100;uniqueIndex:idx_user_settings_user_key" bson:"key"`
+	Value     string    `json:"value" gorm:"not null;type:text" bson:"value"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime" bson:"updated_at"`
+}
+
+// UserSettings maps setting keys to their values
+type UserSettings map[string]interface{}
+
+// UserSettingRepository defines the interface for user setting data access
+type UserSettingRepository interface {
+	// ListByUser retrieves the settings a user has changed
+	ListByUser(ctx context.Context, userID uint) ([]*UserSetting, error)
+
+	// Upsert creates the settings or replaces their values
+	Upsert(ctx context.Context, settings []*UserSetting) error
+
+	// Delete removes settings of a user by key, restoring their defaults
+	Delete(ctx context.Context, userID uint, keys []string) error
+}
+
+// UserSettingsService defines the interface for user preferences
+type UserSettingsService interface {
+	// GetSettings returns every setting of the user, with defaults for the
+	// settings the user hasn't changed
+	GetSettings(ctx context.Context, userID uint) (UserSettings, error)
+
+	// UpdateSettings validates and stores the given settings and returns all
+	// settings of the user. Null values restore the defaults.
+	UpdateSettings(ctx context.Context, userID uint, values UserSettings) (UserSettings, error)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PermissionStatsRead grants access to the admin dashboard statistics
+const PermissionStatsRead = "stats:read"
+
+// StatsDays is the number of days, today included, of the daily statistics
+const StatsDays = 30
+
+// DailyCount is the number of events on a UTC day
+type DailyCount struct {
+	// Date is the day formatted as 2006-01-02
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// AdminStats are aggregate statistics for the admin dashboard
+type AdminStats struct {
+	TotalUsers int64 `json:"total_users"`
+	// ActiveUsers are the users whose account is active
+	ActiveUsers int64 `json:"active_users"`
+	// SignupsPerDay and LoginsPerDay cover the last StatsDays days, oldest
+	// first, including days without any
+	SignupsPerDay []DailyCount `json:"signups_per_day"`
+	LoginsPerDay  []DailyCount `json:"logins_per_day"`
+	GeneratedAt   time.Time    `json:"generated_at"`
+}
+
+// StatsService defines the interface for the admin dashboard statistics
+type StatsService interface {
+	// Overview computes the statistics of the dashboard
+	Overview(ctx context.Context) (*AdminStats, error)
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// TxManager runs multi-step operations atomically. Repository calls made with
+// the context passed to fn join the transaction; nested calls reuse the outer one.
+type TxManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
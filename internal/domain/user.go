@@ -4,90 +4,209 @@ import (
 	"context"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	// Registers the encrypted GORM serializer
+	_ "github.com/luxixing/fx-gin-scaffold/pkg/fieldcrypt"
 )
 
 // User represents a user in the system
 type User struct {
-	ID        uint      `json:"id" gorm:"primaryKey" bson:"_id,omitempty"`
-	Email     string    `json:"email" gorm:"uniqueIndex:idx_users_email;not null;size:255" bson:"email" validate:"required,email"`
-	Password  string    `json:"-" gorm:"not null;size:255" bson:"password" validate:"required,min=8"`
-	Name      string    `json:"name" gorm:"not null;size:100;index:idx_users_name" bson:"name" validate:"required,min=2"`
-	Role      string    `json:"role" gorm:"default:user;size:50;index:idx_users_role,idx_users_role_active" bson:"role"`
-	Active    bool      `json:"active" gorm:"default:true;index:idx_users_active,idx_users_role_active" bson:"active"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_users_created_at" bson:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime" bson:"updated_at"`
-}
+	ID           uint      `json:"id" gorm:"primaryKey" bson:"_id,omitempty"`
+	Email        string    `json:"email" gorm:"uniqueIndex:idx_users_email;not null;size:255" bson:"email" validate:"required,email"`
+	PendingEmail string    `json:"pending_email,omitempty" gorm:"size:255" bson:"pending_email,omitempty"`
+	Password     string    `json:"-" gorm:"not null;size:255" bson:"password" validate:"required,min=8"`
+	Name         string    `json:"name" gorm:"not null;size:100;index:idx_users_name" bson:"name" validate:"required,min=2"`
+	Role         string    `json:"role" gorm:"default:user;size:50;index:idx_users_role,idx_users_role_active" bson:"role"`
+	Active       bool      `json:"active" gorm:"default:true;index:idx_users_active,idx_users_role_active" bson:"active"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_users_created_at" bson:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime" bson:"updated_at"`
+
+	// DeletionScheduledAt is when an account its owner deleted is purged;
+	// logging in before then restores it
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" gorm:"index:idx_users_deletion_scheduled_at" bson:"deletion_scheduled_at,omitempty"`
 
-// TableName returns the table name for User model
-func (User) TableName() string {
-	return GetTableName("users")
+	// Optional profile fields. Phone and metadata are personal data,
+	// encrypted at rest when ENCRYPTION_KEYS is set.
+	AvatarURL string `json:"avatar_url,omitempty" gorm:"size:500" bson:"avatar_url,omitempty"`
+	Phone     string `json:"phone,omitempty" gorm:"size:255;serializer:encrypted" bson:"phone,omitempty"`
+	Locale    string `json:"locale,omitempty" gorm:"size:35" bson:"locale,omitempty"`
+	Timezone  string `json:"timezone,omitempty" gorm:"size:64" bson:"timezone,omitempty"`
+	// Metadata is free-form data for applications built on the scaffold;
+	// JSONB on PostgreSQL, holding a JSON string once encrypted
+	Metadata map[string]interface{} `json:"metadata,omitempty" gorm:"serializer:encrypted" bson:"metadata,omitempty"`
 }
 
+// MaxUserMetadataSize is the maximum size of a user's metadata encoded as JSON
+const MaxUserMetadataSize = 16 << 10
+
 // UserCreateRequest represents the request for creating a new user
 type UserCreateRequest struct {
-	Email    string `json:"email" validate:"required,email"`
+	Email    string `json:"email" sanitize:"trim,lower" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
-	Name     string `json:"name" validate:"required,min=2"`
+	Name     string `json:"name" sanitize:"trim" validate:"required,min=2"`
 	Role     string `json:"role,omitempty"`
+	// InviteCode redeems an invite, which grants its role; required when
+	// registration is invite-only
+	InviteCode string `json:"invite_code,omitempty" sanitize:"trim"`
 }
 
-// UserUpdateRequest represents the request for updating a user
+// UserUpdateRequest represents the request for updating a user. Omitted
+// fields are left unchanged and empty profile fields are cleared. Metadata
+// keys are merged into the stored metadata; null values remove keys.
 type UserUpdateRequest struct {
-	Name   *string `json:"name,omitempty" validate:"omitempty,min=2"`
-	Role   *string `json:"role,omitempty"`
-	Active *bool   `json:"active,omitempty"`
+	Name      *string                `json:"name,omitempty" sanitize:"trim" validate:"omitempty,min=2"`
+	Role      *string                `json:"role,omitempty"`
+	Active    *bool                  `json:"active,omitempty"`
+	AvatarURL *string                `json:"avatar_url,omitempty" sanitize:"trim" validate:"omitempty,max=500,len=0|http_url"`
+	Phone     *string                `json:"phone,omitempty" validate:"omitempty,len=0|e164"`
+	Locale    *string                `json:"locale,omitempty" validate:"omitempty,max=35,len=0|bcp47_language_tag"`
+	Timezone  *string                `json:"timezone,omitempty" validate:"omitempty,max=64,len=0|timezone"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty" validate:"omitempty,max=50"`
+}
+
+// EmailChangeRequest represents the request for changing the current user's email
+type EmailChangeRequest struct {
+	Email    string `json:"email" sanitize:"trim,lower" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// DeleteAccountRequest represents the request for deleting the current user's account
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// ListOptions selects which users are listed besides the query filters.
+// Inactive users are left out unless IncludeInactive is set, and a Role lists
+// only the users with that role.
+type ListOptions struct {
+	IncludeInactive bool
+	Role            string
+}
+
+// UserListFilter represents the filter and sort parameters for listing users
+type UserListFilter struct {
+	Sort            string     `form:"sort"`
+	Role            string     `form:"role"`
+	Active          *bool      `form:"active"`
+	IncludeInactive bool       `form:"include_inactive"`
+	CreatedAfter    *time.Time `form:"created_after"`
+	CreatedBefore   *time.Time `form:"created_before"`
+}
+
+// userQueryFields lists the user columns that may be filtered or sorted on
+var userQueryFields = []string{"name", "email", "role", "active", "created_at", "updated_at"}
+
+// Query converts the filter into a validated query
+func (f *UserListFilter) Query() (*Query, error) {
+	q := NewQuery(userQueryFields...).SortBy(f.Sort)
+	if f.Active != nil {
+		q.Where("active", OpEq, *f.Active)
+	}
+	if f.CreatedAfter != nil {
+		q.Where("created_at", OpGt, *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		q.Where("created_at", OpLt, *f.CreatedBefore)
+	}
+	return q, q.Err()
+}
+
+// Options converts the filter into list options. Filtering on active status
+// includes inactive users, so that active=false lists them.
+func (f *UserListFilter) Options() ListOptions {
+	return ListOptions{
+		IncludeInactive: f.IncludeInactive || f.Active != nil,
+		Role:            f.Role,
+	}
 }
 
 // UserLoginRequest represents the login request
 type UserLoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
+	Email    string `json:"email" sanitize:"trim,lower" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
 
+// ChangePasswordRequest represents the request for changing the current user's password
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
 // UserResponse represents the user data returned to clients (without sensitive data)
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Role      string    `json:"role"`
-	Active    bool      `json:"active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           uint      `json:"id"`
+	Email        string    `json:"email"`
+	PendingEmail string    `json:"pending_email,omitempty"`
+	Name         string    `json:"name"`
+	Role         string    `json:"role"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	AvatarURL string                 `json:"avatar_url,omitempty"`
+	Phone     string                 `json:"phone,omitempty"`
+	Locale    string                 `json:"locale,omitempty"`
+	Timezone  string                 `json:"timezone,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
 }
 
 // ToResponse converts User to UserResponse
 func (u *User) ToResponse() *UserResponse {
 	return &UserResponse{
-		ID:        u.ID,
-		Email:     u.Email,
-		Name:      u.Name,
-		Role:      u.Role,
-		Active:    u.Active,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:           u.ID,
+		Email:        u.Email,
+		PendingEmail: u.PendingEmail,
+		Name:         u.Name,
+		Role:         u.Role,
+		Active:       u.Active,
+		CreatedAt:    u.CreatedAt,
+		UpdatedAt:    u.UpdatedAt,
+		AvatarURL:    u.AvatarURL,
+		Phone:        u.Phone,
+		Locale:       u.Locale,
+		Timezone:     u.Timezone,
+		Metadata:     u.Metadata,
+
+		DeletionScheduledAt: u.DeletionScheduledAt,
 	}
 }
 
+// Cursor returns the keyset pagination position of the user
+func (u *UserResponse) Cursor() *Cursor {
+	return &Cursor{CreatedAt: u.CreatedAt, ID: u.ID}
+}
+
+// PasswordHasher hashes and verifies user passwords
+type PasswordHasher interface {
+	// Hash returns an encoded hash of the password
+	Hash(password string) (string, error)
+
+	// Verify reports whether the password matches the encoded hash
+	Verify(hash, password string) bool
+
+	// NeedsRehash reports whether the hash uses outdated algorithm or parameters
+	NeedsRehash(hash string) bool
+}
+
 // HashPassword hashes the user's password
-func (u *User) HashPassword() error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+func (u *User) HashPassword(hasher PasswordHasher) error {
+	hashedPassword, err := hasher.Hash(u.Password)
 	if err != nil {
 		return err
 	}
-	u.Password = string(hashedPassword)
+	u.Password = hashedPassword
 	return nil
 }
 
 // CheckPassword compares the provided password with the stored hash
-func (u *User) CheckPassword(password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
-	return err == nil
+func (u *User) CheckPassword(hasher PasswordHasher, password string) bool {
+	return hasher.Verify(u.Password, password)
 }
 
 // IsAdmin returns true if the user has admin role
 func (u *User) IsAdmin() bool {
-	return u.Role == "admin"
+	return u.Role == RoleAdmin
 }
 
 // UserRepository defines the interface for user data access
@@ -107,11 +226,26 @@ type UserRepository interface {
 	// Delete soft deletes a user
 	Delete(ctx context.Context, id uint) error
 	
-	// List retrieves users with pagination
-	List(ctx context.Context, offset, limit int) ([]*User, int64, error)
+	// List retrieves users matching the query and options with pagination; a
+	// nil query applies only the options
+	List(ctx context.Context, query *Query, opts ListOptions, offset, limit int) ([]*User, int64, error)
 	
-	// Search searches users by name or email
+	// Search searches active and inactive users by name or email
 	Search(ctx context.Context, query string, offset, limit int) ([]*User, int64, error)
+	
+	// ListByCursor retrieves users matching the query and options with keyset
+	// pagination, reporting whether more rows follow
+	ListByCursor(ctx context.Context, query *Query, opts ListOptions, page *CursorPage) ([]*User, bool, error)
+	
+	// SearchByCursor searches users by name or email with keyset pagination
+	SearchByCursor(ctx context.Context, query string, page *CursorPage) ([]*User, bool, error)
+	
+	// Count counts users matching the query; a nil query counts all users
+	Count(ctx context.Context, query *Query) (int64, error)
+	
+	// CountCreatedByDay counts the users created on each UTC day since since,
+	// oldest first, omitting days without any
+	CountCreatedByDay(ctx context.Context, since time.Time) ([]DailyCount, error)
 }
 
 // UserService defines the interface for user business logic
@@ -119,8 +253,8 @@ type UserService interface {
 	// Register creates a new user account
 	Register(ctx context.Context, req *UserCreateRequest) (*UserResponse, error)
 	
-	// Login authenticates a user and returns a token
-	Login(ctx context.Context, req *UserLoginRequest) (string, *UserResponse, error)
+	// Login authenticates a user and returns a token pair
+	Login(ctx context.Context, req *UserLoginRequest) (*TokenPair, *UserResponse, error)
 	
 	// GetProfile retrieves the user's profile
 	GetProfile(ctx context.Context, userID uint) (*UserResponse, error)
@@ -128,18 +262,55 @@ type UserService interface {
 	// UpdateProfile updates the user's profile
 	UpdateProfile(ctx context.Context, userID uint, req *UserUpdateRequest) (*UserResponse, error)
 	
-	// GetUser retrieves a user by ID (admin only)
+	// PatchProfile applies a patch to the user's profile
+	PatchProfile(ctx context.Context, userID uint, patch *Patch) (*UserResponse, error)
+	
+	// ChangePassword changes the user's password after verifying the old one
+	ChangePassword(ctx context.Context, userID uint, req *ChangePasswordRequest) error
+	
+	// RequestEmailChange stores a pending email and mails a confirmation link to it
+	RequestEmailChange(ctx context.Context, userID uint, req *EmailChangeRequest) (*UserResponse, error)
+	
+	// ConfirmEmailChange swaps in the pending email identified by a confirmation token
+	ConfirmEmailChange(ctx context.Context, token string) (*UserResponse, error)
+	
+	// DeleteAccount schedules the user's account for deletion after verifying
+	// the password and signs the user out of every session
+	DeleteAccount(ctx context.Context, userID uint, req *DeleteAccountRequest) (*UserResponse, error)
+	
+	// PurgeDeletedAccounts deletes the accounts whose deletion is due,
+	// returning how many were deleted
+	PurgeDeletedAccounts(ctx context.Context) (int64, error)
+	
+	// GetUser retrieves a user by ID
 	GetUser(ctx context.Context, id uint) (*UserResponse, error)
 	
-	// ListUsers retrieves users with pagination (admin only)
-	ListUsers(ctx context.Context, offset, limit int) ([]*UserResponse, int64, error)
+	// ListUsers retrieves users matching the query and options with pagination (admin only)
+	ListUsers(ctx context.Context, query *Query, opts ListOptions, offset, limit int) ([]*UserResponse, int64, error)
 	
 	// SearchUsers searches users (admin only)
 	SearchUsers(ctx context.Context, query string, offset, limit int) ([]*UserResponse, int64, error)
 	
+	// ListUsersByCursor retrieves users matching the query and options with keyset pagination (admin only)
+	ListUsersByCursor(ctx context.Context, query *Query, opts ListOptions, page *CursorPage) ([]*UserResponse, bool, error)
+	
+	// SearchUsersByCursor searches users with keyset pagination (admin only)
+	SearchUsersByCursor(ctx context.Context, query string, page *CursorPage) ([]*UserResponse, bool, error)
+	
 	// UpdateUser updates a user (admin only)
 	UpdateUser(ctx context.Context, id uint, req *UserUpdateRequest) (*UserResponse, error)
 	
+	// PatchUser applies a patch to a user (admin only)
+	PatchUser(ctx context.Context, id uint, patch *Patch) (*UserResponse, error)
+	
 	// DeleteUser deletes a user (admin only)
 	DeleteUser(ctx context.Context, id uint) error
+
+	// CreateUser creates an active user with the requested role, whatever the
+	// registration mode (operators only)
+	CreateUser(ctx context.Context, req *UserCreateRequest) (*UserResponse, error)
+
+	// ResetPassword sets a user's password without the old one and signs out
+	// every session (operators only)
+	ResetPassword(ctx context.Context, id uint, password string) error
 }
\ No newline at end of file
@@ -0,0 +1,223 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PermissionWebhooksManage grants access to webhook registrations and their deliveries
+const PermissionWebhooksManage = "webhooks:manage"
+
+// Webhook event types
+const (
+	WebhookEventAll         = "*"
+	WebhookEventUserCreated = "user.created"
+	WebhookEventUserUpdated = "user.updated"
+	WebhookEventUserDeleted = "user.deleted"
+)
+
+// WebhookEvents lists the event types a webhook may subscribe to
+var WebhookEvents = []string{
+	WebhookEventUserCreated,
+	WebhookEventUserUpdated,
+	WebhookEventUserDeleted,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook errors
+var (
+	ErrWebhookNotFound         = &Error{Code: ErrCodeNotFound, Message: "Webhook not found"}
+	ErrWebhookDeliveryNotFound = &Error{Code: ErrCodeNotFound, Message: "Webhook delivery not found"}
+)
+
+// Webhook is an endpoint that receives domain events over HTTP
+type Webhook struct {
+	ID        uint      `json:"id" gorm:"primaryKey" bson:"id"`
+	URL       string    `json:"url" gorm:"not null;size:2048" bson:"url"`
+	Secret    string    `json:"-" gorm:"not null;size:255" bson:"secret"`
+	Events    []string  `json:"events" gorm:"serializer:json;type:text" bson:"events"`
+	Active    bool      `json:"active" gorm:"not null;default:true" bson:"active"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime" bson:"updated_at"`
+}
+
+// Subscribes reports whether the webhook receives events of the given type
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.Events {
+		if e == event || e == WebhookEventAll {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery records an event sent, or to be sent, to a webhook
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey" bson:"id"`
+	WebhookID      uint       `json:"webhook_id" gorm:"not null;index:idx_webhook_deliveries_webhook_id" bson:"webhook_id"`
+	Webhook        *Webhook   `json:"-" gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE" bson:"-"`
+	Event          string     `json:"event" gorm:"not null;size:50" bson:"event"`
+	Payload        string     `json:"payload" gorm:"type:text" bson:"payload"`
+	Status         string     `json:"status" gorm:"not null;size:20;index:idx_webhook_deliveries_status_next_attempt_at,priority:1" bson:"status"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0" bson:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty" bson:"response_status,omitempty"`
+	Error          string     `json:"error,omitempty" gorm:"type:text" bson:"error,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty" gorm:"index:idx_webhook_deliveries_status_next_attempt_at,priority:2" bson:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty" bson:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime" bson:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime" bson:"updated_at"`
+}
+
+// WebhookPayload is the JSON body posted to webhooks
+type WebhookPayload struct {
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookCreateRequest represents the request for registering a webhook. A
+// secret is generated when none is given.
+type WebhookCreateRequest struct {
+	URL    string   `json:"url" sanitize:"trim" validate:"required,url,max=2048"`
+	Secret string   `json:"secret" validate:"omitempty,min=16,max=255"`
+	Events []string `json:"events" sanitize:"trim" validate:"required,min=1,dive,required"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+// WebhookUpdateRequest represents a partial update of a webhook
+type WebhookUpdateRequest struct {
+	URL    *string  `json:"url,omitempty" sanitize:"trim" validate:"omitempty,url,max=2048"`
+	Secret *string  `json:"secret,omitempty" validate:"omitempty,min=16,max=255"`
+	Events []string `json:"events,omitempty" sanitize:"trim" validate:"omitempty,min=1,dive,required"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+// Normalize implements Normalizer, removing duplicate events
+func (r *WebhookCreateRequest) Normalize() {
+	r.Events = uniqueEvents(r.Events)
+}
+
+// Normalize implements Normalizer, removing duplicate events
+func (r *WebhookUpdateRequest) Normalize() {
+	r.Events = uniqueEvents(r.Events)
+}
+
+// uniqueEvents removes repeated events, keeping the first of each
+func uniqueEvents(events []string) []string {
+	if events == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(events))
+	unique := make([]string, 0, len(events))
+	for _, event := range events {
+		if !seen[event] {
+			seen[event] = true
+			unique = append(unique, event)
+		}
+	}
+	return unique
+}
+
+// WebhookResponse represents the webhook data returned to clients. The
+// secret is only included when the webhook is created.
+type WebhookResponse struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ToResponse converts Webhook to WebhookResponse without its secret
+func (w *Webhook) ToResponse() *WebhookResponse {
+	return &WebhookResponse{
+		ID:        w.ID,
+		URL:       w.URL,
+		Events:    w.Events,
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// WebhookRepository defines the interface for webhook data access
+type WebhookRepository interface {
+	// Create creates a new webhook
+	Create(ctx context.Context, webhook *Webhook) error
+
+	// GetByID retrieves a webhook by ID
+	GetByID(ctx context.Context, id uint) (*Webhook, error)
+
+	// Update updates an existing webhook
+	Update(ctx context.Context, webhook *Webhook) error
+
+	// Delete deletes a webhook
+	Delete(ctx context.Context, id uint) error
+
+	// List retrieves webhooks with pagination, newest first
+	List(ctx context.Context, offset, limit int) ([]*Webhook, int64, error)
+
+	// ListActive retrieves every active webhook
+	ListActive(ctx context.Context) ([]*Webhook, error)
+}
+
+// WebhookDeliveryRepository defines the interface for webhook delivery data access
+type WebhookDeliveryRepository interface {
+	// Create creates a new delivery
+	Create(ctx context.Context, delivery *WebhookDelivery) error
+
+	// GetByID retrieves a delivery by ID
+	GetByID(ctx context.Context, id uint) (*WebhookDelivery, error)
+
+	// Update updates an existing delivery
+	Update(ctx context.Context, delivery *WebhookDelivery) error
+
+	// DeleteByWebhook deletes every delivery of a webhook
+	DeleteByWebhook(ctx context.Context, webhookID uint) error
+
+	// ListByWebhook retrieves the deliveries of a webhook with pagination, newest first
+	ListByWebhook(ctx context.Context, webhookID uint, offset, limit int) ([]*WebhookDelivery, int64, error)
+
+	// ListDue retrieves up to limit pending deliveries whose next attempt is due at now, oldest first
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*WebhookDelivery, error)
+}
+
+// WebhookService defines the interface for webhook registration and event emission
+type WebhookService interface {
+	// CreateWebhook registers a webhook; the response includes its secret
+	CreateWebhook(ctx context.Context, req *WebhookCreateRequest) (*WebhookResponse, error)
+
+	// GetWebhook retrieves a webhook by ID
+	GetWebhook(ctx context.Context, id uint) (*WebhookResponse, error)
+
+	// UpdateWebhook applies a partial update to a webhook
+	UpdateWebhook(ctx context.Context, id uint, req *WebhookUpdateRequest) (*WebhookResponse, error)
+
+	// DeleteWebhook deletes a webhook and its deliveries
+	DeleteWebhook(ctx context.Context, id uint) error
+
+	// ListWebhooks retrieves webhooks with pagination
+	ListWebhooks(ctx context.Context, offset, limit int) ([]*WebhookResponse, int64, error)
+
+	// ListDeliveries retrieves the delivery log of a webhook with pagination
+	ListDeliveries(ctx context.Context, webhookID uint, offset, limit int) ([]*WebhookDelivery, int64, error)
+
+	// RedeliverDelivery queues a delivery to be sent again
+	RedeliverDelivery(ctx context.Context, webhookID, deliveryID uint) (*WebhookDelivery, error)
+
+	// Emit queues an event for every active webhook subscribed to it. Deliveries
+	// join the transaction in ctx, so they are only sent if it commits.
+	Emit(ctx context.Context, event string, data interface{}) error
+
+	// DeliverDue sends the deliveries whose next attempt is due, scheduling
+	// retries with exponential backoff, and returns how many were attempted
+	DeliverDue(ctx context.Context) (int, error)
+}
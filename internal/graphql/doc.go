@@ -0,0 +1,12 @@
+// Package graphql serves the users and auth API over GraphQL, next to the
+// REST routes. Resolvers delegate to the domain services, so business rules,
+// validation and auditing are shared with the REST handlers.
+//
+// The executable schema in generated and model is generated by gqlgen from
+// schema.graphqls; run go generate ./internal/graphql after editing it. The
+// resolvers and server are only compiled with the graphql build tag:
+//
+//	go build -tags graphql ./cmd/server
+package graphql
+
+//go:generate go run github.com/99designs/gqlgen generate
@@ -0,0 +1,20 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+type AuthPayload struct {
+	Tokens *domain.TokenPair    `json:"tokens"`
+	User   *domain.UserResponse `json:"user"`
+}
+
+type Mutation struct {
+}
+
+type UserPage struct {
+	Items []*domain.UserResponse `json:"items"`
+	Total int                    `json:"total"`
+}
@@ -0,0 +1,59 @@
+//go:build graphql
+
+package graphql
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// ResolverParams holds dependencies for Resolver
+type ResolverParams struct {
+	fx.In
+	UserService       domain.UserService
+	AuthService       domain.AuthService
+	PermissionService domain.PermissionService
+	Validator         domain.Validator
+}
+
+// Resolver is the root resolver; it delegates to the domain services
+type Resolver struct {
+	userService       domain.UserService
+	authService       domain.AuthService
+	permissionService domain.PermissionService
+	validator         domain.Validator
+}
+
+// NewResolver creates the root resolver
+func NewResolver(p ResolverParams) *Resolver {
+	return &Resolver{
+		userService:       p.UserService,
+		authService:       p.AuthService,
+		permissionService: p.PermissionService,
+		validator:         p.Validator,
+	}
+}
+
+// currentActor returns the authenticated caller, set on the request context
+// by the JWT middleware
+func currentActor(ctx context.Context) (domain.Actor, error) {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok || actor.UserID == 0 {
+		return domain.Actor{}, domain.ErrUnauthorized
+	}
+	return actor, nil
+}
+
+// requirePermission fails unless the caller's role grants the permission
+func (r *Resolver) requirePermission(ctx context.Context, actor domain.Actor, permission string) error {
+	allowed, err := r.permissionService.HasPermission(ctx, actor.Role, permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domain.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+//go:build graphql
+
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/graphql/generated"
+	"github.com/luxixing/fx-gin-scaffold/internal/graphql/model"
+)
+
+// Register is the resolver for the register field.
+func (r *mutationResolver) Register(ctx context.Context, input domain.UserCreateRequest) (*model.AuthPayload, error) {
+	user, err := r.userService.Register(ctx, &input)
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := r.authService.IssueTokenPair(ctx, &domain.User{
+		ID:    user.ID,
+		Email: user.Email,
+		Role:  user.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.AuthPayload{Tokens: pair, User: user}, nil
+}
+
+// Login is the resolver for the login field.
+func (r *mutationResolver) Login(ctx context.Context, input domain.UserLoginRequest) (*model.AuthPayload, error) {
+	pair, user, err := r.userService.Login(ctx, &input)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AuthPayload{Tokens: pair, User: user}, nil
+}
+
+// RefreshToken is the resolver for the refreshToken field.
+func (r *mutationResolver) RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	return r.authService.RefreshToken(ctx, refreshToken)
+}
+
+// UpdateProfile is the resolver for the updateProfile field.
+func (r *mutationResolver) UpdateProfile(ctx context.Context, input domain.UserUpdateRequest) (*domain.UserResponse, error) {
+	actor, err := currentActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Role and status are not part of the profile
+	input.Role, input.Active = nil, nil
+	return r.userService.UpdateProfile(ctx, actor.UserID, &input)
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*domain.UserResponse, error) {
+	actor, err := currentActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.userService.GetProfile(ctx, actor.UserID)
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id uint) (*domain.UserResponse, error) {
+	actor, err := currentActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID != id {
+		if err := r.requirePermission(ctx, actor, domain.PermissionUsersRead); err != nil {
+			return nil, err
+		}
+	}
+	return r.userService.GetUser(ctx, id)
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, page *int, limit *int, search *string) (*model.UserPage, error) {
+	actor, err := currentActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.requirePermission(ctx, actor, domain.PermissionUsersRead); err != nil {
+		return nil, err
+	}
+
+	pagination := domain.PaginationRequest{Page: 1, Limit: 10}
+	if page != nil {
+		pagination.Page = *page
+	}
+	if limit != nil {
+		pagination.Limit = *limit
+	}
+	if err := r.validator.Validate(&pagination); err != nil {
+		return nil, err
+	}
+
+	var (
+		users []*domain.UserResponse
+		total int64
+	)
+	if search != nil && *search != "" {
+		users, total, err = r.userService.SearchUsers(ctx, *search, pagination.GetOffset(), pagination.Limit)
+	} else {
+		users, total, err = r.userService.ListUsers(ctx, nil, domain.ListOptions{}, pagination.GetOffset(), pagination.Limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &model.UserPage{Items: users, Total: int(total)}, nil
+}
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
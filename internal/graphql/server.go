@@ -0,0 +1,73 @@
+//go:build graphql
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gqlgraphql "github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/graphql/generated"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/zap"
+)
+
+// NewServer creates the GraphQL HTTP handler serving queries over GET and POST
+func NewServer(cfg *config.Config, resolver *Resolver) *handler.Server {
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.FixedComplexityLimit(cfg.GraphQL.ComplexityLimit))
+	if !cfg.IsProduction() {
+		srv.Use(extension.Introspection{})
+	}
+
+	srv.SetErrorPresenter(presentError)
+	srv.SetRecoverFunc(func(ctx context.Context, r interface{}) error {
+		logger.FromContext(ctx).Error("graphql resolver panicked", zap.Any("panic", r))
+		return fmt.Errorf("panic: %v", r)
+	})
+	return srv
+}
+
+// presentError exposes domain errors with their code and HTTP status in the
+// error extensions. Other resolver errors are logged and hidden from clients.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := gqlgraphql.DefaultErrorPresenter(ctx, err)
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		gqlErr.Message = domainErr.Message
+		gqlErr.Extensions = map[string]interface{}{
+			"code":   domainErr.Code,
+			"status": domain.HTTPStatusFromError(domainErr),
+		}
+		if len(domainErr.Fields) > 0 {
+			gqlErr.Extensions["fields"] = domainErr.Fields
+		}
+		return gqlErr
+	}
+
+	// Parsing and validation errors describe the query and are safe to show
+	if _, ok := err.(*gqlerror.Error); ok {
+		return gqlErr
+	}
+
+	logger.FromContext(ctx).Error("graphql resolver failed",
+		zap.String("path", gqlErr.Path.String()),
+		zap.Error(err),
+	)
+	gqlErr.Message = domain.ErrInternalServer.Message
+	gqlErr.Extensions = map[string]interface{}{
+		"code":   domain.ErrInternalServer.Code,
+		"status": domain.HTTPStatusFromError(domain.ErrInternalServer),
+	}
+	return gqlErr
+}
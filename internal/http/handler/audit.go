@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// AuditHandlerParams holds dependencies for AuditHandler
+type AuditHandlerParams struct {
+	fx.In
+	AuditService domain.AuditService
+}
+
+// AuditHandler handles audit log requests
+type AuditHandler struct {
+	auditService domain.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(p AuditHandlerParams) *AuditHandler {
+	return &AuditHandler{
+		auditService: p.AuditService,
+	}
+}
+
+// ListAuditLogs handles listing audit log entries
+// @Summary List audit logs
+// @Description Get a paginated list of audit log entries, newest first
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param actor_id query int false "Filter by acting user ID"
+// @Param action query string false "Filter by action, e.g. user.update"
+// @Success 200 {object} domain.Response{data=[]domain.AuditLog,meta=domain.Meta}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /audit-logs [get]
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	var pagination domain.PaginationRequest
+	if !bindQuery(c, &pagination, "Invalid pagination parameters") {
+		return
+	}
+
+	var filter domain.AuditLogFilter
+	if !bindQuery(c, &filter, "Invalid filter parameters") {
+		return
+	}
+
+	entries, total, err := h.auditService.List(c.Request.Context(), filter, pagination.GetOffset(), pagination.Limit)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	meta := pagination.GetMeta(total)
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta(entries, meta))
+}
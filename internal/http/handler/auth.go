@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -12,42 +15,44 @@ import (
 // AuthHandlerParams holds dependencies for AuthHandler
 type AuthHandlerParams struct {
 	fx.In
-	UserService domain.UserService
-	AuthService domain.AuthService
+	UserService       domain.UserService
+	AuthService       domain.AuthService
+	DataExportService domain.DataExportService
 }
 
 // AuthHandler handles authentication related requests
 type AuthHandler struct {
-	userService domain.UserService
-	authService domain.AuthService
+	userService       domain.UserService
+	authService       domain.AuthService
+	dataExportService domain.DataExportService
 }
 
 // NewAuthHandler creates a new auth handler
 func NewAuthHandler(p AuthHandlerParams) *AuthHandler {
 	return &AuthHandler{
-		userService: p.UserService,
-		authService: p.AuthService,
+		userService:       p.UserService,
+		authService:       p.AuthService,
+		dataExportService: p.DataExportService,
 	}
 }
 
 // Register handles user registration
 // @Summary Register a new user
-// @Description Create a new user account
+// @Description Create a new user account. An invite code grants the role of its invite and is required when registration is invite-only. Fails with REGISTRATION_CLOSED when registration is closed.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body domain.UserCreateRequest true "User registration data"
 // @Success 201 {object} domain.Response{data=domain.AuthResponse}
 // @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
 // @Failure 409 {object} domain.Response{error=domain.Error}
 // @Failure 500 {object} domain.Response{error=domain.Error}
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req domain.UserCreateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid request body", err.Error()),
-		))
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -61,8 +66,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Generate token for the new user
-	token, err := h.authService.GenerateToken(&domain.User{
+	// Issue tokens for the new user
+	pair, err := h.authService.IssueTokenPair(c.Request.Context(), &domain.User{
 		ID:    user.ID,
 		Email: user.Email,
 		Role:  user.Role,
@@ -72,12 +77,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	response := &domain.AuthResponse{
-		Token: token,
-		User:  user,
-	}
-
-	c.JSON(http.StatusCreated, domain.NewSuccessResponse(response))
+	c.JSON(http.StatusCreated, domain.NewSuccessResponse(domain.NewAuthResponse(pair, user)))
 }
 
 // Login handles user authentication
@@ -90,18 +90,17 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Success 200 {object} domain.Response{data=domain.AuthResponse}
 // @Failure 400 {object} domain.Response{error=domain.Error}
 // @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 429 {object} domain.Response{error=domain.Error}
 // @Failure 500 {object} domain.Response{error=domain.Error}
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req domain.UserLoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid request body", err.Error()),
-		))
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	token, user, err := h.userService.Login(c.Request.Context(), &req)
+	ctx := domain.WithActor(c.Request.Context(), domain.Actor{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()})
+	pair, user, err := h.userService.Login(ctx, &req)
 	if err != nil {
 		if domainErr, ok := err.(*domain.Error); ok {
 			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
@@ -111,33 +110,29 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response := &domain.AuthResponse{
-		Token: token,
-		User:  user,
-	}
-
-	c.JSON(http.StatusOK, domain.NewSuccessResponse(response))
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(domain.NewAuthResponse(pair, user)))
 }
 
 // RefreshToken handles token refresh
 // @Summary Refresh JWT token
-// @Description Refresh an existing JWT token
+// @Description Exchange a refresh token for a new token pair; the presented refresh token is rotated
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Security BearerAuth
-// @Success 200 {object} domain.Response{data=map[string]string}
+// @Param request body domain.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} domain.Response{data=domain.TokenPair}
+// @Failure 400 {object} domain.Response{error=domain.Error}
 // @Failure 401 {object} domain.Response{error=domain.Error}
 // @Failure 500 {object} domain.Response{error=domain.Error}
 // @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	token := middleware.ExtractToken(c)
-	if token == "" {
-		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+	var req domain.RefreshTokenRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	newToken, err := h.authService.RefreshToken(c.Request.Context(), token)
+	ctx := domain.WithActor(c.Request.Context(), domain.Actor{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()})
+	pair, err := h.authService.RefreshToken(ctx, req.RefreshToken)
 	if err != nil {
 		if domainErr, ok := err.(*domain.Error); ok {
 			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
@@ -147,11 +142,52 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	response := map[string]string{
-		"token": newToken,
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(pair))
+}
+
+// Logout handles user logout
+// @Summary Logout
+// @Description Revoke the current access token and, if given, the refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.LogoutRequest false "Refresh token to revoke"
+// @Success 204 "Logged out successfully"
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req domain.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			newBindingError(c, "Invalid request body", &req, err),
+		))
+		return
+	}
+
+	if err := h.authService.RevokeAccessToken(c.Request.Context(), middleware.ExtractToken(c)); err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	if req.RefreshToken != "" {
+		if err := h.authService.RevokeRefreshToken(c.Request.Context(), req.RefreshToken); err != nil {
+			if domainErr, ok := err.(*domain.Error); ok {
+				c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+			} else {
+				c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+			}
+			return
+		}
 	}
 
-	c.JSON(http.StatusOK, domain.NewSuccessResponse(response))
+	c.Status(http.StatusNoContent)
 }
 
 // GetProfile handles getting current user profile
@@ -160,7 +196,9 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 // @Tags auth
 // @Produce json
 // @Security BearerAuth
+// @Param fields query string false "Comma separated fields to include" example(id,email,name)
 // @Success 200 {object} domain.Response{data=domain.UserResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
 // @Failure 401 {object} domain.Response{error=domain.Error}
 // @Failure 500 {object} domain.Response{error=domain.Error}
 // @Router /auth/profile [get]
@@ -171,6 +209,11 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	fields, ok := bindFields(c, domain.UserResponse{})
+	if !ok {
+		return
+	}
+
 	user, err := h.userService.GetProfile(c.Request.Context(), userID)
 	if err != nil {
 		if domainErr, ok := err.(*domain.Error); ok {
@@ -181,6 +224,116 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(fields.Project(user)))
+}
+
+// ChangePassword handles changing the current user's password
+// @Summary Change password
+// @Description Change the password of the currently authenticated user. All refresh tokens are revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.ChangePasswordRequest true "Old and new password"
+// @Success 204 "Password changed successfully"
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /auth/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	var req domain.ChangePasswordRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.userService.ChangePassword(c.Request.Context(), userID, &req); err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RequestEmailChange handles requesting a change of the current user's email
+// @Summary Request email change
+// @Description Store a pending email for the authenticated user and send a confirmation link to it. The current email stays active until the link is opened.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.EmailChangeRequest true "New email and current password"
+// @Success 202 {object} domain.Response{data=domain.UserResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 409 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /auth/email [put]
+func (h *AuthHandler) RequestEmailChange(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	var req domain.EmailChangeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.userService.RequestEmailChange(c.Request.Context(), userID, &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, domain.NewSuccessResponse(user))
+}
+
+// ConfirmEmailChange handles confirming a pending email change
+// @Summary Confirm email change
+// @Description Replace the user's email with the pending one using the token from the confirmation email
+// @Tags auth
+// @Produce json
+// @Param token query string true "Confirmation token"
+// @Success 200 {object} domain.Response{data=domain.UserResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 409 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /auth/email/confirm [get]
+func (h *AuthHandler) ConfirmEmailChange(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.ValidationError("token", "is required"),
+		))
+		return
+	}
+
+	user, err := h.userService.ConfirmEmailChange(c.Request.Context(), token)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
 	c.JSON(http.StatusOK, domain.NewSuccessResponse(user))
 }
 
@@ -205,10 +358,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	var req domain.UserUpdateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid request body", err.Error()),
-		))
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -223,4 +373,204 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, domain.NewSuccessResponse(user))
-}
\ No newline at end of file
+}
+
+// PatchProfile handles partially updating current user profile
+// @Summary Patch current user profile
+// @Description Apply a JSON Merge Patch (application/merge-patch+json) or JSON Patch (application/json-patch+json) to the name, avatar_url, phone, locale, timezone and metadata of the currently authenticated user
+// @Tags auth
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param request body object true "Merge patch or list of patch operations"
+// @Success 200 {object} domain.Response{data=domain.UserResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 415 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /auth/profile [patch]
+func (h *AuthHandler) PatchProfile(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	patch, ok := bindPatch(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.PatchProfile(c.Request.Context(), userID, patch)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(user))
+}
+
+// ListSessions handles listing the current user's sessions
+// @Summary List sessions
+// @Description List the devices signed in to the current user's account
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.Response{data=[]domain.Session}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	sessionID, _ := middleware.GetSessionID(c)
+	sessions, err := h.authService.ListSessions(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(sessions))
+}
+
+// RevokeSession handles signing out one of the current user's sessions
+// @Summary Revoke session
+// @Description Sign out a device. Its refresh tokens stop working immediately, as do its access tokens.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 204 "Session revoked successfully"
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), userID, c.Param("id")); err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteAccount handles deleting the current user's account
+// @Summary Delete account
+// @Description Schedule the authenticated user's account for deletion after confirming the password. Every session is signed out, and the account and its data are purged once ACCOUNT_DELETION_GRACE_PERIOD has passed; logging in before then restores it.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.DeleteAccountRequest true "Current password"
+// @Success 202 {object} domain.Response{data=domain.UserResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /auth/profile [delete]
+func (h *AuthHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	var req domain.DeleteAccountRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.userService.DeleteAccount(c.Request.Context(), userID, &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	// Refresh tokens are revoked; the access token of this request goes too
+	if err := h.authService.RevokeAccessToken(c.Request.Context(), middleware.ExtractToken(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, domain.NewSuccessResponse(user))
+}
+
+// ExportData handles downloading the current user's personal data
+// @Summary Export personal data
+// @Description Download the profile, settings, sessions and audit log entries of the authenticated user as one JSON document, or as a ZIP archive with a JSON file per section
+// @Tags auth
+// @Produce json,application/zip
+// @Security BearerAuth
+// @Param format query string false "Export format" Enums(json, zip) default(json)
+// @Success 200 {object} domain.DataExport
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /auth/profile/export [get]
+func (h *AuthHandler) ExportData(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	format := c.DefaultQuery("format", domain.DataExportFormatJSON)
+	if format != domain.DataExportFormatJSON && format != domain.DataExportFormatZIP {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.ValidationError("format", "must be json or zip"),
+		))
+		return
+	}
+
+	export, err := h.dataExportService.Export(c.Request.Context(), userID)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	filename := "data-export-" + export.ExportedAt.Format("20060102T150405Z") + "." + format
+	if format == domain.DataExportFormatJSON {
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Header("Cache-Control", "no-store")
+		c.IndentedJSON(http.StatusOK, export)
+		return
+	}
+
+	var archive bytes.Buffer
+	if err := export.WriteZIP(&archive); err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive.Bytes())
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/validation"
+	"github.com/luxixing/fx-gin-scaffold/pkg/fieldset"
+	"github.com/luxixing/fx-gin-scaffold/pkg/jsonpatch"
+)
+
+// acceptPatch lists the patch formats PATCH endpoints accept, for the
+// Accept-Patch header (RFC 5789)
+var acceptPatch = strings.Join([]string{jsonpatch.MergePatchMediaType, jsonpatch.JSONPatchMediaType}, ", ")
+
+// newBindingError converts a request binding error into a validation error.
+// Invalid fields are reported by name with messages in the language the
+// client accepts; malformed input the validator doesn't recognize is
+// reported with the given message.
+func newBindingError(c *gin.Context, message string, obj any, err error) *domain.Error {
+	if v, ok := binding.Validator.(*validation.Validator); ok {
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		lang := validation.Language(c.GetHeader("Accept-Language"))
+		if bindErr := v.BindingError(err, obj, c.Request.URL.Query(), lang); bindErr != nil {
+			return bindErr
+		}
+	}
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return domain.NewErrorWithDetails(domain.ErrCodeValidation, message, err.Error())
+}
+
+// bindJSON binds the JSON request body to obj. It responds with a
+// validation error and returns false when the body is malformed or invalid.
+func bindJSON(c *gin.Context, obj any) bool {
+	return bindWith(c, obj, binding.JSON, "Invalid request body")
+}
+
+// bindQuery binds the query parameters to obj. It responds with a
+// validation error carrying message and returns false when they are
+// invalid.
+func bindQuery(c *gin.Context, obj any, message string) bool {
+	return bindWith(c, obj, binding.Query, message)
+}
+
+// bindWith binds the request to obj with b, responding to failures
+func bindWith(c *gin.Context, obj any, b binding.Binding, message string) bool {
+	if err := c.ShouldBindWith(obj, b); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			newBindingError(c, message, obj, err),
+		))
+		return false
+	}
+	return true
+}
+
+// bindPatch reads a PATCH request body, whose Content-Type selects the patch
+// format. It responds with an error and returns false when the format is
+// not supported or the body cannot be read.
+func bindPatch(c *gin.Context) (*domain.Patch, bool) {
+	c.Header("Accept-Patch", acceptPatch)
+
+	var patchType domain.PatchType
+	switch c.ContentType() {
+	case jsonpatch.MergePatchMediaType:
+		patchType = domain.PatchTypeMerge
+	case jsonpatch.JSONPatchMediaType:
+		patchType = domain.PatchTypeJSON
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, domain.NewErrorResponse(
+			domain.NewErrorWithDetails(domain.ErrCodeInvalid, "Unsupported patch format", "supported formats: "+acceptPatch),
+		))
+		return nil, false
+	}
+
+	document, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			newBindingError(c, "Invalid request body", nil, err),
+		))
+		return nil, false
+	}
+	return &domain.Patch{Type: patchType, Document: document}, true
+}
+
+// bindFields parses the fields query parameter, a sparse fieldset of the
+// JSON fields of model such as ?fields=id,email,name. It responds with a
+// validation error listing the allowed fields and returns false for
+// unknown fields.
+func bindFields(c *gin.Context, model any) (*fieldset.Set, bool) {
+	fields, err := fieldset.Parse(c.Query("fields"), model)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.ValidationError("fields", fmt.Sprintf("%v, allowed fields are %s", err, strings.Join(fieldset.Fields(model), ", "))),
+		))
+		return nil, false
+	}
+	return fields, true
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/http/middleware"
+	"github.com/luxixing/fx-gin-scaffold/internal/realtime"
+	"go.uber.org/fx"
+)
+
+// EventsHandlerParams holds dependencies for EventsHandler
+type EventsHandlerParams struct {
+	fx.In
+	Config *config.Config
+	Broker *realtime.EventBroker
+}
+
+// EventsHandler streams realtime events over Server-Sent Events
+type EventsHandler struct {
+	broker    *realtime.EventBroker
+	keepAlive time.Duration
+}
+
+// NewEventsHandler creates a new SSE handler
+func NewEventsHandler(p EventsHandlerParams) *EventsHandler {
+	return &EventsHandler{
+		broker:    p.Broker,
+		keepAlive: p.Config.Server.SSEKeepAlive,
+	}
+}
+
+// Stream handles Server-Sent Events subscriptions
+// @Summary Stream realtime events
+// @Description Open a Server-Sent Events stream of realtime events for the authenticated user. EventSource clients may pass the token as the access_token query parameter.
+// @Tags realtime
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param access_token query string false "JWT access token when the Authorization header cannot be set"
+// @Success 200 {object} domain.Event
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Router /events [get]
+func (h *EventsHandler) Stream(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	// Streams outlive the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	events, unsubscribe := h.broker.Subscribe(userID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(h.keepAlive)
+	defer ticker.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-ticker.C:
+			// SSE comment lines keep proxies from closing idle streams
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		}
+	})
+}
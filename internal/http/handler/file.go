@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/http/middleware"
+	"go.uber.org/fx"
+)
+
+// FileHandlerParams holds dependencies for FileHandler
+type FileHandlerParams struct {
+	fx.In
+	Config            *config.Config
+	PermissionService domain.PermissionService
+}
+
+// FileHandler serves stored files from the local file storage directory
+type FileHandler struct {
+	root              http.FileSystem
+	maxAge            time.Duration
+	permissionService domain.PermissionService
+}
+
+// NewFileHandler creates a new file handler
+func NewFileHandler(p FileHandlerParams) *FileHandler {
+	return &FileHandler{
+		root:              http.Dir(p.Config.Files.Dir),
+		maxAge:            p.Config.Files.CacheMaxAge,
+		permissionService: p.PermissionService,
+	}
+}
+
+// Serve handles downloading a stored file
+// @Summary Download a stored file
+// @Description Serve a file from storage. Files under public/ are readable by anyone; files under users/{id}/ only by their owner or roles with files:read.
+// @Tags files
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param filepath path string true "File path, e.g. public/logo.png"
+// @Success 200 {file} file
+// @Success 304 "Not modified"
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Router /files/{filepath} [get]
+func (h *FileHandler) Serve(c *gin.Context) {
+	name := path.Clean("/" + c.Param("filepath"))
+	segments := strings.Split(strings.TrimPrefix(name, "/"), "/")
+	for _, segment := range segments {
+		// Never expose dotfiles such as .htaccess or .git
+		if strings.HasPrefix(segment, ".") {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(domain.ErrFileNotFound))
+			return
+		}
+	}
+
+	var cacheControl string
+	switch {
+	case len(segments) >= 2 && segments[0] == domain.FilesPublicDir:
+		cacheControl = fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds()))
+	case len(segments) >= 3 && segments[0] == domain.FilesUserDir:
+		if !h.canReadUserFiles(c, segments[1]) {
+			return
+		}
+		cacheControl = fmt.Sprintf("private, max-age=%d", int(h.maxAge.Seconds()))
+		c.Header("Vary", "Authorization")
+	default:
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(domain.ErrFileNotFound))
+		return
+	}
+
+	file, err := h.root.Open(name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(domain.ErrFileNotFound))
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(domain.ErrFileNotFound))
+		return
+	}
+
+	c.Header("Cache-Control", cacheControl)
+	c.Header("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	// ServeContent handles conditional and range requests
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), file)
+}
+
+// canReadUserFiles checks access to another user's private files, writing
+// the error response when access is denied
+func (h *FileHandler) canReadUserFiles(c *gin.Context, owner string) bool {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return false
+	}
+
+	if owner == strconv.FormatUint(uint64(userID), 10) {
+		return true
+	}
+
+	role, _ := middleware.GetUserRole(c)
+	allowed, err := h.permissionService.HasPermission(c.Request.Context(), role, domain.PermissionFilesRead)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		return false
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, domain.NewErrorResponse(domain.ErrForbidden))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// HealthHandlerParams holds dependencies for HealthHandler
+type HealthHandlerParams struct {
+	fx.In
+	HealthService domain.HealthService
+}
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	healthService domain.HealthService
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(p HealthHandlerParams) *HealthHandler {
+	return &HealthHandler{
+		healthService: p.HealthService,
+	}
+}
+
+// Live handles liveness probes
+// @Summary Liveness probe
+// @Description Reports that the process is running; does not check dependencies
+// @Tags health
+// @Produce json
+// @Success 200 {object} domain.HealthReport
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, &domain.HealthReport{
+		Status: domain.HealthStatusOK,
+		Time:   time.Now().UTC(),
+	})
+}
+
+// Ready handles readiness probes
+// @Summary Readiness probe
+// @Description Checks the database, Redis (when configured) and migration status; reports draining while the server shuts down
+// @Tags health
+// @Produce json
+// @Success 200 {object} domain.HealthReport
+// @Failure 503 {object} domain.HealthReport
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.healthService.Readiness(c.Request.Context())
+
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// InviteHandlerParams holds dependencies for InviteHandler
+type InviteHandlerParams struct {
+	fx.In
+	InviteService domain.InviteService
+}
+
+// InviteHandler handles registration invite requests
+type InviteHandler struct {
+	inviteService domain.InviteService
+}
+
+// NewInviteHandler creates a new invite handler
+func NewInviteHandler(p InviteHandlerParams) *InviteHandler {
+	return &InviteHandler{
+		inviteService: p.InviteService,
+	}
+}
+
+// ListInvites handles listing registration invites
+// @Summary List invites
+// @Description Get a paginated list of registration invites with their status, newest first
+// @Tags invites
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status" Enums(pending, accepted, revoked, expired)
+// @Param email query string false "Filter by invited email address"
+// @Success 200 {object} domain.Response{data=[]domain.InviteResponse,meta=domain.Meta}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /invites [get]
+func (h *InviteHandler) ListInvites(c *gin.Context) {
+	var pagination domain.PaginationRequest
+	if !bindQuery(c, &pagination, "Invalid pagination parameters") {
+		return
+	}
+
+	var filter domain.InviteFilter
+	if !bindQuery(c, &filter, "Invalid filter parameters") {
+		return
+	}
+
+	invites, total, err := h.inviteService.ListInvites(c.Request.Context(), filter, pagination.GetOffset(), pagination.Limit)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta(invites, pagination.GetMeta(total)))
+}
+
+// CreateInvite handles inviting someone to register
+// @Summary Create invite
+// @Description Email an invite code allowing the address to register with the given role
+// @Tags invites
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.InviteCreateRequest true "Invite data"
+// @Success 201 {object} domain.Response{data=domain.InviteResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 409 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /invites [post]
+func (h *InviteHandler) CreateInvite(c *gin.Context) {
+	var req domain.InviteCreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	invite, err := h.inviteService.CreateInvite(c.Request.Context(), &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewSuccessResponse(invite))
+}
+
+// RevokeInvite handles revoking a pending invite
+// @Summary Revoke invite
+// @Description Revoke a pending invite so its code can no longer be used; it stays listed as revoked
+// @Tags invites
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invite ID"
+// @Success 200 {object} domain.Response{data=domain.InviteResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /invites/{id}/revoke [post]
+func (h *InviteHandler) RevokeInvite(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	invite, err := h.inviteService.RevokeInvite(c.Request.Context(), id)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(invite))
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/pkg/jwtkeys"
+	"go.uber.org/fx"
+)
+
+// jwksCacheControl lets verifiers cache the key set briefly; rotated keys
+// should be published at least this long before they start signing
+const jwksCacheControl = "public, max-age=300"
+
+// JWKSHandlerParams holds dependencies for JWKSHandler
+type JWKSHandlerParams struct {
+	fx.In
+	Keys *jwtkeys.KeySet
+}
+
+// JWKSHandler publishes the access token verification keys
+type JWKSHandler struct {
+	keys *jwtkeys.KeySet
+}
+
+// NewJWKSHandler creates a new JWKS handler
+func NewJWKSHandler(p JWKSHandlerParams) *JWKSHandler {
+	return &JWKSHandler{
+		keys: p.Keys,
+	}
+}
+
+// Get handles serving the JSON Web Key Set
+// @Summary JSON Web Key Set
+// @Description Public keys for verifying access tokens, selected by the token's kid header. Empty when tokens are signed with HS256.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} jwtkeys.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) Get(c *gin.Context) {
+	c.Header("Cache-Control", jwksCacheControl)
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
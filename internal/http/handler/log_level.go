@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// LogLevelHandlerParams holds dependencies for LogLevelHandler
+type LogLevelHandlerParams struct {
+	fx.In
+	LogLevelService domain.LogLevelService
+}
+
+// LogLevelHandler handles runtime log level changes
+type LogLevelHandler struct {
+	logLevelService domain.LogLevelService
+}
+
+// NewLogLevelHandler creates a new log level handler
+func NewLogLevelHandler(p LogLevelHandlerParams) *LogLevelHandler {
+	return &LogLevelHandler{
+		logLevelService: p.LogLevelService,
+	}
+}
+
+// GetLogLevels handles getting the log levels
+// @Summary Get log levels
+// @Description Get the global log level and the effective level of each module (http, db, jobs)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.Response{data=domain.LogLevels}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Router /admin/log-level [get]
+func (h *LogLevelHandler) GetLogLevels(c *gin.Context) {
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(h.logLevelService.GetLogLevels(c.Request.Context())))
+}
+
+// SetLogLevel handles changing a log level
+// @Summary Change a log level
+// @Description Change the global log level, or the level of a module; an empty level makes the module follow the global level again. Changes last until the next restart or configuration reload.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.LogLevelUpdateRequest true "Level to change"
+// @Success 200 {object} domain.Response{data=domain.LogLevels}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /admin/log-level [put]
+func (h *LogLevelHandler) SetLogLevel(c *gin.Context) {
+	var req domain.LogLevelUpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	levels, err := h.logLevelService.SetLogLevel(c.Request.Context(), req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(levels))
+}
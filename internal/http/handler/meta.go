@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/buildinfo"
+	"go.uber.org/fx"
+)
+
+// MetaHandlerParams holds dependencies for MetaHandler
+type MetaHandlerParams struct {
+	fx.In
+	ConfigWatcher *config.Watcher
+}
+
+// MetaHandler serves information about the server to its clients
+type MetaHandler struct {
+	watcher *config.Watcher
+}
+
+// NewMetaHandler creates a new meta handler
+func NewMetaHandler(p MetaHandlerParams) *MetaHandler {
+	return &MetaHandler{
+		watcher: p.ConfigWatcher,
+	}
+}
+
+// GetMeta handles getting the server metadata
+// @Summary Get server metadata
+// @Description Get the version and build of the server and its enabled features. Feature flags reflect the latest configuration reload.
+// @Tags meta
+// @Produce json
+// @Success 200 {object} domain.Response{data=domain.ServerMeta}
+// @Router /meta [get]
+func (h *MetaHandler) GetMeta(c *gin.Context) {
+	cfg := h.watcher.Current()
+	build := buildinfo.Get()
+
+	flags := make([]string, 0, len(cfg.Features.Enabled))
+	for _, flag := range cfg.Features.Enabled {
+		if flag = strings.TrimSpace(flag); flag != "" {
+			flags = append(flags, flag)
+		}
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(&domain.ServerMeta{
+		Version:   build.Version,
+		Commit:    build.Commit,
+		BuildTime: build.BuildTime,
+		Features: domain.MetaFeatures{
+			Swagger:          cfg.SwaggerEnabled(),
+			RegistrationMode: cfg.Registration.Mode,
+			FeatureFlags:     flags,
+		},
+	}))
+}
+
+// GetConfig handles getting the public configuration
+// @Summary Get public configuration
+// @Description Get the server configuration frontends adapt to, such as whether registration is open, invite-only or closed
+// @Tags meta
+// @Produce json
+// @Success 200 {object} domain.Response{data=domain.PublicConfig}
+// @Router /meta/config [get]
+func (h *MetaHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(&domain.PublicConfig{
+		RegistrationMode: h.watcher.Current().Registration.Mode,
+	}))
+}
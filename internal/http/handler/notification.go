@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/http/middleware"
+	"go.uber.org/fx"
+)
+
+// NotificationHandlerParams holds dependencies for NotificationHandler
+type NotificationHandlerParams struct {
+	fx.In
+	NotificationService domain.NotificationService
+}
+
+// NotificationHandler handles the current user's in-app notifications
+type NotificationHandler struct {
+	notificationService domain.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(p NotificationHandlerParams) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: p.NotificationService,
+	}
+}
+
+// ListNotifications handles listing the current user's notifications
+// @Summary List notifications
+// @Description Get a paginated list of the current user's notifications, newest first
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param unread query bool false "Only list unread notifications"
+// @Success 200 {object} domain.Response{data=[]domain.Notification,meta=domain.Meta}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	var pagination domain.PaginationRequest
+	if !bindQuery(c, &pagination, "Invalid pagination parameters") {
+		return
+	}
+
+	var filter domain.NotificationFilter
+	if !bindQuery(c, &filter, "Invalid filter parameters") {
+		return
+	}
+
+	notifications, total, err := h.notificationService.ListNotifications(c.Request.Context(), userID, filter, pagination.GetOffset(), pagination.Limit)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta(notifications, pagination.GetMeta(total)))
+}
+
+// UnreadCount handles counting the current user's unread notifications
+// @Summary Count unread notifications
+// @Description Get the number of unread notifications of the current user
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.Response{data=domain.NotificationCountResponse}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	unread, err := h.notificationService.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(domain.NotificationCountResponse{Count: unread}))
+}
+
+// MarkRead handles marking a notification as read
+// @Summary Mark notification as read
+// @Description Mark one of the current user's notifications as read
+// @Tags notifications
+// @Security BearerAuth
+// @Param id path int true "Notification ID"
+// @Success 204
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkRead(c.Request.Context(), userID, id); err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MarkAllRead handles marking every notification as read
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the current user as read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.Response{data=domain.NotificationCountResponse}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	updated, err := h.notificationService.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(domain.NotificationCountResponse{Count: updated}))
+}
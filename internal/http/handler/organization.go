@@ -0,0 +1,480 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// OrganizationHandlerParams holds dependencies for OrganizationHandler
+type OrganizationHandlerParams struct {
+	fx.In
+	OrganizationService domain.OrganizationService
+}
+
+// OrganizationHandler handles organization, membership and invitation requests
+type OrganizationHandler struct {
+	organizationService domain.OrganizationService
+}
+
+// NewOrganizationHandler creates a new organization handler
+func NewOrganizationHandler(p OrganizationHandlerParams) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: p.OrganizationService,
+	}
+}
+
+// ListOrganizations handles listing the current user's organizations
+// @Summary List organizations
+// @Description Get the organizations the current user belongs to, with their role in each
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} domain.Response{data=[]domain.OrganizationResponse,meta=domain.Meta}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /organizations [get]
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	var pagination domain.PaginationRequest
+	if !bindQuery(c, &pagination, "Invalid pagination parameters") {
+		return
+	}
+
+	orgs, total, err := h.organizationService.ListOrganizations(c.Request.Context(), pagination.GetOffset(), pagination.Limit)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta(orgs, pagination.GetMeta(total)))
+}
+
+// CreateOrganization handles creating an organization
+// @Summary Create organization
+// @Description Create an organization owned by the current user
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.OrganizationCreateRequest true "Organization data"
+// @Success 201 {object} domain.Response{data=domain.OrganizationResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 409 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /organizations [post]
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req domain.OrganizationCreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	org, err := h.organizationService.CreateOrganization(c.Request.Context(), &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewSuccessResponse(org))
+}
+
+// GetOrganization handles getting an organization by ID
+// @Summary Get organization
+// @Description Get an organization the current user belongs to
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Success 200 {object} domain.Response{data=domain.OrganizationResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /organizations/{id} [get]
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	org, err := h.organizationService.GetOrganization(c.Request.Context(), id)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(org))
+}
+
+// UpdateOrganization handles updating an organization
+// @Summary Update organization
+// @Description Update the given fields of an organization; requires the admin or owner role
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param request body domain.OrganizationUpdateRequest true "Organization update data"
+// @Success 200 {object} domain.Response{data=domain.OrganizationResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 409 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /organizations/{id} [put]
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.OrganizationUpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	org, err := h.organizationService.UpdateOrganization(c.Request.Context(), id, &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(org))
+}
+
+// DeleteOrganization handles deleting an organization
+// @Summary Delete organization
+// @Description Delete an organization with its members and invitations; requires the owner role
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Success 204 "Organization deleted successfully"
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /organizations/{id} [delete]
+func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	err := h.organizationService.DeleteOrganization(c.Request.Context(), id)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListMembers handles listing the members of an organization
+// @Summary List members
+// @Description Get the members of an organization the current user belongs to
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} domain.Response{data=[]domain.MemberResponse,meta=domain.Meta}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /organizations/{id}/members [get]
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var pagination domain.PaginationRequest
+	if !bindQuery(c, &pagination, "Invalid pagination parameters") {
+		return
+	}
+
+	members, total, err := h.organizationService.ListMembers(c.Request.Context(), id, pagination.GetOffset(), pagination.Limit)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta(members, pagination.GetMeta(total)))
+}
+
+// UpdateMemberRole handles changing a member's role
+// @Summary Update member role
+// @Description Change a member's role; requires the admin role, and the owner role to grant or revoke ownership
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param userId path int true "User ID"
+// @Param request body domain.MembershipUpdateRequest true "New role"
+// @Success 200 {object} domain.Response{data=domain.MemberResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /organizations/{id}/members/{userId} [put]
+func (h *OrganizationHandler) UpdateMemberRole(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := uintParam(c, "userId")
+	if !ok {
+		return
+	}
+
+	var req domain.MembershipUpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	member, err := h.organizationService.UpdateMemberRole(c.Request.Context(), id, userID, &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(member))
+}
+
+// RemoveMember handles removing a member from an organization
+// @Summary Remove member
+// @Description Remove a member; requires the admin role, and the owner role to remove an owner. Members may remove themselves to leave.
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param userId path int true "User ID"
+// @Success 204 "Member removed successfully"
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /organizations/{id}/members/{userId} [delete]
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := uintParam(c, "userId")
+	if !ok {
+		return
+	}
+
+	err := h.organizationService.RemoveMember(c.Request.Context(), id, userID)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// InviteMember handles inviting a user to an organization by email
+// @Summary Invite member
+// @Description Email an invitation to join the organization; requires the admin role, and the owner role to invite owners
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param request body domain.InvitationCreateRequest true "Invitation data"
+// @Success 201 {object} domain.Response{data=domain.InvitationResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 409 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /organizations/{id}/invitations [post]
+func (h *OrganizationHandler) InviteMember(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.InvitationCreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	invitation, err := h.organizationService.InviteMember(c.Request.Context(), id, &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewSuccessResponse(invitation))
+}
+
+// ListInvitations handles listing the pending invitations to an organization
+// @Summary List invitations
+// @Description Get the invitations that were neither accepted nor expired; requires the admin role
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Success 200 {object} domain.Response{data=[]domain.InvitationResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /organizations/{id}/invitations [get]
+func (h *OrganizationHandler) ListInvitations(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	invitations, err := h.organizationService.ListInvitations(c.Request.Context(), id)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(invitations))
+}
+
+// RevokeInvitation handles revoking a pending invitation
+// @Summary Revoke invitation
+// @Description Delete a pending invitation; requires the admin role
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param invitationId path int true "Invitation ID"
+// @Success 204 "Invitation revoked successfully"
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /organizations/{id}/invitations/{invitationId} [delete]
+func (h *OrganizationHandler) RevokeInvitation(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	invitationID, ok := uintParam(c, "invitationId")
+	if !ok {
+		return
+	}
+
+	err := h.organizationService.RevokeInvitation(c.Request.Context(), id, invitationID)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AcceptInvitation handles accepting an invitation
+// @Summary Accept invitation
+// @Description Join the organization of an invitation sent to the current user's email address
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.InvitationAcceptRequest true "Invitation token"
+// @Success 200 {object} domain.Response{data=domain.OrganizationResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 409 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /invitations/accept [post]
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	var req domain.InvitationAcceptRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	org, err := h.organizationService.AcceptInvitation(c.Request.Context(), &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(org))
+}
+
+// uintParam parses a numeric path parameter, responding with 400 when it is invalid
+func uintParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.ValidationError(name, "must be a valid number"),
+		))
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// ProjectHandlerParams holds dependencies for ProjectHandler
+type ProjectHandlerParams struct {
+	fx.In
+	ProjectService domain.ProjectService
+}
+
+// ProjectHandler handles project requests; ownership is enforced by the project service
+type ProjectHandler struct {
+	projectService domain.ProjectService
+}
+
+// NewProjectHandler creates a new project handler
+func NewProjectHandler(p ProjectHandlerParams) *ProjectHandler {
+	return &ProjectHandler{
+		projectService: p.ProjectService,
+	}
+}
+
+// ListProjects handles listing projects
+// @Summary List projects
+// @Description Get the current user's projects, or every user's projects for roles with projects:manage
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param sort query string false "Sort fields, prefix with - for descending" example(name,-created_at)
+// @Param owner_id query int false "Filter by owner"
+// @Param status query string false "Filter by status" Enums(active, archived)
+// @Param created_after query string false "Only projects created after this RFC 3339 time"
+// @Param created_before query string false "Only projects created before this RFC 3339 time"
+// @Success 200 {object} domain.Response{data=[]domain.ProjectResponse,meta=domain.Meta}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /projects [get]
+func (h *ProjectHandler) ListProjects(c *gin.Context) {
+	var filter domain.ProjectListFilter
+	if !bindQuery(c, &filter, "Invalid filter parameters") {
+		return
+	}
+
+	query, err := filter.Query()
+	if err != nil {
+		c.JSON(domain.HTTPStatusFromError(err), domain.NewErrorResponse(err.(*domain.Error)))
+		return
+	}
+
+	var pagination domain.PaginationRequest
+	if !bindQuery(c, &pagination, "Invalid pagination parameters") {
+		return
+	}
+
+	projects, total, err := h.projectService.ListProjects(c.Request.Context(), query, pagination.GetOffset(), pagination.Limit)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta(projects, pagination.GetMeta(total)))
+}
+
+// CreateProject handles creating a project
+// @Summary Create project
+// @Description Create a new project owned by the current user
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.ProjectCreateRequest true "Project data"
+// @Success 201 {object} domain.Response{data=domain.ProjectResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /projects [post]
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	var req domain.ProjectCreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	project, err := h.projectService.CreateProject(c.Request.Context(), &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewSuccessResponse(project))
+}
+
+// GetProject handles getting a project by ID
+// @Summary Get project
+// @Description Get a project owned by the current user, or any project for roles with projects:manage
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} domain.Response{data=domain.ProjectResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /projects/{id} [get]
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	if project, ok := c.Get(projectKey); ok {
+		c.JSON(http.StatusOK, domain.NewSuccessResponse(project))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.ValidationError("id", "must be a valid number"),
+		))
+		return
+	}
+
+	project, err := h.projectService.GetProject(c.Request.Context(), uint(id))
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(project))
+}
+
+// UpdateProject handles updating a project
+// @Summary Update project
+// @Description Update the given fields of a project owned by the current user, or of any project for roles with projects:manage
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param request body domain.ProjectUpdateRequest true "Project update data"
+// @Success 200 {object} domain.Response{data=domain.ProjectResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /projects/{id} [put]
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.ValidationError("id", "must be a valid number"),
+		))
+		return
+	}
+
+	var req domain.ProjectUpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	project, err := h.projectService.UpdateProject(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(project))
+}
+
+// DeleteProject handles deleting a project
+// @Summary Delete project
+// @Description Delete a project owned by the current user, or any project for roles with projects:manage
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 204 "Project deleted successfully"
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /projects/{id} [delete]
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.ValidationError("id", "must be a valid number"),
+		))
+		return
+	}
+
+	if err := h.projectService.DeleteProject(c.Request.Context(), uint(id)); err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// projectKey is the gin context key of the project LoadOwner loaded
+const projectKey = "project"
+
+// LoadOwner returns the owner of the project addressed by the id path parameter,
+// for use with middleware.RequireOwnerOrPermission. The project service hides
+// projects the actor may not access, so those fail with ErrProjectNotFound
+// rather than reaching the ownership check. The loaded project is kept for
+// GetProject to respond with.
+func (h *ProjectHandler) LoadOwner(c *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, domain.ValidationError("id", "must be a valid number")
+	}
+
+	project, err := h.projectService.GetProject(c.Request.Context(), uint(id))
+	if err != nil {
+		return 0, err
+	}
+	c.Set(projectKey, project)
+	return project.OwnerID, nil
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// RoleHandlerParams holds dependencies for RoleHandler
+type RoleHandlerParams struct {
+	fx.In
+	PermissionService domain.PermissionService
+}
+
+// RoleHandler handles role and permission management requests
+type RoleHandler struct {
+	permissionService domain.PermissionService
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(p RoleHandlerParams) *RoleHandler {
+	return &RoleHandler{
+		permissionService: p.PermissionService,
+	}
+}
+
+// ListRoles handles listing roles
+// @Summary List roles
+// @Description Get all roles with their permissions
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.Response{data=[]domain.Role}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /roles [get]
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.permissionService.ListRoles(c.Request.Context())
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(roles))
+}
+
+// CreateRole handles creating a role
+// @Summary Create role
+// @Description Create a new role with a set of permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.RoleCreateRequest true "Role data"
+// @Success 201 {object} domain.Response{data=domain.Role}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 409 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /roles [post]
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req domain.RoleCreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	role, err := h.permissionService.CreateRole(c.Request.Context(), &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewSuccessResponse(role))
+}
+
+// UpdateRole handles updating a role
+// @Summary Update role
+// @Description Update a role's description and permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Role name"
+// @Param request body domain.RoleUpdateRequest true "Role update data"
+// @Success 200 {object} domain.Response{data=domain.Role}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /roles/{name} [put]
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	var req domain.RoleUpdateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	role, err := h.permissionService.UpdateRole(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(role))
+}
+
+// DeleteRole handles deleting a role
+// @Summary Delete role
+// @Description Delete a custom role
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Role name"
+// @Success 204 "Role deleted successfully"
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /roles/{name} [delete]
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	if err := h.permissionService.DeleteRole(c.Request.Context(), c.Param("name")); err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListPermissions handles listing permissions
+// @Summary List permissions
+// @Description Get all known permissions
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.Response{data=[]domain.Permission}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /permissions [get]
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	permissions, err := h.permissionService.ListPermissions(c.Request.Context())
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(permissions))
+}
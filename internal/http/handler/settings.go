@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/http/middleware"
+	"go.uber.org/fx"
+)
+
+// SettingsHandlerParams holds dependencies for SettingsHandler
+type SettingsHandlerParams struct {
+	fx.In
+	SettingsService domain.UserSettingsService
+}
+
+// SettingsHandler handles the current user's settings
+type SettingsHandler struct {
+	settingsService domain.UserSettingsService
+}
+
+// NewSettingsHandler creates a new settings handler
+func NewSettingsHandler(p SettingsHandlerParams) *SettingsHandler {
+	return &SettingsHandler{
+		settingsService: p.SettingsService,
+	}
+}
+
+// GetSettings handles getting the current user's settings
+// @Summary Get current user settings
+// @Description Get every setting of the currently authenticated user, with defaults for the ones never set
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.Response{data=domain.UserSettings}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /auth/settings [get]
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	settings, err := h.settingsService.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(settings))
+}
+
+// UpdateSettings handles updating the current user's settings
+// @Summary Update current user settings
+// @Description Update the given settings of the currently authenticated user; a null value resets a setting to its default
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.UserSettings true "Settings to change"
+// @Success 200 {object} domain.Response{data=domain.UserSettings}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /auth/settings [put]
+func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	var req domain.UserSettings
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	settings, err := h.settingsService.UpdateSettings(c.Request.Context(), userID, req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(settings))
+}
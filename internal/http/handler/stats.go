@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// StatsHandlerParams holds dependencies for StatsHandler
+type StatsHandlerParams struct {
+	fx.In
+	StatsService domain.StatsService
+}
+
+// StatsHandler handles admin dashboard statistics requests
+type StatsHandler struct {
+	statsService domain.StatsService
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(p StatsHandlerParams) *StatsHandler {
+	return &StatsHandler{
+		statsService: p.StatsService,
+	}
+}
+
+// GetStats handles getting the admin dashboard statistics
+// @Summary Get dashboard statistics
+// @Description Get the total and active users, and the signups and logins of each of the last 30 days (UTC), oldest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.Response{data=domain.AdminStats}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /admin/stats [get]
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	stats, err := h.statsService.Overview(c.Request.Context())
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(stats))
+}
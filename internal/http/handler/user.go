@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/luxixing/fx-gin-scaffold/internal/domain"
 	"github.com/luxixing/fx-gin-scaffold/internal/http/middleware"
+	"github.com/luxixing/fx-gin-scaffold/pkg/fieldset"
 	"go.uber.org/fx"
 )
 
@@ -36,21 +37,49 @@ func NewUserHandler(p UserHandlerParams) *UserHandler {
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param sort query string false "Sort fields, prefix with - for descending" example(name,-created_at)
+// @Param role query string false "Filter by role"
+// @Param active query bool false "Filter by active status; inactive users are left out when omitted"
+// @Param include_inactive query bool false "Include inactive users" default(false)
+// @Param created_after query string false "Only users created after this RFC 3339 time"
+// @Param created_before query string false "Only users created before this RFC 3339 time"
+// @Param after query string false "Cursor to continue with older users"
+// @Param before query string false "Cursor to go back to newer users"
+// @Param fields query string false "Comma separated fields to include" example(id,email,name)
 // @Success 200 {object} domain.Response{data=[]domain.UserResponse,meta=domain.Meta}
+// @Failure 400 {object} domain.Response{error=domain.Error}
 // @Failure 401 {object} domain.Response{error=domain.Error}
 // @Failure 403 {object} domain.Response{error=domain.Error}
 // @Failure 500 {object} domain.Response{error=domain.Error}
 // @Router /users [get]
 func (h *UserHandler) ListUsers(c *gin.Context) {
+	fields, ok := bindFields(c, domain.UserResponse{})
+	if !ok {
+		return
+	}
+
+	var filter domain.UserListFilter
+	if !bindQuery(c, &filter, "Invalid filter parameters") {
+		return
+	}
+
+	query, err := filter.Query()
+	if err != nil {
+		c.JSON(domain.HTTPStatusFromError(err), domain.NewErrorResponse(err.(*domain.Error)))
+		return
+	}
+
+	opts := filter.Options()
+	if h.respondByCursor(c, "", query, opts, fields) {
+		return
+	}
+
 	var pagination domain.PaginationRequest
-	if err := c.ShouldBindQuery(&pagination); err != nil {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid pagination parameters", err.Error()),
-		))
+	if !bindQuery(c, &pagination, "Invalid pagination parameters") {
 		return
 	}
 
-	users, total, err := h.userService.ListUsers(c.Request.Context(), pagination.GetOffset(), pagination.Limit)
+	users, total, err := h.userService.ListUsers(c.Request.Context(), query, opts, pagination.GetOffset(), pagination.Limit)
 	if err != nil {
 		if domainErr, ok := err.(*domain.Error); ok {
 			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
@@ -61,7 +90,10 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	}
 
 	meta := pagination.GetMeta(total)
-	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta(users, meta))
+	if len(query.Sorts()) == 0 && len(users) > 0 && int64(pagination.GetOffset()+len(users)) < total {
+		meta.NextCursor = users[len(users)-1].Cursor().Encode()
+	}
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta(fields.Project(users), meta))
 }
 
 // SearchUsers handles searching users
@@ -73,6 +105,9 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 // @Param q query string true "Search query"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param after query string false "Cursor to continue with older users"
+// @Param before query string false "Cursor to go back to newer users"
+// @Param fields query string false "Comma separated fields to include" example(id,email,name)
 // @Success 200 {object} domain.Response{data=[]domain.UserResponse,meta=domain.Meta}
 // @Failure 400 {object} domain.Response{error=domain.Error}
 // @Failure 401 {object} domain.Response{error=domain.Error}
@@ -88,11 +123,17 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 		return
 	}
 
+	fields, ok := bindFields(c, domain.UserResponse{})
+	if !ok {
+		return
+	}
+
+	if h.respondByCursor(c, query, nil, domain.ListOptions{}, fields) {
+		return
+	}
+
 	var pagination domain.PaginationRequest
-	if err := c.ShouldBindQuery(&pagination); err != nil {
-		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid pagination parameters", err.Error()),
-		))
+	if !bindQuery(c, &pagination, "Invalid pagination parameters") {
 		return
 	}
 
@@ -107,16 +148,66 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 	}
 
 	meta := pagination.GetMeta(total)
-	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta(users, meta))
+	if len(users) > 0 && int64(pagination.GetOffset()+len(users)) < total {
+		meta.NextCursor = users[len(users)-1].Cursor().Encode()
+	}
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta(fields.Project(users), meta))
+}
+
+// respondByCursor serves a keyset-paginated page when the request carries an
+// after or before cursor. It returns false if offset pagination applies.
+// Searches pass the search term, listings pass the filter query and options.
+func (h *UserHandler) respondByCursor(c *gin.Context, search string, query *domain.Query, opts domain.ListOptions, fields *fieldset.Set) bool {
+	var pagination domain.CursorPaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil || !pagination.IsSet() {
+		return false
+	}
+
+	if len(query.Sorts()) > 0 {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.ValidationError("sort", "cannot be combined with cursor pagination"),
+		))
+		return true
+	}
+
+	page, err := pagination.ToPage()
+	if err != nil {
+		c.JSON(domain.HTTPStatusFromError(err), domain.NewErrorResponse(err.(*domain.Error)))
+		return true
+	}
+
+	var users []*domain.UserResponse
+	var hasMore bool
+	if search == "" {
+		users, hasMore, err = h.userService.ListUsersByCursor(c.Request.Context(), query, opts, page)
+	} else {
+		users, hasMore, err = h.userService.SearchUsersByCursor(c.Request.Context(), search, page)
+	}
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return true
+	}
+
+	var first, last *domain.Cursor
+	if len(users) > 0 {
+		first, last = users[0].Cursor(), users[len(users)-1].Cursor()
+	}
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta(fields.Project(users), pagination.GetMeta(first, last, hasMore)))
+	return true
 }
 
 // GetUser handles getting a specific user
 // @Summary Get user by ID
-// @Description Get a user by their ID (admin only)
+// @Description Get a user by their ID; users may get themselves, others require users:read
 // @Tags users
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "User ID"
+// @Param fields query string false "Comma separated fields to include" example(id,email,name)
 // @Success 200 {object} domain.Response{data=domain.UserResponse}
 // @Failure 400 {object} domain.Response{error=domain.Error}
 // @Failure 401 {object} domain.Response{error=domain.Error}
@@ -133,6 +224,11 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
+	fields, ok := bindFields(c, domain.UserResponse{})
+	if !ok {
+		return
+	}
+
 	user, err := h.userService.GetUser(c.Request.Context(), uint(id))
 	if err != nil {
 		if domainErr, ok := err.(*domain.Error); ok {
@@ -143,7 +239,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, domain.NewSuccessResponse(user))
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(fields.Project(user)))
 }
 
 // UpdateUser handles updating a user
@@ -181,14 +277,64 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	}
 
 	var req domain.UserUpdateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.userService.UpdateUser(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(user))
+}
+
+// PatchUser handles partially updating a user
+// @Summary Patch user
+// @Description Apply a JSON Merge Patch (application/merge-patch+json) or JSON Patch (application/json-patch+json) to a user's name, role, active, avatar_url, phone, locale, timezone and metadata (admin only)
+// @Tags users
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body object true "Merge patch or list of patch operations"
+// @Success 200 {object} domain.Response{data=domain.UserResponse}
+// @Failure 400 {object} domain.Response{error=domain.Error}
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Failure 403 {object} domain.Response{error=domain.Error}
+// @Failure 404 {object} domain.Response{error=domain.Error}
+// @Failure 415 {object} domain.Response{error=domain.Error}
+// @Failure 500 {object} domain.Response{error=domain.Error}
+// @Router /users/{id} [patch]
+func (h *UserHandler) PatchUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
-			domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid request body", err.Error()),
+			domain.ValidationError("id", "must be a valid number"),
 		))
 		return
 	}
 
-	user, err := h.userService.UpdateUser(c.Request.Context(), uint(id), &req)
+	// Prevent users from updating themselves through admin endpoint
+	userID, _ := middleware.GetUserID(c)
+	if userID == uint(id) {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(
+			domain.NewError(domain.ErrCodeInvalid, "Cannot update your own account through admin endpoint"),
+		))
+		return
+	}
+
+	patch, ok := bindPatch(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.PatchUser(c.Request.Context(), uint(id), patch)
 	if err != nil {
 		if domainErr, ok := err.(*domain.Error); ok {
 			c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
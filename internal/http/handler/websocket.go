@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/http/middleware"
+	"github.com/luxixing/fx-gin-scaffold/internal/realtime"
+	"go.uber.org/fx"
+	"golang.org/x/net/websocket"
+)
+
+// WebSocketHandlerParams holds dependencies for WebSocketHandler
+type WebSocketHandlerParams struct {
+	fx.In
+	Hub *realtime.Hub
+}
+
+// WebSocketHandler upgrades authenticated requests to WebSocket connections
+type WebSocketHandler struct {
+	hub *realtime.Hub
+}
+
+// NewWebSocketHandler creates a new WebSocket handler
+func NewWebSocketHandler(p WebSocketHandlerParams) *WebSocketHandler {
+	return &WebSocketHandler{
+		hub: p.Hub,
+	}
+}
+
+// Connect handles WebSocket upgrades
+// @Summary Open a WebSocket connection
+// @Description Upgrade to a WebSocket that receives realtime events for the authenticated user. Browsers may pass the token as the access_token query parameter.
+// @Tags realtime
+// @Security BearerAuth
+// @Param access_token query string false "JWT access token when the Authorization header cannot be set"
+// @Success 101 "Switching protocols"
+// @Failure 401 {object} domain.Response{error=domain.Error}
+// @Router /ws [get]
+func (h *WebSocketHandler) Connect(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		return
+	}
+
+	server := websocket.Server{
+		// Connections are authenticated by token rather than cookies, so
+		// cross-origin upgrades carry no CSRF risk
+		Handshake: func(*websocket.Config, *http.Request) error {
+			return nil
+		},
+		Handler: func(conn *websocket.Conn) {
+			h.hub.Serve(userID, conn)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// redactedBodyValue replaces the values of sensitive fields in logged bodies
+const redactedBodyValue = "[redacted]"
+
+// sensitiveFieldParts mark body fields whose values are never logged
+var sensitiveFieldParts = []string{"password", "secret", "token", "authorization", "api_key", "apikey"}
+
+// BodyLoggerConfig describes the logging of request and response bodies
+type BodyLoggerConfig struct {
+	Enabled bool
+	// MaxBytes caps the captured size of each body; larger bodies are
+	// logged as truncated, without their contents
+	MaxBytes int
+}
+
+// BodyLogger logs request and response bodies, for diagnosing client
+// integrations. It can be turned on and off while serving.
+type BodyLogger struct {
+	config atomic.Pointer[BodyLoggerConfig]
+}
+
+// NewBodyLogger creates a body logger
+func NewBodyLogger(cfg BodyLoggerConfig) *BodyLogger {
+	m := &BodyLogger{}
+	m.Update(cfg)
+	return m
+}
+
+// Update replaces the configuration
+func (m *BodyLogger) Update(cfg BodyLoggerConfig) {
+	m.config.Store(&cfg)
+}
+
+// Handler returns a middleware that logs the bodies of each request with the
+// http module logger at debug level, along with the request ID. JSON and
+// form bodies are logged with the values of password, secret and token
+// fields redacted; other bodies only have their size logged. WebSocket
+// upgrades and streamed responses aren't captured.
+func (m *BodyLogger) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := m.config.Load()
+		log := logger.NamedFromContext(c.Request.Context(), logger.ModuleHTTP)
+		if !cfg.Enabled || c.GetHeader("Upgrade") != "" || !log.Core().Enabled(zap.DebugLevel) {
+			c.Next()
+			return
+		}
+
+		request := captureRequestBody(c.Request, cfg.MaxBytes)
+		w := &bodyLogWriter{ResponseWriter: c.Writer, body: capturedBody{max: cfg.MaxBytes}}
+		c.Writer = w
+
+		c.Next()
+
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", w.Status()),
+		}
+		fields = append(fields, request.fields("request_body", c.Request.Header.Get("Content-Type"))...)
+		if !w.streamed {
+			fields = append(fields, w.body.fields("response_body", w.Header().Get("Content-Type"))...)
+		}
+		log.Debug("http bodies", fields...)
+	}
+}
+
+// capturedBody holds up to max bytes of a body
+type capturedBody struct {
+	max       int
+	data      []byte
+	size      int
+	truncated bool
+}
+
+func (b *capturedBody) write(p []byte) {
+	b.size += len(p)
+	if b.truncated {
+		return
+	}
+	if len(b.data)+len(p) > b.max {
+		b.truncated = true
+		b.data = nil
+		return
+	}
+	b.data = append(b.data, p...)
+}
+
+// fields returns the log fields of the body, redacted according to its
+// content type
+func (b *capturedBody) fields(name, contentType string) []zap.Field {
+	if b.size == 0 {
+		return nil
+	}
+	// Only the start of a truncated request body is read here
+	if b.truncated {
+		return []zap.Field{zap.Bool(name+"_truncated", true)}
+	}
+	fields := []zap.Field{zap.Int(name+"_size", b.size)}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var value any
+		dec := json.NewDecoder(bytes.NewReader(b.data))
+		dec.UseNumber()
+		if err := dec.Decode(&value); err == nil {
+			fields = append(fields, zap.Any(name, redactBodyValue(value)))
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if values, err := url.ParseQuery(string(b.data)); err == nil {
+			for key := range values {
+				if isSensitiveField(key) {
+					values[key] = []string{redactedBodyValue}
+				}
+			}
+			fields = append(fields, zap.Any(name, values))
+		}
+	}
+	return fields
+}
+
+// captureRequestBody reads up to max+1 bytes of the request body and puts
+// them back in front of the rest for the handlers
+func captureRequestBody(r *http.Request, max int) *capturedBody {
+	body := &capturedBody{max: max}
+	if r.Body == nil || r.Body == http.NoBody {
+		return body
+	}
+
+	head, _ := io.ReadAll(io.LimitReader(r.Body, int64(max)+1))
+	body.write(head)
+	r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), r.Body), Closer: r.Body}
+	return body
+}
+
+// readCloser combines a reader with the closer of the original body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyLogWriter copies the response body while writing it through. A
+// flushed response is a stream and is no longer captured.
+type bodyLogWriter struct {
+	gin.ResponseWriter
+	body     capturedBody
+	streamed bool
+}
+
+func (w *bodyLogWriter) Write(data []byte) (int, error) {
+	if !w.streamed {
+		w.body.write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyLogWriter) WriteString(s string) (int, error) {
+	if !w.streamed {
+		w.body.write([]byte(s))
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyLogWriter) Flush() {
+	w.streamed = true
+	w.ResponseWriter.Flush()
+}
+
+// redactBodyValue replaces the values of sensitive object keys in a decoded
+// JSON value
+func redactBodyValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if isSensitiveField(key) {
+				v[key] = redactedBodyValue
+			} else {
+				v[key] = redactBodyValue(item)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactBodyValue(item)
+		}
+	}
+	return value
+}
+
+// isSensitiveField reports whether a field name looks like it holds a secret
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, part := range sensitiveFieldParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBodyLogger tests that bodies are logged with their secrets redacted,
+// that handlers still read the full request body and that the logger can be
+// turned off while serving
+func TestBodyLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, logger.Initialize(logger.Config{
+		Level:  "info",
+		Output: path,
+		Levels: map[string]string{logger.ModuleHTTP: "debug"},
+	}))
+	defer logger.SetModuleLevel(logger.ModuleHTTP, "")
+
+	bodies := NewBodyLogger(BodyLoggerConfig{Enabled: true, MaxBytes: 64})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), bodies.Handler())
+	router.POST("/login", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		c.JSON(http.StatusOK, gin.H{"access_token": "jwt", "size": len(body)})
+	})
+
+	send := func(body string) {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(RequestIDHeader, "req-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "req-1", w.Header().Get(RequestIDHeader))
+	}
+
+	small := `{"email":"alice@example.com","password":"secret"}`
+	large := `{"email":"alice@example.com","password":"` + strings.Repeat("x", 64) + `"}`
+	send(small)
+	send(large)
+	bodies.Update(BodyLoggerConfig{Enabled: false, MaxBytes: 64})
+	send(`{}`)
+	require.NoError(t, logger.Named(logger.ModuleHTTP).Sync())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var entries []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, "req-1", entries[0]["request_id"])
+	assert.Equal(t, map[string]any{"email": "alice@example.com", "password": "[redacted]"}, entries[0]["request_body"])
+	assert.Equal(t, map[string]any{"access_token": "[redacted]", "size": float64(len(small))}, entries[0]["response_body"])
+
+	assert.Equal(t, true, entries[1]["request_body_truncated"])
+	assert.NotContains(t, entries[1], "request_body")
+	assert.NotContains(t, entries[1], "request_body_size")
+	assert.Equal(t, map[string]any{"access_token": "[redacted]", "size": float64(len(large))}, entries[1]["response_body"])
+}
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/", func(c *gin.Context) { c.String(http.StatusOK, GetRequestID(c)) })
+
+	for header, kept := range map[string]bool{
+		"":                       false,
+		"abc-123":                true,
+		"bad id":                 false,
+		strings.Repeat("a", 100): false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, header)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		id := w.Header().Get(RequestIDHeader)
+		assert.Equal(t, id, w.Body.String())
+		if kept {
+			assert.Equal(t, header, id)
+		} else {
+			assert.Len(t, id, 32)
+		}
+	}
+}
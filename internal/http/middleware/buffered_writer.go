@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"bytes"
+	"mime"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bufferedJSONWriter holds back JSON bodies so a middleware can rewrite
+// them once the handler is done. Other responses, and streams that are
+// flushed, are written through.
+type bufferedJSONWriter struct {
+	gin.ResponseWriter
+
+	decided   bool
+	buffering bool
+	body      bytes.Buffer
+}
+
+func (w *bufferedJSONWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decide()
+	}
+	if w.buffering {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bufferedJSONWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *bufferedJSONWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decide()
+	}
+	if !w.buffering {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *bufferedJSONWriter) Written() bool {
+	return w.body.Len() > 0 || w.ResponseWriter.Written()
+}
+
+func (w *bufferedJSONWriter) Size() int {
+	if w.buffering {
+		return w.body.Len()
+	}
+	return w.ResponseWriter.Size()
+}
+
+// Flush gives up on rewriting: a flushed response is a stream
+func (w *bufferedJSONWriter) Flush() {
+	if w.buffering {
+		w.buffering = false
+		w.ResponseWriter.Write(w.body.Bytes())
+		w.body.Reset()
+	}
+	w.decided = true
+	w.ResponseWriter.Flush()
+}
+
+// decide buffers the body if the response is JSON
+func (w *bufferedJSONWriter) decide() {
+	w.decided = true
+	mediaType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	w.buffering = err == nil && mediaType == "application/json"
+}
+
+// finish writes the held back body as rewritten by rewrite, which may also
+// change the headers. Nothing is rewritten if the response wasn't buffered.
+func (w *bufferedJSONWriter) finish(rewrite func(body []byte) []byte) {
+	if !w.buffering {
+		return
+	}
+
+	body := rewrite(w.body.Bytes())
+	if w.Header().Get("Content-Length") != "" {
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	w.ResponseWriter.Write(body)
+}
@@ -0,0 +1,258 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CompressionConfig describes which responses are compressed
+type CompressionConfig struct {
+	// ContentTypes lists compressible media types, e.g. "application/json"
+	// or "text/*". Other types, such as images and archives, are sent as
+	// is, and so is text/event-stream whatever the list says.
+	ContentTypes []string
+	// MinLength is the smallest body worth compressing, in bytes
+	MinLength int
+	// Encoders lists the supported content codings in order of preference.
+	// Gzip is used when empty; add an Encoder to support others such as br.
+	Encoders []Encoder
+}
+
+// Encoder produces a content coding for response bodies
+type Encoder interface {
+	// Encoding returns the content coding token, e.g. "gzip" or "br"
+	Encoding() string
+
+	// NewWriter returns a writer compressing into w. Closing it flushes
+	// the remaining data without closing w.
+	NewWriter(w io.Writer) io.WriteCloser
+}
+
+// GzipEncoder compresses responses with gzip
+type GzipEncoder struct {
+	pool sync.Pool
+}
+
+// NewGzipEncoder creates a gzip encoder with the given compression level
+func NewGzipEncoder(level int) (*GzipEncoder, error) {
+	// Fail on invalid levels now rather than on the first response
+	if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+		return nil, err
+	}
+
+	e := &GzipEncoder{}
+	e.pool.New = func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, level)
+		return w
+	}
+	return e, nil
+}
+
+// Encoding returns the gzip content coding token
+func (e *GzipEncoder) Encoding() string {
+	return "gzip"
+}
+
+// NewWriter returns a pooled gzip writer compressing into w
+func (e *GzipEncoder) NewWriter(w io.Writer) io.WriteCloser {
+	gz := e.pool.Get().(*gzip.Writer)
+	gz.Reset(w)
+	return &pooledGzipWriter{Writer: gz, pool: &e.pool}
+}
+
+// pooledGzipWriter returns its gzip writer to the pool when closed
+type pooledGzipWriter struct {
+	*gzip.Writer
+	pool *sync.Pool
+}
+
+func (w *pooledGzipWriter) Close() error {
+	err := w.Writer.Close()
+	w.pool.Put(w.Writer)
+	return err
+}
+
+// Compression compresses responses for clients that accept a supported
+// content coding. The decision is made when the body is first written, so
+// handlers don't need to know about it; WebSocket upgrades are skipped.
+func Compression(cfg CompressionConfig) gin.HandlerFunc {
+	encoders := cfg.Encoders
+	if len(encoders) == 0 {
+		gz, _ := NewGzipEncoder(gzip.DefaultCompression)
+		encoders = []Encoder{gz}
+	}
+
+	return func(c *gin.Context) {
+		if c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoder:        negotiateEncoding(c.GetHeader("Accept-Encoding"), encoders),
+			config:         &cfg,
+		}
+		c.Writer = cw
+		defer cw.close()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding picks the first encoder the client accepts, or nil
+func negotiateEncoding(acceptEncoding string, encoders []Encoder) Encoder {
+	if acceptEncoding == "" {
+		return nil
+	}
+
+	accepted := make(map[string]bool)
+	wildcard := false
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+
+		allowed := true
+		for _, param := range strings.Split(params, ";") {
+			if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+					allowed = false
+				}
+			}
+		}
+
+		if coding == "*" {
+			wildcard = allowed
+		} else {
+			accepted[coding] = allowed
+		}
+	}
+
+	for _, encoder := range encoders {
+		allowed, listed := accepted[encoder.Encoding()]
+		if allowed || (!listed && wildcard) {
+			return encoder
+		}
+	}
+	return nil
+}
+
+// compressWriter compresses the body once the response headers show it's
+// worth it
+type compressWriter struct {
+	gin.ResponseWriter
+	encoder Encoder
+	config  *CompressionConfig
+
+	decided bool
+	writer  io.WriteCloser
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decide(len(data))
+	}
+	if w.writer != nil {
+		return w.writer.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decide(-1)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(-1)
+	}
+	if flusher, ok := w.writer.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide checks the response headers and starts compressing if
+// appropriate. firstWrite is the size of the first body write, or -1 when
+// the headers are sent without a body.
+func (w *compressWriter) decide(firstWrite int) {
+	w.decided = true
+
+	header := w.Header()
+	if !w.compressible(header) {
+		return
+	}
+	header.Add("Vary", "Accept-Encoding")
+
+	if w.encoder == nil || firstWrite == 0 {
+		return
+	}
+
+	length := firstWrite
+	if contentLength := header.Get("Content-Length"); contentLength != "" {
+		length, _ = strconv.Atoi(contentLength)
+	}
+	if length >= 0 && length < w.config.MinLength {
+		return
+	}
+
+	header.Set("Content-Encoding", w.encoder.Encoding())
+	header.Del("Content-Length")
+	// The compressed body differs from the one the ETag was computed for
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+	w.writer = w.encoder.NewWriter(w.ResponseWriter)
+}
+
+// compressible reports whether the response may be compressed at all
+func (w *compressWriter) compressible(header http.Header) bool {
+	switch status := w.Status(); {
+	case status < http.StatusOK, status == http.StatusNoContent,
+		status == http.StatusPartialContent, status == http.StatusNotModified:
+		return false
+	}
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	// Compression buffers output, which would hold back streamed events
+	if mediaType == "text/event-stream" {
+		return false
+	}
+	for _, allowed := range w.config.ContentTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// close flushes the compressed stream at the end of the request
+func (w *compressWriter) close() {
+	if w.writer != nil {
+		w.writer.Close()
+	}
+}
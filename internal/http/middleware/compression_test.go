@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// largeBody is comfortably above the minimum compressed length
+var largeBody = strings.Repeat("compressible ", 200)
+
+// newCompressionRouter creates a router serving JSON, SSE and small bodies
+func newCompressionRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Compression(CompressionConfig{
+		ContentTypes: []string{"application/json", "text/*"},
+		MinLength:    256,
+	}))
+	router.GET("/json", func(c *gin.Context) {
+		c.Header("ETag", `"v1"`)
+		c.JSON(http.StatusOK, gin.H{"body": largeBody})
+	})
+	router.GET("/small", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+	router.GET("/image", func(c *gin.Context) { c.Data(http.StatusOK, "image/png", []byte(largeBody)) })
+	router.GET("/events", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Writer.WriteString("data: " + largeBody + "\n\n")
+		c.Writer.Flush()
+	})
+
+	return router
+}
+
+// compressionRequest performs a GET with the given Accept-Encoding header
+func compressionRequest(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestCompressionGzip tests that accepted, compressible responses are gzipped
+func TestCompressionGzip(t *testing.T) {
+	w := compressionRequest(newCompressionRouter(), "/json", "br;q=1.0, gzip;q=0.8")
+
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, `W/"v1"`, w.Header().Get("ETag"))
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), largeBody)
+}
+
+// TestCompressionSkipped tests responses that are sent as is
+func TestCompressionSkipped(t *testing.T) {
+	router := newCompressionRouter()
+
+	cases := map[string]struct {
+		path           string
+		acceptEncoding string
+	}{
+		"not accepted":      {"/json", ""},
+		"refused":           {"/json", "gzip;q=0, identity"},
+		"below min length":  {"/small", "gzip"},
+		"not compressible":  {"/image", "gzip"},
+		"server-sent event": {"/events", "gzip"},
+	}
+
+	for name, tc := range cases {
+		w := compressionRequest(router, tc.path, tc.acceptEncoding)
+		assert.Empty(t, w.Header().Get("Content-Encoding"), name)
+		assert.NotEmpty(t, w.Body.String(), name)
+	}
+}
@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig describes a cross-origin resource sharing policy
+type CORSConfig struct {
+	// AllowedOrigins lists exact origins ("https://app.example.com"),
+	// wildcard subdomains ("https://*.example.com") or "*" for any origin
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORSRoute overrides the policy for requests under a path prefix
+type CORSRoute struct {
+	PathPrefix string
+	Config     CORSConfig
+}
+
+// corsPolicy is a CORSConfig prepared for matching
+type corsPolicy struct {
+	allowAll         bool
+	exact            map[string]bool
+	wildcards        []wildcardOrigin
+	allowMethods     string
+	allowHeaders     string
+	exposeHeaders    string
+	allowCredentials bool
+	maxAge           string
+}
+
+// wildcardOrigin matches any subdomain of a host for a scheme
+type wildcardOrigin struct {
+	scheme string
+	suffix string // ".example.com"
+}
+
+// CORSMiddleware applies CORS policies. The default policy can be replaced
+// while serving, such as when the allowed origins are reloaded.
+type CORSMiddleware struct {
+	defaultPolicy atomic.Pointer[corsPolicy]
+	routes        []routePolicy
+}
+
+// routePolicy is a CORSRoute prepared for matching
+type routePolicy struct {
+	prefix string
+	policy *corsPolicy
+}
+
+// NewCORSMiddleware creates a CORS middleware with a default policy and route overrides
+func NewCORSMiddleware(cfg CORSConfig, routes ...CORSRoute) *CORSMiddleware {
+	m := &CORSMiddleware{routes: make([]routePolicy, 0, len(routes))}
+	m.defaultPolicy.Store(newCORSPolicy(cfg))
+	for _, route := range routes {
+		m.routes = append(m.routes, routePolicy{prefix: route.PathPrefix, policy: newCORSPolicy(route.Config)})
+	}
+	return m
+}
+
+// Update replaces the default policy; route overrides are unchanged
+func (m *CORSMiddleware) Update(cfg CORSConfig) {
+	m.defaultPolicy.Store(newCORSPolicy(cfg))
+}
+
+// Handler returns a middleware applying the default policy, or the policy of
+// the longest matching route override
+func (m *CORSMiddleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, longest := m.defaultPolicy.Load(), -1
+		for _, rp := range m.routes {
+			if strings.HasPrefix(c.Request.URL.Path, rp.prefix) && len(rp.prefix) > longest {
+				policy, longest = rp.policy, len(rp.prefix)
+			}
+		}
+
+		policy.handle(c)
+	}
+}
+
+// CORS returns a middleware applying the default policy, or the policy of
+// the longest matching route override
+func CORS(cfg CORSConfig, routes ...CORSRoute) gin.HandlerFunc {
+	return NewCORSMiddleware(cfg, routes...).Handler()
+}
+
+// newCORSPolicy compiles a configuration
+func newCORSPolicy(cfg CORSConfig) *corsPolicy {
+	p := &corsPolicy{
+		exact:            make(map[string]bool),
+		allowMethods:     strings.Join(cfg.AllowedMethods, ", "),
+		allowHeaders:     strings.Join(cfg.AllowedHeaders, ", "),
+		exposeHeaders:    strings.Join(cfg.ExposedHeaders, ", "),
+		allowCredentials: cfg.AllowCredentials,
+	}
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch {
+		case origin == "":
+		case origin == "*":
+			p.allowAll = true
+		case strings.Contains(origin, "://*."):
+			scheme, host, _ := strings.Cut(origin, "://")
+			p.wildcards = append(p.wildcards, wildcardOrigin{scheme: scheme, suffix: strings.TrimPrefix(host, "*")})
+		default:
+			p.exact[strings.TrimSuffix(origin, "/")] = true
+		}
+	}
+
+	return p
+}
+
+// allowed reports whether the origin matches the policy
+func (p *corsPolicy) allowed(origin string) bool {
+	if p.allowAll {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if p.exact[origin] {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := u.Hostname()
+	for _, w := range p.wildcards {
+		if u.Scheme == w.scheme && strings.HasSuffix(host, w.suffix) && len(host) > len(w.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// handle applies the policy to a request
+func (p *corsPolicy) handle(c *gin.Context) {
+	origin := c.GetHeader("Origin")
+	preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+	// Responses differ per origin unless every origin gets "*"
+	if !p.allowAll || p.allowCredentials {
+		c.Writer.Header().Add("Vary", "Origin")
+	}
+	if preflight {
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+	}
+
+	if origin == "" {
+		c.Next()
+		return
+	}
+
+	if !p.allowed(origin) {
+		if preflight {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+		return
+	}
+
+	// Browsers reject "*" on credentialed requests, so echo the origin instead
+	if p.allowAll && !p.allowCredentials {
+		c.Header("Access-Control-Allow-Origin", "*")
+	} else {
+		c.Header("Access-Control-Allow-Origin", origin)
+	}
+	if p.allowCredentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+
+	if !preflight {
+		if p.exposeHeaders != "" {
+			c.Header("Access-Control-Expose-Headers", p.exposeHeaders)
+		}
+		c.Next()
+		return
+	}
+
+	if p.allowMethods != "" {
+		c.Header("Access-Control-Allow-Methods", p.allowMethods)
+	}
+	if p.allowHeaders != "" {
+		c.Header("Access-Control-Allow-Headers", p.allowHeaders)
+	}
+	if p.maxAge != "" {
+		c.Header("Access-Control-Max-Age", p.maxAge)
+	}
+	c.AbortWithStatus(http.StatusNoContent)
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCORSRouter creates a router with a credentialed policy and a public override
+func newCORSRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(CORS(
+		CORSConfig{
+			AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+			AllowedMethods:   []string{"GET", "PUT"},
+			AllowedHeaders:   []string{"Authorization"},
+			AllowCredentials: true,
+			MaxAge:           time.Hour,
+		},
+		CORSRoute{PathPrefix: "/public", Config: CORSConfig{AllowedOrigins: []string{"*"}}},
+	))
+	router.GET("/private", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/public/info", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return router
+}
+
+// corsRequest performs a request with an Origin header, optionally as a preflight
+func corsRequest(router *gin.Engine, path, origin string, preflight bool) *httptest.ResponseRecorder {
+	method := http.MethodGet
+	if preflight {
+		method = http.MethodOptions
+	}
+
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Origin", origin)
+	if preflight {
+		req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestCORSOriginMatching tests exact and wildcard subdomain origins
+func TestCORSOriginMatching(t *testing.T) {
+	router := newCORSRouter()
+
+	cases := map[string]bool{
+		"https://app.example.com":   true,
+		"https://a.b.example.org":   true,
+		"https://example.org":       false,
+		"http://a.example.org":      false,
+		"https://evil.com":          false,
+		"https://app.example.com.x": false,
+	}
+
+	for origin, allowed := range cases {
+		w := corsRequest(router, "/private", origin, false)
+		assert.Equal(t, http.StatusOK, w.Code, origin)
+		assert.Contains(t, w.Header().Values("Vary"), "Origin", origin)
+		if allowed {
+			assert.Equal(t, origin, w.Header().Get("Access-Control-Allow-Origin"), origin)
+			assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"), origin)
+		} else {
+			assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), origin)
+		}
+	}
+}
+
+// TestCORSPreflight tests preflight responses for allowed and rejected origins
+func TestCORSPreflight(t *testing.T) {
+	router := newCORSRouter()
+
+	w := corsRequest(router, "/private", "https://app.example.com", true)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET, PUT", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "3600", w.Header().Get("Access-Control-Max-Age"))
+
+	w = corsRequest(router, "/private", "https://evil.com", true)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+// TestCORSRouteOverride tests that route overrides replace the default policy
+func TestCORSRouteOverride(t *testing.T) {
+	router := newCORSRouter()
+
+	w := corsRequest(router, "/public/info", "https://evil.com", false)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+// TestCORSUpdate tests that updating the default policy applies to later requests
+func TestCORSUpdate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cors := NewCORSMiddleware(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}})
+	router := gin.New()
+	router.Use(cors.Handler())
+	router.GET("/private", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := corsRequest(router, "/private", "https://new.example.com", false)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	cors.Update(CORSConfig{AllowedOrigins: []string{"https://new.example.com"}})
+
+	w = corsRequest(router, "/private", "https://new.example.com", false)
+	assert.Equal(t, "https://new.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	w = corsRequest(router, "/private", "https://app.example.com", false)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// IdempotencyKeyHeader is the request header carrying an idempotency key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKeyPrefix is the cache key prefix for stored responses
+const idempotencyKeyPrefix = "idempotency:"
+
+// idempotencyLockTTL bounds how long a request holds its key while being
+// processed, so a key isn't stuck if the instance dies before releasing it
+const idempotencyLockTTL = time.Minute
+
+// ErrIdempotencyKeyInUse is returned for retries of a request that is still being processed
+var ErrIdempotencyKeyInUse = domain.NewError(domain.ErrCodeAlreadyExists, "A request with this Idempotency-Key is still being processed")
+
+// ErrIdempotencyKeyReused is returned when a key is sent again with a different request body
+var ErrIdempotencyKeyReused = domain.NewError(domain.ErrCodeAlreadyExists, "This Idempotency-Key was already used for a different request")
+
+// SubjectFunc returns who a request is authenticated as, reporting false for
+// anonymous requests
+type SubjectFunc func(c *gin.Context) (string, bool)
+
+// idempotentResponse is a response stored for replaying to retries. While
+// the first request is being processed only its request hash is stored.
+type idempotentResponse struct {
+	RequestHash string `json:"request_hash"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Idempotency middleware that makes authenticated POST and PATCH requests
+// with an Idempotency-Key header safe to retry: the response is stored in
+// client for ttl and replayed, with an Idempotent-Replayed header, to
+// requests of the same subject with the same key, method and path. Sending
+// the key with a different body, or while the first request is still being
+// processed, gets 409. Anonymous requests, such as logins, are never
+// stored, and neither are server errors, so requests that failed with them
+// run again.
+func Idempotency(client cache.Client, ttl time.Duration, subject SubjectFunc) gin.HandlerFunc {
+	lockTTL := idempotencyLockTTL
+	if ttl < lockTTL {
+		lockTTL = ttl
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || (c.Request.Method != http.MethodPost && c.Request.Method != http.MethodPatch) {
+			c.Next()
+			return
+		}
+		id, ok := subject(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		log := logger.NamedFromContext(ctx, logger.ModuleHTTP)
+		cacheKey := idempotencyCacheKey(c, id, key)
+
+		requestHash, err := hashRequestBody(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(domain.NewError(domain.ErrCodeInvalid, "Failed to read request body")))
+			c.Abort()
+			return
+		}
+
+		pending, err := json.Marshal(idempotentResponse{RequestHash: requestHash})
+		if err != nil {
+			c.Next()
+			return
+		}
+		acquired, err := client.SetNX(ctx, cacheKey, string(pending), lockTTL)
+		if err != nil {
+			log.Error("failed to reserve idempotency key", zap.Error(err))
+			c.Next()
+			return
+		}
+		if !acquired {
+			replayResponse(c, client, cacheKey, requestHash)
+			return
+		}
+
+		// Release the key unless a response was stored, also when the
+		// handler panics or the client goes away
+		stored := false
+		defer func() {
+			if stored {
+				return
+			}
+			if err := client.Delete(context.WithoutCancel(ctx), cacheKey); err != nil {
+				log.Error("failed to release idempotency key", zap.Error(err))
+			}
+		}()
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		data, err := json.Marshal(idempotentResponse{
+			RequestHash: requestHash,
+			Status:      writer.Status(),
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		})
+		if err == nil {
+			err = client.Set(context.WithoutCancel(ctx), cacheKey, string(data), ttl)
+		}
+		if err != nil {
+			log.Error("failed to store idempotent response", zap.Error(err))
+			return
+		}
+		stored = true
+	}
+}
+
+// replayResponse answers a retry with the stored response, or 409 while the
+// first request is still being processed or when the body differs. Requests
+// whose key expired in the meantime are processed as new.
+func replayResponse(c *gin.Context, client cache.Client, cacheKey, requestHash string) {
+	log := logger.NamedFromContext(c.Request.Context(), logger.ModuleHTTP)
+	value, err := client.Get(c.Request.Context(), cacheKey)
+	if errors.Is(err, cache.ErrCacheMiss) {
+		c.Next()
+		return
+	}
+	if err != nil {
+		log.Error("failed to load idempotent response", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		c.Abort()
+		return
+	}
+
+	var stored idempotentResponse
+	if err := json.Unmarshal([]byte(value), &stored); err != nil {
+		log.Error("failed to decode idempotent response", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		c.Abort()
+		return
+	}
+	if stored.RequestHash != requestHash {
+		c.JSON(http.StatusConflict, domain.NewErrorResponse(ErrIdempotencyKeyReused))
+		c.Abort()
+		return
+	}
+	if stored.Status == 0 {
+		c.JSON(http.StatusConflict, domain.NewErrorResponse(ErrIdempotencyKeyInUse))
+		c.Abort()
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	if len(stored.Body) == 0 {
+		c.Status(stored.Status)
+	} else {
+		c.Data(stored.Status, stored.ContentType, stored.Body)
+	}
+	c.Abort()
+}
+
+// idempotencyCacheKey scopes an idempotency key to the subject, method and
+// path of the request, so clients can't replay each other's responses
+func idempotencyCacheKey(c *gin.Context, subject, key string) string {
+	hash := sha256.New()
+	for _, part := range []string{subject, c.Request.Method, c.Request.URL.Path, key} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return idempotencyKeyPrefix + hex.EncodeToString(hash.Sum(nil))
+}
+
+// hashRequestBody hashes the request body, leaving it to be read again
+func hashRequestBody(c *gin.Context) (string, error) {
+	if c.Request.Body == nil {
+		return hex.EncodeToString(sha256.New().Sum(nil)), nil
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return "", err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// recordingWriter keeps a copy of the body it writes
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
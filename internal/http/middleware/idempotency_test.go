@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bearerSubject authenticates requests as the bearer token itself
+func bearerSubject(c *gin.Context) (string, bool) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	return token, token != ""
+}
+
+// newIdempotencyRouter creates a router counting the orders it creates; an
+// order for "fail" fails with a server error, "panic" panics, and "slow"
+// signals on release once it is being processed, then waits for release to
+// be closed
+func newIdempotencyRouter(client cache.Client, created *int, release chan struct{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.Use(Idempotency(client, time.Hour, bearerSubject))
+	router.POST("/orders/:item", func(c *gin.Context) {
+		switch c.Param("item") {
+		case "fail":
+			c.Status(http.StatusInternalServerError)
+			return
+		case "panic":
+			panic("boom")
+		case "slow":
+			release <- struct{}{}
+			<-release
+		}
+		*created++
+		c.JSON(http.StatusCreated, gin.H{"order": *created})
+	})
+	router.GET("/orders/:item", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"order": *created})
+	})
+	return router
+}
+
+// TestIdempotency tests that retries with the same key get the stored
+// response, with both the in-memory and the Redis cache
+func TestIdempotency(t *testing.T) {
+	redisClient, err := cache.NewRedisClient(cache.Config{Addr: miniredis.RunT(t).Addr()})
+	require.NoError(t, err)
+	defer redisClient.Close()
+
+	for name, client := range map[string]cache.Client{"memory": cache.NewMemoryClient(), "redis": redisClient} {
+		t.Run(name, func(t *testing.T) {
+			var created int
+			router := newIdempotencyRouter(client, &created, nil)
+			alice := http.Header{IdempotencyKeyHeader: {"key-1"}, "Authorization": {"Bearer alice"}}
+
+			w := responseFormatRequest(router, http.MethodPost, "/orders/book", alice)
+			require.Equal(t, http.StatusCreated, w.Code)
+			assert.JSONEq(t, `{"order":1}`, w.Body.String())
+			assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+
+			w = responseFormatRequest(router, http.MethodPost, "/orders/book", alice)
+			require.Equal(t, http.StatusCreated, w.Code)
+			assert.JSONEq(t, `{"order":1}`, w.Body.String())
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+			assert.Equal(t, 1, created)
+
+			// Keys are scoped to the subject and path, and only apply to POST and PATCH
+			bob := http.Header{IdempotencyKeyHeader: {"key-1"}, "Authorization": {"Bearer bob"}}
+			w = responseFormatRequest(router, http.MethodPost, "/orders/book", bob)
+			assert.JSONEq(t, `{"order":2}`, w.Body.String())
+			w = responseFormatRequest(router, http.MethodPost, "/orders/pen", alice)
+			assert.JSONEq(t, `{"order":3}`, w.Body.String())
+			w = responseFormatRequest(router, http.MethodGet, "/orders/book", alice)
+			assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+
+			// Anonymous requests, such as logins, are never stored
+			anonymous := http.Header{IdempotencyKeyHeader: {"key-1"}}
+			for i := 0; i < 2; i++ {
+				w = responseFormatRequest(router, http.MethodPost, "/orders/book", anonymous)
+				assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+			}
+			assert.Equal(t, 5, created)
+
+			// A key can't be reused for a different body
+			w = idempotencyRequest(router, "/orders/book", alice, `{"quantity":2}`)
+			assert.Equal(t, http.StatusConflict, w.Code)
+			assert.Contains(t, w.Body.String(), ErrIdempotencyKeyReused.Message)
+			assert.Equal(t, 5, created)
+
+			// Server errors and panics can be retried
+			for _, item := range []string{"fail", "panic"} {
+				failing := http.Header{IdempotencyKeyHeader: {"key-" + item}, "Authorization": {"Bearer alice"}}
+				for i := 0; i < 2; i++ {
+					w = responseFormatRequest(router, http.MethodPost, "/orders/"+item, failing)
+					assert.Equal(t, http.StatusInternalServerError, w.Code, item)
+					assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+				}
+			}
+		})
+	}
+}
+
+// TestIdempotencyInProgress tests that retries of a request that is still
+// being processed get 409
+func TestIdempotencyInProgress(t *testing.T) {
+	var created int
+	release := make(chan struct{})
+	router := newIdempotencyRouter(cache.NewMemoryClient(), &created, release)
+	header := http.Header{IdempotencyKeyHeader: {"key-1"}, "Authorization": {"Bearer alice"}}
+
+	done := make(chan int)
+	go func() {
+		done <- responseFormatRequest(router, http.MethodPost, "/orders/slow", header).Code
+	}()
+
+	<-release
+	w := responseFormatRequest(router, http.MethodPost, "/orders/slow", header)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"success":false,"error":{"code":"ALREADY_EXISTS","message":"A request with this Idempotency-Key is still being processed"}}`, w.Body.String())
+
+	close(release)
+	assert.Equal(t, http.StatusCreated, <-done)
+	assert.Equal(t, http.StatusCreated, responseFormatRequest(router, http.MethodPost, "/orders/slow", header).Code)
+	assert.Equal(t, 1, created)
+}
+
+// TestIdempotencyLockExpires tests that a key whose request never finished,
+// e.g. because the instance died, is only held for the lock TTL
+func TestIdempotencyLockExpires(t *testing.T) {
+	server := miniredis.RunT(t)
+	client, err := cache.NewRedisClient(cache.Config{Addr: server.Addr()})
+	require.NoError(t, err)
+	defer client.Close()
+
+	var created int
+	router := newIdempotencyRouter(client, &created, nil)
+	header := http.Header{IdempotencyKeyHeader: {"key-1"}, "Authorization": {"Bearer alice"}}
+	cacheKey := idempotencyCacheKey(&gin.Context{Request: httptest.NewRequest(http.MethodPost, "/orders/book", nil)}, "alice", "key-1")
+
+	pending, err := json.Marshal(idempotentResponse{RequestHash: hex.EncodeToString(sha256.New().Sum(nil))})
+	require.NoError(t, err)
+	require.NoError(t, server.Set(cacheKey, string(pending)))
+	server.SetTTL(cacheKey, idempotencyLockTTL)
+	assert.Equal(t, http.StatusConflict, responseFormatRequest(router, http.MethodPost, "/orders/book", header).Code)
+
+	server.FastForward(idempotencyLockTTL)
+	assert.Equal(t, http.StatusCreated, responseFormatRequest(router, http.MethodPost, "/orders/book", header).Code)
+	// The stored response is kept for the full TTL
+	assert.Equal(t, time.Hour, server.TTL(cacheKey))
+}
+
+// idempotencyRequest sends a POST request with a body
+func idempotencyRequest(router *gin.Engine, path string, header http.Header, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
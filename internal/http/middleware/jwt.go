@@ -2,57 +2,65 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
 // JWTMiddlewareParams holds dependencies for JWT middleware
 type JWTMiddlewareParams struct {
 	fx.In
-	AuthService domain.AuthService
+	AuthService       domain.AuthService
+	TokenBlacklist    domain.TokenBlacklist
+	PermissionService domain.PermissionService
 }
 
 // JWTMiddleware handles JWT authentication
 type JWTMiddleware struct {
-	authService domain.AuthService
+	authService       domain.AuthService
+	tokenBlacklist    domain.TokenBlacklist
+	permissionService domain.PermissionService
 }
 
 // NewJWTMiddleware creates a new JWT middleware
 func NewJWTMiddleware(p JWTMiddlewareParams) *JWTMiddleware {
 	return &JWTMiddleware{
-		authService: p.AuthService,
+		authService:       p.AuthService,
+		tokenBlacklist:    p.TokenBlacklist,
+		permissionService: p.PermissionService,
 	}
 }
 
 // RequireAuth middleware that requires valid JWT token
 func (m *JWTMiddleware) RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.authenticate(c) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireAuthOrQueryToken middleware that requires a valid JWT token from the
+// Authorization header or the access_token query parameter. Use it only for
+// endpoints browsers cannot attach headers to, such as WebSocket upgrades
+func (m *JWTMiddleware) RequireAuthOrQueryToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := extractToken(c)
 		if token == "" {
-			c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
-			c.Abort()
-			return
+			token = c.Query("access_token")
 		}
 
-		claims, err := m.authService.ValidateToken(token)
-		if err != nil {
-			if domainErr, ok := err.(*domain.Error); ok {
-				c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domainErr))
-			} else {
-				c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrInvalidToken))
-			}
-			c.Abort()
+		if !m.authenticateToken(c, token) {
 			return
 		}
 
-		// Set user information in context
-		c.Set(string(domain.UserIDContextKey), claims.UserID)
-		c.Set(string(domain.UserContextKey), claims.Email)
-		c.Set(string(domain.RoleContextKey), claims.Role)
-		
 		c.Next()
 	}
 }
@@ -61,14 +69,13 @@ func (m *JWTMiddleware) RequireAuth() gin.HandlerFunc {
 func (m *JWTMiddleware) RequireAdmin() gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
 		// First check if user is authenticated
-		m.RequireAuth()(c)
-		if c.IsAborted() {
+		if !m.authenticate(c) {
 			return
 		}
 
 		// Check if user has admin role
 		role, exists := c.Get(string(domain.RoleContextKey))
-		if !exists || role != "admin" {
+		if !exists || role != domain.RoleAdmin {
 			c.JSON(http.StatusForbidden, domain.NewErrorResponse(domain.ErrForbidden))
 			c.Abort()
 			return
@@ -78,6 +85,88 @@ func (m *JWTMiddleware) RequireAdmin() gin.HandlerFunc {
 	})
 }
 
+// RequirePermission middleware that requires the user's role to grant a permission
+func (m *JWTMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// First check if user is authenticated
+		if !m.authenticate(c) {
+			return
+		}
+
+		role, _ := GetUserRole(c)
+		allowed, err := m.permissionService.HasPermission(c.Request.Context(), role, permission)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, domain.NewErrorResponse(domain.ErrForbidden))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// authenticate validates the bearer token and stores the user in the context.
+// It aborts the request and returns false on failure, without advancing the chain.
+func (m *JWTMiddleware) authenticate(c *gin.Context) bool {
+	return m.authenticateToken(c, extractToken(c))
+}
+
+// authenticateToken validates the given token and stores the user in the context
+func (m *JWTMiddleware) authenticateToken(c *gin.Context, token string) bool {
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrUnauthorized))
+		c.Abort()
+		return false
+	}
+
+	claims, err := m.authService.ValidateToken(token)
+	if err != nil {
+		if domainErr, ok := err.(*domain.Error); ok {
+			c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domainErr))
+		} else {
+			c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrInvalidToken))
+		}
+		c.Abort()
+		return false
+	}
+
+	// Reject tokens revoked before their expiry
+	revoked, err := m.isRevoked(c, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		c.Abort()
+		return false
+	}
+	if revoked {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrInvalidToken))
+		c.Abort()
+		return false
+	}
+
+	// Set user information in context
+	c.Set(string(domain.UserIDContextKey), claims.UserID)
+	c.Set(string(domain.UserContextKey), claims.Email)
+	c.Set(string(domain.RoleContextKey), claims.Role)
+	c.Set(string(domain.SessionIDContextKey), claims.SessionID)
+
+	// Expose the actor to services, and the user ID to their logs, through
+	// the request context
+	ctx := logger.WithFields(c.Request.Context(), zap.Uint("user_id", claims.UserID))
+	c.Request = c.Request.WithContext(domain.WithActor(ctx, domain.Actor{
+		UserID:    claims.UserID,
+		Role:      claims.Role,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}))
+
+	return true
+}
+
 // OptionalAuth middleware that optionally validates JWT token
 func (m *JWTMiddleware) OptionalAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -94,15 +183,63 @@ func (m *JWTMiddleware) OptionalAuth() gin.HandlerFunc {
 			return
 		}
 
+		if revoked, err := m.isRevoked(c, claims); err != nil || revoked {
+			c.Next()
+			return
+		}
+
 		// Set user information in context
 		c.Set(string(domain.UserIDContextKey), claims.UserID)
 		c.Set(string(domain.UserContextKey), claims.Email)
 		c.Set(string(domain.RoleContextKey), claims.Role)
+		c.Set(string(domain.SessionIDContextKey), claims.SessionID)
+
+		// Expose the actor to services, and the user ID to their logs, through
+		// the request context
+		ctx := logger.WithFields(c.Request.Context(), zap.Uint("user_id", claims.UserID))
+		c.Request = c.Request.WithContext(domain.WithActor(ctx, domain.Actor{
+			UserID:    claims.UserID,
+			Role:      claims.Role,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}))
 		
 		c.Next()
 	}
 }
 
+// Subject returns the ID of the user the bearer token authenticates, without
+// requiring a token or changing the context. It suits middleware that runs
+// before the route's own authentication, such as Idempotency.
+func (m *JWTMiddleware) Subject(c *gin.Context) (string, bool) {
+	token := extractToken(c)
+	if token == "" {
+		return "", false
+	}
+	claims, err := m.authService.ValidateToken(token)
+	if err != nil {
+		return "", false
+	}
+	if revoked, err := m.isRevoked(c, claims); err != nil || revoked {
+		return "", false
+	}
+	return strconv.FormatUint(uint64(claims.UserID), 10), true
+}
+
+// isRevoked checks whether the token or its session has been blacklisted
+func (m *JWTMiddleware) isRevoked(c *gin.Context, claims *domain.JWTClaims) (bool, error) {
+	if claims.ID != "" {
+		revoked, err := m.tokenBlacklist.Contains(c.Request.Context(), claims.ID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+	if claims.SessionID != "" {
+		return m.tokenBlacklist.Contains(c.Request.Context(), domain.SessionBlacklistKey(claims.SessionID))
+	}
+	return false, nil
+}
+
 // extractToken extracts JWT token from Authorization header
 func extractToken(c *gin.Context) string {
 	authHeader := c.GetHeader("Authorization")
@@ -149,4 +286,14 @@ func GetUserRole(c *gin.Context) (string, bool) {
 	
 	roleStr, ok := role.(string)
 	return roleStr, ok
-}
\ No newline at end of file
+}
+// GetSessionID extracts the session ID from gin context
+func GetSessionID(c *gin.Context) (string, bool) {
+	sessionID, exists := c.Get(string(domain.SessionIDContextKey))
+	if !exists {
+		return "", false
+	}
+	
+	sessionIDStr, ok := sessionID.(string)
+	return sessionIDStr, ok
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRequireAdmin tests that only admins pass, whatever other roles grant
+func TestRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := NewJWTMiddleware(JWTMiddlewareParams{
+		AuthService: tokenAuth{users: map[string]*domain.JWTClaims{
+			"alice":     {UserID: 1, Role: domain.RoleUser},
+			"moderator": {UserID: 2, Role: domain.RoleModerator},
+			"admin":     {UserID: 3, Role: domain.RoleAdmin},
+		}},
+		TokenBlacklist:    emptyBlacklist{},
+		PermissionService: rolePermissions{grants: map[string][]string{
+			domain.RoleAdmin:     {domain.PermissionAll},
+			domain.RoleModerator: {domain.PermissionUsersRead},
+		}},
+	})
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router := gin.New()
+	router.GET("/admin", m.RequireAdmin(), ok)
+
+	tests := []struct {
+		name  string
+		token string
+		path  string
+		want  int
+	}{
+		{"admin passes", "admin", "/admin", http.StatusOK},
+		{"users are forbidden", "alice", "/admin", http.StatusForbidden},
+		{"permissions do not make an admin", "moderator", "/admin", http.StatusForbidden},
+		{"unauthenticated", "", "/admin", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
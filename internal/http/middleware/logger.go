@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// RequestLogger logs every request with the http module logger, so access
+// logs follow the module's level: server errors are logged as errors,
+// client errors as warnings and the rest as info. Query strings aren't
+// logged since they may carry tokens. The number of database queries and
+// the time spent in them are logged too, to help spot N+1 queries.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		ctx, queries := database.WithQueryStats(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Int("size", c.Writer.Size()),
+			zap.Int64("db_queries", queries.Count()),
+			zap.Duration("db_duration", queries.Duration()),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+
+		log := logger.NamedFromContext(c.Request.Context(), logger.ModuleHTTP)
+		switch {
+		case status >= http.StatusInternalServerError:
+			log.Error("request", fields...)
+		case status >= http.StatusBadRequest:
+			log.Warn("request", fields...)
+		default:
+			log.Info("request", fields...)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestRequestLoggerLevel tests that requests are logged at a level matching
+// their status and filtered by the http module level, with the fields of the
+// request context
+func TestRequestLoggerLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, logger.Initialize(logger.Config{
+		Level:  "info",
+		Output: path,
+		Levels: map[string]string{logger.ModuleHTTP: "warn"},
+	}))
+	defer logger.SetModuleLevel(logger.ModuleHTTP, "")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(RequestLogger())
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/missing", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(logger.WithFields(c.Request.Context(), zap.Uint("user_id", 7)))
+		c.Status(http.StatusNotFound)
+	})
+
+	for _, target := range []string{"/ok", "/missing?token=secret"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set(RequestIDHeader, "req-1")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	require.NoError(t, logger.Named(logger.ModuleHTTP).Sync())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var entries []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+
+	require.Len(t, entries, 1)
+	assert.Equal(t, "warn", entries[0]["level"])
+	assert.Equal(t, "http", entries[0]["logger"])
+	assert.Equal(t, "/missing", entries[0]["path"])
+	assert.Equal(t, float64(http.StatusNotFound), entries[0]["status"])
+	assert.Equal(t, float64(0), entries[0]["db_queries"])
+	assert.Equal(t, "req-1", entries[0]["request_id"])
+	assert.Equal(t, float64(7), entries[0]["user_id"])
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// OrgLoader returns the ID of the organization the requested resource belongs to.
+// Return a *domain.Error, such as a not found error, to reject the request.
+type OrgLoader func(c *gin.Context) (uint, error)
+
+// ParamOrg loads the organization from an organization ID path parameter
+func ParamOrg(name string) OrgLoader {
+	return func(c *gin.Context) (uint, error) {
+		id, err := strconv.ParseUint(c.Param(name), 10, 32)
+		if err != nil {
+			return 0, domain.ValidationError(name, "must be a valid number")
+		}
+		return uint(id), nil
+	}
+}
+
+// RequireOrgRole middleware that requires the user's role in the resource's
+// organization to grant at least minRole. Use it after RequireAuth on
+// organization scoped routes; the role is available through GetOrgRole.
+func RequireOrgRole(orgs domain.OrganizationService, minRole string, load OrgLoader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var role string
+		orgID, err := load(c)
+		if err == nil {
+			role, err = orgs.Authorize(c.Request.Context(), orgID, minRole)
+		}
+		if err != nil {
+			if domainErr, ok := err.(*domain.Error); ok {
+				c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+			} else {
+				c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set(string(domain.OrgRoleContextKey), role)
+		c.Next()
+	}
+}
+
+// GetOrgRole extracts the user's organization role set by RequireOrgRole from gin context
+func GetOrgRole(c *gin.Context) (string, bool) {
+	role, exists := c.Get(string(domain.OrgRoleContextKey))
+	if !exists {
+		return "", false
+	}
+
+	roleStr, ok := role.(string)
+	return roleStr, ok
+}
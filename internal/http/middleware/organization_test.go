@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// orgRoles authorizes with fixed roles per organization
+type orgRoles struct {
+	domain.OrganizationService
+	roles map[uint]string
+}
+
+func (o orgRoles) Authorize(_ context.Context, orgID uint, minRole string) (string, error) {
+	role, ok := o.roles[orgID]
+	if !ok {
+		return "", domain.ErrOrganizationNotFound
+	}
+	if !domain.OrgRoleAtLeast(role, minRole) {
+		return "", domain.ErrForbidden
+	}
+	return role, nil
+}
+
+// TestRequireOrgRole tests that requests pass only with a sufficient organization role
+func TestRequireOrgRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	orgs := orgRoles{roles: map[uint]string{1: domain.OrgRoleAdmin, 2: domain.OrgRoleMember}}
+	router := gin.New()
+	router.DELETE("/orgs/:orgId/boards", RequireOrgRole(orgs, domain.OrgRoleAdmin, ParamOrg("orgId")), func(c *gin.Context) {
+		role, _ := GetOrgRole(c)
+		c.String(http.StatusOK, role)
+	})
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/orgs/1/boards", http.StatusOK},
+		{"/orgs/2/boards", http.StatusForbidden},
+		{"/orgs/3/boards", http.StatusNotFound},
+		{"/orgs/abc/boards", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, tt.path, nil))
+		assert.Equal(t, tt.want, w.Code, tt.path)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/orgs/1/boards", nil))
+	assert.Equal(t, domain.OrgRoleAdmin, w.Body.String())
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// OwnerLoader returns the ID of the user owning the resource addressed by the
+// request. Return a *domain.Error, such as a not found error, to reject the request.
+type OwnerLoader func(c *gin.Context) (uint, error)
+
+// ParamOwner loads the owner from a user ID path parameter, for routes whose
+// resource is the user itself
+func ParamOwner(name string) OwnerLoader {
+	return func(c *gin.Context) (uint, error) {
+		id, err := strconv.ParseUint(c.Param(name), 10, 32)
+		if err != nil {
+			return 0, domain.ValidationError(name, "must be a valid number")
+		}
+		return uint(id), nil
+	}
+}
+
+// RequireOwnerOrAdmin middleware that requires the user to own the resource or
+// to have full access, as the admin role does
+func (m *JWTMiddleware) RequireOwnerOrAdmin(load OwnerLoader) gin.HandlerFunc {
+	return m.RequireOwnerOrPermission(domain.PermissionAll, load)
+}
+
+// RequireOwnerOrPermission middleware that requires the user to own the resource
+// or the user's role to grant a permission
+func (m *JWTMiddleware) RequireOwnerOrPermission(permission string, load OwnerLoader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// First check if user is authenticated
+		if !m.authenticate(c) {
+			return
+		}
+
+		ownerID, err := load(c)
+		if err != nil {
+			if domainErr, ok := err.(*domain.Error); ok {
+				c.JSON(domain.HTTPStatusFromError(domainErr), domain.NewErrorResponse(domainErr))
+			} else {
+				c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+			}
+			c.Abort()
+			return
+		}
+
+		if userID, _ := GetUserID(c); userID == ownerID {
+			c.Next()
+			return
+		}
+
+		role, _ := GetUserRole(c)
+		allowed, err := m.permissionService.HasPermission(c.Request.Context(), role, permission)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, domain.NewErrorResponse(domain.ErrForbidden))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// tokenAuth accepts tokens that name one of its users
+type tokenAuth struct {
+	domain.AuthService
+	users map[string]*domain.JWTClaims
+}
+
+func (a tokenAuth) ValidateToken(token string) (*domain.JWTClaims, error) {
+	if claims, ok := a.users[token]; ok {
+		return claims, nil
+	}
+	return nil, domain.ErrInvalidToken
+}
+
+// emptyBlacklist revokes nothing
+type emptyBlacklist struct{}
+
+func (emptyBlacklist) Add(context.Context, string, time.Time) error   { return nil }
+func (emptyBlacklist) Contains(context.Context, string) (bool, error) { return false, nil }
+
+// rolePermissions grants fixed permissions per role
+type rolePermissions struct {
+	domain.PermissionService
+	grants map[string][]string
+}
+
+func (p rolePermissions) HasPermission(_ context.Context, role, permission string) (bool, error) {
+	r := domain.Role{Permissions: p.grants[role]}
+	return r.HasPermission(permission), nil
+}
+
+// TestRequireOwnerOrPermission tests that owners and permitted roles pass while
+// other users are rejected
+func TestRequireOwnerOrPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := NewJWTMiddleware(JWTMiddlewareParams{
+		AuthService: tokenAuth{users: map[string]*domain.JWTClaims{
+			"alice":     {UserID: 1, Role: domain.RoleUser},
+			"bob":       {UserID: 2, Role: domain.RoleUser},
+			"moderator": {UserID: 3, Role: "moderator"},
+			"admin":     {UserID: 4, Role: domain.RoleAdmin},
+		}},
+		TokenBlacklist: emptyBlacklist{},
+		PermissionService: rolePermissions{grants: map[string][]string{
+			domain.RoleAdmin: {domain.PermissionAll},
+			"moderator":      {domain.PermissionUsersRead},
+		}},
+	})
+
+	// Documents 10 and 20 belong to alice, anything else does not exist
+	loadDocumentOwner := func(c *gin.Context) (uint, error) {
+		switch c.Param("id") {
+		case "10", "20":
+			return 1, nil
+		}
+		return 0, domain.NewError(domain.ErrCodeNotFound, "Document not found")
+	}
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router := gin.New()
+	router.GET("/users/:id", m.RequireOwnerOrPermission(domain.PermissionUsersRead, ParamOwner("id")), ok)
+	router.GET("/documents/:id", m.RequireOwnerOrAdmin(loadDocumentOwner), ok)
+
+	tests := []struct {
+		name  string
+		token string
+		path  string
+		want  int
+	}{
+		{"owner reads own user", "alice", "/users/1", http.StatusOK},
+		{"other user is forbidden", "bob", "/users/1", http.StatusForbidden},
+		{"permission grants access", "moderator", "/users/1", http.StatusOK},
+		{"invalid id", "alice", "/users/abc", http.StatusBadRequest},
+		{"unauthenticated", "", "/users/1", http.StatusUnauthorized},
+		{"owner reads own document", "alice", "/documents/10", http.StatusOK},
+		{"other user cannot read document", "bob", "/documents/20", http.StatusForbidden},
+		{"permission other than admin is not enough", "moderator", "/documents/20", http.StatusForbidden},
+		{"admin reads any document", "admin", "/documents/20", http.StatusOK},
+		{"loader errors are returned", "admin", "/documents/30", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
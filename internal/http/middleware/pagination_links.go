@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// PaginationLinks adds links to the first, previous, next and last pages,
+// built from the request URL, to the metadata of paginated responses (see
+// domain.PageLinks). Handlers need nothing beyond writing the page metadata
+// into the domain.Response envelope.
+func PaginationLinks() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		w := &bufferedJSONWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer w.finish(func(body []byte) []byte {
+			return addPageLinks(c.Request.URL, body)
+		})
+
+		c.Next()
+	}
+}
+
+// addPageLinks adds links to the metadata of an envelope body. Other bodies
+// are returned as they are.
+func addPageLinks(u *url.URL, body []byte) []byte {
+	if !bytes.Contains(body, []byte(`"meta"`)) {
+		return body
+	}
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil || env.Success == nil || env.Meta == nil {
+		return body
+	}
+
+	if env.Meta.Links = domain.PageLinks(u, env.Meta); env.Meta.Links == nil {
+		return body
+	}
+	encoded, err := json.Marshal(env)
+	if err != nil {
+		return body
+	}
+	return encoded
+}
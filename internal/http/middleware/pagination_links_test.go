@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPaginationLinksRouter creates a router serving an offset page of 25
+// items and a keyset page
+func newPaginationLinksRouter(handlers ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(handlers...)
+	router.Use(PaginationLinks())
+	router.GET("/items", func(c *gin.Context) {
+		var page domain.PaginationRequest
+		c.ShouldBindQuery(&page)
+		c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta([]int{1}, page.GetMeta(25)))
+	})
+	router.GET("/feed", func(c *gin.Context) {
+		c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta([]int{1}, &domain.Meta{Limit: 10, NextCursor: "n1", PrevCursor: "p1"}))
+	})
+	router.GET("/item", func(c *gin.Context) {
+		c.JSON(http.StatusOK, domain.NewSuccessResponse(gin.H{"id": 1}))
+	})
+
+	return router
+}
+
+// pageLinks performs a GET of path and returns the links of its metadata
+func pageLinks(t *testing.T, router *gin.Engine, path string) *domain.Links {
+	t.Helper()
+
+	w := responseFormatRequest(router, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data []int       `json:"data"`
+		Meta domain.Meta `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []int{1}, resp.Data)
+	return resp.Meta.Links
+}
+
+// TestPaginationLinksOffset tests page number links, which keep the other
+// query parameters
+func TestPaginationLinksOffset(t *testing.T) {
+	router := newPaginationLinksRouter()
+
+	links := pageLinks(t, router, "/items?page=2&limit=10&sort=-name")
+	assert.Equal(t, &domain.Links{
+		Self:  "/items?page=2&limit=10&sort=-name",
+		First: "/items?limit=10&page=1&sort=-name",
+		Prev:  "/items?limit=10&page=1&sort=-name",
+		Next:  "/items?limit=10&page=3&sort=-name",
+		Last:  "/items?limit=10&page=3&sort=-name",
+	}, links)
+
+	links = pageLinks(t, router, "/items?limit=10")
+	assert.Empty(t, links.Prev)
+	assert.Equal(t, "/items?limit=10&page=2", links.Next)
+
+	links = pageLinks(t, router, "/items?page=9&limit=10")
+	assert.Equal(t, "/items?limit=10&page=3", links.Prev, "pages past the end link back to the last page")
+	assert.Empty(t, links.Next)
+}
+
+// TestPaginationLinksCursor tests cursor links, which have no last page
+func TestPaginationLinksCursor(t *testing.T) {
+	links := pageLinks(t, newPaginationLinksRouter(), "/feed?after=c0&limit=10")
+	assert.Equal(t, &domain.Links{
+		Self:  "/feed?after=c0&limit=10",
+		First: "/feed?limit=10",
+		Prev:  "/feed?before=p1&limit=10",
+		Next:  "/feed?after=n1&limit=10",
+	}, links)
+}
+
+// TestPaginationLinksRaw tests that raw responses carry the links in a Link
+// header and that other responses are left alone
+func TestPaginationLinksRaw(t *testing.T) {
+	router := newPaginationLinksRouter(ResponseFormat(ResponseFormatRaw))
+
+	w := responseFormatRequest(router, http.MethodGet, "/items?page=2&limit=10", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[1]`, w.Body.String())
+	assert.Equal(t, `</items?limit=10&page=1>; rel="first", </items?limit=10&page=1>; rel="prev", </items?limit=10&page=3>; rel="next", </items?limit=10&page=3>; rel="last"`, w.Header().Get("Link"))
+
+	w = responseFormatRequest(newPaginationLinksRouter(), http.MethodGet, "/item", nil)
+	assert.JSONEq(t, `{"success":true,"data":{"id":1}}`, w.Body.String())
+}
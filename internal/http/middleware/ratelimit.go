@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/ratelimit"
+	"go.uber.org/zap"
+)
+
+// RateLimit middleware that limits the requests of each client IP with
+// limiter, answering 429 with a Retry-After header over the limit. The limit
+// and what is left of it are sent in X-RateLimit-* headers. Requests are let
+// through when the counters can't be reached.
+func RateLimit(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := limiter.Allow(c.Request.Context(), c.ClientIP())
+		if err != nil {
+			logger.NamedFromContext(c.Request.Context(), logger.ModuleHTTP).Error("failed to count request for rate limiting", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		reset := strconv.Itoa(int(math.Ceil(result.Reset.Seconds())))
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", reset)
+		if !result.Allowed {
+			c.Header("Retry-After", reset)
+			c.JSON(http.StatusTooManyRequests, domain.NewErrorResponse(domain.ErrTooManyRequests))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/luxixing/fx-gin-scaffold/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimit tests that requests over the limit get 429 with the limit headers
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RateLimit(ratelimit.NewLimiter(cache.NewMemoryClient(), "rate_limit:", 2, time.Minute)))
+	router.GET("/items", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	for _, remaining := range []string{"1", "0"} {
+		w := responseFormatRequest(router, http.MethodGet, "/items", nil)
+		require.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, remaining, w.Header().Get("X-RateLimit-Remaining"))
+		assert.NotEmpty(t, w.Header().Get("X-RateLimit-Reset"))
+		assert.Empty(t, w.Header().Get("Retry-After"))
+	}
+
+	w := responseFormatRequest(router, http.MethodGet, "/items", nil)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.JSONEq(t, `{"success":false,"error":{"code":"TOO_MANY_REQUESTS","message":"Too many requests"}}`, w.Body.String())
+	assert.Equal(t, w.Header().Get("X-RateLimit-Reset"), w.Header().Get("Retry-After"))
+
+	// Clients are counted by IP, taken from X-Forwarded-For only when it is
+	// set by a trusted proxy (httptest requests come from 192.0.2.1)
+	spoofed := http.Header{"X-Forwarded-For": {"10.0.0.2"}}
+	require.NoError(t, router.SetTrustedProxies(nil))
+	w = responseFormatRequest(router, http.MethodGet, "/items", spoofed)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	require.NoError(t, router.SetTrustedProxies([]string{"192.0.2.0/24"}))
+	w = responseFormatRequest(router, http.MethodGet, "/items", spoofed)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
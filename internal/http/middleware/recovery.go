@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// panicsTotal counts panics recovered from HTTP handlers
+var panicsTotal = expvar.NewInt("http_panics_total")
+
+// PanicReport describes a panic recovered from an HTTP handler
+type PanicReport struct {
+	Value  any
+	Stack  []byte
+	Method string
+	Path   string
+	Time   time.Time
+}
+
+// PanicHook is notified of recovered panics, e.g. to forward them to an
+// error tracker such as Sentry. Provide one through fx to enable it.
+type PanicHook interface {
+	OnPanic(ctx context.Context, report *PanicReport)
+}
+
+// Recovery recovers panics in later handlers. The panic and its stack are
+// logged, counted and passed to the optional hook, and the client receives
+// the standard internal error response.
+func Recovery(hook PanicHook) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+
+			// http.ErrAbortHandler deliberately aborts the response
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+
+			report := &PanicReport{
+				Value:  value,
+				Stack:  debug.Stack(),
+				Method: c.Request.Method,
+				Path:   c.Request.URL.Path,
+				Time:   time.Now(),
+			}
+
+			// Writing to a connection the client closed isn't a server fault
+			if isBrokenPipe(value) {
+				logger.FromContext(c.Request.Context()).Warn("client connection closed",
+					zap.String("method", report.Method),
+					zap.String("path", report.Path),
+					zap.Any("error", value),
+				)
+				c.Abort()
+				return
+			}
+
+			panicsTotal.Add(1)
+			logger.FromContext(c.Request.Context()).Error("panic recovered",
+				zap.String("method", report.Method),
+				zap.String("path", report.Path),
+				zap.Any("panic", value),
+				zap.ByteString("stack", report.Stack),
+			)
+
+			if hook != nil {
+				notifyPanicHook(c.Request.Context(), hook, report)
+			}
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+		}()
+
+		c.Next()
+	}
+}
+
+// notifyPanicHook calls the hook, keeping a failing hook from escaping the
+// recovery middleware
+func notifyPanicHook(ctx context.Context, hook PanicHook, report *PanicReport) {
+	defer func() {
+		if value := recover(); value != nil {
+			logger.FromContext(ctx).Error("panic hook failed", zap.String("panic", fmt.Sprint(value)))
+		}
+	}()
+	hook.OnPanic(ctx, report)
+}
+
+// isBrokenPipe reports whether the panic was caused by a closed client connection
+func isBrokenPipe(value any) bool {
+	err, ok := value.(error)
+	if !ok {
+		return false
+	}
+
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+
+	var syscallErr *os.SyscallError
+	if !errors.As(opErr, &syscallErr) {
+		return false
+	}
+
+	message := strings.ToLower(syscallErr.Error())
+	return strings.Contains(message, "broken pipe") || strings.Contains(message, "connection reset by peer")
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHook records the panics it is notified of
+type recordingHook struct {
+	reports []*PanicReport
+}
+
+func (h *recordingHook) OnPanic(ctx context.Context, report *PanicReport) {
+	h.reports = append(h.reports, report)
+}
+
+// TestRecovery tests that panics yield the standard error response and
+// reach the hook
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hook := &recordingHook{}
+	router := gin.New()
+	router.Use(Recovery(hook))
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	before := panicsTotal.Value()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var resp domain.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Success)
+	assert.Equal(t, domain.ErrCodeInternal, resp.Error.Code)
+
+	require.Len(t, hook.reports, 1)
+	assert.Equal(t, "boom", hook.reports[0].Value)
+	assert.Equal(t, "/panic", hook.reports[0].Path)
+	assert.NotEmpty(t, hook.reports[0].Stack)
+	assert.Equal(t, before+1, panicsTotal.Value())
+}
+
+// TestRecoveryWithoutHook tests recovery when no hook is configured
+func TestRecoveryWithoutHook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Recovery(nil))
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
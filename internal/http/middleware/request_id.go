@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader carries the request ID in requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the gin context key of the request ID
+const requestIDKey = "request_id"
+
+// maxRequestIDLength limits request IDs accepted from clients
+const maxRequestIDLength = 64
+
+// RequestID gives every request an ID, kept from the X-Request-ID header
+// when a proxy or client sent a valid one, and echoes it in the response so
+// logs can be matched with a client's report. The ID is added to the
+// logger of the request context.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id, _ = utils.GenerateRandomString(32)
+		}
+
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logger.WithFields(c.Request.Context(), zap.String("request_id", id)))
+		c.Next()
+	}
+}
+
+// GetRequestID extracts the request ID from gin context
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// validRequestID accepts short IDs of letters, digits and -_.:
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// Formats of response bodies
+const (
+	// ResponseFormatEnvelope wraps resources and errors in domain.Response
+	ResponseFormatEnvelope = "envelope"
+	// ResponseFormatRaw sends bare resources and RFC 7807 problem details
+	ResponseFormatRaw = "raw"
+)
+
+// ResponseFormatHeader lets a client choose the response format of a request
+const ResponseFormatHeader = "X-Response-Format"
+
+// Headers carrying the pagination metadata of raw responses
+const (
+	HeaderTotalCount = "X-Total-Count"
+	HeaderPage       = "X-Page"
+	HeaderPerPage    = "X-Per-Page"
+	HeaderTotalPages = "X-Total-Pages"
+	HeaderNextCursor = "X-Next-Cursor"
+	HeaderPrevCursor = "X-Prev-Cursor"
+)
+
+// ResponseFormat sends responses in the format the client asks for with the
+// X-Response-Format header, or in raw format when it accepts
+// application/problem+json, and in defaultFormat otherwise. Handlers always
+// write the domain.Response envelope; in raw format successful responses
+// are reduced to their data, with the pagination metadata moved to headers,
+// and errors are sent as problem details.
+func ResponseFormat(defaultFormat string) gin.HandlerFunc {
+	if defaultFormat == "" {
+		defaultFormat = ResponseFormatEnvelope
+	}
+
+	return func(c *gin.Context) {
+		if c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept, "+ResponseFormatHeader)
+		format, err := requestResponseFormat(c, defaultFormat)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, domain.NewErrorResponse(err))
+			return
+		}
+		if format == ResponseFormatEnvelope {
+			c.Next()
+			return
+		}
+
+		w := &bufferedJSONWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer w.finish(func(body []byte) []byte {
+			return unwrapEnvelope(w.Header(), w.Status(), c.Request.URL.Path, body)
+		})
+
+		c.Next()
+	}
+}
+
+// requestResponseFormat applies the request's preference over the default
+func requestResponseFormat(c *gin.Context, defaultFormat string) (string, *domain.Error) {
+	if format := c.GetHeader(ResponseFormatHeader); format != "" {
+		switch format = strings.ToLower(strings.TrimSpace(format)); format {
+		case ResponseFormatEnvelope, ResponseFormatRaw:
+			return format, nil
+		default:
+			return "", domain.ValidationError(ResponseFormatHeader, "must be one of: envelope, raw")
+		}
+	}
+
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(accepted); err == nil && mediaType == domain.ProblemMediaType {
+			return ResponseFormatRaw, nil
+		}
+	}
+	return defaultFormat, nil
+}
+
+// envelope is the domain.Response envelope as decoded from a response
+// body. The data is kept encoded, so re-encoding it leaves the data as the
+// handler wrote it.
+type envelope struct {
+	Success *bool           `json:"success"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   *domain.Error   `json:"error,omitempty"`
+	Meta    *domain.Meta    `json:"meta,omitempty"`
+}
+
+// unwrapEnvelope converts an envelope body to the raw format. Bodies that
+// aren't envelopes are returned as they are.
+func unwrapEnvelope(header http.Header, status int, path string, body []byte) []byte {
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil || env.Success == nil {
+		return body
+	}
+
+	if !*env.Success && env.Error != nil {
+		problem, err := json.Marshal(domain.NewProblem(env.Error, status, path))
+		if err != nil {
+			return body
+		}
+		header.Set("Content-Type", domain.ProblemMediaType)
+		return problem
+	}
+
+	setPaginationHeaders(header, env.Meta)
+	if len(env.Data) == 0 {
+		return []byte("null")
+	}
+	return env.Data
+}
+
+// setPaginationHeaders moves response metadata to headers
+func setPaginationHeaders(header http.Header, meta *domain.Meta) {
+	if meta == nil {
+		return
+	}
+	if meta.Page > 0 {
+		header.Set(HeaderTotalCount, strconv.FormatInt(meta.Total, 10))
+		header.Set(HeaderPage, strconv.Itoa(meta.Page))
+		header.Set(HeaderTotalPages, strconv.Itoa(meta.Pages))
+	}
+	if meta.Limit > 0 {
+		header.Set(HeaderPerPage, strconv.Itoa(meta.Limit))
+	}
+	if meta.NextCursor != "" {
+		header.Set(HeaderNextCursor, meta.NextCursor)
+	}
+	if meta.PrevCursor != "" {
+		header.Set(HeaderPrevCursor, meta.PrevCursor)
+	}
+	if link := linkHeader(meta.Links); link != "" {
+		header.Set("Link", link)
+	}
+}
+
+// linkHeader formats page links as an RFC 8288 Link header
+func linkHeader(links *domain.Links) string {
+	if links == nil {
+		return ""
+	}
+	var values []string
+	for _, link := range []struct{ rel, target string }{
+		{"first", links.First},
+		{"prev", links.Prev},
+		{"next", links.Next},
+		{"last", links.Last},
+	} {
+		if link.target != "" {
+			values = append(values, fmt.Sprintf(`<%s>; rel="%s"`, link.target, link.rel))
+		}
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newResponseFormatRouter creates a router serving enveloped resources,
+// pages and errors, and a body that isn't an envelope
+func newResponseFormatRouter(defaultFormat string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ResponseFormat(defaultFormat))
+	router.GET("/users/1", func(c *gin.Context) {
+		c.JSON(http.StatusOK, domain.NewSuccessResponse(gin.H{"id": 1, "name": "Alice"}))
+	})
+	router.GET("/users", func(c *gin.Context) {
+		page := domain.PaginationRequest{Page: 2, Limit: 1}
+		meta := page.GetMeta(3)
+		meta.NextCursor = "abc"
+		c.JSON(http.StatusOK, domain.NewSuccessResponseWithMeta([]gin.H{{"id": 2}}, meta))
+	})
+	router.POST("/users", func(c *gin.Context) {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(domain.ValidationError("email", "is required")))
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	return router
+}
+
+// responseFormatRequest performs a request without a body
+func responseFormatRequest(router *gin.Engine, method, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestResponseFormatEnvelope tests that envelopes are passed through by
+// default and when asked for
+func TestResponseFormatEnvelope(t *testing.T) {
+	w := responseFormatRequest(newResponseFormatRouter(""), http.MethodGet, "/users/1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"data":{"id":1,"name":"Alice"}}`, w.Body.String())
+	assert.Contains(t, w.Header().Values("Vary"), "Accept, X-Response-Format")
+
+	w = responseFormatRequest(newResponseFormatRouter(ResponseFormatRaw), http.MethodGet, "/users/1", http.Header{ResponseFormatHeader: {"envelope"}})
+	assert.JSONEq(t, `{"success":true,"data":{"id":1,"name":"Alice"}}`, w.Body.String())
+}
+
+// TestResponseFormatRaw tests that raw responses carry bare resources with
+// pagination headers and problem details for errors
+func TestResponseFormatRaw(t *testing.T) {
+	router := newResponseFormatRouter(ResponseFormatRaw)
+
+	w := responseFormatRequest(router, http.MethodGet, "/users/1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":1,"name":"Alice"}`, w.Body.String())
+
+	w = responseFormatRequest(router, http.MethodGet, "/users", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"id":2}]`, w.Body.String())
+	assert.Equal(t, "3", w.Header().Get(HeaderTotalCount))
+	assert.Equal(t, "2", w.Header().Get(HeaderPage))
+	assert.Equal(t, "1", w.Header().Get(HeaderPerPage))
+	assert.Equal(t, "3", w.Header().Get(HeaderTotalPages))
+	assert.Equal(t, "abc", w.Header().Get(HeaderNextCursor))
+
+	w = responseFormatRequest(router, http.MethodPost, "/users", nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, domain.ProblemMediaType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "Validation failed for field 'email': is required",
+		"instance": "/users",
+		"code": "VALIDATION_ERROR",
+		"details": "field=email",
+		"fields": [{"field": "email", "message": "is required"}]
+	}`, w.Body.String())
+
+	w = responseFormatRequest(router, http.MethodGet, "/health", nil)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String(), "bodies that aren't envelopes are kept")
+}
+
+// TestResponseFormatNegotiation tests the per-request choice of format
+func TestResponseFormatNegotiation(t *testing.T) {
+	router := newResponseFormatRouter(ResponseFormatEnvelope)
+
+	w := responseFormatRequest(router, http.MethodPost, "/users", http.Header{"Accept": {"application/json, application/problem+json;q=0.9"}})
+	assert.Equal(t, domain.ProblemMediaType, w.Header().Get("Content-Type"))
+
+	w = responseFormatRequest(router, http.MethodGet, "/users/1", http.Header{ResponseFormatHeader: {"Raw"}})
+	assert.JSONEq(t, `{"id":1,"name":"Alice"}`, w.Body.String())
+
+	w = responseFormatRequest(router, http.MethodGet, "/users/1", http.Header{ResponseFormatHeader: {"xml"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/signing"
+	"go.uber.org/zap"
+)
+
+// signedKeyIDKey is the gin context key of the key ID a request was signed with
+const signedKeyIDKey = "signed_key_id"
+
+// ErrInvalidSignature is returned for requests without a valid signature
+var ErrInvalidSignature = domain.NewError(domain.ErrCodeUnauthorized, "Invalid request signature")
+
+// RequireSignature middleware that requires requests to be signed by a
+// trusted service, for service-to-service routes that don't use user tokens.
+// The calling service is available through GetSignedKeyID.
+func RequireSignature(verifier *signing.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keyID, err := verifier.Verify(c.Request)
+		if err != nil {
+			if errors.Is(err, signing.ErrInvalidSignature) || errors.Is(err, signing.ErrReplayed) {
+				c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(ErrInvalidSignature))
+			} else {
+				logger.NamedFromContext(c.Request.Context(), logger.ModuleHTTP).Error("failed to verify request signature", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(domain.ErrInternalServer))
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set(signedKeyIDKey, keyID)
+		c.Next()
+	}
+}
+
+// GetSignedKeyID extracts the key ID set by RequireSignature from gin context
+func GetSignedKeyID(c *gin.Context) string {
+	return c.GetString(signedKeyIDKey)
+}
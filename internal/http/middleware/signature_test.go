@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/luxixing/fx-gin-scaffold/pkg/signing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRequireSignature tests that only requests signed by trusted services pass, once
+func TestRequireSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	verifier := signing.NewVerifier(signing.VerifierConfig{
+		Keys:      map[string]string{"billing": "billing-secret"},
+		Tolerance: time.Minute,
+		Nonces:    cache.NewMemoryClient(),
+	})
+	router := gin.New()
+	router.POST("/internal/invoices", RequireSignature(verifier), func(c *gin.Context) {
+		c.String(http.StatusOK, GetSignedKeyID(c))
+	})
+
+	newRequest := func(signer *signing.Signer) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/internal/invoices", strings.NewReader(`{"amount":100}`))
+		if signer != nil {
+			require.NoError(t, signer.Sign(req))
+		}
+		return req
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest(nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest(signing.NewSigner("billing", "wrong-secret")))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	signed := newRequest(signing.NewSigner("billing", "billing-secret"))
+	replay := signed.Clone(signed.Context())
+	replay.Body, _ = signed.GetBody()
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, signed)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "billing", w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, replay)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,240 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// Formats of timestamps in responses
+const (
+	TimeFormatRFC3339   = "rfc3339"
+	TimeFormatUnix      = "unix"
+	TimeFormatUnixMilli = "unix_ms"
+)
+
+// TimeFormatConfig describes how timestamps in responses are formatted when
+// the client has no preference
+type TimeFormatConfig struct {
+	// Format is one of rfc3339, unix or unix_ms
+	Format string
+	// Location is the time zone of rfc3339 timestamps; nil means UTC
+	Location *time.Location
+}
+
+// ParseTimeFormat checks a timestamp format name
+func ParseTimeFormat(format string) (string, error) {
+	switch format = strings.ToLower(strings.TrimSpace(format)); format {
+	case TimeFormatRFC3339, TimeFormatUnix, TimeFormatUnixMilli:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported time format: %s (supported: rfc3339, unix, unix_ms)", format)
+	}
+}
+
+// TimeFormat formats the timestamps of JSON responses in the format and
+// time zone the client asks for with the time_format and tz query
+// parameters, or the X-Time-Format and X-Timezone headers. Timestamps are
+// the RFC 3339 string values of object keys named timestamp or ending in
+// _at; they are stored and encoded in UTC, so responses are passed through
+// untouched when the preference is rfc3339 in UTC.
+func TimeFormat(cfg TimeFormatConfig) gin.HandlerFunc {
+	defaults := timeFormatter{format: cfg.Format, location: cfg.Location}
+	if defaults.format == "" {
+		defaults.format = TimeFormatRFC3339
+	}
+	if defaults.location == nil {
+		defaults.location = time.UTC
+	}
+
+	return func(c *gin.Context) {
+		if c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		formatter, err := requestTimeFormatter(c, defaults)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, domain.NewErrorResponse(err))
+			return
+		}
+		if formatter.passthrough() {
+			c.Next()
+			return
+		}
+
+		w := &bufferedJSONWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer w.finish(func(body []byte) []byte {
+			if formatted, err := formatTimestamps(body, formatter); err == nil {
+				return formatted
+			}
+			return body
+		})
+
+		c.Next()
+	}
+}
+
+// requestTimeFormatter applies the request's preferences over the defaults
+func requestTimeFormatter(c *gin.Context, defaults timeFormatter) (timeFormatter, *domain.Error) {
+	formatter := defaults
+
+	format := c.Query("time_format")
+	if format == "" {
+		format = c.GetHeader("X-Time-Format")
+	}
+	if format != "" {
+		parsed, err := ParseTimeFormat(format)
+		if err != nil {
+			return formatter, domain.ValidationError("time_format", "must be one of: rfc3339, unix, unix_ms")
+		}
+		formatter.format = parsed
+	}
+
+	tz := c.Query("tz")
+	if tz == "" {
+		tz = c.GetHeader("X-Timezone")
+	}
+	if tz != "" {
+		location, err := time.LoadLocation(tz)
+		if err != nil {
+			return formatter, domain.ValidationError("tz", "must be an IANA time zone name")
+		}
+		formatter.location = location
+	}
+
+	return formatter, nil
+}
+
+// timeFormatter converts timestamps to the preferred representation
+type timeFormatter struct {
+	format   string
+	location *time.Location
+}
+
+// passthrough reports whether timestamps are already encoded as preferred
+func (f timeFormatter) passthrough() bool {
+	return f.format == TimeFormatRFC3339 && f.location == time.UTC
+}
+
+// value returns the JSON value of t
+func (f timeFormatter) value(t time.Time) interface{} {
+	switch f.format {
+	case TimeFormatUnix:
+		return t.Unix()
+	case TimeFormatUnixMilli:
+		return t.UnixMilli()
+	default:
+		return t.In(f.location).Format(time.RFC3339Nano)
+	}
+}
+
+// isTimestampKey reports whether an object key holds a timestamp
+func isTimestampKey(key string) bool {
+	return key == "timestamp" || strings.HasSuffix(key, "_at")
+}
+
+// jsonFrame tracks an open JSON object or array while re-encoding
+type jsonFrame struct {
+	object bool
+	values int
+	// key is the current object key; expectKey is set while waiting for one
+	key       string
+	expectKey bool
+}
+
+// formatTimestamps re-encodes a JSON document token by token, keeping the
+// key order, and formats the values of timestamp keys
+func formatTimestamps(body []byte, formatter timeFormatter) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var out bytes.Buffer
+	var stack []*jsonFrame
+
+	// valueDone records a complete value in the enclosing object or array
+	valueDone := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		top.values++
+		if top.object {
+			top.expectKey = true
+		}
+	}
+
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if delim, ok := token.(json.Delim); ok && (delim == '}' || delim == ']') {
+			out.WriteByte(byte(delim))
+			stack = stack[:len(stack)-1]
+			valueDone()
+			continue
+		}
+
+		if top != nil && top.object && top.expectKey {
+			key := token.(string)
+			if top.values > 0 {
+				out.WriteByte(',')
+			}
+			writeJSON(&out, key)
+			out.WriteByte(':')
+			top.key = key
+			top.expectKey = false
+			continue
+		}
+
+		if top != nil && !top.object && top.values > 0 {
+			out.WriteByte(',')
+		}
+
+		switch value := token.(type) {
+		case json.Delim:
+			out.WriteByte(byte(value))
+			stack = append(stack, &jsonFrame{object: value == '{', expectKey: value == '{'})
+			continue
+		case json.Number:
+			out.WriteString(value.String())
+		case string:
+			if top != nil && top.object && isTimestampKey(top.key) {
+				if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+					writeJSON(&out, formatter.value(t))
+					break
+				}
+			}
+			writeJSON(&out, value)
+		default:
+			writeJSON(&out, value)
+		}
+		valueDone()
+	}
+
+	return out.Bytes(), nil
+}
+
+// writeJSON appends the JSON encoding of a scalar value
+func writeJSON(out *bytes.Buffer, value interface{}) {
+	encoded, _ := json.Marshal(value)
+	out.Write(encoded)
+}
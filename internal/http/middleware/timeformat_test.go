@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createdAt is the timestamp served by newTimeFormatRouter
+var createdAt = time.Date(2024, 10, 20, 12, 30, 0, 0, time.UTC)
+
+// newTimeFormatRouter creates a router serving JSON with timestamps and an event stream
+func newTimeFormatRouter(cfg TimeFormatConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(TimeFormat(cfg))
+	router.GET("/json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"data": []gin.H{{
+				"name":       "2024-10-20T12:30:00Z",
+				"created_at": createdAt,
+				"read_at":    nil,
+				"count":      12345678901,
+			}},
+			"timestamp": createdAt,
+		})
+	})
+	router.GET("/events", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Writer.WriteString(`data: {"created_at":"2024-10-20T12:30:00Z"}` + "\n\n")
+		c.Writer.Flush()
+	})
+
+	return router
+}
+
+// timeFormatRequest performs a GET of path
+func timeFormatRequest(router *gin.Engine, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestTimeFormatDefault tests that UTC RFC 3339 responses are passed through
+func TestTimeFormatDefault(t *testing.T) {
+	w := timeFormatRequest(newTimeFormatRouter(TimeFormatConfig{}), "/json", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[{"name":"2024-10-20T12:30:00Z","created_at":"2024-10-20T12:30:00Z","read_at":null,"count":12345678901}],"timestamp":"2024-10-20T12:30:00Z"}`, w.Body.String())
+}
+
+// TestTimeFormatTimezone tests that timestamps are shown in the requested
+// time zone and other values, including key order, are kept
+func TestTimeFormatTimezone(t *testing.T) {
+	w := timeFormatRequest(newTimeFormatRouter(TimeFormatConfig{}), "/json?tz=Asia/Shanghai", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"data":[{"count":12345678901,"created_at":"2024-10-20T20:30:00+08:00","name":"2024-10-20T12:30:00Z","read_at":null}],"timestamp":"2024-10-20T20:30:00+08:00"}`, w.Body.String())
+}
+
+// TestTimeFormatUnix tests the epoch formats, from headers and configuration
+func TestTimeFormatUnix(t *testing.T) {
+	w := timeFormatRequest(newTimeFormatRouter(TimeFormatConfig{}), "/json", http.Header{"X-Time-Format": {"unix"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[{"name":"2024-10-20T12:30:00Z","created_at":1729427400,"read_at":null,"count":12345678901}],"timestamp":1729427400}`, w.Body.String())
+
+	w = timeFormatRequest(newTimeFormatRouter(TimeFormatConfig{Format: TimeFormatUnixMilli}), "/json", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[{"name":"2024-10-20T12:30:00Z","created_at":1729427400000,"read_at":null,"count":12345678901}],"timestamp":1729427400000}`, w.Body.String())
+}
+
+// TestTimeFormatInvalid tests that unknown formats and time zones are rejected
+func TestTimeFormatInvalid(t *testing.T) {
+	router := newTimeFormatRouter(TimeFormatConfig{})
+
+	assert.Equal(t, http.StatusBadRequest, timeFormatRequest(router, "/json?time_format=iso", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, timeFormatRequest(router, "/json", http.Header{"X-Timezone": {"Mars/Olympus"}}).Code)
+}
+
+// TestTimeFormatStream tests that flushed streams are written through
+func TestTimeFormatStream(t *testing.T) {
+	w := timeFormatRequest(newTimeFormatRouter(TimeFormatConfig{}), "/events?time_format=unix", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `data: {"created_at":"2024-10-20T12:30:00Z"}`+"\n\n", w.Body.String())
+}
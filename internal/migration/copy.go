@@ -0,0 +1,559 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/repo"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.uber.org/zap"
+)
+
+// DefaultCopyBatchSize is the number of rows read at once by CopyData
+const DefaultCopyBatchSize = 500
+
+// ErrTargetNotEmpty is returned when copying data into a database that
+// already has users
+var ErrTargetNotEmpty = errors.New("the target database already has users; copy into a freshly migrated database")
+
+// Repositories are the repositories of one database, read or written by
+// CopyData
+type Repositories struct {
+	Users             domain.UserRepository
+	RefreshTokens     domain.RefreshTokenRepository
+	Roles             domain.RoleRepository
+	Permissions       domain.PermissionRepository
+	AuditLogs         domain.AuditLogRepository
+	Projects          domain.ProjectRepository
+	Organizations     domain.OrganizationRepository
+	Memberships       domain.MembershipRepository
+	Invitations       domain.InvitationRepository
+	Invites           domain.InviteRepository
+	Webhooks          domain.WebhookRepository
+	WebhookDeliveries domain.WebhookDeliveryRepository
+	UserSettings      domain.UserSettingRepository
+	Notifications     domain.NotificationRepository
+}
+
+// NewRepositories creates the repositories of a database of the driver,
+// whatever DB_DRIVER and DB_REPOSITORY_DRIVERS are
+func NewRepositories(cfg *config.Config, driver string, db *database.Connection, log *zap.Logger) *Repositories {
+	c := *cfg
+	c.Database.Driver = driver
+	c.Database.RepositoryDrivers = nil
+	p := repo.RepositoryParams{Config: &c, DB: db, Logger: log}
+
+	return &Repositories{
+		Users:             repo.NewUserRepository(p),
+		RefreshTokens:     repo.NewRefreshTokenRepository(p),
+		Roles:             repo.NewRoleRepository(p),
+		Permissions:       repo.NewPermissionRepository(p),
+		AuditLogs:         repo.NewAuditLogRepository(p),
+		Projects:          repo.NewProjectRepository(p),
+		Organizations:     repo.NewOrganizationRepository(p),
+		Memberships:       repo.NewMembershipRepository(p),
+		Invitations:       repo.NewInvitationRepository(p),
+		Invites:           repo.NewInviteRepository(p),
+		Webhooks:          repo.NewWebhookRepository(p),
+		WebhookDeliveries: repo.NewWebhookDeliveryRepository(p),
+		UserSettings:      repo.NewUserSettingRepository(p),
+		Notifications:     repo.NewNotificationRepository(p),
+	}
+}
+
+// CopyOptions configures CopyData
+type CopyOptions struct {
+	// BatchSize is the number of rows read at once, DefaultCopyBatchSize
+	// when 0
+	BatchSize int
+	// DryRun reads the source and counts the rows to copy without writing
+	DryRun bool
+	// Progress is called after each batch when set
+	Progress func(CopyProgress)
+}
+
+// CopyProgress counts the rows of a table copied so far
+type CopyProgress struct {
+	Table  string
+	Copied int64
+	// Total is the number of rows to copy, 0 when the source doesn't count
+	// them upfront
+	Total int64
+}
+
+// CopyData copies the rows of every repository from one database to another
+// through the repository interfaces, so that it works between any backends.
+// The target database must be migrated and have no users. Rows get new IDs
+// in the target and references between them are rewritten; creation times
+// are kept. Only what the repositories can list is copied: active refresh
+// tokens and pending organization invitations, but all other rows.
+//
+// It returns the rows copied to each table, in the order they were copied.
+func CopyData(ctx context.Context, from, to *Repositories, opts CopyOptions) ([]CopyProgress, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultCopyBatchSize
+	}
+
+	if !opts.DryRun {
+		_, total, err := to.Users.List(ctx, nil, domain.ListOptions{IncludeInactive: true}, 0, 1)
+		if err != nil {
+			return nil, err
+		}
+		if total > 0 {
+			return nil, ErrTargetNotEmpty
+		}
+	}
+
+	c := &copier{
+		from:          from,
+		to:            to,
+		opts:          opts,
+		users:         make(map[uint]uint),
+		organizations: make(map[uint]uint),
+		invites:       make(map[uint]uint),
+		webhooks:      make(map[uint]uint),
+	}
+	steps := []func(context.Context) error{
+		c.copyPermissions,
+		c.copyRoles,
+		c.copyUsers,
+		c.copyRefreshTokens,
+		c.copyUserSettings,
+		c.copyNotifications,
+		c.copyOrganizations,
+		c.copyInvitations,
+		c.copyProjects,
+		c.copyInvites,
+		c.copyWebhooks,
+		c.copyWebhookDeliveries,
+		c.copyAuditLogs,
+	}
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			return c.stats, err
+		}
+	}
+	return c.stats, nil
+}
+
+// copier copies rows, mapping the IDs of the source to those of the target
+type copier struct {
+	from, to *Repositories
+	opts     CopyOptions
+	stats    []CopyProgress
+
+	users, organizations, invites, webhooks map[uint]uint
+}
+
+// write runs a write to the target unless dry running
+func (c *copier) write(fn func() error) error {
+	if c.opts.DryRun {
+		return nil
+	}
+	return fn()
+}
+
+// created records the target ID of a copied row; a dry run keeps the source
+// ID
+func (c *copier) created(ids map[uint]uint, sourceID, targetID uint) {
+	if c.opts.DryRun {
+		targetID = sourceID
+	}
+	ids[sourceID] = targetID
+}
+
+// progress adds copied rows of a table and reports them
+func (c *copier) progress(table string, copied, total int64) {
+	if len(c.stats) == 0 || c.stats[len(c.stats)-1].Table != table {
+		c.stats = append(c.stats, CopyProgress{Table: table})
+	}
+	current := &c.stats[len(c.stats)-1]
+	current.Copied += copied
+	if total > current.Total {
+		current.Total = total
+	}
+	if c.opts.Progress != nil {
+		c.opts.Progress(*current)
+	}
+}
+
+// sourceUsers returns the source IDs of the copied users in order
+func (c *copier) sourceUsers() []uint {
+	ids := make([]uint, 0, len(c.users))
+	for id := range c.users {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// remap returns the target ID of a referenced row, 0 when it wasn't copied
+func remap(ids map[uint]uint, id uint) uint {
+	return ids[id]
+}
+
+func (c *copier) copyPermissions(ctx context.Context) error {
+	permissions, err := c.from.Permissions.List(ctx)
+	if err != nil {
+		return err
+	}
+	// A dry run doesn't read the target, which may not be migrated yet
+	if c.opts.DryRun {
+		c.progress("permissions", int64(len(permissions)), int64(len(permissions)))
+		return nil
+	}
+
+	for _, permission := range permissions {
+		// Built-in permissions are created by the migrations
+		_, err := c.to.Permissions.GetByName(ctx, permission.Name)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, domain.ErrPermissionNotFound) {
+			return err
+		}
+
+		permission.ID = 0
+		if err := c.write(func() error { return c.to.Permissions.Create(ctx, permission) }); err != nil {
+			return fmt.Errorf("permission %s: %w", permission.Name, err)
+		}
+	}
+	c.progress("permissions", int64(len(permissions)), int64(len(permissions)))
+	return nil
+}
+
+func (c *copier) copyRoles(ctx context.Context) error {
+	roles, err := c.from.Roles.List(ctx)
+	if err != nil {
+		return err
+	}
+	if c.opts.DryRun {
+		c.progress("roles", int64(len(roles)), int64(len(roles)))
+		return nil
+	}
+
+	for _, role := range roles {
+		// Built-in roles are created by the migrations, with the
+		// permissions they were granted since replaced by the source's
+		existing, err := c.to.Roles.GetByName(ctx, role.Name)
+		switch {
+		case err == nil:
+			role.ID = existing.ID
+			err = c.write(func() error { return c.to.Roles.Update(ctx, role) })
+		case errors.Is(err, domain.ErrRoleNotFound):
+			role.ID = 0
+			err = c.write(func() error { return c.to.Roles.Create(ctx, role) })
+		}
+		if err != nil {
+			return fmt.Errorf("role %s: %w", role.Name, err)
+		}
+	}
+	c.progress("roles", int64(len(roles)), int64(len(roles)))
+	return nil
+}
+
+func (c *copier) copyUsers(ctx context.Context) error {
+	_, total, err := c.from.Users.List(ctx, nil, domain.ListOptions{IncludeInactive: true}, 0, 1)
+	if err != nil {
+		return err
+	}
+
+	page := &domain.CursorPage{Limit: c.opts.BatchSize}
+	for {
+		users, hasMore, err := c.from.Users.ListByCursor(ctx, nil, domain.ListOptions{IncludeInactive: true}, page)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			copied := *user
+			copied.ID = 0
+			if err := c.write(func() error { return c.to.Users.Create(ctx, &copied) }); err != nil {
+				return fmt.Errorf("user %d: %w", user.ID, err)
+			}
+			c.created(c.users, user.ID, copied.ID)
+		}
+		c.progress("users", int64(len(users)), total)
+
+		if !hasMore || len(users) == 0 {
+			return nil
+		}
+		last := users[len(users)-1]
+		page.After = &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+func (c *copier) copyRefreshTokens(ctx context.Context) error {
+	for _, sourceID := range c.sourceUsers() {
+		tokens, err := c.from.RefreshTokens.ListActiveForUser(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+		for _, token := range tokens {
+			token.ID = 0
+			token.UserID = c.users[sourceID]
+			if err := c.write(func() error { return c.to.RefreshTokens.Create(ctx, token) }); err != nil {
+				return fmt.Errorf("refresh token of user %d: %w", sourceID, err)
+			}
+		}
+		c.progress("refresh_tokens", int64(len(tokens)), 0)
+	}
+	return nil
+}
+
+func (c *copier) copyUserSettings(ctx context.Context) error {
+	for _, sourceID := range c.sourceUsers() {
+		settings, err := c.from.UserSettings.ListByUser(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+		for _, setting := range settings {
+			setting.ID = 0
+			setting.UserID = c.users[sourceID]
+		}
+		if err := c.write(func() error { return c.to.UserSettings.Upsert(ctx, settings) }); err != nil {
+			return fmt.Errorf("settings of user %d: %w", sourceID, err)
+		}
+		c.progress("user_settings", int64(len(settings)), 0)
+	}
+	return nil
+}
+
+func (c *copier) copyNotifications(ctx context.Context) error {
+	for _, sourceID := range c.sourceUsers() {
+		for offset := 0; ; offset += c.opts.BatchSize {
+			notifications, total, err := c.from.Notifications.ListByUser(ctx, sourceID, domain.NotificationFilter{}, offset, c.opts.BatchSize)
+			if err != nil {
+				return err
+			}
+			for _, notification := range notifications {
+				notification.ID = 0
+				notification.UserID = c.users[sourceID]
+				notification.User = nil
+				if err := c.write(func() error { return c.to.Notifications.Create(ctx, notification) }); err != nil {
+					return fmt.Errorf("notification of user %d: %w", sourceID, err)
+				}
+			}
+			c.progress("notifications", int64(len(notifications)), 0)
+
+			if int64(offset+len(notifications)) >= total || len(notifications) == 0 {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// copyOrganizations copies the organizations, found through the memberships
+// of their members, and the memberships
+func (c *copier) copyOrganizations(ctx context.Context) error {
+	for _, sourceID := range c.sourceUsers() {
+		for offset := 0; ; offset += c.opts.BatchSize {
+			memberships, total, err := c.from.Memberships.ListByUser(ctx, sourceID, offset, c.opts.BatchSize)
+			if err != nil {
+				return err
+			}
+			for _, membership := range memberships {
+				if _, ok := c.organizations[membership.OrganizationID]; !ok {
+					if err := c.copyOrganization(ctx, membership); err != nil {
+						return err
+					}
+				}
+
+				membership.ID = 0
+				membership.OrganizationID = c.organizations[membership.OrganizationID]
+				membership.UserID = c.users[sourceID]
+				membership.Organization, membership.User = nil, nil
+				if err := c.write(func() error { return c.to.Memberships.Create(ctx, membership) }); err != nil {
+					return fmt.Errorf("membership of user %d: %w", sourceID, err)
+				}
+			}
+			c.progress("memberships", int64(len(memberships)), 0)
+
+			if int64(offset+len(memberships)) >= total || len(memberships) == 0 {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// copyOrganization copies the organization of a membership
+func (c *copier) copyOrganization(ctx context.Context, membership *domain.Membership) error {
+	org := membership.Organization
+	if org == nil {
+		var err error
+		if org, err = c.from.Organizations.GetByID(ctx, membership.OrganizationID); err != nil {
+			return fmt.Errorf("organization %d: %w", membership.OrganizationID, err)
+		}
+	}
+
+	copied := *org
+	copied.ID = 0
+	if err := c.write(func() error { return c.to.Organizations.Create(ctx, &copied) }); err != nil {
+		return fmt.Errorf("organization %d: %w", org.ID, err)
+	}
+	c.created(c.organizations, membership.OrganizationID, copied.ID)
+	c.progress("organizations", 1, 0)
+	return nil
+}
+
+func (c *copier) copyInvitations(ctx context.Context) error {
+	sourceIDs := make([]uint, 0, len(c.organizations))
+	for id := range c.organizations {
+		sourceIDs = append(sourceIDs, id)
+	}
+	sort.Slice(sourceIDs, func(i, j int) bool { return sourceIDs[i] < sourceIDs[j] })
+
+	for _, sourceID := range sourceIDs {
+		invitations, err := c.from.Invitations.ListPending(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+		for _, invitation := range invitations {
+			invitation.ID = 0
+			invitation.OrganizationID = c.organizations[sourceID]
+			invitation.InvitedByID = remap(c.users, invitation.InvitedByID)
+			invitation.Organization = nil
+			if err := c.write(func() error { return c.to.Invitations.Create(ctx, invitation) }); err != nil {
+				return fmt.Errorf("invitation to organization %d: %w", sourceID, err)
+			}
+		}
+		c.progress("invitations", int64(len(invitations)), 0)
+	}
+	return nil
+}
+
+func (c *copier) copyProjects(ctx context.Context) error {
+	for offset := 0; ; offset += c.opts.BatchSize {
+		projects, total, err := c.from.Projects.List(ctx, nil, offset, c.opts.BatchSize)
+		if err != nil {
+			return err
+		}
+		for _, project := range projects {
+			sourceID := project.ID
+			project.ID = 0
+			project.OwnerID = remap(c.users, project.OwnerID)
+			project.Owner = nil
+			if err := c.write(func() error { return c.to.Projects.Create(ctx, project) }); err != nil {
+				return fmt.Errorf("project %d: %w", sourceID, err)
+			}
+		}
+		c.progress("projects", int64(len(projects)), total)
+
+		if int64(offset+len(projects)) >= total || len(projects) == 0 {
+			return nil
+		}
+	}
+}
+
+func (c *copier) copyInvites(ctx context.Context) error {
+	for offset := 0; ; offset += c.opts.BatchSize {
+		invites, total, err := c.from.Invites.List(ctx, domain.InviteFilter{}, offset, c.opts.BatchSize)
+		if err != nil {
+			return err
+		}
+		for _, invite := range invites {
+			sourceID := invite.ID
+			invite.ID = 0
+			invite.InvitedByID = remap(c.users, invite.InvitedByID)
+			if invite.AcceptedByID != nil {
+				acceptedBy := remap(c.users, *invite.AcceptedByID)
+				invite.AcceptedByID = &acceptedBy
+			}
+			if err := c.write(func() error { return c.to.Invites.Create(ctx, invite) }); err != nil {
+				return fmt.Errorf("invite %d: %w", sourceID, err)
+			}
+			c.created(c.invites, sourceID, invite.ID)
+		}
+		c.progress("invites", int64(len(invites)), total)
+
+		if int64(offset+len(invites)) >= total || len(invites) == 0 {
+			return nil
+		}
+	}
+}
+
+func (c *copier) copyWebhooks(ctx context.Context) error {
+	for offset := 0; ; offset += c.opts.BatchSize {
+		webhooks, total, err := c.from.Webhooks.List(ctx, offset, c.opts.BatchSize)
+		if err != nil {
+			return err
+		}
+		for _, webhook := range webhooks {
+			sourceID := webhook.ID
+			webhook.ID = 0
+			if err := c.write(func() error { return c.to.Webhooks.Create(ctx, webhook) }); err != nil {
+				return fmt.Errorf("webhook %d: %w", sourceID, err)
+			}
+			c.created(c.webhooks, sourceID, webhook.ID)
+		}
+		c.progress("webhooks", int64(len(webhooks)), total)
+
+		if int64(offset+len(webhooks)) >= total || len(webhooks) == 0 {
+			return nil
+		}
+	}
+}
+
+func (c *copier) copyWebhookDeliveries(ctx context.Context) error {
+	sourceIDs := make([]uint, 0, len(c.webhooks))
+	for id := range c.webhooks {
+		sourceIDs = append(sourceIDs, id)
+	}
+	sort.Slice(sourceIDs, func(i, j int) bool { return sourceIDs[i] < sourceIDs[j] })
+
+	for _, sourceID := range sourceIDs {
+		for offset := 0; ; offset += c.opts.BatchSize {
+			deliveries, total, err := c.from.WebhookDeliveries.ListByWebhook(ctx, sourceID, offset, c.opts.BatchSize)
+			if err != nil {
+				return err
+			}
+			for _, delivery := range deliveries {
+				delivery.ID = 0
+				delivery.WebhookID = c.webhooks[sourceID]
+				delivery.Webhook = nil
+				if err := c.write(func() error { return c.to.WebhookDeliveries.Create(ctx, delivery) }); err != nil {
+					return fmt.Errorf("delivery of webhook %d: %w", sourceID, err)
+				}
+			}
+			c.progress("webhook_deliveries", int64(len(deliveries)), 0)
+
+			if int64(offset+len(deliveries)) >= total || len(deliveries) == 0 {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (c *copier) copyAuditLogs(ctx context.Context) error {
+	for offset := 0; ; offset += c.opts.BatchSize {
+		entries, total, err := c.from.AuditLogs.List(ctx, domain.AuditLogFilter{}, offset, c.opts.BatchSize)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			entry.ID = 0
+			// Entries outlive the users they reference, which are then 0
+			entry.ActorID = remap(c.users, entry.ActorID)
+			switch entry.TargetType {
+			case "user":
+				entry.TargetID = remap(c.users, entry.TargetID)
+			case "invite":
+				entry.TargetID = remap(c.invites, entry.TargetID)
+			}
+			if err := c.write(func() error { return c.to.AuditLogs.Create(ctx, entry) }); err != nil {
+				return fmt.Errorf("audit log: %w", err)
+			}
+		}
+		c.progress("audit_logs", int64(len(entries)), total)
+
+		if int64(offset+len(entries)) >= total || len(entries) == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newCopyRepositories migrates a database and returns its repositories
+func newCopyRepositories(t *testing.T) *Repositories {
+	db := newTestConnection(t)
+	require.NoError(t, RunSchemaMigrations(context.Background(), db, false))
+	return NewRepositories(&config.Config{}, "sqlite", db, zap.NewNop())
+}
+
+func TestCopyData(t *testing.T) {
+	ctx := context.Background()
+	from, to := newCopyRepositories(t), newCopyRepositories(t)
+
+	// A deleted user makes the source IDs differ from the target's
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	spare := &domain.User{Email: "spare@example.com", Password: "hash", Name: "Spare"}
+	require.NoError(t, from.Users.Create(ctx, spare))
+	require.NoError(t, from.Users.Delete(ctx, spare.ID))
+
+	owner := &domain.User{Email: "owner@example.com", Password: "hash", Name: "Owner", Active: true, CreatedAt: created}
+	require.NoError(t, from.Users.Create(ctx, owner))
+	member := &domain.User{Email: "member@example.com", Password: "hash", Name: "Member", Active: true, CreatedAt: created.Add(time.Hour)}
+	require.NoError(t, from.Users.Create(ctx, member))
+
+	org := &domain.Organization{Name: "Acme", Slug: "acme"}
+	require.NoError(t, from.Organizations.Create(ctx, org))
+	require.NoError(t, from.Memberships.Create(ctx, &domain.Membership{OrganizationID: org.ID, UserID: owner.ID, Role: domain.OrgRoleOwner}))
+	require.NoError(t, from.Memberships.Create(ctx, &domain.Membership{OrganizationID: org.ID, UserID: member.ID, Role: domain.OrgRoleMember}))
+	require.NoError(t, from.Projects.Create(ctx, &domain.Project{OwnerID: member.ID, Name: "Roadmap", Status: "active", CreatedAt: created}))
+	require.NoError(t, from.Notifications.Create(ctx, &domain.Notification{UserID: member.ID, Type: "welcome", Title: "Welcome"}))
+	require.NoError(t, from.UserSettings.Upsert(ctx, []*domain.UserSetting{{UserID: member.ID, Key: "theme", Value: `"dark"`}}))
+	require.NoError(t, from.AuditLogs.Create(ctx, &domain.AuditLog{ActorID: owner.ID, Action: "user.update", TargetType: "user", TargetID: member.ID}))
+
+	var reports []CopyProgress
+	stats, err := CopyData(ctx, from, to, CopyOptions{BatchSize: 1, Progress: func(p CopyProgress) { reports = append(reports, p) }})
+	require.NoError(t, err)
+	assert.NotEmpty(t, reports)
+
+	copied := make(map[string]int64)
+	for _, s := range stats {
+		copied[s.Table] = s.Copied
+	}
+	assert.Equal(t, int64(2), copied["users"])
+	assert.Equal(t, int64(1), copied["organizations"])
+	assert.Equal(t, int64(2), copied["memberships"])
+	assert.Equal(t, int64(1), copied["projects"])
+	assert.Equal(t, int64(1), copied["audit_logs"])
+
+	// References point to the copied rows, which keep their creation times
+	copiedMember, err := to.Users.GetByEmail(ctx, "member@example.com")
+	require.NoError(t, err)
+	copiedOwner, err := to.Users.GetByEmail(ctx, "owner@example.com")
+	require.NoError(t, err)
+	assert.NotEqual(t, member.ID, copiedMember.ID)
+	assert.True(t, created.Add(time.Hour).Equal(copiedMember.CreatedAt))
+	assert.Equal(t, "hash", copiedMember.Password)
+
+	projects, _, err := to.Projects.List(ctx, nil, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, copiedMember.ID, projects[0].OwnerID)
+	assert.True(t, created.Equal(projects[0].CreatedAt))
+
+	memberships, _, err := to.Memberships.ListByUser(ctx, copiedMember.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, "acme", memberships[0].Organization.Slug)
+
+	settings, err := to.UserSettings.ListByUser(ctx, copiedMember.ID)
+	require.NoError(t, err)
+	assert.Len(t, settings, 1)
+
+	notifications, _, err := to.Notifications.ListByUser(ctx, copiedMember.ID, domain.NotificationFilter{}, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, notifications, 1)
+
+	entries, _, err := to.AuditLogs.List(ctx, domain.AuditLogFilter{}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, copiedOwner.ID, entries[0].ActorID)
+	assert.Equal(t, copiedMember.ID, entries[0].TargetID)
+
+	// The target now has users
+	_, err = CopyData(ctx, from, to, CopyOptions{})
+	assert.ErrorIs(t, err, ErrTargetNotEmpty)
+}
+
+func TestCopyDataDryRun(t *testing.T) {
+	ctx := context.Background()
+	from, to := newCopyRepositories(t), newCopyRepositories(t)
+	require.NoError(t, from.Users.Create(ctx, &domain.User{Email: "a@example.com", Password: "hash", Name: "A"}))
+
+	stats, err := CopyData(ctx, from, to, CopyOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Contains(t, stats, CopyProgress{Table: "users", Copied: 1, Total: 1})
+
+	_, total, err := to.Users.List(ctx, nil, domain.ListOptions{IncludeInactive: true}, 0, 1)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
@@ -0,0 +1,293 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+)
+
+const (
+	// advisoryLockKey identifies the PostgreSQL advisory lock of migrations
+	advisoryLockKey int64 = 0x66785f6d6967 // "fx_mig"
+	// lockTTL is how long a MongoDB migration lock is honored since it was
+	// last renewed; older locks are taken over, as their holder crashed
+	// before releasing them
+	lockTTL = 10 * time.Minute
+	// lockRenewInterval is how often a held MongoDB lock is renewed, well
+	// within lockTTL so slow renewals don't let it go stale
+	lockRenewInterval = lockTTL / 5
+	// lockRetryInterval is how often a held lock is tried again
+	lockRetryInterval = time.Second
+)
+
+// migrationLock is held while migrations or seeders run, so replicas
+// starting together don't apply the same migrations
+type migrationLock interface {
+	// tryLock takes the lock if it is free
+	tryLock(ctx context.Context) (bool, error)
+	// unlock releases the lock
+	unlock(ctx context.Context) error
+}
+
+// renewableLock is a migration lock that expires unless it is renewed while
+// held
+type renewableLock interface {
+	migrationLock
+	// renew extends the lock, failing if it was taken over
+	renew(ctx context.Context) error
+}
+
+// errLockLost is returned when renewing a lock another instance took over
+var errLockLost = errors.New("migration lock was taken over by another instance")
+
+// Lock waits until this process holds the migration lock and returns a
+// function releasing it. PostgreSQL uses an advisory lock, SQLite a lock
+// file next to the database and MongoDB a document in the migration_lock
+// collection. The first two are released by the database or the operating
+// system if the process dies; the document is renewed until it is released.
+func (m *Migrator) Lock(ctx context.Context) (func(), error) {
+	lock, err := m.newLock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration lock: %w", err)
+	}
+	return acquireLock(ctx, lock, lockRenewInterval)
+}
+
+// acquireLock waits until lock is taken and returns a function releasing it.
+// A renewable lock is renewed every renewInterval until then.
+func acquireLock(ctx context.Context, lock migrationLock, renewInterval time.Duration) (func(), error) {
+	log := logger.FromContext(ctx)
+	logged := false
+	for {
+		acquired, err := lock.tryLock(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if acquired {
+			stopRenewing := func() {}
+			if renewable, ok := lock.(renewableLock); ok {
+				stopRenewing = renewLock(ctx, renewable, renewInterval)
+			}
+			return func() {
+				stopRenewing()
+				// The caller's context may be done by now
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				if err := lock.unlock(ctx); err != nil {
+					log.Error("failed to release migration lock", zap.Error(err))
+				}
+			}, nil
+		}
+
+		if !logged {
+			log.Info("waiting for another instance to finish migrating")
+			logged = true
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to acquire migration lock: %w", ctx.Err())
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// renewLock renews lock every interval in the background and returns a
+// function stopping it, which waits for a renewal in progress
+func renewLock(ctx context.Context, lock renewableLock, interval time.Duration) func() {
+	log := logger.FromContext(ctx)
+	ctx = context.WithoutCancel(ctx)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				renewCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+				err := lock.renew(renewCtx)
+				cancel()
+				if err != nil {
+					log.Error("failed to renew migration lock", zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// newLock creates the lock suited to the database
+func (m *Migrator) newLock(ctx context.Context) (migrationLock, error) {
+	if m.db.GORM != nil {
+		switch dialector := m.db.GORM.Dialector.(type) {
+		case *sqlite.Dialector:
+			path := sqlitePath(dialector.DSN)
+			if path == "" {
+				// An in-memory database is private to this process
+				return noLock{}, nil
+			}
+			return &fileLock{path: path + ".migrate.lock"}, nil
+		default:
+			if dialector.Name() != "postgres" {
+				return nil, fmt.Errorf("unsupported database: %s", dialector.Name())
+			}
+			sqlDB, err := m.db.GORM.DB()
+			if err != nil {
+				return nil, err
+			}
+			return &advisoryLock{db: sqlDB}, nil
+		}
+	}
+
+	if m.db.Mongo != nil {
+		owner, err := utils.GenerateRandomString(32)
+		if err != nil {
+			return nil, err
+		}
+		return &documentLock{
+			collection: m.db.MongoDB().Collection(m.tablePrefix + "migration_lock"),
+			owner:      owner,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no database connection available")
+}
+
+// sqlitePath returns the file of a SQLite DSN, or "" for in-memory databases
+func sqlitePath(dsn string) string {
+	path, query, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+	if path == "" || path == ":memory:" || strings.Contains(query, "mode=memory") {
+		return ""
+	}
+	return path
+}
+
+// noLock is used where no other process can migrate concurrently
+type noLock struct{}
+
+func (noLock) tryLock(context.Context) (bool, error) { return true, nil }
+func (noLock) unlock(context.Context) error          { return nil }
+
+// advisoryLock holds a PostgreSQL session advisory lock on a dedicated
+// connection
+type advisoryLock struct {
+	db   *sql.DB
+	conn *sql.Conn
+}
+
+func (l *advisoryLock) tryLock(ctx context.Context) (bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", advisoryLockKey).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, err
+	}
+	if !acquired {
+		return false, conn.Close()
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+func (l *advisoryLock) unlock(ctx context.Context) error {
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", advisoryLockKey)
+	if err != nil {
+		// Discard the connection so the session, and its lock, end
+		l.conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	l.conn.Close()
+	return err
+}
+
+// fileLock holds an exclusive lock on a file
+type fileLock struct {
+	path string
+	file *os.File
+}
+
+func (l *fileLock) tryLock(context.Context) (bool, error) {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return false, err
+	}
+
+	acquired, err := lockFile(file)
+	if err != nil || !acquired {
+		file.Close()
+		return false, err
+	}
+
+	l.file = file
+	return true, nil
+}
+
+func (l *fileLock) unlock(context.Context) error {
+	// Closing the file releases the lock
+	return l.file.Close()
+}
+
+// documentLock is a document in a MongoDB collection; a lock not renewed
+// for lockTTL is taken over
+type documentLock struct {
+	collection *mongo.Collection
+	owner      string
+}
+
+func (l *documentLock) tryLock(ctx context.Context) (bool, error) {
+	now := time.Now().UTC()
+	stale := bson.M{"_id": "migrations", "locked_at": bson.M{"$lt": now.Add(-lockTTL)}}
+	if _, err := l.collection.DeleteOne(ctx, stale); err != nil {
+		return false, err
+	}
+
+	_, err := l.collection.InsertOne(ctx, bson.M{"_id": "migrations", "owner": l.owner, "locked_at": now})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (l *documentLock) unlock(ctx context.Context) error {
+	_, err := l.collection.DeleteOne(ctx, bson.M{"_id": "migrations", "owner": l.owner})
+	return err
+}
+
+func (l *documentLock) renew(ctx context.Context) error {
+	result, err := l.collection.UpdateOne(ctx,
+		bson.M{"_id": "migrations", "owner": l.owner},
+		bson.M{"$set": bson.M{"locked_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errLockLost
+	}
+	return nil
+}
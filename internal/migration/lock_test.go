@@ -0,0 +1,111 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newFileConnection opens the SQLite database at path, as another instance would
+func newFileConnection(t *testing.T, path string) *database.Connection {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return &database.Connection{GORM: db}
+}
+
+// TestLockExcludesOtherInstances tests that the lock is held until released
+func TestLockExcludesOtherInstances(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "app.db")
+	first := NewMigrator(newFileConnection(t, path))
+	second := NewMigrator(newFileConnection(t, path))
+
+	unlock, err := first.Lock(ctx)
+	require.NoError(t, err)
+	assert.FileExists(t, path+".migrate.lock")
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = second.Lock(waitCtx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	unlock()
+	unlock, err = second.Lock(ctx)
+	require.NoError(t, err)
+	unlock()
+}
+
+// TestMigrateHoldsLock tests that migrations wait for the lock
+func TestMigrateHoldsLock(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "app.db")
+	holder := NewMigrator(newFileConnection(t, path))
+	migrator := NewMigrator(newFileConnection(t, path))
+	migrator.AddMigration(&testMigration{version: "1", sql: "CREATE TABLE a (id INTEGER)"})
+
+	unlock, err := holder.Lock(ctx)
+	require.NoError(t, err)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, migrator.Migrate(waitCtx))
+
+	unlock()
+	require.NoError(t, migrator.Migrate(ctx))
+}
+
+// countingLock is a renewable lock counting its renewals
+type countingLock struct {
+	renewals atomic.Int32
+	unlocked atomic.Bool
+}
+
+func (l *countingLock) tryLock(context.Context) (bool, error) { return true, nil }
+func (l *countingLock) unlock(context.Context) error {
+	l.unlocked.Store(true)
+	return nil
+}
+func (l *countingLock) renew(context.Context) error {
+	l.renewals.Add(1)
+	return nil
+}
+
+// TestLockRenewsUntilReleased tests that a renewable lock is renewed while
+// held, so long migrations keep it, and no longer once released
+func TestLockRenewsUntilReleased(t *testing.T) {
+	lock := &countingLock{}
+	unlock, err := acquireLock(context.Background(), lock, time.Millisecond)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return lock.renewals.Load() >= 2 }, time.Second, time.Millisecond)
+	unlock()
+	assert.True(t, lock.unlocked.Load())
+
+	renewals := lock.renewals.Load()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, renewals, lock.renewals.Load())
+}
+
+func TestSQLitePath(t *testing.T) {
+	assert.Equal(t, "./data/app.db", sqlitePath("./data/app.db"))
+	assert.Equal(t, "app.db", sqlitePath("file:app.db?_foreign_keys=on"))
+	assert.Equal(t, "", sqlitePath(":memory:"))
+	assert.Equal(t, "", sqlitePath("file:test?mode=memory&cache=shared"))
+}
@@ -2,14 +2,21 @@ package migration
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io/fs"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // Migration represents a single database migration
@@ -27,6 +34,23 @@ type Migration interface {
 	Down(ctx context.Context, db *database.Connection) error
 }
 
+// NonTransactional can be implemented by SQL migrations containing statements
+// that cannot run inside a transaction (e.g. CREATE INDEX CONCURRENTLY)
+type NonTransactional interface {
+	// NoTransaction returns true to run the migration outside a transaction
+	NoTransaction() bool
+}
+
+// Checksummed can be implemented by migrations to have their content
+// verified: the checksum is recorded when the migration is applied, and a
+// different checksum later means the applied migration was edited. Go
+// migrations found in the migrator's sources are checksummed from their
+// file; others can return a version string they change with their code.
+type Checksummed interface {
+	// Checksum returns a hash of the migration's content
+	Checksum() string
+}
+
 // Seeder represents a data seeder
 type Seeder interface {
 	// Name returns the seeder name
@@ -39,22 +63,116 @@ type Seeder interface {
 	ShouldRun(env string) bool
 }
 
+// Repeatable can be implemented by seeders that load data on request, such
+// as fixtures files, to run every time instead of once per database
+type Repeatable interface {
+	// Repeatable returns true to run the seeder even if it already ran
+	Repeatable() bool
+}
+
+// MigrationStatus describes whether a registered migration has been applied
+type MigrationStatus struct {
+	Version     string
+	Description string
+	Applied     bool
+	ExecutedAt  *time.Time
+	// Modified is set when the migration changed since it was applied
+	Modified bool
+	// Unverified is set when changes to the migration can't be detected:
+	// it has no checksum, or was applied before checksums were recorded
+	Unverified bool
+}
+
+// Drift describes how the database differs from the registered migrations
+type Drift struct {
+	// Pending migrations are registered but not applied
+	Pending []string
+	// Modified migrations changed since they were applied
+	Modified []string
+	// Unknown migrations are applied but not registered, e.g. by a newer
+	// release that was rolled back
+	Unknown []string
+}
+
+// InSync reports whether the database matches the registered migrations
+func (d *Drift) InSync() bool {
+	return len(d.Pending) == 0 && len(d.Modified) == 0 && len(d.Unknown) == 0
+}
+
+// String summarizes the drift, e.g. "2 pending migrations: 20240101000000, 20240102000000"
+func (d *Drift) String() string {
+	var parts []string
+	for _, group := range []struct {
+		kind     string
+		versions []string
+	}{
+		{"pending", d.Pending},
+		{"modified", d.Modified},
+		{"unknown", d.Unknown},
+	} {
+		if len(group.versions) > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s migrations: %s", len(group.versions), group.kind, strings.Join(group.versions, ", ")))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// legacyTrackingTable is the tracking table's name from before it was
+// prefixed; it is renamed when migrating
+const legacyTrackingTable = "migrations"
+
+// migrationRecord represents a row/document in the migration tracking table/collection
+type migrationRecord struct {
+	Version    string    `gorm:"column:version" bson:"version"`
+	ExecutedAt time.Time `gorm:"column:executed_at" bson:"executed_at"`
+	Checksum   string    `gorm:"column:checksum" bson:"checksum"`
+}
+
 // Migrator handles migration execution
 type Migrator struct {
 	db         *database.Connection
 	migrations []Migration
 	seeders    []Seeder
+	// force applies pending migrations despite checksum mismatches
+	force bool
+	// sources holds the files of Go migrations, to checksum them
+	sources fs.FS
+	// reseed runs seeders again even if they already ran
+	reseed bool
+	// tablePrefix is prepended to the tracking table and lock collection,
+	// and replaces {{prefix}} in SQL migrations
+	tablePrefix string
 }
 
 // NewMigrator creates a new migrator instance
 func NewMigrator(db *database.Connection) *Migrator {
 	return &Migrator{
-		db:         db,
-		migrations: make([]Migration, 0),
-		seeders:    make([]Seeder, 0),
+		db:          db,
+		migrations:  make([]Migration, 0),
+		seeders:     make([]Seeder, 0),
+		tablePrefix: db.TablePrefix,
 	}
 }
 
+// SetTablePrefix replaces the table prefix of the connection
+func (m *Migrator) SetTablePrefix(prefix string) {
+	m.tablePrefix = prefix
+}
+
+// SetForce makes Migrate only warn about applied migrations whose checksum
+// changed, instead of failing
+func (m *Migrator) SetForce(force bool) {
+	m.force = force
+}
+
+// SetSources sets the files of Go migrations, named <version>_<name>.go.
+// Migrations that don't implement Checksummed are checksummed from their
+// file in sources; only that file is hashed, so changes to helpers shared
+// with other files go unnoticed.
+func (m *Migrator) SetSources(sources fs.FS) {
+	m.sources = sources
+}
+
 // AddMigration adds a migration to the migrator
 func (m *Migrator) AddMigration(migration Migration) {
 	m.migrations = append(m.migrations, migration)
@@ -85,12 +203,102 @@ func (m *Migrator) GetExecutedMigrations(ctx context.Context) (map[string]bool,
 	return m.getExecutedMigrations(ctx)
 }
 
-// Migrate runs all pending migrations
+// Status returns the applied state of every registered migration, ordered by version
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	m.sortMigrations()
+
+	if err := m.ensureMigrationTracking(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create migration tracking: %w", err)
+	}
+
+	records, err := m.getMigrationRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executed migrations: %w", err)
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{
+			Version:     migration.Version(),
+			Description: migration.Description(),
+		}
+		if record, exists := records[migration.Version()]; exists {
+			executedAt := record.ExecutedAt
+			status.Applied = true
+			status.ExecutedAt = &executedAt
+			status.Modified = m.checksumChanged(migration, record)
+			status.Unverified = record.Checksum == "" || m.checksum(migration) == ""
+		} else {
+			status.Unverified = m.checksum(migration) == ""
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Pending returns the versions of registered migrations that have not been
+// applied yet. Unlike Status it does not create the tracking table
+func (m *Migrator) Pending(ctx context.Context) ([]string, error) {
+	m.sortMigrations()
+
+	executed, err := m.getExecutedMigrations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executed migrations: %w", err)
+	}
+
+	var pending []string
+	for _, migration := range m.migrations {
+		if !executed[migration.Version()] {
+			pending = append(pending, migration.Version())
+		}
+	}
+
+	return pending, nil
+}
+
+// Drift compares the registered migrations with the applied ones. Like
+// Pending it does not create the tracking table
+func (m *Migrator) Drift(ctx context.Context) (*Drift, error) {
+	m.sortMigrations()
+
+	records, err := m.getMigrationRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executed migrations: %w", err)
+	}
+
+	drift := &Drift{}
+	registered := make(map[string]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		registered[migration.Version()] = true
+		record, exists := records[migration.Version()]
+		if !exists {
+			drift.Pending = append(drift.Pending, migration.Version())
+		} else if m.checksumChanged(migration, record) {
+			drift.Modified = append(drift.Modified, migration.Version())
+		}
+	}
+	for version := range records {
+		if !registered[version] {
+			drift.Unknown = append(drift.Unknown, version)
+		}
+	}
+	sort.Strings(drift.Unknown)
+
+	return drift, nil
+}
+
+// Migrate runs all pending migrations while holding the migration lock, so
+// only one instance applies them
 func (m *Migrator) Migrate(ctx context.Context) error {
 	// Sort migrations by version
-	sort.Slice(m.migrations, func(i, j int) bool {
-		return m.migrations[i].Version() < m.migrations[j].Version()
-	})
+	m.sortMigrations()
+
+	unlock, err := m.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Create migration tracking table/collection if it doesn't exist
 	if err := m.ensureMigrationTracking(ctx); err != nil {
@@ -98,78 +306,305 @@ func (m *Migrator) Migrate(ctx context.Context) error {
 	}
 
 	// Get already executed migrations
-	executed, err := m.getExecutedMigrations(ctx)
+	executed, err := m.getMigrationRecords(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get executed migrations: %w", err)
 	}
 
+	if err := m.verifyChecksums(ctx, executed); err != nil {
+		return err
+	}
+
 	// Run pending migrations
 	for _, migration := range m.migrations {
 		if _, exists := executed[migration.Version()]; exists {
-			zap.L().Debug("migration already executed", 
+			logger.FromContext(ctx).Debug("migration already executed", 
 				zap.String("version", migration.Version()),
 				zap.String("description", migration.Description()))
 			continue
 		}
 
-		zap.L().Info("running migration", 
+		logger.FromContext(ctx).Info("running migration", 
 			zap.String("version", migration.Version()),
 			zap.String("description", migration.Description()))
 
-		if err := migration.Up(ctx, m.db); err != nil {
-			return fmt.Errorf("migration %s failed: %w", migration.Version(), err)
+		if err := m.applyMigration(ctx, migration); err != nil {
+			return err
 		}
 
-		if err := m.recordMigration(ctx, migration); err != nil {
-			return fmt.Errorf("failed to record migration %s: %w", migration.Version(), err)
-		}
-
-		zap.L().Info("migration completed", 
+		logger.FromContext(ctx).Info("migration completed", 
 			zap.String("version", migration.Version()))
 	}
 
 	return nil
 }
 
-// Seed runs all applicable seeders
+// Seed runs the applicable seeders that have not run yet while holding the
+// migration lock
 func (m *Migrator) Seed(ctx context.Context, env string) error {
+	unlock, err := m.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	executed, err := m.prepareSeedTracking(ctx)
+	if err != nil {
+		return err
+	}
+
 	for _, seeder := range m.seeders {
 		if !seeder.ShouldRun(env) {
-			zap.L().Debug("skipping seeder", 
+			logger.FromContext(ctx).Debug("skipping seeder", 
 				zap.String("name", seeder.Name()),
 				zap.String("env", env))
 			continue
 		}
 
-		zap.L().Info("running seeder", zap.String("name", seeder.Name()))
+		if err := m.runSeeder(ctx, seeder, executed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SeedOnly runs the named seeders in the given order while holding the
+// migration lock. They run regardless of the environment, but like Seed
+// skip those that already ran.
+func (m *Migrator) SeedOnly(ctx context.Context, names ...string) error {
+	byName := make(map[string]Seeder, len(m.seeders))
+	for _, seeder := range m.seeders {
+		byName[seeder.Name()] = seeder
+	}
+
+	selected := make([]Seeder, 0, len(names))
+	for _, name := range names {
+		seeder, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("unknown seeder: %s", name)
+		}
+		selected = append(selected, seeder)
+	}
+
+	unlock, err := m.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	executed, err := m.prepareSeedTracking(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, seeder := range selected {
+		if err := m.runSeeder(ctx, seeder, executed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prepareSeedTracking creates the seed tracking table/collection and
+// returns the seeders that already ran
+func (m *Migrator) prepareSeedTracking(ctx context.Context) (map[string]seedRecord, error) {
+	if err := m.ensureSeedTracking(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create seed tracking: %w", err)
+	}
+	executed, err := m.getSeedRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executed seeders: %w", err)
+	}
+	return executed, nil
+}
+
+// runSeeder runs a single seeder unless it already ran, and records it. A
+// seeder failing after writing some rows isn't recorded and runs again, so
+// seeders still check whether their rows exist.
+func (m *Migrator) runSeeder(ctx context.Context, seeder Seeder, executed map[string]seedRecord) error {
+	repeatable := isRepeatable(seeder)
+	if _, ran := executed[seeder.Name()]; ran && !repeatable && !m.reseed {
+		logger.FromContext(ctx).Debug("seeder already executed",
+			zap.String("name", seeder.Name()))
+		return nil
+	}
+
+	logger.FromContext(ctx).Info("running seeder", zap.String("name", seeder.Name()))
 
-		if err := seeder.Run(ctx, m.db); err != nil {
-			return fmt.Errorf("seeder %s failed: %w", seeder.Name(), err)
+	if err := seeder.Run(ctx, m.db); err != nil {
+		return fmt.Errorf("seeder %s failed: %w", seeder.Name(), err)
+	}
+
+	if !repeatable {
+		if err := m.recordSeed(ctx, seeder); err != nil {
+			return fmt.Errorf("failed to record seeder %s: %w", seeder.Name(), err)
 		}
+	}
+
+	logger.FromContext(ctx).Info("seeder completed", zap.String("name", seeder.Name()))
+	return nil
+}
 
-		zap.L().Info("seeder completed", zap.String("name", seeder.Name()))
+// verifyChecksums fails if applied migrations changed since they were
+// applied, or only logs them when forced
+func (m *Migrator) verifyChecksums(ctx context.Context, records map[string]migrationRecord) error {
+	var modified []string
+	for _, migration := range m.migrations {
+		record, exists := records[migration.Version()]
+		if !exists || !m.checksumChanged(migration, record) {
+			continue
+		}
+		if m.force {
+			logger.FromContext(ctx).Warn("applied migration was modified",
+				zap.String("version", migration.Version()),
+				zap.String("description", migration.Description()))
+			continue
+		}
+		modified = append(modified, migration.Version())
 	}
 
+	if len(modified) > 0 {
+		return fmt.Errorf("applied migrations were modified since they ran: %s", strings.Join(modified, ", "))
+	}
 	return nil
 }
 
+// checksumChanged reports whether a migration's checksum differs from the
+// one recorded when it was applied. Migrations without a checksum, and those
+// applied before checksums were recorded, aren't verified.
+func (m *Migrator) checksumChanged(migration Migration, record migrationRecord) bool {
+	checksum := m.checksum(migration)
+	return checksum != "" && record.Checksum != "" && checksum != record.Checksum
+}
+
+// checksum returns the checksum of a migration, if it has one: its own, or
+// the hash of its file in the sources
+func (m *Migrator) checksum(migration Migration) string {
+	if c, ok := migration.(Checksummed); ok {
+		return c.Checksum()
+	}
+	if m.sources == nil {
+		return ""
+	}
+
+	matches, _ := fs.Glob(m.sources, migration.Version()+"_*.go")
+	var files []string
+	for _, file := range matches {
+		if !strings.HasSuffix(file, "_test.go") {
+			files = append(files, file)
+		}
+	}
+	// Several files with the version can't be told apart
+	if len(files) != 1 {
+		return ""
+	}
+	source, err := fs.ReadFile(m.sources, files[0])
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(source)
+	return hex.EncodeToString(sum[:])
+}
+
+// sortMigrations sorts migrations by version
+func (m *Migrator) sortMigrations() {
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version() < m.migrations[j].Version()
+	})
+}
+
+// trackingTable returns the name of the migration tracking table/collection
+func (m *Migrator) trackingTable() string {
+	return m.tablePrefix + legacyTrackingTable
+}
+
+// hasTable reports whether a table or collection exists
+func (m *Migrator) hasTable(ctx context.Context, name string) (bool, error) {
+	if m.db.GORM != nil {
+		return m.db.GORM.WithContext(ctx).Migrator().HasTable(name), nil
+	}
+
+	names, err := m.db.MongoDB().ListCollectionNames(ctx, bson.M{"name": name})
+	return len(names) > 0, err
+}
+
+// readTrackingTable returns the tracking table to read from: databases not
+// migrated since the table was prefixed still have the legacy table
+func (m *Migrator) readTrackingTable(ctx context.Context) (string, error) {
+	table := m.trackingTable()
+	if table == legacyTrackingTable {
+		return table, nil
+	}
+
+	exists, err := m.hasTable(ctx, table)
+	if err != nil || exists {
+		return table, err
+	}
+	legacy, err := m.hasTable(ctx, legacyTrackingTable)
+	if err != nil || !legacy {
+		return table, err
+	}
+	return legacyTrackingTable, nil
+}
+
+// renameLegacyTrackingTable gives the legacy tracking table the prefixed name
+func (m *Migrator) renameLegacyTrackingTable(ctx context.Context) error {
+	table, err := m.readTrackingTable(ctx)
+	if err != nil || table != legacyTrackingTable || table == m.trackingTable() {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("renaming migration tracking table",
+		zap.String("from", legacyTrackingTable),
+		zap.String("to", m.trackingTable()))
+
+	if m.db.GORM != nil {
+		return m.db.GORM.WithContext(ctx).Migrator().RenameTable(legacyTrackingTable, m.trackingTable())
+	}
+
+	database := m.db.MongoDB().Name()
+	return m.db.Mongo.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "renameCollection", Value: database + "." + legacyTrackingTable},
+		{Key: "to", Value: database + "." + m.trackingTable()},
+	}).Err()
+}
+
 // ensureMigrationTracking creates the migration tracking table/collection
 func (m *Migrator) ensureMigrationTracking(ctx context.Context) error {
+	if m.db.GORM == nil && m.db.Mongo == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	if err := m.renameLegacyTrackingTable(ctx); err != nil {
+		return fmt.Errorf("failed to rename %s: %w", legacyTrackingTable, err)
+	}
+
 	if m.db.GORM != nil {
 		// SQL databases - create migrations table
-		return m.db.GORM.Exec(`
-			CREATE TABLE IF NOT EXISTS migrations (
+		if err := m.db.GORM.Exec(fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
 				version VARCHAR(255) PRIMARY KEY,
 				description TEXT,
-				executed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+				executed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				checksum VARCHAR(64)
 			)
-		`).Error
+		`, m.trackingTable())).Error; err != nil {
+			return err
+		}
+
+		// Tables created before checksums were recorded
+		if !m.db.GORM.Migrator().HasColumn(m.trackingTable(), "checksum") {
+			return m.db.GORM.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN checksum VARCHAR(64)", m.trackingTable())).Error
+		}
+		return nil
 	}
 
 	if m.db.Mongo != nil {
 		// MongoDB - ensure migrations collection exists (it will be created automatically)
 		// We can optionally create indexes here
-		collection := m.db.Mongo.Database("fx_gin_scaffold").Collection("migrations")
+		collection := m.db.MongoDB().Collection(m.trackingTable())
 		indexModel := mongo.IndexModel{
 			Keys: map[string]interface{}{"version": 1},
 			Options: options.Index().
@@ -186,11 +621,19 @@ func (m *Migrator) ensureMigrationTracking(ctx context.Context) error {
 // getExecutedMigrations returns a map of executed migration versions
 func (m *Migrator) getExecutedMigrations(ctx context.Context) (map[string]bool, error) {
 	executed := make(map[string]bool)
+	if m.db.GORM == nil && m.db.Mongo == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	table, err := m.readTrackingTable(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	if m.db.GORM != nil {
 		// SQL databases
 		var versions []string
-		if err := m.db.GORM.Raw("SELECT version FROM migrations").Scan(&versions).Error; err != nil {
+		if err := m.db.GORM.WithContext(ctx).Raw(fmt.Sprintf("SELECT version FROM %s", table)).Scan(&versions).Error; err != nil {
 			return nil, err
 		}
 		for _, version := range versions {
@@ -201,7 +644,7 @@ func (m *Migrator) getExecutedMigrations(ctx context.Context) (map[string]bool,
 
 	if m.db.Mongo != nil {
 		// MongoDB
-		collection := m.db.Mongo.Database("fx_gin_scaffold").Collection("migrations")
+		collection := m.db.MongoDB().Collection(table)
 		cursor, err := collection.Find(ctx, map[string]interface{}{})
 		if err != nil {
 			return nil, err
@@ -223,24 +666,126 @@ func (m *Migrator) getExecutedMigrations(ctx context.Context) (map[string]bool,
 	return nil, fmt.Errorf("no database connection available")
 }
 
-// recordMigration records a completed migration
-func (m *Migrator) recordMigration(ctx context.Context, migration Migration) error {
+// getMigrationRecords returns executed migration records keyed by version
+func (m *Migrator) getMigrationRecords(ctx context.Context) (map[string]migrationRecord, error) {
+	records := make(map[string]migrationRecord)
+	if m.db.GORM == nil && m.db.Mongo == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	table, err := m.readTrackingTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	if m.db.GORM != nil {
 		// SQL databases
-		return m.db.GORM.Exec(
-			"INSERT INTO migrations (version, description) VALUES (?, ?)",
+		query := fmt.Sprintf("SELECT version, executed_at, COALESCE(checksum, '') AS checksum FROM %s", table)
+		if !m.db.GORM.Migrator().HasColumn(table, "checksum") {
+			// Not migrated since checksums were recorded
+			query = fmt.Sprintf("SELECT version, executed_at FROM %s", table)
+		}
+
+		var rows []migrationRecord
+		if err := m.db.GORM.WithContext(ctx).Raw(query).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			records[row.Version] = row
+		}
+		return records, nil
+	}
+
+	if m.db.Mongo != nil {
+		// MongoDB
+		collection := m.db.MongoDB().Collection(table)
+		cursor, err := collection.Find(ctx, map[string]interface{}{})
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(ctx)
+
+		var rows []migrationRecord
+		if err := cursor.All(ctx, &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			records[row.Version] = row
+		}
+		return records, nil
+	}
+
+	return nil, fmt.Errorf("no database connection available")
+}
+
+// applyMigration runs a migration and records it as executed. SQL migrations run
+// in the same transaction as their tracking row unless they opt out via NoTransaction.
+func (m *Migrator) applyMigration(ctx context.Context, migration Migration) error {
+	if m.db.GORM != nil && runsInTransaction(migration) {
+		return m.db.GORM.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			txConn := m.connection(tx)
+
+			if err := migration.Up(ctx, txConn); err != nil {
+				return fmt.Errorf("migration %s failed: %w", migration.Version(), err)
+			}
+
+			if err := m.recordMigration(ctx, txConn, migration); err != nil {
+				return fmt.Errorf("failed to record migration %s: %w", migration.Version(), err)
+			}
+
+			return nil
+		})
+	}
+
+	conn := m.connection(m.db.GORM)
+	if err := migration.Up(ctx, conn); err != nil {
+		return fmt.Errorf("migration %s failed: %w", migration.Version(), err)
+	}
+
+	if err := m.recordMigration(ctx, conn, migration); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", migration.Version(), err)
+	}
+
+	return nil
+}
+
+// connection returns the connection migrations run with: the migrator's
+// connection on gormDB, with the migrator's table prefix
+func (m *Migrator) connection(gormDB *gorm.DB) *database.Connection {
+	conn := *m.db
+	conn.GORM = gormDB
+	conn.TablePrefix = m.tablePrefix
+	return &conn
+}
+
+// runsInTransaction reports whether a migration should be wrapped in a transaction
+func runsInTransaction(migration Migration) bool {
+	if nt, ok := migration.(NonTransactional); ok {
+		return !nt.NoTransaction()
+	}
+	return true
+}
+
+// recordMigration records a completed migration
+func (m *Migrator) recordMigration(ctx context.Context, db *database.Connection, migration Migration) error {
+	if db.GORM != nil {
+		// SQL databases
+		return db.GORM.Exec(
+			fmt.Sprintf("INSERT INTO %s (version, description, checksum) VALUES (?, ?, ?)", m.trackingTable()),
 			migration.Version(),
 			migration.Description(),
+			m.checksum(migration),
 		).Error
 	}
 
-	if m.db.Mongo != nil {
+	if db.Mongo != nil {
 		// MongoDB
-		collection := m.db.Mongo.Database("fx_gin_scaffold").Collection("migrations")
+		collection := db.MongoDB().Collection(m.trackingTable())
 		_, err := collection.InsertOne(ctx, map[string]interface{}{
 			"version":     migration.Version(),
 			"description": migration.Description(),
 			"executed_at": time.Now(),
+			"checksum":    m.checksum(migration),
 		})
 		return err
 	}
@@ -0,0 +1,326 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testMigration is a configurable migration used in tests
+type testMigration struct {
+	version  string
+	sql      string
+	err      error
+	noTx     bool
+	checksum string
+}
+
+func (m *testMigration) Version() string     { return m.version }
+func (m *testMigration) Description() string { return "test migration " + m.version }
+func (m *testMigration) NoTransaction() bool { return m.noTx }
+func (m *testMigration) Checksum() string    { return m.checksum }
+
+func (m *testMigration) Up(ctx context.Context, db *database.Connection) error {
+	if err := db.GORM.Exec(m.sql).Error; err != nil {
+		return err
+	}
+	return m.err
+}
+
+func (m *testMigration) Down(ctx context.Context, db *database.Connection) error {
+	return nil
+}
+
+// newTestConnection creates an in-memory SQLite connection limited to a single
+// underlying connection so every statement sees the same database
+func newTestConnection(t *testing.T) *database.Connection {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return &database.Connection{GORM: db}
+}
+
+// TestMigrateRecordsMigrations tests that applied migrations are tracked
+func TestMigrateRecordsMigrations(t *testing.T) {
+	ctx := context.Background()
+	migrator := NewMigrator(newTestConnection(t))
+	migrator.AddMigration(&testMigration{version: "1", sql: "CREATE TABLE a (id INTEGER)"})
+
+	require.NoError(t, migrator.Migrate(ctx))
+
+	executed, err := migrator.GetExecutedMigrations(ctx)
+	require.NoError(t, err)
+	assert.True(t, executed["1"])
+}
+
+// TestMigrateRollsBackFailedMigration tests that a failing migration leaves no trace
+func TestMigrateRollsBackFailedMigration(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConnection(t)
+	migrator := NewMigrator(conn)
+	migrator.AddMigration(&testMigration{version: "1", sql: "CREATE TABLE a (id INTEGER)", err: errors.New("boom")})
+
+	assert.Error(t, migrator.Migrate(ctx))
+
+	executed, err := migrator.GetExecutedMigrations(ctx)
+	require.NoError(t, err)
+	assert.False(t, executed["1"])
+	assert.False(t, conn.GORM.Migrator().HasTable("a"))
+}
+
+// TestMigrateNoTransaction tests that NoTransaction migrations are not rolled back
+func TestMigrateNoTransaction(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConnection(t)
+	migrator := NewMigrator(conn)
+	migrator.AddMigration(&testMigration{version: "1", sql: "CREATE TABLE a (id INTEGER)", err: errors.New("boom"), noTx: true})
+
+	assert.Error(t, migrator.Migrate(ctx))
+
+	executed, err := migrator.GetExecutedMigrations(ctx)
+	require.NoError(t, err)
+	assert.False(t, executed["1"])
+	assert.True(t, conn.GORM.Migrator().HasTable("a"))
+}
+
+// TestMigrateVerifiesChecksums tests that modified applied migrations stop
+// migrating unless forced
+func TestMigrateVerifiesChecksums(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConnection(t)
+	applied := &testMigration{version: "1", sql: "CREATE TABLE a (id INTEGER)", checksum: "v1"}
+	migrator := NewMigrator(conn)
+	migrator.AddMigration(applied)
+	require.NoError(t, migrator.Migrate(ctx))
+
+	applied.checksum = "v2"
+	migrator.AddMigration(&testMigration{version: "2", sql: "CREATE TABLE b (id INTEGER)"})
+	assert.ErrorContains(t, migrator.Migrate(ctx), "modified")
+	assert.False(t, conn.GORM.Migrator().HasTable("b"))
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Modified)
+	assert.False(t, statuses[1].Modified)
+
+	migrator.SetForce(true)
+	require.NoError(t, migrator.Migrate(ctx))
+	assert.True(t, conn.GORM.Migrator().HasTable("b"))
+}
+
+// goMigration is a migration without a checksum of its own, like the Go migrations
+type goMigration struct {
+	version string
+}
+
+func (m *goMigration) Version() string     { return m.version }
+func (m *goMigration) Description() string { return "go migration " + m.version }
+
+func (m *goMigration) Up(ctx context.Context, db *database.Connection) error {
+	return nil
+}
+
+func (m *goMigration) Down(ctx context.Context, db *database.Connection) error {
+	return nil
+}
+
+// TestMigrateChecksumsSources tests that migrations without a checksum are
+// verified with their source file, and reported as unverified without one
+func TestMigrateChecksumsSources(t *testing.T) {
+	ctx := context.Background()
+	sources := fstest.MapFS{
+		"1_create_a.go":      {Data: []byte("package migrations // v1")},
+		"1_create_a_test.go": {Data: []byte("package migrations")},
+	}
+	migrator := NewMigrator(newTestConnection(t))
+	migrator.SetSources(sources)
+	migrator.AddMigration(&goMigration{version: "1"})
+	migrator.AddMigration(&goMigration{version: "2"})
+	require.NoError(t, migrator.Migrate(ctx))
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.False(t, statuses[0].Unverified)
+	assert.True(t, statuses[1].Unverified)
+
+	sources["1_create_a.go"].Data = []byte("package migrations // v2")
+	assert.ErrorContains(t, migrator.Migrate(ctx), "modified since they ran: 1")
+	statuses, err = migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Modified)
+	assert.False(t, statuses[1].Modified)
+}
+
+// TestMigrateAddsChecksumColumn tests that tracking tables created before
+// checksums were recorded get the column, and that their rows aren't verified
+func TestMigrateAddsChecksumColumn(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConnection(t)
+	require.NoError(t, conn.GORM.Exec("CREATE TABLE migrations (version VARCHAR(255) PRIMARY KEY, description TEXT, executed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)").Error)
+	require.NoError(t, conn.GORM.Exec("INSERT INTO migrations (version, description) VALUES ('1', 'old')").Error)
+
+	migrator := NewMigrator(conn)
+	migrator.AddMigration(&testMigration{version: "1", sql: "CREATE TABLE a (id INTEGER)", checksum: "v1"})
+	require.NoError(t, migrator.Migrate(ctx))
+	assert.True(t, conn.GORM.Migrator().HasColumn("migrations", "checksum"))
+	assert.False(t, conn.GORM.Migrator().HasTable("a"))
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Unverified)
+}
+
+// TestMigratePrefixesTrackingTable tests that the tracking table has the
+// table prefix, and that the prefix can be overridden
+func TestMigratePrefixesTrackingTable(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConnection(t)
+	conn.TablePrefix = "app_"
+
+	migrator := NewMigrator(conn)
+	migrator.AddMigration(&testMigration{version: "1", sql: "CREATE TABLE a (id INTEGER)"})
+	require.NoError(t, migrator.Migrate(ctx))
+	assert.True(t, conn.GORM.Migrator().HasTable("app_migrations"))
+	assert.False(t, conn.GORM.Migrator().HasTable("migrations"))
+
+	migrator = NewMigrator(conn)
+	migrator.SetTablePrefix("other_")
+	migrator.AddMigration(&testMigration{version: "1", sql: "CREATE TABLE b (id INTEGER)"})
+	require.NoError(t, migrator.Migrate(ctx))
+	assert.True(t, conn.GORM.Migrator().HasTable("other_migrations"))
+	assert.True(t, conn.GORM.Migrator().HasTable("b"))
+}
+
+// TestMigrateRenamesLegacyTrackingTable tests that the tracking table from
+// before it was prefixed is read, and renamed when migrating
+func TestMigrateRenamesLegacyTrackingTable(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConnection(t)
+	conn.TablePrefix = "app_"
+	require.NoError(t, NewMigrator(&database.Connection{GORM: conn.GORM}).ensureMigrationTracking(ctx))
+	require.NoError(t, conn.GORM.Exec("INSERT INTO migrations (version, description) VALUES ('1', 'old')").Error)
+
+	migrator := NewMigrator(conn)
+	migrator.AddMigration(&testMigration{version: "1", sql: "CREATE TABLE a (id INTEGER)"})
+	executed, err := migrator.GetExecutedMigrations(ctx)
+	require.NoError(t, err)
+	assert.True(t, executed["1"])
+
+	require.NoError(t, migrator.Migrate(ctx))
+	assert.True(t, conn.GORM.Migrator().HasTable("app_migrations"))
+	assert.False(t, conn.GORM.Migrator().HasTable("migrations"))
+	assert.False(t, conn.GORM.Migrator().HasTable("a"))
+}
+
+// testSeeder records that it ran
+type testSeeder struct {
+	name       string
+	env        string
+	ran        bool
+	runs       int
+	repeatable bool
+}
+
+func (s *testSeeder) Name() string              { return s.name }
+func (s *testSeeder) ShouldRun(env string) bool { return env == s.env }
+func (s *testSeeder) Repeatable() bool          { return s.repeatable }
+
+func (s *testSeeder) Run(ctx context.Context, db *database.Connection) error {
+	s.ran = true
+	s.runs++
+	return nil
+}
+
+// TestSeedOnly tests that only the named seeders run, whatever their environment
+func TestSeedOnly(t *testing.T) {
+	ctx := context.Background()
+	migrator := NewMigrator(newTestConnection(t))
+	a := &testSeeder{name: "a", env: "development"}
+	b := &testSeeder{name: "b", env: "development"}
+	migrator.AddSeeder(a)
+	migrator.AddSeeder(b)
+
+	require.NoError(t, migrator.SeedOnly(ctx, "b"))
+	assert.False(t, a.ran)
+	assert.True(t, b.ran)
+
+	assert.ErrorContains(t, migrator.SeedOnly(ctx, "c"), "unknown seeder")
+}
+
+// TestSeedRunsOnce tests that seeders that ran are skipped unless reseeding
+// or repeatable
+func TestSeedRunsOnce(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConnection(t)
+	conn.TablePrefix = "app_"
+	once := &testSeeder{name: "once", env: "development"}
+	repeatable := &testSeeder{name: "repeatable", env: "development", repeatable: true}
+	other := &testSeeder{name: "other", env: "production"}
+
+	migrator := NewMigrator(conn)
+	migrator.AddSeeder(once)
+	migrator.AddSeeder(repeatable)
+	migrator.AddSeeder(other)
+
+	statuses, err := migrator.SeedStatus(ctx)
+	require.NoError(t, err)
+	assert.False(t, statuses[0].Executed)
+
+	require.NoError(t, migrator.Seed(ctx, "development"))
+	require.NoError(t, migrator.Seed(ctx, "development"))
+	require.NoError(t, migrator.SeedOnly(ctx, "once"))
+	assert.Equal(t, 1, once.runs)
+	assert.Equal(t, 2, repeatable.runs)
+	assert.Zero(t, other.runs)
+	assert.True(t, conn.GORM.Migrator().HasTable("app_seeds"))
+
+	statuses, err = migrator.SeedStatus(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Executed)
+	assert.NotNil(t, statuses[0].ExecutedAt)
+	assert.False(t, statuses[1].Executed, "repeatable seeders are not recorded")
+	assert.False(t, statuses[2].Executed)
+
+	migrator.SetReseed(true)
+	require.NoError(t, migrator.Seed(ctx, "development"))
+	assert.Equal(t, 2, once.runs)
+}
+
+// TestDrift tests that pending, modified and unknown migrations are reported
+func TestDrift(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConnection(t)
+	applied := &testMigration{version: "1", sql: "CREATE TABLE a (id INTEGER)", checksum: "v1"}
+	migrator := NewMigrator(conn)
+	migrator.AddMigration(applied)
+	migrator.AddMigration(&testMigration{version: "2", sql: "CREATE TABLE b (id INTEGER)"})
+	require.NoError(t, migrator.Migrate(ctx))
+
+	drift, err := migrator.Drift(ctx)
+	require.NoError(t, err)
+	assert.True(t, drift.InSync())
+
+	applied.checksum = "v2"
+	current := NewMigrator(conn)
+	current.AddMigration(applied)
+	current.AddMigration(&testMigration{version: "3", sql: "CREATE TABLE c (id INTEGER)"})
+	drift, err = current.Drift(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &Drift{Pending: []string{"3"}, Modified: []string{"1"}, Unknown: []string{"2"}}, drift)
+	assert.Equal(t, "1 pending migrations: 3; 1 modified migrations: 1; 1 unknown migrations: 2", drift.String())
+}
@@ -28,8 +28,7 @@ func (m *CreateUsersTable) Up(ctx context.Context, db *database.Connection) erro
 
 	if db.Mongo != nil {
 		// MongoDB - create collection and indexes
-		dbName := "fx_gin_scaffold" // TODO: Get from config
-		collection := db.Mongo.Database(dbName).Collection("fx_users")
+		collection := db.MongoDB().Collection(domain.TableName(domain.User{}))
 
 		// Create indexes for MongoDB
 		indexes := []mongo.IndexModel{
@@ -84,8 +83,7 @@ func (m *CreateUsersTable) Down(ctx context.Context, db *database.Connection) er
 
 	if db.Mongo != nil {
 		// MongoDB - drop collection
-		dbName := "fx_gin_scaffold" // TODO: Get from config
-		collection := db.Mongo.Database(dbName).Collection("fx_users")
+		collection := db.MongoDB().Collection(domain.TableName(domain.User{}))
 		return collection.Drop(ctx)
 	}
 
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateRefreshTokensTable creates the refresh_tokens table/collection
+type CreateRefreshTokensTable struct{}
+
+func (m *CreateRefreshTokensTable) Version() string {
+	return "20240820120000"
+}
+
+func (m *CreateRefreshTokensTable) Description() string {
+	return "Create refresh_tokens table/collection"
+}
+
+func (m *CreateRefreshTokensTable) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - use GORM AutoMigrate
+		return db.GORM.AutoMigrate(&domain.RefreshToken{})
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - create collection and indexes
+		collection := db.MongoDB().Collection(domain.TableName(domain.RefreshToken{}))
+
+		indexes := []mongo.IndexModel{
+			{
+				Keys: map[string]interface{}{"token_hash": 1},
+				Options: options.Index().
+					SetUnique(true).
+					SetName("idx_refresh_tokens_token_hash"),
+			},
+			{
+				Keys: map[string]interface{}{"user_id": 1},
+				Options: options.Index().
+					SetName("idx_refresh_tokens_user_id"),
+			},
+			{
+				Keys: map[string]interface{}{"expires_at": 1},
+				Options: options.Index().
+					SetName("idx_refresh_tokens_expires_at"),
+			},
+		}
+
+		_, err := collection.Indexes().CreateMany(ctx, indexes)
+		return err
+	}
+
+	return nil
+}
+
+func (m *CreateRefreshTokensTable) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - drop table
+		return db.GORM.Migrator().DropTable(&domain.RefreshToken{})
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - drop collection
+		collection := db.MongoDB().Collection(domain.TableName(domain.RefreshToken{}))
+		return collection.Drop(ctx)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateRBACTables creates the roles and permissions tables/collections
+// and populates them with the built-in roles and permissions
+type CreateRBACTables struct{}
+
+func (m *CreateRBACTables) Version() string {
+	return "20240825120000"
+}
+
+func (m *CreateRBACTables) Description() string {
+	return "Create roles and permissions tables/collections"
+}
+
+// defaultPermissions are the permissions the application checks for
+var defaultPermissions = []domain.Permission{
+	{Name: domain.PermissionUsersRead, Description: "List and view users"},
+	{Name: domain.PermissionUsersWrite, Description: "Update and delete users"},
+	{Name: domain.PermissionRolesManage, Description: "Manage roles and their permissions"},
+}
+
+// defaultRoles are the roles every installation starts with
+var defaultRoles = []domain.Role{
+	{Name: domain.RoleAdmin, Description: "Full access", Permissions: []string{domain.PermissionAll}},
+	{Name: domain.RoleUser, Description: "Regular user", Permissions: []string{}},
+}
+
+func (m *CreateRBACTables) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - use GORM AutoMigrate
+		if err := db.GORM.AutoMigrate(&domain.Permission{}, &domain.Role{}); err != nil {
+			return err
+		}
+
+		permissions := append([]domain.Permission(nil), defaultPermissions...)
+		if err := db.GORM.WithContext(ctx).Create(&permissions).Error; err != nil {
+			return err
+		}
+
+		roles := append([]domain.Role(nil), defaultRoles...)
+		return db.GORM.WithContext(ctx).Create(&roles).Error
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - create collections, indexes and default documents
+		mongoDB := db.MongoDB()
+		now := time.Now()
+
+		permissions := mongoDB.Collection(domain.TableName(domain.Permission{}))
+		if _, err := permissions.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    map[string]interface{}{"name": 1},
+			Options: options.Index().SetUnique(true).SetName("idx_permissions_name"),
+		}); err != nil {
+			return err
+		}
+
+		permissionDocs := make([]interface{}, 0, len(defaultPermissions))
+		for _, permission := range defaultPermissions {
+			permission.CreatedAt = now
+			permissionDocs = append(permissionDocs, permission)
+		}
+		if _, err := permissions.InsertMany(ctx, permissionDocs); err != nil {
+			return err
+		}
+
+		roles := mongoDB.Collection(domain.TableName(domain.Role{}))
+		if _, err := roles.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    map[string]interface{}{"name": 1},
+			Options: options.Index().SetUnique(true).SetName("idx_roles_name"),
+		}); err != nil {
+			return err
+		}
+
+		roleDocs := make([]interface{}, 0, len(defaultRoles))
+		for _, role := range defaultRoles {
+			role.CreatedAt = now
+			role.UpdatedAt = now
+			roleDocs = append(roleDocs, role)
+		}
+		_, err := roles.InsertMany(ctx, roleDocs)
+		return err
+	}
+
+	return nil
+}
+
+func (m *CreateRBACTables) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - drop tables
+		return db.GORM.Migrator().DropTable(&domain.Role{}, &domain.Permission{})
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - drop collections
+		mongoDB := db.MongoDB()
+		if err := mongoDB.Collection(domain.TableName(domain.Role{})).Drop(ctx); err != nil {
+			return err
+		}
+		return mongoDB.Collection(domain.TableName(domain.Permission{})).Drop(ctx)
+	}
+
+	return nil
+}
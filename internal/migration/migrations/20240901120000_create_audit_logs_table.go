@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateAuditLogsTable creates the audit_logs table/collection and
+// registers the permission guarding it
+type CreateAuditLogsTable struct{}
+
+func (m *CreateAuditLogsTable) Version() string {
+	return "20240901120000"
+}
+
+func (m *CreateAuditLogsTable) Description() string {
+	return "Create audit_logs table/collection"
+}
+
+// auditReadPermission is the permission required to read audit logs
+var auditReadPermission = domain.Permission{
+	Name:        domain.PermissionAuditRead,
+	Description: "View the audit log",
+}
+
+func (m *CreateAuditLogsTable) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - use GORM AutoMigrate
+		if err := db.GORM.AutoMigrate(&domain.AuditLog{}); err != nil {
+			return err
+		}
+
+		permission := auditReadPermission
+		return db.GORM.WithContext(ctx).Create(&permission).Error
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - create collection and indexes
+		mongoDB := db.MongoDB()
+		collection := mongoDB.Collection(domain.TableName(domain.AuditLog{}))
+
+		indexes := []mongo.IndexModel{
+			{
+				Keys:    map[string]interface{}{"actor_id": 1},
+				Options: options.Index().SetName("idx_audit_logs_actor_id"),
+			},
+			{
+				Keys:    map[string]interface{}{"action": 1},
+				Options: options.Index().SetName("idx_audit_logs_action"),
+			},
+			{
+				Keys:    map[string]interface{}{"created_at": -1},
+				Options: options.Index().SetName("idx_audit_logs_created_at"),
+			},
+		}
+
+		if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+			return err
+		}
+
+		permission := auditReadPermission
+		permission.CreatedAt = time.Now()
+		_, err := mongoDB.Collection(domain.TableName(domain.Permission{})).InsertOne(ctx, permission)
+		return err
+	}
+
+	return nil
+}
+
+func (m *CreateAuditLogsTable) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - drop table and permission
+		if err := db.GORM.WithContext(ctx).Where("name = ?", domain.PermissionAuditRead).Delete(&domain.Permission{}).Error; err != nil {
+			return err
+		}
+		return db.GORM.Migrator().DropTable(&domain.AuditLog{})
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - drop collection and permission
+		mongoDB := db.MongoDB()
+		if _, err := mongoDB.Collection(domain.TableName(domain.Permission{})).DeleteOne(ctx, bson.M{"name": domain.PermissionAuditRead}); err != nil {
+			return err
+		}
+		return mongoDB.Collection(domain.TableName(domain.AuditLog{})).Drop(ctx)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AddFilesReadPermission registers the permission for reading other users'
+// private files
+type AddFilesReadPermission struct{}
+
+func (m *AddFilesReadPermission) Version() string {
+	return "20240905120000"
+}
+
+func (m *AddFilesReadPermission) Description() string {
+	return "Add files:read permission"
+}
+
+// filesReadPermission is the permission required to read other users' files
+var filesReadPermission = domain.Permission{
+	Name:        domain.PermissionFilesRead,
+	Description: "Download any user's private files",
+}
+
+func (m *AddFilesReadPermission) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		permission := filesReadPermission
+		return db.GORM.WithContext(ctx).Create(&permission).Error
+	}
+
+	if db.Mongo != nil {
+		permission := filesReadPermission
+		permission.CreatedAt = time.Now()
+		_, err := db.MongoDB().Collection(domain.TableName(domain.Permission{})).InsertOne(ctx, permission)
+		return err
+	}
+
+	return nil
+}
+
+func (m *AddFilesReadPermission) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		return db.GORM.WithContext(ctx).Where("name = ?", domain.PermissionFilesRead).Delete(&domain.Permission{}).Error
+	}
+
+	if db.Mongo != nil {
+		_, err := db.MongoDB().Collection(domain.TableName(domain.Permission{})).DeleteOne(ctx, bson.M{"name": domain.PermissionFilesRead})
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+)
+
+// AddPendingEmailToUsers adds the pending_email column used by email changes
+type AddPendingEmailToUsers struct{}
+
+func (m *AddPendingEmailToUsers) Version() string {
+	return "20240910120000"
+}
+
+func (m *AddPendingEmailToUsers) Description() string {
+	return "Add pending_email column to users table"
+}
+
+func (m *AddPendingEmailToUsers) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		migrator := db.GORM.WithContext(ctx).Migrator()
+		if migrator.HasColumn(&domain.User{}, "PendingEmail") {
+			return nil
+		}
+		return migrator.AddColumn(&domain.User{}, "PendingEmail")
+	}
+
+	// MongoDB documents pick up the field on their first email change
+	return nil
+}
+
+func (m *AddPendingEmailToUsers) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		migrator := db.GORM.WithContext(ctx).Migrator()
+		if !migrator.HasColumn(&domain.User{}, "PendingEmail") {
+			return nil
+		}
+		return migrator.DropColumn(&domain.User{}, "PendingEmail")
+	}
+
+	return nil
+}
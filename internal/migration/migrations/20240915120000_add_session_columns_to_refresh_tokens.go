@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+)
+
+// AddSessionColumnsToRefreshTokens adds the session and device columns used
+// to list and revoke sessions
+type AddSessionColumnsToRefreshTokens struct{}
+
+func (m *AddSessionColumnsToRefreshTokens) Version() string {
+	return "20240915120000"
+}
+
+func (m *AddSessionColumnsToRefreshTokens) Description() string {
+	return "Add session_id, user_agent and ip columns to refresh_tokens table"
+}
+
+// sessionColumns are the refresh token fields added by this migration
+var sessionColumns = []string{"SessionID", "UserAgent", "IP"}
+
+func (m *AddSessionColumnsToRefreshTokens) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		migrator := db.GORM.WithContext(ctx).Migrator()
+		for _, column := range sessionColumns {
+			if migrator.HasColumn(&domain.RefreshToken{}, column) {
+				continue
+			}
+			if err := migrator.AddColumn(&domain.RefreshToken{}, column); err != nil {
+				return err
+			}
+		}
+		if !migrator.HasIndex(&domain.RefreshToken{}, "idx_refresh_tokens_session_id") {
+			return migrator.CreateIndex(&domain.RefreshToken{}, "idx_refresh_tokens_session_id")
+		}
+		return nil
+	}
+
+	// MongoDB documents pick up the fields as tokens are issued
+	return nil
+}
+
+func (m *AddSessionColumnsToRefreshTokens) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		migrator := db.GORM.WithContext(ctx).Migrator()
+		if migrator.HasIndex(&domain.RefreshToken{}, "idx_refresh_tokens_session_id") {
+			if err := migrator.DropIndex(&domain.RefreshToken{}, "idx_refresh_tokens_session_id"); err != nil {
+				return err
+			}
+		}
+		for _, column := range sessionColumns {
+			if !migrator.HasColumn(&domain.RefreshToken{}, column) {
+				continue
+			}
+			if err := migrator.DropColumn(&domain.RefreshToken{}, column); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
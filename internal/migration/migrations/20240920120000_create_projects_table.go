@@ -0,0 +1,99 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateProjectsTable creates the projects table/collection and registers the
+// permission for managing every user's projects
+type CreateProjectsTable struct{}
+
+func (m *CreateProjectsTable) Version() string {
+	return "20240920120000"
+}
+
+func (m *CreateProjectsTable) Description() string {
+	return "Create projects table/collection"
+}
+
+// projectsManagePermission is the permission required to access other users' projects
+var projectsManagePermission = domain.Permission{
+	Name:        domain.PermissionProjectsManage,
+	Description: "View, update and delete any user's projects",
+}
+
+func (m *CreateProjectsTable) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - use GORM AutoMigrate, which also creates the owner foreign key
+		if err := db.GORM.AutoMigrate(&domain.Project{}); err != nil {
+			return err
+		}
+
+		permission := projectsManagePermission
+		return db.GORM.WithContext(ctx).Create(&permission).Error
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - create collection and indexes
+		mongoDB := db.MongoDB()
+		collection := mongoDB.Collection(domain.TableName(domain.Project{}))
+
+		indexes := []mongo.IndexModel{
+			{
+				Keys:    map[string]interface{}{"id": 1},
+				Options: options.Index().SetUnique(true).SetName("idx_projects_id"),
+			},
+			{
+				Keys:    bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("idx_projects_owner_id_created_at"),
+			},
+			{
+				Keys:    map[string]interface{}{"status": 1},
+				Options: options.Index().SetName("idx_projects_status"),
+			},
+			{
+				Keys:    map[string]interface{}{"created_at": -1},
+				Options: options.Index().SetName("idx_projects_created_at"),
+			},
+		}
+
+		if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+			return err
+		}
+
+		permission := projectsManagePermission
+		permission.CreatedAt = time.Now()
+		_, err := mongoDB.Collection(domain.TableName(domain.Permission{})).InsertOne(ctx, permission)
+		return err
+	}
+
+	return nil
+}
+
+func (m *CreateProjectsTable) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - drop table and permission
+		if err := db.GORM.WithContext(ctx).Where("name = ?", domain.PermissionProjectsManage).Delete(&domain.Permission{}).Error; err != nil {
+			return err
+		}
+		return db.GORM.Migrator().DropTable(&domain.Project{})
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - drop collection and permission
+		mongoDB := db.MongoDB()
+		if _, err := mongoDB.Collection(domain.TableName(domain.Permission{})).DeleteOne(ctx, bson.M{"name": domain.PermissionProjectsManage}); err != nil {
+			return err
+		}
+		return mongoDB.Collection(domain.TableName(domain.Project{})).Drop(ctx)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateOrganizationsTables creates the organizations, memberships and
+// invitations tables/collections and registers the permission for managing
+// every organization
+type CreateOrganizationsTables struct{}
+
+func (m *CreateOrganizationsTables) Version() string {
+	return "20240925120000"
+}
+
+func (m *CreateOrganizationsTables) Description() string {
+	return "Create organizations, memberships and invitations tables/collections"
+}
+
+// organizationsManagePermission is the permission acting as owner of every organization
+var organizationsManagePermission = domain.Permission{
+	Name:        domain.PermissionOrganizationsManage,
+	Description: "Manage any organization, its members and invitations",
+}
+
+func (m *CreateOrganizationsTables) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - use GORM AutoMigrate, which also creates the foreign keys
+		if err := db.GORM.AutoMigrate(&domain.Organization{}, &domain.Membership{}, &domain.Invitation{}); err != nil {
+			return err
+		}
+
+		permission := organizationsManagePermission
+		return db.GORM.WithContext(ctx).Create(&permission).Error
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - create collections and indexes
+		mongoDB := db.MongoDB()
+
+		collectionIndexes := map[string][]mongo.IndexModel{
+			domain.TableName(domain.Organization{}): {
+				{
+					Keys:    map[string]interface{}{"id": 1},
+					Options: options.Index().SetUnique(true).SetName("idx_organizations_id"),
+				},
+				{
+					Keys:    map[string]interface{}{"slug": 1},
+					Options: options.Index().SetUnique(true).SetName("idx_organizations_slug"),
+				},
+			},
+			domain.TableName(domain.Membership{}): {
+				{
+					Keys:    map[string]interface{}{"id": 1},
+					Options: options.Index().SetUnique(true).SetName("idx_memberships_id"),
+				},
+				{
+					Keys:    bson.D{{Key: "organization_id", Value: 1}, {Key: "user_id", Value: 1}},
+					Options: options.Index().SetUnique(true).SetName("idx_memberships_org_user"),
+				},
+				{
+					Keys:    map[string]interface{}{"user_id": 1},
+					Options: options.Index().SetName("idx_memberships_user_id"),
+				},
+			},
+			domain.TableName(domain.Invitation{}): {
+				{
+					Keys:    map[string]interface{}{"id": 1},
+					Options: options.Index().SetUnique(true).SetName("idx_invitations_id"),
+				},
+				{
+					Keys:    map[string]interface{}{"token_hash": 1},
+					Options: options.Index().SetUnique(true).SetName("idx_invitations_token_hash"),
+				},
+				{
+					Keys:    map[string]interface{}{"organization_id": 1},
+					Options: options.Index().SetName("idx_invitations_organization_id"),
+				},
+			},
+		}
+
+		for name, indexes := range collectionIndexes {
+			if _, err := mongoDB.Collection(name).Indexes().CreateMany(ctx, indexes); err != nil {
+				return err
+			}
+		}
+
+		permission := organizationsManagePermission
+		permission.CreatedAt = time.Now()
+		_, err := mongoDB.Collection(domain.TableName(domain.Permission{})).InsertOne(ctx, permission)
+		return err
+	}
+
+	return nil
+}
+
+func (m *CreateOrganizationsTables) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - drop tables and permission
+		if err := db.GORM.WithContext(ctx).Where("name = ?", domain.PermissionOrganizationsManage).Delete(&domain.Permission{}).Error; err != nil {
+			return err
+		}
+		return db.GORM.Migrator().DropTable(&domain.Invitation{}, &domain.Membership{}, &domain.Organization{})
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - drop collections and permission
+		mongoDB := db.MongoDB()
+		if _, err := mongoDB.Collection(domain.TableName(domain.Permission{})).DeleteOne(ctx, bson.M{"name": domain.PermissionOrganizationsManage}); err != nil {
+			return err
+		}
+		for _, name := range []string{domain.TableName(domain.Invitation{}), domain.TableName(domain.Membership{}), domain.TableName(domain.Organization{})} {
+			if err := mongoDB.Collection(name).Drop(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateWebhooksTables creates the webhooks and webhook deliveries
+// tables/collections and registers the permission for managing webhooks
+type CreateWebhooksTables struct{}
+
+func (m *CreateWebhooksTables) Version() string {
+	return "20240930120000"
+}
+
+func (m *CreateWebhooksTables) Description() string {
+	return "Create webhooks and webhook deliveries tables/collections"
+}
+
+// webhooksManagePermission is the permission required to manage webhooks
+var webhooksManagePermission = domain.Permission{
+	Name:        domain.PermissionWebhooksManage,
+	Description: "Register webhooks and view their deliveries",
+}
+
+func (m *CreateWebhooksTables) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - use GORM AutoMigrate, which also creates the foreign key
+		if err := db.GORM.AutoMigrate(&domain.Webhook{}, &domain.WebhookDelivery{}); err != nil {
+			return err
+		}
+
+		permission := webhooksManagePermission
+		return db.GORM.WithContext(ctx).Create(&permission).Error
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - create collections and indexes
+		mongoDB := db.MongoDB()
+
+		collectionIndexes := map[string][]mongo.IndexModel{
+			domain.TableName(domain.Webhook{}): {
+				{
+					Keys:    map[string]interface{}{"id": 1},
+					Options: options.Index().SetUnique(true).SetName("idx_webhooks_id"),
+				},
+			},
+			domain.TableName(domain.WebhookDelivery{}): {
+				{
+					Keys:    map[string]interface{}{"id": 1},
+					Options: options.Index().SetUnique(true).SetName("idx_webhook_deliveries_id"),
+				},
+				{
+					Keys:    bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}},
+					Options: options.Index().SetName("idx_webhook_deliveries_webhook_id_created_at"),
+				},
+				{
+					Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+					Options: options.Index().SetName("idx_webhook_deliveries_status_next_attempt_at"),
+				},
+			},
+		}
+
+		for name, indexes := range collectionIndexes {
+			if _, err := mongoDB.Collection(name).Indexes().CreateMany(ctx, indexes); err != nil {
+				return err
+			}
+		}
+
+		permission := webhooksManagePermission
+		permission.CreatedAt = time.Now()
+		_, err := mongoDB.Collection(domain.TableName(domain.Permission{})).InsertOne(ctx, permission)
+		return err
+	}
+
+	return nil
+}
+
+func (m *CreateWebhooksTables) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - drop tables and permission
+		if err := db.GORM.WithContext(ctx).Where("name = ?", domain.PermissionWebhooksManage).Delete(&domain.Permission{}).Error; err != nil {
+			return err
+		}
+		return db.GORM.Migrator().DropTable(&domain.WebhookDelivery{}, &domain.Webhook{})
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - drop collections and permission
+		mongoDB := db.MongoDB()
+		if _, err := mongoDB.Collection(domain.TableName(domain.Permission{})).DeleteOne(ctx, bson.M{"name": domain.PermissionWebhooksManage}); err != nil {
+			return err
+		}
+		for _, name := range []string{domain.TableName(domain.WebhookDelivery{}), domain.TableName(domain.Webhook{})} {
+			if err := mongoDB.Collection(name).Drop(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
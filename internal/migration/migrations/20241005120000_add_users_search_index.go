@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/repo"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+)
+
+// AddUsersSearchIndex indexes the full-text search document of users on
+// PostgreSQL, used when POSTGRES_FULL_TEXT_SEARCH is enabled
+type AddUsersSearchIndex struct{}
+
+func (m *AddUsersSearchIndex) Version() string {
+	return "20241005120000"
+}
+
+func (m *AddUsersSearchIndex) Description() string {
+	return "Add full-text search index to users table"
+}
+
+func (m *AddUsersSearchIndex) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM == nil || db.GORM.Dialector.Name() != "postgres" {
+		// Substring search doesn't use an index
+		return nil
+	}
+
+	table := domain.TableName(domain.User{})
+	return db.GORM.WithContext(ctx).Exec(
+		"CREATE INDEX IF NOT EXISTS idx_" + table + "_search ON " + table + " USING GIN (" + repo.UserSearchVector + ")",
+	).Error
+}
+
+func (m *AddUsersSearchIndex) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM == nil || db.GORM.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	return db.GORM.WithContext(ctx).Exec("DROP INDEX IF EXISTS idx_" + domain.TableName(domain.User{}) + "_search").Error
+}
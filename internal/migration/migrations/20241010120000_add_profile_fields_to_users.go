@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+)
+
+// userProfileFields are the optional profile columns of users
+var userProfileFields = []string{"AvatarURL", "Phone", "Locale", "Timezone", "Metadata"}
+
+// AddProfileFieldsToUsers adds the optional profile columns and the metadata
+// column, which is JSONB on PostgreSQL
+type AddProfileFieldsToUsers struct{}
+
+func (m *AddProfileFieldsToUsers) Version() string {
+	return "20241010120000"
+}
+
+func (m *AddProfileFieldsToUsers) Description() string {
+	return "Add profile fields and metadata to users table"
+}
+
+func (m *AddProfileFieldsToUsers) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM == nil {
+		// MongoDB documents pick up the fields on their first profile update
+		return nil
+	}
+
+	tx := db.GORM.WithContext(ctx)
+	migrator := tx.Migrator()
+	for _, field := range userProfileFields {
+		if migrator.HasColumn(&domain.User{}, field) {
+			continue
+		}
+		if err := migrator.AddColumn(&domain.User{}, field); err != nil {
+			return err
+		}
+	}
+
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	// The model stores metadata as JSON text, which fresh tables also get
+	// from CreateUsersTable
+	table := domain.TableName(domain.User{})
+	return tx.Exec("ALTER TABLE " + table + " ALTER COLUMN metadata TYPE JSONB USING metadata::jsonb").Error
+}
+
+func (m *AddProfileFieldsToUsers) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM == nil {
+		return nil
+	}
+
+	migrator := db.GORM.WithContext(ctx).Migrator()
+	for _, field := range userProfileFields {
+		if !migrator.HasColumn(&domain.User{}, field) {
+			continue
+		}
+		if err := migrator.DropColumn(&domain.User{}, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateUserSettingsTable creates the user settings table/collection
+type CreateUserSettingsTable struct{}
+
+func (m *CreateUserSettingsTable) Version() string {
+	return "20241015120000"
+}
+
+func (m *CreateUserSettingsTable) Description() string {
+	return "Create user settings table/collection"
+}
+
+func (m *CreateUserSettingsTable) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - use GORM AutoMigrate, which also creates the foreign key
+		return db.GORM.AutoMigrate(&domain.UserSetting{})
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - create the collection with a unique index per user and key
+		_, err := db.MongoDB().Collection(domain.TableName(domain.UserSetting{})).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("idx_user_settings_user_key"),
+		})
+		return err
+	}
+
+	return nil
+}
+
+func (m *CreateUserSettingsTable) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - drop table
+		return db.GORM.Migrator().DropTable(&domain.UserSetting{})
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - drop collection
+		return db.MongoDB().Collection(domain.TableName(domain.UserSetting{})).Drop(ctx)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateNotificationsTable creates the notifications table/collection
+type CreateNotificationsTable struct{}
+
+func (m *CreateNotificationsTable) Version() string {
+	return "20241020120000"
+}
+
+func (m *CreateNotificationsTable) Description() string {
+	return "Create notifications table/collection"
+}
+
+func (m *CreateNotificationsTable) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - use GORM AutoMigrate, which also creates the foreign key
+		return db.GORM.AutoMigrate(&domain.Notification{})
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - create the collection with its ID and inbox indexes
+		_, err := db.MongoDB().Collection(domain.TableName(domain.Notification{})).Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "id", Value: 1}},
+				Options: options.Index().SetUnique(true).SetName("idx_notifications_id"),
+			},
+			{
+				Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "read_at", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("idx_notifications_user_id_read_at"),
+			},
+		})
+		return err
+	}
+
+	return nil
+}
+
+func (m *CreateNotificationsTable) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		// SQL databases - drop table
+		return db.GORM.Migrator().DropTable(&domain.Notification{})
+	}
+
+	if db.Mongo != nil {
+		// MongoDB - drop collection
+		return db.MongoDB().Collection(domain.TableName(domain.Notification{})).Drop(ctx)
+	}
+
+	return nil
+}
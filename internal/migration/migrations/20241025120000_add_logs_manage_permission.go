@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AddLogsManagePermission registers the permission for changing log levels
+// at runtime
+type AddLogsManagePermission struct{}
+
+func (m *AddLogsManagePermission) Version() string {
+	return "20241025120000"
+}
+
+func (m *AddLogsManagePermission) Description() string {
+	return "Add permission for changing log levels"
+}
+
+// logsManagePermission is the permission required to change log levels
+var logsManagePermission = domain.Permission{
+	Name:        domain.PermissionLogsManage,
+	Description: "View and change log levels at runtime",
+}
+
+func (m *AddLogsManagePermission) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		permission := logsManagePermission
+		return db.GORM.WithContext(ctx).Create(&permission).Error
+	}
+
+	if db.Mongo != nil {
+		mongoDB := db.MongoDB()
+
+		permission := logsManagePermission
+		permission.CreatedAt = time.Now()
+		_, err := mongoDB.Collection(domain.TableName(domain.Permission{})).InsertOne(ctx, permission)
+		return err
+	}
+
+	return nil
+}
+
+func (m *AddLogsManagePermission) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		return db.GORM.WithContext(ctx).Where("name = ?", domain.PermissionLogsManage).Delete(&domain.Permission{}).Error
+	}
+
+	if db.Mongo != nil {
+		mongoDB := db.MongoDB()
+		_, err := mongoDB.Collection(domain.TableName(domain.Permission{})).DeleteOne(ctx, bson.M{"name": domain.PermissionLogsManage})
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AddStatsReadPermission registers the permission for reading the admin
+// dashboard statistics
+type AddStatsReadPermission struct{}
+
+func (m *AddStatsReadPermission) Version() string {
+	return "20241030120000"
+}
+
+func (m *AddStatsReadPermission) Description() string {
+	return "Add permission for reading dashboard statistics"
+}
+
+// statsReadPermission is the permission required to read dashboard statistics
+var statsReadPermission = domain.Permission{
+	Name:        domain.PermissionStatsRead,
+	Description: "View the admin dashboard statistics",
+}
+
+func (m *AddStatsReadPermission) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		permission := statsReadPermission
+		return db.GORM.WithContext(ctx).Create(&permission).Error
+	}
+
+	if db.Mongo != nil {
+		mongoDB := db.MongoDB()
+
+		permission := statsReadPermission
+		permission.CreatedAt = time.Now()
+		_, err := mongoDB.Collection(domain.TableName(domain.Permission{})).InsertOne(ctx, permission)
+		return err
+	}
+
+	return nil
+}
+
+func (m *AddStatsReadPermission) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		return db.GORM.WithContext(ctx).Where("name = ?", domain.PermissionStatsRead).Delete(&domain.Permission{}).Error
+	}
+
+	if db.Mongo != nil {
+		mongoDB := db.MongoDB()
+		_, err := mongoDB.Collection(domain.TableName(domain.Permission{})).DeleteOne(ctx, bson.M{"name": domain.PermissionStatsRead})
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AddDeletionScheduledAtToUsers adds the column recording when an account
+// deleted by its owner is purged, indexed for the purge task
+type AddDeletionScheduledAtToUsers struct{}
+
+func (m *AddDeletionScheduledAtToUsers) Version() string {
+	return "20241105120000"
+}
+
+func (m *AddDeletionScheduledAtToUsers) Description() string {
+	return "Add deletion_scheduled_at column to users table"
+}
+
+func (m *AddDeletionScheduledAtToUsers) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		migrator := db.GORM.WithContext(ctx).Migrator()
+		if !migrator.HasColumn(&domain.User{}, "DeletionScheduledAt") {
+			if err := migrator.AddColumn(&domain.User{}, "DeletionScheduledAt"); err != nil {
+				return err
+			}
+		}
+		if migrator.HasIndex(&domain.User{}, "idx_users_deletion_scheduled_at") {
+			return nil
+		}
+		return migrator.CreateIndex(&domain.User{}, "idx_users_deletion_scheduled_at")
+	}
+
+	_, err := db.MongoDB().Collection(domain.TableName(domain.User{})).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "deletion_scheduled_at", Value: 1}},
+		Options: options.Index().SetName("idx_users_deletion_scheduled_at"),
+	})
+	return err
+}
+
+func (m *AddDeletionScheduledAtToUsers) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		migrator := db.GORM.WithContext(ctx).Migrator()
+		if migrator.HasIndex(&domain.User{}, "idx_users_deletion_scheduled_at") {
+			if err := migrator.DropIndex(&domain.User{}, "idx_users_deletion_scheduled_at"); err != nil {
+				return err
+			}
+		}
+		if !migrator.HasColumn(&domain.User{}, "DeletionScheduledAt") {
+			return nil
+		}
+		return migrator.DropColumn(&domain.User{}, "DeletionScheduledAt")
+	}
+
+	_, err := db.MongoDB().Collection(domain.TableName(domain.User{})).Indexes().DropOne(ctx, "idx_users_deletion_scheduled_at")
+	return err
+}
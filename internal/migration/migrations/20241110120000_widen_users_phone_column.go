@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+)
+
+// WidenUsersPhoneColumn widens the phone column to hold encrypted phone
+// numbers. Only PostgreSQL enforces the column size.
+type WidenUsersPhoneColumn struct{}
+
+func (m *WidenUsersPhoneColumn) Version() string {
+	return "20241110120000"
+}
+
+func (m *WidenUsersPhoneColumn) Description() string {
+	return "Widen phone column of users table for encrypted values"
+}
+
+func (m *WidenUsersPhoneColumn) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM == nil {
+		return nil
+	}
+
+	tx := db.GORM.WithContext(ctx)
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return tx.Migrator().AlterColumn(&domain.User{}, "Phone")
+}
+
+func (m *WidenUsersPhoneColumn) Down(ctx context.Context, db *database.Connection) error {
+	// Encrypted phone numbers don't fit the former size, so the column is
+	// left as it is
+	return nil
+}
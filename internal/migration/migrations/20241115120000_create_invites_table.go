@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateInvitesTable creates the registration invites table/collection and
+// registers the permission for managing invites
+type CreateInvitesTable struct{}
+
+func (m *CreateInvitesTable) Version() string {
+	return "20241115120000"
+}
+
+func (m *CreateInvitesTable) Description() string {
+	return "Create registration invites table/collection"
+}
+
+// invitesManagePermission is the permission required to manage invites
+var invitesManagePermission = domain.Permission{
+	Name:        domain.PermissionInvitesManage,
+	Description: "Invite people to register and manage their invites",
+}
+
+func (m *CreateInvitesTable) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		if err := db.GORM.AutoMigrate(&domain.Invite{}); err != nil {
+			return err
+		}
+
+		permission := invitesManagePermission
+		return db.GORM.WithContext(ctx).Create(&permission).Error
+	}
+
+	if db.Mongo != nil {
+		mongoDB := db.MongoDB()
+
+		indexes := []mongo.IndexModel{
+			{
+				Keys:    map[string]interface{}{"id": 1},
+				Options: options.Index().SetUnique(true).SetName("idx_invites_id"),
+			},
+			{
+				Keys:    map[string]interface{}{"token_hash": 1},
+				Options: options.Index().SetUnique(true).SetName("idx_invites_token_hash"),
+			},
+			{
+				Keys:    map[string]interface{}{"email": 1},
+				Options: options.Index().SetName("idx_invites_email"),
+			},
+		}
+		if _, err := mongoDB.Collection(domain.TableName(domain.Invite{})).Indexes().CreateMany(ctx, indexes); err != nil {
+			return err
+		}
+
+		permission := invitesManagePermission
+		permission.CreatedAt = time.Now()
+		_, err := mongoDB.Collection(domain.TableName(domain.Permission{})).InsertOne(ctx, permission)
+		return err
+	}
+
+	return nil
+}
+
+func (m *CreateInvitesTable) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		if err := db.GORM.WithContext(ctx).Where("name = ?", domain.PermissionInvitesManage).Delete(&domain.Permission{}).Error; err != nil {
+			return err
+		}
+		return db.GORM.Migrator().DropTable(&domain.Invite{})
+	}
+
+	if db.Mongo != nil {
+		mongoDB := db.MongoDB()
+		if _, err := mongoDB.Collection(domain.TableName(domain.Permission{})).DeleteOne(ctx, bson.M{"name": domain.PermissionInvitesManage}); err != nil {
+			return err
+		}
+		return mongoDB.Collection(domain.TableName(domain.Invite{})).Drop(ctx)
+	}
+
+	return nil
+}
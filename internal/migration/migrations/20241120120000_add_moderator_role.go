@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AddModeratorRole defines the moderator role of the default role catalog,
+// which development seeders give some users. A moderator role created
+// through the API before is kept as it is.
+type AddModeratorRole struct{}
+
+func (m *AddModeratorRole) Version() string {
+	return "20241120120000"
+}
+
+func (m *AddModeratorRole) Description() string {
+	return "Add moderator role"
+}
+
+// moderatorRole is the moderator role as installations start with it
+var moderatorRole = domain.Role{
+	Name:        domain.RoleModerator,
+	Description: "Reads users",
+	Permissions: []string{domain.PermissionUsersRead},
+}
+
+func (m *AddModeratorRole) Up(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		role := moderatorRole
+		return db.GORM.WithContext(ctx).Where("name = ?", role.Name).FirstOrCreate(&role).Error
+	}
+
+	if db.Mongo != nil {
+		mongoDB := db.MongoDB()
+
+		role := moderatorRole
+		role.CreatedAt = time.Now()
+		role.UpdatedAt = role.CreatedAt
+		_, err := mongoDB.Collection(domain.TableName(domain.Role{})).UpdateOne(ctx,
+			bson.M{"name": role.Name},
+			bson.M{"$setOnInsert": role},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (m *AddModeratorRole) Down(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		return db.GORM.WithContext(ctx).Where("name = ?", domain.RoleModerator).Delete(&domain.Role{}).Error
+	}
+
+	if db.Mongo != nil {
+		mongoDB := db.MongoDB()
+		_, err := mongoDB.Collection(domain.TableName(domain.Role{})).DeleteOne(ctx, bson.M{"name": domain.RoleModerator})
+		return err
+	}
+
+	return nil
+}
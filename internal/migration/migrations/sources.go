@@ -0,0 +1,9 @@
+package migrations
+
+import "embed"
+
+// Sources holds the files of the migrations in this package, named
+// <version>_<name>.go, from which their checksums are computed
+//
+//go:embed *.go
+var Sources embed.FS
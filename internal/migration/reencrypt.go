@@ -0,0 +1,88 @@
+package migration
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/fieldcrypt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gorm.io/gorm"
+)
+
+// reencryptBatchSize is the number of users rewritten per batch
+const reencryptBatchSize = 500
+
+// ReencryptUsers rewrites the encrypted fields of every user with the
+// current key of fieldcrypt.Default(), encrypting plaintext values and
+// values encrypted with retired keys. It returns the number of users
+// rewritten; once done, retired keys can be removed from the configuration.
+func ReencryptUsers(ctx context.Context, db *database.Connection) (int64, error) {
+	if db.GORM != nil {
+		return reencryptUsersGORM(db.GORM.WithContext(ctx))
+	}
+	return reencryptUsersMongo(ctx, db)
+}
+
+func reencryptUsersGORM(tx *gorm.DB) (int64, error) {
+	var users []domain.User
+	var rewritten int64
+	result := tx.Select("id", "phone", "metadata").
+		Where("phone <> '' OR metadata IS NOT NULL").
+		FindInBatches(&users, reencryptBatchSize, func(batch *gorm.DB, _ int) error {
+			for i := range users {
+				user := &users[i]
+				err := batch.Session(&gorm.Session{NewDB: true}).Model(user).
+					Select("Phone", "Metadata").UpdateColumns(user).Error
+				if err != nil {
+					return err
+				}
+				rewritten++
+			}
+			return nil
+		})
+	return rewritten, result.Error
+}
+
+func reencryptUsersMongo(ctx context.Context, db *database.Connection) (int64, error) {
+	collection := db.MongoDB().Collection(domain.TableName(domain.User{}))
+	filter := bson.M{"$or": bson.A{
+		bson.M{"phone": bson.M{"$exists": true}},
+		bson.M{"metadata": bson.M{"$exists": true}},
+	}}
+
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var rewritten int64
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID       primitive.ObjectID  `bson:"_id"`
+			Phone    fieldcrypt.String   `bson:"phone,omitempty"`
+			Metadata fieldcrypt.Document `bson:"metadata,omitempty"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return rewritten, err
+		}
+
+		set := bson.M{}
+		if doc.Phone != "" {
+			set["phone"] = doc.Phone
+		}
+		if doc.Metadata != nil {
+			set["metadata"] = doc.Metadata
+		}
+		if len(set) == 0 {
+			continue
+		}
+		if _, err := collection.UpdateByID(ctx, doc.ID, bson.M{"$set": set}); err != nil {
+			return rewritten, err
+		}
+		rewritten++
+	}
+	return rewritten, cursor.Err()
+}
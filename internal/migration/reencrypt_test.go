@@ -0,0 +1,51 @@
+package migration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/fieldcrypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReencryptUsers(t *testing.T) {
+	ctx := context.Background()
+	db := newTestConnection(t)
+	require.NoError(t, db.GORM.AutoMigrate(&domain.User{}))
+
+	oldKey, newKey := strings.Repeat("11", fieldcrypt.KeySize), strings.Repeat("22", fieldcrypt.KeySize)
+	old, err := fieldcrypt.NewKeyring(fieldcrypt.Config{Keys: map[string]string{"k1": oldKey}})
+	require.NoError(t, err)
+	rotated, err := fieldcrypt.NewKeyring(fieldcrypt.Config{Keys: map[string]string{"k1": oldKey, "k2": newKey}, KeyID: "k2"})
+	require.NoError(t, err)
+	t.Cleanup(func() { fieldcrypt.SetDefault(nil) })
+
+	fieldcrypt.SetDefault(old)
+	users := []*domain.User{
+		{Email: "a@example.com", Name: "A", Phone: "+33612345678", Metadata: map[string]interface{}{"plan": "pro"}},
+		{Email: "b@example.com", Name: "B"},
+	}
+	require.NoError(t, db.GORM.Create(users).Error)
+
+	fieldcrypt.SetDefault(rotated)
+	count, err := ReencryptUsers(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	var raw struct {
+		Phone    string
+		Metadata string
+	}
+	require.NoError(t, db.GORM.Table("users").Where("id = ?", users[0].ID).Take(&raw).Error)
+	assert.True(t, strings.HasPrefix(raw.Phone, "enc:v1:k2:"))
+	assert.True(t, strings.HasPrefix(raw.Metadata, `"enc:v1:k2:`))
+
+	var user domain.User
+	require.NoError(t, db.GORM.First(&user, users[0].ID).Error)
+	assert.Equal(t, "+33612345678", user.Phone)
+	assert.Equal(t, map[string]interface{}{"plan": "pro"}, user.Metadata)
+	assert.Equal(t, "A", user.Name)
+}
@@ -3,6 +3,7 @@ package migration
 import (
 	"context"
 
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
 	"github.com/luxixing/fx-gin-scaffold/internal/migration/migrations"
 	"github.com/luxixing/fx-gin-scaffold/internal/migration/seeders"
 	"github.com/luxixing/fx-gin-scaffold/pkg/database"
@@ -10,24 +11,56 @@ import (
 
 // RegisterMigrations registers all migrations
 func RegisterMigrations(migrator *Migrator) {
+	migrator.SetSources(migrations.Sources)
+
 	// Add all migrations here in chronological order
 	migrator.AddMigration(&migrations.CreateUsersTable{})
+	migrator.AddMigration(&migrations.CreateRefreshTokensTable{})
+	migrator.AddMigration(&migrations.CreateRBACTables{})
+	migrator.AddMigration(&migrations.CreateAuditLogsTable{})
+	migrator.AddMigration(&migrations.AddFilesReadPermission{})
+	migrator.AddMigration(&migrations.AddPendingEmailToUsers{})
+	migrator.AddMigration(&migrations.AddSessionColumnsToRefreshTokens{})
+	migrator.AddMigration(&migrations.CreateProjectsTable{})
+	migrator.AddMigration(&migrations.CreateOrganizationsTables{})
+	migrator.AddMigration(&migrations.CreateWebhooksTables{})
+	migrator.AddMigration(&migrations.AddUsersSearchIndex{})
+	migrator.AddMigration(&migrations.AddProfileFieldsToUsers{})
+	migrator.AddMigration(&migrations.CreateUserSettingsTable{})
+	migrator.AddMigration(&migrations.CreateNotificationsTable{})
+	migrator.AddMigration(&migrations.AddLogsManagePermission{})
+	migrator.AddMigration(&migrations.AddStatsReadPermission{})
+	migrator.AddMigration(&migrations.AddDeletionScheduledAtToUsers{})
+	migrator.AddMigration(&migrations.WidenUsersPhoneColumn{})
+	migrator.AddMigration(&migrations.CreateInvitesTable{})
+	migrator.AddMigration(&migrations.AddModeratorRole{})
+	// gen:migrations
+
+	// SQL migrations from internal/migration/sql
+	for _, m := range SQLMigrations() {
+		migrator.AddMigration(m)
+	}
 }
 
-// RegisterSeeders registers all seeders
-func RegisterSeeders(migrator *Migrator) {
+// RegisterSeeders registers all seeders of the environment of cfg
+func RegisterSeeders(migrator *Migrator, cfg *config.Config) {
 	// Add all seeders here
-	migrator.AddSeeder(&seeders.AdminUserSeeder{})
+	migrator.AddSeeder(seeders.NewAdminUserSeeder(cfg.Seed, cfg.App.Env))
 	migrator.AddSeeder(&seeders.TestUsersSeeder{})
+	migrator.AddSeeder(&seeders.SampleProjectsSeeder{})
 }
 
-// RunMigrations runs all migrations and seeders
-func RunMigrations(ctx context.Context, db *database.Connection, env string) error {
+// RunMigrations runs all migrations and the seeders that have not run yet.
+// With force, applied migrations that were modified are logged instead of
+// failing the run; with reseed, seeders that already ran run again.
+func RunMigrations(ctx context.Context, db *database.Connection, cfg *config.Config, force, reseed bool) error {
 	migrator := NewMigrator(db)
+	migrator.SetForce(force)
+	migrator.SetReseed(reseed)
 	
 	// Register migrations and seeders
 	RegisterMigrations(migrator)
-	RegisterSeeders(migrator)
+	RegisterSeeders(migrator, cfg)
 	
 	// Run migrations first
 	if err := migrator.Migrate(ctx); err != nil {
@@ -35,5 +68,14 @@ func RunMigrations(ctx context.Context, db *database.Connection, env string) err
 	}
 	
 	// Then run seeders
-	return migrator.Seed(ctx, env)
+	return migrator.Seed(ctx, cfg.App.Env)
+}
+
+// RunSchemaMigrations runs the pending migrations without seeders, e.g. on
+// the secondary database
+func RunSchemaMigrations(ctx context.Context, db *database.Connection, force bool) error {
+	migrator := NewMigrator(db)
+	migrator.SetForce(force)
+	RegisterMigrations(migrator)
+	return migrator.Migrate(ctx)
 }
\ No newline at end of file
@@ -2,16 +2,47 @@ package seeders
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
 	"github.com/luxixing/fx-gin-scaffold/internal/domain"
 	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/password"
+	"github.com/luxixing/fx-gin-scaffold/pkg/utils"
 	"go.mongodb.org/mongo-driver/mongo"
 	"gorm.io/gorm"
 )
 
-// AdminUserSeeder creates the default admin user
-type AdminUserSeeder struct{}
+// DefaultAdminPassword is the well-known password the admin was seeded with
+// before it was configurable; the seeder refuses it in staging
+const DefaultAdminPassword = "admin123456"
+
+// generatedAdminPasswordLength is the length of generated admin passwords
+const generatedAdminPasswordLength = 24
+
+// errDefaultAdminPassword is returned when seeding staging with the
+// well-known admin password
+var errDefaultAdminPassword = errors.New("ADMIN_PASSWORD must not be the default password in staging")
+
+// AdminUserSeeder creates the admin user configured by ADMIN_EMAIL,
+// ADMIN_NAME and ADMIN_PASSWORD
+type AdminUserSeeder struct {
+	cfg config.SeedConfig
+	env string
+	// output receives the generated password
+	output io.Writer
+}
+
+// NewAdminUserSeeder creates a seeder of the admin of cfg for the env
+// environment
+func NewAdminUserSeeder(cfg config.SeedConfig, env string) *AdminUserSeeder {
+	return &AdminUserSeeder{cfg: cfg, env: env, output: os.Stdout}
+}
 
 func (s *AdminUserSeeder) Name() string {
 	return "AdminUserSeeder"
@@ -23,63 +54,85 @@ func (s *AdminUserSeeder) ShouldRun(env string) bool {
 }
 
 func (s *AdminUserSeeder) Run(ctx context.Context, db *database.Connection) error {
+	plain := s.cfg.AdminPassword
+	if s.env == "staging" && plain == DefaultAdminPassword {
+		return errDefaultAdminPassword
+	}
+	generated := plain == ""
+	if generated {
+		var err error
+		if plain, err = utils.GenerateRandomString(generatedAdminPasswordLength); err != nil {
+			return err
+		}
+	}
+
 	adminUser := &domain.User{
-		Email:     "admin@example.com",
-		Password:  "admin123456", // Will be hashed
-		Name:      "System Administrator",
+		Email:     strings.ToLower(strings.TrimSpace(s.cfg.AdminEmail)),
+		Password:  plain, // Will be hashed
+		Name:      s.cfg.AdminName,
 		Role:      "admin",
 		Active:    true,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	// Hash the password
-	if err := adminUser.HashPassword(); err != nil {
+	// Hash the password; it is upgraded to the configured algorithm on first login
+	hasher, err := password.NewBcryptHasher(password.DefaultBcryptCost)
+	if err != nil {
 		return err
 	}
-
-	if db.GORM != nil {
-		return s.seedSQL(db.GORM, adminUser)
+	if err := adminUser.HashPassword(hasher); err != nil {
+		return err
 	}
 
-	if db.Mongo != nil {
-		return s.seedMongo(ctx, db.Mongo, adminUser)
+	var created bool
+	switch {
+	case db.GORM != nil:
+		created, err = s.seedSQL(db.GORM, adminUser)
+	case db.Mongo != nil:
+		created, err = s.seedMongo(ctx, db.MongoDB(), adminUser)
+	}
+	if err != nil {
+		return err
 	}
 
+	// The password is only shown once: the admin exists on later runs
+	if created && generated {
+		fmt.Fprintf(s.output, "🔑 Seeded admin %s with the generated password: %s\n", adminUser.Email, plain)
+	}
 	return nil
 }
 
-func (s *AdminUserSeeder) seedSQL(gormDB *gorm.DB, user *domain.User) error {
+func (s *AdminUserSeeder) seedSQL(gormDB *gorm.DB, user *domain.User) (bool, error) {
 	// Check if admin user already exists
 	var existingUser domain.User
 	err := gormDB.Where("email = ?", user.Email).First(&existingUser).Error
 	if err == nil {
 		// User already exists, skip
-		return nil
+		return false, nil
 	}
 	if err != gorm.ErrRecordNotFound {
 		// Some other error occurred
-		return err
+		return false, err
 	}
 
 	// Create the admin user
-	return gormDB.Create(user).Error
+	return true, gormDB.Create(user).Error
 }
 
-func (s *AdminUserSeeder) seedMongo(ctx context.Context, mongoDB *mongo.Client, user *domain.User) error {
-	dbName := "fx_gin_scaffold" // TODO: Get from config
-	collection := mongoDB.Database(dbName).Collection("fx_users")
+func (s *AdminUserSeeder) seedMongo(ctx context.Context, mongoDB *mongo.Database, user *domain.User) (bool, error) {
+	collection := mongoDB.Collection(domain.TableName(domain.User{}))
 
 	// Check if admin user already exists
 	count, err := collection.CountDocuments(ctx, map[string]interface{}{
 		"email": user.Email,
 	})
 	if err != nil {
-		return err
+		return false, err
 	}
 	if count > 0 {
 		// User already exists, skip
-		return nil
+		return false, nil
 	}
 
 	// Convert user to MongoDB document
@@ -94,5 +147,5 @@ func (s *AdminUserSeeder) seedMongo(ctx context.Context, mongoDB *mongo.Client,
 	}
 
 	_, err = collection.InsertOne(ctx, userDoc)
-	return err
+	return err == nil, err
 }
\ No newline at end of file
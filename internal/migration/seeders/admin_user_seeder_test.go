@@ -0,0 +1,82 @@
+package seeders
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newAdminSeederConnection(t *testing.T) *database.Connection {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.User{}))
+	return &database.Connection{GORM: db}
+}
+
+// TestAdminUserSeeder tests that the admin is created from the configuration
+// and that a generated password is printed only when the admin is created
+func TestAdminUserSeeder(t *testing.T) {
+	ctx := context.Background()
+	hasher, err := password.NewBcryptHasher(password.DefaultBcryptCost)
+	require.NoError(t, err)
+
+	t.Run("generates and prints a password when unset", func(t *testing.T) {
+		conn := newAdminSeederConnection(t)
+		var out bytes.Buffer
+		seeder := NewAdminUserSeeder(config.SeedConfig{AdminEmail: " Ops@Example.com", AdminName: "Ops"}, "development")
+		seeder.output = &out
+
+		require.NoError(t, seeder.Run(ctx, conn))
+		printed := strings.TrimSpace(out.String())
+		generated := printed[strings.LastIndex(printed, " ")+1:]
+		assert.Len(t, generated, generatedAdminPasswordLength)
+
+		var admin domain.User
+		require.NoError(t, conn.GORM.First(&admin).Error)
+		assert.Equal(t, "ops@example.com", admin.Email)
+		assert.Equal(t, "Ops", admin.Name)
+		assert.Equal(t, domain.RoleAdmin, admin.Role)
+		assert.True(t, admin.CheckPassword(hasher, generated))
+
+		out.Reset()
+		require.NoError(t, seeder.Run(ctx, conn))
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("uses the configured password", func(t *testing.T) {
+		conn := newAdminSeederConnection(t)
+		var out bytes.Buffer
+		seeder := NewAdminUserSeeder(config.SeedConfig{AdminEmail: "ops@example.com", AdminPassword: "correct horse"}, "staging")
+		seeder.output = &out
+
+		require.NoError(t, seeder.Run(ctx, conn))
+		assert.Empty(t, out.String())
+
+		var admin domain.User
+		require.NoError(t, conn.GORM.First(&admin).Error)
+		assert.True(t, admin.CheckPassword(hasher, "correct horse"))
+	})
+
+	t.Run("refuses the default password in staging", func(t *testing.T) {
+		conn := newAdminSeederConnection(t)
+		cfg := config.SeedConfig{AdminEmail: "ops@example.com", AdminPassword: DefaultAdminPassword}
+
+		assert.ErrorIs(t, NewAdminUserSeeder(cfg, "staging").Run(ctx, conn), errDefaultAdminPassword)
+		var count int64
+		require.NoError(t, conn.GORM.Model(&domain.User{}).Count(&count).Error)
+		assert.Zero(t, count)
+
+		require.NoError(t, NewAdminUserSeeder(cfg, "development").Run(ctx, conn))
+	})
+}
@@ -0,0 +1,168 @@
+package seeders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/password"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// FakeUserPassword is the password of every fake user
+	FakeUserPassword = "password123"
+	// fakeUsersBatchSize is the number of users inserted per statement
+	fakeUsersBatchSize = 500
+)
+
+// FakeUsersSeeder creates fake users for load testing pagination and search.
+// The same seed always generates the same users, and users whose email
+// already exists are skipped, so datasets can be grown or rebuilt.
+type FakeUsersSeeder struct {
+	count int
+	seed  int64
+}
+
+// NewFakeUsersSeeder creates a seeder generating count users from seed
+func NewFakeUsersSeeder(count int, seed int64) *FakeUsersSeeder {
+	return &FakeUsersSeeder{count: count, seed: seed}
+}
+
+func (s *FakeUsersSeeder) Name() string {
+	return "FakeUsersSeeder"
+}
+
+func (s *FakeUsersSeeder) ShouldRun(env string) bool {
+	// Fake users are generated on request only
+	return false
+}
+
+// Repeatable runs the seeder on every request, as it grows or rebuilds
+// the dataset
+func (s *FakeUsersSeeder) Repeatable() bool {
+	return true
+}
+
+func (s *FakeUsersSeeder) Run(ctx context.Context, db *database.Connection) error {
+	// Hash the shared password once; bcrypt per user would dominate the run
+	hasher, err := password.NewBcryptHasher(password.DefaultBcryptCost)
+	if err != nil {
+		return err
+	}
+	hash, err := hasher.Hash(FakeUserPassword)
+	if err != nil {
+		return err
+	}
+
+	faker := gofakeit.New(s.seed)
+	now := time.Now()
+	for start := 0; start < s.count; start += fakeUsersBatchSize {
+		size := min(fakeUsersBatchSize, s.count-start)
+		users := make([]*domain.User, size)
+		for i := range users {
+			users[i] = fakeUser(faker, start+i, hash, now)
+		}
+
+		if db.GORM != nil {
+			err = insertUsersSQL(ctx, db.GORM, users)
+		} else if db.Mongo != nil {
+			err = insertUsersMongo(ctx, db.MongoDB(), users)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to insert fake users %d-%d: %w", start+1, start+size, err)
+		}
+
+		logger.FromContext(ctx).Debug("inserted fake users", zap.Int("count", start+size), zap.Int("total", s.count))
+	}
+
+	return nil
+}
+
+// fakeUser generates the n-th user. The number in the email keeps emails
+// unique however many users share a name.
+func fakeUser(faker *gofakeit.Faker, n int, hash string, now time.Time) *domain.User {
+	first, last := faker.FirstName(), faker.LastName()
+	role := "user"
+	if faker.Number(1, 20) == 1 {
+		role = domain.RoleModerator
+	}
+	createdAt := now.Add(-time.Duration(faker.Number(0, 365*24*60)) * time.Minute)
+
+	return &domain.User{
+		Email:     strings.ToLower(fmt.Sprintf("%s.%s.%d@%s", first, last, n+1, faker.DomainName())),
+		Password:  hash,
+		Name:      first + " " + last,
+		Role:      role,
+		Active:    faker.Number(1, 10) > 1,
+		Phone:     faker.Phone(),
+		Locale:    faker.RandomString([]string{"en-US", "en-GB", "zh-CN", "de-DE", "fr-FR", "ja-JP"}),
+		Timezone:  faker.TimeZoneRegion(),
+		Metadata:  map[string]interface{}{"job_title": faker.JobTitle(), "city": faker.City()},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+// insertUsersSQL inserts a batch of users, skipping existing emails
+func insertUsersSQL(ctx context.Context, gormDB *gorm.DB, users []*domain.User) error {
+	// Create writes the column default, true, for inactive users, and sets
+	// it on them
+	var inactive []string
+	for _, user := range users {
+		if !user.Active {
+			inactive = append(inactive, user.Email)
+		}
+	}
+
+	return gormDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(users).Error; err != nil {
+			return err
+		}
+		if len(inactive) == 0 {
+			return nil
+		}
+		return tx.Model(&domain.User{}).Where("email IN ?", inactive).Update("active", false).Error
+	})
+}
+
+// insertUsersMongo inserts a batch of users, skipping existing emails
+func insertUsersMongo(ctx context.Context, mongoDB *mongo.Database, users []*domain.User) error {
+	collection := mongoDB.Collection(domain.TableName(domain.User{}))
+
+	docs := make([]interface{}, len(users))
+	for i, user := range users {
+		docs[i] = userDocument(user)
+	}
+
+	// Unordered inserts go on past duplicates, which are then ignored
+	_, err := collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil && !isOnlyDuplicateKeyErrors(err) {
+		return err
+	}
+	return nil
+}
+
+// isOnlyDuplicateKeyErrors reports whether a bulk insert failed only on
+// existing keys
+func isOnlyDuplicateKeyErrors(err error) bool {
+	bulkErr, ok := err.(mongo.BulkWriteException)
+	if !ok || bulkErr.WriteConcernError != nil {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package seeders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestFakeUsersSeeder tests that users are generated deterministically and
+// that running the seeder again doesn't duplicate them
+func TestFakeUsersSeeder(t *testing.T) {
+	now := time.Now()
+	first, second := gofakeit.New(7), gofakeit.New(7)
+	for n := 0; n < 10; n++ {
+		assert.Equal(t, fakeUser(first, n, "hash", now), fakeUser(second, n, "hash", now))
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.User{}))
+	conn := &database.Connection{GORM: db}
+
+	count := fakeUsersBatchSize + 10
+	require.NoError(t, NewFakeUsersSeeder(count, 7).Run(context.Background(), conn))
+	require.NoError(t, NewFakeUsersSeeder(count, 7).Run(context.Background(), conn))
+
+	var total, inactive int64
+	require.NoError(t, db.Model(&domain.User{}).Count(&total).Error)
+	require.NoError(t, db.Model(&domain.User{}).Where("active = ?", false).Count(&inactive).Error)
+	assert.Equal(t, int64(count), total)
+	assert.Greater(t, inactive, int64(0))
+	assert.Less(t, inactive, total/2)
+}
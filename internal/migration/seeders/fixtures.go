@@ -0,0 +1,150 @@
+package seeders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/password"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures describes data loaded from a YAML or JSON file
+type Fixtures struct {
+	Users []FixtureUser `json:"users" yaml:"users"`
+}
+
+// FixtureUser is a user in a fixtures file. The password is given in plain
+// text and hashed when loaded.
+type FixtureUser struct {
+	Email     string                 `json:"email" yaml:"email"`
+	Password  string                 `json:"password" yaml:"password"`
+	Name      string                 `json:"name" yaml:"name"`
+	Role      string                 `json:"role" yaml:"role"`
+	Active    *bool                  `json:"active" yaml:"active"`
+	AvatarURL string                 `json:"avatar_url" yaml:"avatar_url"`
+	Phone     string                 `json:"phone" yaml:"phone"`
+	Locale    string                 `json:"locale" yaml:"locale"`
+	Timezone  string                 `json:"timezone" yaml:"timezone"`
+	Metadata  map[string]interface{} `json:"metadata" yaml:"metadata"`
+}
+
+// FixturesSeeder loads a fixtures file. Users whose email already exists are
+// skipped, so a file can be loaded repeatedly.
+type FixturesSeeder struct {
+	path     string
+	fixtures *Fixtures
+}
+
+// NewFixturesSeeder reads the fixtures file at path; files ending in .json
+// are parsed as JSON and others as YAML
+func NewFixturesSeeder(path string) (*FixturesSeeder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	fixtures, err := ParseFixtures(data, strings.EqualFold(filepath.Ext(path), ".json"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixtures %s: %w", path, err)
+	}
+
+	return &FixturesSeeder{path: path, fixtures: fixtures}, nil
+}
+
+// ParseFixtures decodes and validates fixtures in JSON or YAML
+func ParseFixtures(data []byte, isJSON bool) (*Fixtures, error) {
+	var fixtures Fixtures
+	if isJSON {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&fixtures); err != nil {
+			return nil, err
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&fixtures); err != nil {
+			return nil, err
+		}
+	}
+
+	emails := make(map[string]bool)
+	for i, user := range fixtures.Users {
+		if user.Email == "" || user.Password == "" {
+			return nil, fmt.Errorf("user %d: email and password are required", i+1)
+		}
+		if emails[user.Email] {
+			return nil, fmt.Errorf("user %d: duplicate email %s", i+1, user.Email)
+		}
+		emails[user.Email] = true
+	}
+
+	return &fixtures, nil
+}
+
+func (s *FixturesSeeder) Name() string {
+	return "Fixtures(" + s.path + ")"
+}
+
+func (s *FixturesSeeder) ShouldRun(env string) bool {
+	// Fixtures are loaded on request, in any environment
+	return true
+}
+
+// Repeatable loads the file on every request, as it may have changed
+func (s *FixturesSeeder) Repeatable() bool {
+	return true
+}
+
+func (s *FixturesSeeder) Run(ctx context.Context, db *database.Connection) error {
+	users, err := s.fixtures.domainUsers()
+	if err != nil {
+		return err
+	}
+	return seedUsers(ctx, db, users)
+}
+
+// domainUsers converts the fixture users, hashing their passwords; they are
+// upgraded to the configured algorithm on first login
+func (f *Fixtures) domainUsers() ([]*domain.User, error) {
+	hasher, err := password.NewBcryptHasher(password.DefaultBcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	users := make([]*domain.User, 0, len(f.Users))
+	for _, fixture := range f.Users {
+		user := &domain.User{
+			Email:     fixture.Email,
+			Password:  fixture.Password,
+			Name:      fixture.Name,
+			Role:      fixture.Role,
+			Active:    fixture.Active == nil || *fixture.Active,
+			AvatarURL: fixture.AvatarURL,
+			Phone:     fixture.Phone,
+			Locale:    fixture.Locale,
+			Timezone:  fixture.Timezone,
+			Metadata:  fixture.Metadata,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if user.Role == "" {
+			user.Role = "user"
+		}
+		if err := user.HashPassword(hasher); err != nil {
+			return nil, fmt.Errorf("failed to hash password for user %s: %w", user.Email, err)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
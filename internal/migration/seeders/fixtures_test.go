@@ -0,0 +1,72 @@
+package seeders
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestFixturesSeeder tests that fixture users are created once, with their
+// passwords hashed and the defaults applied
+func TestFixturesSeeder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+users:
+  - email: alice@example.com
+    password: password123
+    name: Alice
+    role: admin
+  - email: bob@example.com
+    password: password123
+    name: Bob
+    active: false
+    locale: zh-CN
+`), 0o600))
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.User{}))
+	conn := &database.Connection{GORM: db}
+
+	seeder, err := NewFixturesSeeder(path)
+	require.NoError(t, err)
+	require.NoError(t, seeder.Run(context.Background(), conn))
+	require.NoError(t, seeder.Run(context.Background(), conn))
+
+	var users []domain.User
+	require.NoError(t, db.Order("email").Find(&users).Error)
+	require.Len(t, users, 2)
+	assert.Equal(t, "admin", users[0].Role)
+	assert.True(t, users[0].Active)
+	assert.NotEqual(t, "password123", users[0].Password)
+	assert.Equal(t, "user", users[1].Role)
+	assert.False(t, users[1].Active)
+	assert.Equal(t, "zh-CN", users[1].Locale)
+}
+
+func TestParseFixtures(t *testing.T) {
+	fixtures, err := ParseFixtures([]byte(`{"users":[{"email":"a@example.com","password":"password123"}]}`), true)
+	require.NoError(t, err)
+	assert.Len(t, fixtures.Users, 1)
+
+	for name, data := range map[string]string{
+		"unknown field":   `{"users":[{"email":"a@example.com","password":"x","admin":true}]}`,
+		"missing email":   `{"users":[{"password":"x"}]}`,
+		"duplicate email": `{"users":[{"email":"a@example.com","password":"x"},{"email":"a@example.com","password":"y"}]}`,
+	} {
+		_, err := ParseFixtures([]byte(data), true)
+		assert.Error(t, err, name)
+	}
+
+	_, err = ParseFixtures([]byte("users:\n  - email: a@example.com\n    password: x\n    admin: true\n"), false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package seeders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/repo"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+)
+
+// SampleProjectsSeeder creates sample projects for the test users
+type SampleProjectsSeeder struct{}
+
+// sampleProjects lists the projects seeded for each owner's email
+var sampleProjects = map[string][]domain.Project{
+	"user1@example.com": {
+		{Name: "Website Redesign", Description: "Refresh the marketing site", Status: domain.ProjectStatusActive},
+		{Name: "Legacy Import", Description: "One-off data import from the old system", Status: domain.ProjectStatusArchived},
+	},
+	"user2@example.com": {
+		{Name: "Mobile App", Description: "iOS and Android client", Status: domain.ProjectStatusActive},
+	},
+}
+
+func (s *SampleProjectsSeeder) Name() string {
+	return "SampleProjectsSeeder"
+}
+
+func (s *SampleProjectsSeeder) ShouldRun(env string) bool {
+	// Only run in development environment, after TestUsersSeeder
+	return env == "development"
+}
+
+func (s *SampleProjectsSeeder) Run(ctx context.Context, db *database.Connection) error {
+	if db.GORM != nil {
+		return s.seedSQL(ctx, db.GORM)
+	}
+
+	if db.Mongo != nil {
+		return s.seedMongo(ctx, db.MongoDB())
+	}
+
+	return nil
+}
+
+func (s *SampleProjectsSeeder) seedSQL(ctx context.Context, gormDB *gorm.DB) error {
+	projects := repo.NewProjectGormRepository(gormDB)
+
+	for email, samples := range sampleProjects {
+		var owner domain.User
+		err := gormDB.WithContext(ctx).Where("email = ?", email).First(&owner).Error
+		if err == gorm.ErrRecordNotFound {
+			// Owner was not seeded, skip
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.seedOwner(ctx, projects, owner.ID, samples); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SampleProjectsSeeder) seedMongo(ctx context.Context, database *mongo.Database) error {
+	projects := repo.NewProjectMongoRepository(database)
+
+	for email, samples := range sampleProjects {
+		var owner struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		err := database.Collection(domain.TableName(domain.User{})).FindOne(ctx, bson.M{"email": email}).Decode(&owner)
+		if err == mongo.ErrNoDocuments {
+			// Owner was not seeded, skip
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// MongoDB users expose their ObjectID timestamp as the numeric ID
+		ownerID := uint(owner.ID.Timestamp().Unix())
+		if err := s.seedOwner(ctx, projects, ownerID, samples); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// seedOwner creates the sample projects unless the owner already has projects
+func (s *SampleProjectsSeeder) seedOwner(ctx context.Context, projects domain.ProjectRepository, ownerID uint, samples []domain.Project) error {
+	query, err := (&domain.ProjectListFilter{OwnerID: ownerID}).Query()
+	if err != nil {
+		return err
+	}
+	_, total, err := projects.List(ctx, query, 0, 1)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return nil
+	}
+
+	for _, sample := range samples {
+		project := sample
+		project.OwnerID = ownerID
+		if err := projects.Create(ctx, &project); err != nil {
+			return fmt.Errorf("failed to create project %s: %w", project.Name, err)
+		}
+	}
+	return nil
+}
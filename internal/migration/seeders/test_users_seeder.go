@@ -7,6 +7,8 @@ import (
 
 	"github.com/luxixing/fx-gin-scaffold/internal/domain"
 	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/fieldcrypt"
+	"github.com/luxixing/fx-gin-scaffold/pkg/password"
 	"go.mongodb.org/mongo-driver/mongo"
 	"gorm.io/gorm"
 )
@@ -47,7 +49,7 @@ func (s *TestUsersSeeder) Run(ctx context.Context, db *database.Connection) erro
 			Email:     "moderator@example.com",
 			Password:  "password123",
 			Name:      "Test Moderator",
-			Role:      "moderator",
+			Role:      domain.RoleModerator,
 			Active:    true,
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
@@ -63,25 +65,34 @@ func (s *TestUsersSeeder) Run(ctx context.Context, db *database.Connection) erro
 		},
 	}
 
-	// Hash passwords
+	// Hash passwords; they are upgraded to the configured algorithm on first login
+	hasher, err := password.NewBcryptHasher(password.DefaultBcryptCost)
+	if err != nil {
+		return err
+	}
 	for _, user := range testUsers {
-		if err := user.HashPassword(); err != nil {
+		if err := user.HashPassword(hasher); err != nil {
 			return fmt.Errorf("failed to hash password for user %s: %w", user.Email, err)
 		}
 	}
 
+	return seedUsers(ctx, db, testUsers)
+}
+
+// seedUsers creates the users whose email isn't taken yet
+func seedUsers(ctx context.Context, db *database.Connection, users []*domain.User) error {
 	if db.GORM != nil {
-		return s.seedSQL(db.GORM, testUsers)
+		return seedUsersSQL(db.GORM, users)
 	}
 
 	if db.Mongo != nil {
-		return s.seedMongo(ctx, db.Mongo, testUsers)
+		return seedUsersMongo(ctx, db.MongoDB(), users)
 	}
 
 	return nil
 }
 
-func (s *TestUsersSeeder) seedSQL(gormDB *gorm.DB, users []*domain.User) error {
+func seedUsersSQL(gormDB *gorm.DB, users []*domain.User) error {
 	for _, user := range users {
 		// Check if user already exists
 		var existingUser domain.User
@@ -95,18 +106,24 @@ func (s *TestUsersSeeder) seedSQL(gormDB *gorm.DB, users []*domain.User) error {
 			return err
 		}
 
-		// Create the user
+		// Create the user. Create leaves out false, which the column
+		// defaults to true, and reads the default back.
+		active := user.Active
 		if err := gormDB.Create(user).Error; err != nil {
 			return fmt.Errorf("failed to create user %s: %w", user.Email, err)
 		}
+		if !active {
+			if err := gormDB.Model(user).Update("active", false).Error; err != nil {
+				return fmt.Errorf("failed to deactivate user %s: %w", user.Email, err)
+			}
+		}
 	}
 
 	return nil
 }
 
-func (s *TestUsersSeeder) seedMongo(ctx context.Context, mongoDB *mongo.Client, users []*domain.User) error {
-	dbName := "fx_gin_scaffold" // TODO: Get from config
-	collection := mongoDB.Database(dbName).Collection("fx_users")
+func seedUsersMongo(ctx context.Context, mongoDB *mongo.Database, users []*domain.User) error {
+	collection := mongoDB.Collection(domain.TableName(domain.User{}))
 
 	for _, user := range users {
 		// Check if user already exists
@@ -121,21 +138,40 @@ func (s *TestUsersSeeder) seedMongo(ctx context.Context, mongoDB *mongo.Client,
 			continue
 		}
 
-		// Convert user to MongoDB document
-		userDoc := map[string]interface{}{
-			"email":      user.Email,
-			"password":   user.Password,
-			"name":       user.Name,
-			"role":       user.Role,
-			"active":     user.Active,
-			"created_at": user.CreatedAt,
-			"updated_at": user.UpdatedAt,
-		}
-
-		if _, err := collection.InsertOne(ctx, userDoc); err != nil {
+		if _, err := collection.InsertOne(ctx, userDocument(user)); err != nil {
 			return fmt.Errorf("failed to create user %s: %w", user.Email, err)
 		}
 	}
 
 	return nil
+}
+
+// userDocument converts a user to a MongoDB document
+func userDocument(user *domain.User) map[string]interface{} {
+	userDoc := map[string]interface{}{
+		"email":      user.Email,
+		"password":   user.Password,
+		"name":       user.Name,
+		"role":       user.Role,
+		"active":     user.Active,
+		"created_at": user.CreatedAt,
+		"updated_at": user.UpdatedAt,
+	}
+	for field, value := range map[string]string{
+		"avatar_url": user.AvatarURL,
+		"locale":     user.Locale,
+		"timezone":   user.Timezone,
+	} {
+		if value != "" {
+			userDoc[field] = value
+		}
+	}
+	// Personal data is encrypted as the repository does
+	if user.Phone != "" {
+		userDoc["phone"] = fieldcrypt.String(user.Phone)
+	}
+	if len(user.Metadata) > 0 {
+		userDoc["metadata"] = fieldcrypt.Document(user.Metadata)
+	}
+	return userDoc
 }
\ No newline at end of file
@@ -0,0 +1,146 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// seedTrackingTable is the name of the seeder tracking table/collection,
+// before the table prefix
+const seedTrackingTable = "seeds"
+
+// SeedStatus describes whether a registered seeder has run
+type SeedStatus struct {
+	Name       string
+	Executed   bool
+	ExecutedAt *time.Time
+}
+
+// seedRecord represents a row/document in the seed tracking table/collection
+type seedRecord struct {
+	Name       string    `gorm:"column:name" bson:"name"`
+	ExecutedAt time.Time `gorm:"column:executed_at" bson:"executed_at"`
+}
+
+// SetReseed makes Seed and SeedOnly run seeders again even if they already
+// ran
+func (m *Migrator) SetReseed(reseed bool) {
+	m.reseed = reseed
+}
+
+// SeedStatus returns whether every registered seeder has run. Like Pending
+// it does not create the tracking table
+func (m *Migrator) SeedStatus(ctx context.Context) ([]SeedStatus, error) {
+	records, err := m.getSeedRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executed seeders: %w", err)
+	}
+
+	statuses := make([]SeedStatus, 0, len(m.seeders))
+	for _, seeder := range m.seeders {
+		status := SeedStatus{Name: seeder.Name()}
+		if record, exists := records[seeder.Name()]; exists {
+			executedAt := record.ExecutedAt
+			status.Executed = true
+			status.ExecutedAt = &executedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// seedTable returns the name of the seed tracking table/collection
+func (m *Migrator) seedTable() string {
+	return m.tablePrefix + seedTrackingTable
+}
+
+// ensureSeedTracking creates the seed tracking table/collection
+func (m *Migrator) ensureSeedTracking(ctx context.Context) error {
+	if m.db.GORM != nil {
+		return m.db.GORM.WithContext(ctx).Exec(fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				name VARCHAR(255) PRIMARY KEY,
+				executed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			)
+		`, m.seedTable())).Error
+	}
+
+	if m.db.Mongo != nil {
+		collection := m.db.MongoDB().Collection(m.seedTable())
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("idx_seeds_name"),
+		})
+		return err
+	}
+
+	return fmt.Errorf("no database connection available")
+}
+
+// getSeedRecords returns the seeders that ran keyed by name, none when the
+// tracking table does not exist yet
+func (m *Migrator) getSeedRecords(ctx context.Context) (map[string]seedRecord, error) {
+	if m.db.GORM == nil && m.db.Mongo == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	records := make(map[string]seedRecord)
+	exists, err := m.hasTable(ctx, m.seedTable())
+	if err != nil || !exists {
+		return records, err
+	}
+
+	var rows []seedRecord
+	if m.db.GORM != nil {
+		query := fmt.Sprintf("SELECT name, executed_at FROM %s", m.seedTable())
+		if err := m.db.GORM.WithContext(ctx).Raw(query).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		cursor, err := m.db.MongoDB().Collection(m.seedTable()).Find(ctx, bson.M{})
+		if err != nil {
+			return nil, err
+		}
+		if err := cursor.All(ctx, &rows); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, row := range rows {
+		records[row.Name] = row
+	}
+	return records, nil
+}
+
+// recordSeed records that a seeder ran, updating the time of a reseed
+func (m *Migrator) recordSeed(ctx context.Context, seeder Seeder) error {
+	if m.db.GORM != nil {
+		return m.db.GORM.WithContext(ctx).Exec(fmt.Sprintf(
+			"INSERT INTO %s (name, executed_at) VALUES (?, CURRENT_TIMESTAMP) "+
+				"ON CONFLICT (name) DO UPDATE SET executed_at = CURRENT_TIMESTAMP", m.seedTable()),
+			seeder.Name(),
+		).Error
+	}
+
+	if m.db.Mongo != nil {
+		_, err := m.db.MongoDB().Collection(m.seedTable()).UpdateOne(ctx,
+			bson.M{"name": seeder.Name()},
+			bson.M{"$set": bson.M{"executed_at": time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	}
+
+	return fmt.Errorf("no database connection available")
+}
+
+// isRepeatable reports whether a seeder runs every time it is requested
+func isRepeatable(seeder Seeder) bool {
+	r, ok := seeder.(Repeatable)
+	return ok && r.Repeatable()
+}
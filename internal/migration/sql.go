@@ -0,0 +1,178 @@
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+)
+
+// sqlFiles holds the SQL migrations in internal/migration/sql
+//
+//go:embed sql
+var sqlFiles embed.FS
+
+// sqlFileName matches SQL migration files such as
+// 20241101120000_add_projects_index.up.sql or, for one database only,
+// 20241101120000_add_projects_index.postgres.up.sql
+var sqlFileName = regexp.MustCompile(`^(\d{14})_(\w+?)(?:\.(postgres|sqlite))?\.(up|down)\.sql$`)
+
+// tablePrefixPlaceholder is replaced by DB_TABLE_PREFIX in SQL migrations
+const tablePrefixPlaceholder = "{{prefix}}"
+
+// noTransactionDirective on the first line of an up file runs it outside a
+// transaction, e.g. for CREATE INDEX CONCURRENTLY
+const noTransactionDirective = "-- migrate:no-transaction"
+
+// SQLMigration is a migration written as plain SQL files. Statements of the
+// file for the connected database are used, falling back to the file for
+// all databases; with neither, or on MongoDB, the migration does nothing.
+type SQLMigration struct {
+	version     string
+	description string
+	// up and down hold the SQL by dialect; "" is for all databases
+	up   map[string]string
+	down map[string]string
+}
+
+func (m *SQLMigration) Version() string {
+	return m.version
+}
+
+func (m *SQLMigration) Description() string {
+	return m.description
+}
+
+func (m *SQLMigration) Up(ctx context.Context, db *database.Connection) error {
+	return m.exec(ctx, db, m.up)
+}
+
+func (m *SQLMigration) Down(ctx context.Context, db *database.Connection) error {
+	return m.exec(ctx, db, m.down)
+}
+
+// Checksum hashes the up and down files, so editing an applied migration
+// is detected
+func (m *SQLMigration) Checksum() string {
+	hash := sha256.New()
+	for _, files := range []map[string]string{m.up, m.down} {
+		dialects := make([]string, 0, len(files))
+		for dialect := range files {
+			dialects = append(dialects, dialect)
+		}
+		sort.Strings(dialects)
+		for _, dialect := range dialects {
+			fmt.Fprintf(hash, "%s\x00%s\x00", dialect, files[dialect])
+		}
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// NoTransaction reports whether the up files asked to run outside a transaction
+func (m *SQLMigration) NoTransaction() bool {
+	for _, statements := range m.up {
+		if strings.HasPrefix(strings.TrimSpace(statements), noTransactionDirective) {
+			return true
+		}
+	}
+	return false
+}
+
+// exec runs the statements for the connected database
+func (m *SQLMigration) exec(ctx context.Context, db *database.Connection, files map[string]string) error {
+	if db.GORM == nil {
+		return nil
+	}
+
+	statements, ok := files[db.GORM.Dialector.Name()]
+	if !ok {
+		statements = files[""]
+	}
+	if strings.TrimSpace(statements) == "" {
+		return nil
+	}
+
+	statements = strings.ReplaceAll(statements, tablePrefixPlaceholder, db.TablePrefix)
+	return db.GORM.WithContext(ctx).Exec(statements).Error
+}
+
+// SQLMigrations returns the SQL migrations embedded from
+// internal/migration/sql. The files are checked by the tests, so a bad file
+// name is a programming error.
+func SQLMigrations() []Migration {
+	fsys, err := fs.Sub(sqlFiles, "sql")
+	if err != nil {
+		panic(err)
+	}
+	migrations, err := LoadSQLMigrations(fsys)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load SQL migrations: %v", err))
+	}
+	return migrations
+}
+
+// LoadSQLMigrations reads the SQL migrations in the root of fsys, ordered by
+// version. Other files are ignored.
+func LoadSQLMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string]*SQLMigration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		match := sqlFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid SQL migration file name %s (expected VERSION_name[.postgres|.sqlite].up|down.sql)", entry.Name())
+		}
+		version, name, dialect, direction := match[1], match[2], match[3], match[4]
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &SQLMigration{
+				version:     version,
+				description: strings.ReplaceAll(name, "_", " "),
+				up:          make(map[string]string),
+				down:        make(map[string]string),
+			}
+			byVersion[version] = migration
+		} else if migration.description != strings.ReplaceAll(name, "_", " ") {
+			return nil, fmt.Errorf("SQL migration %s has files with different names", version)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if direction == "up" {
+			migration.up[dialect] = string(content)
+		} else {
+			migration.down[dialect] = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if len(migration.up) == 0 {
+			return nil, fmt.Errorf("SQL migration %s has no up file", migration.version)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version() < migrations[j].Version()
+	})
+	return migrations, nil
+}
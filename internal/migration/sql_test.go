@@ -0,0 +1,83 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSQLMigrations tests that SQL files are loaded by version and that the
+// file for the connected database wins over the generic one
+func TestSQLMigrations(t *testing.T) {
+	ctx := context.Background()
+	fsys := fstest.MapFS{
+		"20240102000000_add_b.up.sql":          {Data: []byte("CREATE TABLE b (id INTEGER)")},
+		"20240102000000_add_b.down.sql":        {Data: []byte("DROP TABLE b")},
+		"20240101000000_add_a.up.sql":          {Data: []byte("CREATE TABLE a (id INTEGER); CREATE TABLE a2 (id INTEGER)")},
+		"20240101000000_add_a.postgres.up.sql": {Data: []byte("CREATE TABLE a (id BIGSERIAL)")},
+		"20240103000000_add_c.postgres.up.sql": {Data: []byte("-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY c ON a (id)")},
+		"README.md":                            {Data: []byte("docs")},
+	}
+
+	migrations, err := LoadSQLMigrations(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+	assert.Equal(t, "20240101000000", migrations[0].Version())
+	assert.Equal(t, "add a", migrations[0].Description())
+	assert.False(t, migrations[0].(NonTransactional).NoTransaction())
+	assert.True(t, migrations[2].(NonTransactional).NoTransaction())
+
+	checksum := migrations[1].(Checksummed).Checksum()
+	assert.Len(t, checksum, 64)
+	fsys["20240102000000_add_b.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE IF EXISTS b")}
+	reloaded, err := LoadSQLMigrations(fsys)
+	require.NoError(t, err)
+	assert.NotEqual(t, checksum, reloaded[1].(Checksummed).Checksum())
+	assert.Equal(t, migrations[0].(Checksummed).Checksum(), reloaded[0].(Checksummed).Checksum())
+
+	conn := newTestConnection(t)
+	migrator := NewMigrator(conn)
+	for _, m := range migrations {
+		migrator.AddMigration(m)
+	}
+	require.NoError(t, migrator.Migrate(ctx))
+	assert.True(t, conn.GORM.Migrator().HasTable("a2"))
+	assert.True(t, conn.GORM.Migrator().HasTable("b"))
+
+	require.NoError(t, migrations[1].Down(ctx, conn))
+	assert.False(t, conn.GORM.Migrator().HasTable("b"))
+}
+
+func TestLoadSQLMigrationsErrors(t *testing.T) {
+	for name, fsys := range map[string]fstest.MapFS{
+		"bad name":   {"add_a.up.sql": {}},
+		"no up":      {"20240101000000_add_a.down.sql": {}},
+		"mixed name": {"20240101000000_add_a.up.sql": {}, "20240101000000_add_b.down.sql": {}},
+	} {
+		_, err := LoadSQLMigrations(fsys)
+		assert.Error(t, err, name)
+	}
+}
+
+// TestEmbeddedSQLMigrations tests that the files in internal/migration/sql
+// load and don't reuse the version of another migration
+func TestEmbeddedSQLMigrations(t *testing.T) {
+	migrator := NewMigrator(newTestConnection(t))
+	RegisterMigrations(migrator)
+
+	versions := make(map[string]bool)
+	for _, m := range migrator.GetMigrations() {
+		assert.False(t, versions[m.Version()], "duplicate migration version %s", m.Version())
+		versions[m.Version()] = true
+	}
+
+	// Every migration has a checksum, from its SQL files or its Go file
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.False(t, status.Unverified, "migration %s has no checksum", status.Version)
+	}
+}
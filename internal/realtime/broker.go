@@ -0,0 +1,152 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// subscriberBufferSize is the number of pending events a subscriber may
+// queue before further events are dropped
+const subscriberBufferSize = 32
+
+// EventBrokerParams holds dependencies for EventBroker
+type EventBrokerParams struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+}
+
+// EventBroker delivers events to in-process subscribers such as
+// Server-Sent Events streams
+type EventBroker struct {
+	log         *zap.Logger
+	mu          sync.RWMutex
+	subscribers map[uint]map[*subscriber]struct{}
+}
+
+// subscriber is a single event stream of a user
+type subscriber struct {
+	events chan *domain.Event
+	closed bool
+}
+
+// NewEventBroker creates a broker that ends all streams on shutdown
+func NewEventBroker(p EventBrokerParams) *EventBroker {
+	b := &EventBroker{
+		log:         p.Logger,
+		subscribers: make(map[uint]map[*subscriber]struct{}),
+	}
+
+	p.Lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			b.Close()
+			return nil
+		},
+	})
+
+	return b
+}
+
+// Subscribe opens an event stream for a user. The returned function must
+// be called to release the subscription; the channel is closed afterwards
+func (b *EventBroker) Subscribe(userID uint) (<-chan *domain.Event, func()) {
+	sub := &subscriber{
+		events: make(chan *domain.Event, subscriberBufferSize),
+	}
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[*subscriber]struct{})
+	}
+	b.subscribers[userID][sub] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if subs, ok := b.subscribers[userID]; ok {
+			delete(subs, sub)
+			if len(subs) == 0 {
+				delete(b.subscribers, userID)
+			}
+		}
+		sub.close()
+	}
+
+	return sub.events, unsubscribe
+}
+
+// NotifyUser publishes an event to every stream of a user
+func (b *EventBroker) NotifyUser(ctx context.Context, userID uint, event *domain.Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers[userID] {
+		b.publish(userID, sub, event)
+	}
+	return nil
+}
+
+// Broadcast publishes an event to every stream
+func (b *EventBroker) Broadcast(ctx context.Context, event *domain.Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for userID, subs := range b.subscribers {
+		for sub := range subs {
+			b.publish(userID, sub, event)
+		}
+	}
+	return nil
+}
+
+// Subscribers returns the number of open streams
+func (b *EventBroker) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	count := 0
+	for _, subs := range b.subscribers {
+		count += len(subs)
+	}
+	return count
+}
+
+// Close ends every stream
+func (b *EventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for userID, subs := range b.subscribers {
+		for sub := range subs {
+			sub.close()
+		}
+		delete(b.subscribers, userID)
+	}
+}
+
+// publish queues an event without blocking. Callers hold at least the read
+// lock, and subscribers are only closed under the write lock
+func (b *EventBroker) publish(userID uint, sub *subscriber, event *domain.Event) {
+	select {
+	case sub.events <- event:
+	default:
+		b.log.Warn("event stream too slow, dropping event",
+			zap.Uint("user_id", userID),
+			zap.String("type", event.Type),
+		)
+	}
+}
+
+// close closes the event channel once; callers hold the write lock
+func (s *subscriber) close() {
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+}
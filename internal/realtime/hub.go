@@ -0,0 +1,221 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+// sendBufferSize is the number of pending messages a client may queue
+// before it is considered too slow and disconnected
+const sendBufferSize = 32
+
+// HubParams holds dependencies for Hub
+type HubParams struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+}
+
+// Hub tracks WebSocket connections per user and fans out events to them
+type Hub struct {
+	log     *zap.Logger
+	mu      sync.RWMutex
+	clients map[uint]map[*Client]struct{}
+}
+
+// NewHub creates a hub that closes all connections on shutdown
+func NewHub(p HubParams) *Hub {
+	h := &Hub{
+		log:     p.Logger,
+		clients: make(map[uint]map[*Client]struct{}),
+	}
+
+	p.Lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			h.Close()
+			return nil
+		},
+	})
+
+	return h
+}
+
+// Client is a single WebSocket connection of a user
+type Client struct {
+	userID uint
+	conn   *websocket.Conn
+	send   chan []byte
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Serve registers the connection and blocks until it is closed
+func (h *Hub) Serve(userID uint, conn *websocket.Conn) {
+	client := &Client{
+		userID: userID,
+		conn:   conn,
+		send:   make(chan []byte, sendBufferSize),
+	}
+
+	// Hijacked connections keep the HTTP server's read/write deadlines;
+	// clear them so long-lived connections are not cut off
+	_ = conn.SetDeadline(time.Time{})
+
+	h.register(client)
+	defer h.unregister(client)
+
+	go client.writeLoop()
+	client.readLoop()
+}
+
+// NotifyUser sends an event to every connection of a user
+func (h *Hub) NotifyUser(ctx context.Context, userID uint, event *domain.Event) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.clients[userID] {
+		h.enqueue(client, message)
+	}
+	return nil
+}
+
+// Broadcast sends an event to every connected user
+func (h *Hub) Broadcast(ctx context.Context, event *domain.Event) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, clients := range h.clients {
+		for client := range clients {
+			h.enqueue(client, message)
+		}
+	}
+	return nil
+}
+
+// Connections returns the number of open connections
+func (h *Hub) Connections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := 0
+	for _, clients := range h.clients {
+		count += len(clients)
+	}
+	return count
+}
+
+// Close disconnects every client
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for userID, clients := range h.clients {
+		for client := range clients {
+			client.close()
+		}
+		delete(h.clients, userID)
+	}
+}
+
+// register adds a client to its user's set
+func (h *Hub) register(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[client.userID] == nil {
+		h.clients[client.userID] = make(map[*Client]struct{})
+	}
+	h.clients[client.userID][client] = struct{}{}
+}
+
+// unregister removes a client and closes its connection
+func (h *Hub) unregister(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if clients, ok := h.clients[client.userID]; ok {
+		delete(clients, client)
+		if len(clients) == 0 {
+			delete(h.clients, client.userID)
+		}
+	}
+	client.close()
+}
+
+// enqueue queues a message without blocking, dropping clients that fall behind
+func (h *Hub) enqueue(client *Client, message []byte) {
+	if !client.trySend(message) {
+		h.log.Warn("websocket client too slow, disconnecting", zap.Uint("user_id", client.userID))
+		client.close()
+	}
+}
+
+// readLoop consumes incoming frames until the connection fails. Clients
+// only receive events, so incoming messages are discarded
+func (c *Client) readLoop() {
+	var message string
+	for {
+		if err := websocket.Message.Receive(c.conn, &message); err != nil {
+			return
+		}
+	}
+}
+
+// writeLoop writes queued messages to the connection
+func (c *Client) writeLoop() {
+	for message := range c.send {
+		if err := websocket.Message.Send(c.conn, string(message)); err != nil {
+			c.close()
+			return
+		}
+	}
+}
+
+// trySend queues a message, reporting false if the queue is full
+func (c *Client) trySend(message []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return true
+	}
+
+	select {
+	case c.send <- message:
+		return true
+	default:
+		return false
+	}
+}
+
+// close closes the connection and the send queue exactly once
+func (c *Client) close() {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.closed = true
+	close(c.send)
+	c.mu.Unlock()
+
+	c.conn.Close()
+}
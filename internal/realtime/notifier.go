@@ -0,0 +1,50 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// NotifierParams holds dependencies for the composite notifier
+type NotifierParams struct {
+	fx.In
+	Hub    *Hub
+	Broker *EventBroker
+}
+
+// notifier fans events out to every realtime transport
+type notifier struct {
+	targets []domain.Notifier
+}
+
+// NewNotifier creates a notifier delivering over WebSocket and SSE
+func NewNotifier(p NotifierParams) domain.Notifier {
+	return &notifier{
+		targets: []domain.Notifier{p.Hub, p.Broker},
+	}
+}
+
+// NotifyUser sends an event to every connection of a user
+func (n *notifier) NotifyUser(ctx context.Context, userID uint, event *domain.Event) error {
+	var errs []error
+	for _, target := range n.targets {
+		if err := target.NotifyUser(ctx, userID, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Broadcast sends an event to every connected user
+func (n *notifier) Broadcast(ctx context.Context, event *domain.Event) error {
+	var errs []error
+	for _, target := range n.targets {
+		if err := target.Broadcast(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// auditLogGormRepository implements AuditLogRepository for GORM-based databases
+type auditLogGormRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogGormRepository creates a new GORM-based audit log repository
+func NewAuditLogGormRepository(db *gorm.DB) domain.AuditLogRepository {
+	return &auditLogGormRepository{
+		db: db,
+	}
+}
+
+// Create stores a new audit log entry
+func (r *auditLogGormRepository) Create(ctx context.Context, entry *domain.AuditLog) error {
+	if err := gormConn(ctx, r.db).Create(entry).Error; err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create audit log")
+	}
+	return nil
+}
+
+// List retrieves audit log entries matching the filter, newest first
+func (r *auditLogGormRepository) List(ctx context.Context, filter domain.AuditLogFilter, offset, limit int) ([]*domain.AuditLog, int64, error) {
+	var entries []*domain.AuditLog
+	var total int64
+
+	query := gormConn(ctx, r.db).Model(&domain.AuditLog{})
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count audit logs")
+	}
+
+	err := query.
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC, id DESC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list audit logs")
+	}
+
+	return entries, total, nil
+}
+
+// CountByDay counts the entries of an action on each UTC day since since
+func (r *auditLogGormRepository) CountByDay(ctx context.Context, action string, since time.Time) ([]domain.DailyCount, error) {
+	query := gormConn(ctx, r.db).Model(&domain.AuditLog{}).Where("action = ?", action)
+	counts, err := gormCountByDay(query, "created_at", since)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count audit logs by day")
+	}
+	return counts, nil
+}
@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// AuditLogGormRepositoryTestSuite defines the test suite for audit log GORM repository
+type AuditLogGormRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo domain.AuditLogRepository
+}
+
+// SetupSuite sets up the test suite
+func (suite *AuditLogGormRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(suite.T(), err)
+
+	err = db.AutoMigrate(&domain.AuditLog{})
+	require.NoError(suite.T(), err)
+
+	suite.db = db
+	suite.repo = NewAuditLogGormRepository(db)
+}
+
+// TearDownSuite tears down the test suite
+func (suite *AuditLogGormRepositoryTestSuite) TearDownSuite() {
+	sqlDB, err := suite.db.DB()
+	require.NoError(suite.T(), err)
+	sqlDB.Close()
+}
+
+// SetupTest sets up each test
+func (suite *AuditLogGormRepositoryTestSuite) SetupTest() {
+	suite.db.Exec("DELETE FROM audit_logs")
+}
+
+// TestCreateAndList tests storing entries and listing them newest first
+func (suite *AuditLogGormRepositoryTestSuite) TestCreateAndList() {
+	ctx := context.Background()
+
+	first := &domain.AuditLog{ActorID: 1, Action: domain.AuditActionUserUpdate, TargetType: "user", TargetID: 2,
+		Before: map[string]interface{}{"name": "Old"}, After: map[string]interface{}{"name": "New"}}
+	second := &domain.AuditLog{ActorID: 1, Action: domain.AuditActionUserDelete, TargetType: "user", TargetID: 3}
+	require.NoError(suite.T(), suite.repo.Create(ctx, first))
+	require.NoError(suite.T(), suite.repo.Create(ctx, second))
+
+	entries, total, err := suite.repo.List(ctx, domain.AuditLogFilter{}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+	require.Len(suite.T(), entries, 2)
+	assert.Equal(suite.T(), domain.AuditActionUserDelete, entries[0].Action)
+	assert.Equal(suite.T(), "New", entries[1].After["name"])
+}
+
+// TestListFilters tests filtering by actor and action
+func (suite *AuditLogGormRepositoryTestSuite) TestListFilters() {
+	ctx := context.Background()
+
+	require.NoError(suite.T(), suite.repo.Create(ctx, &domain.AuditLog{ActorID: 1, Action: domain.AuditActionLogin}))
+	require.NoError(suite.T(), suite.repo.Create(ctx, &domain.AuditLog{ActorID: 2, Action: domain.AuditActionLogin}))
+	require.NoError(suite.T(), suite.repo.Create(ctx, &domain.AuditLog{ActorID: 2, Action: domain.AuditActionUserUpdate}))
+
+	entries, total, err := suite.repo.List(ctx, domain.AuditLogFilter{ActorID: 2}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+	assert.Len(suite.T(), entries, 2)
+
+	entries, total, err = suite.repo.List(ctx, domain.AuditLogFilter{ActorID: 2, Action: domain.AuditActionLogin}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	require.Len(suite.T(), entries, 1)
+	assert.Equal(suite.T(), uint(2), entries[0].ActorID)
+}
+
+// TestCountByDay tests counting an action per UTC day
+func (suite *AuditLogGormRepositoryTestSuite) TestCountByDay() {
+	ctx := context.Background()
+	utc8 := time.FixedZone("UTC+8", 8*60*60)
+
+	times := []time.Time{
+		// 2024-05-01 in UTC, 2024-05-02 in UTC+8
+		time.Date(2024, 5, 2, 7, 30, 0, 0, utc8),
+		time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC),
+		// Before since
+		time.Date(2024, 4, 30, 23, 0, 0, 0, time.UTC),
+	}
+	for _, createdAt := range times {
+		require.NoError(suite.T(), suite.repo.Create(ctx, &domain.AuditLog{ActorID: 1, Action: domain.AuditActionLogin, CreatedAt: createdAt}))
+	}
+	require.NoError(suite.T(), suite.repo.Create(ctx, &domain.AuditLog{ActorID: 1, Action: domain.AuditActionUserUpdate,
+		CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}))
+
+	counts, err := suite.repo.CountByDay(ctx, domain.AuditActionLogin, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.DailyCount{
+		{Date: "2024-05-01", Count: 2},
+		{Date: "2024-05-03", Count: 1},
+	}, counts)
+}
+
+// TestAuditLogGormRepositoryTestSuite runs the test suite
+func TestAuditLogGormRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditLogGormRepositoryTestSuite))
+}
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// auditLogMongoRepository implements AuditLogRepository for MongoDB
+type auditLogMongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAuditLogMongoRepository creates a new MongoDB-based audit log repository
+func NewAuditLogMongoRepository(db *mongo.Database) domain.AuditLogRepository {
+	return &auditLogMongoRepository{
+		collection: db.Collection(domain.TableName(domain.AuditLog{})),
+	}
+}
+
+// Create stores a new audit log entry
+func (r *auditLogMongoRepository) Create(ctx context.Context, entry *domain.AuditLog) error {
+	stampCreated(&entry.CreatedAt, nil)
+
+	if _, err := r.collection.InsertOne(ctx, entry); err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create audit log")
+	}
+	return nil
+}
+
+// List retrieves audit log entries matching the filter, newest first
+func (r *auditLogMongoRepository) List(ctx context.Context, filter domain.AuditLogFilter, offset, limit int) ([]*domain.AuditLog, int64, error) {
+	query := bson.M{}
+	if filter.ActorID != 0 {
+		query["actor_id"] = filter.ActorID
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count audit logs")
+	}
+
+	findOptions := options.Find().
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"created_at": -1})
+
+	cursor, err := r.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list audit logs")
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*domain.AuditLog
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to decode audit logs")
+	}
+
+	return entries, total, nil
+}
+
+// CountByDay counts the entries of an action on each UTC day since since
+func (r *auditLogMongoRepository) CountByDay(ctx context.Context, action string, since time.Time) ([]domain.DailyCount, error) {
+	counts, err := mongoCountByDay(ctx, r.collection, bson.M{"action": action}, "created_at", since)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count audit logs by day")
+	}
+	return counts, nil
+}
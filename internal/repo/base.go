@@ -0,0 +1,23 @@
+package repo
+
+// Entity describes a model for the generic repositories
+type Entity struct {
+	// Name is used in error messages, e.g. "user"
+	Name string
+	// Plural defaults to Name + "s"
+	Plural string
+	// NotFound is returned when no record matches
+	NotFound error
+	// Conflict is returned on unique constraint violations; nil keeps the database error
+	Conflict error
+	// DefaultOrder is the GORM List order when the query has no sorts
+	DefaultOrder string
+}
+
+// plural returns the plural entity name for error messages
+func (e Entity) plural() string {
+	if e.Plural != "" {
+		return e.Plural
+	}
+	return e.Name + "s"
+}
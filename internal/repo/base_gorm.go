@@ -0,0 +1,136 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormRepository provides the common CRUD operations for a GORM model.
+// Embed it in an entity repository and add the entity specific queries.
+type GormRepository[T any] struct {
+	db     *gorm.DB
+	entity Entity
+}
+
+// NewGormRepository creates a generic GORM repository for T
+func NewGormRepository[T any](db *gorm.DB, entity Entity) *GormRepository[T] {
+	return &GormRepository[T]{
+		db:     db,
+		entity: entity,
+	}
+}
+
+// DB returns the connection for ctx, joining its transaction if there is one
+func (r *GormRepository[T]) DB(ctx context.Context) *gorm.DB {
+	return gormConn(ctx, r.db)
+}
+
+// Create inserts a new record; associations such as preloaded relations are not saved
+func (r *GormRepository[T]) Create(ctx context.Context, entity *T) error {
+	if err := r.DB(ctx).Omit(clause.Associations).Create(entity).Error; err != nil {
+		if r.entity.Conflict != nil && isUniqueConstraintError(err) {
+			return r.entity.Conflict
+		}
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create "+r.entity.Name)
+	}
+	return nil
+}
+
+// GetByID retrieves a record by primary key
+func (r *GormRepository[T]) GetByID(ctx context.Context, id uint) (*T, error) {
+	var entity T
+	if err := r.DB(ctx).First(&entity, id).Error; err != nil {
+		return nil, r.notFoundOr(err, "Failed to get "+r.entity.Name+" by ID")
+	}
+	return &entity, nil
+}
+
+// First retrieves the first record matching the condition
+func (r *GormRepository[T]) First(ctx context.Context, query any, args ...any) (*T, error) {
+	var entity T
+	if err := r.DB(ctx).Where(query, args...).First(&entity).Error; err != nil {
+		return nil, r.notFoundOr(err, "Failed to get "+r.entity.Name)
+	}
+	return &entity, nil
+}
+
+// Update saves all fields of an existing record; associations are not saved
+func (r *GormRepository[T]) Update(ctx context.Context, entity *T) error {
+	result := r.DB(ctx).Omit(clause.Associations).Save(entity)
+	if result.Error != nil {
+		if r.entity.Conflict != nil && isUniqueConstraintError(result.Error) {
+			return r.entity.Conflict
+		}
+		return domain.WrapError(result.Error, domain.ErrCodeDatabase, "Failed to update "+r.entity.Name)
+	}
+	if result.RowsAffected == 0 {
+		return r.entity.NotFound
+	}
+	return nil
+}
+
+// Delete deletes a record by primary key (soft delete when T has gorm.DeletedAt)
+func (r *GormRepository[T]) Delete(ctx context.Context, id uint) error {
+	var entity T
+	result := r.DB(ctx).Delete(&entity, id)
+	if result.Error != nil {
+		return domain.WrapError(result.Error, domain.ErrCodeDatabase, "Failed to delete "+r.entity.Name)
+	}
+	if result.RowsAffected == 0 {
+		return r.entity.NotFound
+	}
+	return nil
+}
+
+// List retrieves records matching the query with pagination
+func (r *GormRepository[T]) List(ctx context.Context, query *domain.Query, offset, limit int) ([]*T, int64, error) {
+	return r.Paginate(ctx, r.Filtered(ctx, query), query, offset, limit)
+}
+
+// Count counts records matching the query
+func (r *GormRepository[T]) Count(ctx context.Context, query *domain.Query) (int64, error) {
+	var total int64
+	if err := r.Filtered(ctx, query).Count(&total).Error; err != nil {
+		return 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count "+r.entity.plural())
+	}
+	return total, nil
+}
+
+// Filtered returns a model scoped builder with the query filters applied
+func (r *GormRepository[T]) Filtered(ctx context.Context, query *domain.Query) *gorm.DB {
+	var model T
+	return applyGormFilters(r.DB(ctx).Model(&model), query)
+}
+
+// Paginate counts the builder's matches and fetches one page, ordered by the
+// query sorts or the entity's default order
+func (r *GormRepository[T]) Paginate(ctx context.Context, builder *gorm.DB, query *domain.Query, offset, limit int) ([]*T, int64, error) {
+	var entities []*T
+	var total int64
+
+	if err := builder.Count(&total).Error; err != nil {
+		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count "+r.entity.plural())
+	}
+
+	err := applyGormSorts(builder, query, r.entity.DefaultOrder).
+		Offset(offset).
+		Limit(limit).
+		Find(&entities).Error
+	if err != nil {
+		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list "+r.entity.plural())
+	}
+
+	return entities, total, nil
+}
+
+// notFoundOr maps a missing record to the entity's not found error and wraps anything else
+func (r *GormRepository[T]) notFoundOr(err error, message string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return r.entity.NotFound
+	}
+	return domain.WrapError(err, domain.ErrCodeDatabase, message)
+}
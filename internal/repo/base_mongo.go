@@ -0,0 +1,158 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository provides the common CRUD operations for documents of type D.
+// Entity repositories hold one and convert between D and the domain model.
+type MongoRepository[D any] struct {
+	collection *mongo.Collection
+	entity     Entity
+}
+
+// NewMongoRepository creates a generic MongoDB repository over collection
+func NewMongoRepository[D any](collection *mongo.Collection, entity Entity) *MongoRepository[D] {
+	return &MongoRepository[D]{
+		collection: collection,
+		entity:     entity,
+	}
+}
+
+// Collection returns the underlying collection
+func (r *MongoRepository[D]) Collection() *mongo.Collection {
+	return r.collection
+}
+
+// Create inserts a document and returns its generated ID
+func (r *MongoRepository[D]) Create(ctx context.Context, doc *D) (any, error) {
+	result, err := r.collection.InsertOne(ctx, doc)
+	if err != nil {
+		if r.entity.Conflict != nil && mongo.IsDuplicateKeyError(err) {
+			return nil, r.entity.Conflict
+		}
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create "+r.entity.Name)
+	}
+	return result.InsertedID, nil
+}
+
+// FindOne retrieves the first document matching filter
+func (r *MongoRepository[D]) FindOne(ctx context.Context, filter any) (*D, error) {
+	var doc D
+	err := database.Collection(ctx, r.collection).FindOne(ctx, filter).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, r.entity.NotFound
+		}
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to get "+r.entity.Name)
+	}
+	return &doc, nil
+}
+
+// Update applies update to the document matching filter
+func (r *MongoRepository[D]) Update(ctx context.Context, filter, update any) error {
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		if r.entity.Conflict != nil && mongo.IsDuplicateKeyError(err) {
+			return r.entity.Conflict
+		}
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to update "+r.entity.Name)
+	}
+	if result.MatchedCount == 0 {
+		return r.entity.NotFound
+	}
+	return nil
+}
+
+// Delete removes the document matching filter
+func (r *MongoRepository[D]) Delete(ctx context.Context, filter any) error {
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to delete "+r.entity.Name)
+	}
+	if result.DeletedCount == 0 {
+		return r.entity.NotFound
+	}
+	return nil
+}
+
+// Find retrieves every document matching filter
+func (r *MongoRepository[D]) Find(ctx context.Context, filter any, opts ...*options.FindOptions) ([]*D, error) {
+	cursor, err := database.Collection(ctx, r.collection).Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list "+r.entity.plural())
+	}
+	defer cursor.Close(ctx)
+
+	var docs []*D
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to decode "+r.entity.plural())
+	}
+	return docs, nil
+}
+
+// Count counts documents matching filter
+func (r *MongoRepository[D]) Count(ctx context.Context, filter any) (int64, error) {
+	total, err := database.Collection(ctx, r.collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count "+r.entity.plural())
+	}
+	return total, nil
+}
+
+// List counts the documents matching filter and fetches one page in sort order
+func (r *MongoRepository[D]) List(ctx context.Context, filter any, sort bson.D, offset, limit int) ([]*D, int64, error) {
+	total, err := r.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit)).
+		SetSort(sort)
+	docs, err := r.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	return docs, total, nil
+}
+
+// mongoCounter is a document in the counters collection
+type mongoCounter struct {
+	Seq uint `bson:"seq"`
+}
+
+// NextMongoID returns the next numeric ID for name from the counters collection,
+// for entities whose domain model uses uint IDs
+func NextMongoID(ctx context.Context, db *mongo.Database, name string) (uint, error) {
+	var counter mongoCounter
+	err := db.Collection("counters").FindOneAndUpdate(ctx,
+		bson.M{"_id": name},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to allocate "+name+" ID")
+	}
+	return counter.Seq, nil
+}
+
+// stampCreated sets the creation time, and the update time when given, of a
+// new document to now unless they are set, as GORM's autoCreateTime does, so
+// that copied documents keep theirs
+func stampCreated(createdAt, updatedAt *time.Time) {
+	if createdAt.IsZero() {
+		*createdAt = time.Now()
+	}
+	if updatedAt != nil && updatedAt.IsZero() {
+		*updatedAt = *createdAt
+	}
+}
@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+)
+
+// applyGormCursorPage restricts and orders a query for keyset pagination on
+// (created_at, id). One extra row is fetched to detect further pages.
+func applyGormCursorPage(db *gorm.DB, page *domain.CursorPage) *gorm.DB {
+	switch {
+	case page.After != nil:
+		c := page.After
+		db = db.Where("created_at < ? OR (created_at = ? AND id < ?)", c.CreatedAt, c.CreatedAt, c.ID).
+			Order("created_at DESC, id DESC")
+	case page.Before != nil:
+		c := page.Before
+		db = db.Where("created_at > ? OR (created_at = ? AND id > ?)", c.CreatedAt, c.CreatedAt, c.ID).
+			Order("created_at ASC, id ASC")
+	default:
+		db = db.Order("created_at DESC, id DESC")
+	}
+	return db.Limit(page.Limit + 1)
+}
+
+// mongoCursorPage returns the filter and find options for keyset pagination.
+// Documents don't carry the numeric domain ID, so only created_at is used as
+// the key; rows sharing a timestamp with the cursor may be skipped.
+func mongoCursorPage(filter bson.M, page *domain.CursorPage) (bson.M, *options.FindOptions) {
+	findOptions := options.Find().SetLimit(int64(page.Limit + 1))
+	switch {
+	case page.After != nil:
+		mongoCondition(filter, "created_at", "$lt", page.After.CreatedAt)
+		findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	case page.Before != nil:
+		mongoCondition(filter, "created_at", "$gt", page.Before.CreatedAt)
+		findOptions.SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	default:
+		findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	}
+	return filter, findOptions
+}
+
+// trimCursorPage drops the look-ahead row and restores newest-first order,
+// reporting whether more rows exist in the direction of travel
+func trimCursorPage[T any](rows []T, page *domain.CursorPage) ([]T, bool) {
+	hasMore := len(rows) > page.Limit
+	if hasMore {
+		rows = rows[:page.Limit]
+	}
+	if page.Before != nil {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	return rows, hasMore
+}
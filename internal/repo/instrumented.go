@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// Repository metrics, labeled with the repository and method called
+var (
+	repositoryCallDuration = metrics.Default.NewHistogram("repository_call_duration_seconds",
+		"Latency of repository calls.", metrics.DefaultBuckets, "repository", "method")
+	repositoryErrorsTotal = metrics.Default.NewCounter("repository_errors_total",
+		"Failed repository calls, by domain error code.", "repository", "method", "code")
+)
+
+// observeCall records the latency and any error of a repository call, and
+// logs it at debug level in the db module
+func observeCall(ctx context.Context, repository, method string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	repositoryCallDuration.With(repository, method).Observe(elapsed.Seconds())
+
+	fields := []zap.Field{
+		zap.String("repository", repository),
+		zap.String("method", method),
+		zap.Duration("elapsed", elapsed),
+	}
+	if err != nil {
+		code := domain.ErrCodeInternal
+		var domainErr *domain.Error
+		if errors.As(err, &domainErr) {
+			code = domainErr.Code
+		}
+		repositoryErrorsTotal.With(repository, method, code).Inc()
+		fields = append(fields, zap.String("code", code), zap.Error(err))
+	}
+	logger.NamedFromContext(ctx, logger.ModuleDB).Debug("repository call", fields...)
+}
+
+// instrumentedUserRepository records metrics and debug logs for the calls
+// to a user repository
+type instrumentedUserRepository struct {
+	next domain.UserRepository
+}
+
+// NewInstrumentedUserRepository wraps a user repository of any backend with
+// latency and error metrics and debug logs
+func NewInstrumentedUserRepository(next domain.UserRepository) domain.UserRepository {
+	return &instrumentedUserRepository{next: next}
+}
+
+func (r *instrumentedUserRepository) Create(ctx context.Context, user *domain.User) error {
+	start := time.Now()
+	err := r.next.Create(ctx, user)
+	observeCall(ctx, "user", "Create", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) GetByID(ctx context.Context, id uint) (*domain.User, error) {
+	start := time.Now()
+	user, err := r.next.GetByID(ctx, id)
+	observeCall(ctx, "user", "GetByID", start, err)
+	return user, err
+}
+
+func (r *instrumentedUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	start := time.Now()
+	user, err := r.next.GetByEmail(ctx, email)
+	observeCall(ctx, "user", "GetByEmail", start, err)
+	return user, err
+}
+
+func (r *instrumentedUserRepository) Update(ctx context.Context, user *domain.User) error {
+	start := time.Now()
+	err := r.next.Update(ctx, user)
+	observeCall(ctx, "user", "Update", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) Delete(ctx context.Context, id uint) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	observeCall(ctx, "user", "Delete", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) List(ctx context.Context, query *domain.Query, opts domain.ListOptions, offset, limit int) ([]*domain.User, int64, error) {
+	start := time.Now()
+	users, total, err := r.next.List(ctx, query, opts, offset, limit)
+	observeCall(ctx, "user", "List", start, err)
+	return users, total, err
+}
+
+func (r *instrumentedUserRepository) Search(ctx context.Context, query string, offset, limit int) ([]*domain.User, int64, error) {
+	start := time.Now()
+	users, total, err := r.next.Search(ctx, query, offset, limit)
+	observeCall(ctx, "user", "Search", start, err)
+	return users, total, err
+}
+
+func (r *instrumentedUserRepository) ListByCursor(ctx context.Context, query *domain.Query, opts domain.ListOptions, page *domain.CursorPage) ([]*domain.User, bool, error) {
+	start := time.Now()
+	users, hasMore, err := r.next.ListByCursor(ctx, query, opts, page)
+	observeCall(ctx, "user", "ListByCursor", start, err)
+	return users, hasMore, err
+}
+
+func (r *instrumentedUserRepository) SearchByCursor(ctx context.Context, query string, page *domain.CursorPage) ([]*domain.User, bool, error) {
+	start := time.Now()
+	users, hasMore, err := r.next.SearchByCursor(ctx, query, page)
+	observeCall(ctx, "user", "SearchByCursor", start, err)
+	return users, hasMore, err
+}
+
+func (r *instrumentedUserRepository) Count(ctx context.Context, query *domain.Query) (int64, error) {
+	start := time.Now()
+	total, err := r.next.Count(ctx, query)
+	observeCall(ctx, "user", "Count", start, err)
+	return total, err
+}
+
+func (r *instrumentedUserRepository) CountCreatedByDay(ctx context.Context, since time.Time) ([]domain.DailyCount, error) {
+	start := time.Now()
+	counts, err := r.next.CountCreatedByDay(ctx, since)
+	observeCall(ctx, "user", "CountCreatedByDay", start, err)
+	return counts, err
+}
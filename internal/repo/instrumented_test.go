@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newInstrumentedUserBackend wraps the user repository on in-memory SQLite
+// in the instrumentation
+func newInstrumentedUserBackend(t *testing.T) *userRepositoryBackend {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	backend := gormUserBackend(t, db)
+	backend.repo = NewInstrumentedUserRepository(backend.repo)
+	return backend
+}
+
+// TestInstrumentedUserRepository runs the user repository suite through the
+// instrumentation, which must not change results
+func TestInstrumentedUserRepository(t *testing.T) {
+	suite.Run(t, &UserRepositoryTestSuite{open: newInstrumentedUserBackend})
+}
+
+// TestInstrumentedUserRepositoryMetrics tests that calls and their domain
+// error codes are recorded
+func TestInstrumentedUserRepositoryMetrics(t *testing.T) {
+	backend := newInstrumentedUserBackend(t)
+	defer backend.close()
+
+	_, err := backend.repo.GetByID(context.Background(), 42)
+	require.Error(t, err)
+
+	var b strings.Builder
+	_, err = metrics.Default.WriteTo(&b)
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `repository_call_duration_seconds_count{repository="user",method="GetByID"} `)
+	assert.Contains(t, b.String(), `repository_errors_total{repository="user",method="GetByID",code="NOT_FOUND"} `)
+}
@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// invitationGormRepository implements InvitationRepository for GORM-based databases
+type invitationGormRepository struct {
+	*GormRepository[domain.Invitation]
+}
+
+// NewInvitationGormRepository creates a new GORM-based invitation repository
+func NewInvitationGormRepository(db *gorm.DB) domain.InvitationRepository {
+	return &invitationGormRepository{
+		GormRepository: NewGormRepository[domain.Invitation](db, Entity{
+			Name:     "invitation",
+			NotFound: domain.ErrInvitationNotFound,
+		}),
+	}
+}
+
+// GetByTokenHash retrieves an invitation by the hash of its token
+func (r *invitationGormRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	return r.First(ctx, "token_hash = ?", tokenHash)
+}
+
+// DeleteByOrganization deletes every invitation to an organization
+func (r *invitationGormRepository) DeleteByOrganization(ctx context.Context, orgID uint) error {
+	if err := r.DB(ctx).Where("organization_id = ?", orgID).Delete(&domain.Invitation{}).Error; err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to delete invitations")
+	}
+	return nil
+}
+
+// ListPending retrieves the open invitations to an organization
+func (r *invitationGormRepository) ListPending(ctx context.Context, orgID uint) ([]*domain.Invitation, error) {
+	var invitations []*domain.Invitation
+	err := r.DB(ctx).
+		Where("organization_id = ? AND accepted_at IS NULL AND expires_at > ?", orgID, time.Now()).
+		Order("created_at DESC, id DESC").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list invitations")
+	}
+	return invitations, nil
+}
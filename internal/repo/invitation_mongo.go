@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// invitationMongoRepository implements InvitationRepository for MongoDB
+type invitationMongoRepository struct {
+	db   *mongo.Database
+	docs *MongoRepository[domain.Invitation]
+}
+
+// NewInvitationMongoRepository creates a new MongoDB-based invitation repository
+func NewInvitationMongoRepository(db *mongo.Database) domain.InvitationRepository {
+	return &invitationMongoRepository{
+		db: db,
+		docs: NewMongoRepository[domain.Invitation](db.Collection(domain.TableName(domain.Invitation{})), Entity{
+			Name:     "invitation",
+			NotFound: domain.ErrInvitationNotFound,
+		}),
+	}
+}
+
+// Create creates a new invitation with the next sequential ID
+func (r *invitationMongoRepository) Create(ctx context.Context, invitation *domain.Invitation) error {
+	id, err := NextMongoID(ctx, r.db, domain.TableName(domain.Invitation{}))
+	if err != nil {
+		return err
+	}
+
+	invitation.ID = id
+	stampCreated(&invitation.CreatedAt, nil)
+	_, err = r.docs.Create(ctx, invitation)
+	return err
+}
+
+// GetByID retrieves an invitation by ID
+func (r *invitationMongoRepository) GetByID(ctx context.Context, id uint) (*domain.Invitation, error) {
+	return r.docs.FindOne(ctx, bson.M{"id": id})
+}
+
+// GetByTokenHash retrieves an invitation by the hash of its token
+func (r *invitationMongoRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	return r.docs.FindOne(ctx, bson.M{"token_hash": tokenHash})
+}
+
+// Update replaces an existing invitation
+func (r *invitationMongoRepository) Update(ctx context.Context, invitation *domain.Invitation) error {
+	return r.docs.Update(ctx, bson.M{"id": invitation.ID}, bson.M{"$set": invitation})
+}
+
+// Delete deletes an invitation
+func (r *invitationMongoRepository) Delete(ctx context.Context, id uint) error {
+	return r.docs.Delete(ctx, bson.M{"id": id})
+}
+
+// DeleteByOrganization deletes every invitation to an organization
+func (r *invitationMongoRepository) DeleteByOrganization(ctx context.Context, orgID uint) error {
+	if _, err := r.docs.Collection().DeleteMany(ctx, bson.M{"organization_id": orgID}); err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to delete invitations")
+	}
+	return nil
+}
+
+// ListPending retrieves the open invitations to an organization
+func (r *invitationMongoRepository) ListPending(ctx context.Context, orgID uint) ([]*domain.Invitation, error) {
+	filter := bson.M{
+		"organization_id": orgID,
+		"accepted_at":     bson.M{"$exists": false},
+		"expires_at":      bson.M{"$gt": time.Now()},
+	}
+	sort := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "id", Value: -1}})
+	return r.docs.Find(ctx, filter, sort)
+}
@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// inviteGormRepository implements InviteRepository for GORM-based databases
+type inviteGormRepository struct {
+	*GormRepository[domain.Invite]
+}
+
+// NewInviteGormRepository creates a new GORM-based invite repository
+func NewInviteGormRepository(db *gorm.DB) domain.InviteRepository {
+	return &inviteGormRepository{
+		GormRepository: NewGormRepository[domain.Invite](db, Entity{
+			Name:         "invite",
+			NotFound:     domain.ErrInviteNotFound,
+			DefaultOrder: "created_at DESC, id DESC",
+		}),
+	}
+}
+
+// GetByTokenHash retrieves an invite by the hash of its code
+func (r *inviteGormRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invite, error) {
+	return r.First(ctx, "token_hash = ?", tokenHash)
+}
+
+// List retrieves invites matching the filter with pagination, newest first
+func (r *inviteGormRepository) List(ctx context.Context, filter domain.InviteFilter, offset, limit int) ([]*domain.Invite, int64, error) {
+	builder := r.Filtered(ctx, nil)
+	if filter.Email != "" {
+		builder = builder.Where("email = ?", strings.ToLower(strings.TrimSpace(filter.Email)))
+	}
+
+	now := time.Now()
+	switch filter.Status {
+	case domain.InviteStatusPending:
+		builder = builder.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", now)
+	case domain.InviteStatusAccepted:
+		builder = builder.Where("accepted_at IS NOT NULL")
+	case domain.InviteStatusRevoked:
+		builder = builder.Where("accepted_at IS NULL AND revoked_at IS NOT NULL")
+	case domain.InviteStatusExpired:
+		builder = builder.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at <= ?", now)
+	}
+
+	return r.Paginate(ctx, builder, nil, offset, limit)
+}
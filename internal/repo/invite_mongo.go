@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// inviteMongoRepository implements InviteRepository for MongoDB
+type inviteMongoRepository struct {
+	db   *mongo.Database
+	docs *MongoRepository[domain.Invite]
+}
+
+// NewInviteMongoRepository creates a new MongoDB-based invite repository
+func NewInviteMongoRepository(db *mongo.Database) domain.InviteRepository {
+	return &inviteMongoRepository{
+		db: db,
+		docs: NewMongoRepository[domain.Invite](db.Collection(domain.TableName(domain.Invite{})), Entity{
+			Name:     "invite",
+			NotFound: domain.ErrInviteNotFound,
+		}),
+	}
+}
+
+// Create creates a new invite with the next sequential ID
+func (r *inviteMongoRepository) Create(ctx context.Context, invite *domain.Invite) error {
+	id, err := NextMongoID(ctx, r.db, domain.TableName(domain.Invite{}))
+	if err != nil {
+		return err
+	}
+
+	invite.ID = id
+	stampCreated(&invite.CreatedAt, nil)
+	_, err = r.docs.Create(ctx, invite)
+	return err
+}
+
+// GetByID retrieves an invite by ID
+func (r *inviteMongoRepository) GetByID(ctx context.Context, id uint) (*domain.Invite, error) {
+	return r.docs.FindOne(ctx, bson.M{"id": id})
+}
+
+// GetByTokenHash retrieves an invite by the hash of its code
+func (r *inviteMongoRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invite, error) {
+	return r.docs.FindOne(ctx, bson.M{"token_hash": tokenHash})
+}
+
+// Update replaces an existing invite
+func (r *inviteMongoRepository) Update(ctx context.Context, invite *domain.Invite) error {
+	return r.docs.Update(ctx, bson.M{"id": invite.ID}, bson.M{"$set": invite})
+}
+
+// List retrieves invites matching the filter with pagination, newest first
+func (r *inviteMongoRepository) List(ctx context.Context, filter domain.InviteFilter, offset, limit int) ([]*domain.Invite, int64, error) {
+	query := bson.M{}
+	if filter.Email != "" {
+		query["email"] = strings.ToLower(strings.TrimSpace(filter.Email))
+	}
+
+	now := time.Now()
+	switch filter.Status {
+	case domain.InviteStatusPending:
+		query["accepted_at"] = bson.M{"$exists": false}
+		query["revoked_at"] = bson.M{"$exists": false}
+		query["expires_at"] = bson.M{"$gt": now}
+	case domain.InviteStatusAccepted:
+		query["accepted_at"] = bson.M{"$exists": true}
+	case domain.InviteStatusRevoked:
+		query["accepted_at"] = bson.M{"$exists": false}
+		query["revoked_at"] = bson.M{"$exists": true}
+	case domain.InviteStatusExpired:
+		query["accepted_at"] = bson.M{"$exists": false}
+		query["revoked_at"] = bson.M{"$exists": false}
+		query["expires_at"] = bson.M{"$lte": now}
+	}
+
+	sort := bson.D{{Key: "created_at", Value: -1}, {Key: "id", Value: -1}}
+	return r.docs.List(ctx, query, sort, offset, limit)
+}
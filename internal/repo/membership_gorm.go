@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// membershipGormRepository implements MembershipRepository for GORM-based databases
+type membershipGormRepository struct {
+	*GormRepository[domain.Membership]
+}
+
+// NewMembershipGormRepository creates a new GORM-based membership repository
+func NewMembershipGormRepository(db *gorm.DB) domain.MembershipRepository {
+	return &membershipGormRepository{
+		GormRepository: NewGormRepository[domain.Membership](db, Entity{
+			Name:         "membership",
+			NotFound:     domain.ErrMembershipNotFound,
+			Conflict:     domain.ErrAlreadyMember,
+			DefaultOrder: "created_at ASC, id ASC",
+		}),
+	}
+}
+
+// Get retrieves a user's membership of an organization
+func (r *membershipGormRepository) Get(ctx context.Context, orgID, userID uint) (*domain.Membership, error) {
+	return r.First(ctx, "organization_id = ? AND user_id = ?", orgID, userID)
+}
+
+// Delete removes a user from an organization
+func (r *membershipGormRepository) Delete(ctx context.Context, orgID, userID uint) error {
+	result := r.DB(ctx).Where("organization_id = ? AND user_id = ?", orgID, userID).Delete(&domain.Membership{})
+	if result.Error != nil {
+		return domain.WrapError(result.Error, domain.ErrCodeDatabase, "Failed to delete membership")
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrMembershipNotFound
+	}
+	return nil
+}
+
+// DeleteByOrganization removes every member of an organization
+func (r *membershipGormRepository) DeleteByOrganization(ctx context.Context, orgID uint) error {
+	if err := r.DB(ctx).Where("organization_id = ?", orgID).Delete(&domain.Membership{}).Error; err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to delete memberships")
+	}
+	return nil
+}
+
+// ListByOrganization retrieves the members of an organization with their users
+func (r *membershipGormRepository) ListByOrganization(ctx context.Context, orgID uint, offset, limit int) ([]*domain.Membership, int64, error) {
+	builder := r.Filtered(ctx, nil).Where("organization_id = ?", orgID).Preload("User")
+	return r.Paginate(ctx, builder, nil, offset, limit)
+}
+
+// ListByUser retrieves a user's memberships with their organizations
+func (r *membershipGormRepository) ListByUser(ctx context.Context, userID uint, offset, limit int) ([]*domain.Membership, int64, error) {
+	builder := r.Filtered(ctx, nil).Where("user_id = ?", userID).Preload("Organization")
+	return r.Paginate(ctx, builder, nil, offset, limit)
+}
+
+// CountByRole counts the members of an organization with a role
+func (r *membershipGormRepository) CountByRole(ctx context.Context, orgID uint, role string) (int64, error) {
+	var total int64
+	err := r.Filtered(ctx, nil).Where("organization_id = ? AND role = ?", orgID, role).Count(&total).Error
+	if err != nil {
+		return 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count memberships")
+	}
+	return total, nil
+}
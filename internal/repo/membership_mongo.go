@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// membershipMongoRepository implements MembershipRepository for MongoDB. Users
+// and organizations are not embedded; the service resolves them by ID.
+type membershipMongoRepository struct {
+	db   *mongo.Database
+	docs *MongoRepository[domain.Membership]
+}
+
+// NewMembershipMongoRepository creates a new MongoDB-based membership repository
+func NewMembershipMongoRepository(db *mongo.Database) domain.MembershipRepository {
+	return &membershipMongoRepository{
+		db: db,
+		docs: NewMongoRepository[domain.Membership](db.Collection(domain.TableName(domain.Membership{})), Entity{
+			Name:     "membership",
+			NotFound: domain.ErrMembershipNotFound,
+			Conflict: domain.ErrAlreadyMember,
+		}),
+	}
+}
+
+// Create adds a user to an organization with the next sequential ID
+func (r *membershipMongoRepository) Create(ctx context.Context, membership *domain.Membership) error {
+	id, err := NextMongoID(ctx, r.db, domain.TableName(domain.Membership{}))
+	if err != nil {
+		return err
+	}
+
+	membership.ID = id
+	stampCreated(&membership.CreatedAt, &membership.UpdatedAt)
+	_, err = r.docs.Create(ctx, membership)
+	return err
+}
+
+// Get retrieves a user's membership of an organization
+func (r *membershipMongoRepository) Get(ctx context.Context, orgID, userID uint) (*domain.Membership, error) {
+	return r.docs.FindOne(ctx, bson.M{"organization_id": orgID, "user_id": userID})
+}
+
+// Update replaces an existing membership
+func (r *membershipMongoRepository) Update(ctx context.Context, membership *domain.Membership) error {
+	membership.UpdatedAt = time.Now()
+	return r.docs.Update(ctx, bson.M{"id": membership.ID}, bson.M{"$set": membership})
+}
+
+// Delete removes a user from an organization
+func (r *membershipMongoRepository) Delete(ctx context.Context, orgID, userID uint) error {
+	return r.docs.Delete(ctx, bson.M{"organization_id": orgID, "user_id": userID})
+}
+
+// DeleteByOrganization removes every member of an organization
+func (r *membershipMongoRepository) DeleteByOrganization(ctx context.Context, orgID uint) error {
+	if _, err := r.docs.Collection().DeleteMany(ctx, bson.M{"organization_id": orgID}); err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to delete memberships")
+	}
+	return nil
+}
+
+// ListByOrganization retrieves the members of an organization with pagination
+func (r *membershipMongoRepository) ListByOrganization(ctx context.Context, orgID uint, offset, limit int) ([]*domain.Membership, int64, error) {
+	sort := bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}}
+	return r.docs.List(ctx, bson.M{"organization_id": orgID}, sort, offset, limit)
+}
+
+// ListByUser retrieves a user's memberships with pagination
+func (r *membershipMongoRepository) ListByUser(ctx context.Context, userID uint, offset, limit int) ([]*domain.Membership, int64, error) {
+	sort := bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}}
+	return r.docs.List(ctx, bson.M{"user_id": userID}, sort, offset, limit)
+}
+
+// CountByRole counts the members of an organization with a role
+func (r *membershipMongoRepository) CountByRole(ctx context.Context, orgID uint, role string) (int64, error) {
+	return r.docs.Count(ctx, bson.M{"organization_id": orgID, "role": role})
+}
@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// notificationGormRepository implements NotificationRepository for GORM-based databases
+type notificationGormRepository struct {
+	*GormRepository[domain.Notification]
+}
+
+// NewNotificationGormRepository creates a new GORM-based notification repository
+func NewNotificationGormRepository(db *gorm.DB) domain.NotificationRepository {
+	return &notificationGormRepository{
+		GormRepository: NewGormRepository[domain.Notification](db, Entity{
+			Name:         "notification",
+			NotFound:     domain.ErrNotificationNotFound,
+			DefaultOrder: "created_at DESC, id DESC",
+		}),
+	}
+}
+
+// ListByUser retrieves a user's notifications with pagination, newest first
+func (r *notificationGormRepository) ListByUser(ctx context.Context, userID uint, filter domain.NotificationFilter, offset, limit int) ([]*domain.Notification, int64, error) {
+	builder := r.Filtered(ctx, nil).Where("user_id = ?", userID)
+	if filter.UnreadOnly {
+		builder = builder.Where("read_at IS NULL")
+	}
+	return r.Paginate(ctx, builder, nil, offset, limit)
+}
+
+// CountUnread counts a user's unread notifications
+func (r *notificationGormRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var total int64
+	err := r.DB(ctx).Model(&domain.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&total).Error
+	if err != nil {
+		return 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count notifications")
+	}
+	return total, nil
+}
+
+// MarkRead marks a notification of the user as read
+func (r *notificationGormRepository) MarkRead(ctx context.Context, userID, id uint, at time.Time) error {
+	notification, err := r.First(ctx, "id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+
+	err = r.DB(ctx).Model(notification).Update("read_at", at).Error
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to update notification")
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of the user as read
+func (r *notificationGormRepository) MarkAllRead(ctx context.Context, userID uint, at time.Time) (int64, error) {
+	result := r.DB(ctx).Model(&domain.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at)
+	if result.Error != nil {
+		return 0, domain.WrapError(result.Error, domain.ErrCodeDatabase, "Failed to update notifications")
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// notificationMongoRepository implements NotificationRepository for MongoDB
+type notificationMongoRepository struct {
+	db   *mongo.Database
+	docs *MongoRepository[domain.Notification]
+}
+
+// NewNotificationMongoRepository creates a new MongoDB-based notification repository
+func NewNotificationMongoRepository(db *mongo.Database) domain.NotificationRepository {
+	return &notificationMongoRepository{
+		db: db,
+		docs: NewMongoRepository[domain.Notification](db.Collection(domain.TableName(domain.Notification{})), Entity{
+			Name:     "notification",
+			NotFound: domain.ErrNotificationNotFound,
+		}),
+	}
+}
+
+// Create creates a new notification with the next sequential ID
+func (r *notificationMongoRepository) Create(ctx context.Context, notification *domain.Notification) error {
+	id, err := NextMongoID(ctx, r.db, domain.TableName(domain.Notification{}))
+	if err != nil {
+		return err
+	}
+
+	notification.ID = id
+	stampCreated(&notification.CreatedAt, nil)
+	_, err = r.docs.Create(ctx, notification)
+	return err
+}
+
+// ListByUser retrieves a user's notifications with pagination, newest first
+func (r *notificationMongoRepository) ListByUser(ctx context.Context, userID uint, filter domain.NotificationFilter, offset, limit int) ([]*domain.Notification, int64, error) {
+	query := bson.M{"user_id": userID}
+	if filter.UnreadOnly {
+		query["read_at"] = nil
+	}
+	sort := bson.D{{Key: "created_at", Value: -1}, {Key: "id", Value: -1}}
+	return r.docs.List(ctx, query, sort, offset, limit)
+}
+
+// CountUnread counts a user's unread notifications
+func (r *notificationMongoRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	return r.docs.Count(ctx, bson.M{"user_id": userID, "read_at": nil})
+}
+
+// MarkRead marks a notification of the user as read
+func (r *notificationMongoRepository) MarkRead(ctx context.Context, userID, id uint, at time.Time) error {
+	notification, err := r.docs.FindOne(ctx, bson.M{"id": id, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return r.docs.Update(ctx, bson.M{"id": id, "user_id": userID}, bson.M{"$set": bson.M{"read_at": at}})
+}
+
+// MarkAllRead marks every unread notification of the user as read
+func (r *notificationMongoRepository) MarkAllRead(ctx context.Context, userID uint, at time.Time) (int64, error) {
+	result, err := r.docs.Collection().UpdateMany(ctx,
+		bson.M{"user_id": userID, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": at}},
+	)
+	if err != nil {
+		return 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to update notifications")
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// organizationGormRepository implements OrganizationRepository for GORM-based databases
+type organizationGormRepository struct {
+	*GormRepository[domain.Organization]
+}
+
+// NewOrganizationGormRepository creates a new GORM-based organization repository
+func NewOrganizationGormRepository(db *gorm.DB) domain.OrganizationRepository {
+	return &organizationGormRepository{
+		GormRepository: NewGormRepository[domain.Organization](db, Entity{
+			Name:     "organization",
+			NotFound: domain.ErrOrganizationNotFound,
+			Conflict: domain.ErrOrganizationExists,
+		}),
+	}
+}
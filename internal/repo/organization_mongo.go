@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// organizationMongoRepository implements OrganizationRepository for MongoDB
+type organizationMongoRepository struct {
+	db   *mongo.Database
+	docs *MongoRepository[domain.Organization]
+}
+
+// NewOrganizationMongoRepository creates a new MongoDB-based organization repository
+func NewOrganizationMongoRepository(db *mongo.Database) domain.OrganizationRepository {
+	return &organizationMongoRepository{
+		db: db,
+		docs: NewMongoRepository[domain.Organization](db.Collection(domain.TableName(domain.Organization{})), Entity{
+			Name:     "organization",
+			NotFound: domain.ErrOrganizationNotFound,
+			Conflict: domain.ErrOrganizationExists,
+		}),
+	}
+}
+
+// Create creates a new organization with the next sequential ID
+func (r *organizationMongoRepository) Create(ctx context.Context, org *domain.Organization) error {
+	id, err := NextMongoID(ctx, r.db, domain.TableName(domain.Organization{}))
+	if err != nil {
+		return err
+	}
+
+	org.ID = id
+	stampCreated(&org.CreatedAt, &org.UpdatedAt)
+	_, err = r.docs.Create(ctx, org)
+	return err
+}
+
+// GetByID retrieves an organization by ID
+func (r *organizationMongoRepository) GetByID(ctx context.Context, id uint) (*domain.Organization, error) {
+	return r.docs.FindOne(ctx, bson.M{"id": id})
+}
+
+// Update replaces an existing organization
+func (r *organizationMongoRepository) Update(ctx context.Context, org *domain.Organization) error {
+	org.UpdatedAt = time.Now()
+	return r.docs.Update(ctx, bson.M{"id": org.ID}, bson.M{"$set": org})
+}
+
+// Delete deletes an organization
+func (r *organizationMongoRepository) Delete(ctx context.Context, id uint) error {
+	return r.docs.Delete(ctx, bson.M{"id": id})
+}
@@ -0,0 +1,54 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// permissionGormRepository implements PermissionRepository for GORM-based databases
+type permissionGormRepository struct {
+	db *gorm.DB
+}
+
+// NewPermissionGormRepository creates a new GORM-based permission repository
+func NewPermissionGormRepository(db *gorm.DB) domain.PermissionRepository {
+	return &permissionGormRepository{
+		db: db,
+	}
+}
+
+// Create creates a new permission
+func (r *permissionGormRepository) Create(ctx context.Context, permission *domain.Permission) error {
+	if err := gormConn(ctx, r.db).Create(permission).Error; err != nil {
+		if isUniqueConstraintError(err) {
+			return domain.NewError(domain.ErrCodeAlreadyExists, "Permission already exists")
+		}
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create permission")
+	}
+	return nil
+}
+
+// GetByName retrieves a permission by name
+func (r *permissionGormRepository) GetByName(ctx context.Context, name string) (*domain.Permission, error) {
+	var permission domain.Permission
+	err := gormConn(ctx, r.db).Where("name = ?", name).First(&permission).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPermissionNotFound
+		}
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to get permission")
+	}
+	return &permission, nil
+}
+
+// List retrieves all permissions
+func (r *permissionGormRepository) List(ctx context.Context) ([]*domain.Permission, error) {
+	var permissions []*domain.Permission
+	if err := gormConn(ctx, r.db).Order("name ASC").Find(&permissions).Error; err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list permissions")
+	}
+	return permissions, nil
+}
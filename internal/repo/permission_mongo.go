@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// permissionMongoRepository implements PermissionRepository for MongoDB
+type permissionMongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPermissionMongoRepository creates a new MongoDB-based permission repository
+func NewPermissionMongoRepository(db *mongo.Database) domain.PermissionRepository {
+	return &permissionMongoRepository{
+		collection: db.Collection(domain.TableName(domain.Permission{})),
+	}
+}
+
+// Create creates a new permission
+func (r *permissionMongoRepository) Create(ctx context.Context, permission *domain.Permission) error {
+	stampCreated(&permission.CreatedAt, nil)
+
+	if _, err := r.collection.InsertOne(ctx, permission); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.NewError(domain.ErrCodeAlreadyExists, "Permission already exists")
+		}
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create permission")
+	}
+	return nil
+}
+
+// GetByName retrieves a permission by name
+func (r *permissionMongoRepository) GetByName(ctx context.Context, name string) (*domain.Permission, error) {
+	var permission domain.Permission
+	err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&permission)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrPermissionNotFound
+		}
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to get permission")
+	}
+	return &permission, nil
+}
+
+// List retrieves all permissions
+func (r *permissionMongoRepository) List(ctx context.Context) ([]*domain.Permission, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list permissions")
+	}
+	defer cursor.Close(ctx)
+
+	var permissions []*domain.Permission
+	if err := cursor.All(ctx, &permissions); err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to decode permissions")
+	}
+	return permissions, nil
+}
@@ -0,0 +1,38 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// projectGormRepository implements ProjectRepository for GORM-based databases
+type projectGormRepository struct {
+	*GormRepository[domain.Project]
+}
+
+// NewProjectGormRepository creates a new GORM-based project repository
+func NewProjectGormRepository(db *gorm.DB) domain.ProjectRepository {
+	return &projectGormRepository{
+		GormRepository: NewGormRepository[domain.Project](db, Entity{
+			Name:         "project",
+			NotFound:     domain.ErrProjectNotFound,
+			DefaultOrder: "created_at DESC, id DESC",
+		}),
+	}
+}
+
+// GetByID retrieves a project by ID together with its owner
+func (r *projectGormRepository) GetByID(ctx context.Context, id uint) (*domain.Project, error) {
+	var project domain.Project
+	if err := r.DB(ctx).Preload("Owner").First(&project, id).Error; err != nil {
+		return nil, r.notFoundOr(err, "Failed to get project by ID")
+	}
+	return &project, nil
+}
+
+// List retrieves projects matching the query with pagination, loading their owners
+func (r *projectGormRepository) List(ctx context.Context, query *domain.Query, offset, limit int) ([]*domain.Project, int64, error) {
+	return r.Paginate(ctx, r.Filtered(ctx, query).Preload("Owner"), query, offset, limit)
+}
@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// ProjectGormRepositoryTestSuite defines the test suite for project GORM repository
+type ProjectGormRepositoryTestSuite struct {
+	suite.Suite
+	db    *gorm.DB
+	repo  domain.ProjectRepository
+	alice *domain.User
+	bob   *domain.User
+}
+
+// SetupSuite sets up the test suite
+func (suite *ProjectGormRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(suite.T(), err)
+
+	err = db.AutoMigrate(&domain.User{}, &domain.Project{})
+	require.NoError(suite.T(), err)
+
+	suite.alice = &domain.User{Email: "alice@example.com", Password: "hashedpassword", Name: "Alice", Active: true}
+	suite.bob = &domain.User{Email: "bob@example.com", Password: "hashedpassword", Name: "Bob", Active: true}
+	require.NoError(suite.T(), db.Create(suite.alice).Error)
+	require.NoError(suite.T(), db.Create(suite.bob).Error)
+
+	suite.db = db
+	suite.repo = NewProjectGormRepository(db)
+}
+
+// TearDownSuite tears down the test suite
+func (suite *ProjectGormRepositoryTestSuite) TearDownSuite() {
+	sqlDB, err := suite.db.DB()
+	require.NoError(suite.T(), err)
+	sqlDB.Close()
+}
+
+// SetupTest sets up each test
+func (suite *ProjectGormRepositoryTestSuite) SetupTest() {
+	suite.db.Exec("DELETE FROM projects")
+}
+
+// TestCRUD tests creating, reading, updating and deleting a project
+func (suite *ProjectGormRepositoryTestSuite) TestCRUD() {
+	ctx := context.Background()
+
+	project := &domain.Project{OwnerID: suite.alice.ID, Name: "Website", Status: domain.ProjectStatusActive}
+	require.NoError(suite.T(), suite.repo.Create(ctx, project))
+	assert.NotZero(suite.T(), project.ID)
+
+	retrieved, err := suite.repo.GetByID(ctx, project.ID)
+	require.NoError(suite.T(), err)
+	require.NotNil(suite.T(), retrieved.Owner, "owner is preloaded")
+	assert.Equal(suite.T(), "Alice", retrieved.Owner.Name)
+
+	// Saving a project with a preloaded owner must not write the owner back
+	retrieved.Name = "Website v2"
+	retrieved.Owner.Name = "Mallory"
+	require.NoError(suite.T(), suite.repo.Update(ctx, retrieved))
+
+	updated, err := suite.repo.GetByID(ctx, project.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Website v2", updated.Name)
+	assert.Equal(suite.T(), "Alice", updated.Owner.Name)
+
+	require.NoError(suite.T(), suite.repo.Delete(ctx, project.ID))
+	_, err = suite.repo.GetByID(ctx, project.ID)
+	assert.Equal(suite.T(), domain.ErrProjectNotFound, err)
+	assert.Equal(suite.T(), domain.ErrProjectNotFound, suite.repo.Delete(ctx, project.ID))
+}
+
+// TestList tests listing projects filtered by owner and status
+func (suite *ProjectGormRepositoryTestSuite) TestList() {
+	ctx := context.Background()
+
+	require.NoError(suite.T(), suite.repo.Create(ctx, &domain.Project{OwnerID: suite.alice.ID, Name: "A1", Status: domain.ProjectStatusActive}))
+	require.NoError(suite.T(), suite.repo.Create(ctx, &domain.Project{OwnerID: suite.alice.ID, Name: "A2", Status: domain.ProjectStatusArchived}))
+	require.NoError(suite.T(), suite.repo.Create(ctx, &domain.Project{OwnerID: suite.bob.ID, Name: "B1", Status: domain.ProjectStatusActive}))
+
+	projects, total, err := suite.repo.List(ctx, nil, 0, 10)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), total)
+	assert.Len(suite.T(), projects, 3)
+
+	query, err := (&domain.ProjectListFilter{OwnerID: suite.alice.ID, Sort: "name"}).Query()
+	require.NoError(suite.T(), err)
+	projects, total, err = suite.repo.List(ctx, query, 0, 10)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+	require.Len(suite.T(), projects, 2)
+	assert.Equal(suite.T(), "A1", projects[0].Name)
+	assert.Equal(suite.T(), "Alice", projects[0].Owner.Name)
+
+	query, err = (&domain.ProjectListFilter{Status: domain.ProjectStatusActive}).Query()
+	require.NoError(suite.T(), err)
+	_, total, err = suite.repo.List(ctx, query, 0, 10)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+
+	_, err = (&domain.ProjectListFilter{Status: "deleted"}).Query()
+	assert.Error(suite.T(), err)
+}
+
+// TestProjectGormRepository runs the test suite
+func TestProjectGormRepository(t *testing.T) {
+	suite.Run(t, new(ProjectGormRepositoryTestSuite))
+}
@@ -0,0 +1,64 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// projectMongoRepository implements ProjectRepository for MongoDB. Owners are
+// not embedded in project documents; the service resolves them by OwnerID.
+type projectMongoRepository struct {
+	db   *mongo.Database
+	docs *MongoRepository[domain.Project]
+}
+
+// NewProjectMongoRepository creates a new MongoDB-based project repository
+func NewProjectMongoRepository(db *mongo.Database) domain.ProjectRepository {
+	return &projectMongoRepository{
+		db: db,
+		docs: NewMongoRepository[domain.Project](db.Collection(domain.TableName(domain.Project{})), Entity{
+			Name:     "project",
+			NotFound: domain.ErrProjectNotFound,
+		}),
+	}
+}
+
+// Create creates a new project with the next sequential ID
+func (r *projectMongoRepository) Create(ctx context.Context, project *domain.Project) error {
+	id, err := NextMongoID(ctx, r.db, domain.TableName(domain.Project{}))
+	if err != nil {
+		return err
+	}
+
+	project.ID = id
+	stampCreated(&project.CreatedAt, &project.UpdatedAt)
+	_, err = r.docs.Create(ctx, project)
+	return err
+}
+
+// GetByID retrieves a project by ID
+func (r *projectMongoRepository) GetByID(ctx context.Context, id uint) (*domain.Project, error) {
+	return r.docs.FindOne(ctx, bson.M{"id": id})
+}
+
+// Update replaces an existing project
+func (r *projectMongoRepository) Update(ctx context.Context, project *domain.Project) error {
+	project.UpdatedAt = time.Now()
+	return r.docs.Update(ctx, bson.M{"id": project.ID}, bson.M{"$set": project})
+}
+
+// Delete deletes a project
+func (r *projectMongoRepository) Delete(ctx context.Context, id uint) error {
+	return r.docs.Delete(ctx, bson.M{"id": id})
+}
+
+// List retrieves projects matching the query with pagination
+func (r *projectMongoRepository) List(ctx context.Context, query *domain.Query, offset, limit int) ([]*domain.Project, int64, error) {
+	filter := mongoFilter(bson.M{}, query)
+	sort := mongoSort(query, bson.D{{Key: "created_at", Value: -1}, {Key: "id", Value: -1}})
+	return r.docs.List(ctx, filter, sort, offset, limit)
+}
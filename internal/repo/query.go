@@ -0,0 +1,89 @@
+package repo
+
+import (
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mongoOperators maps filter operators to MongoDB query operators
+var mongoOperators = map[domain.FilterOp]string{
+	domain.OpEq:  "$eq",
+	domain.OpGt:  "$gt",
+	domain.OpGte: "$gte",
+	domain.OpLt:  "$lt",
+	domain.OpLte: "$lte",
+}
+
+// applyGormFilters adds the query's filters as WHERE conditions
+func applyGormFilters(db *gorm.DB, query *domain.Query) *gorm.DB {
+	for _, f := range query.Filters() {
+		column := clause.Column{Name: f.Field}
+		switch f.Op {
+		case domain.OpEq:
+			db = db.Where(clause.Eq{Column: column, Value: f.Value})
+		case domain.OpGt:
+			db = db.Where(clause.Gt{Column: column, Value: f.Value})
+		case domain.OpGte:
+			db = db.Where(clause.Gte{Column: column, Value: f.Value})
+		case domain.OpLt:
+			db = db.Where(clause.Lt{Column: column, Value: f.Value})
+		case domain.OpLte:
+			db = db.Where(clause.Lte{Column: column, Value: f.Value})
+		}
+	}
+	return db
+}
+
+// applyGormSorts orders by the query's sort terms, falling back to the given default
+func applyGormSorts(db *gorm.DB, query *domain.Query, fallback string) *gorm.DB {
+	sorts := query.Sorts()
+	if len(sorts) == 0 {
+		return db.Order(fallback)
+	}
+	for _, s := range sorts {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Field}, Desc: s.Desc})
+	}
+	return db
+}
+
+// mongoFilter merges the query's filters into a MongoDB filter document
+func mongoFilter(filter bson.M, query *domain.Query) bson.M {
+	for _, f := range query.Filters() {
+		mongoCondition(filter, f.Field, mongoOperators[f.Op], f.Value)
+	}
+	return filter
+}
+
+// mongoCondition adds an operator condition on field. Equality replaces any
+// previous condition on the field; range operators are combined.
+func mongoCondition(filter bson.M, field, operator string, value any) {
+	if operator == "$eq" {
+		filter[field] = value
+		return
+	}
+	cond, ok := filter[field].(bson.M)
+	if !ok {
+		cond = bson.M{}
+		filter[field] = cond
+	}
+	cond[operator] = value
+}
+
+// mongoSort converts the query's sort terms, falling back to the given default
+func mongoSort(query *domain.Query, fallback bson.D) bson.D {
+	sorts := query.Sorts()
+	if len(sorts) == 0 {
+		return fallback
+	}
+	sort := make(bson.D, 0, len(sorts))
+	for _, s := range sorts {
+		direction := 1
+		if s.Desc {
+			direction = -1
+		}
+		sort = append(sort, bson.E{Key: s.Field, Value: direction})
+	}
+	return sort
+}
@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// refreshTokenGormRepository implements RefreshTokenRepository for GORM-based databases
+type refreshTokenGormRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenGormRepository creates a new GORM-based refresh token repository
+func NewRefreshTokenGormRepository(db *gorm.DB) domain.RefreshTokenRepository {
+	return &refreshTokenGormRepository{
+		db: db,
+	}
+}
+
+// Create stores a new refresh token
+func (r *refreshTokenGormRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	if err := gormConn(ctx, r.db).Create(token).Error; err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create refresh token")
+	}
+	return nil
+}
+
+// GetByHash retrieves a refresh token by its hash
+func (r *refreshTokenGormRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	err := gormConn(ctx, r.db).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTokenNotFound
+		}
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to get refresh token")
+	}
+	return &token, nil
+}
+
+// Revoke marks a refresh token as revoked
+func (r *refreshTokenGormRepository) Revoke(ctx context.Context, tokenHash string) error {
+	result := gormConn(ctx, r.db).Model(&domain.RefreshToken{}).
+		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return domain.WrapError(result.Error, domain.ErrCodeDatabase, "Failed to revoke refresh token")
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTokenNotFound
+	}
+	return nil
+}
+
+// RevokeAllForUser revokes every active refresh token of a user
+func (r *refreshTokenGormRepository) RevokeAllForUser(ctx context.Context, userID uint) error {
+	err := gormConn(ctx, r.db).Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to revoke refresh tokens")
+	}
+	return nil
+}
+
+// ListActiveForUser retrieves a user's unrevoked, unexpired tokens, newest first
+func (r *refreshTokenGormRepository) ListActiveForUser(ctx context.Context, userID uint) ([]*domain.RefreshToken, error) {
+	var tokens []*domain.RefreshToken
+	err := gormConn(ctx, r.db).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list refresh tokens")
+	}
+	return tokens, nil
+}
+
+// RevokeSession revokes the active tokens of a user's session
+func (r *refreshTokenGormRepository) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	result := gormConn(ctx, r.db).Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND session_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, sessionID, time.Now()).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return domain.WrapError(result.Error, domain.ErrCodeDatabase, "Failed to revoke session")
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTokenNotFound
+	}
+	return nil
+}
+
+// DeleteExpired removes tokens that expired before the given time
+func (r *refreshTokenGormRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := gormConn(ctx, r.db).Where("expires_at < ?", before).Delete(&domain.RefreshToken{})
+	if result.Error != nil {
+		return 0, domain.WrapError(result.Error, domain.ErrCodeDatabase, "Failed to delete expired refresh tokens")
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,195 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// RefreshTokenGormRepositoryTestSuite defines the test suite for refresh token GORM repository
+type RefreshTokenGormRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo domain.RefreshTokenRepository
+}
+
+// SetupSuite sets up the test suite
+func (suite *RefreshTokenGormRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(suite.T(), err)
+
+	err = db.AutoMigrate(&domain.RefreshToken{})
+	require.NoError(suite.T(), err)
+
+	suite.db = db
+	suite.repo = NewRefreshTokenGormRepository(db)
+}
+
+// TearDownSuite tears down the test suite
+func (suite *RefreshTokenGormRepositoryTestSuite) TearDownSuite() {
+	sqlDB, err := suite.db.DB()
+	require.NoError(suite.T(), err)
+	sqlDB.Close()
+}
+
+// SetupTest sets up each test
+func (suite *RefreshTokenGormRepositoryTestSuite) SetupTest() {
+	suite.db.Exec("DELETE FROM refresh_tokens")
+}
+
+// TestCreateAndGetByHash tests storing and retrieving a refresh token
+func (suite *RefreshTokenGormRepositoryTestSuite) TestCreateAndGetByHash() {
+	ctx := context.Background()
+
+	token := &domain.RefreshToken{UserID: 1, TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(suite.T(), suite.repo.Create(ctx, token))
+
+	retrieved, err := suite.repo.GetByHash(ctx, "hash-1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(1), retrieved.UserID)
+	assert.False(suite.T(), retrieved.IsRevoked())
+
+	_, err = suite.repo.GetByHash(ctx, "missing")
+	assert.Equal(suite.T(), domain.ErrTokenNotFound, err)
+}
+
+// TestRevoke tests revoking a single refresh token
+func (suite *RefreshTokenGormRepositoryTestSuite) TestRevoke() {
+	ctx := context.Background()
+
+	token := &domain.RefreshToken{UserID: 1, TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(suite.T(), suite.repo.Create(ctx, token))
+
+	assert.NoError(suite.T(), suite.repo.Revoke(ctx, "hash-1"))
+
+	retrieved, err := suite.repo.GetByHash(ctx, "hash-1")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), retrieved.IsRevoked())
+
+	// Revoking twice reports the token as not found
+	assert.Equal(suite.T(), domain.ErrTokenNotFound, suite.repo.Revoke(ctx, "hash-1"))
+}
+
+// TestRevokeAllForUser tests revoking every token of a user
+func (suite *RefreshTokenGormRepositoryTestSuite) TestRevokeAllForUser() {
+	ctx := context.Background()
+
+	tokens := []*domain.RefreshToken{
+		{UserID: 1, TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour)},
+		{UserID: 1, TokenHash: "hash-2", ExpiresAt: time.Now().Add(time.Hour)},
+		{UserID: 2, TokenHash: "hash-3", ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	for _, token := range tokens {
+		require.NoError(suite.T(), suite.repo.Create(ctx, token))
+	}
+
+	assert.NoError(suite.T(), suite.repo.RevokeAllForUser(ctx, 1))
+
+	for hash, revoked := range map[string]bool{"hash-1": true, "hash-2": true, "hash-3": false} {
+		retrieved, err := suite.repo.GetByHash(ctx, hash)
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), revoked, retrieved.IsRevoked(), hash)
+	}
+}
+
+// TestSessions tests listing and revoking a user's sessions
+func (suite *RefreshTokenGormRepositoryTestSuite) TestSessions() {
+	ctx := context.Background()
+
+	now := time.Now()
+	tokens := []*domain.RefreshToken{
+		{UserID: 1, SessionID: "s1", TokenHash: "hash-1", ExpiresAt: now.Add(time.Hour), CreatedAt: now.Add(-2 * time.Minute)},
+		{UserID: 1, SessionID: "s2", TokenHash: "hash-2", ExpiresAt: now.Add(time.Hour), CreatedAt: now.Add(-time.Minute)},
+		{UserID: 1, SessionID: "s3", TokenHash: "hash-3", ExpiresAt: now.Add(-time.Hour)},
+		{UserID: 2, SessionID: "s4", TokenHash: "hash-4", ExpiresAt: now.Add(time.Hour)},
+	}
+	for _, token := range tokens {
+		require.NoError(suite.T(), suite.repo.Create(ctx, token))
+	}
+
+	active, err := suite.repo.ListActiveForUser(ctx, 1)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), active, 2)
+	assert.Equal(suite.T(), "s2", active[0].SessionID)
+	assert.Equal(suite.T(), "s1", active[1].SessionID)
+
+	assert.NoError(suite.T(), suite.repo.RevokeSession(ctx, 1, "s1"))
+
+	// Sessions of other users and ended sessions are not found
+	assert.Equal(suite.T(), domain.ErrTokenNotFound, suite.repo.RevokeSession(ctx, 1, "s4"))
+	assert.Equal(suite.T(), domain.ErrTokenNotFound, suite.repo.RevokeSession(ctx, 1, "s1"))
+
+	active, err = suite.repo.ListActiveForUser(ctx, 1)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), active, 1)
+	assert.Equal(suite.T(), "s2", active[0].SessionID)
+}
+
+// TestDeleteExpired tests purging expired tokens
+func (suite *RefreshTokenGormRepositoryTestSuite) TestDeleteExpired() {
+	ctx := context.Background()
+
+	tokens := []*domain.RefreshToken{
+		{UserID: 1, TokenHash: "hash-1", ExpiresAt: time.Now().Add(-time.Hour)},
+		{UserID: 1, TokenHash: "hash-2", ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	for _, token := range tokens {
+		require.NoError(suite.T(), suite.repo.Create(ctx, token))
+	}
+
+	deleted, err := suite.repo.DeleteExpired(ctx, time.Now())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), deleted)
+
+	_, err = suite.repo.GetByHash(ctx, "hash-1")
+	assert.Equal(suite.T(), domain.ErrTokenNotFound, err)
+
+	_, err = suite.repo.GetByHash(ctx, "hash-2")
+	assert.NoError(suite.T(), err)
+}
+
+// TestWithinTransaction tests that repository calls join the transaction and roll back together
+func (suite *RefreshTokenGormRepositoryTestSuite) TestWithinTransaction() {
+	ctx := context.Background()
+	txManager := NewGormTxManager(suite.db)
+	expiresAt := time.Now().Add(time.Hour)
+
+	errRollback := domain.NewError(domain.ErrCodeInternal, "rollback")
+	err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		require.NoError(suite.T(), suite.repo.Create(ctx, &domain.RefreshToken{UserID: 1, TokenHash: "rolled-back", ExpiresAt: expiresAt}))
+
+		// Nested calls join the outer transaction
+		return txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := suite.repo.GetByHash(ctx, "rolled-back")
+			require.NoError(suite.T(), err)
+			return errRollback
+		})
+	})
+	assert.Equal(suite.T(), errRollback, err)
+
+	_, err = suite.repo.GetByHash(ctx, "rolled-back")
+	assert.Equal(suite.T(), domain.ErrTokenNotFound, err)
+
+	err = txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		return suite.repo.Create(ctx, &domain.RefreshToken{UserID: 1, TokenHash: "committed", ExpiresAt: expiresAt})
+	})
+	require.NoError(suite.T(), err)
+
+	_, err = suite.repo.GetByHash(ctx, "committed")
+	assert.NoError(suite.T(), err)
+}
+
+// TestRefreshTokenGormRepository runs the test suite
+func TestRefreshTokenGormRepository(t *testing.T) {
+	suite.Run(t, new(RefreshTokenGormRepositoryTestSuite))
+}
@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// refreshTokenMongoRepository implements RefreshTokenRepository for MongoDB
+type refreshTokenMongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewRefreshTokenMongoRepository creates a new MongoDB-based refresh token repository
+func NewRefreshTokenMongoRepository(db *mongo.Database) domain.RefreshTokenRepository {
+	return &refreshTokenMongoRepository{
+		collection: db.Collection(domain.TableName(domain.RefreshToken{})),
+	}
+}
+
+// Create stores a new refresh token
+func (r *refreshTokenMongoRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	stampCreated(&token.CreatedAt, nil)
+	if _, err := r.collection.InsertOne(ctx, token); err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create refresh token")
+	}
+	return nil
+}
+
+// GetByHash retrieves a refresh token by its hash
+func (r *refreshTokenMongoRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	err := database.Collection(ctx, r.collection).FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTokenNotFound
+		}
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to get refresh token")
+	}
+	return &token, nil
+}
+
+// Revoke marks a refresh token as revoked
+func (r *refreshTokenMongoRepository) Revoke(ctx context.Context, tokenHash string) error {
+	filter := bson.M{"token_hash": tokenHash, "revoked_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to revoke refresh token")
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTokenNotFound
+	}
+	return nil
+}
+
+// RevokeAllForUser revokes every active refresh token of a user
+func (r *refreshTokenMongoRepository) RevokeAllForUser(ctx context.Context, userID uint) error {
+	filter := bson.M{"user_id": userID, "revoked_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+	if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to revoke refresh tokens")
+	}
+	return nil
+}
+
+// ListActiveForUser retrieves a user's unrevoked, unexpired tokens, newest first
+func (r *refreshTokenMongoRepository) ListActiveForUser(ctx context.Context, userID uint) ([]*domain.RefreshToken, error) {
+	filter := bson.M{
+		"user_id":    userID,
+		"revoked_at": bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+	findOptions := options.Find().SetSort(bson.M{"created_at": -1})
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list refresh tokens")
+	}
+	defer cursor.Close(ctx)
+
+	var tokens []*domain.RefreshToken
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to decode refresh tokens")
+	}
+	return tokens, nil
+}
+
+// RevokeSession revokes the active tokens of a user's session
+func (r *refreshTokenMongoRepository) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	filter := bson.M{
+		"user_id":    userID,
+		"session_id": sessionID,
+		"revoked_at": bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to revoke session")
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTokenNotFound
+	}
+	return nil
+}
+
+// DeleteExpired removes tokens that expired before the given time
+func (r *refreshTokenMongoRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to delete expired refresh tokens")
+	}
+	return result.DeletedCount, nil
+}
@@ -6,36 +6,351 @@ import (
 	"github.com/luxixing/fx-gin-scaffold/internal/config"
 	"github.com/luxixing/fx-gin-scaffold/internal/domain"
 	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
-// RepositoryParams holds dependencies for repository initialization
+// RepositoryParams holds dependencies for repository initialization. DB is
+// the primary database; GORM and Mongo are the connection of each kind, nil
+// when neither DB_DRIVER nor DB_SECONDARY_DRIVER uses it.
 type RepositoryParams struct {
 	fx.In
 	Config *config.Config
 	DB     *database.Connection
+	GORM   *database.Connection `name:"gorm" optional:"true"`
+	Mongo  *database.Connection `name:"mongo" optional:"true"`
+	Logger *zap.Logger
 }
 
-// NewUserRepository creates a user repository based on the configured database driver
+// backend returns the driver and connection of the named repository, e.g.
+// users, as DB_REPOSITORY_DRIVERS sets it
+func (p RepositoryParams) backend(name string) (string, *database.Connection) {
+	driver := p.Config.RepositoryDriver(name)
+	if driver == p.Config.Database.Driver {
+		return driver, p.DB
+	}
+	if config.IsGORMDriver(driver) {
+		return driver, p.GORM
+	}
+	return driver, p.Mongo
+}
+
+// NewUserRepository creates a user repository based on its configured database driver
 func NewUserRepository(p RepositoryParams) domain.UserRepository {
+	driver, db := p.backend("users")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewUserGormRepository(db.GORM, WithFullTextSearch(p.Config.Database.PostgresFullTextSearch))
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewUserMongoRepository(database, p.Logger)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewRefreshTokenRepository creates a refresh token repository based on its configured database driver
+func NewRefreshTokenRepository(p RepositoryParams) domain.RefreshTokenRepository {
+	driver, db := p.backend("refresh_tokens")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewRefreshTokenGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewRefreshTokenMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewRoleRepository creates a role repository based on its configured database driver
+func NewRoleRepository(p RepositoryParams) domain.RoleRepository {
+	driver, db := p.backend("roles")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewRoleGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewRoleMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewPermissionRepository creates a permission repository based on its configured database driver
+func NewPermissionRepository(p RepositoryParams) domain.PermissionRepository {
+	driver, db := p.backend("permissions")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewPermissionGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewPermissionMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewAuditLogRepository creates an audit log repository based on its configured database driver
+func NewAuditLogRepository(p RepositoryParams) domain.AuditLogRepository {
+	driver, db := p.backend("audit_logs")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewAuditLogGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewAuditLogMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewProjectRepository creates a project repository based on its configured database driver
+func NewProjectRepository(p RepositoryParams) domain.ProjectRepository {
+	driver, db := p.backend("projects")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewProjectGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewProjectMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewOrganizationRepository creates an organization repository based on its configured database driver
+func NewOrganizationRepository(p RepositoryParams) domain.OrganizationRepository {
+	driver, db := p.backend("organizations")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewOrganizationGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewOrganizationMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewMembershipRepository creates a membership repository based on its configured database driver
+func NewMembershipRepository(p RepositoryParams) domain.MembershipRepository {
+	driver, db := p.backend("memberships")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewMembershipGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewMembershipMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewInvitationRepository creates an invitation repository based on its configured database driver
+func NewInvitationRepository(p RepositoryParams) domain.InvitationRepository {
+	driver, db := p.backend("invitations")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewInvitationGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewInvitationMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewInviteRepository creates an invite repository based on its configured database driver
+func NewInviteRepository(p RepositoryParams) domain.InviteRepository {
+	driver, db := p.backend("invites")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewInviteGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewInviteMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewWebhookRepository creates a webhook repository based on its configured database driver
+func NewWebhookRepository(p RepositoryParams) domain.WebhookRepository {
+	driver, db := p.backend("webhooks")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewWebhookGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewWebhookMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewWebhookDeliveryRepository creates a webhook delivery repository based on its configured database driver
+func NewWebhookDeliveryRepository(p RepositoryParams) domain.WebhookDeliveryRepository {
+	driver, db := p.backend("webhook_deliveries")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewWebhookDeliveryGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewWebhookDeliveryMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewUserSettingRepository creates a user setting repository based on its configured database driver
+func NewUserSettingRepository(p RepositoryParams) domain.UserSettingRepository {
+	driver, db := p.backend("user_settings")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewUserSettingGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewUserSettingMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewNotificationRepository creates a notification repository based on its configured database driver
+func NewNotificationRepository(p RepositoryParams) domain.NotificationRepository {
+	driver, db := p.backend("notifications")
+	switch driver {
+	case "sqlite", "postgres":
+		if db.GORM == nil {
+			panic("GORM connection is nil for " + driver)
+		}
+		return NewNotificationGormRepository(db.GORM)
+	case "mongo":
+		if db.Mongo == nil {
+			panic("MongoDB connection is nil")
+		}
+		database := db.Mongo.Database(p.Config.Database.MongoDatabase)
+		return NewNotificationMongoRepository(database)
+	default:
+		panic("unsupported database driver: " + driver)
+	}
+}
+
+// NewTxManager creates a transaction manager based on the configured database driver
+func NewTxManager(p RepositoryParams) domain.TxManager {
 	switch p.Config.Database.Driver {
 	case "sqlite", "postgres":
 		if p.DB.GORM == nil {
 			panic("GORM connection is nil for " + p.Config.Database.Driver)
 		}
-		return NewUserGormRepository(p.DB.GORM)
+		return NewGormTxManager(p.DB.GORM)
 	case "mongo":
 		if p.DB.Mongo == nil {
 			panic("MongoDB connection is nil")
 		}
-		database := p.DB.Mongo.Database(p.Config.Database.MongoDatabase)
-		return NewUserMongoRepository(database)
+		return NewMongoTxManager(p.DB.Mongo)
 	default:
 		panic("unsupported database driver: " + p.Config.Database.Driver)
 	}
 }
 
+// TokenBlacklistParams holds dependencies for token blacklist initialization
+type TokenBlacklistParams struct {
+	fx.In
+	Config *config.Config
+	Cache  cache.Client
+}
+
+// NewTokenBlacklist creates a Redis-backed token blacklist when Redis is configured,
+// falling back to an in-memory blacklist for single-node deployments
+func NewTokenBlacklist(p TokenBlacklistParams) domain.TokenBlacklist {
+	if !p.Config.IsRedisEnabled() {
+		return NewMemoryTokenBlacklist()
+	}
+	return NewCacheTokenBlacklist(p.Cache)
+}
+
 // isUniqueConstraintError checks if the error is a unique constraint violation
 func isUniqueConstraintError(err error) bool {
 	if err == nil {
@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRepositoryParamsBackend tests that repositories use the database
+// DB_REPOSITORY_DRIVERS sets for them
+func TestRepositoryParamsBackend(t *testing.T) {
+	primary, secondary := &database.Connection{}, &database.Connection{}
+	p := RepositoryParams{
+		Config: &config.Config{Database: config.DatabaseConfig{
+			Driver:            "sqlite",
+			SecondaryDriver:   "mongo",
+			RepositoryDrivers: map[string]string{"audit_logs": "mongo", "users": "sqlite"},
+		}},
+		DB:    primary,
+		GORM:  primary,
+		Mongo: secondary,
+	}
+
+	driver, db := p.backend("audit_logs")
+	assert.Equal(t, "mongo", driver)
+	assert.Same(t, secondary, db)
+
+	for _, name := range []string{"users", "projects"} {
+		driver, db = p.backend(name)
+		assert.Equal(t, "sqlite", driver)
+		assert.Same(t, primary, db)
+	}
+}
@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// roleGormRepository implements RoleRepository for GORM-based databases
+type roleGormRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleGormRepository creates a new GORM-based role repository
+func NewRoleGormRepository(db *gorm.DB) domain.RoleRepository {
+	return &roleGormRepository{
+		db: db,
+	}
+}
+
+// Create creates a new role
+func (r *roleGormRepository) Create(ctx context.Context, role *domain.Role) error {
+	if err := gormConn(ctx, r.db).Create(role).Error; err != nil {
+		if isUniqueConstraintError(err) {
+			return domain.ErrRoleExists
+		}
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create role")
+	}
+	return nil
+}
+
+// GetByName retrieves a role by name
+func (r *roleGormRepository) GetByName(ctx context.Context, name string) (*domain.Role, error) {
+	var role domain.Role
+	err := gormConn(ctx, r.db).Where("name = ?", name).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrRoleNotFound
+		}
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to get role")
+	}
+	return &role, nil
+}
+
+// List retrieves all roles
+func (r *roleGormRepository) List(ctx context.Context) ([]*domain.Role, error) {
+	var roles []*domain.Role
+	if err := gormConn(ctx, r.db).Order("name ASC").Find(&roles).Error; err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list roles")
+	}
+	return roles, nil
+}
+
+// Update updates an existing role
+func (r *roleGormRepository) Update(ctx context.Context, role *domain.Role) error {
+	result := gormConn(ctx, r.db).Save(role)
+	if result.Error != nil {
+		return domain.WrapError(result.Error, domain.ErrCodeDatabase, "Failed to update role")
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrRoleNotFound
+	}
+	return nil
+}
+
+// Delete deletes a role by name
+func (r *roleGormRepository) Delete(ctx context.Context, name string) error {
+	result := gormConn(ctx, r.db).Where("name = ?", name).Delete(&domain.Role{})
+	if result.Error != nil {
+		return domain.WrapError(result.Error, domain.ErrCodeDatabase, "Failed to delete role")
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrRoleNotFound
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// roleMongoRepository implements RoleRepository for MongoDB
+type roleMongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewRoleMongoRepository creates a new MongoDB-based role repository
+func NewRoleMongoRepository(db *mongo.Database) domain.RoleRepository {
+	return &roleMongoRepository{
+		collection: db.Collection(domain.TableName(domain.Role{})),
+	}
+}
+
+// Create creates a new role
+func (r *roleMongoRepository) Create(ctx context.Context, role *domain.Role) error {
+	stampCreated(&role.CreatedAt, &role.UpdatedAt)
+
+	if _, err := r.collection.InsertOne(ctx, role); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrRoleExists
+		}
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create role")
+	}
+	return nil
+}
+
+// GetByName retrieves a role by name
+func (r *roleMongoRepository) GetByName(ctx context.Context, name string) (*domain.Role, error) {
+	var role domain.Role
+	err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&role)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrRoleNotFound
+		}
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to get role")
+	}
+	return &role, nil
+}
+
+// List retrieves all roles
+func (r *roleMongoRepository) List(ctx context.Context) ([]*domain.Role, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list roles")
+	}
+	defer cursor.Close(ctx)
+
+	var roles []*domain.Role
+	if err := cursor.All(ctx, &roles); err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to decode roles")
+	}
+	return roles, nil
+}
+
+// Update updates an existing role
+func (r *roleMongoRepository) Update(ctx context.Context, role *domain.Role) error {
+	role.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"description": role.Description,
+			"permissions": role.Permissions,
+			"updated_at":  role.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"name": role.Name}, update)
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to update role")
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrRoleNotFound
+	}
+	return nil
+}
+
+// Delete deletes a role by name
+func (r *roleMongoRepository) Delete(ctx context.Context, name string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to delete role")
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrRoleNotFound
+	}
+	return nil
+}
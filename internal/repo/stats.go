@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+)
+
+// gormDay returns the SQL expression formatting a timestamp column as its
+// UTC day, 2006-01-02
+func gormDay(dialect, column string) string {
+	if dialect == "postgres" {
+		return "to_char(" + column + " AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+	}
+	// SQLite converts times stored with an offset to UTC
+	return "strftime('%Y-%m-%d', " + column + ")"
+}
+
+// gormCountByDay counts the rows of builder per UTC day of a timestamp
+// column since since, oldest first
+func gormCountByDay(builder *gorm.DB, column string, since time.Time) ([]domain.DailyCount, error) {
+	var counts []domain.DailyCount
+	err := builder.
+		Select(gormDay(builder.Dialector.Name(), column)+" AS date, COUNT(*) AS count").
+		Where(column+" >= ?", since).
+		Group("date").
+		Order("date").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// mongoCountByDay counts the documents matching filter per UTC day of a
+// date field since since, oldest first
+func mongoCountByDay(ctx context.Context, collection *mongo.Collection, filter bson.M, field string, since time.Time) ([]domain.DailyCount, error) {
+	match := bson.M{field: bson.M{"$gte": since}}
+	for key, value := range filter {
+		match[key] = value
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$" + field}},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := database.Collection(ctx, collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Date  string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	counts := make([]domain.DailyCount, len(results))
+	for i, result := range results {
+		counts[i] = domain.DailyCount{Date: result.Date, Count: result.Count}
+	}
+	return counts, nil
+}
@@ -0,0 +1,46 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+)
+
+// tokenBlacklistKeyPrefix is the cache key prefix for blacklisted tokens
+const tokenBlacklistKeyPrefix = "token_blacklist:"
+
+// cacheTokenBlacklist implements TokenBlacklist backed by the shared cache (Redis)
+type cacheTokenBlacklist struct {
+	cache cache.Client
+}
+
+// NewCacheTokenBlacklist creates a new cache-backed token blacklist
+func NewCacheTokenBlacklist(client cache.Client) domain.TokenBlacklist {
+	return &cacheTokenBlacklist{
+		cache: client,
+	}
+}
+
+// Add blacklists a token ID until the given expiry time
+func (b *cacheTokenBlacklist) Add(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := b.cache.Set(ctx, tokenBlacklistKeyPrefix+tokenID, "1", ttl); err != nil {
+		return domain.WrapError(err, domain.ErrCodeInternal, "Failed to blacklist token")
+	}
+	return nil
+}
+
+// Contains reports whether a token ID has been blacklisted
+func (b *cacheTokenBlacklist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	exists, err := b.cache.Exists(ctx, tokenBlacklistKeyPrefix+tokenID)
+	if err != nil {
+		return false, domain.WrapError(err, domain.ErrCodeInternal, "Failed to check token blacklist")
+	}
+	return exists, nil
+}
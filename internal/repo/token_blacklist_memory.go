@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// memoryTokenBlacklist implements TokenBlacklist in process memory
+type memoryTokenBlacklist struct {
+	mu     sync.RWMutex
+	tokens map[string]time.Time
+}
+
+// NewMemoryTokenBlacklist creates a new in-memory token blacklist
+func NewMemoryTokenBlacklist() domain.TokenBlacklist {
+	return &memoryTokenBlacklist{
+		tokens: make(map[string]time.Time),
+	}
+}
+
+// Add blacklists a token ID until the given expiry time
+func (b *memoryTokenBlacklist) Add(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Drop entries whose tokens have expired anyway
+	now := time.Now()
+	for id, exp := range b.tokens {
+		if now.After(exp) {
+			delete(b.tokens, id)
+		}
+	}
+
+	b.tokens[tokenID] = expiresAt
+	return nil
+}
+
+// Contains reports whether a token ID has been blacklisted
+func (b *memoryTokenBlacklist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	expiresAt, exists := b.tokens[tokenID]
+	return exists && time.Now().Before(expiresAt), nil
+}
@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+)
+
+// gormTxContextKey carries the active GORM transaction
+type gormTxContextKey struct{}
+
+// gormTxManager implements TxManager with GORM transactions
+type gormTxManager struct {
+	db *gorm.DB
+}
+
+// NewGormTxManager creates a GORM transaction manager
+func NewGormTxManager(db *gorm.DB) domain.TxManager {
+	return &gormTxManager{db: db}
+}
+
+// WithinTransaction runs fn in a transaction, committing when it returns nil
+func (m *gormTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(gormTxContextKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, gormTxContextKey{}, tx))
+	})
+}
+
+// gormConn returns the transaction bound to ctx, or db scoped to ctx outside a transaction
+func gormConn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(gormTxContextKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// mongoTxManager implements TxManager with MongoDB sessions.
+// Transactions require a replica set or sharded cluster.
+type mongoTxManager struct {
+	client *mongo.Client
+}
+
+// NewMongoTxManager creates a MongoDB transaction manager
+func NewMongoTxManager(client *mongo.Client) domain.TxManager {
+	return &mongoTxManager{client: client}
+}
+
+// WithinTransaction runs fn in a session transaction; the driver retries it on transient errors
+func (m *mongoTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	session, err := m.client.StartSession()
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to start database session")
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
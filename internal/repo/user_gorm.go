@@ -2,134 +2,127 @@ package repo
 
 import (
 	"context"
-	"errors"
+	"strings"
+	"time"
 
 	"github.com/luxixing/fx-gin-scaffold/internal/domain"
 	"gorm.io/gorm"
 )
 
+// UserSearchVector is the PostgreSQL text search document of a user. The
+// email is split at the @ so its local part matches on its own.
+const UserSearchVector = "to_tsvector('simple', coalesce(name, '') || ' ' || replace(coalesce(email, ''), '@', ' '))"
+
+// likeEscaper escapes the LIKE wildcards of user input
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 // userGormRepository implements UserRepository for GORM-based databases
 type userGormRepository struct {
-	db *gorm.DB
+	*GormRepository[domain.User]
+	fullTextSearch bool
 }
 
-// NewUserGormRepository creates a new GORM-based user repository
-func NewUserGormRepository(db *gorm.DB) domain.UserRepository {
-	return &userGormRepository{
-		db: db,
-	}
-}
+// UserGormOption configures a GORM user repository
+type UserGormOption func(*userGormRepository)
 
-// Create creates a new user
-func (r *userGormRepository) Create(ctx context.Context, user *domain.User) error {
-	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
-		if isUniqueConstraintError(err) {
-			return domain.ErrUserExists
-		}
-		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create user")
+// WithFullTextSearch makes PostgreSQL searches match whole words of the name
+// and email with full-text search instead of substrings. Other dialects
+// ignore it.
+func WithFullTextSearch(enabled bool) UserGormOption {
+	return func(r *userGormRepository) {
+		r.fullTextSearch = enabled
 	}
-	return nil
 }
 
-// GetByID retrieves a user by ID
-func (r *userGormRepository) GetByID(ctx context.Context, id uint) (*domain.User, error) {
-	var user domain.User
-	err := r.db.WithContext(ctx).First(&user, id).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, domain.ErrUserNotFound
-		}
-		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to get user by ID")
+// NewUserGormRepository creates a new GORM-based user repository
+func NewUserGormRepository(db *gorm.DB, opts ...UserGormOption) domain.UserRepository {
+	r := &userGormRepository{
+		GormRepository: NewGormRepository[domain.User](db, Entity{
+			Name:         "user",
+			NotFound:     domain.ErrUserNotFound,
+			Conflict:     domain.ErrUserExists,
+			DefaultOrder: "created_at DESC",
+		}),
 	}
-	return &user, nil
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // GetByEmail retrieves a user by email
 func (r *userGormRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	var user domain.User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, domain.ErrUserNotFound
-		}
-		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to get user by email")
-	}
-	return &user, nil
+	return r.First(ctx, "email = ?", email)
 }
 
-// Update updates an existing user
-func (r *userGormRepository) Update(ctx context.Context, user *domain.User) error {
-	result := r.db.WithContext(ctx).Save(user)
-	if result.Error != nil {
-		if isUniqueConstraintError(result.Error) {
-			return domain.ErrUserExists
-		}
-		return domain.WrapError(result.Error, domain.ErrCodeDatabase, "Failed to update user")
-	}
-	if result.RowsAffected == 0 {
-		return domain.ErrUserNotFound
-	}
-	return nil
+// List retrieves users matching the query and options with pagination
+func (r *userGormRepository) List(ctx context.Context, query *domain.Query, opts domain.ListOptions, offset, limit int) ([]*domain.User, int64, error) {
+	return r.Paginate(ctx, applyUserListOptions(r.Filtered(ctx, query), opts), query, offset, limit)
 }
 
-// Delete soft deletes a user
-func (r *userGormRepository) Delete(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(&domain.User{}, id)
-	if result.Error != nil {
-		return domain.WrapError(result.Error, domain.ErrCodeDatabase, "Failed to delete user")
-	}
-	if result.RowsAffected == 0 {
-		return domain.ErrUserNotFound
-	}
-	return nil
+// Search searches users by name or email
+func (r *userGormRepository) Search(ctx context.Context, query string, offset, limit int) ([]*domain.User, int64, error) {
+	queryBuilder := r.search(r.DB(ctx).Model(&domain.User{}), query)
+
+	return r.Paginate(ctx, queryBuilder, nil, offset, limit)
 }
 
-// List retrieves users with pagination
-func (r *userGormRepository) List(ctx context.Context, offset, limit int) ([]*domain.User, int64, error) {
+// ListByCursor retrieves users with keyset pagination
+func (r *userGormRepository) ListByCursor(ctx context.Context, query *domain.Query, opts domain.ListOptions, page *domain.CursorPage) ([]*domain.User, bool, error) {
 	var users []*domain.User
-	var total int64
-
-	// Count total records
-	if err := r.db.WithContext(ctx).Model(&domain.User{}).Count(&total).Error; err != nil {
-		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count users")
-	}
-
-	// Get paginated records
-	err := r.db.WithContext(ctx).
-		Offset(offset).
-		Limit(limit).
-		Order("created_at DESC").
-		Find(&users).Error
+	queryBuilder := applyUserListOptions(applyGormFilters(r.DB(ctx), query), opts)
+	err := applyGormCursorPage(queryBuilder, page).Find(&users).Error
 	if err != nil {
-		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list users")
+		return nil, false, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list users")
 	}
 
-	return users, total, nil
+	users, hasMore := trimCursorPage(users, page)
+	return users, hasMore, nil
 }
 
-// Search searches users by name or email
-func (r *userGormRepository) Search(ctx context.Context, query string, offset, limit int) ([]*domain.User, int64, error) {
+// SearchByCursor searches users by name or email with keyset pagination
+func (r *userGormRepository) SearchByCursor(ctx context.Context, query string, page *domain.CursorPage) ([]*domain.User, bool, error) {
 	var users []*domain.User
-	var total int64
 
-	searchPattern := "%" + query + "%"
-	queryBuilder := r.db.WithContext(ctx).Model(&domain.User{}).
-		Where("name ILIKE ? OR email ILIKE ?", searchPattern, searchPattern)
+	queryBuilder := r.search(r.DB(ctx), query)
 
-	// Count total records
-	if err := queryBuilder.Count(&total).Error; err != nil {
-		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count search results")
+	err := applyGormCursorPage(queryBuilder, page).Find(&users).Error
+	if err != nil {
+		return nil, false, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to search users")
 	}
 
-	// Get paginated records
-	err := queryBuilder.
-		Offset(offset).
-		Limit(limit).
-		Order("created_at DESC").
-		Find(&users).Error
+	users, hasMore := trimCursorPage(users, page)
+	return users, hasMore, nil
+}
+
+// CountCreatedByDay counts the users created on each UTC day since since
+func (r *userGormRepository) CountCreatedByDay(ctx context.Context, since time.Time) ([]domain.DailyCount, error) {
+	counts, err := gormCountByDay(r.DB(ctx).Model(&domain.User{}), "created_at", since)
 	if err != nil {
-		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to search users")
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count users by day")
+	}
+	return counts, nil
+}
+
+// applyUserListOptions filters out inactive users unless included, and users
+// without the role when one is given
+func applyUserListOptions(db *gorm.DB, opts domain.ListOptions) *gorm.DB {
+	if !opts.IncludeInactive {
+		db = db.Where("active = ?", true)
+	}
+	if opts.Role != "" {
+		db = db.Where("role = ?", opts.Role)
+	}
+	return db
+}
+
+// search filters users whose name or email matches query, ignoring case.
+// LOWER and LIKE behave the same on every SQL dialect, unlike ILIKE.
+func (r *userGormRepository) search(db *gorm.DB, query string) *gorm.DB {
+	if r.fullTextSearch && db.Dialector.Name() == "postgres" {
+		return db.Where(UserSearchVector+" @@ plainto_tsquery('simple', ?)", query)
 	}
 
-	return users, total, nil
-}
\ No newline at end of file
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(query)) + "%"
+	return db.Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\'`, pattern, pattern)
+}
@@ -0,0 +1,142 @@
+//go:build integration
+
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Images match the services of docker-compose.yml
+const (
+	postgresImage = "postgres:15-alpine"
+	mongoImage    = "mongo:7"
+)
+
+// terminateOnCleanup stops container when the test ends
+func terminateOnCleanup(t *testing.T, container testcontainers.Container) {
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := container.Terminate(ctx); err != nil {
+			t.Errorf("terminate container: %v", err)
+		}
+	})
+}
+
+// openPostgres starts a PostgreSQL container for the test
+func openPostgres(t *testing.T) *gorm.DB {
+	ctx := context.Background()
+	container, err := tcpostgres.Run(ctx, postgresImage,
+		tcpostgres.WithDatabase("fx_gin_scaffold_test"),
+		tcpostgres.WithUsername("postgres"),
+		tcpostgres.WithPassword("password"),
+		testcontainers.WithWaitStrategy(wait.ForLog("database system is ready to accept connections").
+			WithOccurrence(2).
+			WithStartupTimeout(time.Minute)),
+	)
+	require.NoError(t, err)
+	terminateOnCleanup(t, container)
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db
+}
+
+// TestUserPostgresRepository runs the user repository suite against PostgreSQL
+func TestUserPostgresRepository(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	suite.Run(t, &UserRepositoryTestSuite{open: func(t *testing.T) *userRepositoryBackend {
+		return gormUserBackend(t, openPostgres(t))
+	}})
+}
+
+// TestUserPostgresFullTextSearch tests searching users by whole words
+func TestUserPostgresFullTextSearch(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	db := openPostgres(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}))
+	repo := NewUserGormRepository(db, WithFullTextSearch(true))
+
+	for _, user := range []*domain.User{
+		{Email: "john@example.com", Password: "pass", Name: "John Doe", Role: "user", Active: true},
+		{Email: "johnny@example.com", Password: "pass", Name: "Johnny Walker", Role: "user", Active: true},
+	} {
+		require.NoError(t, repo.Create(ctx, user))
+	}
+
+	users, total, err := repo.Search(ctx, "JOHN", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, users, 1)
+	assert.Equal(t, "John Doe", users[0].Name)
+
+	// The local part of the email is a word of its own
+	_, total, err = repo.Search(ctx, "johnny", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+// TestUserMongoRepository runs the user repository suite against MongoDB
+func TestUserMongoRepository(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	suite.Run(t, &UserRepositoryTestSuite{open: func(t *testing.T) *userRepositoryBackend {
+		ctx := context.Background()
+		container, err := mongodb.Run(ctx, mongoImage)
+		require.NoError(t, err)
+		terminateOnCleanup(t, container)
+
+		uri, err := container.ConnectionString(ctx)
+		require.NoError(t, err)
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+		require.NoError(t, err)
+
+		db := client.Database("fx_gin_scaffold_test")
+		repo := NewUserMongoRepository(db, zap.NewNop())
+
+		// The repository creates its indexes in the background; create the
+		// unique email index up front so duplicate checks don't race it
+		collection := db.Collection(domain.TableName(domain.User{}))
+		_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.M{"email": 1},
+			Options: options.Index().SetUnique(true),
+		})
+		require.NoError(t, err)
+
+		return &userRepositoryBackend{
+			repo: repo,
+			reset: func(ctx context.Context) error {
+				_, err := collection.DeleteMany(ctx, bson.M{})
+				return err
+			},
+			close: func() error {
+				return client.Disconnect(context.Background())
+			},
+		}
+	}})
+}
@@ -2,24 +2,26 @@ package repo
 
 import (
 	"context"
-	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/fieldcrypt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
 )
 
 // userMongoRepository implements UserRepository for MongoDB
 type userMongoRepository struct {
-	collection *mongo.Collection
+	docs *MongoRepository[mongoUser]
 }
 
 // NewUserMongoRepository creates a new MongoDB-based user repository
-func NewUserMongoRepository(db *mongo.Database) domain.UserRepository {
-	collection := db.Collection("users")
+func NewUserMongoRepository(db *mongo.Database, log *zap.Logger) domain.UserRepository {
+	collection := db.Collection(domain.TableName(domain.User{}))
 	
 	// Create indexes
 	go func() {
@@ -35,51 +37,87 @@ func NewUserMongoRepository(db *mongo.Database) domain.UserRepository {
 		_, err := collection.Indexes().CreateOne(ctx, emailIndex)
 		if err != nil {
 			// Log error but don't fail - indexes might already exist
-			fmt.Printf("Warning: Failed to create email index: %v\n", err)
+			log.Warn("failed to create email index", zap.Error(err))
+		}
+
+		// Role and active filters of List, newest first
+		listIndex := mongo.IndexModel{
+			Keys: bson.D{{Key: "role", Value: 1}, {Key: "active", Value: 1}, {Key: "created_at", Value: -1}},
+		}
+		if _, err := collection.Indexes().CreateOne(ctx, listIndex); err != nil {
+			log.Warn("failed to create list index", zap.Error(err))
 		}
 	}()
 	
 	return &userMongoRepository{
-		collection: collection,
+		docs: NewMongoRepository[mongoUser](collection, Entity{
+			Name:     "user",
+			NotFound: domain.ErrUserNotFound,
+			Conflict: domain.ErrUserExists,
+		}),
 	}
 }
 
 // mongoUser represents the User model for MongoDB with proper ID handling
 type mongoUser struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	Email     string             `bson:"email"`
-	Password  string             `bson:"password"`
-	Name      string             `bson:"name"`
-	Role      string             `bson:"role"`
-	Active    bool               `bson:"active"`
-	CreatedAt time.Time          `bson:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at"`
+	ID           primitive.ObjectID  `bson:"_id,omitempty"`
+	Email        string              `bson:"email"`
+	PendingEmail string              `bson:"pending_email,omitempty"`
+	Password     string              `bson:"password"`
+	Name         string              `bson:"name"`
+	Role         string              `bson:"role"`
+	Active       bool                `bson:"active"`
+	CreatedAt    time.Time           `bson:"created_at"`
+	UpdatedAt    time.Time           `bson:"updated_at"`
+	AvatarURL    string              `bson:"avatar_url,omitempty"`
+	Phone        fieldcrypt.String   `bson:"phone,omitempty"`
+	Locale       string              `bson:"locale,omitempty"`
+	Timezone     string              `bson:"timezone,omitempty"`
+	Metadata     fieldcrypt.Document `bson:"metadata,omitempty"`
+
+	DeletionScheduledAt *time.Time `bson:"deletion_scheduled_at,omitempty"`
 }
 
 // toDomainUser converts mongoUser to domain.User
 func (m *mongoUser) toDomainUser() *domain.User {
 	return &domain.User{
-		ID:        uint(m.ID.Timestamp().Unix()), // Use timestamp as ID for compatibility
-		Email:     m.Email,
-		Password:  m.Password,
-		Name:      m.Name,
-		Role:      m.Role,
-		Active:    m.Active,
-		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
+		ID:           uint(m.ID.Timestamp().Unix()), // Use timestamp as ID for compatibility
+		Email:        m.Email,
+		PendingEmail: m.PendingEmail,
+		Password:     m.Password,
+		Name:         m.Name,
+		Role:         m.Role,
+		Active:       m.Active,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+		AvatarURL:    m.AvatarURL,
+		Phone:        string(m.Phone),
+		Locale:       m.Locale,
+		Timezone:     m.Timezone,
+		Metadata:     m.Metadata,
+
+		DeletionScheduledAt: m.DeletionScheduledAt,
 	}
 }
 
 // fromDomainUser converts domain.User to mongoUser
 func fromDomainUser(user *domain.User) *mongoUser {
 	m := &mongoUser{
-		Email:     user.Email,
-		Password:  user.Password,
-		Name:      user.Name,
-		Role:      user.Role,
-		Active:    user.Active,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		Email:        user.Email,
+		PendingEmail: user.PendingEmail,
+		Password:     user.Password,
+		Name:         user.Name,
+		Role:         user.Role,
+		Active:       user.Active,
+		CreatedAt:    user.CreatedAt,
+		UpdatedAt:    user.UpdatedAt,
+		AvatarURL:    user.AvatarURL,
+		Phone:        fieldcrypt.String(user.Phone),
+		Locale:       user.Locale,
+		Timezone:     user.Timezone,
+		Metadata:     user.Metadata,
+
+		DeletionScheduledAt: user.DeletionScheduledAt,
 	}
 	
 	// If ID is provided, try to create ObjectID from it
@@ -95,19 +133,15 @@ func fromDomainUser(user *domain.User) *mongoUser {
 // Create creates a new user
 func (r *userMongoRepository) Create(ctx context.Context, user *domain.User) error {
 	mongoUser := fromDomainUser(user)
-	mongoUser.CreatedAt = time.Now()
-	mongoUser.UpdatedAt = time.Now()
+	stampCreated(&mongoUser.CreatedAt, &mongoUser.UpdatedAt)
 	
-	result, err := r.collection.InsertOne(ctx, mongoUser)
+	insertedID, err := r.docs.Create(ctx, mongoUser)
 	if err != nil {
-		if mongo.IsDuplicateKeyError(err) {
-			return domain.ErrUserExists
-		}
-		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to create user")
+		return err
 	}
 	
 	// Set the generated ID back to the user
-	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+	if oid, ok := insertedID.(primitive.ObjectID); ok {
 		user.ID = uint(oid.Timestamp().Unix())
 		user.CreatedAt = mongoUser.CreatedAt
 		user.UpdatedAt = mongoUser.UpdatedAt
@@ -125,13 +159,9 @@ func (r *userMongoRepository) GetByID(ctx context.Context, id uint) (*domain.Use
 
 // GetByEmail retrieves a user by email
 func (r *userMongoRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	var mongoUser mongoUser
-	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&mongoUser)
+	mongoUser, err := r.docs.FindOne(ctx, bson.M{"email": email})
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, domain.ErrUserNotFound
-		}
-		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to get user by email")
+		return nil, err
 	}
 	
 	return mongoUser.toDomainUser(), nil
@@ -144,20 +174,24 @@ func (r *userMongoRepository) Update(ctx context.Context, user *domain.User) err
 	
 	update := bson.M{
 		"$set": bson.M{
-			"name":       mongoUser.Name,
-			"role":       mongoUser.Role,
-			"active":     mongoUser.Active,
-			"updated_at": mongoUser.UpdatedAt,
+			"name":          mongoUser.Name,
+			"pending_email": mongoUser.PendingEmail,
+			"password":      mongoUser.Password,
+			"role":          mongoUser.Role,
+			"active":        mongoUser.Active,
+			"updated_at":    mongoUser.UpdatedAt,
+			"avatar_url":    mongoUser.AvatarURL,
+			"phone":         mongoUser.Phone,
+			"locale":        mongoUser.Locale,
+			"timezone":      mongoUser.Timezone,
+			"metadata":      mongoUser.Metadata,
+
+			"deletion_scheduled_at": mongoUser.DeletionScheduledAt,
 		},
 	}
 	
-	result, err := r.collection.UpdateOne(ctx, bson.M{"email": user.Email}, update)
-	if err != nil {
-		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to update user")
-	}
-	
-	if result.MatchedCount == 0 {
-		return domain.ErrUserNotFound
+	if err := r.docs.Update(ctx, bson.M{"email": user.Email}, update); err != nil {
+		return err
 	}
 	
 	user.UpdatedAt = mongoUser.UpdatedAt
@@ -171,80 +205,101 @@ func (r *userMongoRepository) Delete(ctx context.Context, id uint) error {
 	return domain.NewError(domain.ErrCodeNotFound, "Delete by ID not implemented for MongoDB")
 }
 
-// List retrieves users with pagination
-func (r *userMongoRepository) List(ctx context.Context, offset, limit int) ([]*domain.User, int64, error) {
-	// Count total documents
-	total, err := r.collection.CountDocuments(ctx, bson.M{"active": true})
+// List retrieves users matching the query and options with pagination
+func (r *userMongoRepository) List(ctx context.Context, query *domain.Query, opts domain.ListOptions, offset, limit int) ([]*domain.User, int64, error) {
+	filter := userListFilter(query, opts)
+	sort := mongoSort(query, bson.D{{Key: "created_at", Value: -1}})
+	
+	mongoUsers, total, err := r.docs.List(ctx, filter, sort, offset, limit)
 	if err != nil {
-		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count users")
+		return nil, 0, err
 	}
 	
-	// Find documents with pagination
-	findOptions := options.Find()
-	findOptions.SetSkip(int64(offset))
-	findOptions.SetLimit(int64(limit))
-	findOptions.SetSort(bson.M{"created_at": -1})
-	
-	cursor, err := r.collection.Find(ctx, bson.M{"active": true}, findOptions)
+	return toDomainUsers(mongoUsers), total, nil
+}
+
+// Search searches users by name or email
+func (r *userMongoRepository) Search(ctx context.Context, query string, offset, limit int) ([]*domain.User, int64, error) {
+	mongoUsers, total, err := r.docs.List(ctx, searchFilter(query), bson.D{{Key: "created_at", Value: -1}}, offset, limit)
 	if err != nil {
-		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list users")
+		return nil, 0, err
 	}
-	defer cursor.Close(ctx)
 	
-	var mongoUsers []mongoUser
-	if err := cursor.All(ctx, &mongoUsers); err != nil {
-		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to decode users")
+	return toDomainUsers(mongoUsers), total, nil
+}
+
+// ListByCursor retrieves users matching the query and options with keyset pagination
+func (r *userMongoRepository) ListByCursor(ctx context.Context, query *domain.Query, opts domain.ListOptions, page *domain.CursorPage) ([]*domain.User, bool, error) {
+	return r.findByCursor(ctx, userListFilter(query, opts), page)
+}
+
+// SearchByCursor searches users by name or email with keyset pagination
+func (r *userMongoRepository) SearchByCursor(ctx context.Context, query string, page *domain.CursorPage) ([]*domain.User, bool, error) {
+	return r.findByCursor(ctx, searchFilter(query), page)
+}
+
+// Count counts users matching the query
+func (r *userMongoRepository) Count(ctx context.Context, query *domain.Query) (int64, error) {
+	return r.docs.Count(ctx, mongoFilter(bson.M{}, query))
+}
+
+// CountCreatedByDay counts the users created on each UTC day since since
+func (r *userMongoRepository) CountCreatedByDay(ctx context.Context, since time.Time) ([]domain.DailyCount, error) {
+	counts, err := mongoCountByDay(ctx, r.docs.Collection(), bson.M{}, "created_at", since)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count users by day")
 	}
-	
-	// Convert to domain users
-	users := make([]*domain.User, len(mongoUsers))
-	for i, mu := range mongoUsers {
-		users[i] = mu.toDomainUser()
+	return counts, nil
+}
+
+// userListFilter matches the users of the query and options. The options are
+// combined with $and so that they narrow the query's own conditions on the
+// same fields rather than replace them, as in SQL.
+func userListFilter(query *domain.Query, opts domain.ListOptions) bson.M {
+	filter := mongoFilter(bson.M{}, query)
+
+	var conditions []bson.M
+	if !opts.IncludeInactive {
+		conditions = append(conditions, bson.M{"active": true})
 	}
-	
-	return users, total, nil
+	if opts.Role != "" {
+		conditions = append(conditions, bson.M{"role": opts.Role})
+	}
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+	return filter
 }
 
-// Search searches users by name or email
-func (r *userMongoRepository) Search(ctx context.Context, query string, offset, limit int) ([]*domain.User, int64, error) {
-	// Create regex pattern for case-insensitive search
-	pattern := primitive.Regex{Pattern: query, Options: "i"}
-	filter := bson.M{
-		"active": true,
+// searchFilter matches users whose name or email contains query literally, ignoring case
+func searchFilter(query string) bson.M {
+	pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+	return bson.M{
 		"$or": []bson.M{
 			{"name": pattern},
 			{"email": pattern},
 		},
 	}
+}
+
+// findByCursor runs a keyset-paginated query
+func (r *userMongoRepository) findByCursor(ctx context.Context, filter bson.M, page *domain.CursorPage) ([]*domain.User, bool, error) {
+	filter, findOptions := mongoCursorPage(filter, page)
 	
-	// Count total documents
-	total, err := r.collection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to count search results")
-	}
-	
-	// Find documents with pagination
-	findOptions := options.Find()
-	findOptions.SetSkip(int64(offset))
-	findOptions.SetLimit(int64(limit))
-	findOptions.SetSort(bson.M{"created_at": -1})
-	
-	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	mongoUsers, err := r.docs.Find(ctx, filter, findOptions)
 	if err != nil {
-		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to search users")
+		return nil, false, err
 	}
-	defer cursor.Close(ctx)
 	
-	var mongoUsers []mongoUser
-	if err := cursor.All(ctx, &mongoUsers); err != nil {
-		return nil, 0, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to decode search results")
-	}
-	
-	// Convert to domain users
+	mongoUsers, hasMore := trimCursorPage(mongoUsers, page)
+	return toDomainUsers(mongoUsers), hasMore, nil
+}
+
+// toDomainUsers converts mongoUsers to domain users
+func toDomainUsers(mongoUsers []*mongoUser) []*domain.User {
 	users := make([]*domain.User, len(mongoUsers))
 	for i, mu := range mongoUsers {
 		users[i] = mu.toDomainUser()
 	}
-	
-	return users, total, nil
-}
\ No newline at end of file
+	return users
+}
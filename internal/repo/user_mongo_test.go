@@ -0,0 +1,23 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestUserListFilter tests that list options narrow the query's conditions
+// instead of replacing them
+func TestUserListFilter(t *testing.T) {
+	assert.Equal(t, bson.M{"$and": []bson.M{{"active": true}}}, userListFilter(nil, domain.ListOptions{}))
+	assert.Equal(t, bson.M{}, userListFilter(nil, domain.ListOptions{IncludeInactive: true}))
+
+	query := domain.NewQuery("role", "active").Where("role", domain.OpEq, "user").Where("active", domain.OpEq, false)
+	assert.Equal(t, bson.M{
+		"role":   "user",
+		"active": false,
+		"$and":   []bson.M{{"active": true}, {"role": "admin"}},
+	}, userListFilter(query, domain.ListOptions{Role: "admin"}))
+}
@@ -0,0 +1,498 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// userRepositoryBackend is a user repository on a database of its own
+type userRepositoryBackend struct {
+	repo domain.UserRepository
+
+	// reset removes every user
+	reset func(ctx context.Context) error
+	close func() error
+}
+
+// gormUserBackend creates the users table on db and wraps it in a backend
+func gormUserBackend(t *testing.T, db *gorm.DB) *userRepositoryBackend {
+	require.NoError(t, db.AutoMigrate(&domain.User{}))
+
+	return &userRepositoryBackend{
+		repo: NewUserGormRepository(db),
+		reset: func(ctx context.Context) error {
+			return db.WithContext(ctx).Exec("DELETE FROM " + domain.TableName(domain.User{})).Error
+		},
+		close: func() error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.Close()
+		},
+	}
+}
+
+// UserRepositoryTestSuite runs the same tests against every UserRepository
+// implementation, so dialect differences between the backends show up here
+type UserRepositoryTestSuite struct {
+	suite.Suite
+	open    func(t *testing.T) *userRepositoryBackend
+	backend *userRepositoryBackend
+	repo    domain.UserRepository
+}
+
+// SetupSuite opens the backend
+func (suite *UserRepositoryTestSuite) SetupSuite() {
+	suite.backend = suite.open(suite.T())
+	suite.repo = suite.backend.repo
+}
+
+// TearDownSuite closes the backend
+func (suite *UserRepositoryTestSuite) TearDownSuite() {
+	require.NoError(suite.T(), suite.backend.close())
+}
+
+// SetupTest sets up each test
+func (suite *UserRepositoryTestSuite) SetupTest() {
+	// Clean the database before each test
+	require.NoError(suite.T(), suite.backend.reset(context.Background()))
+}
+
+// TestCreateUser tests user creation
+func (suite *UserRepositoryTestSuite) TestCreateUser() {
+	ctx := context.Background()
+	
+	user := &domain.User{
+		Email:     "test@example.com",
+		Password:  "hashedpassword",
+		Name:      "Test User",
+		Role:      "user",
+		Active:    true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err := suite.repo.Create(ctx, user)
+	assert.NoError(suite.T(), err)
+	assert.NotZero(suite.T(), user.ID)
+}
+
+// TestCreateUserDuplicateEmail tests creating a user with duplicate email
+func (suite *UserRepositoryTestSuite) TestCreateUserDuplicateEmail() {
+	ctx := context.Background()
+	
+	user1 := &domain.User{
+		Email:    "test@example.com",
+		Password: "hashedpassword",
+		Name:     "Test User 1",
+		Role:     "user",
+		Active:   true,
+	}
+
+	err := suite.repo.Create(ctx, user1)
+	assert.NoError(suite.T(), err)
+
+	user2 := &domain.User{
+		Email:    "test@example.com",
+		Password: "hashedpassword",
+		Name:     "Test User 2",
+		Role:     "user",
+		Active:   true,
+	}
+
+	err = suite.repo.Create(ctx, user2)
+	assert.Equal(suite.T(), domain.ErrUserExists, err)
+}
+
+// TestGetByID tests getting a user by ID
+func (suite *UserRepositoryTestSuite) TestGetByID() {
+	ctx := context.Background()
+	
+	// Create a user first
+	user := &domain.User{
+		Email:    "test@example.com",
+		Password: "hashedpassword",
+		Name:     "Test User",
+		Role:     "user",
+		Active:   true,
+	}
+
+	err := suite.repo.Create(ctx, user)
+	require.NoError(suite.T(), err)
+
+	// Get the user by ID
+	retrievedUser, err := suite.repo.GetByID(ctx, user.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), user.Email, retrievedUser.Email)
+	assert.Equal(suite.T(), user.Name, retrievedUser.Name)
+}
+
+// TestGetByIDNotFound tests getting a non-existent user by ID
+func (suite *UserRepositoryTestSuite) TestGetByIDNotFound() {
+	ctx := context.Background()
+	
+	_, err := suite.repo.GetByID(ctx, 999)
+	assert.Equal(suite.T(), domain.ErrUserNotFound, err)
+}
+
+// TestGetByEmail tests getting a user by email
+func (suite *UserRepositoryTestSuite) TestGetByEmail() {
+	ctx := context.Background()
+	
+	// Create a user first
+	user := &domain.User{
+		Email:    "test@example.com",
+		Password: "hashedpassword",
+		Name:     "Test User",
+		Role:     "user",
+		Active:   true,
+	}
+
+	err := suite.repo.Create(ctx, user)
+	require.NoError(suite.T(), err)
+
+	// Get the user by email
+	retrievedUser, err := suite.repo.GetByEmail(ctx, "test@example.com")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), user.Email, retrievedUser.Email)
+	assert.Equal(suite.T(), user.Name, retrievedUser.Name)
+}
+
+// TestGetByEmailNotFound tests getting a non-existent user by email
+func (suite *UserRepositoryTestSuite) TestGetByEmailNotFound() {
+	ctx := context.Background()
+	
+	_, err := suite.repo.GetByEmail(ctx, "nonexistent@example.com")
+	assert.Equal(suite.T(), domain.ErrUserNotFound, err)
+}
+
+// TestUpdateUser tests updating a user
+func (suite *UserRepositoryTestSuite) TestUpdateUser() {
+	ctx := context.Background()
+	
+	// Create a user first
+	user := &domain.User{
+		Email:    "test@example.com",
+		Password: "hashedpassword",
+		Name:     "Test User",
+		Role:     "user",
+		Active:   true,
+	}
+
+	err := suite.repo.Create(ctx, user)
+	require.NoError(suite.T(), err)
+
+	// Update the user
+	user.Name = "Updated User"
+	user.UpdatedAt = time.Now()
+
+	err = suite.repo.Update(ctx, user)
+	assert.NoError(suite.T(), err)
+
+	// Verify the update
+	retrievedUser, err := suite.repo.GetByID(ctx, user.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Updated User", retrievedUser.Name)
+
+	// Profile fields and metadata round-trip
+	user.AvatarURL = "https://example.com/avatar.png"
+	user.Timezone = "Asia/Shanghai"
+	user.Metadata = map[string]interface{}{"theme": "dark", "beta": true}
+	require.NoError(suite.T(), suite.repo.Update(ctx, user))
+
+	retrievedUser, err = suite.repo.GetByEmail(ctx, user.Email)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "https://example.com/avatar.png", retrievedUser.AvatarURL)
+	assert.Equal(suite.T(), "Asia/Shanghai", retrievedUser.Timezone)
+	assert.Empty(suite.T(), retrievedUser.Phone)
+	assert.Equal(suite.T(), "dark", retrievedUser.Metadata["theme"])
+	assert.Equal(suite.T(), true, retrievedUser.Metadata["beta"])
+}
+
+// TestDeleteUser tests deleting a user
+func (suite *UserRepositoryTestSuite) TestDeleteUser() {
+	ctx := context.Background()
+	
+	// Create a user first
+	user := &domain.User{
+		Email:    "test@example.com",
+		Password: "hashedpassword",
+		Name:     "Test User",
+		Role:     "user",
+		Active:   true,
+	}
+
+	err := suite.repo.Create(ctx, user)
+	require.NoError(suite.T(), err)
+
+	// Delete the user
+	err = suite.repo.Delete(ctx, user.ID)
+	assert.NoError(suite.T(), err)
+
+	// Verify the user is deleted
+	_, err = suite.repo.GetByID(ctx, user.ID)
+	assert.Equal(suite.T(), domain.ErrUserNotFound, err)
+}
+
+// TestListUsers tests listing users with pagination
+func (suite *UserRepositoryTestSuite) TestListUsers() {
+	ctx := context.Background()
+	
+	// Create multiple users
+	users := []*domain.User{
+		{Email: "user1@example.com", Password: "pass", Name: "User 1", Role: "user", Active: true},
+		{Email: "user2@example.com", Password: "pass", Name: "User 2", Role: "user", Active: true},
+		{Email: "user3@example.com", Password: "pass", Name: "User 3", Role: "admin", Active: true},
+	}
+
+	for _, user := range users {
+		err := suite.repo.Create(ctx, user)
+		require.NoError(suite.T(), err)
+	}
+
+	// List users with pagination
+	retrievedUsers, total, err := suite.repo.List(ctx, nil, domain.ListOptions{}, 0, 2)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), total)
+	assert.Len(suite.T(), retrievedUsers, 2)
+
+	// Inactive users are left out unless included
+	users[0].Active = false
+	require.NoError(suite.T(), suite.repo.Update(ctx, users[0]))
+
+	retrievedUsers, total, err = suite.repo.List(ctx, nil, domain.ListOptions{}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+	for _, user := range retrievedUsers {
+		assert.True(suite.T(), user.Active)
+	}
+
+	_, total, err = suite.repo.List(ctx, nil, domain.ListOptions{IncludeInactive: true}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), total)
+
+	// A role narrows the list, with or without inactive users
+	retrievedUsers, total, err = suite.repo.List(ctx, nil, domain.ListOptions{Role: "user"}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	require.Len(suite.T(), retrievedUsers, 1)
+	assert.Equal(suite.T(), "user2@example.com", retrievedUsers[0].Email)
+
+	_, total, err = suite.repo.List(ctx, nil, domain.ListOptions{IncludeInactive: true, Role: "user"}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+
+	// Options narrow the query's own conditions rather than replace them
+	query := domain.NewQuery("role", "active").Where("active", domain.OpEq, false)
+	_, total, err = suite.repo.List(ctx, query, domain.ListOptions{}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), total)
+
+	query = domain.NewQuery("role", "active").Where("role", domain.OpEq, "user")
+	_, total, err = suite.repo.List(ctx, query, domain.ListOptions{IncludeInactive: true, Role: "admin"}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), total)
+
+	query = domain.NewQuery("role", "active").Where("active", domain.OpEq, false)
+	retrievedUsers, total, err = suite.repo.List(ctx, query, domain.ListOptions{IncludeInactive: true, Role: "user"}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	require.Len(suite.T(), retrievedUsers, 1)
+	assert.Equal(suite.T(), "user1@example.com", retrievedUsers[0].Email)
+}
+
+// TestListUsersWithQuery tests filtering and sorting users
+func (suite *UserRepositoryTestSuite) TestListUsersWithQuery() {
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+
+	users := []*domain.User{
+		{Email: "carol@example.com", Password: "pass", Name: "Carol", Role: "user", Active: true, CreatedAt: base},
+		{Email: "alice@example.com", Password: "pass", Name: "Alice", Role: "admin", Active: true, CreatedAt: base.Add(time.Minute)},
+		{Email: "bob@example.com", Password: "pass", Name: "Bob", Role: "user", Active: true, CreatedAt: base.Add(2 * time.Minute)},
+	}
+	for _, user := range users {
+		require.NoError(suite.T(), suite.repo.Create(ctx, user))
+	}
+	// GORM skips zero values on create, so deactivate with an update
+	users[2].Active = false
+	require.NoError(suite.T(), suite.repo.Update(ctx, users[2]))
+
+	query := domain.NewQuery("name", "role", "active", "created_at").Where("role", domain.OpEq, "user").SortBy("name")
+	require.NoError(suite.T(), query.Err())
+	result, total, err := suite.repo.List(ctx, query, domain.ListOptions{IncludeInactive: true}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+	require.Len(suite.T(), result, 2)
+	assert.Equal(suite.T(), "Bob", result[0].Name)
+	assert.Equal(suite.T(), "Carol", result[1].Name)
+
+	query = domain.NewQuery("name", "role", "active", "created_at").
+		Where("active", domain.OpEq, true).
+		Where("created_at", domain.OpGt, base).
+		SortBy("-name")
+	result, total, err = suite.repo.List(ctx, query, domain.ListOptions{IncludeInactive: true}, 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	require.Len(suite.T(), result, 1)
+	assert.Equal(suite.T(), "Alice", result[0].Name)
+
+	// Fields outside the allow-list are rejected
+	query = domain.NewQuery("name").SortBy("name,password")
+	assert.Error(suite.T(), query.Err())
+}
+
+// TestSearchUsers tests searching users
+func (suite *UserRepositoryTestSuite) TestSearchUsers() {
+	ctx := context.Background()
+	
+	// Create multiple users
+	users := []*domain.User{
+		{Email: "john@example.com", Password: "pass", Name: "John Doe", Role: "user", Active: true},
+		{Email: "jane@example.com", Password: "pass", Name: "Jane Smith", Role: "user", Active: true},
+		{Email: "admin@example.com", Password: "pass", Name: "Admin User", Role: "admin", Active: true},
+	}
+
+	for _, user := range users {
+		err := suite.repo.Create(ctx, user)
+		require.NoError(suite.T(), err)
+	}
+
+	// Search by name
+	searchResults, total, err := suite.repo.Search(ctx, "John", 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	assert.Len(suite.T(), searchResults, 1)
+	assert.Equal(suite.T(), "John Doe", searchResults[0].Name)
+
+	// Search by email
+	searchResults, total, err = suite.repo.Search(ctx, "admin", 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	assert.Len(suite.T(), searchResults, 1)
+	assert.Equal(suite.T(), "admin@example.com", searchResults[0].Email)
+
+	// Matching ignores case
+	searchResults, total, err = suite.repo.Search(ctx, "jANE", 0, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	require.Len(suite.T(), searchResults, 1)
+	assert.Equal(suite.T(), "Jane Smith", searchResults[0].Name)
+
+	// Wildcards in the query match literally
+	for _, query := range []string{"%", "_", ".*"} {
+		_, total, err = suite.repo.Search(ctx, query, 0, 10)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), int64(0), total, query)
+	}
+
+	users, hasMore, err := suite.repo.SearchByCursor(ctx, "DOE", &domain.CursorPage{Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), hasMore)
+	require.Len(suite.T(), users, 1)
+	assert.Equal(suite.T(), "John Doe", users[0].Name)
+}
+
+// TestListByCursor tests keyset pagination over users
+func (suite *UserRepositoryTestSuite) TestListByCursor() {
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+
+	// Two users share a timestamp so the id tie-breaker is exercised
+	offsets := []time.Duration{0, time.Minute, time.Minute, 2 * time.Minute, 3 * time.Minute}
+	for i, offset := range offsets {
+		user := &domain.User{
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			Password:  "pass",
+			Name:      fmt.Sprintf("User %d", i),
+			Role:      "user",
+			Active:    true,
+			CreatedAt: base.Add(offset),
+		}
+		require.NoError(suite.T(), suite.repo.Create(ctx, user))
+	}
+
+	// Walk forward from the newest user
+	var names []string
+	page := &domain.CursorPage{Limit: 2}
+	for {
+		users, hasMore, err := suite.repo.ListByCursor(ctx, nil, domain.ListOptions{}, page)
+		require.NoError(suite.T(), err)
+		for _, user := range users {
+			names = append(names, user.Name)
+		}
+		if !hasMore {
+			break
+		}
+		last := users[len(users)-1]
+		page = &domain.CursorPage{Limit: 2, After: &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}}
+	}
+	assert.Equal(suite.T(), []string{"User 4", "User 3", "User 2", "User 1", "User 0"}, names)
+
+	// Step back from the oldest user
+	oldest, _, err := suite.repo.ListByCursor(ctx, nil, domain.ListOptions{}, &domain.CursorPage{Limit: 5})
+	require.NoError(suite.T(), err)
+	last := oldest[len(oldest)-1]
+	users, hasMore, err := suite.repo.ListByCursor(ctx, nil, domain.ListOptions{}, &domain.CursorPage{
+		Limit:  2,
+		Before: &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID},
+	})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), hasMore)
+	require.Len(suite.T(), users, 2)
+	assert.Equal(suite.T(), "User 2", users[0].Name)
+	assert.Equal(suite.T(), "User 1", users[1].Name)
+
+	// Pages leave out inactive users unless included
+	users[0].Active = false
+	require.NoError(suite.T(), suite.repo.Update(ctx, users[0]))
+	users, _, err = suite.repo.ListByCursor(ctx, nil, domain.ListOptions{}, &domain.CursorPage{Limit: 5})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), users, 4)
+	users, _, err = suite.repo.ListByCursor(ctx, nil, domain.ListOptions{IncludeInactive: true, Role: "user"}, &domain.CursorPage{Limit: 5})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), users, 5)
+}
+
+// TestCountCreatedByDay tests counting users and their signups per UTC day
+func (suite *UserRepositoryTestSuite) TestCountCreatedByDay() {
+	ctx := context.Background()
+	since := time.Now().Add(-time.Hour)
+
+	users := []*domain.User{
+		{Email: "user1@example.com", Password: "pass", Name: "User 1", Role: "user", Active: true},
+		{Email: "user2@example.com", Password: "pass", Name: "User 2", Role: "user", Active: true},
+		{Email: "user3@example.com", Password: "pass", Name: "User 3", Role: "user", Active: true},
+	}
+	for _, user := range users {
+		require.NoError(suite.T(), suite.repo.Create(ctx, user))
+	}
+	users[2].Active = false
+	require.NoError(suite.T(), suite.repo.Update(ctx, users[2]))
+	today := time.Now().UTC().Format(time.DateOnly)
+
+	total, err := suite.repo.Count(ctx, nil)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), total)
+
+	active, err := suite.repo.Count(ctx, domain.NewQuery("active").Where("active", domain.OpEq, true))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), active)
+
+	counts, err := suite.repo.CountCreatedByDay(ctx, since)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.DailyCount{{Date: today, Count: 3}}, counts)
+
+	counts, err = suite.repo.CountCreatedByDay(ctx, time.Now().Add(time.Hour))
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), counts)
+}
@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// userSettingGormRepository implements UserSettingRepository for GORM-based databases
+type userSettingGormRepository struct {
+	*GormRepository[domain.UserSetting]
+}
+
+// NewUserSettingGormRepository creates a new GORM-based user setting repository
+func NewUserSettingGormRepository(db *gorm.DB) domain.UserSettingRepository {
+	return &userSettingGormRepository{
+		GormRepository: NewGormRepository[domain.UserSetting](db, Entity{
+			Name: "user setting",
+		}),
+	}
+}
+
+// ListByUser retrieves the settings a user has changed
+func (r *userSettingGormRepository) ListByUser(ctx context.Context, userID uint) ([]*domain.UserSetting, error) {
+	var settings []*domain.UserSetting
+	if err := r.DB(ctx).Where("user_id = ?", userID).Order("key ASC").Find(&settings).Error; err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list user settings")
+	}
+	return settings, nil
+}
+
+// Upsert creates the settings or replaces their values
+func (r *userSettingGormRepository) Upsert(ctx context.Context, settings []*domain.UserSetting) error {
+	if len(settings) == 0 {
+		return nil
+	}
+
+	err := r.DB(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(settings).Error
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to save user settings")
+	}
+	return nil
+}
+
+// Delete removes settings of a user by key
+func (r *userSettingGormRepository) Delete(ctx context.Context, userID uint, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	err := r.DB(ctx).Where("user_id = ? AND key IN ?", userID, keys).Delete(&domain.UserSetting{}).Error
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to delete user settings")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// userSettingMongoRepository implements UserSettingRepository for MongoDB
+type userSettingMongoRepository struct {
+	docs *MongoRepository[domain.UserSetting]
+}
+
+// NewUserSettingMongoRepository creates a new MongoDB-based user setting repository
+func NewUserSettingMongoRepository(db *mongo.Database) domain.UserSettingRepository {
+	return &userSettingMongoRepository{
+		docs: NewMongoRepository[domain.UserSetting](db.Collection(domain.TableName(domain.UserSetting{})), Entity{
+			Name: "user setting",
+		}),
+	}
+}
+
+// ListByUser retrieves the settings a user has changed
+func (r *userSettingMongoRepository) ListByUser(ctx context.Context, userID uint) ([]*domain.UserSetting, error) {
+	return r.docs.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "key", Value: 1}}))
+}
+
+// Upsert creates the settings or replaces their values
+func (r *userSettingMongoRepository) Upsert(ctx context.Context, settings []*domain.UserSetting) error {
+	if len(settings) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	models := make([]mongo.WriteModel, len(settings))
+	for i, setting := range settings {
+		setting.UpdatedAt = now
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"user_id": setting.UserID, "key": setting.Key}).
+			SetUpdate(bson.M{"$set": bson.M{"value": setting.Value, "updated_at": now}}).
+			SetUpsert(true)
+	}
+
+	if _, err := r.docs.Collection().BulkWrite(ctx, models); err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to save user settings")
+	}
+	return nil
+}
+
+// Delete removes settings of a user by key
+func (r *userSettingMongoRepository) Delete(ctx context.Context, userID uint, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	_, err := r.docs.Collection().DeleteMany(ctx, bson.M{"user_id": userID, "key": bson.M{"$in": keys}})
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to delete user settings")
+	}
+	return nil
+}
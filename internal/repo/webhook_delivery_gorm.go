@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// webhookDeliveryGormRepository implements WebhookDeliveryRepository for GORM-based databases
+type webhookDeliveryGormRepository struct {
+	*GormRepository[domain.WebhookDelivery]
+}
+
+// NewWebhookDeliveryGormRepository creates a new GORM-based webhook delivery repository
+func NewWebhookDeliveryGormRepository(db *gorm.DB) domain.WebhookDeliveryRepository {
+	return &webhookDeliveryGormRepository{
+		GormRepository: NewGormRepository[domain.WebhookDelivery](db, Entity{
+			Name:         "webhook delivery",
+			Plural:       "webhook deliveries",
+			NotFound:     domain.ErrWebhookDeliveryNotFound,
+			DefaultOrder: "created_at DESC, id DESC",
+		}),
+	}
+}
+
+// DeleteByWebhook deletes every delivery of a webhook
+func (r *webhookDeliveryGormRepository) DeleteByWebhook(ctx context.Context, webhookID uint) error {
+	if err := r.DB(ctx).Where("webhook_id = ?", webhookID).Delete(&domain.WebhookDelivery{}).Error; err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to delete webhook deliveries")
+	}
+	return nil
+}
+
+// ListByWebhook retrieves the deliveries of a webhook with pagination, newest first
+func (r *webhookDeliveryGormRepository) ListByWebhook(ctx context.Context, webhookID uint, offset, limit int) ([]*domain.WebhookDelivery, int64, error) {
+	return r.Paginate(ctx, r.Filtered(ctx, nil).Where("webhook_id = ?", webhookID), nil, offset, limit)
+}
+
+// ListDue retrieves up to limit pending deliveries whose next attempt is due at now, oldest first
+func (r *webhookDeliveryGormRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+	err := r.DB(ctx).
+		Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list due webhook deliveries")
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// webhookDeliveryMongoRepository implements WebhookDeliveryRepository for MongoDB
+type webhookDeliveryMongoRepository struct {
+	db   *mongo.Database
+	docs *MongoRepository[domain.WebhookDelivery]
+}
+
+// NewWebhookDeliveryMongoRepository creates a new MongoDB-based webhook delivery repository
+func NewWebhookDeliveryMongoRepository(db *mongo.Database) domain.WebhookDeliveryRepository {
+	return &webhookDeliveryMongoRepository{
+		db: db,
+		docs: NewMongoRepository[domain.WebhookDelivery](db.Collection(domain.TableName(domain.WebhookDelivery{})), Entity{
+			Name:     "webhook delivery",
+			Plural:   "webhook deliveries",
+			NotFound: domain.ErrWebhookDeliveryNotFound,
+		}),
+	}
+}
+
+// Create creates a new delivery with the next sequential ID
+func (r *webhookDeliveryMongoRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	id, err := NextMongoID(ctx, r.db, domain.TableName(domain.WebhookDelivery{}))
+	if err != nil {
+		return err
+	}
+
+	delivery.ID = id
+	stampCreated(&delivery.CreatedAt, &delivery.UpdatedAt)
+	_, err = r.docs.Create(ctx, delivery)
+	return err
+}
+
+// GetByID retrieves a delivery by ID
+func (r *webhookDeliveryMongoRepository) GetByID(ctx context.Context, id uint) (*domain.WebhookDelivery, error) {
+	return r.docs.FindOne(ctx, bson.M{"id": id})
+}
+
+// Update replaces an existing delivery. A cleared next attempt is unset so
+// finished deliveries don't keep their last schedule.
+func (r *webhookDeliveryMongoRepository) Update(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+	update := bson.M{"$set": delivery}
+	if delivery.NextAttemptAt == nil {
+		update["$unset"] = bson.M{"next_attempt_at": ""}
+	}
+	return r.docs.Update(ctx, bson.M{"id": delivery.ID}, update)
+}
+
+// DeleteByWebhook deletes every delivery of a webhook
+func (r *webhookDeliveryMongoRepository) DeleteByWebhook(ctx context.Context, webhookID uint) error {
+	if _, err := r.docs.Collection().DeleteMany(ctx, bson.M{"webhook_id": webhookID}); err != nil {
+		return domain.WrapError(err, domain.ErrCodeDatabase, "Failed to delete webhook deliveries")
+	}
+	return nil
+}
+
+// ListByWebhook retrieves the deliveries of a webhook with pagination, newest first
+func (r *webhookDeliveryMongoRepository) ListByWebhook(ctx context.Context, webhookID uint, offset, limit int) ([]*domain.WebhookDelivery, int64, error) {
+	sort := bson.D{{Key: "created_at", Value: -1}, {Key: "id", Value: -1}}
+	return r.docs.List(ctx, bson.M{"webhook_id": webhookID}, sort, offset, limit)
+}
+
+// ListDue retrieves up to limit pending deliveries whose next attempt is due at now, oldest first
+func (r *webhookDeliveryMongoRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	filter := bson.M{
+		"status":          domain.WebhookDeliveryPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "id", Value: 1}}).
+		SetLimit(int64(limit))
+	return r.docs.Find(ctx, filter, opts)
+}
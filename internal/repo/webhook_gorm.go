@@ -0,0 +1,38 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"gorm.io/gorm"
+)
+
+// webhookGormRepository implements WebhookRepository for GORM-based databases
+type webhookGormRepository struct {
+	*GormRepository[domain.Webhook]
+}
+
+// NewWebhookGormRepository creates a new GORM-based webhook repository
+func NewWebhookGormRepository(db *gorm.DB) domain.WebhookRepository {
+	return &webhookGormRepository{
+		GormRepository: NewGormRepository[domain.Webhook](db, Entity{
+			Name:         "webhook",
+			NotFound:     domain.ErrWebhookNotFound,
+			DefaultOrder: "created_at DESC, id DESC",
+		}),
+	}
+}
+
+// List retrieves webhooks with pagination, newest first
+func (r *webhookGormRepository) List(ctx context.Context, offset, limit int) ([]*domain.Webhook, int64, error) {
+	return r.GormRepository.List(ctx, nil, offset, limit)
+}
+
+// ListActive retrieves every active webhook
+func (r *webhookGormRepository) ListActive(ctx context.Context) ([]*domain.Webhook, error) {
+	var webhooks []*domain.Webhook
+	if err := r.DB(ctx).Where("active = ?", true).Order("id ASC").Find(&webhooks).Error; err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeDatabase, "Failed to list webhooks")
+	}
+	return webhooks, nil
+}
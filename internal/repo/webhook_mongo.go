@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// webhookMongoRepository implements WebhookRepository for MongoDB
+type webhookMongoRepository struct {
+	db   *mongo.Database
+	docs *MongoRepository[domain.Webhook]
+}
+
+// NewWebhookMongoRepository creates a new MongoDB-based webhook repository
+func NewWebhookMongoRepository(db *mongo.Database) domain.WebhookRepository {
+	return &webhookMongoRepository{
+		db: db,
+		docs: NewMongoRepository[domain.Webhook](db.Collection(domain.TableName(domain.Webhook{})), Entity{
+			Name:     "webhook",
+			NotFound: domain.ErrWebhookNotFound,
+		}),
+	}
+}
+
+// Create creates a new webhook with the next sequential ID
+func (r *webhookMongoRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	id, err := NextMongoID(ctx, r.db, domain.TableName(domain.Webhook{}))
+	if err != nil {
+		return err
+	}
+
+	webhook.ID = id
+	stampCreated(&webhook.CreatedAt, &webhook.UpdatedAt)
+	_, err = r.docs.Create(ctx, webhook)
+	return err
+}
+
+// GetByID retrieves a webhook by ID
+func (r *webhookMongoRepository) GetByID(ctx context.Context, id uint) (*domain.Webhook, error) {
+	return r.docs.FindOne(ctx, bson.M{"id": id})
+}
+
+// Update replaces an existing webhook
+func (r *webhookMongoRepository) Update(ctx context.Context, webhook *domain.Webhook) error {
+	webhook.UpdatedAt = time.Now()
+	return r.docs.Update(ctx, bson.M{"id": webhook.ID}, bson.M{"$set": webhook})
+}
+
+// Delete deletes a webhook
+func (r *webhookMongoRepository) Delete(ctx context.Context, id uint) error {
+	return r.docs.Delete(ctx, bson.M{"id": id})
+}
+
+// List retrieves webhooks with pagination, newest first
+func (r *webhookMongoRepository) List(ctx context.Context, offset, limit int) ([]*domain.Webhook, int64, error) {
+	sort := bson.D{{Key: "created_at", Value: -1}, {Key: "id", Value: -1}}
+	return r.docs.List(ctx, bson.M{}, sort, offset, limit)
+}
+
+// ListActive retrieves every active webhook
+func (r *webhookMongoRepository) ListActive(ctx context.Context) ([]*domain.Webhook, error) {
+	return r.docs.Find(ctx, bson.M{"active": true}, options.Find().SetSort(bson.D{{Key: "id", Value: 1}}))
+}
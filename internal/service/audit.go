@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// AuditServiceParams holds dependencies for AuditService
+type AuditServiceParams struct {
+	fx.In
+	AuditLogRepo domain.AuditLogRepository
+}
+
+// auditService implements domain.AuditService
+type auditService struct {
+	auditLogRepo domain.AuditLogRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(p AuditServiceParams) domain.AuditService {
+	return &auditService{
+		auditLogRepo: p.AuditLogRepo,
+	}
+}
+
+// Record stores an audit log entry, filling actor and IP from the context
+func (s *auditService) Record(ctx context.Context, entry *domain.AuditLog) error {
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		if entry.ActorID == 0 {
+			entry.ActorID = actor.UserID
+		}
+		if entry.IP == "" {
+			entry.IP = actor.IP
+		}
+	}
+
+	return s.auditLogRepo.Create(ctx, entry)
+}
+
+// List retrieves audit log entries with pagination
+func (s *auditService) List(ctx context.Context, filter domain.AuditLogFilter, offset, limit int) ([]*domain.AuditLog, int64, error) {
+	return s.auditLogRepo.List(ctx, filter, offset, limit)
+}
+
+// recordAudit records an audit entry without failing the calling operation
+func recordAudit(ctx context.Context, auditService domain.AuditService, entry *domain.AuditLog) {
+	if err := auditService.Record(ctx, entry); err != nil {
+		logger.FromContext(ctx).Error("failed to record audit log",
+			zap.String("action", entry.Action),
+			zap.Uint("target_id", entry.TargetID),
+			zap.Error(err),
+		)
+	}
+}
@@ -2,49 +2,102 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/luxixing/fx-gin-scaffold/internal/config"
 	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/jwtkeys"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/utils"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// refreshTokenLength is the length of generated refresh tokens
+	refreshTokenLength = 64
+
+	// tokenIDLength is the length of generated access token IDs (jti)
+	tokenIDLength = 32
+
+	// sessionIDLength is the length of generated session IDs
+	sessionIDLength = 32
+
+	// maxUserAgentLength bounds the user agent stored with a session
+	maxUserAgentLength = 255
+
+	// emailChangeKeySuffix derives the email change signing key from the JWT
+	// secret so confirmation tokens can never pass as access tokens. These
+	// tokens never leave this service, so they stay HS256 whatever the
+	// access token algorithm.
+	emailChangeKeySuffix = ":email-change"
 )
 
 // AuthServiceParams holds dependencies for AuthService
 type AuthServiceParams struct {
 	fx.In
-	Config *config.Config
+	Config           *config.Config
+	UserRepo         domain.UserRepository
+	RefreshTokenRepo domain.RefreshTokenRepository
+	TokenBlacklist   domain.TokenBlacklist
+	TxManager        domain.TxManager
+	Keys             *jwtkeys.KeySet
 }
 
 // authService implements domain.AuthService
 type authService struct {
-	config *config.Config
+	config           *config.Config
+	userRepo         domain.UserRepository
+	refreshTokenRepo domain.RefreshTokenRepository
+	tokenBlacklist   domain.TokenBlacklist
+	txManager        domain.TxManager
+	keys             *jwtkeys.KeySet
 }
 
 // NewAuthService creates a new auth service
 func NewAuthService(p AuthServiceParams) domain.AuthService {
 	return &authService{
-		config: p.Config,
+		config:           p.Config,
+		userRepo:         p.UserRepo,
+		refreshTokenRepo: p.RefreshTokenRepo,
+		tokenBlacklist:   p.TokenBlacklist,
+		txManager:        p.TxManager,
+		keys:             p.Keys,
 	}
 }
 
 // GenerateToken generates a JWT token for the user
 func (s *authService) GenerateToken(user *domain.User) (string, error) {
+	return s.generateAccessToken(user, "")
+}
+
+// generateAccessToken generates a JWT access token bound to a session
+func (s *authService) generateAccessToken(user *domain.User, sessionID string) (string, error) {
+	tokenID, err := utils.GenerateRandomString(tokenIDLength)
+	if err != nil {
+		return "", domain.WrapError(err, domain.ErrCodeInternal, "Failed to generate token")
+	}
+
 	claims := &domain.JWTClaims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.JWT.Expiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "fx-gin-scaffold",
 			Subject:   user.Email,
+			ID:        tokenID,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.config.JWT.Secret))
+	tokenString, err := s.keys.Sign(claims)
 	if err != nil {
 		return "", domain.WrapError(err, domain.ErrCodeInternal, "Failed to generate token")
 	}
@@ -54,13 +107,8 @@ func (s *authService) GenerateToken(user *domain.User) (string, error) {
 
 // ValidateToken validates a JWT token and returns claims
 func (s *authService) ValidateToken(tokenString string) (*domain.JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &domain.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, domain.NewError(domain.ErrCodeInvalidToken, "Invalid signing method")
-		}
-		return []byte(s.config.JWT.Secret), nil
-	})
+	// The key set checks the signing method and resolves the key by kid
+	token, err := jwt.ParseWithClaims(tokenString, &domain.JWTClaims{}, s.keys.Keyfunc)
 
 	if err != nil {
 		return nil, domain.ErrInvalidToken
@@ -78,37 +126,230 @@ func (s *authService) ValidateToken(tokenString string) (*domain.JWTClaims, erro
 	return claims, nil
 }
 
-// RefreshToken refreshes an existing token
-func (s *authService) RefreshToken(ctx context.Context, tokenString string) (string, error) {
+// IssueTokenPair starts a new session and issues its token pair
+func (s *authService) IssueTokenPair(ctx context.Context, user *domain.User) (*domain.TokenPair, error) {
+	sessionID, err := utils.GenerateRandomString(sessionIDLength)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to generate session")
+	}
+	return s.issueTokenPair(ctx, user, sessionID)
+}
+
+// issueTokenPair issues an access token and a persisted refresh token for a
+// session, recording the device of the context's actor
+func (s *authService) issueTokenPair(ctx context.Context, user *domain.User, sessionID string) (*domain.TokenPair, error) {
+	accessToken, err := s.generateAccessToken(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := utils.GenerateRandomString(refreshTokenLength)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to generate refresh token")
+	}
+
+	actor, _ := domain.ActorFromContext(ctx)
+	userAgent := actor.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	record := &domain.RefreshToken{
+		UserID:    user.ID,
+		SessionID: sessionID,
+		TokenHash: hashToken(refreshToken),
+		UserAgent: userAgent,
+		IP:        actor.IP,
+		ExpiresAt: time.Now().Add(s.config.JWT.RefreshExpiration),
+	}
+	if err := s.refreshTokenRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().Add(s.config.JWT.Expiration),
+	}, nil
+}
+
+// RefreshToken rotates a refresh token and returns a new token pair
+func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	// Read from the primary so a token rotated moments ago is already seen as revoked
+	record, err := s.refreshTokenRepo.GetByHash(database.WithPrimary(ctx), hashToken(refreshToken))
+	if err != nil {
+		if err == domain.ErrTokenNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	// A revoked token being presented again indicates it was stolen, so revoke the whole family
+	if record.IsRevoked() {
+		logger.FromContext(ctx).Warn("revoked refresh token reused", zap.Uint("user_id", record.UserID))
+		if err := s.refreshTokenRepo.RevokeAllForUser(ctx, record.UserID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidToken
+	}
+
+	if record.IsExpired() {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// Load the current user so role and status changes are reflected in the new token
+	user, err := s.userRepo.GetByID(ctx, record.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if !user.Active {
+		return nil, domain.NewError(domain.ErrCodeForbidden, "Account is deactivated")
+	}
+
+	// Rotate: the presented token can only be used once, and is only spent
+	// if its replacement was stored
+	var pair *domain.TokenPair
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.refreshTokenRepo.Revoke(ctx, record.TokenHash); err != nil {
+			if err == domain.ErrTokenNotFound {
+				return domain.ErrInvalidToken
+			}
+			return err
+		}
+
+		// Tokens issued before sessions were tracked start a new session
+		var err error
+		if record.SessionID == "" {
+			pair, err = s.IssueTokenPair(ctx, user)
+		} else {
+			pair, err = s.issueTokenPair(ctx, user, record.SessionID)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// RevokeRefreshToken revokes a refresh token
+func (s *authService) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	if err := s.refreshTokenRepo.Revoke(ctx, hashToken(refreshToken)); err != nil {
+		if err == domain.ErrTokenNotFound {
+			return domain.ErrInvalidToken
+		}
+		return err
+	}
+	return nil
+}
+
+// RevokeAccessToken blacklists an access token until it expires
+func (s *authService) RevokeAccessToken(ctx context.Context, tokenString string) error {
 	claims, err := s.ValidateToken(tokenString)
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return domain.ErrInvalidToken
+	}
+
+	return s.tokenBlacklist.Add(ctx, claims.ID, claims.ExpiresAt.Time)
+}
+
+// RevokeAllRefreshTokens revokes every refresh token of a user
+func (s *authService) RevokeAllRefreshTokens(ctx context.Context, userID uint) error {
+	return s.refreshTokenRepo.RevokeAllForUser(ctx, userID)
+}
+
+// ListSessions retrieves the user's active sessions, flagging the current one
+func (s *authService) ListSessions(ctx context.Context, userID uint, currentSessionID string) ([]*domain.Session, error) {
+	tokens, err := s.refreshTokenRepo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check if token is close to expiration (within 1 hour)
-	if time.Until(claims.ExpiresAt.Time) > time.Hour {
-		return "", domain.NewError(domain.ErrCodeInvalid, "Token is not close to expiration")
+	// Tokens are newest first, so the first token seen per session is its latest
+	sessions := make([]*domain.Session, 0, len(tokens))
+	seen := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		if token.SessionID == "" || seen[token.SessionID] {
+			continue
+		}
+		seen[token.SessionID] = true
+		sessions = append(sessions, &domain.Session{
+			ID:         token.SessionID,
+			UserAgent:  token.UserAgent,
+			IP:         token.IP,
+			LastSeenAt: token.CreatedAt,
+			ExpiresAt:  token.ExpiresAt,
+			Current:    token.SessionID == currentSessionID,
+		})
 	}
 
-	// Create new token with updated expiration
-	newClaims := &domain.JWTClaims{
-		UserID: claims.UserID,
-		Email:  claims.Email,
-		Role:   claims.Role,
+	return sessions, nil
+}
+
+// RevokeSession ends a session. Its refresh tokens are revoked and the
+// session is blacklisted for the lifetime of the access tokens it issued.
+func (s *authService) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	if err := s.refreshTokenRepo.RevokeSession(ctx, userID, sessionID); err != nil {
+		if err == domain.ErrTokenNotFound {
+			return domain.ErrSessionNotFound
+		}
+		return err
+	}
+
+	return s.tokenBlacklist.Add(ctx, domain.SessionBlacklistKey(sessionID), time.Now().Add(s.config.JWT.Expiration))
+}
+
+// GenerateEmailChangeToken generates a token confirming the user's pending email
+func (s *authService) GenerateEmailChangeToken(user *domain.User) (string, error) {
+	claims := &domain.EmailChangeClaims{
+		UserID: user.ID,
+		Email:  user.PendingEmail,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.JWT.Expiration)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.JWT.EmailChangeExpiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "fx-gin-scaffold",
-			Subject:   claims.Email,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims)
-	newTokenString, err := token.SignedString([]byte(s.config.JWT.Secret))
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(s.config.JWT.Secret + emailChangeKeySuffix))
 	if err != nil {
-		return "", domain.WrapError(err, domain.ErrCodeInternal, "Failed to refresh token")
+		return "", domain.WrapError(err, domain.ErrCodeInternal, "Failed to generate token")
 	}
 
-	return newTokenString, nil
-}
\ No newline at end of file
+	return tokenString, nil
+}
+
+// ValidateEmailChangeToken validates an email change token and returns its claims
+func (s *authService) ValidateEmailChangeToken(tokenString string) (*domain.EmailChangeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &domain.EmailChangeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, domain.NewError(domain.ErrCodeInvalidToken, "Invalid signing method")
+		}
+		return []byte(s.config.JWT.Secret + emailChangeKeySuffix), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, domain.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*domain.EmailChangeClaims)
+	if !ok || claims.UserID == 0 || claims.Email == "" {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// hashToken returns the SHA-256 hex digest used to store refresh tokens
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/mocks"
+	"github.com/luxixing/fx-gin-scaffold/pkg/jwtkeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWTSecret = "unit-test-secret-that-is-long-enough"
+
+// authServiceMocks holds the mocked dependencies of an authService
+type authServiceMocks struct {
+	users     *mocks.UserRepository
+	tokens    *mocks.RefreshTokenRepository
+	blacklist *mocks.TokenBlacklist
+}
+
+// newMockedAuthService creates an authService signing HS256 tokens with
+// testJWTSecret. Transactions always succeed.
+func newMockedAuthService(t *testing.T, expiration time.Duration) (domain.AuthService, *authServiceMocks) {
+	t.Helper()
+
+	m := &authServiceMocks{
+		users:     mocks.NewUserRepository(t),
+		tokens:    mocks.NewRefreshTokenRepository(t),
+		blacklist: mocks.NewTokenBlacklist(t),
+	}
+	tx := mocks.NewTxManager(t)
+	tx.On("WithinTransaction", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }).Maybe()
+
+	keys, err := jwtkeys.NewKeySet(jwtkeys.Config{Algorithm: jwtkeys.AlgorithmHS256, Secret: testJWTSecret})
+	require.NoError(t, err)
+
+	cfg := &config.Config{}
+	cfg.JWT.Secret = testJWTSecret
+	cfg.JWT.Expiration = expiration
+	cfg.JWT.RefreshExpiration = 24 * time.Hour
+	cfg.JWT.EmailChangeExpiration = time.Hour
+
+	service := NewAuthService(AuthServiceParams{
+		Config:           cfg,
+		UserRepo:         m.users,
+		RefreshTokenRepo: m.tokens,
+		TokenBlacklist:   m.blacklist,
+		TxManager:        tx,
+		Keys:             keys,
+	})
+	return service, m
+}
+
+func TestAuthServiceAccessTokens(t *testing.T) {
+	service, _ := newMockedAuthService(t, time.Hour)
+	user := storedUser()
+
+	token, err := service.GenerateToken(user)
+	require.NoError(t, err)
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, user.Role, claims.Role)
+	assert.NotEmpty(t, claims.ID)
+
+	// Tampered tokens and tokens signed with another secret are rejected
+	_, err = service.ValidateToken(token + "x")
+	assert.Equal(t, domain.ErrInvalidToken, err)
+
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("another-secret"))
+	require.NoError(t, err)
+	_, err = service.ValidateToken(forged)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+
+	// Unsigned tokens are rejected
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = service.ValidateToken(unsigned)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+
+	// Expired tokens are rejected
+	expired, _ := newMockedAuthService(t, -time.Minute)
+	token, err = expired.GenerateToken(user)
+	require.NoError(t, err)
+	_, err = expired.ValidateToken(token)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+}
+
+func TestAuthServiceEmailChangeTokens(t *testing.T) {
+	service, _ := newMockedAuthService(t, time.Hour)
+	user := storedUser()
+	user.PendingEmail = "new@example.com"
+
+	token, err := service.GenerateEmailChangeToken(user)
+	require.NoError(t, err)
+	claims, err := service.ValidateEmailChangeToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, "new@example.com", claims.Email)
+
+	// The two token kinds are signed with different keys and never interchangeable
+	_, err = service.ValidateToken(token)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+
+	access, err := service.GenerateToken(user)
+	require.NoError(t, err)
+	_, err = service.ValidateEmailChangeToken(access)
+	assert.Equal(t, domain.ErrInvalidToken, err)
+}
+
+func TestAuthServiceRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	revokedAt := time.Now().Add(-time.Minute)
+	active := func() *domain.RefreshToken {
+		return &domain.RefreshToken{UserID: 7, SessionID: "session", TokenHash: hashToken("refresh"), ExpiresAt: time.Now().Add(time.Hour)}
+	}
+
+	t.Run("rejects unknown tokens", func(t *testing.T) {
+		service, m := newMockedAuthService(t, time.Hour)
+		m.tokens.On("GetByHash", mock.Anything, hashToken("refresh")).Return(nil, domain.ErrTokenNotFound)
+
+		_, err := service.RefreshToken(ctx, "refresh")
+		assert.Equal(t, domain.ErrInvalidToken, err)
+	})
+
+	t.Run("revokes the token family when a revoked token is reused", func(t *testing.T) {
+		service, m := newMockedAuthService(t, time.Hour)
+		record := active()
+		record.RevokedAt = &revokedAt
+		m.tokens.On("GetByHash", mock.Anything, hashToken("refresh")).Return(record, nil)
+		m.tokens.On("RevokeAllForUser", ctx, uint(7)).Return(nil)
+
+		_, err := service.RefreshToken(ctx, "refresh")
+		assert.Equal(t, domain.ErrInvalidToken, err)
+	})
+
+	t.Run("rejects expired tokens", func(t *testing.T) {
+		service, m := newMockedAuthService(t, time.Hour)
+		record := active()
+		record.ExpiresAt = time.Now().Add(-time.Second)
+		m.tokens.On("GetByHash", mock.Anything, hashToken("refresh")).Return(record, nil)
+
+		_, err := service.RefreshToken(ctx, "refresh")
+		assert.Equal(t, domain.ErrInvalidToken, err)
+		m.tokens.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects tokens of deleted and deactivated users", func(t *testing.T) {
+		service, m := newMockedAuthService(t, time.Hour)
+		m.tokens.On("GetByHash", mock.Anything, hashToken("refresh")).Return(active(), nil)
+		m.users.On("GetByID", ctx, uint(7)).Return(nil, domain.ErrUserNotFound).Once()
+
+		_, err := service.RefreshToken(ctx, "refresh")
+		assert.Equal(t, domain.ErrInvalidToken, err)
+
+		user := storedUser()
+		user.Active = false
+		m.users.On("GetByID", ctx, uint(7)).Return(user, nil).Once()
+
+		_, err = service.RefreshToken(ctx, "refresh")
+		requireCode(t, err, domain.ErrCodeForbidden)
+	})
+
+	t.Run("rotates the token within its session", func(t *testing.T) {
+		service, m := newMockedAuthService(t, time.Hour)
+		m.tokens.On("GetByHash", mock.Anything, hashToken("refresh")).Return(active(), nil)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.tokens.On("Revoke", ctx, hashToken("refresh")).Return(nil)
+		m.tokens.On("Create", ctx, mock.MatchedBy(func(record *domain.RefreshToken) bool {
+			return record.UserID == 7 && record.SessionID == "session" && record.TokenHash != hashToken("refresh")
+		})).Return(nil)
+
+		pair, err := service.RefreshToken(ctx, "refresh")
+		require.NoError(t, err)
+		assert.NotEqual(t, "refresh", pair.RefreshToken)
+
+		claims, err := service.ValidateToken(pair.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "session", claims.SessionID)
+	})
+
+	t.Run("loses a race against a concurrent rotation", func(t *testing.T) {
+		service, m := newMockedAuthService(t, time.Hour)
+		m.tokens.On("GetByHash", mock.Anything, hashToken("refresh")).Return(active(), nil)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.tokens.On("Revoke", ctx, hashToken("refresh")).Return(domain.ErrTokenNotFound)
+
+		_, err := service.RefreshToken(ctx, "refresh")
+		assert.Equal(t, domain.ErrInvalidToken, err)
+		m.tokens.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthServiceRevocation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("blacklists access tokens by ID until they expire", func(t *testing.T) {
+		service, m := newMockedAuthService(t, time.Hour)
+		token, err := service.GenerateToken(storedUser())
+		require.NoError(t, err)
+		claims, err := service.ValidateToken(token)
+		require.NoError(t, err)
+		m.blacklist.On("Add", ctx, claims.ID, claims.ExpiresAt.Time).Return(nil)
+
+		assert.NoError(t, service.RevokeAccessToken(ctx, token))
+		assert.Equal(t, domain.ErrInvalidToken, service.RevokeAccessToken(ctx, "not-a-token"))
+	})
+
+	t.Run("maps unknown refresh tokens and sessions", func(t *testing.T) {
+		service, m := newMockedAuthService(t, time.Hour)
+		m.tokens.On("Revoke", ctx, hashToken("refresh")).Return(domain.ErrTokenNotFound)
+		m.tokens.On("RevokeSession", ctx, uint(7), "session").Return(domain.ErrTokenNotFound)
+
+		assert.Equal(t, domain.ErrInvalidToken, service.RevokeRefreshToken(ctx, "refresh"))
+		assert.Equal(t, domain.ErrSessionNotFound, service.RevokeSession(ctx, 7, "session"))
+		m.blacklist.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("blacklists revoked sessions", func(t *testing.T) {
+		service, m := newMockedAuthService(t, time.Hour)
+		m.tokens.On("RevokeSession", ctx, uint(7), "session").Return(nil)
+		m.blacklist.On("Add", ctx, domain.SessionBlacklistKey("session"), mock.AnythingOfType("time.Time")).Return(nil)
+
+		assert.NoError(t, service.RevokeSession(ctx, 7, "session"))
+	})
+}
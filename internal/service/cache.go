@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Cache keys for user reads. List keys embed a generation number that is
+// bumped on every change, orphaning old pages until their TTL expires.
+const (
+	userCacheKeyPrefix     = "cache:user:"
+	userListCacheKeyPrefix = "cache:users:"
+	userListGenerationKey  = "cache:users:generation"
+)
+
+// cacheAside returns the value cached at key, loading and caching it on a
+// miss. A zero TTL disables caching. Cache failures are logged and fall
+// back to the loader, so the cache never fails a read.
+func cacheAside[T any](ctx context.Context, client cache.Client, key string, ttl time.Duration, load func() (T, error)) (T, error) {
+	if ttl <= 0 {
+		return load()
+	}
+
+	var value T
+	cached, err := client.Get(ctx, key)
+	if err == nil {
+		if err := json.Unmarshal([]byte(cached), &value); err == nil {
+			return value, nil
+		}
+	} else if !errors.Is(err, cache.ErrCacheMiss) {
+		logger.FromContext(ctx).Warn("failed to read cache", zap.String("key", key), zap.Error(err))
+	}
+
+	value, err = load()
+	if err != nil {
+		return value, err
+	}
+
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = client.Set(ctx, key, string(data), ttl)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to write cache", zap.String("key", key), zap.Error(err))
+	}
+
+	return value, nil
+}
+
+// userCacheKey returns the cache key of a single user
+func userCacheKey(id uint) string {
+	return fmt.Sprintf("%s%d", userCacheKeyPrefix, id)
+}
+
+// userListCacheKey returns the cache key of a page of users in the current
+// list generation
+func userListCacheKey(ctx context.Context, client cache.Client, query *domain.Query, opts domain.ListOptions, offset, limit int) string {
+	generation, err := client.Get(ctx, userListGenerationKey)
+	if err != nil {
+		generation = "0"
+	}
+
+	params, _ := json.Marshal(struct {
+		Filters []domain.Filter
+		Sorts   []domain.Sort
+		Options domain.ListOptions
+		Offset  int
+		Limit   int
+	}{query.Filters(), query.Sorts(), opts, offset, limit})
+	sum := sha256.Sum256(params)
+
+	return userListCacheKeyPrefix + generation + ":" + hex.EncodeToString(sum[:16])
+}
+
+// invalidateUserCache drops the cached user and every cached user list.
+// A zero ID only drops the lists, e.g. after a registration.
+func invalidateUserCache(ctx context.Context, client cache.Client, id uint) {
+	if id != 0 {
+		if err := client.Delete(ctx, userCacheKey(id)); err != nil {
+			logger.FromContext(ctx).Warn("failed to invalidate user cache", zap.Uint("user_id", id), zap.Error(err))
+		}
+	}
+	if _, err := client.Incr(ctx, userListGenerationKey); err != nil {
+		logger.FromContext(ctx).Warn("failed to invalidate user list cache", zap.Error(err))
+	}
+}
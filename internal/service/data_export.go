@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// dataExportPageSize is the number of audit log entries read per query
+const dataExportPageSize = 500
+
+// DataExportServiceParams holds dependencies for DataExportService
+type DataExportServiceParams struct {
+	fx.In
+	UserRepo            domain.UserRepository
+	AuditLogRepo        domain.AuditLogRepository
+	AuthService         domain.AuthService
+	UserSettingsService domain.UserSettingsService
+	AuditService        domain.AuditService
+}
+
+// dataExportService implements domain.DataExportService
+type dataExportService struct {
+	userRepo            domain.UserRepository
+	auditLogRepo        domain.AuditLogRepository
+	authService         domain.AuthService
+	userSettingsService domain.UserSettingsService
+	auditService        domain.AuditService
+}
+
+// NewDataExportService creates a new data export service
+func NewDataExportService(p DataExportServiceParams) domain.DataExportService {
+	return &dataExportService{
+		userRepo:            p.UserRepo,
+		auditLogRepo:        p.AuditLogRepo,
+		authService:         p.AuthService,
+		userSettingsService: p.UserSettingsService,
+		auditService:        p.AuditService,
+	}
+}
+
+// Export collects the user's profile, settings, sessions and audit log
+// entries. The export itself is recorded in the audit log.
+func (s *dataExportService) Export(ctx context.Context, userID uint) (*domain.DataExport, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.userSettingsService.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.authService.ListSessions(ctx, userID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	auditLogs, err := s.listAuditLogs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.auditService, &domain.AuditLog{
+		ActorID:    userID,
+		Action:     domain.AuditActionDataExport,
+		TargetType: "user",
+		TargetID:   userID,
+	})
+
+	return &domain.DataExport{
+		ExportedAt: time.Now().UTC(),
+		Profile:    user.ToResponse(),
+		Settings:   settings,
+		Sessions:   sessions,
+		AuditLogs:  auditLogs,
+	}, nil
+}
+
+// listAuditLogs reads every audit log entry of the user's actions
+func (s *dataExportService) listAuditLogs(ctx context.Context, userID uint) ([]*domain.AuditLog, error) {
+	filter := domain.AuditLogFilter{ActorID: userID}
+	entries := make([]*domain.AuditLog, 0)
+	for {
+		page, total, err := s.auditLogRepo.List(ctx, filter, len(entries), dataExportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+		if len(page) == 0 || int64(len(entries)) >= total {
+			return entries, nil
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataExportService(t *testing.T) {
+	ctx := context.Background()
+
+	users := mocks.NewUserRepository(t)
+	auditLogs := mocks.NewAuditLogRepository(t)
+	auth := mocks.NewAuthService(t)
+	settings := mocks.NewUserSettingsService(t)
+	audit := mocks.NewAuditService(t)
+
+	users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+	settings.On("GetSettings", ctx, uint(7)).Return(domain.UserSettings{"theme": "dark"}, nil)
+	auth.On("ListSessions", ctx, uint(7), "").Return([]*domain.Session{{ID: "session"}}, nil)
+
+	// Audit log entries are read page by page
+	filter := domain.AuditLogFilter{ActorID: 7}
+	firstPage := make([]*domain.AuditLog, dataExportPageSize)
+	for i := range firstPage {
+		firstPage[i] = &domain.AuditLog{ActorID: 7, Action: domain.AuditActionLogin}
+	}
+	auditLogs.On("List", ctx, filter, 0, dataExportPageSize).Return(firstPage, int64(dataExportPageSize+1), nil)
+	auditLogs.On("List", ctx, filter, dataExportPageSize, dataExportPageSize).
+		Return([]*domain.AuditLog{{ActorID: 7, Action: domain.AuditActionEmailChange}}, int64(dataExportPageSize+1), nil)
+
+	audit.On("Record", ctx, mock.MatchedBy(func(entry *domain.AuditLog) bool {
+		return entry.Action == domain.AuditActionDataExport && entry.TargetID == 7
+	})).Return(nil)
+
+	service := NewDataExportService(DataExportServiceParams{
+		UserRepo:            users,
+		AuditLogRepo:        auditLogs,
+		AuthService:         auth,
+		UserSettingsService: settings,
+		AuditService:        audit,
+	})
+
+	export, err := service.Export(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", export.Profile.Email)
+	assert.Equal(t, "dark", export.Settings["theme"])
+	assert.Len(t, export.Sessions, 1)
+	assert.Len(t, export.AuditLogs, dataExportPageSize+1)
+
+	// The archive holds a JSON file per section
+	var archive bytes.Buffer
+	require.NoError(t, export.WriteZIP(&archive))
+	reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	require.NoError(t, err)
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"profile.json", "settings.json", "sessions.json", "audit_logs.json"}, names)
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/events"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// publishEvent publishes a domain event without failing the calling
+// operation; the change it describes has already been made
+func publishEvent(ctx context.Context, bus *events.Bus, event events.Event) {
+	if err := bus.Publish(ctx, event); err != nil {
+		logger.FromContext(ctx).Error("event handler failed",
+			zap.String("event", event.EventName()),
+			zap.Error(err),
+		)
+	}
+}
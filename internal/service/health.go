@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/migration"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"go.uber.org/fx"
+)
+
+// healthCheckTimeout bounds each dependency probe
+const healthCheckTimeout = 2 * time.Second
+
+// HealthServiceParams holds dependencies for HealthService
+type HealthServiceParams struct {
+	fx.In
+	Config *config.Config
+	DB     *database.Connection
+	Cache  cache.Client
+	// Secondary is the DB_SECONDARY_DRIVER database, nil when none is
+	// configured
+	Secondary *database.Connection `name:"secondary" optional:"true"`
+}
+
+// healthProbe checks a single dependency
+type healthProbe func(ctx context.Context) error
+
+// healthService implements domain.HealthService
+type healthService struct {
+	probes   map[string]healthProbe
+	draining atomic.Bool
+}
+
+// NewHealthService creates a new health service
+func NewHealthService(p HealthServiceParams) domain.HealthService {
+	s := &healthService{
+		probes: map[string]healthProbe{
+			"database":   p.DB.Health,
+			"migrations": migrationsProbe(p.DB),
+		},
+	}
+
+	if p.Secondary != nil {
+		s.probes["secondary_database"] = p.Secondary.Health
+	}
+	if p.Config.IsRedisEnabled() {
+		s.probes["redis"] = p.Cache.Health
+	}
+
+	return s
+}
+
+// Readiness probes every dependency concurrently; dependencies are not
+// probed once the application drains
+func (s *healthService) Readiness(ctx context.Context) *domain.HealthReport {
+	if s.draining.Load() {
+		return &domain.HealthReport{Status: domain.HealthStatusDraining, Time: time.Now().UTC()}
+	}
+
+	report := &domain.HealthReport{
+		Status: domain.HealthStatusOK,
+		Checks: make(map[string]domain.HealthCheck, len(s.probes)),
+		Time:   time.Now().UTC(),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, probe := range s.probes {
+		wg.Add(1)
+		go func(name string, probe healthProbe) {
+			defer wg.Done()
+
+			check := runProbe(ctx, probe)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = check
+			if check.Status != domain.HealthStatusUp {
+				report.Status = domain.HealthStatusDegraded
+			}
+		}(name, probe)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// Drain makes readiness report draining
+func (s *healthService) Drain() {
+	s.draining.Store(true)
+}
+
+// runProbe executes a probe with a timeout and measures its latency
+func runProbe(ctx context.Context, probe healthProbe) domain.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := probe(ctx)
+	check := domain.HealthCheck{
+		Status:    domain.HealthStatusUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		check.Status = domain.HealthStatusDown
+		check.Error = err.Error()
+	}
+	return check
+}
+
+// migrationsProbe reports an error while the schema drifts from the
+// registered migrations: migrations are pending, applied ones were modified,
+// or the database has migrations this release doesn't know
+func migrationsProbe(db *database.Connection) healthProbe {
+	return func(ctx context.Context) error {
+		migrator := migration.NewMigrator(db)
+		migration.RegisterMigrations(migrator)
+
+		drift, err := migrator.Drift(ctx)
+		if err != nil {
+			return err
+		}
+		if !drift.InSync() {
+			return errors.New(drift.String())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthServiceReadiness(t *testing.T) {
+	var probed int
+	cacheErr := errors.New("connection refused")
+	service := &healthService{probes: map[string]healthProbe{
+		"database": func(context.Context) error { probed++; return nil },
+		"redis":    func(context.Context) error { return cacheErr },
+	}}
+
+	report := service.Readiness(context.Background())
+	assert.Equal(t, domain.HealthStatusDegraded, report.Status)
+	assert.Equal(t, domain.HealthStatusUp, report.Checks["database"].Status)
+	assert.Equal(t, domain.HealthStatusDown, report.Checks["redis"].Status)
+	assert.Equal(t, cacheErr.Error(), report.Checks["redis"].Error)
+
+	// Draining reports not ready without probing dependencies
+	service.Drain()
+	report = service.Readiness(context.Background())
+	assert.Equal(t, domain.HealthStatusDraining, report.Status)
+	assert.False(t, report.Healthy())
+	assert.Empty(t, report.Checks)
+	assert.Equal(t, 1, probed)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/mailer"
+	"github.com/luxixing/fx-gin-scaffold/pkg/utils"
+	"go.uber.org/fx"
+)
+
+// inviteCodeLength is the length of generated invite codes
+const inviteCodeLength = 32
+
+// InviteServiceParams holds dependencies for InviteService
+type InviteServiceParams struct {
+	fx.In
+	Config       *config.Config
+	InviteRepo   domain.InviteRepository
+	UserRepo     domain.UserRepository
+	AuditService domain.AuditService
+	Mailer       mailer.Mailer
+	MailRenderer *mailer.Renderer
+	Validator    domain.Validator
+}
+
+// inviteService implements domain.InviteService
+type inviteService struct {
+	config       *config.Config
+	inviteRepo   domain.InviteRepository
+	userRepo     domain.UserRepository
+	auditService domain.AuditService
+	mailer       mailer.Mailer
+	mailRenderer *mailer.Renderer
+	validator    domain.Validator
+}
+
+// NewInviteService creates a new invite service
+func NewInviteService(p InviteServiceParams) domain.InviteService {
+	return &inviteService{
+		config:       p.Config,
+		inviteRepo:   p.InviteRepo,
+		userRepo:     p.UserRepo,
+		auditService: p.AuditService,
+		mailer:       p.Mailer,
+		mailRenderer: p.MailRenderer,
+		validator:    p.Validator,
+	}
+}
+
+// CreateInvite stores an invite and emails its code to the invitee
+func (s *inviteService) CreateInvite(ctx context.Context, req *domain.InviteCreateRequest) (*domain.InviteResponse, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+	if !s.config.RoleAssignable(req.Role) {
+		return nil, domain.ValidationError("role", "is not in the role catalog")
+	}
+
+	email := req.Email
+	if _, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		return nil, domain.ErrUserExists
+	} else if err != domain.ErrUserNotFound {
+		return nil, err
+	}
+
+	code, err := utils.GenerateRandomString(inviteCodeLength)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to generate invite code")
+	}
+
+	actor, _ := domain.ActorFromContext(ctx)
+	invite := &domain.Invite{
+		Email:       email,
+		Role:        req.Role,
+		TokenHash:   hashToken(code),
+		InvitedByID: actor.UserID,
+		ExpiresAt:   time.Now().Add(s.config.Registration.InviteExpiration),
+	}
+	if err := s.inviteRepo.Create(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	link := strings.TrimRight(s.config.App.URL, "/") + "/register?invite_code=" + url.QueryEscape(code)
+	msg, err := s.mailRenderer.Message("registration_invite", map[string]interface{}{
+		"Role":      req.Role,
+		"Code":      code,
+		"Link":      link,
+		"ExpiresIn": s.config.Registration.InviteExpiration.String(),
+	}, "You have been invited to register", email)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to render invite email")
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to send invite email")
+	}
+
+	response := invite.ToResponse()
+	recordAudit(ctx, s.auditService, &domain.AuditLog{
+		Action:     domain.AuditActionInviteCreate,
+		TargetType: "invite",
+		TargetID:   invite.ID,
+		After:      domain.AuditSnapshot(response),
+	})
+	return response, nil
+}
+
+// ListInvites retrieves invites with pagination, newest first
+func (s *inviteService) ListInvites(ctx context.Context, filter domain.InviteFilter, offset, limit int) ([]*domain.InviteResponse, int64, error) {
+	if err := s.validator.Validate(&filter); err != nil {
+		return nil, 0, err
+	}
+
+	invites, total, err := s.inviteRepo.List(ctx, filter, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*domain.InviteResponse, len(invites))
+	for i, invite := range invites {
+		responses[i] = invite.ToResponse()
+	}
+	return responses, total, nil
+}
+
+// RevokeInvite revokes a pending invite, keeping it listed as revoked
+func (s *inviteService) RevokeInvite(ctx context.Context, id uint) (*domain.InviteResponse, error) {
+	invite, err := s.inviteRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invite.Status() != domain.InviteStatusPending {
+		return nil, domain.ErrInviteNotFound
+	}
+
+	now := time.Now()
+	invite.RevokedAt = &now
+	if err := s.inviteRepo.Update(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	response := invite.ToResponse()
+	recordAudit(ctx, s.auditService, &domain.AuditLog{
+		Action:     domain.AuditActionInviteRevoke,
+		TargetType: "invite",
+		TargetID:   invite.ID,
+	})
+	return response, nil
+}
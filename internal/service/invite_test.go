@@ -0,0 +1,135 @@
+package service
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/mocks"
+	"github.com/luxixing/fx-gin-scaffold/internal/repo"
+	"github.com/luxixing/fx-gin-scaffold/internal/validation"
+	"github.com/luxixing/fx-gin-scaffold/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// inviteCodePattern extracts the code from invite emails
+var inviteCodePattern = regexp.MustCompile(`invite code: (\w+)`)
+
+func newTestInviteService(t *testing.T) (domain.InviteService, *gorm.DB, *mailer.MockMailer, *domain.User) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // every connection to :memory: is a new database
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Invite{}))
+
+	admin := &domain.User{Email: "admin@example.com", Password: "hashedpassword", Name: "Admin", Role: domain.RoleAdmin, Active: true}
+	require.NoError(t, db.Create(admin).Error)
+
+	renderer, err := mailer.NewDefaultRenderer()
+	require.NoError(t, err)
+	mailbox := mailer.NewMockMailer()
+
+	audit := mocks.NewAuditService(t)
+	audit.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	cfg := &config.Config{}
+	cfg.App.URL = "http://localhost:8080"
+	cfg.Registration.InviteExpiration = time.Hour
+	cfg.Roles.Catalog = []string{domain.RoleAdmin, domain.RoleModerator, domain.RoleUser}
+
+	service := NewInviteService(InviteServiceParams{
+		Config:       cfg,
+		InviteRepo:   repo.NewInviteGormRepository(db),
+		UserRepo:     repo.NewUserGormRepository(db),
+		AuditService: audit,
+		Mailer:       mailbox,
+		MailRenderer: renderer,
+		Validator:    validation.New(),
+	})
+	return service, db, mailbox, admin
+}
+
+func TestInviteService(t *testing.T) {
+	service, db, mailbox, admin := newTestInviteService(t)
+	ctx := asUser(admin)
+
+	_, err := service.CreateInvite(ctx, &domain.InviteCreateRequest{Email: "bob@example.com", Role: "owner"})
+	requireCode(t, err, domain.ErrCodeValidation)
+
+	_, err = service.CreateInvite(ctx, &domain.InviteCreateRequest{Email: "Admin@Example.com", Role: domain.RoleUser})
+	assert.Equal(t, domain.ErrUserExists, err)
+
+	bob, err := service.CreateInvite(ctx, &domain.InviteCreateRequest{Email: "Bob@Example.com", Role: domain.RoleAdmin})
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", bob.Email)
+	assert.Equal(t, domain.InviteStatusPending, bob.Status)
+	assert.Equal(t, admin.ID, bob.InvitedByID)
+
+	// Only the hash of the emailed code is stored
+	msg := mailbox.Last()
+	assert.Equal(t, []string{"bob@example.com"}, msg.To)
+	match := inviteCodePattern.FindStringSubmatch(msg.TextBody)
+	require.Len(t, match, 2)
+	assert.Contains(t, msg.TextBody, "http://localhost:8080/register?invite_code="+match[1])
+	var stored domain.Invite
+	require.NoError(t, db.First(&stored, bob.ID).Error)
+	assert.Equal(t, hashToken(match[1]), stored.TokenHash)
+
+	carol, err := service.CreateInvite(ctx, &domain.InviteCreateRequest{Email: "carol@example.com", Role: domain.RoleUser})
+	require.NoError(t, err)
+	dave, err := service.CreateInvite(ctx, &domain.InviteCreateRequest{Email: "dave@example.com", Role: domain.RoleUser})
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&domain.Invite{}).Where("id = ?", dave.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+	revoked, err := service.RevokeInvite(ctx, carol.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.InviteStatusRevoked, revoked.Status)
+	require.NotNil(t, revoked.RevokedAt)
+
+	// Only pending invites can be revoked
+	_, err = service.RevokeInvite(ctx, carol.ID)
+	assert.Equal(t, domain.ErrInviteNotFound, err)
+	_, err = service.RevokeInvite(ctx, dave.ID)
+	assert.Equal(t, domain.ErrInviteNotFound, err)
+	_, err = service.RevokeInvite(ctx, 99)
+	assert.Equal(t, domain.ErrInviteNotFound, err)
+
+	invites, total, err := service.ListInvites(ctx, domain.InviteFilter{}, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, invites, 3)
+	assert.Equal(t, dave.ID, invites[0].ID, "newest first")
+	assert.Equal(t, domain.InviteStatusExpired, invites[0].Status)
+
+	for status, want := range map[string]uint{
+		domain.InviteStatusPending: bob.ID,
+		domain.InviteStatusRevoked: carol.ID,
+		domain.InviteStatusExpired: dave.ID,
+	} {
+		invites, total, err := service.ListInvites(ctx, domain.InviteFilter{Status: status}, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total, status)
+		require.Len(t, invites, 1, status)
+		assert.Equal(t, want, invites[0].ID, status)
+	}
+
+	invites, _, err = service.ListInvites(ctx, domain.InviteFilter{Email: "CAROL@example.com"}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	assert.Equal(t, carol.ID, invites[0].ID)
+
+	_, _, err = service.ListInvites(ctx, domain.InviteFilter{Status: "unknown"}, 0, 10)
+	requireCode(t, err, domain.ErrCodeValidation)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logLevelService implements domain.LogLevelService over the process-wide
+// loggers. Changes last until the next restart or configuration reload.
+type logLevelService struct{}
+
+// NewLogLevelService creates a new log level service
+func NewLogLevelService() domain.LogLevelService {
+	return &logLevelService{}
+}
+
+// GetLogLevels returns the current log levels
+func (s *logLevelService) GetLogLevels(ctx context.Context) *domain.LogLevels {
+	return &domain.LogLevels{
+		Level:   logger.GetLevel(),
+		Modules: logger.ModuleLevels(),
+	}
+}
+
+// SetLogLevel changes the global level or the level of a module
+func (s *logLevelService) SetLogLevel(ctx context.Context, req domain.LogLevelUpdateRequest) (*domain.LogLevels, error) {
+	module := strings.TrimSpace(req.Module)
+	level := strings.ToLower(strings.TrimSpace(req.Level))
+
+	if module == "" && level == "" {
+		return nil, domain.ValidationError("level", "is required")
+	}
+	if level != "" {
+		if _, err := zapcore.ParseLevel(level); err != nil {
+			return nil, domain.ValidationError("level", "must be one of: debug, info, warn, error")
+		}
+	}
+
+	if module == "" {
+		if err := logger.SetLevel(level); err != nil {
+			return nil, err
+		}
+	} else if err := logger.SetModuleLevel(module, level); err != nil {
+		return nil, domain.ValidationError("module", "must be one of: "+strings.Join(logger.ModuleNames(), ", "))
+	}
+
+	logger.FromContext(ctx).Info("log level changed", zap.String("module", module), zap.String("level", level))
+
+	return s.GetLogLevels(ctx), nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// NotificationServiceParams holds dependencies for NotificationService
+type NotificationServiceParams struct {
+	fx.In
+	Config           *config.Config
+	NotificationRepo domain.NotificationRepository
+	Notifier         domain.Notifier
+}
+
+// notificationService implements domain.NotificationService
+type notificationService struct {
+	config           *config.Config
+	notificationRepo domain.NotificationRepository
+	notifier         domain.Notifier
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(p NotificationServiceParams) domain.NotificationService {
+	return &notificationService{
+		config:           p.Config,
+		notificationRepo: p.NotificationRepo,
+		notifier:         p.Notifier,
+		now:              time.Now,
+	}
+}
+
+// Notify stores a notification and, when NOTIFICATIONS_PUSH is enabled,
+// pushes it to the user's open connections. Push failures are logged; the
+// notification stays in the inbox.
+func (s *notificationService) Notify(ctx context.Context, notification *domain.Notification) error {
+	notification.ReadAt = nil
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	if s.config.Notifications.Push {
+		event := domain.NewEvent(domain.EventNotificationCreated, notification)
+		if err := s.notifier.NotifyUser(ctx, notification.UserID, event); err != nil {
+			logger.FromContext(ctx).Warn("failed to push notification", zap.Uint("user_id", notification.UserID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// ListNotifications retrieves the user's notifications with pagination, newest first
+func (s *notificationService) ListNotifications(ctx context.Context, userID uint, filter domain.NotificationFilter, offset, limit int) ([]*domain.Notification, int64, error) {
+	return s.notificationRepo.ListByUser(ctx, userID, filter, offset, limit)
+}
+
+// UnreadCount counts the user's unread notifications
+func (s *notificationService) UnreadCount(ctx context.Context, userID uint) (int64, error) {
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *notificationService) MarkRead(ctx context.Context, userID, id uint) error {
+	return s.notificationRepo.MarkRead(ctx, userID, id, s.now())
+}
+
+// MarkAllRead marks every notification of the user as read
+func (s *notificationService) MarkAllRead(ctx context.Context, userID uint) (int64, error) {
+	return s.notificationRepo.MarkAllRead(ctx, userID, s.now())
+}
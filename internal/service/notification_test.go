@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/mocks"
+	"github.com/luxixing/fx-gin-scaffold/internal/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestNotificationService(t *testing.T, notifier domain.Notifier, push bool) (*notificationService, *domain.User) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Notification{}))
+
+	user := &domain.User{Email: "alice@example.com", Password: "hashedpassword", Name: "Alice", Role: domain.RoleUser, Active: true}
+	require.NoError(t, db.Create(user).Error)
+
+	cfg := &config.Config{}
+	cfg.Notifications.Push = push
+
+	service := NewNotificationService(NotificationServiceParams{
+		Config:           cfg,
+		NotificationRepo: repo.NewNotificationGormRepository(db),
+		Notifier:         notifier,
+	}).(*notificationService)
+	return service, user
+}
+
+func TestNotificationService(t *testing.T) {
+	notifier := mocks.NewNotifier(t)
+	service, user := newTestNotificationService(t, notifier, true)
+	ctx := context.Background()
+
+	// New notifications are pushed to the user's connections
+	notifier.On("NotifyUser", mock.Anything, user.ID, mock.MatchedBy(func(e *domain.Event) bool {
+		return e.Type == domain.EventNotificationCreated
+	})).Return(nil).Twice()
+
+	first := &domain.Notification{UserID: user.ID, Type: domain.NotificationTypeWelcome, Title: "Welcome"}
+	require.NoError(t, service.Notify(ctx, first))
+	second := &domain.Notification{UserID: user.ID, Type: "test", Title: "Second", Data: map[string]interface{}{"project_id": float64(1)}}
+	require.NoError(t, service.Notify(ctx, second))
+
+	count, err := service.UnreadCount(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// Reading keeps the first read time
+	readAt := time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return readAt }
+	require.NoError(t, service.MarkRead(ctx, user.ID, first.ID))
+	service.now = func() time.Time { return readAt.Add(time.Hour) }
+	require.NoError(t, service.MarkRead(ctx, user.ID, first.ID))
+
+	notifications, total, err := service.ListNotifications(ctx, user.ID, domain.NotificationFilter{}, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, second.ID, notifications[0].ID)
+	assert.Equal(t, map[string]interface{}{"project_id": float64(1)}, notifications[0].Data)
+	require.NotNil(t, notifications[1].ReadAt)
+	assert.True(t, readAt.Equal(*notifications[1].ReadAt))
+
+	notifications, total, err = service.ListNotifications(ctx, user.ID, domain.NotificationFilter{UnreadOnly: true}, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, second.ID, notifications[0].ID)
+
+	updated, err := service.MarkAllRead(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+
+	count, err = service.UnreadCount(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestNotificationServiceMarkReadOtherUser(t *testing.T) {
+	service, user := newTestNotificationService(t, mocks.NewNotifier(t), false)
+	ctx := context.Background()
+
+	// Nothing is pushed when push is disabled
+	notification := &domain.Notification{UserID: user.ID, Type: "test", Title: "Private"}
+	require.NoError(t, service.Notify(ctx, notification))
+
+	err := service.MarkRead(ctx, user.ID+1, notification.ID)
+	assert.Equal(t, domain.ErrNotificationNotFound, err)
+}
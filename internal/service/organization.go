@@ -0,0 +1,529 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/mailer"
+	"github.com/luxixing/fx-gin-scaffold/pkg/utils"
+	"go.uber.org/fx"
+)
+
+// invitationTokenLength is the length of generated invitation tokens
+const invitationTokenLength = 64
+
+var (
+	// slugPattern matches valid organization slugs
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	// slugSeparators matches the characters replaced when deriving a slug
+	slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// OrganizationServiceParams holds dependencies for OrganizationService
+type OrganizationServiceParams struct {
+	fx.In
+	Config            *config.Config
+	OrganizationRepo  domain.OrganizationRepository
+	MembershipRepo    domain.MembershipRepository
+	InvitationRepo    domain.InvitationRepository
+	UserRepo          domain.UserRepository
+	PermissionService domain.PermissionService
+	Mailer            mailer.Mailer
+	MailRenderer      *mailer.Renderer
+	Validator         domain.Validator
+	TxManager         domain.TxManager
+}
+
+// organizationService implements domain.OrganizationService
+type organizationService struct {
+	config            *config.Config
+	orgRepo           domain.OrganizationRepository
+	membershipRepo    domain.MembershipRepository
+	invitationRepo    domain.InvitationRepository
+	userRepo          domain.UserRepository
+	permissionService domain.PermissionService
+	mailer            mailer.Mailer
+	mailRenderer      *mailer.Renderer
+	validator         domain.Validator
+	txManager         domain.TxManager
+}
+
+// NewOrganizationService creates a new organization service
+func NewOrganizationService(p OrganizationServiceParams) domain.OrganizationService {
+	return &organizationService{
+		config:            p.Config,
+		orgRepo:           p.OrganizationRepo,
+		membershipRepo:    p.MembershipRepo,
+		invitationRepo:    p.InvitationRepo,
+		userRepo:          p.UserRepo,
+		permissionService: p.PermissionService,
+		mailer:            p.Mailer,
+		mailRenderer:      p.MailRenderer,
+		validator:         p.Validator,
+		txManager:         p.TxManager,
+	}
+}
+
+// CreateOrganization creates an organization with the actor as its owner
+func (s *organizationService) CreateOrganization(ctx context.Context, req *domain.OrganizationCreateRequest) (*domain.OrganizationResponse, error) {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	name := req.Name
+	slug := req.Slug
+	if slug == "" {
+		slug = deriveSlug(name)
+	}
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+
+	org := &domain.Organization{Name: name, Slug: slug}
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.Create(ctx, org); err != nil {
+			return err
+		}
+		return s.membershipRepo.Create(ctx, &domain.Membership{
+			OrganizationID: org.ID,
+			UserID:         actor.UserID,
+			Role:           domain.OrgRoleOwner,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return org.ToResponse(domain.OrgRoleOwner), nil
+}
+
+// GetOrganization retrieves an organization the actor belongs to
+func (s *organizationService) GetOrganization(ctx context.Context, id uint) (*domain.OrganizationResponse, error) {
+	role, err := s.Authorize(ctx, id, domain.OrgRoleMember)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return org.ToResponse(role), nil
+}
+
+// UpdateOrganization applies a partial update to an organization
+func (s *organizationService) UpdateOrganization(ctx context.Context, id uint, req *domain.OrganizationUpdateRequest) (*domain.OrganizationResponse, error) {
+	role, err := s.Authorize(ctx, id, domain.OrgRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		org.Name = *req.Name
+		if org.Name == "" {
+			return nil, domain.ValidationError("name", "cannot be empty")
+		}
+	}
+	if req.Slug != nil {
+		if err := validateSlug(*req.Slug); err != nil {
+			return nil, err
+		}
+		org.Slug = *req.Slug
+	}
+
+	if err := s.orgRepo.Update(ctx, org); err != nil {
+		return nil, err
+	}
+	return org.ToResponse(role), nil
+}
+
+// DeleteOrganization deletes an organization with its members and invitations
+func (s *organizationService) DeleteOrganization(ctx context.Context, id uint) error {
+	if _, err := s.Authorize(ctx, id, domain.OrgRoleOwner); err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.invitationRepo.DeleteByOrganization(ctx, id); err != nil {
+			return err
+		}
+		if err := s.membershipRepo.DeleteByOrganization(ctx, id); err != nil {
+			return err
+		}
+		return s.orgRepo.Delete(ctx, id)
+	})
+}
+
+// ListOrganizations retrieves the organizations the actor belongs to
+func (s *organizationService) ListOrganizations(ctx context.Context, offset, limit int) ([]*domain.OrganizationResponse, int64, error) {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, 0, domain.ErrUnauthorized
+	}
+
+	memberships, total, err := s.membershipRepo.ListByUser(ctx, actor.UserID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*domain.OrganizationResponse, 0, len(memberships))
+	for _, membership := range memberships {
+		org := membership.Organization
+		if org == nil {
+			// MongoDB memberships only store the organization's ID
+			if org, err = s.orgRepo.GetByID(ctx, membership.OrganizationID); err != nil {
+				return nil, 0, err
+			}
+		}
+		responses = append(responses, org.ToResponse(membership.Role))
+	}
+	return responses, total, nil
+}
+
+// ListMembers retrieves the members of an organization
+func (s *organizationService) ListMembers(ctx context.Context, orgID uint, offset, limit int) ([]*domain.MemberResponse, int64, error) {
+	if _, err := s.Authorize(ctx, orgID, domain.OrgRoleMember); err != nil {
+		return nil, 0, err
+	}
+
+	memberships, total, err := s.membershipRepo.ListByOrganization(ctx, orgID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.loadUsers(ctx, memberships...); err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*domain.MemberResponse, len(memberships))
+	for i, membership := range memberships {
+		responses[i] = membership.ToMemberResponse()
+	}
+	return responses, total, nil
+}
+
+// UpdateMemberRole changes a member's role. Only owners may grant or revoke
+// the owner role, and the last owner cannot be demoted.
+func (s *organizationService) UpdateMemberRole(ctx context.Context, orgID, userID uint, req *domain.MembershipUpdateRequest) (*domain.MemberResponse, error) {
+	role, err := s.Authorize(ctx, orgID, domain.OrgRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	membership, err := s.membershipRepo.Get(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if (membership.Role == domain.OrgRoleOwner || req.Role == domain.OrgRoleOwner) && role != domain.OrgRoleOwner {
+		return nil, domain.ErrForbidden
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if membership.Role == domain.OrgRoleOwner && req.Role != domain.OrgRoleOwner {
+			if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+				return err
+			}
+		}
+		membership.Role = req.Role
+		return s.membershipRepo.Update(ctx, membership)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadUsers(ctx, membership); err != nil {
+		return nil, err
+	}
+	return membership.ToMemberResponse(), nil
+}
+
+// RemoveMember removes a member from an organization. Members may leave, admins
+// remove members and admins, and only owners remove owners; the last owner
+// cannot be removed.
+func (s *organizationService) RemoveMember(ctx context.Context, orgID, userID uint) error {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	minRole := domain.OrgRoleAdmin
+	if userID == actor.UserID {
+		minRole = domain.OrgRoleMember
+	}
+	role, err := s.Authorize(ctx, orgID, minRole)
+	if err != nil {
+		return err
+	}
+
+	membership, err := s.membershipRepo.Get(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if membership.Role == domain.OrgRoleOwner && role != domain.OrgRoleOwner {
+		return domain.ErrForbidden
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if membership.Role == domain.OrgRoleOwner {
+			if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+				return err
+			}
+		}
+		return s.membershipRepo.Delete(ctx, orgID, userID)
+	})
+}
+
+// InviteMember stores an invitation and emails its token to the invitee
+func (s *organizationService) InviteMember(ctx context.Context, orgID uint, req *domain.InvitationCreateRequest) (*domain.InvitationResponse, error) {
+	role, err := s.Authorize(ctx, orgID, domain.OrgRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+	if req.Role == domain.OrgRoleOwner && role != domain.OrgRoleOwner {
+		return nil, domain.ErrForbidden
+	}
+
+	email := req.Email
+	if user, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		if _, err := s.membershipRepo.Get(ctx, orgID, user.ID); err == nil {
+			return nil, domain.ErrAlreadyMember
+		} else if err != domain.ErrMembershipNotFound {
+			return nil, err
+		}
+	} else if err != domain.ErrUserNotFound {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRandomString(invitationTokenLength)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to generate invitation token")
+	}
+
+	actor, _ := domain.ActorFromContext(ctx)
+	invitation := &domain.Invitation{
+		OrganizationID: orgID,
+		Email:          email,
+		Role:           req.Role,
+		TokenHash:      hashToken(token),
+		InvitedByID:    actor.UserID,
+		ExpiresAt:      time.Now().Add(s.config.Orgs.InvitationExpiration),
+	}
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	link := strings.TrimRight(s.config.App.URL, "/") + "/invitations/accept?token=" + url.QueryEscape(token)
+	msg, err := s.mailRenderer.Message("organization_invitation", map[string]interface{}{
+		"Organization": org.Name,
+		"Role":         req.Role,
+		"Token":        token,
+		"Link":         link,
+		"ExpiresIn":    s.config.Orgs.InvitationExpiration.String(),
+	}, "You have been invited to join "+org.Name, email)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to render invitation email")
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to send invitation email")
+	}
+
+	return invitation.ToResponse(), nil
+}
+
+// ListInvitations retrieves the pending invitations to an organization
+func (s *organizationService) ListInvitations(ctx context.Context, orgID uint) ([]*domain.InvitationResponse, error) {
+	if _, err := s.Authorize(ctx, orgID, domain.OrgRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.ListPending(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*domain.InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		responses[i] = invitation.ToResponse()
+	}
+	return responses, nil
+}
+
+// RevokeInvitation deletes a pending invitation
+func (s *organizationService) RevokeInvitation(ctx context.Context, orgID, invitationID uint) error {
+	if _, err := s.Authorize(ctx, orgID, domain.OrgRoleAdmin); err != nil {
+		return err
+	}
+
+	invitation, err := s.invitationRepo.GetByID(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.OrganizationID != orgID || !invitation.IsPending() {
+		return domain.ErrInvitationNotFound
+	}
+	return s.invitationRepo.Delete(ctx, invitationID)
+}
+
+// AcceptInvitation adds the actor to the organization of an invitation. The
+// invitation must be pending and addressed to the actor's email.
+func (s *organizationService) AcceptInvitation(ctx context.Context, req *domain.InvitationAcceptRequest) (*domain.OrganizationResponse, error) {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.invitationRepo.GetByTokenHash(ctx, hashToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	if !invitation.IsPending() {
+		return nil, domain.ErrInvitationNotFound
+	}
+
+	user, err := s.userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, domain.NewError(domain.ErrCodeForbidden, "Invitation was sent to a different email address")
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, invitation.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.membershipRepo.Create(ctx, &domain.Membership{
+			OrganizationID: invitation.OrganizationID,
+			UserID:         user.ID,
+			Role:           invitation.Role,
+		}); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		invitation.AcceptedAt = &now
+		return s.invitationRepo.Update(ctx, invitation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return org.ToResponse(invitation.Role), nil
+}
+
+// Authorize returns the actor's role in an organization. Non-members get
+// ErrOrganizationNotFound so that organizations are not disclosed, and
+// members whose role is below minRole get ErrForbidden.
+func (s *organizationService) Authorize(ctx context.Context, orgID uint, minRole string) (string, error) {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return "", domain.ErrUnauthorized
+	}
+
+	role := ""
+	membership, err := s.membershipRepo.Get(ctx, orgID, actor.UserID)
+	switch err {
+	case nil:
+		role = membership.Role
+	case domain.ErrMembershipNotFound:
+	default:
+		return "", err
+	}
+
+	if role != domain.OrgRoleOwner {
+		canManage, err := s.permissionService.HasPermission(ctx, actor.Role, domain.PermissionOrganizationsManage)
+		if err != nil {
+			return "", err
+		}
+		if canManage {
+			if _, err := s.orgRepo.GetByID(ctx, orgID); err != nil {
+				return "", err
+			}
+			role = domain.OrgRoleOwner
+		}
+	}
+
+	if role == "" {
+		return "", domain.ErrOrganizationNotFound
+	}
+	if !domain.OrgRoleAtLeast(role, minRole) {
+		return "", domain.ErrForbidden
+	}
+	return role, nil
+}
+
+// ensureAnotherOwner fails with ErrLastOwner unless the organization has
+// more than one owner
+func (s *organizationService) ensureAnotherOwner(ctx context.Context, orgID uint) error {
+	owners, err := s.membershipRepo.CountByRole(ctx, orgID, domain.OrgRoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return domain.ErrLastOwner
+	}
+	return nil
+}
+
+// loadUsers fills in the users the repository did not load, such as on
+// MongoDB where memberships only store the user's ID
+func (s *organizationService) loadUsers(ctx context.Context, memberships ...*domain.Membership) error {
+	for _, membership := range memberships {
+		if membership.User != nil {
+			continue
+		}
+		user, err := s.userRepo.GetByID(ctx, membership.UserID)
+		if err != nil && err != domain.ErrUserNotFound {
+			return err
+		}
+		membership.User = user
+	}
+	return nil
+}
+
+// deriveSlug converts a name into a slug, e.g. "Acme Inc." becomes "acme-inc"
+func deriveSlug(name string) string {
+	slug := strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(slug) > 50 {
+		slug = strings.TrimRight(slug[:50], "-")
+	}
+	return slug
+}
+
+// validateSlug checks that a slug only has lowercase letters, digits and single hyphens
+func validateSlug(slug string) error {
+	if len(slug) < 2 || !slugPattern.MatchString(slug) {
+		return domain.ValidationError("slug", "must be at least 2 lowercase letters, digits or hyphens")
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/repo"
+	"github.com/luxixing/fx-gin-scaffold/internal/validation"
+	"github.com/luxixing/fx-gin-scaffold/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// invitationTokenPattern extracts the token from invitation emails
+var invitationTokenPattern = regexp.MustCompile(`invitation token: (\w+)`)
+
+func newTestOrganizationService(t *testing.T) (domain.OrganizationService, *mailer.MockMailer, []*domain.User) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // every connection to :memory: is a new database
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Organization{}, &domain.Membership{}, &domain.Invitation{}))
+
+	users := []*domain.User{
+		{Email: "alice@example.com", Password: "hashedpassword", Name: "Alice", Role: domain.RoleUser, Active: true},
+		{Email: "bob@example.com", Password: "hashedpassword", Name: "Bob", Role: domain.RoleUser, Active: true},
+		{Email: "carol@example.com", Password: "hashedpassword", Name: "Carol", Role: domain.RoleUser, Active: true},
+		{Email: "admin@example.com", Password: "hashedpassword", Name: "Admin", Role: domain.RoleAdmin, Active: true},
+	}
+	require.NoError(t, db.Create(&users).Error)
+
+	renderer, err := mailer.NewDefaultRenderer()
+	require.NoError(t, err)
+	mock := mailer.NewMockMailer()
+
+	cfg := &config.Config{}
+	cfg.App.URL = "http://localhost:8080"
+	cfg.Orgs.InvitationExpiration = time.Hour
+
+	service := NewOrganizationService(OrganizationServiceParams{
+		Config:            cfg,
+		OrganizationRepo:  repo.NewOrganizationGormRepository(db),
+		MembershipRepo:    repo.NewMembershipGormRepository(db),
+		InvitationRepo:    repo.NewInvitationGormRepository(db),
+		UserRepo:          repo.NewUserGormRepository(db),
+		PermissionService: rolePermissions{grants: map[string][]string{domain.RoleAdmin: {domain.PermissionAll}}},
+		Mailer:            mock,
+		MailRenderer:      renderer,
+		Validator:         validation.New(),
+		TxManager:         repo.NewGormTxManager(db),
+	})
+	return service, mock, users
+}
+
+// invite invites email to the organization and returns the emailed token
+func invite(t *testing.T, service domain.OrganizationService, mock *mailer.MockMailer, as *domain.User, orgID uint, email, role string) string {
+	t.Helper()
+
+	_, err := service.InviteMember(asUser(as), orgID, &domain.InvitationCreateRequest{Email: email, Role: role})
+	require.NoError(t, err)
+
+	match := invitationTokenPattern.FindStringSubmatch(mock.Last().TextBody)
+	require.Len(t, match, 2)
+	return match[1]
+}
+
+func TestOrganizationMembership(t *testing.T) {
+	service, mock, users := newTestOrganizationService(t)
+	alice, bob, carol, admin := users[0], users[1], users[2], users[3]
+
+	org, err := service.CreateOrganization(asUser(alice), &domain.OrganizationCreateRequest{Name: "Acme Inc."})
+	require.NoError(t, err)
+	assert.Equal(t, "acme-inc", org.Slug)
+	assert.Equal(t, domain.OrgRoleOwner, org.Role)
+
+	_, err = service.CreateOrganization(asUser(bob), &domain.OrganizationCreateRequest{Name: "Acme", Slug: "acme-inc"})
+	assert.Equal(t, domain.ErrOrganizationExists, err)
+
+	// Non-members cannot see the organization
+	_, err = service.GetOrganization(asUser(bob), org.ID)
+	assert.Equal(t, domain.ErrOrganizationNotFound, err)
+
+	// Invitations are accepted by the invited email only
+	token := invite(t, service, mock, alice, org.ID, "Bob@Example.com", domain.OrgRoleMember)
+	assert.Equal(t, []string{"bob@example.com"}, mock.Last().To)
+	_, err = service.AcceptInvitation(asUser(carol), &domain.InvitationAcceptRequest{Token: token})
+	assert.Error(t, err)
+
+	joined, err := service.AcceptInvitation(asUser(bob), &domain.InvitationAcceptRequest{Token: token})
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrgRoleMember, joined.Role)
+
+	_, err = service.AcceptInvitation(asUser(bob), &domain.InvitationAcceptRequest{Token: token})
+	assert.Equal(t, domain.ErrInvitationNotFound, err)
+	_, err = service.InviteMember(asUser(alice), org.ID, &domain.InvitationCreateRequest{Email: "bob@example.com", Role: domain.OrgRoleMember})
+	assert.Equal(t, domain.ErrAlreadyMember, err)
+
+	// Members cannot invite or manage members
+	_, err = service.InviteMember(asUser(bob), org.ID, &domain.InvitationCreateRequest{Email: "carol@example.com", Role: domain.OrgRoleMember})
+	assert.Equal(t, domain.ErrForbidden, err)
+
+	members, total, err := service.ListMembers(asUser(bob), org.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "Alice", members[0].Name)
+
+	orgs, total, err := service.ListOrganizations(asUser(bob), 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "Acme Inc.", orgs[0].Name)
+
+	// Admins manage members but not owners
+	member, err := service.UpdateMemberRole(asUser(alice), org.ID, bob.ID, &domain.MembershipUpdateRequest{Role: domain.OrgRoleAdmin})
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrgRoleAdmin, member.Role)
+	_, err = service.UpdateMemberRole(asUser(bob), org.ID, bob.ID, &domain.MembershipUpdateRequest{Role: domain.OrgRoleOwner})
+	assert.Equal(t, domain.ErrForbidden, err)
+	assert.Equal(t, domain.ErrForbidden, service.RemoveMember(asUser(bob), org.ID, alice.ID))
+
+	token = invite(t, service, mock, bob, org.ID, "carol@example.com", domain.OrgRoleMember)
+	invitations, err := service.ListInvitations(asUser(bob), org.ID)
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+	require.NoError(t, service.RevokeInvitation(asUser(bob), org.ID, invitations[0].ID))
+	_, err = service.AcceptInvitation(asUser(carol), &domain.InvitationAcceptRequest{Token: token})
+	assert.Equal(t, domain.ErrInvitationNotFound, err)
+
+	// The last owner cannot leave or be demoted
+	assert.Equal(t, domain.ErrLastOwner, service.RemoveMember(asUser(alice), org.ID, alice.ID))
+	_, err = service.UpdateMemberRole(asUser(alice), org.ID, alice.ID, &domain.MembershipUpdateRequest{Role: domain.OrgRoleMember})
+	assert.Equal(t, domain.ErrLastOwner, err)
+
+	// organizations:manage acts as owner without membership
+	role, err := service.Authorize(asUser(admin), org.ID, domain.OrgRoleOwner)
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrgRoleOwner, role)
+
+	// Members may leave
+	require.NoError(t, service.RemoveMember(asUser(bob), org.ID, bob.ID))
+	_, err = service.GetOrganization(asUser(bob), org.ID)
+	assert.Equal(t, domain.ErrOrganizationNotFound, err)
+
+	// Unknown roles are never granted
+	_, err = service.Authorize(asUser(alice), org.ID, "superuser")
+	assert.Equal(t, domain.ErrForbidden, err)
+
+	require.NoError(t, service.DeleteOrganization(asUser(alice), org.ID))
+	_, total, err = service.ListOrganizations(asUser(alice), 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// PermissionServiceParams holds dependencies for PermissionService
+type PermissionServiceParams struct {
+	fx.In
+	RoleRepo       domain.RoleRepository
+	PermissionRepo domain.PermissionRepository
+}
+
+// permissionService implements domain.PermissionService
+type permissionService struct {
+	roleRepo       domain.RoleRepository
+	permissionRepo domain.PermissionRepository
+}
+
+// NewPermissionService creates a new permission service
+func NewPermissionService(p PermissionServiceParams) domain.PermissionService {
+	return &permissionService{
+		roleRepo:       p.RoleRepo,
+		permissionRepo: p.PermissionRepo,
+	}
+}
+
+// HasPermission reports whether the role grants the permission
+func (s *permissionService) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	r, err := s.roleRepo.GetByName(ctx, role)
+	if err != nil {
+		if err == domain.ErrRoleNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return r.HasPermission(permission), nil
+}
+
+// RoleExists reports whether a role is defined
+func (s *permissionService) RoleExists(ctx context.Context, role string) (bool, error) {
+	if _, err := s.roleRepo.GetByName(ctx, role); err != nil {
+		if err == domain.ErrRoleNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ListRoles retrieves all roles
+func (s *permissionService) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	return s.roleRepo.List(ctx)
+}
+
+// CreateRole creates a new role
+func (s *permissionService) CreateRole(ctx context.Context, req *domain.RoleCreateRequest) (*domain.Role, error) {
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if name == "" {
+		return nil, domain.ValidationError("name", "is required")
+	}
+
+	if err := s.validatePermissions(ctx, req.Permissions); err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Permissions: req.Permissions,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// UpdateRole updates a role's description and permissions
+func (s *permissionService) UpdateRole(ctx context.Context, name string, req *domain.RoleUpdateRequest) (*domain.Role, error) {
+	role, err := s.roleRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		role.Description = strings.TrimSpace(*req.Description)
+	}
+
+	if req.Permissions != nil {
+		if role.Name == domain.RoleAdmin {
+			return nil, domain.NewError(domain.ErrCodeInvalid, "Cannot change permissions of the admin role")
+		}
+		if err := s.validatePermissions(ctx, req.Permissions); err != nil {
+			return nil, err
+		}
+		role.Permissions = req.Permissions
+	}
+
+	role.UpdatedAt = time.Now()
+
+	if err := s.roleRepo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// DeleteRole deletes a custom role
+func (s *permissionService) DeleteRole(ctx context.Context, name string) error {
+	role, err := s.roleRepo.GetByName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if role.IsBuiltIn() {
+		return domain.NewError(domain.ErrCodeInvalid, "Cannot delete a built-in role")
+	}
+
+	return s.roleRepo.Delete(ctx, name)
+}
+
+// ListPermissions retrieves all known permissions
+func (s *permissionService) ListPermissions(ctx context.Context) ([]*domain.Permission, error) {
+	return s.permissionRepo.List(ctx)
+}
+
+// validatePermissions ensures every permission is known or a wildcard
+func (s *permissionService) validatePermissions(ctx context.Context, permissions []string) error {
+	for _, permission := range permissions {
+		if permission == domain.PermissionAll || strings.HasSuffix(permission, ":*") {
+			continue
+		}
+		if _, err := s.permissionRepo.GetByName(ctx, permission); err != nil {
+			if err == domain.ErrPermissionNotFound {
+				return domain.ValidationError("permissions", "unknown permission '"+permission+"'")
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// ProjectServiceParams holds dependencies for ProjectService
+type ProjectServiceParams struct {
+	fx.In
+	ProjectRepo       domain.ProjectRepository
+	UserRepo          domain.UserRepository
+	PermissionService domain.PermissionService
+	Validator         domain.Validator
+}
+
+// projectService implements domain.ProjectService
+type projectService struct {
+	projectRepo       domain.ProjectRepository
+	userRepo          domain.UserRepository
+	permissionService domain.PermissionService
+	validator         domain.Validator
+}
+
+// NewProjectService creates a new project service
+func NewProjectService(p ProjectServiceParams) domain.ProjectService {
+	return &projectService{
+		projectRepo:       p.ProjectRepo,
+		userRepo:          p.UserRepo,
+		permissionService: p.PermissionService,
+		validator:         p.Validator,
+	}
+}
+
+// CreateProject creates a project owned by the actor
+func (s *projectService) CreateProject(ctx context.Context, req *domain.ProjectCreateRequest) (*domain.ProjectResponse, error) {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	name := req.Name
+	if name == "" {
+		return nil, domain.ValidationError("name", "cannot be empty")
+	}
+
+	project := &domain.Project{
+		OwnerID:     actor.UserID,
+		Name:        name,
+		Description: req.Description,
+		Status:      domain.ProjectStatusActive,
+	}
+	if err := s.projectRepo.Create(ctx, project); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadOwners(ctx, project); err != nil {
+		return nil, err
+	}
+	return project.ToResponse(), nil
+}
+
+// GetProject retrieves a project the actor may access
+func (s *projectService) GetProject(ctx context.Context, id uint) (*domain.ProjectResponse, error) {
+	project, err := s.getAccessible(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return project.ToResponse(), nil
+}
+
+// UpdateProject applies a partial update to a project the actor may access
+func (s *projectService) UpdateProject(ctx context.Context, id uint, req *domain.ProjectUpdateRequest) (*domain.ProjectResponse, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	project, err := s.getAccessible(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Apply(project)
+	if project.Name == "" {
+		return nil, domain.ValidationError("name", "cannot be empty")
+	}
+
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		return nil, err
+	}
+	return project.ToResponse(), nil
+}
+
+// DeleteProject deletes a project the actor may access
+func (s *projectService) DeleteProject(ctx context.Context, id uint) error {
+	if _, err := s.getAccessible(ctx, id); err != nil {
+		return err
+	}
+	return s.projectRepo.Delete(ctx, id)
+}
+
+// ListProjects retrieves the projects the actor may access matching the query with pagination
+func (s *projectService) ListProjects(ctx context.Context, query *domain.Query, offset, limit int) ([]*domain.ProjectResponse, int64, error) {
+	actor, canManage, err := s.authorize(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if query == nil {
+		query, _ = (&domain.ProjectListFilter{}).Query()
+	}
+	if !canManage {
+		// Narrow any owner filter down to the actor's own projects
+		query.Where("owner_id", domain.OpEq, actor.UserID)
+	}
+
+	projects, total, err := s.projectRepo.List(ctx, query, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.loadOwners(ctx, projects...); err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*domain.ProjectResponse, len(projects))
+	for i, project := range projects {
+		responses[i] = project.ToResponse()
+	}
+	return responses, total, nil
+}
+
+// authorize returns the actor in ctx and whether it may manage every user's projects
+func (s *projectService) authorize(ctx context.Context) (domain.Actor, bool, error) {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return actor, false, domain.ErrUnauthorized
+	}
+	canManage, err := s.permissionService.HasPermission(ctx, actor.Role, domain.PermissionProjectsManage)
+	if err != nil {
+		return actor, false, err
+	}
+	return actor, canManage, nil
+}
+
+// getAccessible loads a project with its owner, hiding projects the actor may not
+// access behind ErrProjectNotFound so their existence is not revealed
+func (s *projectService) getAccessible(ctx context.Context, id uint) (*domain.Project, error) {
+	actor, canManage, err := s.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage && project.OwnerID != actor.UserID {
+		return nil, domain.ErrProjectNotFound
+	}
+
+	if err := s.loadOwners(ctx, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// loadOwners fills in the owners the repository did not load, such as on
+// MongoDB where projects only store the owner's ID
+func (s *projectService) loadOwners(ctx context.Context, projects ...*domain.Project) error {
+	owners := make(map[uint]*domain.User)
+	for _, project := range projects {
+		if project.Owner != nil {
+			continue
+		}
+
+		owner, seen := owners[project.OwnerID]
+		if !seen {
+			user, err := s.userRepo.GetByID(ctx, project.OwnerID)
+			if err != nil && err != domain.ErrUserNotFound {
+				return err
+			}
+			owner = user
+			owners[project.OwnerID] = owner
+		}
+		project.Owner = owner
+	}
+	return nil
+}
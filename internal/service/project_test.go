@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/repo"
+	"github.com/luxixing/fx-gin-scaffold/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// rolePermissions is a PermissionService granting fixed permissions per role
+type rolePermissions struct {
+	domain.PermissionService
+	grants map[string][]string
+}
+
+func (p rolePermissions) HasPermission(_ context.Context, role, permission string) (bool, error) {
+	r := domain.Role{Permissions: p.grants[role]}
+	return r.HasPermission(permission), nil
+}
+
+func newTestProjectService(t *testing.T) (domain.ProjectService, []*domain.User) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Project{}))
+
+	users := []*domain.User{
+		{Email: "alice@example.com", Password: "hashedpassword", Name: "Alice", Role: domain.RoleUser, Active: true},
+		{Email: "bob@example.com", Password: "hashedpassword", Name: "Bob", Role: domain.RoleUser, Active: true},
+		{Email: "admin@example.com", Password: "hashedpassword", Name: "Admin", Role: domain.RoleAdmin, Active: true},
+	}
+	require.NoError(t, db.Create(&users).Error)
+
+	service := NewProjectService(ProjectServiceParams{
+		ProjectRepo:       repo.NewProjectGormRepository(db),
+		UserRepo:          repo.NewUserGormRepository(db),
+		PermissionService: rolePermissions{grants: map[string][]string{domain.RoleAdmin: {domain.PermissionAll}}},
+		Validator:         validation.New(),
+	})
+	return service, users
+}
+
+func asUser(user *domain.User) context.Context {
+	return domain.WithActor(context.Background(), domain.Actor{UserID: user.ID, Role: user.Role})
+}
+
+func TestProjectOwnership(t *testing.T) {
+	service, users := newTestProjectService(t)
+	alice, bob, admin := asUser(users[0]), asUser(users[1]), asUser(users[2])
+
+	project, err := service.CreateProject(alice, &domain.ProjectCreateRequest{Name: "Website"})
+	require.NoError(t, err)
+	assert.Equal(t, users[0].ID, project.OwnerID)
+	assert.Equal(t, domain.ProjectStatusActive, project.Status)
+	require.NotNil(t, project.Owner)
+	assert.Equal(t, "Alice", project.Owner.Name)
+
+	_, err = service.CreateProject(bob, &domain.ProjectCreateRequest{Name: "Mobile"})
+	require.NoError(t, err)
+
+	// Other users cannot see or change the project
+	_, err = service.GetProject(bob, project.ID)
+	assert.Equal(t, domain.ErrProjectNotFound, err)
+	name := "Hijacked"
+	_, err = service.UpdateProject(bob, project.ID, &domain.ProjectUpdateRequest{Name: &name})
+	assert.Equal(t, domain.ErrProjectNotFound, err)
+	assert.Equal(t, domain.ErrProjectNotFound, service.DeleteProject(bob, project.ID))
+
+	// Listing is limited to the caller's projects, even when filtering by another owner
+	query, err := (&domain.ProjectListFilter{OwnerID: users[0].ID}).Query()
+	require.NoError(t, err)
+	projects, total, err := service.ListProjects(bob, query, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.Empty(t, projects)
+
+	query, err = (&domain.ProjectListFilter{}).Query()
+	require.NoError(t, err)
+	_, total, err = service.ListProjects(alice, query, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	// projects:manage grants access to every project
+	query, err = (&domain.ProjectListFilter{}).Query()
+	require.NoError(t, err)
+	_, total, err = service.ListProjects(admin, query, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	status := domain.ProjectStatusArchived
+	updated, err := service.UpdateProject(admin, project.ID, &domain.ProjectUpdateRequest{Status: &status})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ProjectStatusArchived, updated.Status)
+
+	invalid := "deleted"
+	_, err = service.UpdateProject(alice, project.ID, &domain.ProjectUpdateRequest{Status: &invalid})
+	assert.Error(t, err)
+
+	require.NoError(t, service.DeleteProject(alice, project.ID))
+	_, err = service.GetProject(alice, project.ID)
+	assert.Equal(t, domain.ErrProjectNotFound, err)
+
+	_, _, err = service.ListProjects(context.Background(), nil, 0, 10)
+	assert.Equal(t, domain.ErrUnauthorized, err)
+	_, err = service.GetProject(context.Background(), project.ID)
+	assert.Equal(t, domain.ErrUnauthorized, err)
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/search"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// reindexBatchSize is the number of users loaded per page while reindexing
+const reindexBatchSize = 200
+
+// SearchServiceParams holds dependencies for SearchService
+type SearchServiceParams struct {
+	fx.In
+	// Index is nil when search is disabled
+	Index    search.Index
+	UserRepo domain.UserRepository
+}
+
+// searchService implements domain.SearchService
+type searchService struct {
+	index    search.Index
+	userRepo domain.UserRepository
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(p SearchServiceParams) domain.SearchService {
+	return &searchService{
+		index:    p.Index,
+		userRepo: p.UserRepo,
+	}
+}
+
+// Enabled reports whether a search index is configured
+func (s *searchService) Enabled() bool {
+	return s.index != nil
+}
+
+// IndexUser adds or replaces the user in the index
+func (s *searchService) IndexUser(ctx context.Context, user *domain.UserResponse) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	if err := s.index.Index(ctx, domain.SearchCollectionUsers, userDocument(user)); err != nil {
+		return domain.WrapError(err, domain.ErrCodeInternal, "Failed to index user")
+	}
+	return nil
+}
+
+// RemoveUser removes the user from the index
+func (s *searchService) RemoveUser(ctx context.Context, id uint) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	if err := s.index.Delete(ctx, domain.SearchCollectionUsers, strconv.FormatUint(uint64(id), 10)); err != nil {
+		return domain.WrapError(err, domain.ErrCodeInternal, "Failed to remove user from the search index")
+	}
+	return nil
+}
+
+// SearchUserIDs returns the IDs of the users matching query
+func (s *searchService) SearchUserIDs(ctx context.Context, query string, offset, limit int) ([]uint, int64, error) {
+	if !s.Enabled() {
+		return []uint{}, 0, nil
+	}
+
+	hits, total, err := s.index.Search(ctx, domain.SearchCollectionUsers, query, offset, limit)
+	if err != nil {
+		return nil, 0, domain.WrapError(err, domain.ErrCodeInternal, "Failed to search users")
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		id, err := strconv.ParseUint(hit, 10, 0)
+		if err != nil {
+			logger.FromContext(ctx).Warn("ignoring search hit with invalid user ID", zap.String("id", hit))
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, total, nil
+}
+
+// ReindexUsers indexes every user, walking the repository page by page
+func (s *searchService) ReindexUsers(ctx context.Context) (int, error) {
+	if !s.Enabled() {
+		return 0, nil
+	}
+
+	indexed := 0
+	page := &domain.CursorPage{Limit: reindexBatchSize}
+	for {
+		users, hasMore, err := s.userRepo.ListByCursor(ctx, nil, domain.ListOptions{IncludeInactive: true}, page)
+		if err != nil {
+			return indexed, err
+		}
+
+		for _, user := range users {
+			if err := s.IndexUser(ctx, user.ToResponse()); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+
+		if !hasMore || len(users) == 0 {
+			return indexed, nil
+		}
+		page = &domain.CursorPage{After: users[len(users)-1].ToResponse().Cursor(), Limit: reindexBatchSize}
+	}
+}
+
+// userDocument returns the searchable fields of the user
+func userDocument(user *domain.UserResponse) search.Document {
+	return search.Document{
+		ID: strconv.FormatUint(uint64(user.ID), 10),
+		Fields: map[string]string{
+			"name":  user.Name,
+			"email": user.Email,
+		},
+	}
+}
@@ -21,5 +21,83 @@ func GetModule() fx.Option {
 				fx.As(new(domain.UserService)),
 			),
 		),
+		fx.Provide(
+			fx.Annotate(
+				NewPermissionService,
+				fx.As(new(domain.PermissionService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewAuditService,
+				fx.As(new(domain.AuditService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewHealthService,
+				fx.As(new(domain.HealthService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewProjectService,
+				fx.As(new(domain.ProjectService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewWebhookService,
+				fx.As(new(domain.WebhookService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewInviteService,
+				fx.As(new(domain.InviteService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewOrganizationService,
+				fx.As(new(domain.OrganizationService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewSearchService,
+				fx.As(new(domain.SearchService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewUserSettingsService,
+				fx.As(new(domain.UserSettingsService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewNotificationService,
+				fx.As(new(domain.NotificationService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewLogLevelService,
+				fx.As(new(domain.LogLevelService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewStatsService,
+				fx.As(new(domain.StatsService)),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				NewDataExportService,
+				fx.As(new(domain.DataExportService)),
+			),
+		),
 	)
-}
\ No newline at end of file
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"go.uber.org/fx"
+)
+
+// StatsServiceParams holds dependencies for StatsService
+type StatsServiceParams struct {
+	fx.In
+	UserRepo     domain.UserRepository
+	AuditLogRepo domain.AuditLogRepository
+}
+
+// statsService implements domain.StatsService
+type statsService struct {
+	userRepo     domain.UserRepository
+	auditLogRepo domain.AuditLogRepository
+	now          func() time.Time
+}
+
+// NewStatsService creates a new stats service
+func NewStatsService(p StatsServiceParams) domain.StatsService {
+	return &statsService{
+		userRepo:     p.UserRepo,
+		auditLogRepo: p.AuditLogRepo,
+		now:          time.Now,
+	}
+}
+
+// Overview computes the statistics of the admin dashboard. Logins are
+// counted from the audit log.
+func (s *statsService) Overview(ctx context.Context) (*domain.AdminStats, error) {
+	now := s.now().UTC()
+	since := now.Truncate(24*time.Hour).AddDate(0, 0, -(domain.StatsDays - 1))
+
+	total, err := s.userRepo.Count(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.userRepo.Count(ctx, domain.NewQuery("active").Where("active", domain.OpEq, true))
+	if err != nil {
+		return nil, err
+	}
+
+	signups, err := s.userRepo.CountCreatedByDay(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	logins, err := s.auditLogRepo.CountByDay(ctx, domain.AuditActionLogin, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AdminStats{
+		TotalUsers:    total,
+		ActiveUsers:   active,
+		SignupsPerDay: everyDay(since, domain.StatsDays, signups),
+		LoginsPerDay:  everyDay(since, domain.StatsDays, logins),
+		GeneratedAt:   now,
+	}, nil
+}
+
+// everyDay returns the counts of days consecutive days from since, adding
+// the days without any
+func everyDay(since time.Time, days int, counts []domain.DailyCount) []domain.DailyCount {
+	byDate := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		byDate[count.Date] = count.Count
+	}
+
+	result := make([]domain.DailyCount, days)
+	for i := range result {
+		date := since.AddDate(0, 0, i).Format(time.DateOnly)
+		result[i] = domain.DailyCount{Date: date, Count: byDate[date]}
+	}
+	return result
+}
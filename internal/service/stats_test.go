@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsServiceOverview(t *testing.T) {
+	users := mocks.NewUserRepository(t)
+	auditLogs := mocks.NewAuditLogRepository(t)
+
+	now := time.Date(2024, 5, 30, 15, 4, 5, 0, time.UTC)
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	users.On("Count", mock.Anything, (*domain.Query)(nil)).Return(int64(10), nil)
+	users.On("Count", mock.Anything, mock.AnythingOfType("*domain.Query")).Return(int64(7), nil)
+	users.On("CountCreatedByDay", mock.Anything, since).
+		Return([]domain.DailyCount{{Date: "2024-05-01", Count: 2}, {Date: "2024-05-30", Count: 1}}, nil)
+	auditLogs.On("CountByDay", mock.Anything, domain.AuditActionLogin, since).
+		Return([]domain.DailyCount{{Date: "2024-05-15", Count: 4}}, nil)
+
+	service := &statsService{userRepo: users, auditLogRepo: auditLogs, now: func() time.Time { return now }}
+
+	stats, err := service.Overview(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), stats.TotalUsers)
+	assert.Equal(t, int64(7), stats.ActiveUsers)
+	assert.Equal(t, now, stats.GeneratedAt)
+
+	// Every day is present, oldest first, including those without any
+	require.Len(t, stats.SignupsPerDay, domain.StatsDays)
+	require.Len(t, stats.LoginsPerDay, domain.StatsDays)
+	assert.Equal(t, domain.DailyCount{Date: "2024-05-01", Count: 2}, stats.SignupsPerDay[0])
+	assert.Equal(t, domain.DailyCount{Date: "2024-05-02", Count: 0}, stats.SignupsPerDay[1])
+	assert.Equal(t, domain.DailyCount{Date: "2024-05-30", Count: 1}, stats.SignupsPerDay[29])
+	assert.Equal(t, domain.DailyCount{Date: "2024-05-15", Count: 4}, stats.LoginsPerDay[14])
+	assert.Equal(t, domain.DailyCount{Date: "2024-05-30", Count: 0}, stats.LoginsPerDay[29])
+}
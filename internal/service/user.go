@@ -2,40 +2,107 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
 	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/luxixing/fx-gin-scaffold/pkg/database"
+	"github.com/luxixing/fx-gin-scaffold/pkg/events"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/mailer"
+	"github.com/luxixing/fx-gin-scaffold/pkg/ratelimit"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
 // UserServiceParams holds dependencies for UserService
 type UserServiceParams struct {
 	fx.In
-	UserRepo    domain.UserRepository
-	AuthService domain.AuthService
+	Config            *config.Config
+	Cache             cache.Client
+	UserRepo          domain.UserRepository
+	InviteRepo        domain.InviteRepository
+	AuthService       domain.AuthService
+	PermissionService domain.PermissionService
+	AuditService      domain.AuditService
+	SearchService     domain.SearchService
+	Events            *events.Bus
+	Notifier          domain.Notifier
+	Mailer            mailer.Mailer
+	MailRenderer      *mailer.Renderer
+	PasswordHasher    domain.PasswordHasher
+	Validator         domain.Validator
+	TxManager         domain.TxManager
 }
 
 // userService implements domain.UserService
 type userService struct {
-	userRepo    domain.UserRepository
-	authService domain.AuthService
+	config            *config.Config
+	cache             cache.Client
+	userRepo          domain.UserRepository
+	inviteRepo        domain.InviteRepository
+	authService       domain.AuthService
+	permissionService domain.PermissionService
+	auditService      domain.AuditService
+	searchService     domain.SearchService
+	events            *events.Bus
+	notifier          domain.Notifier
+	mailer            mailer.Mailer
+	mailRenderer      *mailer.Renderer
+	passwordHasher    domain.PasswordHasher
+	validator         domain.Validator
+	txManager         domain.TxManager
 }
 
 // NewUserService creates a new user service
 func NewUserService(p UserServiceParams) domain.UserService {
 	return &userService{
-		userRepo:    p.UserRepo,
-		authService: p.AuthService,
+		config:            p.Config,
+		cache:             p.Cache,
+		userRepo:          p.UserRepo,
+		inviteRepo:        p.InviteRepo,
+		authService:       p.AuthService,
+		permissionService: p.PermissionService,
+		auditService:      p.AuditService,
+		searchService:     p.SearchService,
+		events:            p.Events,
+		notifier:          p.Notifier,
+		mailer:            p.Mailer,
+		mailRenderer:      p.MailRenderer,
+		passwordHasher:    p.PasswordHasher,
+		validator:         p.Validator,
+		txManager:         p.TxManager,
 	}
 }
 
-// Register creates a new user account
+// Register creates a new user account. An invite code grants the role of its
+// invite, and is required when registration is invite-only.
 func (s *userService) Register(ctx context.Context, req *domain.UserCreateRequest) (*domain.UserResponse, error) {
+	if s.config.Registration.Mode == config.RegistrationModeClosed {
+		return nil, domain.ErrRegistrationClosed
+	}
+
 	// Validate input
 	if err := s.validateCreateRequest(req); err != nil {
 		return nil, err
 	}
+	role, ok := s.config.RegistrationRole(req.Role)
+	if !ok {
+		return nil, domain.ValidationError("role", "cannot be chosen when registering")
+	}
+
+	var invite *domain.Invite
+	if req.InviteCode != "" || s.config.Registration.Mode == config.RegistrationModeInvite {
+		var err error
+		if invite, err = s.pendingInvite(ctx, req); err != nil {
+			return nil, err
+		}
+	}
 
 	// Check if user already exists
 	if _, err := s.userRepo.GetByEmail(ctx, req.Email); err == nil {
@@ -46,61 +113,117 @@ func (s *userService) Register(ctx context.Context, req *domain.UserCreateReques
 
 	// Create user
 	user := &domain.User{
-		Email:     strings.ToLower(strings.TrimSpace(req.Email)),
+		Email:     req.Email,
 		Password:  req.Password,
-		Name:      strings.TrimSpace(req.Name),
-		Role:      s.getDefaultRole(req.Role),
+		Name:      req.Name,
+		Role:      role,
 		Active:    true,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	if invite != nil {
+		user.Role = invite.Role
+	}
 
 	// Hash password
-	if err := user.HashPassword(); err != nil {
+	if err := user.HashPassword(s.passwordHasher); err != nil {
 		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to hash password")
 	}
 
-	// Save user
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	// Save user, redeeming the invite
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		if invite == nil {
+			return nil
+		}
+
+		now := time.Now()
+		invite.AcceptedAt = &now
+		invite.AcceptedByID = &user.ID
+		return s.inviteRepo.Update(ctx, invite)
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.invalidateUserCache(ctx, 0)
 
-	return user.ToResponse(), nil
+	response := user.ToResponse()
+	publishEvent(ctx, s.events, domain.UserRegistered{User: response})
+
+	return response, nil
 }
 
-// Login authenticates a user and returns a token
-func (s *userService) Login(ctx context.Context, req *domain.UserLoginRequest) (string, *domain.UserResponse, error) {
+// Login authenticates a user and returns a token pair
+func (s *userService) Login(ctx context.Context, req *domain.UserLoginRequest) (*domain.TokenPair, *domain.UserResponse, error) {
 	// Validate input
 	if err := s.validateLoginRequest(req); err != nil {
-		return "", nil, err
+		return nil, nil, err
+	}
+
+	// Refuse accounts locked out by failed attempts, even with the right
+	// password; logins go on when the failure counters can't be reached
+	lockout := s.loginLockout()
+	if lockout != nil {
+		locked, err := lockout.Locked(ctx, req.Email)
+		if err != nil {
+			logger.FromContext(ctx).Warn("failed to check login lockout", zap.Error(err))
+		}
+		if locked {
+			return nil, nil, domain.ErrLoginLocked
+		}
 	}
 
-	// Get user by email
-	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(req.Email)))
+	// Get user by email; unknown addresses count towards a lockout too, so
+	// that lockouts don't reveal which accounts exist
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		if err == domain.ErrUserNotFound {
-			return "", nil, domain.ErrInvalidPassword
+			s.loginFailed(ctx, lockout, req.Email)
+			return nil, nil, domain.ErrInvalidPassword
 		}
-		return "", nil, err
+		return nil, nil, err
 	}
 
 	// Check if user is active
 	if !user.Active {
-		return "", nil, domain.NewError(domain.ErrCodeForbidden, "Account is deactivated")
+		return nil, nil, domain.NewError(domain.ErrCodeForbidden, "Account is deactivated")
 	}
 
 	// Verify password
-	if !user.CheckPassword(req.Password) {
-		return "", nil, domain.ErrInvalidPassword
+	if !user.CheckPassword(s.passwordHasher, req.Password) {
+		s.loginFailed(ctx, lockout, req.Email)
+		return nil, nil, domain.ErrInvalidPassword
+	}
+	if lockout != nil {
+		if err := lockout.Reset(ctx, req.Email); err != nil {
+			logger.FromContext(ctx).Warn("failed to reset login failures", zap.Uint("user_id", user.ID), zap.Error(err))
+		}
 	}
 
-	// Generate token
-	token, err := s.authService.GenerateToken(user)
+	// Upgrade the stored hash if the hashing configuration has changed
+	if s.passwordHasher.NeedsRehash(user.Password) {
+		s.rehashPassword(ctx, user, req.Password)
+	}
+
+	// Logging in during the grace period restores a deleted account
+	if user.DeletionScheduledAt != nil {
+		if err := s.cancelDeletion(ctx, user); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Issue access and refresh tokens
+	pair, err := s.authService.IssueTokenPair(ctx, user)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
-	return token, user.ToResponse(), nil
+	response := user.ToResponse()
+	publishEvent(ctx, s.events, domain.LoginSucceeded{User: response})
+
+	return pair, response, nil
 }
 
 // GetProfile retrieves the user's profile
@@ -122,11 +245,8 @@ func (s *userService) UpdateProfile(ctx context.Context, userID uint, req *domai
 	}
 
 	// Update fields
-	if req.Name != nil {
-		user.Name = strings.TrimSpace(*req.Name)
-		if user.Name == "" {
-			return nil, domain.ValidationError("name", "cannot be empty")
-		}
+	if err := s.applyProfileUpdate(user, req); err != nil {
+		return nil, err
 	}
 
 	user.UpdatedAt = time.Now()
@@ -135,23 +255,358 @@ func (s *userService) UpdateProfile(ctx context.Context, userID uint, req *domai
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
+	s.invalidateUserCache(ctx, user.ID)
 
-	return user.ToResponse(), nil
+	response := user.ToResponse()
+	s.notifyProfileUpdated(ctx, response)
+	publishEvent(ctx, s.events, domain.UserUpdated{User: response})
+
+	return response, nil
 }
 
-// GetUser retrieves a user by ID (admin only)
-func (s *userService) GetUser(ctx context.Context, id uint) (*domain.UserResponse, error) {
+// ChangePassword changes the user's password after verifying the old one
+// and signs the user out of every other session
+func (s *userService) ChangePassword(ctx context.Context, userID uint, req *domain.ChangePasswordRequest) error {
+	if req.OldPassword == "" {
+		return domain.ValidationError("old_password", "is required")
+	}
+
+	if len(req.NewPassword) < 8 {
+		return domain.ValidationError("new_password", "must be at least 8 characters")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.CheckPassword(s.passwordHasher, req.OldPassword) {
+		return domain.ErrInvalidPassword
+	}
+
+	if user.CheckPassword(s.passwordHasher, req.NewPassword) {
+		return domain.ValidationError("new_password", "must differ from the old password")
+	}
+
+	user.Password = req.NewPassword
+	if err := user.HashPassword(s.passwordHasher); err != nil {
+		return domain.WrapError(err, domain.ErrCodeInternal, "Failed to hash password")
+	}
+	user.UpdatedAt = time.Now()
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		// Existing refresh tokens were issued against the old password
+		return s.authService.RevokeAllRefreshTokens(ctx, user.ID)
+	})
+}
+
+// ResetPassword sets a user's password without checking the old one. Every
+// session is signed out, as when the user changes it.
+func (s *userService) ResetPassword(ctx context.Context, id uint, password string) error {
+	if len(password) < 8 {
+		return domain.ValidationError("password", "must be at least 8 characters")
+	}
+
 	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
+		return err
+	}
+
+	user.Password = password
+	if err := user.HashPassword(s.passwordHasher); err != nil {
+		return domain.WrapError(err, domain.ErrCodeInternal, "Failed to hash password")
+	}
+	user.UpdatedAt = time.Now()
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.authService.RevokeAllRefreshTokens(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.auditService, &domain.AuditLog{
+		Action:     domain.AuditActionPasswordReset,
+		TargetType: "user",
+		TargetID:   user.ID,
+	})
+	return nil
+}
+
+// RequestEmailChange stores a pending email after verifying the password
+// and mails a confirmation link to the new address
+func (s *userService) RequestEmailChange(ctx context.Context, userID uint, req *domain.EmailChangeRequest) (*domain.UserResponse, error) {
+	if err := s.validator.Validate(req); err != nil {
 		return nil, err
 	}
+	email := req.Email
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.CheckPassword(s.passwordHasher, req.Password) {
+		return nil, domain.ErrInvalidPassword
+	}
+
+	if email == user.Email {
+		return nil, domain.ValidationError("email", "must differ from the current email")
+	}
+
+	if err := s.ensureEmailAvailable(ctx, email); err != nil {
+		return nil, err
+	}
+
+	user.PendingEmail = email
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	s.invalidateUserCache(ctx, user.ID)
+
+	token, err := s.authService.GenerateEmailChangeToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	link := strings.TrimRight(s.config.App.URL, "/") + "/api/v1/auth/email/confirm?token=" + url.QueryEscape(token)
+	msg, err := s.mailRenderer.Message("email_change", map[string]interface{}{
+		"Name":      user.Name,
+		"Email":     email,
+		"Link":      link,
+		"ExpiresIn": s.config.JWT.EmailChangeExpiration.String(),
+	}, "Confirm your new email address", email)
+	if err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to render confirmation email")
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to send confirmation email")
+	}
 
 	return user.ToResponse(), nil
 }
 
-// ListUsers retrieves users with pagination (admin only)
-func (s *userService) ListUsers(ctx context.Context, offset, limit int) ([]*domain.UserResponse, int64, error) {
-	users, total, err := s.userRepo.List(ctx, offset, limit)
+// ConfirmEmailChange swaps in the pending email identified by a confirmation
+// token. Tokens for an email that is no longer pending are rejected.
+func (s *userService) ConfirmEmailChange(ctx context.Context, token string) (*domain.UserResponse, error) {
+	claims, err := s.authService.ValidateEmailChangeToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if user.PendingEmail == "" || user.PendingEmail != claims.Email {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// The address may have been taken since the change was requested
+	if err := s.ensureEmailAvailable(ctx, claims.Email); err != nil {
+		return nil, err
+	}
+
+	before := user.ToResponse()
+	user.Email = claims.Email
+	user.PendingEmail = ""
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	s.invalidateUserCache(ctx, user.ID)
+
+	after := user.ToResponse()
+	recordAudit(ctx, s.auditService, &domain.AuditLog{
+		ActorID:    user.ID,
+		Action:     domain.AuditActionEmailChange,
+		TargetType: "user",
+		TargetID:   user.ID,
+		Before:     domain.AuditSnapshot(before),
+		After:      domain.AuditSnapshot(after),
+	})
+	s.notifyProfileUpdated(ctx, after)
+	publishEvent(ctx, s.events, domain.UserUpdated{User: after})
+
+	return after, nil
+}
+
+// DeleteAccount schedules the user's account for deletion after verifying
+// the password. The account is purged once the grace period has passed,
+// unless the user logs in again before then.
+func (s *userService) DeleteAccount(ctx context.Context, userID uint, req *domain.DeleteAccountRequest) (*domain.UserResponse, error) {
+	if req.Password == "" {
+		return nil, domain.ValidationError("password", "is required")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.CheckPassword(s.passwordHasher, req.Password) {
+		return nil, domain.ErrInvalidPassword
+	}
+
+	// Deleting again keeps the original schedule
+	if user.DeletionScheduledAt != nil {
+		return user.ToResponse(), nil
+	}
+
+	scheduledAt := time.Now().Add(s.config.Accounts.DeletionGracePeriod)
+	user.DeletionScheduledAt = &scheduledAt
+	user.UpdatedAt = time.Now()
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		// Sign out every device; logging in again cancels the deletion
+		return s.authService.RevokeAllRefreshTokens(ctx, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateUserCache(ctx, user.ID)
+
+	response := user.ToResponse()
+	recordAudit(ctx, s.auditService, &domain.AuditLog{
+		ActorID:    user.ID,
+		Action:     domain.AuditActionDeleteRequest,
+		TargetType: "user",
+		TargetID:   user.ID,
+		After:      map[string]interface{}{"deletion_scheduled_at": scheduledAt},
+	})
+
+	return response, nil
+}
+
+// cancelDeletion restores an account scheduled for deletion
+func (s *userService) cancelDeletion(ctx context.Context, user *domain.User) error {
+	user.DeletionScheduledAt = nil
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	s.invalidateUserCache(ctx, user.ID)
+
+	recordAudit(ctx, s.auditService, &domain.AuditLog{
+		ActorID:    user.ID,
+		Action:     domain.AuditActionDeleteCancel,
+		TargetType: "user",
+		TargetID:   user.ID,
+	})
+	return nil
+}
+
+// purgeBatchSize is the number of due accounts loaded per query when purging
+const purgeBatchSize = 100
+
+// PurgeDeletedAccounts deletes the accounts whose grace period has passed
+func (s *userService) PurgeDeletedAccounts(ctx context.Context) (int64, error) {
+	query := domain.NewQuery("deletion_scheduled_at").Where("deletion_scheduled_at", domain.OpLt, time.Now())
+
+	var purged int64
+	for {
+		users, _, err := s.userRepo.List(database.WithPrimary(ctx), query, domain.ListOptions{IncludeInactive: true}, 0, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, user := range users {
+			if err := s.deleteUser(ctx, user); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(users) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// ensureEmailAvailable returns ErrUserExists if the email belongs to an account
+func (s *userService) ensureEmailAvailable(ctx context.Context, email string) error {
+	if _, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		return domain.ErrUserExists
+	} else if err != domain.ErrUserNotFound {
+		return err
+	}
+	return nil
+}
+
+// GetUser retrieves a user by ID
+func (s *userService) GetUser(ctx context.Context, id uint) (*domain.UserResponse, error) {
+	return cacheAside(ctx, s.cache, userCacheKey(id), s.config.Cache.UserTTL, func() (*domain.UserResponse, error) {
+		user, err := s.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return user.ToResponse(), nil
+	})
+}
+
+// userPage is a cached page of users
+type userPage struct {
+	Users []*domain.UserResponse `json:"users"`
+	Total int64                  `json:"total"`
+}
+
+// ListUsers retrieves users matching the query and options with pagination (admin only)
+func (s *userService) ListUsers(ctx context.Context, query *domain.Query, opts domain.ListOptions, offset, limit int) ([]*domain.UserResponse, int64, error) {
+	var key string
+	if s.config.Cache.UserListTTL > 0 {
+		key = userListCacheKey(ctx, s.cache, query, opts, offset, limit)
+	}
+
+	page, err := cacheAside(ctx, s.cache, key, s.config.Cache.UserListTTL, func() (*userPage, error) {
+		users, total, err := s.userRepo.List(ctx, query, opts, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		responses := make([]*domain.UserResponse, len(users))
+		for i, user := range users {
+			responses[i] = user.ToResponse()
+		}
+
+		return &userPage{Users: responses, Total: total}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return page.Users, page.Total, nil
+}
+
+// SearchUsers searches active and inactive users (admin only). The search
+// index ranks results when it is enabled; otherwise the repository matches
+// name and email.
+func (s *userService) SearchUsers(ctx context.Context, query string, offset, limit int) ([]*domain.UserResponse, int64, error) {
+	if strings.TrimSpace(query) == "" {
+		return s.ListUsers(ctx, nil, domain.ListOptions{IncludeInactive: true}, offset, limit)
+	}
+
+	if s.searchService.Enabled() {
+		return s.searchIndexedUsers(ctx, query, offset, limit)
+	}
+
+	users, total, err := s.userRepo.Search(ctx, query, offset, limit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -164,15 +619,55 @@ func (s *userService) ListUsers(ctx context.Context, offset, limit int) ([]*doma
 	return responses, total, nil
 }
 
-// SearchUsers searches users (admin only)
-func (s *userService) SearchUsers(ctx context.Context, query string, offset, limit int) ([]*domain.UserResponse, int64, error) {
+// searchIndexedUsers loads the users found in the search index in ranking
+// order. Users deleted since they were indexed are skipped.
+func (s *userService) searchIndexedUsers(ctx context.Context, query string, offset, limit int) ([]*domain.UserResponse, int64, error) {
+	ids, total, err := s.searchService.SearchUserIDs(ctx, query, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*domain.UserResponse, 0, len(ids))
+	for _, id := range ids {
+		user, err := s.userRepo.GetByID(ctx, id)
+		if err == domain.ErrUserNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		responses = append(responses, user.ToResponse())
+	}
+
+	return responses, total, nil
+}
+
+// ListUsersByCursor retrieves users matching the query and options with keyset pagination (admin only)
+func (s *userService) ListUsersByCursor(ctx context.Context, query *domain.Query, opts domain.ListOptions, page *domain.CursorPage) ([]*domain.UserResponse, bool, error) {
+	users, hasMore, err := s.userRepo.ListByCursor(ctx, query, opts, page)
+	if err != nil {
+		return nil, false, err
+	}
+
+	responses := make([]*domain.UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = user.ToResponse()
+	}
+
+	return responses, hasMore, nil
+}
+
+// SearchUsersByCursor searches active and inactive users with keyset
+// pagination (admin only). Keyset pages always come from the repository, even
+// with the search index enabled.
+func (s *userService) SearchUsersByCursor(ctx context.Context, query string, page *domain.CursorPage) ([]*domain.UserResponse, bool, error) {
 	if strings.TrimSpace(query) == "" {
-		return s.ListUsers(ctx, offset, limit)
+		return s.ListUsersByCursor(ctx, nil, domain.ListOptions{IncludeInactive: true}, page)
 	}
 
-	users, total, err := s.userRepo.Search(ctx, query, offset, limit)
+	users, hasMore, err := s.userRepo.SearchByCursor(ctx, query, page)
 	if err != nil {
-		return nil, 0, err
+		return nil, false, err
 	}
 
 	responses := make([]*domain.UserResponse, len(users))
@@ -180,7 +675,7 @@ func (s *userService) SearchUsers(ctx context.Context, query string, offset, lim
 		responses[i] = user.ToResponse()
 	}
 
-	return responses, total, nil
+	return responses, hasMore, nil
 }
 
 // UpdateUser updates a user (admin only)
@@ -190,18 +685,16 @@ func (s *userService) UpdateUser(ctx context.Context, id uint, req *domain.UserU
 	if err != nil {
 		return nil, err
 	}
+	before := user.ToResponse()
 
 	// Update fields
-	if req.Name != nil {
-		user.Name = strings.TrimSpace(*req.Name)
-		if user.Name == "" {
-			return nil, domain.ValidationError("name", "cannot be empty")
-		}
+	if err := s.applyProfileUpdate(user, req); err != nil {
+		return nil, err
 	}
 
 	if req.Role != nil {
-		if *req.Role != "user" && *req.Role != "admin" {
-			return nil, domain.ValidationError("role", "must be 'user' or 'admin'")
+		if err := s.checkAssignableRole(ctx, *req.Role); err != nil {
+			return nil, err
 		}
 		user.Role = *req.Role
 	}
@@ -216,36 +709,158 @@ func (s *userService) UpdateUser(ctx context.Context, id uint, req *domain.UserU
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
+	s.invalidateUserCache(ctx, user.ID)
 
-	return user.ToResponse(), nil
+	after := user.ToResponse()
+	action := domain.AuditActionUserUpdate
+	if before.Role != after.Role {
+		action = domain.AuditActionUserRoleChange
+	}
+	recordAudit(ctx, s.auditService, &domain.AuditLog{
+		Action:     action,
+		TargetType: "user",
+		TargetID:   user.ID,
+		Before:     domain.AuditSnapshot(before),
+		After:      domain.AuditSnapshot(after),
+	})
+	s.notifyProfileUpdated(ctx, after)
+	publishEvent(ctx, s.events, domain.UserUpdated{User: after})
+
+	return after, nil
+}
+
+// CreateUser creates an active user with the requested role. Unlike
+// Register it ignores the registration mode, so operators can create
+// accounts when registration is closed.
+func (s *userService) CreateUser(ctx context.Context, req *domain.UserCreateRequest) (*domain.UserResponse, error) {
+	if err := s.validateCreateRequest(req); err != nil {
+		return nil, err
+	}
+
+	role := req.Role
+	if role == "" {
+		role = domain.RoleUser
+	}
+	if err := s.checkAssignableRole(ctx, role); err != nil {
+		return nil, err
+	}
+
+	email := req.Email
+	if _, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		return nil, domain.ErrUserExists
+	} else if err != domain.ErrUserNotFound {
+		return nil, err
+	}
+
+	user := &domain.User{
+		Email:     email,
+		Password:  req.Password,
+		Name:      req.Name,
+		Role:      role,
+		Active:    true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := user.HashPassword(s.passwordHasher); err != nil {
+		return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to hash password")
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	s.invalidateUserCache(ctx, 0)
+
+	response := user.ToResponse()
+	recordAudit(ctx, s.auditService, &domain.AuditLog{
+		Action:     domain.AuditActionUserCreate,
+		TargetType: "user",
+		TargetID:   user.ID,
+		After:      domain.AuditSnapshot(response),
+	})
+	publishEvent(ctx, s.events, domain.UserRegistered{User: response})
+
+	return response, nil
 }
 
 // DeleteUser deletes a user (admin only)
 func (s *userService) DeleteUser(ctx context.Context, id uint) error {
 	// Check if user exists
-	if _, err := s.userRepo.GetByID(ctx, id); err != nil {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
 		return err
 	}
 
-	return s.userRepo.Delete(ctx, id)
+	return s.deleteUser(ctx, user)
+}
+
+// deleteUser deletes a user and announces it
+func (s *userService) deleteUser(ctx context.Context, user *domain.User) error {
+	if err := s.userRepo.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+	s.invalidateUserCache(ctx, user.ID)
+
+	publishEvent(ctx, s.events, domain.UserDeleted{User: user.ToResponse()})
+
+	return nil
 }
 
 // validateCreateRequest validates user creation request
 func (s *userService) validateCreateRequest(req *domain.UserCreateRequest) error {
-	if strings.TrimSpace(req.Email) == "" {
-		return domain.ValidationError("email", "is required")
-	}
+	return s.validator.Validate(req)
+}
 
-	if strings.TrimSpace(req.Name) == "" {
-		return domain.ValidationError("name", "is required")
+// applyProfileUpdate validates req and applies the fields it sets to the
+// user's profile. Metadata is merged into a copy so earlier snapshots of the
+// user keep their values.
+func (s *userService) applyProfileUpdate(user *domain.User, req *domain.UserUpdateRequest) error {
+	if err := s.validator.Validate(req); err != nil {
+		return err
 	}
 
-	if len(strings.TrimSpace(req.Name)) < 2 {
-		return domain.ValidationError("name", "must be at least 2 characters")
+	if req.Name != nil {
+		user.Name = *req.Name
+		if user.Name == "" {
+			return domain.ValidationError("name", "cannot be empty")
+		}
+	}
+	if req.AvatarURL != nil {
+		user.AvatarURL = *req.AvatarURL
+	}
+	if req.Phone != nil {
+		user.Phone = *req.Phone
+	}
+	if req.Locale != nil {
+		user.Locale = *req.Locale
+	}
+	if req.Timezone != nil {
+		user.Timezone = *req.Timezone
 	}
 
-	if len(req.Password) < 8 {
-		return domain.ValidationError("password", "must be at least 8 characters")
+	if req.Metadata != nil {
+		metadata := make(map[string]interface{}, len(user.Metadata)+len(req.Metadata))
+		for key, value := range user.Metadata {
+			metadata[key] = value
+		}
+		for key, value := range req.Metadata {
+			if value == nil {
+				delete(metadata, key)
+				continue
+			}
+			metadata[key] = value
+		}
+
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			return domain.ValidationError("metadata", "must be a JSON object")
+		}
+		if len(encoded) > domain.MaxUserMetadataSize {
+			return domain.ValidationError("metadata", fmt.Sprintf("must be at most %d bytes", domain.MaxUserMetadataSize))
+		}
+		if len(metadata) == 0 {
+			metadata = nil
+		}
+		user.Metadata = metadata
 	}
 
 	return nil
@@ -253,21 +868,95 @@ func (s *userService) validateCreateRequest(req *domain.UserCreateRequest) error
 
 // validateLoginRequest validates login request
 func (s *userService) validateLoginRequest(req *domain.UserLoginRequest) error {
-	if strings.TrimSpace(req.Email) == "" {
-		return domain.ValidationError("email", "is required")
+	return s.validator.Validate(req)
+}
+
+// notifyProfileUpdated pushes the updated profile to the user's open connections
+func (s *userService) notifyProfileUpdated(ctx context.Context, user *domain.UserResponse) {
+	event := domain.NewEvent(domain.EventProfileUpdated, user)
+	if err := s.notifier.NotifyUser(ctx, user.ID, event); err != nil {
+		logger.FromContext(ctx).Warn("failed to notify user", zap.Uint("user_id", user.ID), zap.Error(err))
 	}
+}
 
-	if req.Password == "" {
-		return domain.ValidationError("password", "is required")
+// invalidateUserCache drops cached reads of a user and of user lists after a
+// change. A zero ID only drops the lists.
+func (s *userService) invalidateUserCache(ctx context.Context, id uint) {
+	if s.config.Cache.UserTTL > 0 || s.config.Cache.UserListTTL > 0 {
+		invalidateUserCache(ctx, s.cache, id)
 	}
+}
 
-	return nil
+// loginFailuresKeyPrefix is the cache key prefix for failed login counters
+const loginFailuresKeyPrefix = "login_failures:"
+
+// loginLockout returns the lockout of email addresses failing to log in, or
+// nil when LOGIN_MAX_ATTEMPTS is 0. The counters are kept in the cache, so
+// they are shared by all instances when REDIS_ADDR is set.
+func (s *userService) loginLockout() *ratelimit.Lockout {
+	if s.config.Accounts.LoginMaxAttempts == 0 {
+		return nil
+	}
+	return ratelimit.NewLockout(s.cache, loginFailuresKeyPrefix, s.config.Accounts.LoginMaxAttempts, s.config.Accounts.LoginLockoutDuration)
+}
+
+// loginFailed counts a failed login to email towards its lockout
+func (s *userService) loginFailed(ctx context.Context, lockout *ratelimit.Lockout, email string) {
+	if lockout == nil {
+		return
+	}
+	if _, err := lockout.Fail(ctx, email); err != nil {
+		logger.FromContext(ctx).Warn("failed to count login failure", zap.Error(err))
+	}
+}
+
+// rehashPassword re-hashes a verified password with the current hashing
+// configuration. Failures are logged; the old hash keeps working.
+func (s *userService) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	hash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to rehash password", zap.Uint("user_id", user.ID), zap.Error(err))
+		return
+	}
+
+	user.Password = hash
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logger.FromContext(ctx).Warn("failed to store rehashed password", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
 }
 
-// getDefaultRole returns the default role for a user
-func (s *userService) getDefaultRole(requestedRole string) string {
-	if requestedRole == "admin" || requestedRole == "user" {
-		return requestedRole
+// pendingInvite returns the pending invite of the request's invite code,
+// which must have been sent to the registering email address
+func (s *userService) pendingInvite(ctx context.Context, req *domain.UserCreateRequest) (*domain.Invite, error) {
+	if req.InviteCode == "" {
+		return nil, domain.ErrInviteRequired
+	}
+
+	invite, err := s.inviteRepo.GetByTokenHash(ctx, hashToken(req.InviteCode))
+	if err != nil {
+		return nil, err
+	}
+	if invite.Status() != domain.InviteStatusPending {
+		return nil, domain.ErrInviteNotFound
 	}
-	return "user"
+	if !strings.EqualFold(invite.Email, req.Email) {
+		return nil, domain.ErrInviteMismatch
+	}
+	return invite, nil
+}
+
+// checkAssignableRole returns a validation error unless the role is defined
+// and listed in the ROLES catalog
+func (s *userService) checkAssignableRole(ctx context.Context, role string) error {
+	exists, err := s.permissionService.RoleExists(ctx, role)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ValidationError("role", "is not a defined role")
+	}
+	if !s.config.RoleAssignable(role) {
+		return domain.ValidationError("role", "is not in the role catalog")
+	}
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/jsonpatch"
+)
+
+// profilePatchDocument is the document patches of a profile apply to: the
+// fields of UserUpdateRequest a user can change
+type profilePatchDocument struct {
+	Name      *string                `json:"name"`
+	AvatarURL string                 `json:"avatar_url"`
+	Phone     string                 `json:"phone"`
+	Locale    string                 `json:"locale"`
+	Timezone  string                 `json:"timezone"`
+	Metadata  map[string]interface{} `json:"metadata"`
+}
+
+// userPatchDocument is the document patches of a user apply to, which adds
+// the fields only admins can change
+type userPatchDocument struct {
+	profilePatchDocument
+	Role   *string `json:"role"`
+	Active *bool   `json:"active"`
+}
+
+// newProfilePatchDocument returns the profile document of a user. Metadata
+// is an empty object rather than null so JSON Patch can add keys to it.
+func newProfilePatchDocument(user *domain.User) profilePatchDocument {
+	metadata := user.Metadata
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	return profilePatchDocument{
+		Name:      &user.Name,
+		AvatarURL: user.AvatarURL,
+		Phone:     user.Phone,
+		Locale:    user.Locale,
+		Timezone:  user.Timezone,
+		Metadata:  metadata,
+	}
+}
+
+// PatchProfile applies a patch to the user's profile and saves the result
+// as UpdateProfile does
+func (s *userService) PatchProfile(ctx context.Context, userID uint, patch *domain.Patch) (*domain.UserResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var before, after profilePatchDocument
+	if err := applyPatch(patch, newProfilePatchDocument(user), &before, &after); err != nil {
+		return nil, err
+	}
+
+	req := &domain.UserUpdateRequest{}
+	if err := profileChanges(&before, &after, req); err != nil {
+		return nil, err
+	}
+	return s.UpdateProfile(ctx, userID, req)
+}
+
+// PatchUser applies a patch to a user and saves the result as UpdateUser
+// does
+func (s *userService) PatchUser(ctx context.Context, id uint, patch *domain.Patch) (*domain.UserResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var before, after userPatchDocument
+	current := userPatchDocument{profilePatchDocument: newProfilePatchDocument(user), Role: &user.Role, Active: &user.Active}
+	if err := applyPatch(patch, current, &before, &after); err != nil {
+		return nil, err
+	}
+
+	req := &domain.UserUpdateRequest{}
+	if err := profileChanges(&before.profilePatchDocument, &after.profilePatchDocument, req); err != nil {
+		return nil, err
+	}
+	if after.Role == nil {
+		return nil, domain.ValidationError("role", "cannot be removed")
+	}
+	if *after.Role != *before.Role {
+		req.Role = after.Role
+	}
+	if after.Active == nil {
+		return nil, domain.ValidationError("active", "cannot be removed")
+	}
+	if *after.Active != *before.Active {
+		req.Active = after.Active
+	}
+	return s.UpdateUser(ctx, id, req)
+}
+
+// applyPatch applies a patch to the JSON document of current and decodes
+// the document before and after the patch. Decoding both sides the same
+// way lets callers compare them field by field.
+func applyPatch(patch *domain.Patch, current, before, after interface{}) error {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeInternal, "Failed to encode document")
+	}
+	if err := json.Unmarshal(doc, before); err != nil {
+		return domain.WrapError(err, domain.ErrCodeInternal, "Failed to decode document")
+	}
+
+	var patched []byte
+	switch patch.Type {
+	case domain.PatchTypeMerge:
+		patched, err = jsonpatch.MergePatch(doc, patch.Document)
+	case domain.PatchTypeJSON:
+		patched, err = jsonpatch.Apply(doc, patch.Document)
+	default:
+		return domain.NewError(domain.ErrCodeInvalid, "Unsupported patch type")
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return domain.NewErrorWithDetails(domain.ErrCodeInvalid, "Patch test failed", err.Error())
+	}
+	if err != nil {
+		return domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid patch", err.Error())
+	}
+
+	if !bytes.HasPrefix(patched, []byte("{")) {
+		return domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid patch", "the patched document must be an object")
+	}
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(after); err != nil {
+		return domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid patch", err.Error())
+	}
+	return nil
+}
+
+// profileChanges sets the fields of req that differ between the profile
+// documents. Metadata keys missing after the patch are set to null, which
+// UserUpdateRequest treats as removal.
+func profileChanges(before, after *profilePatchDocument, req *domain.UserUpdateRequest) error {
+	if after.Name == nil {
+		return domain.ValidationError("name", "cannot be removed")
+	}
+	if *after.Name != *before.Name {
+		req.Name = after.Name
+	}
+
+	changedString := func(before, after string) *string {
+		if before == after {
+			return nil
+		}
+		return &after
+	}
+	req.AvatarURL = changedString(before.AvatarURL, after.AvatarURL)
+	req.Phone = changedString(before.Phone, after.Phone)
+	req.Locale = changedString(before.Locale, after.Locale)
+	req.Timezone = changedString(before.Timezone, after.Timezone)
+
+	metadata := make(map[string]interface{})
+	for key := range before.Metadata {
+		if _, exists := after.Metadata[key]; !exists {
+			metadata[key] = nil
+		}
+	}
+	for key, value := range after.Metadata {
+		if value == nil {
+			return domain.ValidationError("metadata", "values cannot be null")
+		}
+		if old, exists := before.Metadata[key]; !exists || !reflect.DeepEqual(old, value) {
+			metadata[key] = value
+		}
+	}
+	if len(metadata) > 0 {
+		req.Metadata = metadata
+	}
+	return nil
+}
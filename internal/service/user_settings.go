@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// userSettingsCacheKeyPrefix prefixes the cache keys of a user's settings
+const userSettingsCacheKeyPrefix = "cache:user_settings:"
+
+// UserSettingsServiceParams holds dependencies for UserSettingsService
+type UserSettingsServiceParams struct {
+	fx.In
+	Config          *config.Config
+	Cache           cache.Client
+	UserSettingRepo domain.UserSettingRepository
+	TxManager       domain.TxManager
+}
+
+// userSettingsService implements domain.UserSettingsService
+type userSettingsService struct {
+	config          *config.Config
+	cache           cache.Client
+	userSettingRepo domain.UserSettingRepository
+	txManager       domain.TxManager
+}
+
+// NewUserSettingsService creates a new user settings service
+func NewUserSettingsService(p UserSettingsServiceParams) domain.UserSettingsService {
+	return &userSettingsService{
+		config:          p.Config,
+		cache:           p.Cache,
+		userSettingRepo: p.UserSettingRepo,
+		txManager:       p.TxManager,
+	}
+}
+
+// GetSettings returns the user's settings merged over the defaults
+func (s *userSettingsService) GetSettings(ctx context.Context, userID uint) (domain.UserSettings, error) {
+	// The stored settings are cached rather than the merged ones, so values
+	// keep their setting types after a round trip through the cache
+	stored, err := cacheAside(ctx, s.cache, userSettingsCacheKey(userID), s.config.Cache.UserSettingsTTL, func() ([]*domain.UserSetting, error) {
+		return s.userSettingRepo.ListByUser(ctx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mergeUserSettings(ctx, stored), nil
+}
+
+// UpdateSettings stores the settings that differ from their defaults and
+// deletes the ones reset to null
+func (s *userSettingsService) UpdateSettings(ctx context.Context, userID uint, values domain.UserSettings) (domain.UserSettings, error) {
+	var upserts []*domain.UserSetting
+	var resets []string
+	var fields []domain.FieldError
+
+	for _, key := range sortedSettingKeys(values) {
+		definition, ok := domain.LookupUserSetting(key)
+		if !ok {
+			fields = append(fields, domain.FieldError{Field: key, Message: "is not a known setting"})
+			continue
+		}
+
+		if values[key] == nil {
+			resets = append(resets, key)
+			continue
+		}
+
+		value, err := definition.Normalize(values[key])
+		if err != nil {
+			if domainErr, ok := err.(*domain.Error); ok {
+				fields = append(fields, domainErr.Fields...)
+				continue
+			}
+			return nil, err
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to encode setting")
+		}
+		upserts = append(upserts, &domain.UserSetting{UserID: userID, Key: key, Value: string(encoded)})
+	}
+	if len(fields) > 0 {
+		return nil, domain.NewValidationError(fields)
+	}
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userSettingRepo.Delete(ctx, userID, resets); err != nil {
+			return err
+		}
+		return s.userSettingRepo.Upsert(ctx, upserts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.Cache.UserSettingsTTL > 0 {
+		if err := s.cache.Delete(ctx, userSettingsCacheKey(userID)); err != nil {
+			logger.FromContext(ctx).Warn("failed to invalidate user settings cache", zap.Uint("user_id", userID), zap.Error(err))
+		}
+	}
+
+	return s.GetSettings(ctx, userID)
+}
+
+// mergeUserSettings returns the defaults overridden by the stored settings.
+// Settings that are no longer defined or no longer valid are ignored.
+func mergeUserSettings(ctx context.Context, stored []*domain.UserSetting) domain.UserSettings {
+	settings := make(domain.UserSettings, len(domain.UserSettingDefinitions))
+	for _, definition := range domain.UserSettingDefinitions {
+		settings[definition.Key] = definition.Default
+	}
+
+	for _, setting := range stored {
+		definition, ok := domain.LookupUserSetting(setting.Key)
+		if !ok {
+			continue
+		}
+
+		var raw interface{}
+		if err := json.Unmarshal([]byte(setting.Value), &raw); err != nil {
+			logger.FromContext(ctx).Warn("ignoring undecodable user setting", zap.Uint("user_id", setting.UserID), zap.String("key", setting.Key))
+			continue
+		}
+		value, err := definition.Normalize(raw)
+		if err != nil {
+			continue
+		}
+		settings[setting.Key] = value
+	}
+
+	return settings
+}
+
+// sortedSettingKeys returns the keys of values in order, so validation
+// errors are reported deterministically
+func sortedSettingKeys(values domain.UserSettings) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// userSettingsCacheKey returns the cache key of a user's settings
+func userSettingsCacheKey(userID uint) string {
+	return fmt.Sprintf("%s%d", userSettingsCacheKeyPrefix, userID)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/repo"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestUserSettingsService(t *testing.T) (domain.UserSettingsService, *domain.User) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.UserSetting{}))
+
+	user := &domain.User{Email: "alice@example.com", Password: "hashedpassword", Name: "Alice", Role: domain.RoleUser, Active: true}
+	require.NoError(t, db.Create(user).Error)
+
+	cfg := &config.Config{}
+	cfg.Cache.UserSettingsTTL = time.Minute
+
+	service := NewUserSettingsService(UserSettingsServiceParams{
+		Config:          cfg,
+		Cache:           cache.NewMemoryClient(),
+		UserSettingRepo: repo.NewUserSettingGormRepository(db),
+		TxManager:       repo.NewGormTxManager(db),
+	})
+	return service, user
+}
+
+func TestUserSettingsService(t *testing.T) {
+	service, user := newTestUserSettingsService(t)
+	ctx := context.Background()
+
+	// Defaults are returned before anything is stored
+	settings, err := service.GetSettings(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserSettings{"theme": "system", "email_notifications": true, "page_size": 10}, settings)
+
+	// JSON numbers are accepted for int settings, and cached settings are invalidated
+	settings, err = service.UpdateSettings(ctx, user.ID, domain.UserSettings{"theme": "dark", "page_size": float64(50)})
+	require.NoError(t, err)
+	assert.Equal(t, "dark", settings["theme"])
+	assert.Equal(t, 50, settings["page_size"])
+
+	// Updating a stored setting replaces it, and null resets to the default
+	settings, err = service.UpdateSettings(ctx, user.ID, domain.UserSettings{"theme": "light", "page_size": nil})
+	require.NoError(t, err)
+	assert.Equal(t, "light", settings["theme"])
+	assert.Equal(t, 10, settings["page_size"])
+
+	settings, err = service.GetSettings(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserSettings{"theme": "light", "email_notifications": true, "page_size": 10}, settings)
+}
+
+func TestUserSettingsServiceValidation(t *testing.T) {
+	service, user := newTestUserSettingsService(t)
+
+	_, err := service.UpdateSettings(context.Background(), user.ID, domain.UserSettings{
+		"theme":               "purple",
+		"email_notifications": "yes",
+		"page_size":           float64(500),
+		"language":            "en",
+	})
+	require.Error(t, err)
+	domainErr, ok := err.(*domain.Error)
+	require.True(t, ok)
+	assert.Equal(t, domain.ErrCodeValidation, domainErr.Code)
+
+	var fields []string
+	for _, field := range domainErr.Fields {
+		fields = append(fields, field.Field)
+	}
+	assert.Equal(t, []string{"email_notifications", "language", "page_size", "theme"}, fields)
+
+	// Nothing is stored when any setting is invalid
+	_, err = service.UpdateSettings(context.Background(), user.ID, domain.UserSettings{"theme": "dark", "page_size": float64(0)})
+	require.Error(t, err)
+	settings, err := service.GetSettings(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "system", settings["theme"])
+}
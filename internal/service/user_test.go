@@ -0,0 +1,861 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/mocks"
+	"github.com/luxixing/fx-gin-scaffold/internal/validation"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/luxixing/fx-gin-scaffold/pkg/events"
+	"github.com/luxixing/fx-gin-scaffold/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// errDatabase stands in for an unexpected repository failure
+var errDatabase = domain.NewError(domain.ErrCodeDatabase, "connection reset")
+
+// userServiceMocks holds the mocked dependencies of a userService
+type userServiceMocks struct {
+	config      *config.Config
+	users       *mocks.UserRepository
+	invites     *mocks.InviteRepository
+	auth        *mocks.AuthService
+	permissions *mocks.PermissionService
+	hasher      *mocks.PasswordHasher
+	mailer      *mocks.Mailer
+	search      *mocks.SearchService
+	redis       *miniredis.Miniredis
+}
+
+// newMockedUserService creates a userService whose repository, auth service,
+// permissions, hasher and mailer are mocks, caching in an in-memory Redis.
+// Audit logging, notifications and transactions always succeed.
+func newMockedUserService(t *testing.T) (domain.UserService, *userServiceMocks) {
+	t.Helper()
+
+	m := &userServiceMocks{
+		users:       mocks.NewUserRepository(t),
+		invites:     mocks.NewInviteRepository(t),
+		auth:        mocks.NewAuthService(t),
+		permissions: mocks.NewPermissionService(t),
+		hasher:      mocks.NewPasswordHasher(t),
+		mailer:      mocks.NewMailer(t),
+		search:      mocks.NewSearchService(t),
+		redis:       miniredis.RunT(t),
+	}
+
+	audit := mocks.NewAuditService(t)
+	audit.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+	notifier := mocks.NewNotifier(t)
+	notifier.On("NotifyUser", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	tx := mocks.NewTxManager(t)
+	tx.On("WithinTransaction", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }).Maybe()
+
+	renderer, err := mailer.NewDefaultRenderer()
+	require.NoError(t, err)
+	redisClient, err := cache.NewRedisClient(cache.Config{Addr: m.redis.Addr()})
+	require.NoError(t, err)
+	t.Cleanup(func() { redisClient.Close() })
+
+	cfg := &config.Config{}
+	cfg.App.URL = "http://localhost:8080"
+	cfg.JWT.EmailChangeExpiration = time.Hour
+	cfg.Accounts.DeletionGracePeriod = 24 * time.Hour
+	cfg.Registration.Mode = config.RegistrationModeOpen
+	cfg.Roles.Catalog = []string{domain.RoleAdmin, domain.RoleModerator, domain.RoleUser}
+	cfg.Roles.Registration = []string{domain.RoleUser}
+	m.config = cfg
+
+	service := NewUserService(UserServiceParams{
+		Config:            cfg,
+		Cache:             redisClient,
+		UserRepo:          m.users,
+		InviteRepo:        m.invites,
+		AuthService:       m.auth,
+		PermissionService: m.permissions,
+		AuditService:      audit,
+		SearchService:     m.search,
+		Events:            events.NewBus(),
+		Notifier:          notifier,
+		Mailer:            m.mailer,
+		MailRenderer:      renderer,
+		PasswordHasher:    m.hasher,
+		Validator:         validation.New(),
+		TxManager:         tx,
+	})
+	return service, m
+}
+
+// requireCode asserts that err is a domain error with the given code
+func requireCode(t *testing.T, err error, code string) {
+	t.Helper()
+
+	var domainErr *domain.Error
+	require.True(t, errors.As(err, &domainErr), "expected a domain error, got %v", err)
+	assert.Equal(t, code, domainErr.Code, domainErr.Message)
+}
+
+// storedUser returns an active user whose password hash is "hashed"
+func storedUser() *domain.User {
+	return &domain.User{ID: 7, Email: "alice@example.com", Password: "hashed", Name: "Alice", Role: domain.RoleUser, Active: true}
+}
+
+func TestUserServiceRegister(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects invalid input before touching the repository", func(t *testing.T) {
+		service, _ := newMockedUserService(t)
+
+		_, err := service.Register(ctx, &domain.UserCreateRequest{Email: "not-an-email", Password: "password123", Name: "Alice"})
+		requireCode(t, err, domain.ErrCodeValidation)
+
+		_, err = service.Register(ctx, &domain.UserCreateRequest{Email: "alice@example.com", Password: "password123", Name: "  A  "})
+		requireCode(t, err, domain.ErrCodeValidation)
+	})
+
+	t.Run("rejects everyone when registration is closed", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.config.Registration.Mode = config.RegistrationModeClosed
+
+		_, err := service.Register(ctx, &domain.UserCreateRequest{Email: "alice@example.com", Password: "password123", Name: "Alice", InviteCode: "code"})
+		assert.Equal(t, domain.ErrRegistrationClosed, err)
+		m.invites.AssertNotCalled(t, "GetByTokenHash", mock.Anything, mock.Anything)
+		m.users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a taken email", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(storedUser(), nil)
+
+		_, err := service.Register(ctx, &domain.UserCreateRequest{Email: "alice@example.com", Password: "password123", Name: "Alice"})
+		assert.Equal(t, domain.ErrUserExists, err)
+	})
+
+	t.Run("passes repository failures through", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(nil, errDatabase)
+
+		_, err := service.Register(ctx, &domain.UserCreateRequest{Email: "alice@example.com", Password: "password123", Name: "Alice"})
+		assert.Equal(t, errDatabase, err)
+	})
+
+	t.Run("stores a normalized user with a hashed password", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(nil, domain.ErrUserNotFound)
+		m.hasher.On("Hash", "password123").Return("hashed", nil)
+		m.users.On("Create", ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.Email == "alice@example.com" && user.Name == "Alice" && user.Password == "hashed" &&
+				user.Role == domain.RoleUser && user.Active
+		})).Return(nil)
+
+		// Without a requested role users get the first registration role
+		user, err := service.Register(ctx, &domain.UserCreateRequest{Email: "Alice@Example.com", Password: "password123", Name: " Alice "})
+		require.NoError(t, err)
+		assert.Equal(t, "alice@example.com", user.Email)
+		assert.Equal(t, domain.RoleUser, user.Role)
+	})
+
+	t.Run("only grants the roles of REGISTRATION_ROLES", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+
+		for _, role := range []string{domain.RoleAdmin, domain.RoleModerator, "owner"} {
+			_, err := service.Register(ctx, &domain.UserCreateRequest{Email: "alice@example.com", Password: "password123", Name: "Alice", Role: role})
+			requireCode(t, err, domain.ErrCodeValidation)
+		}
+
+		m.config.Roles.Registration = []string{domain.RoleUser, domain.RoleModerator}
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(nil, domain.ErrUserNotFound)
+		m.hasher.On("Hash", "password123").Return("hashed", nil)
+		m.users.On("Create", ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.Role == domain.RoleModerator
+		})).Return(nil)
+
+		user, err := service.Register(ctx, &domain.UserCreateRequest{Email: "alice@example.com", Password: "password123", Name: "Alice", Role: domain.RoleModerator})
+		require.NoError(t, err)
+		assert.Equal(t, domain.RoleModerator, user.Role)
+	})
+
+	t.Run("reports hashing failures as internal errors", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(nil, domain.ErrUserNotFound)
+		m.hasher.On("Hash", "password123").Return("", errors.New("out of memory"))
+
+		_, err := service.Register(ctx, &domain.UserCreateRequest{Email: "alice@example.com", Password: "password123", Name: "Alice"})
+		requireCode(t, err, domain.ErrCodeInternal)
+		m.users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserServiceRegisterWithInvite(t *testing.T) {
+	ctx := context.Background()
+	pendingInvite := func() *domain.Invite {
+		return &domain.Invite{ID: 3, Email: "alice@example.com", Role: domain.RoleAdmin, ExpiresAt: time.Now().Add(time.Hour)}
+	}
+
+	t.Run("requires an invite code in invite mode", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.config.Registration.Mode = config.RegistrationModeInvite
+
+		_, err := service.Register(ctx, &domain.UserCreateRequest{Email: "alice@example.com", Password: "password123", Name: "Alice"})
+		assert.Equal(t, domain.ErrInviteRequired, err)
+		m.users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects invites that are not pending", func(t *testing.T) {
+		now := time.Now()
+		for name, invite := range map[string]*domain.Invite{
+			"accepted": {Email: "alice@example.com", ExpiresAt: now.Add(time.Hour), AcceptedAt: &now},
+			"revoked":  {Email: "alice@example.com", ExpiresAt: now.Add(time.Hour), RevokedAt: &now},
+			"expired":  {Email: "alice@example.com", ExpiresAt: now.Add(-time.Hour)},
+		} {
+			t.Run(name, func(t *testing.T) {
+				service, m := newMockedUserService(t)
+				m.config.Registration.Mode = config.RegistrationModeInvite
+				m.invites.On("GetByTokenHash", ctx, hashToken("code")).Return(invite, nil)
+
+				_, err := service.Register(ctx, &domain.UserCreateRequest{Email: "alice@example.com", Password: "password123", Name: "Alice", InviteCode: "code"})
+				assert.Equal(t, domain.ErrInviteNotFound, err)
+			})
+		}
+	})
+
+	t.Run("rejects invites sent to another address", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.config.Registration.Mode = config.RegistrationModeInvite
+		m.invites.On("GetByTokenHash", ctx, hashToken("code")).Return(pendingInvite(), nil)
+
+		_, err := service.Register(ctx, &domain.UserCreateRequest{Email: "mallory@example.com", Password: "password123", Name: "Mallory", InviteCode: "code"})
+		assert.Equal(t, domain.ErrInviteMismatch, err)
+	})
+
+	t.Run("grants the invite's role and marks it accepted", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.config.Registration.Mode = config.RegistrationModeInvite
+		m.invites.On("GetByTokenHash", ctx, hashToken("code")).Return(pendingInvite(), nil)
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(nil, domain.ErrUserNotFound)
+		m.hasher.On("Hash", "password123").Return("hashed", nil)
+		m.users.On("Create", ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.Role == domain.RoleAdmin
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.User).ID = 9
+		}).Return(nil)
+		m.invites.On("Update", ctx, mock.MatchedBy(func(invite *domain.Invite) bool {
+			return invite.ID == 3 && invite.AcceptedAt != nil && invite.AcceptedByID != nil && *invite.AcceptedByID == 9
+		})).Return(nil)
+
+		user, err := service.Register(ctx, &domain.UserCreateRequest{Email: " Alice@Example.com", Password: "password123", Name: "Alice", InviteCode: " code "})
+		require.NoError(t, err)
+		assert.Equal(t, domain.RoleAdmin, user.Role)
+	})
+
+	t.Run("redeems invite codes given in open mode", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.invites.On("GetByTokenHash", ctx, hashToken("unknown")).Return(nil, domain.ErrInviteNotFound)
+
+		_, err := service.Register(ctx, &domain.UserCreateRequest{Email: "alice@example.com", Password: "password123", Name: "Alice", InviteCode: "unknown"})
+		assert.Equal(t, domain.ErrInviteNotFound, err)
+	})
+}
+
+func TestUserServiceLogin(t *testing.T) {
+	ctx := context.Background()
+	req := &domain.UserLoginRequest{Email: "alice@example.com", Password: "password123"}
+
+	t.Run("does not reveal whether the email exists", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(nil, domain.ErrUserNotFound)
+
+		_, _, err := service.Login(ctx, req)
+		assert.Equal(t, domain.ErrInvalidPassword, err)
+	})
+
+	t.Run("rejects a wrong password", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "password123").Return(false)
+
+		_, _, err := service.Login(ctx, req)
+		assert.Equal(t, domain.ErrInvalidPassword, err)
+	})
+
+	t.Run("rejects deactivated accounts", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		user := storedUser()
+		user.Active = false
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(user, nil)
+
+		_, _, err := service.Login(ctx, req)
+		requireCode(t, err, domain.ErrCodeForbidden)
+		m.auth.AssertNotCalled(t, "IssueTokenPair", mock.Anything, mock.Anything)
+	})
+
+	t.Run("upgrades outdated hashes and issues tokens", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "password123").Return(true)
+		m.hasher.On("NeedsRehash", "hashed").Return(true)
+		m.hasher.On("Hash", "password123").Return("rehashed", nil)
+		m.users.On("Update", ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.Password == "rehashed"
+		})).Return(nil)
+		pair := &domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+		m.auth.On("IssueTokenPair", ctx, mock.AnythingOfType("*domain.User")).Return(pair, nil)
+
+		tokens, user, err := service.Login(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, pair, tokens)
+		assert.Equal(t, uint(7), user.ID)
+	})
+
+	t.Run("still signs in when storing the upgraded hash fails", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "password123").Return(true)
+		m.hasher.On("NeedsRehash", "hashed").Return(true)
+		m.hasher.On("Hash", "password123").Return("rehashed", nil)
+		m.users.On("Update", ctx, mock.Anything).Return(errDatabase)
+		m.auth.On("IssueTokenPair", ctx, mock.Anything).Return(&domain.TokenPair{}, nil)
+
+		_, _, err := service.Login(ctx, req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("locks out an address after repeated failures", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.config.Accounts.LoginMaxAttempts = 2
+		m.config.Accounts.LoginLockoutDuration = time.Minute
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "wrong-password").Return(false)
+		m.hasher.On("Verify", "hashed", "password123").Return(true)
+		m.hasher.On("NeedsRehash", "hashed").Return(false)
+		m.auth.On("IssueTokenPair", ctx, mock.Anything).Return(&domain.TokenPair{}, nil)
+		wrong := &domain.UserLoginRequest{Email: "alice@example.com", Password: "wrong-password"}
+
+		// A successful login clears earlier failures
+		_, _, err := service.Login(ctx, wrong)
+		assert.Equal(t, domain.ErrInvalidPassword, err)
+		_, _, err = service.Login(ctx, req)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			_, _, err = service.Login(ctx, wrong)
+			assert.Equal(t, domain.ErrInvalidPassword, err)
+		}
+		_, _, err = service.Login(ctx, req)
+		assert.Equal(t, domain.ErrLoginLocked, err)
+		m.auth.AssertNumberOfCalls(t, "IssueTokenPair", 1)
+	})
+
+	t.Run("signs in when the lockout can't be checked", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.config.Accounts.LoginMaxAttempts = 2
+		m.config.Accounts.LoginLockoutDuration = time.Minute
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "password123").Return(true)
+		m.hasher.On("NeedsRehash", "hashed").Return(false)
+		m.auth.On("IssueTokenPair", ctx, mock.Anything).Return(&domain.TokenPair{}, nil)
+		m.redis.SetError("connection refused")
+
+		_, _, err := service.Login(ctx, req)
+		assert.NoError(t, err)
+	})
+}
+
+func TestUserServiceChangePassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("validates the request first", func(t *testing.T) {
+		service, _ := newMockedUserService(t)
+
+		err := service.ChangePassword(ctx, 7, &domain.ChangePasswordRequest{NewPassword: "new-password"})
+		requireCode(t, err, domain.ErrCodeValidation)
+		err = service.ChangePassword(ctx, 7, &domain.ChangePasswordRequest{OldPassword: "old-password", NewPassword: "short"})
+		requireCode(t, err, domain.ErrCodeValidation)
+	})
+
+	t.Run("requires the old password", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "wrong-password").Return(false)
+
+		err := service.ChangePassword(ctx, 7, &domain.ChangePasswordRequest{OldPassword: "wrong-password", NewPassword: "new-password"})
+		assert.Equal(t, domain.ErrInvalidPassword, err)
+	})
+
+	t.Run("rejects reusing the old password", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "old-password").Return(true)
+
+		err := service.ChangePassword(ctx, 7, &domain.ChangePasswordRequest{OldPassword: "old-password", NewPassword: "old-password"})
+		requireCode(t, err, domain.ErrCodeValidation)
+	})
+
+	t.Run("stores the new hash and revokes refresh tokens", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "old-password").Return(true)
+		m.hasher.On("Verify", "hashed", "new-password").Return(false)
+		m.hasher.On("Hash", "new-password").Return("new-hash", nil)
+		m.users.On("Update", ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.Password == "new-hash"
+		})).Return(nil)
+		m.auth.On("RevokeAllRefreshTokens", ctx, uint(7)).Return(nil)
+
+		err := service.ChangePassword(ctx, 7, &domain.ChangePasswordRequest{OldPassword: "old-password", NewPassword: "new-password"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("fails when the tokens cannot be revoked", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "old-password").Return(true)
+		m.hasher.On("Verify", "hashed", "new-password").Return(false)
+		m.hasher.On("Hash", "new-password").Return("new-hash", nil)
+		m.users.On("Update", ctx, mock.Anything).Return(nil)
+		m.auth.On("RevokeAllRefreshTokens", ctx, uint(7)).Return(errDatabase)
+
+		err := service.ChangePassword(ctx, 7, &domain.ChangePasswordRequest{OldPassword: "old-password", NewPassword: "new-password"})
+		assert.Equal(t, errDatabase, err)
+	})
+}
+
+func TestUserServiceResetPassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects short passwords", func(t *testing.T) {
+		service, _ := newMockedUserService(t)
+
+		requireCode(t, service.ResetPassword(ctx, 7, "short"), domain.ErrCodeValidation)
+	})
+
+	t.Run("stores the new hash without the old password and revokes refresh tokens", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.hasher.On("Hash", "new-password").Return("new-hash", nil)
+		m.users.On("Update", ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.Password == "new-hash"
+		})).Return(nil)
+		m.auth.On("RevokeAllRefreshTokens", ctx, uint(7)).Return(nil)
+
+		assert.NoError(t, service.ResetPassword(ctx, 7, "new-password"))
+	})
+}
+
+func TestUserServiceCreateUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates users with the requested role when registration is closed", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.config.Registration.Mode = config.RegistrationModeClosed
+		m.permissions.On("RoleExists", ctx, domain.RoleAdmin).Return(true, nil)
+		m.users.On("GetByEmail", ctx, "root@example.com").Return(nil, domain.ErrUserNotFound)
+		m.hasher.On("Hash", "password123").Return("hashed", nil)
+		m.users.On("Create", ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.Email == "root@example.com" && user.Role == domain.RoleAdmin && user.Active
+		})).Return(nil)
+
+		user, err := service.CreateUser(ctx, &domain.UserCreateRequest{Email: "Root@Example.com", Password: "password123", Name: "Root", Role: domain.RoleAdmin})
+		require.NoError(t, err)
+		assert.Equal(t, domain.RoleAdmin, user.Role)
+	})
+
+	t.Run("rejects undefined roles", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.permissions.On("RoleExists", ctx, "owner").Return(false, nil)
+
+		_, err := service.CreateUser(ctx, &domain.UserCreateRequest{Email: "root@example.com", Password: "password123", Name: "Root", Role: "owner"})
+		requireCode(t, err, domain.ErrCodeValidation)
+		m.users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a taken email", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.permissions.On("RoleExists", ctx, domain.RoleUser).Return(true, nil)
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(storedUser(), nil)
+
+		_, err := service.CreateUser(ctx, &domain.UserCreateRequest{Email: "alice@example.com", Password: "password123", Name: "Alice"})
+		assert.Equal(t, domain.ErrUserExists, err)
+	})
+}
+
+func TestUserServiceDeleteAccount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("requires the password", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "wrong-password").Return(false)
+
+		_, err := service.DeleteAccount(ctx, 7, &domain.DeleteAccountRequest{Password: "wrong-password"})
+		assert.Equal(t, domain.ErrInvalidPassword, err)
+	})
+
+	t.Run("schedules the deletion and signs out every session", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "password123").Return(true)
+		m.users.On("Update", ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.DeletionScheduledAt != nil
+		})).Return(nil)
+		m.auth.On("RevokeAllRefreshTokens", ctx, uint(7)).Return(nil)
+
+		user, err := service.DeleteAccount(ctx, 7, &domain.DeleteAccountRequest{Password: "password123"})
+		require.NoError(t, err)
+		require.NotNil(t, user.DeletionScheduledAt)
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), *user.DeletionScheduledAt, time.Minute)
+	})
+
+	t.Run("keeps the original schedule when deleting again", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		scheduledAt := time.Now().Add(time.Hour)
+		user := storedUser()
+		user.DeletionScheduledAt = &scheduledAt
+		m.users.On("GetByID", ctx, uint(7)).Return(user, nil)
+		m.hasher.On("Verify", "hashed", "password123").Return(true)
+
+		response, err := service.DeleteAccount(ctx, 7, &domain.DeleteAccountRequest{Password: "password123"})
+		require.NoError(t, err)
+		assert.Equal(t, &scheduledAt, response.DeletionScheduledAt)
+		m.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("logging in restores the account", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		scheduledAt := time.Now().Add(time.Hour)
+		user := storedUser()
+		user.DeletionScheduledAt = &scheduledAt
+		m.users.On("GetByEmail", ctx, "alice@example.com").Return(user, nil)
+		m.hasher.On("Verify", "hashed", "password123").Return(true)
+		m.hasher.On("NeedsRehash", "hashed").Return(false)
+		m.users.On("Update", ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.DeletionScheduledAt == nil
+		})).Return(nil)
+		m.auth.On("IssueTokenPair", ctx, mock.Anything).Return(&domain.TokenPair{}, nil)
+
+		_, response, err := service.Login(ctx, &domain.UserLoginRequest{Email: "alice@example.com", Password: "password123"})
+		require.NoError(t, err)
+		assert.Nil(t, response.DeletionScheduledAt)
+	})
+
+	t.Run("purges the accounts that are due", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("List", mock.Anything, mock.AnythingOfType("*domain.Query"), domain.ListOptions{IncludeInactive: true}, 0, purgeBatchSize).
+			Return([]*domain.User{storedUser()}, int64(1), nil)
+		m.users.On("Delete", ctx, uint(7)).Return(nil)
+
+		purged, err := service.PurgeDeletedAccounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+	})
+}
+
+func TestUserServiceEmailChange(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("mails a confirmation link to the new address", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "password123").Return(true)
+		m.users.On("GetByEmail", ctx, "new@example.com").Return(nil, domain.ErrUserNotFound)
+		m.users.On("Update", ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.Email == "alice@example.com" && user.PendingEmail == "new@example.com"
+		})).Return(nil)
+		m.auth.On("GenerateEmailChangeToken", mock.AnythingOfType("*domain.User")).Return("change-token", nil)
+		m.mailer.On("Send", ctx, mock.MatchedBy(func(msg *mailer.Message) bool {
+			return len(msg.To) == 1 && msg.To[0] == "new@example.com"
+		})).Return(nil)
+
+		user, err := service.RequestEmailChange(ctx, 7, &domain.EmailChangeRequest{Email: " New@Example.com", Password: "password123"})
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", user.PendingEmail)
+	})
+
+	t.Run("rejects a taken address", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.hasher.On("Verify", "hashed", "password123").Return(true)
+		m.users.On("GetByEmail", ctx, "bob@example.com").Return(&domain.User{ID: 8}, nil)
+
+		_, err := service.RequestEmailChange(ctx, 7, &domain.EmailChangeRequest{Email: "bob@example.com", Password: "password123"})
+		assert.Equal(t, domain.ErrUserExists, err)
+	})
+
+	t.Run("only confirms the address that is pending", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		user := storedUser()
+		user.PendingEmail = "newer@example.com"
+		m.auth.On("ValidateEmailChangeToken", "stale-token").
+			Return(&domain.EmailChangeClaims{UserID: 7, Email: "new@example.com"}, nil)
+		m.users.On("GetByID", ctx, uint(7)).Return(user, nil)
+
+		_, err := service.ConfirmEmailChange(ctx, "stale-token")
+		assert.Equal(t, domain.ErrInvalidToken, err)
+	})
+
+	t.Run("treats tokens of deleted users as invalid", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.auth.On("ValidateEmailChangeToken", "token").
+			Return(&domain.EmailChangeClaims{UserID: 7, Email: "new@example.com"}, nil)
+		m.users.On("GetByID", ctx, uint(7)).Return(nil, domain.ErrUserNotFound)
+
+		_, err := service.ConfirmEmailChange(ctx, "token")
+		assert.Equal(t, domain.ErrInvalidToken, err)
+	})
+
+	t.Run("swaps in the pending address", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		user := storedUser()
+		user.PendingEmail = "new@example.com"
+		m.auth.On("ValidateEmailChangeToken", "token").
+			Return(&domain.EmailChangeClaims{UserID: 7, Email: "new@example.com"}, nil)
+		m.users.On("GetByID", ctx, uint(7)).Return(user, nil)
+		m.users.On("GetByEmail", ctx, "new@example.com").Return(nil, domain.ErrUserNotFound)
+		m.users.On("Update", ctx, mock.MatchedBy(func(user *domain.User) bool {
+			return user.Email == "new@example.com" && user.PendingEmail == ""
+		})).Return(nil)
+
+		response, err := service.ConfirmEmailChange(ctx, "token")
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", response.Email)
+	})
+}
+
+func TestUserServiceUpdateUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects undefined roles", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		role := "owner"
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.permissions.On("RoleExists", ctx, "owner").Return(false, nil)
+
+		_, err := service.UpdateUser(ctx, 7, &domain.UserUpdateRequest{Role: &role})
+		requireCode(t, err, domain.ErrCodeValidation)
+		m.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects defined roles missing from the catalog", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.config.Roles.Catalog = []string{domain.RoleAdmin, domain.RoleUser}
+		role := domain.RoleModerator
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+		m.permissions.On("RoleExists", ctx, domain.RoleModerator).Return(true, nil)
+
+		_, err := service.UpdateUser(ctx, 7, &domain.UserUpdateRequest{Role: &role})
+		requireCode(t, err, domain.ErrCodeValidation)
+		m.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("maps a missing user to not found", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByID", ctx, uint(9)).Return(nil, domain.ErrUserNotFound)
+
+		_, err := service.UpdateUser(ctx, 9, &domain.UserUpdateRequest{})
+		assert.Equal(t, domain.ErrUserNotFound, err)
+	})
+}
+
+func TestUserServiceUpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	ptr := func(s string) *string { return &s }
+
+	t.Run("applies only the fields that are set", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		user := storedUser()
+		user.Phone = "+8613800000000"
+		user.Locale = "zh-CN"
+		user.Metadata = map[string]interface{}{"theme": "dark", "beta": true}
+		m.users.On("GetByID", ctx, uint(7)).Return(user, nil)
+		m.users.On("Update", ctx, user).Return(nil)
+
+		response, err := service.UpdateProfile(ctx, 7, &domain.UserUpdateRequest{
+			Timezone: ptr("Asia/Shanghai"),
+			Locale:   ptr(""),
+			Metadata: map[string]interface{}{"beta": nil, "newsletter": false},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", response.Name)
+		assert.Equal(t, "+8613800000000", response.Phone)
+		assert.Empty(t, response.Locale)
+		assert.Equal(t, "Asia/Shanghai", response.Timezone)
+		assert.Equal(t, map[string]interface{}{"theme": "dark", "newsletter": false}, response.Metadata)
+	})
+
+	t.Run("validates profile fields", func(t *testing.T) {
+		for name, req := range map[string]*domain.UserUpdateRequest{
+			"phone":      {Phone: ptr("555-0100")},
+			"locale":     {Locale: ptr("not a locale")},
+			"timezone":   {Timezone: ptr("Mars/Olympus")},
+			"avatar_url": {AvatarURL: ptr("ftp://example.com/a.png")},
+			"metadata":   {Metadata: map[string]interface{}{"blob": strings.Repeat("x", domain.MaxUserMetadataSize)}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				service, m := newMockedUserService(t)
+				m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+
+				_, err := service.UpdateProfile(ctx, 7, req)
+				requireCode(t, err, domain.ErrCodeValidation)
+				m.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
+
+func TestUserServicePatchProfile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("merge patches set, clear and nest fields", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		user := storedUser()
+		user.Locale = "zh-CN"
+		user.Metadata = map[string]interface{}{"theme": "dark", "beta": true, "ui": map[string]interface{}{"compact": true, "lang": "en"}}
+		m.users.On("GetByID", ctx, uint(7)).Return(user, nil)
+		m.users.On("Update", ctx, user).Return(nil)
+
+		response, err := service.PatchProfile(ctx, 7, &domain.Patch{
+			Type:     domain.PatchTypeMerge,
+			Document: []byte(`{"timezone":"Asia/Shanghai","locale":null,"metadata":{"beta":null,"ui":{"lang":"fr"}}}`),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", response.Name)
+		assert.Empty(t, response.Locale)
+		assert.Equal(t, "Asia/Shanghai", response.Timezone)
+		assert.Equal(t, map[string]interface{}{"theme": "dark", "ui": map[string]interface{}{"compact": true, "lang": "fr"}}, response.Metadata)
+	})
+
+	t.Run("applies JSON Patch operations", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		user := storedUser()
+		m.users.On("GetByID", ctx, uint(7)).Return(user, nil)
+		m.users.On("Update", ctx, user).Return(nil)
+
+		response, err := service.PatchProfile(ctx, 7, &domain.Patch{
+			Type:     domain.PatchTypeJSON,
+			Document: []byte(`[{"op":"test","path":"/name","value":"Alice"},{"op":"replace","path":"/name","value":"Alicia"},{"op":"add","path":"/metadata/theme","value":"light"}]`),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Alicia", response.Name)
+		assert.Equal(t, map[string]interface{}{"theme": "light"}, response.Metadata)
+	})
+
+	t.Run("rejects invalid patches before saving", func(t *testing.T) {
+		for name, tt := range map[string]struct {
+			patch *domain.Patch
+			code  string
+		}{
+			"malformed":          {&domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`{"name":`)}, domain.ErrCodeValidation},
+			"admin field":        {&domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`{"role":"admin"}`)}, domain.ErrCodeValidation},
+			"unknown field":      {&domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`{"email":"eve@example.com"}`)}, domain.ErrCodeValidation},
+			"removed name":       {&domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`{"name":null}`)}, domain.ErrCodeValidation},
+			"wrong type":         {&domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`{"phone":12}`)}, domain.ErrCodeValidation},
+			"not an object":      {&domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`"Alice"`)}, domain.ErrCodeValidation},
+			"invalid phone":      {&domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`{"phone":"555-0100"}`)}, domain.ErrCodeValidation},
+			"failed test":        {&domain.Patch{Type: domain.PatchTypeJSON, Document: []byte(`[{"op":"test","path":"/name","value":"Bob"}]`)}, domain.ErrCodeInvalid},
+			"unknown patch type": {&domain.Patch{Type: "xml", Document: []byte(`{}`)}, domain.ErrCodeInvalid},
+		} {
+			t.Run(name, func(t *testing.T) {
+				service, m := newMockedUserService(t)
+				m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+
+				_, err := service.PatchProfile(ctx, 7, tt.patch)
+				requireCode(t, err, tt.code)
+				m.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
+
+func TestUserServicePatchUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("changes role and active like UpdateUser", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		user := storedUser()
+		m.users.On("GetByID", ctx, uint(7)).Return(user, nil)
+		m.users.On("Update", ctx, user).Return(nil)
+		m.permissions.On("RoleExists", ctx, domain.RoleModerator).Return(true, nil)
+
+		response, err := service.PatchUser(ctx, 7, &domain.Patch{
+			Type:     domain.PatchTypeMerge,
+			Document: []byte(`{"role":"moderator","active":false}`),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, domain.RoleModerator, response.Role)
+		assert.False(t, response.Active)
+	})
+
+	t.Run("does not check unchanged roles", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		user := storedUser()
+		m.users.On("GetByID", ctx, uint(7)).Return(user, nil)
+		m.users.On("Update", ctx, user).Return(nil)
+
+		_, err := service.PatchUser(ctx, 7, &domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`{"role":"user","name":"Alicia"}`)})
+		require.NoError(t, err)
+		m.permissions.AssertNotCalled(t, "RoleExists", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects removing role or active", func(t *testing.T) {
+		for _, field := range []string{"role", "active"} {
+			service, m := newMockedUserService(t)
+			m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+
+			_, err := service.PatchUser(ctx, 7, &domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`{"` + field + `":null}`)})
+			requireCode(t, err, domain.ErrCodeValidation)
+		}
+	})
+
+	t.Run("maps a missing user to not found", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.users.On("GetByID", ctx, uint(9)).Return(nil, domain.ErrUserNotFound)
+
+		_, err := service.PatchUser(ctx, 9, &domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`{}`)})
+		assert.Equal(t, domain.ErrUserNotFound, err)
+	})
+}
+
+func TestUserServiceSearchUsers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("searches the repository without an index", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		m.search.On("Enabled").Return(false)
+		m.users.On("Search", ctx, "alice", 0, 10).Return([]*domain.User{storedUser()}, int64(1), nil)
+
+		users, total, err := service.SearchUsers(ctx, "alice", 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, users, 1)
+		assert.Equal(t, "alice@example.com", users[0].Email)
+	})
+
+	t.Run("loads indexed users in ranking order", func(t *testing.T) {
+		service, m := newMockedUserService(t)
+		second := storedUser()
+		second.ID = 9
+		m.search.On("Enabled").Return(true)
+		m.search.On("SearchUserIDs", ctx, "alice", 0, 10).Return([]uint{9, 8, 7}, int64(3), nil)
+		m.users.On("GetByID", ctx, uint(9)).Return(second, nil)
+		m.users.On("GetByID", ctx, uint(8)).Return(nil, domain.ErrUserNotFound)
+		m.users.On("GetByID", ctx, uint(7)).Return(storedUser(), nil)
+
+		users, total, err := service.SearchUsers(ctx, "alice", 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, users, 2)
+		assert.Equal(t, uint(9), users[0].ID)
+		assert.Equal(t, uint(7), users[1].ID)
+		m.users.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
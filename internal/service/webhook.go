@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/utils"
+	"github.com/luxixing/fx-gin-scaffold/pkg/webhook"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// webhookDeliveryBatchSize is the number of due deliveries loaded at a time
+	webhookDeliveryBatchSize = 50
+
+	// maxWebhookRetryBackoff caps the delay between delivery attempts
+	maxWebhookRetryBackoff = 24 * time.Hour
+)
+
+// WebhookServiceParams holds dependencies for WebhookService
+type WebhookServiceParams struct {
+	fx.In
+	Config        *config.Config
+	WebhookRepo   domain.WebhookRepository
+	DeliveryRepo  domain.WebhookDeliveryRepository
+	WebhookClient *webhook.Client
+	Validator     domain.Validator
+	TxManager     domain.TxManager
+}
+
+// webhookService implements domain.WebhookService
+type webhookService struct {
+	config       *config.Config
+	webhookRepo  domain.WebhookRepository
+	deliveryRepo domain.WebhookDeliveryRepository
+	client       *webhook.Client
+	validator    domain.Validator
+	txManager    domain.TxManager
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(p WebhookServiceParams) domain.WebhookService {
+	return &webhookService{
+		config:       p.Config,
+		webhookRepo:  p.WebhookRepo,
+		deliveryRepo: p.DeliveryRepo,
+		client:       p.WebhookClient,
+		validator:    p.Validator,
+		txManager:    p.TxManager,
+		now:          time.Now,
+	}
+}
+
+// CreateWebhook registers a webhook, generating a secret when none is given
+func (s *webhookService) CreateWebhook(ctx context.Context, req *domain.WebhookCreateRequest) (*domain.WebhookResponse, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookEvents(req.Events); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = utils.GenerateRandomString(32); err != nil {
+			return nil, domain.WrapError(err, domain.ErrCodeInternal, "Failed to generate webhook secret")
+		}
+	}
+
+	hook := &domain.Webhook{
+		URL:    req.URL,
+		Secret: secret,
+		Events: req.Events,
+		Active: req.Active == nil || *req.Active,
+	}
+	if err := s.webhookRepo.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+
+	response := hook.ToResponse()
+	response.Secret = hook.Secret
+	return response, nil
+}
+
+// GetWebhook retrieves a webhook by ID
+func (s *webhookService) GetWebhook(ctx context.Context, id uint) (*domain.WebhookResponse, error) {
+	hook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return hook.ToResponse(), nil
+}
+
+// UpdateWebhook applies a partial update to a webhook
+func (s *webhookService) UpdateWebhook(ctx context.Context, id uint, req *domain.WebhookUpdateRequest) (*domain.WebhookResponse, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, err
+	}
+
+	hook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		hook.URL = *req.URL
+	}
+	if req.Secret != nil {
+		hook.Secret = *req.Secret
+	}
+	if req.Events != nil {
+		if err := checkWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+		hook.Events = req.Events
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+
+	if err := s.webhookRepo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook.ToResponse(), nil
+}
+
+// DeleteWebhook deletes a webhook and its deliveries
+func (s *webhookService) DeleteWebhook(ctx context.Context, id uint) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.deliveryRepo.DeleteByWebhook(ctx, id); err != nil {
+			return err
+		}
+		return s.webhookRepo.Delete(ctx, id)
+	})
+}
+
+// ListWebhooks retrieves webhooks with pagination
+func (s *webhookService) ListWebhooks(ctx context.Context, offset, limit int) ([]*domain.WebhookResponse, int64, error) {
+	hooks, total, err := s.webhookRepo.List(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*domain.WebhookResponse, len(hooks))
+	for i, hook := range hooks {
+		responses[i] = hook.ToResponse()
+	}
+	return responses, total, nil
+}
+
+// ListDeliveries retrieves the delivery log of a webhook with pagination
+func (s *webhookService) ListDeliveries(ctx context.Context, webhookID uint, offset, limit int) ([]*domain.WebhookDelivery, int64, error) {
+	if _, err := s.webhookRepo.GetByID(ctx, webhookID); err != nil {
+		return nil, 0, err
+	}
+	return s.deliveryRepo.ListByWebhook(ctx, webhookID, offset, limit)
+}
+
+// RedeliverDelivery queues a new delivery of the payload of an earlier one,
+// keeping the original in the log
+func (s *webhookService) RedeliverDelivery(ctx context.Context, webhookID, deliveryID uint) (*domain.WebhookDelivery, error) {
+	original, err := s.deliveryRepo.GetByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.WebhookID != webhookID {
+		return nil, domain.ErrWebhookDeliveryNotFound
+	}
+
+	now := s.now()
+	delivery := &domain.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        domain.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+	}
+	if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Emit queues an event for every active webhook subscribed to it
+func (s *webhookService) Emit(ctx context.Context, event string, data interface{}) error {
+	if !s.config.Webhooks.Enabled {
+		return nil
+	}
+
+	hooks, err := s.webhookRepo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	var subscribed []*domain.Webhook
+	for _, hook := range hooks {
+		if hook.Subscribes(event) {
+			subscribed = append(subscribed, hook)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	now := s.now()
+	payload, err := json.Marshal(&domain.WebhookPayload{
+		Event:     event,
+		Timestamp: now,
+		Data:      data,
+	})
+	if err != nil {
+		return domain.WrapError(err, domain.ErrCodeInternal, "Failed to encode webhook payload")
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, hook := range subscribed {
+			delivery := &domain.WebhookDelivery{
+				WebhookID:     hook.ID,
+				Event:         event,
+				Payload:       string(payload),
+				Status:        domain.WebhookDeliveryPending,
+				NextAttemptAt: &now,
+			}
+			if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeliverDue sends the deliveries whose next attempt is due
+func (s *webhookService) DeliverDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for ctx.Err() == nil {
+		deliveries, err := s.deliveryRepo.ListDue(ctx, s.now(), webhookDeliveryBatchSize)
+		if err != nil {
+			return attempted, err
+		}
+
+		hooks := make(map[uint]*domain.Webhook)
+		for _, delivery := range deliveries {
+			if ctx.Err() != nil {
+				break
+			}
+			if err := s.deliver(ctx, delivery, hooks); err != nil {
+				return attempted, err
+			}
+			attempted++
+		}
+
+		if len(deliveries) < webhookDeliveryBatchSize {
+			break
+		}
+	}
+	return attempted, nil
+}
+
+// deliver makes one attempt to send a delivery and records the outcome.
+// hooks caches the webhooks loaded for the current batch.
+func (s *webhookService) deliver(ctx context.Context, delivery *domain.WebhookDelivery, hooks map[uint]*domain.Webhook) error {
+	hook, ok := hooks[delivery.WebhookID]
+	if !ok {
+		var err error
+		if hook, err = s.webhookRepo.GetByID(ctx, delivery.WebhookID); err != nil && err != domain.ErrWebhookNotFound {
+			return err
+		}
+		hooks[delivery.WebhookID] = hook
+	}
+
+	switch {
+	case hook == nil:
+		s.finishDelivery(delivery, domain.WebhookDeliveryFailed, "webhook was deleted")
+	case !hook.Active:
+		s.finishDelivery(delivery, domain.WebhookDeliveryFailed, "webhook is inactive")
+	default:
+		status, err := s.client.Send(ctx, &webhook.Request{
+			URL:        hook.URL,
+			Secret:     hook.Secret,
+			Event:      delivery.Event,
+			DeliveryID: strconv.FormatUint(uint64(delivery.ID), 10),
+			Body:       []byte(delivery.Payload),
+		})
+		if err != nil && ctx.Err() != nil {
+			// Shutting down; the attempt is retried on the next run
+			return nil
+		}
+
+		delivery.Attempts++
+		delivery.ResponseStatus = status
+		switch {
+		case err == nil:
+			s.finishDelivery(delivery, domain.WebhookDeliverySucceeded, "")
+		case delivery.Attempts >= s.config.Webhooks.MaxAttempts:
+			s.finishDelivery(delivery, domain.WebhookDeliveryFailed, err.Error())
+		default:
+			next := s.now().Add(webhookRetryBackoff(s.config.Webhooks.RetryBackoff, delivery.Attempts))
+			delivery.NextAttemptAt = &next
+			delivery.Error = err.Error()
+		}
+
+		if err != nil {
+			logger.FromContext(ctx).Warn("webhook delivery failed",
+				zap.Uint("webhook_id", hook.ID),
+				zap.Uint("delivery_id", delivery.ID),
+				zap.Int("attempts", delivery.Attempts),
+				zap.Error(err),
+			)
+		}
+	}
+
+	// The delivery is gone if its webhook was deleted meanwhile
+	if err := s.deliveryRepo.Update(ctx, delivery); err != nil && err != domain.ErrWebhookDeliveryNotFound {
+		return err
+	}
+	return nil
+}
+
+// finishDelivery marks a delivery as done; no further attempts are made
+func (s *webhookService) finishDelivery(delivery *domain.WebhookDelivery, status, reason string) {
+	delivery.Status = status
+	delivery.Error = reason
+	delivery.NextAttemptAt = nil
+	if status == domain.WebhookDeliverySucceeded {
+		now := s.now()
+		delivery.DeliveredAt = &now
+	}
+}
+
+// webhookRetryBackoff returns the delay before the attempt following the
+// given number of failed ones: base, then doubling up to a day
+func webhookRetryBackoff(base time.Duration, attempts int) time.Duration {
+	backoff := base
+	for i := 1; i < attempts && backoff < maxWebhookRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxWebhookRetryBackoff {
+		backoff = maxWebhookRetryBackoff
+	}
+	return backoff
+}
+
+// checkWebhookEvents validates subscribed event types. Requests remove
+// duplicates when they are normalized.
+func checkWebhookEvents(events []string) error {
+	known := map[string]bool{domain.WebhookEventAll: true}
+	for _, event := range domain.WebhookEvents {
+		known[event] = true
+	}
+
+	for _, event := range events {
+		if !known[event] {
+			return domain.ValidationError("events", "unknown event type: "+event)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/internal/repo"
+	"github.com/luxixing/fx-gin-scaffold/internal/validation"
+	"github.com/luxixing/fx-gin-scaffold/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// webhookReceiver records verified payloads and fails while failing is set
+type webhookReceiver struct {
+	mu       sync.Mutex
+	secret   string
+	failing  bool
+	payloads []domain.WebhookPayload
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	err := webhook.Verify(r.secret, req.Header.Get(webhook.HeaderTimestamp), req.Header.Get(webhook.HeaderSignature), body, time.Minute)
+	if err != nil || r.failing {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var payload domain.WebhookPayload
+	_ = json.Unmarshal(body, &payload)
+	r.payloads = append(r.payloads, payload)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *webhookReceiver) setFailing(failing bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failing = failing
+}
+
+func newTestWebhookService(t *testing.T) *webhookService {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // every connection to :memory: is a new database
+	require.NoError(t, db.AutoMigrate(&domain.Webhook{}, &domain.WebhookDelivery{}))
+
+	cfg := &config.Config{}
+	cfg.Webhooks.Enabled = true
+	cfg.Webhooks.Timeout = 5 * time.Second
+	cfg.Webhooks.MaxAttempts = 2
+	cfg.Webhooks.RetryBackoff = time.Minute
+
+	return NewWebhookService(WebhookServiceParams{
+		Config:        cfg,
+		WebhookRepo:   repo.NewWebhookGormRepository(db),
+		DeliveryRepo:  repo.NewWebhookDeliveryGormRepository(db),
+		WebhookClient: webhook.NewClient(&http.Client{Timeout: cfg.Webhooks.Timeout}),
+		Validator:     validation.New(),
+		TxManager:     repo.NewGormTxManager(db),
+	}).(*webhookService)
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	ctx := context.Background()
+	service := newTestWebhookService(t)
+
+	receiver := &webhookReceiver{secret: "0123456789abcdef0123"}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	_, err := service.CreateWebhook(ctx, &domain.WebhookCreateRequest{URL: server.URL, Events: []string{"user.renamed"}})
+	assert.Error(t, err)
+
+	hook, err := service.CreateWebhook(ctx, &domain.WebhookCreateRequest{
+		URL:    server.URL,
+		Secret: receiver.secret,
+		Events: []string{domain.WebhookEventUserCreated, domain.WebhookEventUserCreated},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain.WebhookEventUserCreated}, hook.Events)
+	assert.Equal(t, receiver.secret, hook.Secret)
+
+	// Only subscribed events are queued, and sent signed
+	require.NoError(t, service.Emit(ctx, domain.WebhookEventUserDeleted, map[string]uint{"id": 1}))
+	require.NoError(t, service.Emit(ctx, domain.WebhookEventUserCreated, map[string]uint{"id": 1}))
+	attempted, err := service.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+	require.Len(t, receiver.payloads, 1)
+	assert.Equal(t, domain.WebhookEventUserCreated, receiver.payloads[0].Event)
+
+	// Failed attempts are retried after the backoff until MaxAttempts
+	receiver.setFailing(true)
+	require.NoError(t, service.Emit(ctx, domain.WebhookEventUserCreated, map[string]uint{"id": 2}))
+	_, err = service.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	deliveries, total, err := service.ListDeliveries(ctx, hook.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	retried := deliveries[0]
+	assert.Equal(t, domain.WebhookDeliveryPending, retried.Status)
+	assert.Equal(t, 1, retried.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, retried.ResponseStatus)
+
+	attempted, err = service.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, attempted, "retry is not due before the backoff")
+
+	service.now = func() time.Time { return time.Now().Add(time.Minute) }
+	_, err = service.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	deliveries, _, err = service.ListDeliveries(ctx, hook.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryFailed, deliveries[0].Status)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Nil(t, deliveries[0].NextAttemptAt)
+
+	// Redelivery sends the payload again as a new delivery
+	receiver.setFailing(false)
+	_, err = service.RedeliverDelivery(ctx, hook.ID, deliveries[0].ID)
+	require.NoError(t, err)
+	_, err = service.DeliverDue(ctx)
+	require.NoError(t, err)
+	require.Len(t, receiver.payloads, 2)
+	assert.Equal(t, map[string]interface{}{"id": float64(2)}, receiver.payloads[1].Data)
+
+	require.NoError(t, service.DeleteWebhook(ctx, hook.ID))
+	_, _, err = service.ListDeliveries(ctx, hook.ID, 0, 10)
+	assert.Equal(t, domain.ErrWebhookNotFound, err)
+}
+
+func TestWebhookRetryBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookRetryBackoff(30*time.Second, 1))
+	assert.Equal(t, 4*time.Minute, webhookRetryBackoff(30*time.Second, 4))
+	assert.Equal(t, maxWebhookRetryBackoff, webhookRetryBackoff(30*time.Second, 100))
+}
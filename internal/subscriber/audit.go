@@ -0,0 +1,55 @@
+package subscriber
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/events"
+	"go.uber.org/fx"
+)
+
+// AuditSubscriberParams holds dependencies for AuditSubscriber
+type AuditSubscriberParams struct {
+	fx.In
+	AuditService domain.AuditService
+}
+
+// AuditSubscriber records logins and account deletions in the audit log
+type AuditSubscriber struct {
+	auditService domain.AuditService
+}
+
+// NewAuditSubscriber creates the audit log subscriber
+func NewAuditSubscriber(p AuditSubscriberParams) *AuditSubscriber {
+	return &AuditSubscriber{
+		auditService: p.AuditService,
+	}
+}
+
+// Subscriptions returns the events recorded in the audit log
+func (s *AuditSubscriber) Subscriptions() []events.Subscription {
+	return []events.Subscription{
+		events.On(s.loginSucceeded),
+		events.On(s.userDeleted),
+	}
+}
+
+// loginSucceeded records a login by the user
+func (s *AuditSubscriber) loginSucceeded(ctx context.Context, e domain.LoginSucceeded) error {
+	return s.auditService.Record(ctx, &domain.AuditLog{
+		ActorID:    e.User.ID,
+		Action:     domain.AuditActionLogin,
+		TargetType: "user",
+		TargetID:   e.User.ID,
+	})
+}
+
+// userDeleted records the deleted account; the actor comes from the context
+func (s *AuditSubscriber) userDeleted(ctx context.Context, e domain.UserDeleted) error {
+	return s.auditService.Record(ctx, &domain.AuditLog{
+		Action:     domain.AuditActionUserDelete,
+		TargetType: "user",
+		TargetID:   e.User.ID,
+		Before:     domain.AuditSnapshot(e.User),
+	})
+}
@@ -0,0 +1,57 @@
+package subscriber
+
+import (
+	"context"
+	"strings"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/events"
+	"github.com/luxixing/fx-gin-scaffold/pkg/mailer"
+	"go.uber.org/fx"
+)
+
+// EmailSubscriberParams holds dependencies for EmailSubscriber
+type EmailSubscriberParams struct {
+	fx.In
+	Config       *config.Config
+	Mailer       mailer.Mailer
+	MailRenderer *mailer.Renderer
+}
+
+// EmailSubscriber sends transactional emails triggered by domain events
+type EmailSubscriber struct {
+	config       *config.Config
+	mailer       mailer.Mailer
+	mailRenderer *mailer.Renderer
+}
+
+// NewEmailSubscriber creates the email subscriber
+func NewEmailSubscriber(p EmailSubscriberParams) *EmailSubscriber {
+	return &EmailSubscriber{
+		config:       p.Config,
+		mailer:       p.Mailer,
+		mailRenderer: p.MailRenderer,
+	}
+}
+
+// Subscriptions returns the events that send email. Sending runs in the
+// background so slow mail servers don't delay responses.
+func (s *EmailSubscriber) Subscriptions() []events.Subscription {
+	return []events.Subscription{
+		events.OnAsync(s.userRegistered),
+	}
+}
+
+// userRegistered welcomes a new user
+func (s *EmailSubscriber) userRegistered(ctx context.Context, e domain.UserRegistered) error {
+	msg, err := s.mailRenderer.Message("welcome", map[string]interface{}{
+		"Name":  e.User.Name,
+		"Email": e.User.Email,
+		"Link":  strings.TrimRight(s.config.App.URL, "/"),
+	}, "Welcome", e.User.Email)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, msg)
+}
@@ -0,0 +1,51 @@
+package subscriber
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/events"
+	"go.uber.org/fx"
+)
+
+// NotificationSubscriberParams holds dependencies for NotificationSubscriber
+type NotificationSubscriberParams struct {
+	fx.In
+	Config              *config.Config
+	NotificationService domain.NotificationService
+}
+
+// NotificationSubscriber stores in-app notifications for domain events
+type NotificationSubscriber struct {
+	config              *config.Config
+	notificationService domain.NotificationService
+}
+
+// NewNotificationSubscriber creates the notification subscriber
+func NewNotificationSubscriber(p NotificationSubscriberParams) *NotificationSubscriber {
+	return &NotificationSubscriber{
+		config:              p.Config,
+		notificationService: p.NotificationService,
+	}
+}
+
+// Subscriptions returns the events that notify users
+func (s *NotificationSubscriber) Subscriptions() []events.Subscription {
+	return []events.Subscription{
+		events.On(s.userRegistered),
+	}
+}
+
+// userRegistered greets a new user in their inbox
+func (s *NotificationSubscriber) userRegistered(ctx context.Context, e domain.UserRegistered) error {
+	if !s.config.Notifications.Welcome {
+		return nil
+	}
+	return s.notificationService.Notify(ctx, &domain.Notification{
+		UserID: e.User.ID,
+		Type:   domain.NotificationTypeWelcome,
+		Title:  "Welcome, " + e.User.Name,
+		Body:   "Your account is ready.",
+	})
+}
@@ -0,0 +1,47 @@
+package subscriber
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/events"
+	"go.uber.org/fx"
+)
+
+// SearchSubscriberParams holds dependencies for SearchSubscriber
+type SearchSubscriberParams struct {
+	fx.In
+	SearchService domain.SearchService
+}
+
+// SearchSubscriber keeps the search index in sync with user changes
+type SearchSubscriber struct {
+	searchService domain.SearchService
+}
+
+// NewSearchSubscriber creates the search index subscriber
+func NewSearchSubscriber(p SearchSubscriberParams) *SearchSubscriber {
+	return &SearchSubscriber{
+		searchService: p.SearchService,
+	}
+}
+
+// Subscriptions returns the events applied to the search index. Indexing
+// runs in the background so a slow index never delays requests.
+func (s *SearchSubscriber) Subscriptions() []events.Subscription {
+	if !s.searchService.Enabled() {
+		return nil
+	}
+
+	return []events.Subscription{
+		events.OnAsync(func(ctx context.Context, e domain.UserRegistered) error {
+			return s.searchService.IndexUser(ctx, e.User)
+		}),
+		events.OnAsync(func(ctx context.Context, e domain.UserUpdated) error {
+			return s.searchService.IndexUser(ctx, e.User)
+		}),
+		events.OnAsync(func(ctx context.Context, e domain.UserDeleted) error {
+			return s.searchService.RemoveUser(ctx, e.User.ID)
+		}),
+	}
+}
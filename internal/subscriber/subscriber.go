@@ -0,0 +1,43 @@
+package subscriber
+
+import (
+	"github.com/luxixing/fx-gin-scaffold/pkg/events"
+	"go.uber.org/fx"
+)
+
+// GetModule returns the fx.Option for domain event subscribers
+func GetModule() fx.Option {
+	return fx.Options(
+		// Provide subscribers
+		fx.Provide(asSubscriber(NewAuditSubscriber)),
+		fx.Provide(asSubscriber(NewEmailSubscriber)),
+		fx.Provide(asSubscriber(NewWebhookSubscriber)),
+		fx.Provide(asSubscriber(NewSearchSubscriber)),
+		fx.Provide(asSubscriber(NewNotificationSubscriber)),
+
+		fx.Invoke(Register),
+	)
+}
+
+// asSubscriber annotates a subscriber constructor so it joins the event subscriber group
+func asSubscriber(constructor interface{}) interface{} {
+	return fx.Annotate(
+		constructor,
+		fx.As(new(events.Subscriber)),
+		fx.ResultTags(`group:"event_subscribers"`),
+	)
+}
+
+// RegisterParams holds dependencies for Register
+type RegisterParams struct {
+	fx.In
+	Bus         *events.Bus
+	Subscribers []events.Subscriber `group:"event_subscribers"`
+}
+
+// Register subscribes every subscriber to the event bus
+func Register(p RegisterParams) {
+	for _, s := range p.Subscribers {
+		p.Bus.Subscribe(s.Subscriptions()...)
+	}
+}
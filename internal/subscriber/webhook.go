@@ -0,0 +1,42 @@
+package subscriber
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/events"
+	"go.uber.org/fx"
+)
+
+// WebhookSubscriberParams holds dependencies for WebhookSubscriber
+type WebhookSubscriberParams struct {
+	fx.In
+	WebhookService domain.WebhookService
+}
+
+// WebhookSubscriber queues webhook deliveries for user events
+type WebhookSubscriber struct {
+	webhookService domain.WebhookService
+}
+
+// NewWebhookSubscriber creates the webhook subscriber
+func NewWebhookSubscriber(p WebhookSubscriberParams) *WebhookSubscriber {
+	return &WebhookSubscriber{
+		webhookService: p.WebhookService,
+	}
+}
+
+// Subscriptions returns the events forwarded to webhooks
+func (s *WebhookSubscriber) Subscriptions() []events.Subscription {
+	return []events.Subscription{
+		events.On(func(ctx context.Context, e domain.UserRegistered) error {
+			return s.webhookService.Emit(ctx, domain.WebhookEventUserCreated, e.User)
+		}),
+		events.On(func(ctx context.Context, e domain.UserUpdated) error {
+			return s.webhookService.Emit(ctx, domain.WebhookEventUserUpdated, e.User)
+		}),
+		events.On(func(ctx context.Context, e domain.UserDeleted) error {
+			return s.webhookService.Emit(ctx, domain.WebhookEventUserDeleted, e.User)
+		}),
+	}
+}
@@ -0,0 +1,51 @@
+package task
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// DeliverWebhooksParams holds dependencies for DeliverWebhooks
+type DeliverWebhooksParams struct {
+	fx.In
+	Config         *config.Config
+	WebhookService domain.WebhookService
+}
+
+// DeliverWebhooks sends queued webhook deliveries and retries failed ones
+type DeliverWebhooks struct {
+	config         *config.Config
+	webhookService domain.WebhookService
+}
+
+// NewDeliverWebhooks creates the webhook delivery task
+func NewDeliverWebhooks(p DeliverWebhooksParams) *DeliverWebhooks {
+	return &DeliverWebhooks{
+		config:         p.Config,
+		webhookService: p.WebhookService,
+	}
+}
+
+// Name returns the task name
+func (t *DeliverWebhooks) Name() string {
+	return "deliver_webhooks"
+}
+
+// Schedule polls for due deliveries every WEBHOOK_POLL_INTERVAL
+func (t *DeliverWebhooks) Schedule() string {
+	return "@every " + t.config.Webhooks.PollInterval.String()
+}
+
+// Run sends every delivery that is due
+func (t *DeliverWebhooks) Run(ctx context.Context) error {
+	attempted, err := t.webhookService.DeliverDue(ctx)
+	if attempted > 0 {
+		logger.Named(logger.ModuleJobs).Debug("delivered webhooks", zap.Int("count", attempted))
+	}
+	return err
+}
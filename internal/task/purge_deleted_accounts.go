@@ -0,0 +1,47 @@
+package task
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// PurgeDeletedAccountsParams holds dependencies for PurgeDeletedAccounts
+type PurgeDeletedAccountsParams struct {
+	fx.In
+	UserService domain.UserService
+}
+
+// PurgeDeletedAccounts deletes accounts whose deletion grace period has passed
+type PurgeDeletedAccounts struct {
+	userService domain.UserService
+}
+
+// NewPurgeDeletedAccounts creates the deleted account purge task
+func NewPurgeDeletedAccounts(p PurgeDeletedAccountsParams) *PurgeDeletedAccounts {
+	return &PurgeDeletedAccounts{
+		userService: p.UserService,
+	}
+}
+
+// Name returns the task name
+func (t *PurgeDeletedAccounts) Name() string {
+	return "purge_deleted_accounts"
+}
+
+// Schedule runs the task every hour
+func (t *PurgeDeletedAccounts) Schedule() string {
+	return "@hourly"
+}
+
+// Run deletes the accounts that are due
+func (t *PurgeDeletedAccounts) Run(ctx context.Context) error {
+	purged, err := t.userService.PurgeDeletedAccounts(ctx)
+	if purged > 0 {
+		logger.Named(logger.ModuleJobs).Info("purged deleted accounts", zap.Int64("count", purged))
+	}
+	return err
+}
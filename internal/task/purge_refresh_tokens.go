@@ -0,0 +1,53 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// PurgeRefreshTokensParams holds dependencies for PurgeRefreshTokens
+type PurgeRefreshTokensParams struct {
+	fx.In
+	RefreshTokenRepo domain.RefreshTokenRepository
+}
+
+// PurgeRefreshTokens deletes refresh tokens that can no longer be used
+type PurgeRefreshTokens struct {
+	refreshTokenRepo domain.RefreshTokenRepository
+}
+
+// NewPurgeRefreshTokens creates the expired refresh token purge task
+func NewPurgeRefreshTokens(p PurgeRefreshTokensParams) *PurgeRefreshTokens {
+	return &PurgeRefreshTokens{
+		refreshTokenRepo: p.RefreshTokenRepo,
+	}
+}
+
+// Name returns the task name
+func (t *PurgeRefreshTokens) Name() string {
+	return "purge_refresh_tokens"
+}
+
+// Schedule runs the task every hour
+func (t *PurgeRefreshTokens) Schedule() string {
+	return "@hourly"
+}
+
+// Run deletes expired refresh tokens. Revoked tokens are kept until they
+// expire so that reuse of a rotated token can still be detected
+func (t *PurgeRefreshTokens) Run(ctx context.Context) error {
+	deleted, err := t.refreshTokenRepo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		logger.Named(logger.ModuleJobs).Info("purged expired refresh tokens", zap.Int64("count", deleted))
+	}
+	return nil
+}
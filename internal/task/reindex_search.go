@@ -0,0 +1,58 @@
+package task
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// ReindexSearchParams holds dependencies for ReindexSearch
+type ReindexSearchParams struct {
+	fx.In
+	Config        *config.Config
+	SearchService domain.SearchService
+}
+
+// ReindexSearch rebuilds the search index from the database, repairing
+// changes whose indexing failed
+type ReindexSearch struct {
+	schedule      string
+	searchService domain.SearchService
+}
+
+// NewReindexSearch creates the search reindex task
+func NewReindexSearch(p ReindexSearchParams) *ReindexSearch {
+	return &ReindexSearch{
+		schedule:      p.Config.Search.ReindexSchedule,
+		searchService: p.SearchService,
+	}
+}
+
+// Name returns the task name
+func (t *ReindexSearch) Name() string {
+	return "reindex_search"
+}
+
+// Schedule runs the task on SEARCH_REINDEX_SCHEDULE
+func (t *ReindexSearch) Schedule() string {
+	return t.schedule
+}
+
+// Run reindexes every user; it does nothing when search is disabled
+func (t *ReindexSearch) Run(ctx context.Context) error {
+	if !t.searchService.Enabled() {
+		return nil
+	}
+
+	indexed, err := t.searchService.ReindexUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Named(logger.ModuleJobs).Info("reindexed search", zap.Int("users", indexed))
+	return nil
+}
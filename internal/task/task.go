@@ -0,0 +1,80 @@
+package task
+
+import (
+	"context"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/luxixing/fx-gin-scaffold/pkg/scheduler"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// GetModule returns the fx.Option for scheduled tasks
+func GetModule() fx.Option {
+	return fx.Options(
+		// Provide tasks
+		fx.Provide(asTask(NewPurgeRefreshTokens)),
+		fx.Provide(asTask(NewPurgeDeletedAccounts)),
+		fx.Provide(asTask(NewDeliverWebhooks)),
+		fx.Provide(asTask(NewReindexSearch)),
+
+		fx.Provide(NewScheduler),
+		fx.Invoke(func(*scheduler.Scheduler) {}),
+	)
+}
+
+// asTask annotates a task constructor so it joins the scheduled task group
+func asTask(constructor interface{}) interface{} {
+	return fx.Annotate(
+		constructor,
+		fx.As(new(scheduler.ScheduledTask)),
+		fx.ResultTags(`group:"scheduled_tasks"`),
+	)
+}
+
+// SchedulerParams holds dependencies for the scheduler
+type SchedulerParams struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Config    *config.Config
+	Tasks     []scheduler.ScheduledTask `group:"scheduled_tasks"`
+}
+
+// NewScheduler registers enabled tasks and ties the scheduler to the app lifecycle
+func NewScheduler(p SchedulerParams) (*scheduler.Scheduler, error) {
+	s := scheduler.New()
+
+	if !p.Config.Scheduler.Enabled {
+		logger.Named(logger.ModuleJobs).Info("scheduler disabled")
+		return s, nil
+	}
+
+	disabled := make(map[string]bool, len(p.Config.Scheduler.DisabledTasks))
+	for _, name := range p.Config.Scheduler.DisabledTasks {
+		disabled[name] = true
+	}
+
+	for _, t := range p.Tasks {
+		if disabled[t.Name()] {
+			logger.Named(logger.ModuleJobs).Info("scheduled task disabled", zap.String("task", t.Name()))
+			continue
+		}
+		if err := s.Register(t); err != nil {
+			return nil, err
+		}
+	}
+
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			s.Start()
+			logger.Named(logger.ModuleJobs).Info("scheduler started", zap.Strings("tasks", s.Tasks()))
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return s.Stop(ctx)
+		},
+	})
+
+	return s, nil
+}
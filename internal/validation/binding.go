@@ -0,0 +1,145 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// BindingError converts an error from binding a request to obj into a
+// validation *domain.Error with field messages in lang, so Go type names
+// never reach clients. JSON type mismatches are reported by their field
+// path; query values that don't parse are reported by their parameter,
+// found by looking the value up in values; failed rules are checked again
+// to describe them in lang. It returns nil for errors it doesn't recognize.
+func (v *Validator) BindingError(err error, obj any, values url.Values, lang string) *domain.Error {
+	var (
+		domainErr      *domain.Error
+		validationErrs validator.ValidationErrors
+		typeErr        *json.UnmarshalTypeError
+		syntaxErr      *json.SyntaxError
+		numErr         *strconv.NumError
+		timeErr        *time.ParseError
+	)
+	switch {
+	case errors.As(err, &domainErr):
+		if domainErr.Code == domain.ErrCodeValidation && len(domainErr.Fields) > 0 && lang != DefaultLanguage {
+			if localized, ok := v.validateIn(obj, lang).(*domain.Error); ok && localized.Code == domain.ErrCodeValidation {
+				return localized
+			}
+		}
+		return domainErr
+	case errors.As(err, &validationErrs):
+		return validationError(validationErrs, lang)
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid request body",
+				"request body "+translate(DefaultLanguage, typeKey(typeErr.Type)))
+		}
+		return typeError(jsonFieldPath(typeErr.Field), typeKey(typeErr.Type), lang)
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid request body", "request body is not valid JSON")
+	case errors.Is(err, io.EOF):
+		return domain.NewErrorWithDetails(domain.ErrCodeValidation, "Invalid request body", "request body is empty")
+	case errors.As(err, &numErr):
+		key := "type.integer"
+		switch numErr.Func {
+		case "ParseFloat":
+			key = "type.number"
+		case "ParseBool":
+			key = "type.boolean"
+		}
+		return valueError(values, numErr.Num, key, lang)
+	case errors.As(err, &timeErr):
+		return valueError(values, timeErr.Value, "type.time", lang)
+	}
+	return nil
+}
+
+// typeError reports a value of the wrong type for field
+func typeError(field, key, lang string) *domain.Error {
+	return domain.NewValidationError([]domain.FieldError{
+		{Field: field, Rule: "type", Message: translate(lang, key)},
+	})
+}
+
+// jsonFieldPath writes the path of a JSON type mismatch, such as
+// "events.0", the way the validator writes paths, as in "events[0]"
+func jsonFieldPath(field string) string {
+	var path strings.Builder
+	for i, part := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			fmt.Fprintf(&path, "[%s]", part)
+			continue
+		}
+		if i > 0 {
+			path.WriteByte('.')
+		}
+		path.WriteString(part)
+	}
+	return path.String()
+}
+
+// valueError reports a form value that doesn't parse as the parameter
+// carrying it. Values no parameter carries are reported without a field.
+func valueError(values url.Values, value, key, lang string) *domain.Error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, candidate := range values[name] {
+			if candidate == value {
+				return typeError(name, key, lang)
+			}
+		}
+	}
+	return domain.NewErrorWithDetails(domain.ErrCodeValidation, domain.ErrValidation.Message,
+		fmt.Sprintf("invalid value %q: %s", value, translate(DefaultLanguage, key)))
+}
+
+// timeType is the type of time.Time, which JSON encodes as a string
+var timeType = reflect.TypeOf(time.Time{})
+
+// typeKey returns the catalog key describing the JSON type of t
+func typeKey(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return "type.value"
+	}
+	if t == timeType {
+		return "type.time"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "type.string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "type.integer"
+	case reflect.Float32, reflect.Float64:
+		return "type.number"
+	case reflect.Bool:
+		return "type.boolean"
+	case reflect.Slice, reflect.Array:
+		return "type.array"
+	case reflect.Map, reflect.Struct:
+		return "type.object"
+	default:
+		return "type.value"
+	}
+}
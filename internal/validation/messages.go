@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Languages of field messages
+const (
+	LanguageEnglish = "en"
+	LanguageChinese = "zh"
+)
+
+// DefaultLanguage is used when the client accepts no supported language
+const DefaultLanguage = LanguageEnglish
+
+// catalog holds the field messages of each language by key. Size rules have
+// a message per unit, suffixed ".string" for characters and ".items" for
+// slices and maps, and type mismatches are keyed "type." plus the expected
+// JSON type.
+var catalog = map[string]map[string]string{
+	LanguageEnglish: {
+		"required":           "is required",
+		"email":              "must be a valid email address",
+		"url":                "must be a valid URL",
+		"e164":               "must be a phone number in E.164 format",
+		"bcp47_language_tag": "must be a BCP 47 language tag",
+		"timezone":           "must be an IANA time zone",
+		"min":                "must be at least %s",
+		"min.string":         "must be at least %s characters",
+		"min.items":          "must be at least %s items",
+		"max":                "must be at most %s",
+		"max.string":         "must be at most %s characters",
+		"max.items":          "must be at most %s items",
+		"len":                "must be exactly %s",
+		"len.string":         "must be exactly %s characters",
+		"len.items":          "must be exactly %s items",
+		"oneof":              "must be one of: %s",
+		"rule":               "failed the '%s' rule",
+		"type.string":        "must be a string",
+		"type.integer":       "must be an integer",
+		"type.number":        "must be a number",
+		"type.boolean":       "must be a boolean",
+		"type.array":         "must be an array",
+		"type.object":        "must be an object",
+		"type.time":          "must be a time in RFC 3339 format",
+		"type.value":         "has an invalid type",
+	},
+	LanguageChinese: {
+		"required":           "不能为空",
+		"email":              "必须是有效的邮箱地址",
+		"url":                "必须是有效的 URL",
+		"e164":               "必须是 E.164 格式的电话号码",
+		"bcp47_language_tag": "必须是 BCP 47 语言标签",
+		"timezone":           "必须是 IANA 时区",
+		"min":                "不能小于 %s",
+		"min.string":         "长度不能少于 %s 个字符",
+		"min.items":          "不能少于 %s 项",
+		"max":                "不能大于 %s",
+		"max.string":         "长度不能超过 %s 个字符",
+		"max.items":          "不能超过 %s 项",
+		"len":                "必须等于 %s",
+		"len.string":         "长度必须为 %s 个字符",
+		"len.items":          "必须为 %s 项",
+		"oneof":              "必须是以下之一：%s",
+		"rule":               "未通过 '%s' 规则校验",
+		"type.string":        "必须是字符串",
+		"type.integer":       "必须是整数",
+		"type.number":        "必须是数字",
+		"type.boolean":       "必须是布尔值",
+		"type.array":         "必须是数组",
+		"type.object":        "必须是对象",
+		"type.time":          "必须是 RFC 3339 格式的时间",
+		"type.value":         "类型无效",
+	},
+}
+
+// translate formats the message key in lang, falling back to the default
+// language for languages and keys the catalog lacks
+func translate(lang, key string, args ...any) string {
+	format, ok := catalog[lang][key]
+	if !ok {
+		format = catalog[DefaultLanguage][key]
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Language returns the supported language an Accept-Language header
+// prefers, matching on the primary subtag, or DefaultLanguage
+func Language(acceptLanguage string) string {
+	best, bestQuality := DefaultLanguage, 0.0
+	for _, tag := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(tag, ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		primary, _, _ = strings.Cut(primary, "_")
+		if _, ok := catalog[primary]; ok && quality > bestQuality {
+			best, bestQuality = primary, quality
+		}
+	}
+	return best
+}
@@ -0,0 +1,192 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/sanitize"
+	"go.uber.org/fx"
+)
+
+// GetModule returns the fx.Option for the validation module. It also makes
+// the validator gin's binding validator, so ShouldBind* checks validate tags.
+func GetModule() fx.Option {
+	return fx.Options(
+		fx.Provide(New),
+		fx.Provide(asDomainValidator),
+		fx.Invoke(RegisterBinding),
+	)
+}
+
+// Validator validates structs against their validate tags, reporting
+// invalid fields by their JSON or form name
+type Validator struct {
+	validate *validator.Validate
+}
+
+// New creates a new validator
+func New() *Validator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.SetTagName("validate")
+	validate.RegisterTagNameFunc(fieldName)
+	return &Validator{validate: validate}
+}
+
+// asDomainValidator exposes the validator to services
+func asDomainValidator(v *Validator) domain.Validator {
+	return v
+}
+
+// RegisterBinding makes the validator gin's binding validator
+func RegisterBinding(v *Validator) {
+	binding.Validator = v
+}
+
+// Validate normalizes obj when it is a pointer, applying its sanitize tags
+// and then its Normalize method, and returns a validation *domain.Error
+// listing every invalid field
+func (v *Validator) Validate(obj any) error {
+	normalize(obj)
+	return v.validateIn(obj, DefaultLanguage)
+}
+
+// normalize applies the sanitize tags of the struct obj points to, then
+// calls its Normalize method if it is a domain.Normalizer
+func normalize(obj any) {
+	sanitize.Struct(obj)
+	if normalizer, ok := obj.(domain.Normalizer); ok {
+		normalizer.Normalize()
+	}
+}
+
+// validateIn is Validate with field messages in lang
+func (v *Validator) validateIn(obj any, lang string) error {
+	err := v.validate.Struct(obj)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return domain.WrapError(err, domain.ErrCodeInternal, "Failed to validate request")
+	}
+	return validationError(validationErrs, lang)
+}
+
+// validationError lists failed rules as invalid fields
+func validationError(validationErrs validator.ValidationErrors, lang string) *domain.Error {
+	fields := make([]domain.FieldError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		fields = append(fields, domain.FieldError{
+			Field:   fieldPath(fieldErr),
+			Rule:    rule(fieldErr),
+			Message: message(fieldErr, lang),
+		})
+	}
+	return domain.NewValidationError(fields)
+}
+
+// ValidateStruct implements binding.StructValidator, so bound requests are
+// normalized before they are validated. Pointers are followed, slices are
+// validated element by element and other values are ignored.
+func (v *Validator) ValidateStruct(obj any) error {
+	if obj == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(obj)
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		if value.Elem().Kind() == reflect.Struct {
+			return v.Validate(obj)
+		}
+		return v.ValidateStruct(value.Elem().Interface())
+	case reflect.Struct:
+		return v.Validate(obj)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			item := value.Index(i)
+			if item.Kind() == reflect.Struct && item.CanAddr() {
+				item = item.Addr()
+			}
+			if err := v.ValidateStruct(item.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Engine implements binding.StructValidator
+func (v *Validator) Engine() any {
+	return v.validate
+}
+
+// fieldName names struct fields after their JSON or form key
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// fieldPath returns the field's path below the validated struct,
+// e.g. "address.city" rather than "UserCreateRequest.address.city"
+func fieldPath(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// optionalPrefix marks a format rule that also accepts an empty value, used
+// by pointer fields where an empty string clears the value
+const optionalPrefix = "len=0|"
+
+// rule returns the name of the failed rule, without optionalPrefix
+func rule(fieldErr validator.FieldError) string {
+	return strings.TrimPrefix(fieldErr.Tag(), optionalPrefix)
+}
+
+// message describes a failed rule in lang, in the register of
+// domain.ValidationError
+func message(fieldErr validator.FieldError, lang string) string {
+	unit := ""
+	switch fieldErr.Kind() {
+	case reflect.String:
+		unit = ".string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = ".items"
+	}
+
+	switch name := rule(fieldErr); name {
+	case "required", "email", "e164", "bcp47_language_tag", "timezone":
+		return translate(lang, name)
+	case "url", "http_url":
+		return translate(lang, "url")
+	case "min", "gte":
+		return translate(lang, "min"+unit, fieldErr.Param())
+	case "max", "lte":
+		return translate(lang, "max"+unit, fieldErr.Param())
+	case "len":
+		return translate(lang, "len"+unit, fieldErr.Param())
+	case "oneof":
+		return translate(lang, "oneof", strings.Join(strings.Fields(fieldErr.Param()), ", "))
+	default:
+		return translate(lang, "rule", name)
+	}
+}
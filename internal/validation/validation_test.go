@@ -0,0 +1,189 @@
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidate tests that every invalid field is reported by its JSON name
+func TestValidate(t *testing.T) {
+	v := New()
+
+	err := v.Validate(&domain.UserCreateRequest{Email: "not-an-email", Name: "A"})
+	require.Error(t, err)
+
+	domainErr, ok := err.(*domain.Error)
+	require.True(t, ok)
+	assert.Equal(t, domain.ErrCodeValidation, domainErr.Code)
+	assert.Equal(t, []domain.FieldError{
+		{Field: "email", Rule: "email", Message: "must be a valid email address"},
+		{Field: "password", Rule: "required", Message: "is required"},
+		{Field: "name", Rule: "min", Message: "must be at least 2 characters"},
+	}, domainErr.Fields)
+
+	assert.NoError(t, v.Validate(&domain.UserCreateRequest{Email: "user@example.com", Password: "password123", Name: "Alice"}))
+}
+
+// TestValidateOptionalFormats tests format rules that accept an empty value
+func TestValidateOptionalFormats(t *testing.T) {
+	v := New()
+	empty, phone := "", "555-0100"
+
+	assert.NoError(t, v.Validate(&domain.UserUpdateRequest{Phone: &empty, Timezone: &empty}))
+
+	err := v.Validate(&domain.UserUpdateRequest{Phone: &phone})
+	domainErr, ok := err.(*domain.Error)
+	require.True(t, ok)
+	assert.Equal(t, []domain.FieldError{
+		{Field: "phone", Rule: "e164", Message: "must be a phone number in E.164 format"},
+	}, domainErr.Fields)
+}
+
+// TestBinding tests that gin binding reports validation failures as domain errors
+func TestBinding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	RegisterBinding(New())
+
+	var bindErr error
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		var req domain.PaginationRequest
+		bindErr = c.ShouldBindQuery(&req)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?page=0&limit=500", nil))
+	require.Error(t, bindErr)
+
+	domainErr, ok := bindErr.(*domain.Error)
+	require.True(t, ok)
+	require.Len(t, domainErr.Fields, 2)
+	assert.Equal(t, "page", domainErr.Fields[0].Field)
+	assert.Equal(t, "must be at most 100", domainErr.Fields[1].Message)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?page=2&limit=20", nil))
+	assert.NoError(t, bindErr)
+}
+
+// bindingError binds a request to obj as gin does and converts the error
+func bindingError(t *testing.T, v *Validator, req *http.Request, obj any, lang string) *domain.Error {
+	t.Helper()
+
+	var bindErr error
+	router := gin.New()
+	router.Any("/", func(c *gin.Context) {
+		if req.Method == http.MethodGet {
+			bindErr = c.ShouldBindQuery(obj)
+		} else {
+			bindErr = c.ShouldBindJSON(obj)
+		}
+	})
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	require.Error(t, bindErr)
+	return v.BindingError(bindErr, obj, req.URL.Query(), lang)
+}
+
+// TestBindingError tests that binding errors name the invalid field
+// without Go type names
+func TestBindingError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	v := New()
+	RegisterBinding(v)
+
+	body := func(s string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/", strings.NewReader(s))
+	}
+
+	err := bindingError(t, v, body(`{"name":"Alice","active":"yes"}`), &domain.UserUpdateRequest{}, DefaultLanguage)
+	assert.Equal(t, []domain.FieldError{{Field: "active", Rule: "type", Message: "must be a boolean"}}, err.Fields)
+
+	err = bindingError(t, v, body(`{"url":"https://example.com","events":[1]}`), &domain.WebhookCreateRequest{}, DefaultLanguage)
+	assert.Equal(t, []domain.FieldError{{Field: "events[0]", Rule: "type", Message: "must be a string"}}, err.Fields)
+
+	err = bindingError(t, v, body(`[]`), &domain.UserUpdateRequest{}, DefaultLanguage)
+	assert.Equal(t, "request body must be an object", err.Details)
+
+	err = bindingError(t, v, body(`{"name":`), &domain.UserUpdateRequest{}, DefaultLanguage)
+	assert.Equal(t, "request body is not valid JSON", err.Details)
+
+	err = bindingError(t, v, body(``), &domain.UserUpdateRequest{}, DefaultLanguage)
+	assert.Equal(t, "request body is empty", err.Details)
+
+	err = bindingError(t, v, httptest.NewRequest(http.MethodGet, "/?page=2&limit=ten", nil), &domain.PaginationRequest{}, DefaultLanguage)
+	assert.Equal(t, domain.ErrCodeValidation, err.Code)
+	assert.Equal(t, []domain.FieldError{{Field: "limit", Rule: "type", Message: "must be an integer"}}, err.Fields)
+
+	err = bindingError(t, v, httptest.NewRequest(http.MethodGet, "/?active=maybe", nil), &domain.UserListFilter{}, DefaultLanguage)
+	assert.Equal(t, []domain.FieldError{{Field: "active", Rule: "type", Message: "must be a boolean"}}, err.Fields)
+
+	err = bindingError(t, v, httptest.NewRequest(http.MethodGet, "/?created_after=yesterday", nil), &domain.UserListFilter{}, DefaultLanguage)
+	assert.Equal(t, []domain.FieldError{{Field: "created_after", Rule: "type", Message: "must be a time in RFC 3339 format"}}, err.Fields)
+
+	assert.Nil(t, v.BindingError(errors.New("unexpected"), nil, nil, DefaultLanguage))
+}
+
+// TestBindingErrorLanguage tests that field messages follow the language
+func TestBindingErrorLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	v := New()
+	RegisterBinding(v)
+
+	err := bindingError(t, v, httptest.NewRequest(http.MethodGet, "/?page=0&limit=500", nil), &domain.PaginationRequest{}, LanguageChinese)
+	assert.Equal(t, []domain.FieldError{
+		{Field: "page", Rule: "min", Message: "不能小于 1"},
+		{Field: "limit", Rule: "max", Message: "不能大于 100"},
+	}, err.Fields)
+
+	err = bindingError(t, v, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"x","name":"A"}`)), &domain.UserCreateRequest{}, LanguageChinese)
+	assert.Equal(t, []domain.FieldError{
+		{Field: "email", Rule: "email", Message: "必须是有效的邮箱地址"},
+		{Field: "password", Rule: "required", Message: "不能为空"},
+		{Field: "name", Rule: "min", Message: "长度不能少于 2 个字符"},
+	}, err.Fields)
+
+	err = bindingError(t, v, httptest.NewRequest(http.MethodGet, "/?limit=ten", nil), &domain.PaginationRequest{}, LanguageChinese)
+	assert.Equal(t, "必须是整数", err.Fields[0].Message)
+}
+
+// TestLanguage tests Accept-Language negotiation
+func TestLanguage(t *testing.T) {
+	assert.Equal(t, LanguageEnglish, Language(""))
+	assert.Equal(t, LanguageChinese, Language("zh-CN,zh;q=0.9,en;q=0.8"))
+	assert.Equal(t, LanguageChinese, Language("fr, en;q=0.5, zh_TW;q=0.8"))
+	assert.Equal(t, LanguageEnglish, Language("fr, de;q=0.5"))
+	assert.Equal(t, LanguageEnglish, Language("zh;q=0, en;q=0.1"))
+}
+
+// TestNormalize tests that requests are normalized before they are
+// validated, whether bound by gin or validated by a service
+func TestNormalize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	v := New()
+	RegisterBinding(v)
+
+	req := &domain.UserCreateRequest{Email: "  Alice@Example.COM ", Password: "password123", Name: " A "}
+	err := v.Validate(req)
+	assert.Equal(t, "alice@example.com", req.Email)
+	assert.Equal(t, "A", req.Name)
+	domainErr, ok := err.(*domain.Error)
+	require.True(t, ok)
+	assert.Equal(t, []domain.FieldError{{Field: "name", Rule: "min", Message: "must be at least 2 characters"}}, domainErr.Fields,
+		"padding doesn't count towards lengths")
+
+	var hook domain.WebhookCreateRequest
+	router := gin.New()
+	router.POST("/", func(c *gin.Context) {
+		require.NoError(t, c.ShouldBindJSON(&hook))
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/",
+		strings.NewReader(`{"url":" https://example.com/hook ","events":["user.created"," user.created ","*"]}`)))
+	assert.Equal(t, "https://example.com/hook", hook.URL)
+	assert.Equal(t, []string{"user.created", "*"}, hook.Events, "Normalize runs after the sanitize tags")
+}
@@ -0,0 +1,82 @@
+// Package buildinfo reports the version of the running binary.
+//
+// The values are set at build time with -ldflags, as the Makefile and the
+// Dockerfile do:
+//
+//	go build -ldflags "-X github.com/luxixing/fx-gin-scaffold/pkg/buildinfo.Version=v1.2.0 \
+//		-X github.com/luxixing/fx-gin-scaffold/pkg/buildinfo.Commit=$(git rev-parse HEAD) \
+//		-X github.com/luxixing/fx-gin-scaffold/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without them, the commit and its time are read from the VCS information
+// the Go toolchain embeds in binaries built inside a git checkout.
+//
+// The build is also reported to the metrics registry as the build_info
+// gauge, always 1, with the version, commit and Go version as labels.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/metrics"
+)
+
+// devVersion is the version of binaries built without one
+const devVersion = "dev"
+
+// Build information set with -ldflags -X
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+func init() {
+	info := Get()
+	metrics.Default.NewGauge("build_info",
+		"Build of the running binary, always 1.", "version", "commit", "build_time", "go_version").
+		With(info.Version, info.Commit, info.BuildTime, runtime.Version()).Set(1)
+}
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+}
+
+// Get returns the build information of the running binary
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		for _, setting := range bi.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = devVersion
+	}
+	return info
+}
+
+// String formats the build information for --version output
+func (i Info) String() string {
+	s := i.Version
+	if i.Commit != "" {
+		s += " commit " + i.Commit
+	}
+	if i.BuildTime != "" {
+		s += " built " + i.BuildTime
+	}
+	return s + " " + runtime.Version()
+}
@@ -0,0 +1,39 @@
+package buildinfo
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	t.Run("defaults to a development version", func(t *testing.T) {
+		assert.Equal(t, devVersion, Get().Version)
+	})
+
+	t.Run("prefers the values set with ldflags", func(t *testing.T) {
+		defer func(version, commit, buildTime string) {
+			Version, Commit, BuildTime = version, commit, buildTime
+		}(Version, Commit, BuildTime)
+		Version, Commit, BuildTime = "v1.2.0", "abc123", "2024-11-20T12:00:00Z"
+
+		assert.Equal(t, Info{Version: "v1.2.0", Commit: "abc123", BuildTime: "2024-11-20T12:00:00Z"}, Get())
+	})
+}
+
+func TestInfoString(t *testing.T) {
+	info := Info{Version: "v1.2.0", Commit: "abc123", BuildTime: "2024-11-20T12:00:00Z"}
+	assert.Equal(t, "v1.2.0 commit abc123 built 2024-11-20T12:00:00Z "+runtime.Version(), info.String())
+	assert.Equal(t, "dev "+runtime.Version(), Info{Version: "dev"}.String())
+}
+
+func TestBuildInfoMetric(t *testing.T) {
+	var out strings.Builder
+	_, err := metrics.Default.WriteTo(&out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), `build_info{version="`+Get().Version+`"`)
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCacheMiss is returned when a key does not exist in the cache
+var ErrCacheMiss = errors.New("cache: key not found")
+
+// Config holds cache configuration
+type Config struct {
+	Addr         string        `json:"addr" yaml:"addr"`
+	Password     string        `json:"password" yaml:"password"`
+	DB           int           `json:"db" yaml:"db"`
+	PoolSize     int           `json:"pool_size" yaml:"pool_size"`
+	MinIdleConns int           `json:"min_idle_conns" yaml:"min_idle_conns"`
+	DialTimeout  time.Duration `json:"dial_timeout" yaml:"dial_timeout"`
+}
+
+// Client defines the cache operations available to services
+type Client interface {
+	// Get returns the value stored at key or ErrCacheMiss
+	Get(ctx context.Context, key string) (string, error)
+
+	// Set stores a value with an optional TTL (zero means no expiration)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+
+	// SetNX stores a value only if the key does not exist yet
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+
+	// Delete removes the given keys
+	Delete(ctx context.Context, keys ...string) error
+
+	// Exists reports whether key exists
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// Incr atomically increments the integer stored at key
+	Incr(ctx context.Context, key string) (int64, error)
+
+	// Expire sets a TTL on an existing key
+	Expire(ctx context.Context, key string, ttl time.Duration) error
+
+	// Health checks cache connectivity
+	Health(ctx context.Context) error
+
+	// Close releases the underlying resources
+	Close() error
+}
+
+// NewClient creates a Redis client when an address is configured,
+// falling back to an in-process memory cache otherwise
+func NewClient(cfg Config) (Client, error) {
+	if cfg.Addr == "" {
+		return NewMemoryClient(), nil
+	}
+	return NewRedisClient(cfg)
+}
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memoryEntry is a single cached value
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// expired reports whether the entry has a TTL that has passed
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// memoryClient implements Client in process memory for single-node deployments
+type memoryClient struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+// NewMemoryClient creates an in-memory cache client
+func NewMemoryClient() Client {
+	return &memoryClient{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Get returns the value stored at key or ErrCacheMiss
+func (c *memoryClient) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.lookup(key)
+	if !ok {
+		return "", ErrCacheMiss
+	}
+	return entry.value, nil
+}
+
+// Set stores a value with an optional TTL (zero means no expiration)
+func (c *memoryClient) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = newMemoryEntry(value, ttl)
+	return nil
+}
+
+// SetNX stores a value only if the key does not exist yet
+func (c *memoryClient) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.lookup(key); ok {
+		return false, nil
+	}
+	c.entries[key] = newMemoryEntry(value, ttl)
+	return true, nil
+}
+
+// Delete removes the given keys
+func (c *memoryClient) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+// Exists reports whether key exists
+func (c *memoryClient) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.lookup(key)
+	return ok, nil
+}
+
+// Incr atomically increments the integer stored at key
+func (c *memoryClient) Incr(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.lookup(key)
+	var current int64
+	if ok {
+		value, err := strconv.ParseInt(entry.value, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		current = value
+	}
+
+	current++
+	entry.value = strconv.FormatInt(current, 10)
+	c.entries[key] = entry
+	return current, nil
+}
+
+// Expire sets a TTL on an existing key
+func (c *memoryClient) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.lookup(key)
+	if !ok {
+		return nil
+	}
+	c.entries[key] = newMemoryEntry(entry.value, ttl)
+	return nil
+}
+
+// Health checks cache connectivity
+func (c *memoryClient) Health(ctx context.Context) error {
+	return nil
+}
+
+// Close releases the underlying resources
+func (c *memoryClient) Close() error {
+	return nil
+}
+
+// lookup returns a live entry, evicting it if it has expired; callers must hold mu
+func (c *memoryClient) lookup(key string) (memoryEntry, bool) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if entry.expired(time.Now()) {
+		delete(c.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
+
+// newMemoryEntry creates an entry expiring after ttl (zero means never)
+func newMemoryEntry(value string, ttl time.Duration) memoryEntry {
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	return entry
+}
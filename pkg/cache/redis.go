@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisClient implements Client backed by Redis
+type redisClient struct {
+	client *redis.Client
+}
+
+// NewRedisClient creates a Redis client and verifies the connection
+func NewRedisClient(cfg Config) (Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		MinIdleConns: cfg.MinIdleConns,
+		DialTimeout:  cfg.DialTimeout,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &redisClient{client: client}, nil
+}
+
+// Get returns the value stored at key or ErrCacheMiss
+func (c *redisClient) Get(ctx context.Context, key string) (string, error) {
+	value, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrCacheMiss
+	}
+	return value, err
+}
+
+// Set stores a value with an optional TTL (zero means no expiration)
+func (c *redisClient) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// SetNX stores a value only if the key does not exist yet
+func (c *redisClient) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// Delete removes the given keys
+func (c *redisClient) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// Exists reports whether key exists
+func (c *redisClient) Exists(ctx context.Context, key string) (bool, error) {
+	count, err := c.client.Exists(ctx, key).Result()
+	return count > 0, err
+}
+
+// Incr atomically increments the integer stored at key
+func (c *redisClient) Incr(ctx context.Context, key string) (int64, error) {
+	return c.client.Incr(ctx, key).Result()
+}
+
+// Expire sets a TTL on an existing key
+func (c *redisClient) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return c.client.Expire(ctx, key, ttl).Err()
+}
+
+// Health checks cache connectivity
+func (c *redisClient) Health(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// Close releases the underlying resources
+func (c *redisClient) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// ListRoles lists the roles and their permissions
+func (c *Client) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	var roles []*domain.Role
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/roles"}, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// CreateRole creates a role
+func (c *Client) CreateRole(ctx context.Context, req *domain.RoleCreateRequest) (*domain.Role, error) {
+	var role domain.Role
+	if _, err := c.do(ctx, &request{method: http.MethodPost, path: apiPrefix + "/roles", body: req}, &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// UpdateRole updates a role
+func (c *Client) UpdateRole(ctx context.Context, name string, req *domain.RoleUpdateRequest) (*domain.Role, error) {
+	var role domain.Role
+	if _, err := c.do(ctx, &request{method: http.MethodPut, path: apiPrefix + "/roles/" + url.PathEscape(name), body: req}, &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// DeleteRole deletes a role
+func (c *Client) DeleteRole(ctx context.Context, name string) error {
+	_, err := c.do(ctx, &request{method: http.MethodDelete, path: apiPrefix + "/roles/" + url.PathEscape(name)}, nil)
+	return err
+}
+
+// ListPermissions lists the permissions that can be granted to roles
+func (c *Client) ListPermissions(ctx context.Context) ([]*domain.Permission, error) {
+	var permissions []*domain.Permission
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/permissions"}, &permissions); err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// ListAuditLogs lists audit log entries matching the filter, newest first
+func (c *Client) ListAuditLogs(ctx context.Context, filter *domain.AuditLogFilter, page *domain.PaginationRequest) ([]*domain.AuditLog, *domain.Meta, error) {
+	var logs []*domain.AuditLog
+	meta, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/audit-logs", query: pageQuery(page, filter)}, &logs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return logs, meta, nil
+}
+
+// ListInvites lists registration invites matching the filter, newest first
+func (c *Client) ListInvites(ctx context.Context, filter *domain.InviteFilter, page *domain.PaginationRequest) ([]*domain.InviteResponse, *domain.Meta, error) {
+	var invites []*domain.InviteResponse
+	meta, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/invites", query: pageQuery(page, filter)}, &invites)
+	if err != nil {
+		return nil, nil, err
+	}
+	return invites, meta, nil
+}
+
+// CreateInvite emails a registration invite code
+func (c *Client) CreateInvite(ctx context.Context, req *domain.InviteCreateRequest) (*domain.InviteResponse, error) {
+	var invite domain.InviteResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPost, path: apiPrefix + "/invites", body: req}, &invite); err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// RevokeInvite revokes a pending registration invite
+func (c *Client) RevokeInvite(ctx context.Context, id uint) (*domain.InviteResponse, error) {
+	var invite domain.InviteResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPost, path: apiPrefix + "/invites/" + idPath(id) + "/revoke"}, &invite); err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// ListWebhooks lists the registered webhooks
+func (c *Client) ListWebhooks(ctx context.Context, page *domain.PaginationRequest) ([]*domain.WebhookResponse, *domain.Meta, error) {
+	var hooks []*domain.WebhookResponse
+	meta, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/webhooks", query: pageQuery(page)}, &hooks)
+	if err != nil {
+		return nil, nil, err
+	}
+	return hooks, meta, nil
+}
+
+// CreateWebhook registers a webhook; the response includes its secret
+func (c *Client) CreateWebhook(ctx context.Context, req *domain.WebhookCreateRequest) (*domain.WebhookResponse, error) {
+	var hook domain.WebhookResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPost, path: apiPrefix + "/webhooks", body: req}, &hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// GetWebhook retrieves a webhook by ID
+func (c *Client) GetWebhook(ctx context.Context, id uint) (*domain.WebhookResponse, error) {
+	var hook domain.WebhookResponse
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/webhooks/" + idPath(id)}, &hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// UpdateWebhook applies a partial update to a webhook
+func (c *Client) UpdateWebhook(ctx context.Context, id uint, req *domain.WebhookUpdateRequest) (*domain.WebhookResponse, error) {
+	var hook domain.WebhookResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPut, path: apiPrefix + "/webhooks/" + idPath(id), body: req}, &hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// DeleteWebhook deletes a webhook and its deliveries
+func (c *Client) DeleteWebhook(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, &request{method: http.MethodDelete, path: apiPrefix + "/webhooks/" + idPath(id)}, nil)
+	return err
+}
+
+// ListWebhookDeliveries lists the delivery log of a webhook, newest first
+func (c *Client) ListWebhookDeliveries(ctx context.Context, webhookID uint, page *domain.PaginationRequest) ([]*domain.WebhookDelivery, *domain.Meta, error) {
+	var deliveries []*domain.WebhookDelivery
+	meta, err := c.do(ctx, &request{
+		method: http.MethodGet,
+		path:   apiPrefix + "/webhooks/" + idPath(webhookID) + "/deliveries",
+		query:  pageQuery(page),
+	}, &deliveries)
+	if err != nil {
+		return nil, nil, err
+	}
+	return deliveries, meta, nil
+}
+
+// RedeliverWebhookDelivery queues a delivery to be sent again
+func (c *Client) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID uint) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	if _, err := c.do(ctx, &request{
+		method: http.MethodPost,
+		path:   apiPrefix + "/webhooks/" + idPath(webhookID) + "/deliveries/" + idPath(deliveryID) + "/redeliver",
+	}, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// Register creates an account and signs the client in as the new user
+func (c *Client) Register(ctx context.Context, req *domain.UserCreateRequest) (*domain.AuthResponse, error) {
+	return c.authenticate(ctx, "/auth/register", req)
+}
+
+// Login signs the client in
+func (c *Client) Login(ctx context.Context, req *domain.UserLoginRequest) (*domain.AuthResponse, error) {
+	return c.authenticate(ctx, "/auth/login", req)
+}
+
+// authenticate posts credentials and stores the issued tokens
+func (c *Client) authenticate(ctx context.Context, path string, body any) (*domain.AuthResponse, error) {
+	var auth domain.AuthResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPost, path: apiPrefix + path, body: body, public: true}, &auth); err != nil {
+		return nil, err
+	}
+	c.SetTokens(domain.TokenPair{
+		AccessToken:  auth.Token,
+		RefreshToken: auth.RefreshToken,
+		ExpiresAt:    auth.ExpiresAt,
+	})
+	return &auth, nil
+}
+
+// RefreshToken exchanges the current refresh token for new tokens. Requests
+// do this automatically when the access token has expired.
+func (c *Client) RefreshToken(ctx context.Context) (*domain.TokenPair, error) {
+	if err := c.refresh(ctx, c.Tokens().AccessToken); err != nil {
+		return nil, err
+	}
+	tokens := c.Tokens()
+	return &tokens, nil
+}
+
+// Logout revokes the current session and forgets the tokens
+func (c *Client) Logout(ctx context.Context) error {
+	_, err := c.do(ctx, &request{
+		method: http.MethodPost,
+		path:   apiPrefix + "/auth/logout",
+		body:   &domain.LogoutRequest{RefreshToken: c.Tokens().RefreshToken},
+	}, nil)
+	if err != nil {
+		return err
+	}
+	c.SetTokens(domain.TokenPair{})
+	return nil
+}
+
+// GetProfile retrieves the signed in user
+func (c *Client) GetProfile(ctx context.Context) (*domain.UserResponse, error) {
+	var user domain.UserResponse
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/auth/profile"}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateProfile updates the signed in user's profile
+func (c *Client) UpdateProfile(ctx context.Context, req *domain.UserUpdateRequest) (*domain.UserResponse, error) {
+	var user domain.UserResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPut, path: apiPrefix + "/auth/profile", body: req}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// PatchProfile applies a JSON Merge Patch or JSON Patch to the signed in
+// user's profile
+func (c *Client) PatchProfile(ctx context.Context, patch *domain.Patch) (*domain.UserResponse, error) {
+	var user domain.UserResponse
+	if _, err := c.do(ctx, patchRequest(apiPrefix+"/auth/profile", patch), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteAccount schedules the signed in user's account for deletion and
+// forgets the tokens, which the server has revoked
+func (c *Client) DeleteAccount(ctx context.Context, password string) (*domain.UserResponse, error) {
+	var user domain.UserResponse
+	if _, err := c.do(ctx, &request{
+		method: http.MethodDelete,
+		path:   apiPrefix + "/auth/profile",
+		body:   &domain.DeleteAccountRequest{Password: password},
+	}, &user); err != nil {
+		return nil, err
+	}
+	c.SetTokens(domain.TokenPair{})
+	return &user, nil
+}
+
+// ExportData downloads the signed in user's personal data
+func (c *Client) ExportData(ctx context.Context) (*domain.DataExport, error) {
+	var export domain.DataExport
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/auth/profile/export", raw: true}, &export); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ChangePassword changes the signed in user's password
+func (c *Client) ChangePassword(ctx context.Context, req *domain.ChangePasswordRequest) error {
+	_, err := c.do(ctx, &request{method: http.MethodPut, path: apiPrefix + "/auth/password", body: req}, nil)
+	return err
+}
+
+// RequestEmailChange mails a confirmation link to the new email
+func (c *Client) RequestEmailChange(ctx context.Context, req *domain.EmailChangeRequest) (*domain.UserResponse, error) {
+	var user domain.UserResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPut, path: apiPrefix + "/auth/email", body: req}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ConfirmEmailChange confirms an email change with the token from the link
+func (c *Client) ConfirmEmailChange(ctx context.Context, token string) (*domain.UserResponse, error) {
+	var user domain.UserResponse
+	if _, err := c.do(ctx, &request{
+		method: http.MethodGet,
+		path:   apiPrefix + "/auth/email/confirm",
+		query:  url.Values{"token": {token}},
+		public: true,
+	}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ListSessions lists the signed in user's sessions
+func (c *Client) ListSessions(ctx context.Context) ([]*domain.Session, error) {
+	var sessions []*domain.Session
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/auth/sessions"}, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs out one of the signed in user's sessions
+func (c *Client) RevokeSession(ctx context.Context, id string) error {
+	_, err := c.do(ctx, &request{method: http.MethodDelete, path: apiPrefix + "/auth/sessions/" + url.PathEscape(id)}, nil)
+	return err
+}
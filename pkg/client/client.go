@@ -0,0 +1,388 @@
+// Package client is a typed Go client for the fx-gin-scaffold REST API. It
+// decodes the standard response envelope into the domain types, keeps the
+// caller's access and refresh tokens, refreshing them when the access token
+// expires, and retries idempotent requests on transient failures.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/jsonpatch"
+)
+
+// apiPrefix is the path of the versioned API routes
+const apiPrefix = "/api/v1"
+
+// Config contains client settings
+type Config struct {
+	// BaseURL is the server address, e.g. https://api.example.com
+	BaseURL string
+
+	// HTTPClient sends the requests; a client with Timeout is used when nil
+	HTTPClient *http.Client
+	Timeout    time.Duration
+
+	// MaxRetries is the number of times an idempotent request is retried
+	// after a network error, 429 or 5xx gateway response
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubling for each
+	// further one unless the server sends Retry-After
+	RetryBackoff time.Duration
+
+	// OnTokenRefresh is called with the new tokens after they are refreshed,
+	// so callers can persist them
+	OnTokenRefresh func(domain.TokenPair)
+}
+
+// Error is returned for responses with an error status. It unwraps to the
+// *domain.Error of the response envelope.
+type Error struct {
+	StatusCode int
+	Err        *domain.Error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("api error %d: %s: %s", e.StatusCode, e.Err.Code, e.Err.Message)
+}
+
+// Unwrap returns the domain error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+	onRefresh  func(domain.TokenPair)
+
+	mu     sync.Mutex
+	tokens domain.TokenPair
+
+	// refreshMu serializes token refreshes
+	refreshMu sync.Mutex
+}
+
+// New creates a client
+func New(cfg Config) (*Client, error) {
+	base, err := url.Parse(cfg.BaseURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("client: invalid base URL %q", cfg.BaseURL)
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		timeout := cfg.Timeout
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		httpClient = &http.Client{Timeout: timeout}
+	}
+	backoff := cfg.RetryBackoff
+	if backoff <= 0 {
+		backoff = 200 * time.Millisecond
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(base.String(), "/"),
+		httpClient: httpClient,
+		maxRetries: cfg.MaxRetries,
+		backoff:    backoff,
+		onRefresh:  cfg.OnTokenRefresh,
+	}, nil
+}
+
+// SetTokens sets the tokens used to authenticate requests, e.g. ones stored
+// from an earlier session
+func (c *Client) SetTokens(tokens domain.TokenPair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens = tokens
+}
+
+// Tokens returns the current tokens
+func (c *Client) Tokens() domain.TokenPair {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens
+}
+
+// request describes an API call
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+
+	// contentType of the body, application/json when empty
+	contentType string
+
+	// public requests are sent without the access token
+	public bool
+
+	// raw responses are not wrapped in the response envelope
+	raw bool
+
+	// okStatus lists non-2xx statuses whose body is decoded as a result
+	okStatus []int
+}
+
+// accepts reports whether a response status carries a result
+func (r *request) accepts(status int) bool {
+	if status >= 200 && status < 300 {
+		return true
+	}
+	for _, s := range r.okStatus {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// patchRequest builds a PATCH request whose content type names the patch
+// format
+func patchRequest(path string, patch *domain.Patch) *request {
+	contentType := jsonpatch.MergePatchMediaType
+	if patch.Type == domain.PatchTypeJSON {
+		contentType = jsonpatch.JSONPatchMediaType
+	}
+	return &request{
+		method:      http.MethodPatch,
+		path:        path,
+		body:        json.RawMessage(patch.Document),
+		contentType: contentType,
+	}
+}
+
+// envelope is the standard response body
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   *domain.Error   `json:"error"`
+	Meta    *domain.Meta    `json:"meta"`
+}
+
+// do sends a request and decodes its data into out, returning the response
+// metadata. An expired access token is refreshed once.
+func (c *Client) do(ctx context.Context, req *request, out any) (*domain.Meta, error) {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return nil, fmt.Errorf("client: encode request: %w", err)
+		}
+	}
+
+	tokens := c.Tokens()
+	if !req.public && tokens.AccessToken != "" && tokens.RefreshToken != "" &&
+		!tokens.ExpiresAt.IsZero() && time.Now().After(tokens.ExpiresAt) {
+		if err := c.refresh(ctx, tokens.AccessToken); err != nil {
+			return nil, err
+		}
+		tokens = c.Tokens()
+	}
+
+	status, respBody, err := c.send(ctx, req, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// The access token may have expired early, e.g. after a key rotation
+	if status == http.StatusUnauthorized && !req.public && tokens.RefreshToken != "" {
+		if err := c.refresh(ctx, tokens.AccessToken); err != nil {
+			return nil, err
+		}
+		if status, respBody, err = c.send(ctx, req, body); err != nil {
+			return nil, err
+		}
+	}
+
+	return decode(req, status, respBody, out)
+}
+
+// send sends a request, retrying idempotent ones on transient failures
+func (c *Client) send(ctx context.Context, req *request, body []byte) (int, []byte, error) {
+	retries := 0
+	if isIdempotent(req.method) {
+		retries = c.maxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		status, respBody, retryAfter, err := c.sendOnce(ctx, req, body)
+		if err == nil && (!isRetryableStatus(status) || req.accepts(status)) || attempt >= retries || ctx.Err() != nil {
+			return status, respBody, err
+		}
+
+		delay := retryAfter
+		if delay <= 0 {
+			delay = c.backoff << attempt
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// sendOnce makes one attempt, returning the response status, body and Retry-After delay
+func (c *Client) sendOnce(ctx context.Context, req *request, body []byte) (int, []byte, time.Duration, error) {
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, reader)
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("client: create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	// The client decodes envelopes whatever the server's default format
+	httpReq.Header.Set("X-Response-Format", "envelope")
+	if body != nil {
+		contentType := req.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if !req.public {
+		if token := c.Tokens().AccessToken; token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("client: %s %s: %w", req.method, req.path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("client: read response: %w", err)
+	}
+	return resp.StatusCode, respBody, retryAfter(resp.Header.Get("Retry-After")), nil
+}
+
+// refresh exchanges the refresh token for new tokens, unless another request
+// did so since the access token was read
+func (c *Client) refresh(ctx context.Context, staleAccessToken string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	tokens := c.Tokens()
+	if tokens.AccessToken != staleAccessToken {
+		return nil
+	}
+
+	var pair domain.TokenPair
+	if _, err := c.do(ctx, &request{
+		method: http.MethodPost,
+		path:   apiPrefix + "/auth/refresh",
+		body:   &domain.RefreshTokenRequest{RefreshToken: tokens.RefreshToken},
+		public: true,
+	}, &pair); err != nil {
+		return err
+	}
+	c.storeTokens(pair)
+	return nil
+}
+
+// storeTokens replaces the current tokens and notifies OnTokenRefresh
+func (c *Client) storeTokens(pair domain.TokenPair) {
+	c.SetTokens(pair)
+	if c.onRefresh != nil {
+		c.onRefresh(pair)
+	}
+}
+
+// decode turns a response into its data and metadata, or an *Error
+func decode(req *request, status int, body []byte, out any) (*domain.Meta, error) {
+	ok := req.accepts(status)
+	if req.raw && ok {
+		if out != nil && len(body) > 0 {
+			if err := json.Unmarshal(body, out); err != nil {
+				return nil, fmt.Errorf("client: decode response: %w", err)
+			}
+		}
+		return nil, nil
+	}
+
+	var env envelope
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &env); err != nil && ok {
+			return nil, fmt.Errorf("client: decode response: %w", err)
+		}
+	}
+
+	if !ok {
+		apiErr := env.Error
+		if apiErr == nil {
+			apiErr = &domain.Error{Code: domain.ErrCodeInternal, Message: http.StatusText(status)}
+		}
+		return nil, &Error{StatusCode: status, Err: apiErr}
+	}
+
+	if out != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return nil, fmt.Errorf("client: decode response: %w", err)
+		}
+	}
+	return env.Meta, nil
+}
+
+// isIdempotent reports whether a request may be sent again safely
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isRetryableStatus reports whether a response status is likely transient
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header given in seconds
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// IsNotFound reports whether err is a not found API error
+func IsNotFound(err error) bool {
+	var domainErr *domain.Error
+	return errors.As(err, &domainErr) && domainErr.Code == domain.ErrCodeNotFound
+}
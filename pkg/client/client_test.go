@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	var refreshed []domain.TokenPair
+	var unavailable atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, domain.NewSuccessResponse(&domain.AuthResponse{
+			Token:        "access-1",
+			RefreshToken: "refresh-1",
+			ExpiresAt:    time.Now().Add(time.Hour),
+			User:         &domain.UserResponse{ID: 1, Email: "user@example.com"},
+		}))
+	})
+	mux.HandleFunc("/api/v1/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		var req domain.RefreshTokenRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.RefreshToken != "refresh-1" {
+			writeJSON(w, http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrInvalidToken))
+			return
+		}
+		writeJSON(w, http.StatusOK, domain.NewSuccessResponse(&domain.TokenPair{
+			AccessToken:  "access-2",
+			RefreshToken: "refresh-2",
+			ExpiresAt:    time.Now().Add(time.Hour),
+		}))
+	})
+	mux.HandleFunc("/api/v1/auth/profile", func(w http.ResponseWriter, r *http.Request) {
+		// The first access token has been revoked
+		if r.Header.Get("Authorization") != "Bearer access-2" {
+			writeJSON(w, http.StatusUnauthorized, domain.NewErrorResponse(domain.ErrInvalidToken))
+			return
+		}
+		writeJSON(w, http.StatusOK, domain.NewSuccessResponse(&domain.UserResponse{ID: 1, Name: "User"}))
+	})
+	mux.HandleFunc("/api/v1/users", func(w http.ResponseWriter, r *http.Request) {
+		if unavailable.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			writeJSON(w, http.StatusServiceUnavailable, domain.NewErrorResponse(domain.ErrInternalServer))
+			return
+		}
+		assert.Equal(t, "admin", r.URL.Query().Get("role"))
+		assert.Equal(t, "false", r.URL.Query().Get("active"))
+		assert.Equal(t, "2", r.URL.Query().Get("page"))
+		assert.Empty(t, r.URL.Query().Get("sort"))
+		writeJSON(w, http.StatusOK, domain.NewSuccessResponseWithMeta(
+			[]*domain.UserResponse{{ID: 2}},
+			&domain.Meta{Total: 11, Page: 2, Limit: 10, Pages: 2},
+		))
+	})
+	mux.HandleFunc("/api/v1/users/3", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, domain.NewErrorResponse(domain.ErrUserNotFound))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, err := New(Config{
+		BaseURL:        server.URL,
+		MaxRetries:     1,
+		RetryBackoff:   time.Millisecond,
+		OnTokenRefresh: func(pair domain.TokenPair) { refreshed = append(refreshed, pair) },
+	})
+	require.NoError(t, err)
+
+	// Logging in stores the tokens
+	auth, err := c.Login(ctx, &domain.UserLoginRequest{Email: "user@example.com", Password: "password"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), auth.User.ID)
+	assert.Equal(t, "access-1", c.Tokens().AccessToken)
+
+	// A rejected access token is refreshed and the request sent again
+	profile, err := c.GetProfile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "User", profile.Name)
+	require.Len(t, refreshed, 1)
+	assert.Equal(t, "refresh-2", c.Tokens().RefreshToken)
+
+	// Idempotent requests are retried; filters and pagination become query parameters
+	active := false
+	users, meta, err := c.ListUsers(ctx, &domain.UserListFilter{Role: "admin", Active: &active}, &domain.PaginationRequest{Page: 2})
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, int64(11), meta.Total)
+
+	// Error responses decode into the domain error
+	_, err = c.GetUser(ctx, 3)
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, domain.ErrUserNotFound.Message, apiErr.Err.Message)
+	assert.True(t, IsNotFound(err))
+}
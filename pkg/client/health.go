@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/jwtkeys"
+)
+
+// Live calls the liveness probe
+func (c *Client) Live(ctx context.Context) (*domain.HealthReport, error) {
+	var report domain.HealthReport
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: "/health/live", public: true, raw: true}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Ready calls the readiness probe. An unhealthy report is returned without
+// an error; check report.Healthy().
+func (c *Client) Ready(ctx context.Context) (*domain.HealthReport, error) {
+	var report domain.HealthReport
+	if _, err := c.do(ctx, &request{
+		method:   http.MethodGet,
+		path:     "/health/ready",
+		public:   true,
+		raw:      true,
+		okStatus: []int{http.StatusServiceUnavailable},
+	}, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Meta retrieves the version of the server and its enabled features
+func (c *Client) Meta(ctx context.Context) (*domain.ServerMeta, error) {
+	var meta domain.ServerMeta
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/meta", public: true}, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// MetaConfig retrieves the public server configuration, such as the
+// registration mode
+func (c *Client) MetaConfig(ctx context.Context) (*domain.PublicConfig, error) {
+	var cfg domain.PublicConfig
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/meta/config", public: true}, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// JWKS retrieves the public keys for verifying access tokens
+func (c *Client) JWKS(ctx context.Context) (*jwtkeys.JWKSet, error) {
+	var keys jwtkeys.JWKSet
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: "/.well-known/jwks.json", public: true, raw: true}, &keys); err != nil {
+		return nil, err
+	}
+	return &keys, nil
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// ListOrganizations lists the organizations the caller belongs to
+func (c *Client) ListOrganizations(ctx context.Context, page *domain.PaginationRequest) ([]*domain.OrganizationResponse, *domain.Meta, error) {
+	var orgs []*domain.OrganizationResponse
+	meta, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/organizations", query: pageQuery(page)}, &orgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return orgs, meta, nil
+}
+
+// CreateOrganization creates an organization owned by the caller
+func (c *Client) CreateOrganization(ctx context.Context, req *domain.OrganizationCreateRequest) (*domain.OrganizationResponse, error) {
+	var org domain.OrganizationResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPost, path: apiPrefix + "/organizations", body: req}, &org); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// GetOrganization retrieves an organization by ID
+func (c *Client) GetOrganization(ctx context.Context, id uint) (*domain.OrganizationResponse, error) {
+	var org domain.OrganizationResponse
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/organizations/" + idPath(id)}, &org); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// UpdateOrganization updates an organization
+func (c *Client) UpdateOrganization(ctx context.Context, id uint, req *domain.OrganizationUpdateRequest) (*domain.OrganizationResponse, error) {
+	var org domain.OrganizationResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPut, path: apiPrefix + "/organizations/" + idPath(id), body: req}, &org); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// DeleteOrganization deletes an organization
+func (c *Client) DeleteOrganization(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, &request{method: http.MethodDelete, path: apiPrefix + "/organizations/" + idPath(id)}, nil)
+	return err
+}
+
+// ListMembers lists the members of an organization
+func (c *Client) ListMembers(ctx context.Context, orgID uint, page *domain.PaginationRequest) ([]*domain.MemberResponse, *domain.Meta, error) {
+	var members []*domain.MemberResponse
+	meta, err := c.do(ctx, &request{
+		method: http.MethodGet,
+		path:   apiPrefix + "/organizations/" + idPath(orgID) + "/members",
+		query:  pageQuery(page),
+	}, &members)
+	if err != nil {
+		return nil, nil, err
+	}
+	return members, meta, nil
+}
+
+// UpdateMemberRole changes the role of a member
+func (c *Client) UpdateMemberRole(ctx context.Context, orgID, userID uint, req *domain.MembershipUpdateRequest) (*domain.MemberResponse, error) {
+	var member domain.MemberResponse
+	if _, err := c.do(ctx, &request{
+		method: http.MethodPut,
+		path:   apiPrefix + "/organizations/" + idPath(orgID) + "/members/" + idPath(userID),
+		body:   req,
+	}, &member); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// RemoveMember removes a member from an organization
+func (c *Client) RemoveMember(ctx context.Context, orgID, userID uint) error {
+	_, err := c.do(ctx, &request{
+		method: http.MethodDelete,
+		path:   apiPrefix + "/organizations/" + idPath(orgID) + "/members/" + idPath(userID),
+	}, nil)
+	return err
+}
+
+// ListInvitations lists the pending invitations of an organization
+func (c *Client) ListInvitations(ctx context.Context, orgID uint) ([]*domain.InvitationResponse, error) {
+	var invitations []*domain.InvitationResponse
+	if _, err := c.do(ctx, &request{
+		method: http.MethodGet,
+		path:   apiPrefix + "/organizations/" + idPath(orgID) + "/invitations",
+	}, &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// InviteMember invites a user to an organization by email
+func (c *Client) InviteMember(ctx context.Context, orgID uint, req *domain.InvitationCreateRequest) (*domain.InvitationResponse, error) {
+	var invitation domain.InvitationResponse
+	if _, err := c.do(ctx, &request{
+		method: http.MethodPost,
+		path:   apiPrefix + "/organizations/" + idPath(orgID) + "/invitations",
+		body:   req,
+	}, &invitation); err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// RevokeInvitation revokes a pending invitation
+func (c *Client) RevokeInvitation(ctx context.Context, orgID, invitationID uint) error {
+	_, err := c.do(ctx, &request{
+		method: http.MethodDelete,
+		path:   apiPrefix + "/organizations/" + idPath(orgID) + "/invitations/" + idPath(invitationID),
+	}, nil)
+	return err
+}
+
+// AcceptInvitation joins the organization of an invitation
+func (c *Client) AcceptInvitation(ctx context.Context, req *domain.InvitationAcceptRequest) (*domain.OrganizationResponse, error) {
+	var org domain.OrganizationResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPost, path: apiPrefix + "/invitations/accept", body: req}, &org); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// ListProjects lists the caller's projects, or all of them with the
+// projects:manage permission, with pagination. Both arguments may be nil.
+func (c *Client) ListProjects(ctx context.Context, filter *domain.ProjectListFilter, page *domain.PaginationRequest) ([]*domain.ProjectResponse, *domain.Meta, error) {
+	var projects []*domain.ProjectResponse
+	meta, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/projects", query: pageQuery(page, filter)}, &projects)
+	if err != nil {
+		return nil, nil, err
+	}
+	return projects, meta, nil
+}
+
+// CreateProject creates a project owned by the caller
+func (c *Client) CreateProject(ctx context.Context, req *domain.ProjectCreateRequest) (*domain.ProjectResponse, error) {
+	var project domain.ProjectResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPost, path: apiPrefix + "/projects", body: req}, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetProject retrieves a project by ID
+func (c *Client) GetProject(ctx context.Context, id uint) (*domain.ProjectResponse, error) {
+	var project domain.ProjectResponse
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/projects/" + idPath(id)}, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// UpdateProject updates a project
+func (c *Client) UpdateProject(ctx context.Context, id uint, req *domain.ProjectUpdateRequest) (*domain.ProjectResponse, error) {
+	var project domain.ProjectResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPut, path: apiPrefix + "/projects/" + idPath(id), body: req}, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// DeleteProject deletes a project
+func (c *Client) DeleteProject(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, &request{method: http.MethodDelete, path: apiPrefix + "/projects/" + idPath(id)}, nil)
+	return err
+}
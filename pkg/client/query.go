@@ -0,0 +1,79 @@
+package client
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// queryOf encodes the form-tagged fields of the given structs, such as
+// domain.UserListFilter or domain.PaginationRequest, as query parameters.
+// Zero values are left out so the server applies its defaults.
+func queryOf(params ...any) url.Values {
+	values := url.Values{}
+	for _, p := range params {
+		v := reflect.ValueOf(p)
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				continue
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("form"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			if value, ok := formatQueryValue(v.Field(i)); ok {
+				values.Set(name, value)
+			}
+		}
+	}
+	return values
+}
+
+// formatQueryValue formats a field value, reporting false for zero values
+func formatQueryValue(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	} else if v.IsZero() {
+		return "", false
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339Nano), true
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	}
+	return "", false
+}
+
+// pageQuery encodes pagination parameters, using the server defaults when nil
+func pageQuery(page *domain.PaginationRequest, params ...any) url.Values {
+	return queryOf(append(params, page)...)
+}
+
+// idPath formats a numeric path segment
+func idPath(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
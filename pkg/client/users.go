@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+)
+
+// ListUsers lists users matching the filter with pagination. Both may be nil.
+func (c *Client) ListUsers(ctx context.Context, filter *domain.UserListFilter, page *domain.PaginationRequest) ([]*domain.UserResponse, *domain.Meta, error) {
+	var users []*domain.UserResponse
+	meta, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/users", query: pageQuery(page, filter)}, &users)
+	if err != nil {
+		return nil, nil, err
+	}
+	return users, meta, nil
+}
+
+// ListUsersByCursor lists users with keyset pagination; pass the returned
+// meta.NextCursor as page.After to continue
+func (c *Client) ListUsersByCursor(ctx context.Context, filter *domain.UserListFilter, page *domain.CursorPaginationRequest) ([]*domain.UserResponse, *domain.Meta, error) {
+	var users []*domain.UserResponse
+	meta, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/users", query: queryOf(filter, page)}, &users)
+	if err != nil {
+		return nil, nil, err
+	}
+	return users, meta, nil
+}
+
+// SearchUsers searches users by name or email with pagination
+func (c *Client) SearchUsers(ctx context.Context, q string, page *domain.PaginationRequest) ([]*domain.UserResponse, *domain.Meta, error) {
+	query := pageQuery(page)
+	query.Set("q", q)
+
+	var users []*domain.UserResponse
+	meta, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/users/search", query: query}, &users)
+	if err != nil {
+		return nil, nil, err
+	}
+	return users, meta, nil
+}
+
+// GetUser retrieves a user by ID
+func (c *Client) GetUser(ctx context.Context, id uint) (*domain.UserResponse, error) {
+	var user domain.UserResponse
+	if _, err := c.do(ctx, &request{method: http.MethodGet, path: apiPrefix + "/users/" + idPath(id)}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser updates a user
+func (c *Client) UpdateUser(ctx context.Context, id uint, req *domain.UserUpdateRequest) (*domain.UserResponse, error) {
+	var user domain.UserResponse
+	if _, err := c.do(ctx, &request{method: http.MethodPut, path: apiPrefix + "/users/" + idPath(id), body: req}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// PatchUser applies a JSON Merge Patch or JSON Patch to a user
+func (c *Client) PatchUser(ctx context.Context, id uint, patch *domain.Patch) (*domain.UserResponse, error) {
+	var user domain.UserResponse
+	if _, err := c.do(ctx, patchRequest(apiPrefix+"/users/"+idPath(id), patch), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteUser deletes a user
+func (c *Client) DeleteUser(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, &request{method: http.MethodDelete, path: apiPrefix + "/users/" + idPath(id)}, nil)
+	return err
+}
@@ -2,18 +2,20 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	applog "github.com/luxixing/fx-gin-scaffold/pkg/logger"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 // SQLiteConfig holds SQLite specific configuration
@@ -44,6 +46,12 @@ func (c PostgresConfig) GetDSN() string {
 // MongoConfig holds MongoDB specific configuration
 type MongoConfig struct {
 	URI string `json:"uri" yaml:"uri"`
+	// Database is the database of the application; DefaultMongoDatabase
+	// when empty
+	Database string `json:"database" yaml:"database"`
+	// ReadPreference is a mongo read preference mode such as secondaryPreferred
+	ReadPreference string        `json:"read_preference" yaml:"read_preference"`
+	MaxStaleness   time.Duration `json:"max_staleness" yaml:"max_staleness"`
 }
 
 // Config holds database configuration
@@ -52,17 +60,171 @@ type Config struct {
 	SQLite   SQLiteConfig   `json:"sqlite" yaml:"sqlite"`
 	Postgres PostgresConfig `json:"postgres" yaml:"postgres"`
 	Mongo    MongoConfig    `json:"mongo" yaml:"mongo"`
+	Replicas ReplicaConfig  `json:"replicas" yaml:"replicas"`
+	Pool     PoolConfig     `json:"pool" yaml:"pool"`
+	Retry    RetryConfig    `json:"retry" yaml:"retry"`
+	// SlowQueryThreshold logs GORM queries taking longer as warnings; zero
+	// disables slow query logging
+	SlowQueryThreshold time.Duration `json:"slow_query_threshold" yaml:"slow_query_threshold"`
+	// TablePrefix is prepended to the tables and collections of the application
+	TablePrefix string       `json:"table_prefix" yaml:"table_prefix"`
+	Naming      NamingConfig `json:"naming" yaml:"naming"`
 }
 
+// PoolConfig holds connection pool settings; zero values fall back to the driver defaults
+type PoolConfig struct {
+	MaxOpenConns    int           `json:"max_open_conns" yaml:"max_open_conns"`
+	MaxIdleConns    int           `json:"max_idle_conns" yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time" yaml:"conn_max_idle_time"`
+}
+
+var (
+	// SQLite doesn't support concurrent writes, so a single connection is the default
+	sqlitePoolDefaults = PoolConfig{
+		MaxOpenConns:    1,
+		MaxIdleConns:    1,
+		ConnMaxLifetime: time.Hour,
+	}
+	postgresPoolDefaults = PoolConfig{
+		MaxOpenConns:    25,
+		MaxIdleConns:    10,
+		ConnMaxLifetime: 5 * time.Minute,
+	}
+)
+
+// withDefaults fills unset fields from the driver defaults
+func (c PoolConfig) withDefaults(defaults PoolConfig) PoolConfig {
+	if c.MaxOpenConns == 0 {
+		c.MaxOpenConns = defaults.MaxOpenConns
+	}
+	if c.MaxIdleConns == 0 {
+		c.MaxIdleConns = defaults.MaxIdleConns
+	}
+	if c.ConnMaxLifetime == 0 {
+		c.ConnMaxLifetime = defaults.ConnMaxLifetime
+	}
+	if c.ConnMaxIdleTime == 0 {
+		c.ConnMaxIdleTime = defaults.ConnMaxIdleTime
+	}
+	return c
+}
+
+// apply configures sqlDB with the pool settings
+func (c PoolConfig) apply(sqlDB *sql.DB) {
+	sqlDB.SetMaxOpenConns(c.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(c.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(c.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+}
+
+// DefaultMongoDatabase is the MongoDB database used when none is configured
+const DefaultMongoDatabase = "fx_gin_scaffold"
+
 // Connection holds database connections
 type Connection struct {
 	GORM  *gorm.DB
 	Mongo *mongo.Client
+	// MongoDatabase names the database of the application on Mongo
+	MongoDatabase string
+	// TablePrefix is prepended to the tables and collections of the application
+	TablePrefix string
+}
+
+// TableName returns the name of a table or collection with the prefix
+func (c *Connection) TableName(name string) string {
+	return c.TablePrefix + name
+}
+
+// MongoDB returns the MongoDB database of the application
+func (c *Connection) MongoDB() *mongo.Database {
+	if c.MongoDatabase == "" {
+		return c.Mongo.Database(DefaultMongoDatabase)
+	}
+	return c.Mongo.Database(c.MongoDatabase)
+}
+
+// RetryConfig controls how long NewConnection keeps retrying an unreachable database
+type RetryConfig struct {
+	// MaxWait bounds the total time spent retrying; zero makes a single attempt
+	MaxWait        time.Duration `json:"max_wait" yaml:"max_wait"`
+	InitialBackoff time.Duration `json:"initial_backoff" yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `json:"max_backoff" yaml:"max_backoff"`
+}
+
+// withDefaults fills unset backoff bounds
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = 500 * time.Millisecond
+	}
+	if c.MaxBackoff < c.InitialBackoff {
+		c.MaxBackoff = max(10*time.Second, c.InitialBackoff)
+	}
+	return c
 }
 
-// NewConnection creates database connections based on configuration
+// NewConnection creates database connections based on configuration, retrying with
+// exponential backoff while the database is unreachable for up to cfg.Retry.MaxWait.
 func NewConnection(cfg Config) (*Connection, error) {
-	conn := &Connection{}
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	retry := cfg.Retry.withDefaults()
+	deadline := time.Now().Add(retry.MaxWait)
+	backoff := retry.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		conn, err := connect(cfg)
+		if err == nil {
+			if attempt > 1 {
+				applog.Named(applog.ModuleDB).Info("Database connected",
+					zap.String("driver", cfg.Driver),
+					zap.Int("attempts", attempt))
+			}
+			return conn, nil
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			applog.Named(applog.ModuleDB).Error("Database connection failed",
+				zap.String("driver", cfg.Driver),
+				zap.Int("attempts", attempt),
+				zap.Error(err))
+			return nil, err
+		}
+
+		applog.Named(applog.ModuleDB).Warn("Database connection attempt failed, retrying",
+			zap.String("driver", cfg.Driver),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+		time.Sleep(backoff)
+		backoff = min(backoff*2, retry.MaxBackoff)
+	}
+}
+
+// validateConfig rejects configuration errors that retrying cannot fix
+func validateConfig(cfg Config) error {
+	switch cfg.Driver {
+	case "sqlite", "postgres":
+		if len(cfg.Replicas.DSNs) > 0 {
+			if _, err := newReplicaPolicy(cfg.Replicas.Policy); err != nil {
+				return err
+			}
+		}
+	case "mongo":
+		if _, err := mongoReadPreference(cfg.Mongo); err != nil {
+			return fmt.Errorf("invalid read preference: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported database driver: %s", cfg.Driver)
+	}
+	return nil
+}
+
+// connect makes a single connection attempt for the configured driver
+func connect(cfg Config) (*Connection, error) {
+	conn := &Connection{TablePrefix: cfg.TablePrefix}
 
 	switch cfg.Driver {
 	case "sqlite":
@@ -85,6 +247,7 @@ func NewConnection(cfg Config) (*Connection, error) {
 			return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
 		}
 		conn.Mongo = mongoDB
+		conn.MongoDatabase = cfg.Mongo.Database
 
 	default:
 		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
@@ -101,9 +264,7 @@ func connectSQLite(cfg Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
-	db, err := gorm.Open(sqlite.Open(cfg.SQLite.Path), &gorm.Config{
-		Logger: newGormLogger(),
-	})
+	db, err := gorm.Open(sqlite.Open(cfg.SQLite.Path), newGormConfig(cfg))
 	if err != nil {
 		return nil, err
 	}
@@ -114,10 +275,12 @@ func connectSQLite(cfg Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	// SQLite specific settings
-	sqlDB.SetMaxOpenConns(1) // SQLite doesn't support concurrent writes
-	sqlDB.SetMaxIdleConns(1)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	pool := cfg.Pool.withDefaults(sqlitePoolDefaults)
+	pool.apply(sqlDB)
+
+	if err := useReplicas(db, cfg, sqlite.Open, pool); err != nil {
+		return nil, err
+	}
 
 	return db, nil
 }
@@ -126,9 +289,7 @@ func connectSQLite(cfg Config) (*gorm.DB, error) {
 func connectPostgres(cfg Config) (*gorm.DB, error) {
 	dsn := cfg.Postgres.GetDSN()
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: newGormLogger(),
-	})
+	db, err := gorm.Open(postgres.Open(dsn), newGormConfig(cfg))
 	if err != nil {
 		return nil, err
 	}
@@ -139,52 +300,124 @@ func connectPostgres(cfg Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+	pool := cfg.Pool.withDefaults(postgresPoolDefaults)
+	pool.apply(sqlDB)
+
+	if err := useReplicas(db, cfg, postgres.Open, pool); err != nil {
+		return nil, err
+	}
 
 	return db, nil
 }
 
+// useReplicas opens the configured replicas and registers the read resolver on db
+func useReplicas(db *gorm.DB, cfg Config, open func(dsn string) gorm.Dialector, pool PoolConfig) error {
+	if len(cfg.Replicas.DSNs) == 0 {
+		return nil
+	}
+
+	policy, err := newReplicaPolicy(cfg.Replicas.Policy)
+	if err != nil {
+		return err
+	}
+
+	r := &resolver{policy: policy}
+	for i, dsn := range cfg.Replicas.DSNs {
+		replica, err := gorm.Open(open(dsn), newGormConfig(cfg))
+		if err != nil {
+			closeReplicas(r.replicas)
+			return fmt.Errorf("failed to connect to replica %d: %w", i, err)
+		}
+		sqlDB, err := replica.DB()
+		if err != nil {
+			closeReplicas(r.replicas)
+			return err
+		}
+		pool.apply(sqlDB)
+		r.replicas = append(r.replicas, sqlDB)
+	}
+
+	if err := db.Use(r); err != nil {
+		closeReplicas(r.replicas)
+		return err
+	}
+	return nil
+}
+
+// closeReplicas closes replica pools opened by useReplicas
+func closeReplicas(replicas []gorm.ConnPool) []error {
+	var errs []error
+	for _, replica := range replicas {
+		if sqlDB, ok := replica.(*sql.DB); ok {
+			if err := sqlDB.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+// replicasOf returns the replica pools registered on db
+func replicasOf(db *gorm.DB) []gorm.ConnPool {
+	if r, ok := db.Config.Plugins[(&resolver{}).Name()].(*resolver); ok {
+		return r.replicas
+	}
+	return nil
+}
+
 // connectMongo establishes MongoDB connection
 func connectMongo(cfg Config) (*mongo.Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	clientOptions := options.Client().ApplyURI(cfg.Mongo.URI)
+	readPreference, err := mongoReadPreference(cfg.Mongo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid read preference: %w", err)
+	}
+
+	clientOptions := options.Client().
+		ApplyURI(cfg.Mongo.URI).
+		SetReadPreference(readPreference).
+		SetPoolMonitor(newMongoPoolMonitor())
+	if cfg.Pool.MaxOpenConns > 0 {
+		clientOptions.SetMaxPoolSize(uint64(cfg.Pool.MaxOpenConns))
+	}
+	if cfg.Pool.ConnMaxIdleTime > 0 {
+		clientOptions.SetMaxConnIdleTime(cfg.Pool.ConnMaxIdleTime)
+	}
 
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	// Test the connection
-	err = client.Ping(ctx, nil)
+	// Test the connection against the primary; reads may still go to secondaries
+	err = client.Ping(ctx, readpref.Primary())
 	if err != nil {
+		_ = client.Disconnect(context.Background())
 		return nil, err
 	}
 
 	return client, nil
 }
 
-// newGormLogger creates a custom GORM logger that integrates with zap
-func newGormLogger() logger.Interface {
-	return logger.New(
-		&gormLogWriter{},
-		logger.Config{
-			SlowThreshold:             time.Second,
-			LogLevel:                  logger.Info,
-			IgnoreRecordNotFoundError: true,
-			Colorful:                  false,
-		},
-	)
-}
-
-// gormLogWriter implements GORM's logger.Writer interface using zap
-type gormLogWriter struct{}
+// newGormConfig returns the GORM configuration shared by primaries and
+// replicas. Timestamps set by GORM are stored in UTC whatever the server's
+// local time zone is.
+func newGormConfig(cfg Config) *gorm.Config {
+	// PostgreSQL quotes values with single quotes, SQLite with double quotes
+	quote := byte('"')
+	if cfg.Driver == "postgres" {
+		quote = '\''
+	}
 
-func (w *gormLogWriter) Printf(format string, args ...any) {
-	zap.L().Info(fmt.Sprintf(format, args...))
+	return &gorm.Config{
+		Logger:         newGormLogger(cfg.SlowQueryThreshold, quote),
+		NamingStrategy: NewNamingStrategy(cfg),
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
 }
 
 // Close gracefully closes database connections
@@ -198,6 +431,9 @@ func (c *Connection) Close() error {
 				errors = append(errors, fmt.Errorf("failed to close GORM connection: %w", err))
 			}
 		}
+		for _, err := range closeReplicas(replicasOf(c.GORM)) {
+			errors = append(errors, fmt.Errorf("failed to close replica connection: %w", err))
+		}
 	}
 
 	if c.Mongo != nil {
@@ -235,11 +471,21 @@ func (c *Connection) Health(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		return sqlDB.PingContext(ctx)
+		if err := sqlDB.PingContext(ctx); err != nil {
+			return err
+		}
+		for i, replica := range replicasOf(c.GORM) {
+			if pinger, ok := replica.(interface{ PingContext(context.Context) error }); ok {
+				if err := pinger.PingContext(ctx); err != nil {
+					return fmt.Errorf("replica %d: %w", i, err)
+				}
+			}
+		}
+		return nil
 	}
 
 	if c.Mongo != nil {
-		return c.Mongo.Ping(ctx, nil)
+		return c.Mongo.Ping(ctx, readpref.Primary())
 	}
 
 	return fmt.Errorf("no database connection available")
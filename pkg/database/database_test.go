@@ -0,0 +1,58 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolConfigWithDefaults(t *testing.T) {
+	pool := PoolConfig{MaxOpenConns: 50, ConnMaxIdleTime: time.Minute}.withDefaults(postgresPoolDefaults)
+
+	assert.Equal(t, 50, pool.MaxOpenConns)
+	assert.Equal(t, 10, pool.MaxIdleConns)
+	assert.Equal(t, 5*time.Minute, pool.ConnMaxLifetime)
+	assert.Equal(t, time.Minute, pool.ConnMaxIdleTime)
+}
+
+func TestNewConnectionAppliesPool(t *testing.T) {
+	conn, err := NewConnection(Config{
+		Driver: "sqlite",
+		SQLite: SQLiteConfig{Path: filepath.Join(t.TempDir(), "app.db")},
+		Pool:   PoolConfig{MaxOpenConns: 4},
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sqlDB, err := conn.GORM.DB()
+	require.NoError(t, err)
+	assert.Equal(t, 4, sqlDB.Stats().MaxOpenConnections)
+}
+
+func TestNewConnectionRetriesUntilMaxWait(t *testing.T) {
+	// A regular file in place of the parent directory makes every attempt fail
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	require.NoError(t, os.WriteFile(blocker, nil, 0o600))
+
+	start := time.Now()
+	_, err := NewConnection(Config{
+		Driver: "sqlite",
+		SQLite: SQLiteConfig{Path: filepath.Join(blocker, "app.db")},
+		Retry:  RetryConfig{MaxWait: 100 * time.Millisecond, InitialBackoff: 20 * time.Millisecond},
+	})
+
+	assert.Error(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond, "should back off 20ms then 40ms")
+}
+
+func TestNewConnectionRejectsInvalidConfigWithoutRetrying(t *testing.T) {
+	start := time.Now()
+	_, err := NewConnection(Config{Driver: "oracle", Retry: RetryConfig{MaxWait: time.Minute}})
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,207 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	applog "github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// redactedArg replaces query arguments that may hold personal data or secrets
+const redactedArg = "[redacted]"
+
+// QueryStats counts the GORM queries run with a context, e.g. while serving
+// a request, so N+1 query patterns show up in access logs
+type QueryStats struct {
+	count    atomic.Int64
+	duration atomic.Int64
+}
+
+// Count returns the number of queries
+func (s *QueryStats) Count() int64 {
+	return s.count.Load()
+}
+
+// Duration returns the total time spent in queries
+func (s *QueryStats) Duration() time.Duration {
+	return time.Duration(s.duration.Load())
+}
+
+func (s *QueryStats) add(elapsed time.Duration) {
+	s.count.Add(1)
+	s.duration.Add(int64(elapsed))
+}
+
+type queryStatsKey struct{}
+
+// WithQueryStats returns a context counting the GORM queries run with it
+func WithQueryStats(ctx context.Context) (context.Context, *QueryStats) {
+	stats := &QueryStats{}
+	return context.WithValue(ctx, queryStatsKey{}, stats), stats
+}
+
+// QueryStatsFrom returns the query stats of ctx, or nil if it has none
+func QueryStatsFrom(ctx context.Context) *QueryStats {
+	stats, _ := ctx.Value(queryStatsKey{}).(*QueryStats)
+	return stats
+}
+
+// gormLogger logs GORM queries with the db module logger as structured
+// fields: failed queries as errors, queries slower than slowThreshold as
+// warnings and the others at debug level. String and binary arguments are
+// redacted.
+type gormLogger struct {
+	slowThreshold time.Duration
+	level         logger.LogLevel
+	// quote is the character the dialect quotes interpolated values with
+	quote byte
+
+	// args passes the arguments of the query being explained from
+	// ParamsFilter back to Trace
+	args *queryArgs
+}
+
+// queryArgs holds the arguments of the query being explained
+type queryArgs struct {
+	mu       sync.Mutex
+	values   []any
+	filtered bool
+}
+
+// newGormLogger creates a GORM logger that integrates with zap for a
+// dialect quoting values with quote
+func newGormLogger(slowThreshold time.Duration, quote byte) logger.Interface {
+	return &gormLogger{
+		slowThreshold: slowThreshold,
+		level:         logger.Info,
+		quote:         quote,
+		args:          &queryArgs{},
+	}
+}
+
+// LogMode returns a copy of the logger logging at level
+func (l *gormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Info {
+		applog.Named(applog.ModuleDB).Info(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Warn {
+		applog.Named(applog.ModuleDB).Warn(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Error {
+		applog.Named(applog.ModuleDB).Error(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace logs a query and adds it to the query stats of ctx
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	slow := l.slowThreshold > 0 && elapsed > l.slowThreshold
+	recordQuery(elapsed, failed, slow)
+	if stats := QueryStatsFrom(ctx); stats != nil {
+		stats.add(elapsed)
+	}
+
+	if l.level <= logger.Silent {
+		return
+	}
+
+	log := applog.Named(applog.ModuleDB)
+	switch {
+	case failed && l.level >= logger.Error:
+		log.Error("query failed", append(l.queryFields(fc, elapsed), zap.Error(err))...)
+	case slow && l.level >= logger.Warn:
+		log.Warn("slow query", append(l.queryFields(fc, elapsed), zap.Duration("threshold", l.slowThreshold))...)
+	case l.level >= logger.Info && log.Core().Enabled(zap.DebugLevel):
+		log.Debug("query", l.queryFields(fc, elapsed)...)
+	}
+}
+
+// ParamsFilter keeps the arguments out of the explained SQL and hands them,
+// redacted, to Trace
+func (l *gormLogger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	l.args.values = redactArgs(params)
+	l.args.filtered = true
+	return sql, nil
+}
+
+// queryFields explains the query and returns its fields
+func (l *gormLogger) queryFields(fc func() (string, int64), elapsed time.Duration) []zap.Field {
+	// fc calls ParamsFilter; the lock keeps concurrent queries' arguments apart
+	l.args.mu.Lock()
+	l.args.values, l.args.filtered = nil, false
+	sql, rows := fc()
+	args, filtered := l.args.values, l.args.filtered
+	l.args.mu.Unlock()
+
+	// Scan explains queries itself, with the arguments in the SQL
+	if !filtered {
+		sql = redactLiterals(sql, l.quote)
+	}
+
+	fields := []zap.Field{
+		zap.String("query", sql),
+		zap.Any("args", args),
+		zap.Duration("duration", elapsed),
+	}
+	if rows >= 0 {
+		fields = append(fields, zap.Int64("rows", rows))
+	}
+	return fields
+}
+
+// redactArgs keeps numbers, booleans, times and nulls, which help debugging,
+// and redacts the rest
+func redactArgs(params []any) []any {
+	args := make([]any, len(params))
+	for i, param := range params {
+		switch param.(type) {
+		case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, time.Time, *time.Time:
+			args[i] = param
+		default:
+			args[i] = redactedArg
+		}
+	}
+	return args
+}
+
+// redactLiterals redacts the quoted values in sql. gorm escapes quotes
+// inside values with a backslash.
+func redactLiterals(sql string, quote byte) string {
+	var b strings.Builder
+	for i := 0; i < len(sql); i++ {
+		if sql[i] != quote {
+			b.WriteByte(sql[i])
+			continue
+		}
+		for i++; i < len(sql) && sql[i] != quote; i++ {
+			if sql[i] == '\\' {
+				i++
+			}
+		}
+		b.WriteByte(quote)
+		b.WriteString(redactedArg)
+		b.WriteByte(quote)
+	}
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactArgs(t *testing.T) {
+	at := time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)
+
+	args := redactArgs([]any{42, uint(7), true, nil, at, "alice@example.com", []byte("hash")})
+
+	assert.Equal(t, []any{42, uint(7), true, nil, at, redactedArg, redactedArg}, args)
+}
+
+func TestRedactLiterals(t *testing.T) {
+	assert.Equal(t, `SELECT * FROM users WHERE email = "[redacted]" AND id = 1`,
+		redactLiterals(`SELECT * FROM users WHERE email = "a\"b@example.com" AND id = 1`, '"'))
+	assert.Equal(t, `SELECT * FROM "users" WHERE name = '[redacted]'`,
+		redactLiterals(`SELECT * FROM "users" WHERE name = 'O\'Brien'`, '\''))
+}
+
+func TestGormLoggerSlowQueries(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+	require.NoError(t, logger.Initialize(logger.Config{Level: "info", Output: logPath}))
+
+	conn, err := NewConnection(Config{
+		Driver:             "sqlite",
+		SQLite:             SQLiteConfig{Path: filepath.Join(dir, "app.db")},
+		SlowQueryThreshold: time.Nanosecond,
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, stats := WithQueryStats(context.Background())
+	require.NoError(t, conn.GORM.WithContext(ctx).Exec("SELECT ?, ?", "secret", 42).Error)
+	var count int
+	require.NoError(t, conn.GORM.WithContext(ctx).Raw("SELECT count(*) FROM sqlite_master WHERE name = ?", "secret").Scan(&count).Error)
+
+	assert.Equal(t, int64(2), stats.Count())
+	assert.Positive(t, stats.Duration())
+	assert.Nil(t, QueryStatsFrom(context.Background()))
+
+	require.NoError(t, logger.Named(logger.ModuleDB).Sync())
+	file, err := os.Open(logPath)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var slow []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		if entry["msg"] == "slow query" {
+			slow = append(slow, entry)
+		}
+	}
+
+	require.Len(t, slow, 2)
+	assert.Equal(t, "db", slow[0]["logger"])
+	assert.Equal(t, "SELECT ?, ?", slow[0]["query"])
+	assert.Equal(t, []any{redactedArg, float64(42)}, slow[0]["args"])
+	assert.Contains(t, slow[0], "duration")
+	assert.Equal(t, `SELECT count(*) FROM sqlite_master WHERE name = "[redacted]"`, slow[1]["query"])
+}
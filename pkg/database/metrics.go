@@ -0,0 +1,170 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/metrics"
+	"go.mongodb.org/mongo-driver/event"
+	"gorm.io/gorm"
+)
+
+// Query metrics, updated as GORM queries run
+var (
+	queriesTotal = metrics.Default.NewCounter("db_queries_total",
+		"GORM queries run, by status (ok or error).", "status")
+	queryDurationTotal = metrics.Default.NewCounter("db_query_duration_seconds_total",
+		"Time spent running GORM queries.").With()
+	slowQueriesTotal = metrics.Default.NewCounter("db_slow_queries_total",
+		"GORM queries slower than the slow query threshold.").With()
+)
+
+// SQL connection pool metrics, refreshed by StatsCollector. The pool label
+// is primary or replica_N.
+var (
+	poolOpenConnections = metrics.Default.NewGauge("db_pool_open_connections",
+		"Established connections, in use and idle.", "pool")
+	poolInUseConnections = metrics.Default.NewGauge("db_pool_in_use_connections",
+		"Connections currently in use.", "pool")
+	poolIdleConnections = metrics.Default.NewGauge("db_pool_idle_connections",
+		"Idle connections.", "pool")
+	poolMaxOpenConnections = metrics.Default.NewGauge("db_pool_max_open_connections",
+		"Maximum number of open connections.", "pool")
+	poolWaitCount = metrics.Default.NewGauge("db_pool_wait_count",
+		"Connections waited for since the pool was opened.", "pool")
+	poolWaitDuration = metrics.Default.NewGauge("db_pool_wait_duration_seconds",
+		"Time spent waiting for connections since the pool was opened.", "pool")
+)
+
+// MongoDB connection pool metrics, updated from pool events. The address
+// label is the server the pool connects to.
+var (
+	mongoPoolOpenConnections = metrics.Default.NewGauge("mongo_pool_open_connections",
+		"Established MongoDB connections, in use and idle.", "address")
+	mongoPoolInUseConnections = metrics.Default.NewGauge("mongo_pool_in_use_connections",
+		"MongoDB connections currently checked out.", "address")
+	mongoPoolCheckoutFailuresTotal = metrics.Default.NewCounter("mongo_pool_checkout_failures_total",
+		"Failed MongoDB connection checkouts.", "address")
+)
+
+// recordQuery updates the query metrics
+func recordQuery(elapsed time.Duration, failed, slow bool) {
+	status := "ok"
+	if failed {
+		status = "error"
+	}
+	queriesTotal.With(status).Inc()
+	queryDurationTotal.Add(elapsed.Seconds())
+	if slow {
+		slowQueriesTotal.Inc()
+	}
+}
+
+// recordPoolStats sets the pool gauges from stats
+func recordPoolStats(pool string, stats sql.DBStats) {
+	poolOpenConnections.With(pool).Set(float64(stats.OpenConnections))
+	poolInUseConnections.With(pool).Set(float64(stats.InUse))
+	poolIdleConnections.With(pool).Set(float64(stats.Idle))
+	poolMaxOpenConnections.With(pool).Set(float64(stats.MaxOpenConnections))
+	poolWaitCount.With(pool).Set(float64(stats.WaitCount))
+	poolWaitDuration.With(pool).Set(stats.WaitDuration.Seconds())
+}
+
+// newMongoPoolMonitor tracks MongoDB pool events in the pool metrics
+func newMongoPoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			switch e.Type {
+			case event.ConnectionCreated:
+				mongoPoolOpenConnections.With(e.Address).Add(1)
+			case event.ConnectionClosed:
+				mongoPoolOpenConnections.With(e.Address).Add(-1)
+			case event.GetSucceeded:
+				mongoPoolInUseConnections.With(e.Address).Add(1)
+			case event.ConnectionReturned:
+				mongoPoolInUseConnections.With(e.Address).Add(-1)
+			case event.GetFailed:
+				mongoPoolCheckoutFailuresTotal.With(e.Address).Inc()
+			}
+		},
+	}
+}
+
+// StatsCollector periodically copies the SQL connection pool statistics of
+// the primary and replicas into the pool metrics. MongoDB pool metrics are
+// updated as events happen and need no collector.
+type StatsCollector struct {
+	db       *gorm.DB
+	interval time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewStatsCollector creates a collector for conn refreshing every interval
+func NewStatsCollector(conn *Connection, interval time.Duration) *StatsCollector {
+	return &StatsCollector{
+		db:       conn.GORM,
+		interval: interval,
+	}
+}
+
+// Collect refreshes the pool metrics once
+func (c *StatsCollector) Collect() {
+	if c.db == nil {
+		return
+	}
+
+	if sqlDB, err := c.db.DB(); err == nil {
+		recordPoolStats("primary", sqlDB.Stats())
+	}
+	for i, replica := range replicasOf(c.db) {
+		if sqlDB, ok := replica.(*sql.DB); ok {
+			recordPoolStats(fmt.Sprintf("replica_%d", i), sqlDB.Stats())
+		}
+	}
+}
+
+// Start collects now and then every interval until Stop is called
+func (c *StatsCollector) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.db == nil || c.stop != nil {
+		return
+	}
+
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go func(stop <-chan struct{}, done chan<- struct{}) {
+		defer close(done)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		c.Collect()
+		for {
+			select {
+			case <-ticker.C:
+				c.Collect()
+			case <-stop:
+				return
+			}
+		}
+	}(c.stop, c.done)
+}
+
+// Stop stops collecting and waits for the collector to finish
+func (c *StatsCollector) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop == nil {
+		return
+	}
+
+	close(c.stop)
+	<-c.done
+	c.stop = nil
+}
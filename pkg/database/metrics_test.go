@@ -0,0 +1,31 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsCollector(t *testing.T) {
+	conn := newReplicatedConnection(t, "random")
+	firstName(t, conn.GORM)
+
+	collector := NewStatsCollector(conn, time.Hour)
+	collector.Start()
+	collector.Stop()
+	collector.Stop()
+
+	var b strings.Builder
+	_, err := metrics.Default.WriteTo(&b)
+	require.NoError(t, err)
+
+	out := b.String()
+	assert.Contains(t, out, `db_pool_max_open_connections{pool="primary"} 1`)
+	assert.Contains(t, out, `db_pool_open_connections{pool="replica_0"}`)
+	assert.Contains(t, out, `db_queries_total{status="ok"}`)
+	assert.Contains(t, out, "# TYPE db_query_duration_seconds_total counter")
+}
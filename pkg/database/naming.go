@@ -0,0 +1,50 @@
+package database
+
+import (
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// NamingConfig controls how tables, collections and columns are named after
+// models and their fields
+type NamingConfig struct {
+	// SingularTables names tables after models without pluralizing, e.g.
+	// user instead of users
+	SingularTables bool `json:"singular_tables" yaml:"singular_tables"`
+	// Columns overrides column names. Keys are default column names, for
+	// every table, or qualified by the table without prefix, e.g.
+	// users.avatar_url.
+	Columns map[string]string `json:"columns" yaml:"columns"`
+}
+
+// NamingStrategy is the GORM naming strategy of the configuration. Tables
+// and collections of models are named by its TableName from the model's
+// type name.
+type NamingStrategy struct {
+	schema.NamingStrategy
+	columns map[string]string
+}
+
+// NewNamingStrategy returns the naming strategy of the configuration
+func NewNamingStrategy(cfg Config) NamingStrategy {
+	return NamingStrategy{
+		NamingStrategy: schema.NamingStrategy{
+			TablePrefix:   cfg.TablePrefix,
+			SingularTable: cfg.Naming.SingularTables,
+		},
+		columns: cfg.Naming.Columns,
+	}
+}
+
+// ColumnName returns the configured name of a column, or the default one
+func (ns NamingStrategy) ColumnName(table, column string) string {
+	name := ns.NamingStrategy.ColumnName(table, column)
+	if override, ok := ns.columns[strings.TrimPrefix(table, ns.TablePrefix)+"."+name]; ok {
+		return override
+	}
+	if override, ok := ns.columns[name]; ok {
+		return override
+	}
+	return name
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type namingModel struct {
+	ID        uint
+	AvatarURL string
+	Name      string
+}
+
+// TestNamingStrategy tests that tables and columns are named as configured
+func TestNamingStrategy(t *testing.T) {
+	ns := NewNamingStrategy(Config{TablePrefix: "fx_"})
+	assert.Equal(t, "fx_users", ns.TableName("User"))
+	assert.Equal(t, "fx_webhook_deliveries", ns.TableName("WebhookDelivery"))
+	assert.Equal(t, "avatar_url", ns.ColumnName("fx_users", "AvatarURL"))
+
+	ns = NewNamingStrategy(Config{
+		TablePrefix: "fx_",
+		Naming: NamingConfig{
+			SingularTables: true,
+			Columns:        map[string]string{"users.avatar_url": "avatar", "name": "full_name"},
+		},
+	})
+	assert.Equal(t, "fx_user", ns.TableName("User"))
+	assert.Equal(t, "avatar", ns.ColumnName("fx_users", "AvatarURL"))
+	assert.Equal(t, "avatar_url", ns.ColumnName("fx_projects", "AvatarURL"))
+	assert.Equal(t, "full_name", ns.ColumnName("fx_projects", "Name"))
+}
+
+// TestNamingStrategyAppliesToGORM tests that GORM creates tables and
+// columns with the naming strategy
+func TestNamingStrategyAppliesToGORM(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+		NamingStrategy: NewNamingStrategy(Config{
+			TablePrefix: "app_",
+			Naming: NamingConfig{
+				SingularTables: true,
+				Columns:        map[string]string{"naming_model.avatar_url": "avatar"},
+			},
+		}),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&namingModel{}))
+
+	assert.True(t, db.Migrator().HasTable("app_naming_model"))
+	assert.True(t, db.Migrator().HasColumn(&namingModel{}, "avatar"))
+	assert.True(t, db.Migrator().HasColumn(&namingModel{}, "name"))
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"gorm.io/gorm"
+)
+
+// Replica selection policies
+const (
+	ReplicaPolicyRandom     = "random"
+	ReplicaPolicyRoundRobin = "round_robin"
+)
+
+// ReplicaConfig holds read replica settings for the SQL drivers
+type ReplicaConfig struct {
+	// DSNs are opened with the primary's driver: file paths for SQLite, DSNs for PostgreSQL
+	DSNs   []string `json:"dsns" yaml:"dsns"`
+	Policy string   `json:"policy" yaml:"policy"`
+}
+
+type primaryContextKey struct{}
+
+// WithPrimary marks ctx so that reads made with it are served by the primary.
+// Use it for reads that must observe a write made moments earlier.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// usePrimary reports whether ctx was marked by WithPrimary
+func usePrimary(ctx context.Context) bool {
+	forced, _ := ctx.Value(primaryContextKey{}).(bool)
+	return forced
+}
+
+// Collection returns coll with a primary read preference when ctx was marked by WithPrimary
+func Collection(ctx context.Context, coll *mongo.Collection) *mongo.Collection {
+	if !usePrimary(ctx) {
+		return coll
+	}
+	primary, err := coll.Clone(options.Collection().SetReadPreference(readpref.Primary()))
+	if err != nil {
+		return coll
+	}
+	return primary
+}
+
+// replicaPolicy picks the replica that serves a read
+type replicaPolicy func(replicas []gorm.ConnPool) gorm.ConnPool
+
+// newReplicaPolicy returns the selection policy registered under name
+func newReplicaPolicy(name string) (replicaPolicy, error) {
+	switch name {
+	case "", ReplicaPolicyRandom:
+		return func(replicas []gorm.ConnPool) gorm.ConnPool {
+			return replicas[rand.Intn(len(replicas))]
+		}, nil
+	case ReplicaPolicyRoundRobin:
+		var next atomic.Uint64
+		return func(replicas []gorm.ConnPool) gorm.ConnPool {
+			return replicas[(next.Add(1)-1)%uint64(len(replicas))]
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported replica policy: %s (supported: random, round_robin)", name)
+	}
+}
+
+// resolver is a GORM plugin that routes reads to replicas and leaves writes on the primary
+type resolver struct {
+	replicas []gorm.ConnPool
+	policy   replicaPolicy
+}
+
+// Name implements gorm.Plugin
+func (r *resolver) Name() string {
+	return "database:resolver"
+}
+
+// Initialize implements gorm.Plugin. Only model queries (Find, First, Count, ...) are routed;
+// raw rows stay on the primary so migrator introspection never reads a lagging schema.
+func (r *resolver) Initialize(db *gorm.DB) error {
+	return db.Callback().Query().Before("gorm:query").Register("database:resolver", r.switchReplica)
+}
+
+// switchReplica points a read statement at a replica unless it must stay on the primary:
+// inside a transaction, when locking rows, or when the context was marked by WithPrimary.
+func (r *resolver) switchReplica(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	if _, locking := db.Statement.Clauses["FOR"]; locking {
+		return
+	}
+	if ctx := db.Statement.Context; ctx != nil && usePrimary(ctx) {
+		return
+	}
+	db.Statement.ConnPool = r.policy(r.replicas)
+}
+
+// mongoReadPreference builds the client read preference from configuration
+func mongoReadPreference(cfg MongoConfig) (*readpref.ReadPref, error) {
+	if cfg.ReadPreference == "" {
+		return readpref.Primary(), nil
+	}
+	mode, err := readpref.ModeFromString(cfg.ReadPreference)
+	if err != nil {
+		return nil, err
+	}
+	var opts []readpref.Option
+	if cfg.MaxStaleness > 0 {
+		opts = append(opts, readpref.WithMaxStaleness(cfg.MaxStaleness))
+	}
+	return readpref.New(mode, opts...)
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type item struct {
+	ID   uint
+	Name string
+}
+
+func newReplicatedConnection(t *testing.T, policy string) *Connection {
+	t.Helper()
+	dir := t.TempDir()
+
+	// Seed the replica separately so reads reveal which database served them
+	replicaPath := filepath.Join(dir, "replica.db")
+	replica, err := NewConnection(Config{Driver: "sqlite", SQLite: SQLiteConfig{Path: replicaPath}})
+	require.NoError(t, err)
+	require.NoError(t, replica.GORM.AutoMigrate(&item{}))
+	require.NoError(t, replica.GORM.Create(&item{Name: "replica"}).Error)
+	require.NoError(t, replica.Close())
+
+	conn, err := NewConnection(Config{
+		Driver:   "sqlite",
+		SQLite:   SQLiteConfig{Path: filepath.Join(dir, "primary.db")},
+		Replicas: ReplicaConfig{DSNs: []string{replicaPath}, Policy: policy},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	require.NoError(t, conn.GORM.AutoMigrate(&item{}))
+	require.NoError(t, conn.GORM.Create(&item{Name: "primary"}).Error)
+	return conn
+}
+
+func firstName(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+	var it item
+	require.NoError(t, db.First(&it).Error)
+	return it.Name
+}
+
+func TestReplicaRouting(t *testing.T) {
+	conn := newReplicatedConnection(t, ReplicaPolicyRoundRobin)
+	ctx := context.Background()
+
+	assert.Equal(t, "replica", firstName(t, conn.GORM.WithContext(ctx)))
+	assert.Equal(t, "primary", firstName(t, conn.GORM.WithContext(WithPrimary(ctx))))
+	assert.Equal(t, "primary", firstName(t, conn.GORM.Clauses(clause.Locking{Strength: "UPDATE"})), "locking reads stay on the primary")
+
+	err := conn.GORM.Transaction(func(tx *gorm.DB) error {
+		assert.Equal(t, "primary", firstName(t, tx))
+		return nil
+	})
+	require.NoError(t, err)
+
+	var count int64
+	require.NoError(t, conn.GORM.WithContext(WithPrimary(ctx)).Model(&item{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count, "writes go to the primary")
+
+	assert.NoError(t, conn.Health(ctx))
+}
+
+func TestReplicaPolicy(t *testing.T) {
+	_, err := newReplicaPolicy("fastest")
+	assert.Error(t, err)
+
+	policy, err := newReplicaPolicy(ReplicaPolicyRoundRobin)
+	require.NoError(t, err)
+	a, b := &gorm.PreparedStmtDB{}, &gorm.PreparedStmtDB{}
+	replicas := []gorm.ConnPool{a, b}
+	assert.Same(t, a, policy(replicas))
+	assert.Same(t, b, policy(replicas))
+	assert.Same(t, a, policy(replicas))
+}
@@ -0,0 +1,136 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Event is a named occurrence published on the bus
+type Event interface {
+	// EventName returns the name subscribers register for
+	EventName() string
+}
+
+// Handler handles a published event
+type Handler func(ctx context.Context, event Event) error
+
+// Subscription binds a handler to an event name
+type Subscription struct {
+	Event   string
+	Handler Handler
+
+	// Async handlers run in the background after Publish returns; their
+	// errors are logged instead of returned
+	Async bool
+}
+
+// Subscriber declares the events it handles
+type Subscriber interface {
+	Subscriptions() []Subscription
+}
+
+// On subscribes a handler typed to the event E
+func On[E Event](handler func(ctx context.Context, event E) error) Subscription {
+	var zero E
+	return Subscription{
+		Event: zero.EventName(),
+		Handler: func(ctx context.Context, event Event) error {
+			return handler(ctx, event.(E))
+		},
+	}
+}
+
+// OnAsync subscribes a handler typed to the event E that runs in the background
+func OnAsync[E Event](handler func(ctx context.Context, event E) error) Subscription {
+	s := On(handler)
+	s.Async = true
+	return s
+}
+
+// Bus delivers events to the handlers subscribed to them in-process
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Subscription
+	async    sync.WaitGroup
+}
+
+// NewBus creates an empty bus
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[string][]Subscription),
+	}
+}
+
+// Subscribe registers subscriptions
+func (b *Bus) Subscribe(subscriptions ...Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, s := range subscriptions {
+		b.handlers[s.Event] = append(b.handlers[s.Event], s)
+	}
+}
+
+// Publish runs the synchronous handlers of an event in subscription order and
+// starts the asynchronous ones. Every synchronous handler runs even if an
+// earlier one fails; their errors are joined.
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	subscriptions := b.handlers[event.EventName()]
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subscriptions {
+		if s.Async {
+			b.async.Add(1)
+			go func(handler Handler) {
+				defer b.async.Done()
+				// The request may finish before the handler does
+				if err := run(context.WithoutCancel(ctx), handler, event); err != nil {
+					logger.FromContext(ctx).Error("async event handler failed",
+						zap.String("event", event.EventName()),
+						zap.Error(err),
+					)
+				}
+			}(s.Handler)
+			continue
+		}
+
+		if err := run(ctx, s.Handler, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Wait blocks until running asynchronous handlers finish or ctx expires
+func (b *Bus) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.async.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run calls a handler, turning panics into errors
+func run(ctx context.Context, handler Handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("events: handler for %s panicked: %v\n%s", event.EventName(), r, debug.Stack())
+		}
+	}()
+	return handler(ctx, event)
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type userRegistered struct{ ID uint }
+
+func (userRegistered) EventName() string { return "user.registered" }
+
+type userDeleted struct{ ID uint }
+
+func (userDeleted) EventName() string { return "user.deleted" }
+
+func TestBusPublish(t *testing.T) {
+	bus := NewBus()
+
+	var received []uint
+	var async atomic.Int32
+	bus.Subscribe(
+		On(func(ctx context.Context, e userRegistered) error {
+			received = append(received, e.ID)
+			return nil
+		}),
+		On(func(ctx context.Context, e userRegistered) error {
+			return errors.New("mail server down")
+		}),
+		On(func(ctx context.Context, e userRegistered) error {
+			panic("boom")
+		}),
+		OnAsync(func(ctx context.Context, e userRegistered) error {
+			time.Sleep(10 * time.Millisecond)
+			async.Add(1)
+			return nil
+		}),
+	)
+
+	// Failing handlers don't stop the others; their errors are joined
+	err := bus.Publish(context.Background(), userRegistered{ID: 7})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mail server down")
+	assert.Contains(t, err.Error(), "panicked: boom")
+	assert.Equal(t, []uint{7}, received)
+
+	require.NoError(t, bus.Wait(context.Background()))
+	assert.Equal(t, int32(1), async.Load())
+
+	// Events without subscribers are ignored
+	assert.NoError(t, bus.Publish(context.Background(), userDeleted{ID: 7}))
+}
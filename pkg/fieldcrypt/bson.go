@@ -0,0 +1,98 @@
+package fieldcrypt
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// String is a string stored encrypted in MongoDB documents
+type String string
+
+// MarshalBSONValue encrypts the string
+func (s String) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	encrypted, err := Default().Encrypt(string(s))
+	if err != nil {
+		return 0, nil, err
+	}
+	return bson.MarshalValue(encrypted)
+}
+
+// UnmarshalBSONValue decrypts the string; plaintext strings are read as
+// they are
+func (s *String) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bson.TypeNull {
+		*s = ""
+		return nil
+	}
+
+	var value string
+	if err := (bson.RawValue{Type: t, Value: data}).Unmarshal(&value); err != nil {
+		return err
+	}
+	plaintext, err := Default().Decrypt(value)
+	if err != nil {
+		return err
+	}
+	*s = String(plaintext)
+	return nil
+}
+
+// Document is a document stored encrypted in MongoDB documents. Encrypted
+// documents are stored as a string of their JSON encoding, so the values
+// read back are those of encoding/json.
+type Document map[string]interface{}
+
+// MarshalBSONValue encrypts the document; without keys it is stored as an
+// embedded document
+func (d Document) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if d == nil {
+		return bson.TypeNull, nil, nil
+	}
+	if !Default().Enabled() {
+		return bson.MarshalValue(bson.M(d))
+	}
+
+	data, err := json.Marshal(map[string]interface{}(d))
+	if err != nil {
+		return 0, nil, err
+	}
+	encrypted, err := Default().Encrypt(string(data))
+	if err != nil {
+		return 0, nil, err
+	}
+	return bson.MarshalValue(encrypted)
+}
+
+// UnmarshalBSONValue decrypts the document; embedded documents stored in
+// plaintext are read as they are
+func (d *Document) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+	switch t {
+	case bson.TypeNull:
+		*d = nil
+		return nil
+	case bson.TypeEmbeddedDocument:
+		var m bson.M
+		if err := raw.Unmarshal(&m); err != nil {
+			return err
+		}
+		*d = Document(m)
+		return nil
+	case bson.TypeString:
+		plaintext, err := Default().Decrypt(raw.StringValue())
+		if err != nil {
+			return err
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(plaintext), &m); err != nil {
+			return err
+		}
+		*d = Document(m)
+		return nil
+	default:
+		return fmt.Errorf("fieldcrypt: cannot decode %s into a document", t)
+	}
+}
@@ -0,0 +1,237 @@
+// Package fieldcrypt encrypts individual model fields at rest with AES-256-GCM.
+//
+// Encrypted values are self-describing strings, enc:v1:<key id>:<base64 of
+// nonce and ciphertext>, so keys can be rotated: new values are encrypted
+// with the current key while values encrypted with retired keys still
+// decrypt as long as those keys are configured. Values without the prefix
+// are returned as they are, which lets existing plaintext data be read
+// after encryption is turned on.
+package fieldcrypt
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// prefix marks encrypted values and the version of their format
+const prefix = "enc:v1:"
+
+// KeySize is the size of encryption keys, AES-256
+const KeySize = 32
+
+// Errors returned while decrypting values
+var (
+	ErrUnknownKey = errors.New("fieldcrypt: unknown key ID")
+	ErrMalformed  = errors.New("fieldcrypt: malformed encrypted value")
+)
+
+// Config holds field encryption key configuration
+type Config struct {
+	// Keys maps key IDs to hex-encoded 32-byte keys
+	Keys map[string]string
+	// KeyFile holds more keys, one kid=key pair per line, e.g. a file
+	// written by a secrets manager. Blank lines and lines starting with #
+	// are ignored.
+	KeyFile string
+	// KeyID is the key that encrypts new values; it may be empty when there
+	// is a single key
+	KeyID string
+}
+
+// Keyring encrypts values with its current key and decrypts values
+// encrypted with any of its keys. A keyring without keys stores values in
+// plaintext.
+type Keyring struct {
+	ciphers map[string]cipher.AEAD
+	keyID   string
+}
+
+// NewKeyring creates a keyring from the configured keys
+func NewKeyring(cfg Config) (*Keyring, error) {
+	keys := make(map[string]string, len(cfg.Keys))
+	for id, key := range cfg.Keys {
+		keys[id] = key
+	}
+	if cfg.KeyFile != "" {
+		fileKeys, err := readKeyFile(cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		for id, key := range fileKeys {
+			keys[id] = key
+		}
+	}
+
+	k := &Keyring{ciphers: make(map[string]cipher.AEAD, len(keys)), keyID: cfg.KeyID}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("fieldcrypt: invalid key ID %q", id)
+		}
+		raw, err := hex.DecodeString(key)
+		if err != nil || len(raw) != KeySize {
+			return nil, fmt.Errorf("fieldcrypt: key %s must be %d hex-encoded bytes", id, KeySize)
+		}
+		block, err := aes.NewCipher(raw)
+		if err != nil {
+			return nil, err
+		}
+		if k.ciphers[id], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case len(keys) == 0 && k.keyID != "":
+		return nil, fmt.Errorf("fieldcrypt: key %s is not configured", k.keyID)
+	case len(keys) == 0:
+	case k.keyID == "" && len(keys) > 1:
+		return nil, errors.New("fieldcrypt: the current key ID is required with several keys")
+	case k.keyID == "":
+		for id := range keys {
+			k.keyID = id
+		}
+	case k.ciphers[k.keyID] == nil:
+		return nil, fmt.Errorf("fieldcrypt: key %s is not configured", k.keyID)
+	}
+
+	return k, nil
+}
+
+// readKeyFile reads kid=key pairs from a file
+func readKeyFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: read key file: %w", err)
+	}
+	defer f.Close()
+
+	keys := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		id, key, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("fieldcrypt: key file line %d is not kid=key", line)
+		}
+		keys[strings.TrimSpace(id)] = strings.TrimSpace(key)
+	}
+	return keys, scanner.Err()
+}
+
+// Enabled reports whether the keyring encrypts values
+func (k *Keyring) Enabled() bool {
+	return k != nil && k.keyID != ""
+}
+
+// KeyID returns the ID of the key encrypting new values
+func (k *Keyring) KeyID() string {
+	if k == nil {
+		return ""
+	}
+	return k.keyID
+}
+
+// KeyIDs returns the IDs of every key of the keyring, sorted
+func (k *Keyring) KeyIDs() []string {
+	if k == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(k.ciphers))
+	for id := range k.ciphers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Encrypt encrypts a value with the current key. Without keys, and for empty
+// values, the value is returned as it is.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if !k.Enabled() || plaintext == "" {
+		return plaintext, nil
+	}
+
+	aead := k.ciphers[k.keyID]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("fieldcrypt: generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return prefix + k.keyID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value encrypted with any key of the keyring. Values
+// that aren't encrypted are returned as they are.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	var aead cipher.AEAD
+	if k != nil {
+		aead = k.ciphers[keyID]
+	}
+	if aead == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformed
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether a value should be encrypted again: it is
+// plaintext while encryption is enabled, or was encrypted with another key
+// than the current one
+func (k *Keyring) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return k.Enabled()
+	}
+	return !strings.HasPrefix(value, prefix+k.KeyID()+":")
+}
+
+// IsEncrypted reports whether a value is encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// defaultKeyring is the keyring of encrypted model fields
+var defaultKeyring atomic.Pointer[Keyring]
+
+// SetDefault sets the keyring used by the GORM serializer and the BSON types
+func SetDefault(k *Keyring) {
+	defaultKeyring.Store(k)
+}
+
+// Default returns the keyring set by SetDefault, which has no keys until
+// then
+func Default() *Keyring {
+	return defaultKeyring.Load()
+}
@@ -0,0 +1,192 @@
+package fieldcrypt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var (
+	oldKey = strings.Repeat("11", KeySize)
+	newKey = strings.Repeat("22", KeySize)
+)
+
+func newKeyring(t *testing.T, keyID string, keys map[string]string) *Keyring {
+	keyring, err := NewKeyring(Config{Keys: keys, KeyID: keyID})
+	require.NoError(t, err)
+	return keyring
+}
+
+// useKeyring sets the default keyring for the duration of the test
+func useKeyring(t *testing.T, k *Keyring) {
+	previous := Default()
+	SetDefault(k)
+	t.Cleanup(func() { SetDefault(previous) })
+}
+
+func TestNewKeyring(t *testing.T) {
+	keyring, err := NewKeyring(Config{})
+	require.NoError(t, err)
+	assert.False(t, keyring.Enabled())
+
+	keyring, err = NewKeyring(Config{Keys: map[string]string{"k1": oldKey}})
+	require.NoError(t, err)
+	assert.Equal(t, "k1", keyring.KeyID())
+
+	path := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(path, []byte("# rotated 2024-11\nk1="+oldKey+"\n\nk2 = "+newKey+"\n"), 0o600))
+	keyring, err = NewKeyring(Config{KeyFile: path, KeyID: "k2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k1", "k2"}, keyring.KeyIDs())
+
+	invalid := []Config{
+		{Keys: map[string]string{"k1": "short"}},
+		{Keys: map[string]string{"k:1": oldKey}},
+		{Keys: map[string]string{"k1": oldKey, "k2": newKey}},
+		{Keys: map[string]string{"k1": oldKey}, KeyID: "k2"},
+		{KeyID: "k1"},
+		{KeyFile: filepath.Join(t.TempDir(), "missing")},
+	}
+	for _, cfg := range invalid {
+		_, err := NewKeyring(cfg)
+		assert.Error(t, err, "%+v", cfg)
+	}
+}
+
+func TestKeyringEncrypt(t *testing.T) {
+	keyring := newKeyring(t, "k1", map[string]string{"k1": oldKey})
+
+	encrypted, err := keyring.Encrypt("+33612345678")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "enc:v1:k1:"))
+	assert.NotContains(t, encrypted, "33612345678")
+
+	again, err := keyring.Encrypt("+33612345678")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "nonces are random")
+
+	decrypted, err := keyring.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "+33612345678", decrypted)
+
+	// Empty and plaintext values pass through
+	empty, err := keyring.Encrypt("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+	plaintext, err := keyring.Decrypt("+33612345678")
+	require.NoError(t, err)
+	assert.Equal(t, "+33612345678", plaintext)
+
+	// Tampered values are rejected
+	_, err = keyring.Decrypt(encrypted[:len(encrypted)-2] + "AA")
+	assert.ErrorIs(t, err, ErrMalformed)
+	_, err = keyring.Decrypt("enc:v1:k1")
+	assert.ErrorIs(t, err, ErrMalformed)
+
+	// A keyring without keys stores plaintext and can't read encrypted values
+	var disabled *Keyring
+	stored, err := disabled.Encrypt("+33612345678")
+	require.NoError(t, err)
+	assert.Equal(t, "+33612345678", stored)
+	_, err = disabled.Decrypt(encrypted)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyringRotation(t *testing.T) {
+	old := newKeyring(t, "k1", map[string]string{"k1": oldKey})
+	encrypted, err := old.Encrypt("secret")
+	require.NoError(t, err)
+
+	rotated := newKeyring(t, "k2", map[string]string{"k1": oldKey, "k2": newKey})
+	decrypted, err := rotated.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+	assert.True(t, rotated.NeedsRotation(encrypted))
+	assert.True(t, rotated.NeedsRotation("plaintext"))
+	assert.False(t, rotated.NeedsRotation(""))
+
+	reencrypted, err := rotated.Encrypt(decrypted)
+	require.NoError(t, err)
+	assert.False(t, rotated.NeedsRotation(reencrypted))
+
+	retired := newKeyring(t, "k2", map[string]string{"k2": newKey})
+	_, err = retired.Decrypt(encrypted)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+type record struct {
+	ID       uint
+	Phone    string                 `gorm:"serializer:encrypted"`
+	Metadata map[string]interface{} `gorm:"serializer:encrypted"`
+}
+
+func TestSerializer(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&record{}))
+
+	// Rows written before encryption was enabled stay readable
+	useKeyring(t, nil)
+	require.NoError(t, db.Create(&record{Phone: "+33600000000", Metadata: map[string]interface{}{"plan": "free"}}).Error)
+
+	useKeyring(t, newKeyring(t, "k1", map[string]string{"k1": oldKey}))
+	require.NoError(t, db.Create(&record{Phone: "+33612345678", Metadata: map[string]interface{}{"plan": "pro"}}).Error)
+	require.NoError(t, db.Create(&record{}).Error)
+
+	var raw struct {
+		Phone    string
+		Metadata *string
+	}
+	require.NoError(t, db.Table("records").Where("id = ?", 2).Take(&raw).Error)
+	assert.True(t, IsEncrypted(raw.Phone))
+	require.NotNil(t, raw.Metadata)
+	assert.True(t, strings.HasPrefix(*raw.Metadata, `"enc:v1:k1:`))
+
+	var records []record
+	require.NoError(t, db.Order("id").Find(&records).Error)
+	require.Len(t, records, 3)
+	assert.Equal(t, "+33600000000", records[0].Phone)
+	assert.Equal(t, map[string]interface{}{"plan": "free"}, records[0].Metadata)
+	assert.Equal(t, "+33612345678", records[1].Phone)
+	assert.Equal(t, map[string]interface{}{"plan": "pro"}, records[1].Metadata)
+	assert.Empty(t, records[2].Phone)
+	assert.Nil(t, records[2].Metadata)
+
+	require.NoError(t, db.Table("records").Where("id = ?", 3).Take(&raw).Error)
+	assert.Nil(t, raw.Metadata)
+}
+
+func TestBSON(t *testing.T) {
+	useKeyring(t, newKeyring(t, "k1", map[string]string{"k1": oldKey}))
+
+	type document struct {
+		Phone    String   `bson:"phone,omitempty"`
+		Metadata Document `bson:"metadata,omitempty"`
+	}
+	data, err := bson.Marshal(document{Phone: "+33612345678", Metadata: Document{"plan": "pro"}})
+	require.NoError(t, err)
+
+	var raw bson.M
+	require.NoError(t, bson.Unmarshal(data, &raw))
+	assert.True(t, IsEncrypted(raw["phone"].(string)))
+	assert.True(t, IsEncrypted(raw["metadata"].(string)))
+
+	var decoded document
+	require.NoError(t, bson.Unmarshal(data, &decoded))
+	assert.Equal(t, String("+33612345678"), decoded.Phone)
+	assert.Equal(t, Document{"plan": "pro"}, decoded.Metadata)
+
+	// Documents stored before encryption was enabled stay readable
+	data, err = bson.Marshal(bson.M{"phone": "+33600000000", "metadata": bson.M{"plan": "free"}})
+	require.NoError(t, err)
+	require.NoError(t, bson.Unmarshal(data, &decoded))
+	assert.Equal(t, String("+33600000000"), decoded.Phone)
+	assert.Equal(t, Document{"plan": "free"}, decoded.Metadata)
+}
@@ -0,0 +1,98 @@
+package fieldcrypt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("encrypted", Serializer{})
+}
+
+// Serializer is the GORM serializer of encrypted fields, registered as
+// "encrypted": `gorm:"serializer:encrypted"`. String fields are stored as
+// encrypted text. Other fields are encoded as JSON, and once encrypted are
+// stored as a JSON string so that JSON and JSONB columns accept them.
+type Serializer struct{}
+
+// Scan decrypts a column value into the field
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var value string
+		switch v := dbValue.(type) {
+		case []byte:
+			value = string(v)
+		case string:
+			value = v
+		default:
+			return fmt.Errorf("fieldcrypt: unsupported column value %T of field %s", dbValue, field.Name)
+		}
+
+		if field.FieldType.Kind() == reflect.String {
+			plaintext, err := Default().Decrypt(value)
+			if err != nil {
+				return err
+			}
+			fieldValue.Elem().SetString(plaintext)
+		} else if value != "" {
+			data, err := decryptJSON(value)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(data, fieldValue.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value encrypts the field into a column value
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	if field.FieldType.Kind() == reflect.String {
+		return Default().Encrypt(reflect.ValueOf(fieldValue).String())
+	}
+
+	data, err := json.Marshal(fieldValue)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "null" {
+		return nil, nil
+	}
+	return encryptJSON(data)
+}
+
+// encryptJSON encrypts a JSON document into a JSON string
+func encryptJSON(data []byte) (string, error) {
+	if !Default().Enabled() {
+		return string(data), nil
+	}
+
+	encrypted, err := Default().Encrypt(string(data))
+	if err != nil {
+		return "", err
+	}
+	quoted, err := json.Marshal(encrypted)
+	return string(quoted), err
+}
+
+// decryptJSON returns the JSON document of a value written by encryptJSON
+func decryptJSON(value string) ([]byte, error) {
+	var encrypted string
+	if err := json.Unmarshal([]byte(value), &encrypted); err != nil || !IsEncrypted(encrypted) {
+		// Plaintext JSON stored before encryption was enabled
+		return []byte(value), nil
+	}
+
+	plaintext, err := Default().Decrypt(encrypted)
+	return []byte(plaintext), err
+}
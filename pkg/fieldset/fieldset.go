@@ -0,0 +1,188 @@
+// Package fieldset implements sparse fieldsets: clients list the fields of
+// a response they need, as in ?fields=id,email,name, and the response is
+// reduced to those fields. Field names are the JSON names of a DTO type, so
+// any struct encoded with encoding/json can be projected.
+package fieldset
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownField is returned for field names the DTO does not have
+var ErrUnknownField = errors.New("unknown field")
+
+// Set is a parsed list of fields, kept in the order of the DTO
+type Set struct {
+	fields []string
+}
+
+// fieldsCache holds the JSON field names of each DTO type
+var fieldsCache sync.Map
+
+// Fields returns the JSON field names of the struct model, or of the
+// struct it points to, in declaration order
+func Fields(model any) []string {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	if fields, ok := fieldsCache.Load(t); ok {
+		return fields.([]string)
+	}
+	fields := structFields(t)
+	fieldsCache.Store(t, fields)
+	return fields
+}
+
+// structFields lists the JSON field names of a struct type, including the
+// promoted fields of embedded structs
+func structFields(t reflect.Type) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fields = append(fields, structFields(ft)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+// Parse parses a comma separated list of the fields of model. It returns a
+// nil Set, which selects every field, when the list is empty.
+func Parse(list string, model any) (*Set, error) {
+	fields := Fields(model)
+	known := make(map[string]bool, len(fields))
+	for _, name := range fields {
+		known[name] = true
+	}
+
+	requested := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !known[name] {
+			return nil, fmt.Errorf("%w %q", ErrUnknownField, name)
+		}
+		requested[name] = true
+	}
+	if len(requested) == 0 {
+		return nil, nil
+	}
+
+	s := &Set{}
+	for _, name := range fields {
+		if requested[name] {
+			s.fields = append(s.fields, name)
+		}
+	}
+	return s, nil
+}
+
+// Project returns data reduced to the fields of the set when encoded as
+// JSON. Data may be a struct or a slice of structs; a nil set returns data
+// unchanged.
+func (s *Set) Project(data any) any {
+	if s == nil {
+		return data
+	}
+	return projection{set: s, data: data}
+}
+
+// projection projects data while it is encoded, so encoding errors surface
+// where the response is written
+type projection struct {
+	set  *Set
+	data any
+}
+
+// MarshalJSON implements json.Marshaler
+func (p projection) MarshalJSON() ([]byte, error) {
+	encoded, err := json.Marshal(p.data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch bytes.TrimSpace(encoded)[0] {
+	case '{':
+		return p.set.projectObject(encoded)
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(encoded, &items); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for i, item := range items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			projected, err := p.set.projectObject(item)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(projected)
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+	default:
+		return encoded, nil
+	}
+}
+
+// projectObject keeps the fields of the set in an encoded object. Other
+// values, such as null list items, are kept as they are.
+func (s *Set) projectObject(encoded []byte) ([]byte, error) {
+	if trimmed := bytes.TrimSpace(encoded); len(trimmed) == 0 || trimmed[0] != '{' {
+		return encoded, nil
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &members); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, name := range s.fields {
+		value, ok := members[name]
+		if !ok {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,68 @@
+package fieldset
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type base struct {
+	ID uint `json:"id"`
+}
+
+type item struct {
+	base
+	Name     string            `json:"name"`
+	Email    string            `json:"email,omitempty"`
+	Secret   string            `json:"-"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Untagged bool
+	hidden   bool
+}
+
+func TestFields(t *testing.T) {
+	assert.Equal(t, []string{"id", "name", "email", "tags", "Untagged"}, Fields(item{}))
+	assert.Equal(t, Fields(item{}), Fields(&item{}))
+	assert.Nil(t, Fields([]item{}))
+}
+
+func TestParse(t *testing.T) {
+	s, err := Parse(" name, id,,name ", item{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "name"}, s.fields, "DTO order without duplicates")
+
+	s, err = Parse(" , ", item{})
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	_, err = Parse("id,password,Secret", item{})
+	assert.ErrorIs(t, err, ErrUnknownField)
+	assert.ErrorContains(t, err, `"password"`)
+	_, err = Parse("Secret", item{})
+	assert.ErrorIs(t, err, ErrUnknownField)
+}
+
+func TestProject(t *testing.T) {
+	s, err := Parse("email,id,tags", item{})
+	require.NoError(t, err)
+
+	a := item{base: base{ID: 1}, Name: "Alice", Email: "alice@example.com", Tags: map[string]string{"team": "core"}}
+	b := &item{base: base{ID: 2}, Name: "Bob"}
+
+	encoded, err := json.Marshal(s.Project(a))
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1,"email":"alice@example.com","tags":{"team":"core"}}`, string(encoded))
+
+	encoded, err = json.Marshal(s.Project([]*item{b, nil}))
+	require.NoError(t, err)
+	assert.Equal(t, `[{"id":2},null]`, string(encoded), "omitted empty fields stay omitted")
+
+	encoded, err = json.Marshal(map[string]any{"data": s.Project([]item{})})
+	require.NoError(t, err)
+	assert.Equal(t, `{"data":[]}`, string(encoded))
+
+	var none *Set
+	assert.Equal(t, a, none.Project(a))
+}
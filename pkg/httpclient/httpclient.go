@@ -0,0 +1,156 @@
+// Package httpclient creates the *http.Client of outbound integrations such
+// as webhooks, OAuth providers and search clusters. Clients share a pooled
+// transport with per-host connection limits, and add retries of idempotent
+// requests, request metrics and OpenTelemetry spans.
+package httpclient
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/resilience"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Config holds the settings of the clients of a Factory
+type Config struct {
+	// Timeout bounds a whole request, retries included; zero leaves it unbounded
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	DialTimeout           time.Duration `json:"dial_timeout" yaml:"dial_timeout"`
+	TLSHandshakeTimeout   time.Duration `json:"tls_handshake_timeout" yaml:"tls_handshake_timeout"`
+	ResponseHeaderTimeout time.Duration `json:"response_header_timeout" yaml:"response_header_timeout"`
+	IdleConnTimeout       time.Duration `json:"idle_conn_timeout" yaml:"idle_conn_timeout"`
+
+	// Pool limits; zero leaves them unlimited, except MaxIdleConnsPerHost
+	// which then keeps Go's default of 2
+	MaxIdleConns        int `json:"max_idle_conns" yaml:"max_idle_conns"`
+	MaxIdleConnsPerHost int `json:"max_idle_conns_per_host" yaml:"max_idle_conns_per_host"`
+	MaxConnsPerHost     int `json:"max_conns_per_host" yaml:"max_conns_per_host"`
+
+	// ProxyURL sends requests through a proxy; empty uses HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY
+	ProxyURL string `json:"proxy_url" yaml:"proxy_url"`
+
+	// Retry retries idempotent requests after network errors, 429 and 502-504
+	// responses; one attempt or less disables retries
+	Retry resilience.RetryConfig `json:"retry" yaml:"retry"`
+
+	// Tracing wraps requests in OpenTelemetry client spans and propagates
+	// the trace context, using the global tracer provider and propagator
+	Tracing bool `json:"tracing" yaml:"tracing"`
+
+	// UserAgent is set on requests that have none
+	UserAgent string `json:"user_agent" yaml:"user_agent"`
+}
+
+// Factory creates clients sharing one connection pool. It is safe for
+// concurrent use.
+type Factory struct {
+	cfg       Config
+	transport *http.Transport
+}
+
+// NewFactory creates a factory and its connection pool
+func NewFactory(cfg Config) (*Factory, error) {
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("httpclient: invalid proxy URL %q", cfg.ProxyURL)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	dialer := &net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: 30 * time.Second}
+	return &Factory{
+		cfg: cfg,
+		transport: &http.Transport{
+			Proxy:                 proxy,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+			ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+			IdleConnTimeout:       cfg.IdleConnTimeout,
+			MaxIdleConns:          cfg.MaxIdleConns,
+			MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+			MaxConnsPerHost:       cfg.MaxConnsPerHost,
+			ExpectContinueTimeout: time.Second,
+		},
+	}, nil
+}
+
+// Option changes the settings of a single client
+type Option func(*options)
+
+// options are the settings of a client, defaulting to the factory's
+type options struct {
+	timeout    time.Duration
+	retry      resilience.RetryConfig
+	middleware []func(http.RoundTripper) http.RoundTripper
+}
+
+// WithTimeout bounds the requests of the client instead of Config.Timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithRetry retries the requests of the client with cfg instead of
+// Config.Retry
+func WithRetry(cfg resilience.RetryConfig) Option {
+	return func(o *options) {
+		o.retry = cfg
+	}
+}
+
+// WithMiddleware wraps the transport of the client, e.g. to sign or
+// authenticate requests. Middleware runs for every attempt, in the order
+// given, and sees requests before the User-Agent is set.
+func WithMiddleware(middleware ...func(http.RoundTripper) http.RoundTripper) Option {
+	return func(o *options) {
+		o.middleware = append(o.middleware, middleware...)
+	}
+}
+
+// WithoutRetry sends every request once, e.g. for callers that retry
+// failed requests themselves
+func WithoutRetry() Option {
+	return WithRetry(resilience.RetryConfig{})
+}
+
+// Client returns a client for an integration; name labels its metrics,
+// retries and spans
+func (f *Factory) Client(name string, opts ...Option) *http.Client {
+	o := options{timeout: f.cfg.Timeout, retry: f.cfg.Retry}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var transport http.RoundTripper = f.transport
+	if f.cfg.Tracing {
+		transport = otelhttp.NewTransport(transport,
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return name + " " + r.Method
+			}),
+		)
+	}
+	transport = &instrumentedTransport{name: name, userAgent: f.cfg.UserAgent, next: transport}
+	for i := len(o.middleware) - 1; i >= 0; i-- {
+		transport = o.middleware[i](transport)
+	}
+	if o.retry.MaxAttempts > 1 {
+		transport = &retryTransport{name: name, cfg: o.retry, next: transport}
+	}
+
+	return &http.Client{Transport: transport, Timeout: o.timeout}
+}
+
+// CloseIdleConnections closes the idle connections of the pool
+func (f *Factory) CloseIdleConnections() {
+	f.transport.CloseIdleConnections()
+}
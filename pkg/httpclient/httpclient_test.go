@@ -0,0 +1,136 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/metrics"
+	"github.com/luxixing/fx-gin-scaffold/pkg/resilience"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastRetry = resilience.RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+// flakyServer answers 503 to the first failures requests, echoing the
+// request body once it succeeds
+func flakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-User-Agent", r.UserAgent())
+		_, _ = io.Copy(w, r.Body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newFactory(t *testing.T, cfg Config) *Factory {
+	factory, err := NewFactory(cfg)
+	require.NoError(t, err)
+	t.Cleanup(factory.CloseIdleConnections)
+	return factory
+}
+
+func TestClientRetriesIdempotentRequests(t *testing.T) {
+	server, calls := flakyServer(t, 2)
+	client := newFactory(t, Config{Retry: fastRetry, UserAgent: "scaffold-test"}).Client("flaky")
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "payload", string(body))
+	assert.Equal(t, "scaffold-test", resp.Header.Get("X-User-Agent"))
+	assert.EqualValues(t, 3, calls.Load())
+
+	var b strings.Builder
+	_, err = metrics.Default.WriteTo(&b)
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `http_client_requests_total{client="flaky",method="PUT",code="503"} 2`)
+	assert.Contains(t, b.String(), `http_client_requests_total{client="flaky",method="PUT",code="200"} 1`)
+	assert.Contains(t, b.String(), `http_client_request_duration_seconds_count{client="flaky",method="PUT"} 3`)
+	assert.Contains(t, b.String(), `retries_total{operation="flaky"} 2`)
+}
+
+func TestClientReturnsLastResponseWhenAttemptsRunOut(t *testing.T) {
+	server, calls := flakyServer(t, 5)
+	client := newFactory(t, Config{Retry: fastRetry}).Client("down")
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestClientDoesNotRetryNonIdempotentRequests(t *testing.T) {
+	server, calls := flakyServer(t, 1)
+	client := newFactory(t, Config{Retry: fastRetry}).Client("post")
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.EqualValues(t, 1, calls.Load())
+
+	// An idempotency key makes the request safe to retry
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestClientOptions(t *testing.T) {
+	server, calls := flakyServer(t, 1)
+	factory := newFactory(t, Config{Retry: fastRetry})
+
+	resp, err := factory.Client("once", WithoutRetry()).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.EqualValues(t, 1, calls.Load())
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer slow.Close()
+	_, err = factory.Client("slow", WithTimeout(10*time.Millisecond)).Get(slow.URL)
+	assert.Error(t, err)
+}
+
+func TestClientTracing(t *testing.T) {
+	server, _ := flakyServer(t, 0)
+	client := newFactory(t, Config{Tracing: true}).Client("traced")
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestNewFactoryRejectsInvalidProxy(t *testing.T) {
+	_, err := NewFactory(Config{ProxyURL: "localhost"})
+	assert.EqualError(t, err, `httpclient: invalid proxy URL "localhost"`)
+
+	proxied := newFactory(t, Config{ProxyURL: "http://proxy.internal:3128"})
+	proxyURL, err := proxied.transport.Proxy(httptest.NewRequest(http.MethodGet, "https://example.com", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.internal:3128", proxyURL.Host)
+}
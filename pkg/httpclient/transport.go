@@ -0,0 +1,158 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/metrics"
+	"github.com/luxixing/fx-gin-scaffold/pkg/resilience"
+)
+
+// Request metrics, labeled with the client name
+var (
+	requestsTotal = metrics.Default.NewCounter("http_client_requests_total",
+		"Outbound HTTP requests by response status; error when none was received.", "client", "method", "code")
+	requestDuration = metrics.Default.NewHistogram("http_client_request_duration_seconds",
+		"Latency of outbound HTTP requests.", metrics.DefaultBuckets, "client", "method")
+)
+
+// IdempotencyKeyHeader marks a request as safe to retry whatever its method
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// instrumentedTransport records metrics for every attempt and sets the
+// User-Agent
+type instrumentedTransport struct {
+	name      string
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	requestDuration.With(t.name, req.Method).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	requestsTotal.With(t.name, req.Method, code).Inc()
+	return resp, err
+}
+
+// retryTransport retries idempotent requests whose body can be sent again
+type retryTransport struct {
+	name string
+	cfg  resilience.RetryConfig
+	next http.RoundTripper
+}
+
+// statusError marks a response with a retryable status
+type statusError struct {
+	statusCode int
+}
+
+func (e *statusError) Error() string {
+	return "httpclient: status " + strconv.Itoa(e.statusCode)
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !retryableRequest(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	cfg := t.cfg
+	if cfg.Retryable == nil {
+		cfg.Retryable = retryableError
+	}
+
+	var resp *http.Response
+	attempt := 0
+	err := resilience.Retry(req.Context(), t.name, cfg, func(ctx context.Context) error {
+		attempt++
+		r := req
+		if attempt > 1 {
+			// Discard the response of the failed attempt so its connection
+			// can be reused, and send the body again
+			discard(resp)
+			resp = nil
+			r = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return resilience.Permanent(err)
+				}
+				r.Body = body
+			}
+		}
+
+		var err error
+		resp, err = t.next.RoundTrip(r)
+		if err != nil {
+			return err
+		}
+		if retryableStatus(resp.StatusCode) {
+			return &statusError{statusCode: resp.StatusCode}
+		}
+		return nil
+	})
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		// The attempts ran out; return the last response as received
+		return resp, nil
+	}
+	if err != nil {
+		discard(resp)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// retryableRequest reports whether a request may be sent more than once:
+// its method is idempotent or it has an idempotency key, and its body can
+// be sent again
+func retryableRequest(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// retryableStatus reports whether a response status is likely transient
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryableError retries retryable statuses and network errors, but not
+// requests cancelled or timed out by the caller
+func retryableError(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// discard drains a bounded amount of a response body and closes it
+func discard(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
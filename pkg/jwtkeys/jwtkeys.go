@@ -0,0 +1,187 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// Errors returned while verifying tokens
+var (
+	ErrUnexpectedAlgorithm = errors.New("jwtkeys: unexpected signing algorithm")
+	ErrUnknownKey          = errors.New("jwtkeys: unknown key ID")
+)
+
+// Config holds JWT signing key configuration
+type Config struct {
+	Algorithm string `json:"algorithm" yaml:"algorithm"` // HS256, RS256, EdDSA
+	// Secret is the shared key used with HS256
+	Secret string `json:"-" yaml:"-"`
+	// KeyFiles maps key IDs to PEM files used with RS256 and EdDSA. Private
+	// keys sign and verify; public keys only verify, which keeps tokens
+	// signed by a retired key valid until they expire.
+	KeyFiles map[string]string `json:"key_files" yaml:"key_files"`
+	// SigningKeyID selects the key used to sign new tokens. It may be empty
+	// when only one key is configured.
+	SigningKeyID string `json:"signing_key_id" yaml:"signing_key_id"`
+}
+
+// key is a single signing or verification key
+type key struct {
+	id        string
+	signKey   interface{} // nil for verification-only keys
+	verifyKey interface{}
+}
+
+// KeySet signs tokens with the active key and verifies tokens signed by any
+// configured key, identified by the kid header
+type KeySet struct {
+	method  jwt.SigningMethod
+	signing *key
+	keys    map[string]*key
+}
+
+// NewKeySet loads the configured keys
+func NewKeySet(cfg Config) (*KeySet, error) {
+	switch cfg.Algorithm {
+	case AlgorithmHS256, "":
+		if cfg.Secret == "" {
+			return nil, errors.New("jwtkeys: secret is required for HS256")
+		}
+		k := &key{signKey: []byte(cfg.Secret), verifyKey: []byte(cfg.Secret)}
+		return &KeySet{method: jwt.SigningMethodHS256, signing: k, keys: map[string]*key{"": k}}, nil
+	case AlgorithmRS256:
+		return newAsymmetricKeySet(jwt.SigningMethodRS256, cfg)
+	case AlgorithmEdDSA:
+		return newAsymmetricKeySet(jwt.SigningMethodEdDSA, cfg)
+	default:
+		return nil, fmt.Errorf("jwtkeys: unsupported algorithm: %s", cfg.Algorithm)
+	}
+}
+
+// newAsymmetricKeySet loads PEM key files for RS256 or EdDSA
+func newAsymmetricKeySet(method jwt.SigningMethod, cfg Config) (*KeySet, error) {
+	if len(cfg.KeyFiles) == 0 {
+		return nil, fmt.Errorf("jwtkeys: at least one key file is required for %s", method.Alg())
+	}
+
+	keys := make(map[string]*key, len(cfg.KeyFiles))
+	for id, path := range cfg.KeyFiles {
+		if id == "" {
+			return nil, errors.New("jwtkeys: key ID cannot be empty")
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("jwtkeys: failed to read key %s: %w", id, err)
+		}
+
+		k, err := parseKey(method, id, data)
+		if err != nil {
+			return nil, err
+		}
+		keys[id] = k
+	}
+
+	signingKeyID := cfg.SigningKeyID
+	if signingKeyID == "" {
+		if len(keys) > 1 {
+			return nil, errors.New("jwtkeys: signing key ID is required when several keys are configured")
+		}
+		for id := range keys {
+			signingKeyID = id
+		}
+	}
+
+	signing, ok := keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("jwtkeys: signing key %s is not configured", signingKeyID)
+	}
+	if signing.signKey == nil {
+		return nil, fmt.Errorf("jwtkeys: signing key %s is not a private key", signingKeyID)
+	}
+
+	return &KeySet{method: method, signing: signing, keys: keys}, nil
+}
+
+// Algorithm returns the signing algorithm of the key set
+func (s *KeySet) Algorithm() string {
+	return s.method.Alg()
+}
+
+// Sign signs the claims with the active key
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.method, claims)
+	if s.signing.id != "" {
+		token.Header["kid"] = s.signing.id
+	}
+	return token.SignedString(s.signing.signKey)
+}
+
+// Keyfunc resolves the verification key of a token for jwt.Parse
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != s.method.Alg() {
+		return nil, ErrUnexpectedAlgorithm
+	}
+
+	id, _ := token.Header["kid"].(string)
+	k, ok := s.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return k.verifyKey, nil
+}
+
+// JWKS returns the public keys of the set. Shared HS256 secrets are never
+// published, so the set is empty for HS256.
+func (s *KeySet) JWKS() *JWKSet {
+	set := &JWKSet{Keys: make([]JWK, 0, len(s.keys))}
+	for _, k := range s.keys {
+		if jwk, ok := newJWK(s.method.Alg(), k); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+
+	// Map iteration order is random; keep the document stable
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].KeyID < set.Keys[j].KeyID
+	})
+	return set
+}
+
+// parseKey parses a PEM private or public key, checking that it suits the
+// signing method
+func parseKey(method jwt.SigningMethod, id string, data []byte) (*key, error) {
+	switch method {
+	case jwt.SigningMethodRS256:
+		if private, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+			return &key{id: id, signKey: private, verifyKey: &private.PublicKey}, nil
+		}
+		if public, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+			return &key{id: id, verifyKey: public}, nil
+		}
+	case jwt.SigningMethodEdDSA:
+		if private, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+			if private, ok := private.(ed25519.PrivateKey); ok {
+				return &key{id: id, signKey: private, verifyKey: private.Public()}, nil
+			}
+		}
+		if public, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+			if public, ok := public.(ed25519.PublicKey); ok {
+				return &key{id: id, verifyKey: public}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("jwtkeys: key %s is not a valid %s PEM key", id, method.Alg())
+}
@@ -0,0 +1,153 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePEM writes a PEM block to a file in dir and returns its path
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// newRSAKeyFiles writes an RSA private key and its public key
+func newRSAKeyFiles(t *testing.T, dir, name string) (string, string) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+
+	return writePEM(t, dir, name+".pem", "PRIVATE KEY", privateDER),
+		writePEM(t, dir, name+".pub.pem", "PUBLIC KEY", publicDER)
+}
+
+// testClaims returns claims valid for an hour
+func testClaims() jwt.Claims {
+	return jwt.RegisteredClaims{
+		Subject:   "user@example.com",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+// verify parses a token with the key set
+func verify(set *KeySet, token string) error {
+	_, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, set.Keyfunc)
+	return err
+}
+
+// TestRotation tests that tokens signed by a retired key still verify
+func TestRotation(t *testing.T) {
+	dir := t.TempDir()
+	oldPrivate, oldPublic := newRSAKeyFiles(t, dir, "old")
+	newPrivate, _ := newRSAKeyFiles(t, dir, "new")
+
+	before, err := NewKeySet(Config{Algorithm: AlgorithmRS256, KeyFiles: map[string]string{"old": oldPrivate}})
+	require.NoError(t, err)
+	oldToken, err := before.Sign(testClaims())
+	require.NoError(t, err)
+
+	after, err := NewKeySet(Config{
+		Algorithm:    AlgorithmRS256,
+		KeyFiles:     map[string]string{"old": oldPublic, "new": newPrivate},
+		SigningKeyID: "new",
+	})
+	require.NoError(t, err)
+	newToken, err := after.Sign(testClaims())
+	require.NoError(t, err)
+
+	assert.NoError(t, verify(after, oldToken))
+	assert.NoError(t, verify(after, newToken))
+	assert.ErrorIs(t, verify(before, newToken), ErrUnknownKey)
+
+	jwks := after.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "new", jwks.Keys[0].KeyID)
+	assert.Equal(t, "old", jwks.Keys[1].KeyID)
+	assert.Equal(t, "RSA", jwks.Keys[0].KeyType)
+	assert.Equal(t, "AQAB", jwks.Keys[0].E)
+}
+
+// TestEdDSA tests signing with an Ed25519 key
+func TestEdDSA(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	path := writePEM(t, t.TempDir(), "ed.pem", "PRIVATE KEY", der)
+
+	set, err := NewKeySet(Config{Algorithm: AlgorithmEdDSA, KeyFiles: map[string]string{"ed": path}})
+	require.NoError(t, err)
+
+	token, err := set.Sign(testClaims())
+	require.NoError(t, err)
+	assert.NoError(t, verify(set, token))
+
+	jwks := set.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+}
+
+// TestAlgorithmMismatch tests that tokens signed with another algorithm,
+// including HS256 tokens keyed with the public key, are rejected
+func TestAlgorithmMismatch(t *testing.T) {
+	dir := t.TempDir()
+	private, public := newRSAKeyFiles(t, dir, "rsa")
+
+	rsaSet, err := NewKeySet(Config{Algorithm: AlgorithmRS256, KeyFiles: map[string]string{"rsa": private}})
+	require.NoError(t, err)
+
+	publicPEM, err := os.ReadFile(public)
+	require.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	forged.Header["kid"] = "rsa"
+	forgedToken, err := forged.SignedString(publicPEM)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, verify(rsaSet, forgedToken), ErrUnexpectedAlgorithm)
+
+	hmacSet, err := NewKeySet(Config{Algorithm: AlgorithmHS256, Secret: "secret"})
+	require.NoError(t, err)
+	token, err := hmacSet.Sign(testClaims())
+	require.NoError(t, err)
+	assert.NoError(t, verify(hmacSet, token))
+	assert.Empty(t, hmacSet.JWKS().Keys)
+}
+
+// TestNewKeySetInvalidConfig tests rejection of unusable configurations
+func TestNewKeySetInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	private, public := newRSAKeyFiles(t, dir, "rsa")
+
+	cases := map[string]Config{
+		"missing secret":     {Algorithm: AlgorithmHS256},
+		"unknown algorithm":  {Algorithm: "none", Secret: "secret"},
+		"no key files":       {Algorithm: AlgorithmRS256},
+		"missing file":       {Algorithm: AlgorithmRS256, KeyFiles: map[string]string{"a": filepath.Join(dir, "missing.pem")}},
+		"ambiguous signer":   {Algorithm: AlgorithmRS256, KeyFiles: map[string]string{"a": private, "b": public}},
+		"public signing key": {Algorithm: AlgorithmRS256, KeyFiles: map[string]string{"a": public}},
+		"wrong key type":     {Algorithm: AlgorithmEdDSA, KeyFiles: map[string]string{"a": private}},
+	}
+
+	for name, cfg := range cases {
+		_, err := NewKeySet(cfg)
+		assert.Error(t, err, name)
+	}
+}
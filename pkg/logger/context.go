@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// contextKey is the context key of the context logger
+type contextKey struct{}
+
+// contextLogger is the logger of a context and the fields added to it, kept
+// apart so module loggers get the fields too
+type contextLogger struct {
+	base   *zap.Logger
+	fields []zap.Field
+}
+
+// Root returns the global logger for direct use. Unlike GetLogger, which
+// backs the convenience functions, it reports the caller's location.
+func Root() *zap.Logger {
+	return GetLogger().WithOptions(zap.AddCallerSkip(-1))
+}
+
+// ToContext returns a context whose logger is l, keeping the fields added
+// to ctx
+func ToContext(ctx context.Context, l *zap.Logger) context.Context {
+	current := fromContext(ctx)
+	return context.WithValue(ctx, contextKey{}, &contextLogger{base: l, fields: current.fields})
+}
+
+// WithFields returns a context whose logger adds fields to every entry, such
+// as the request ID and user ID of a request
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	current := fromContext(ctx)
+	merged := make([]zap.Field, 0, len(current.fields)+len(fields))
+	merged = append(append(merged, current.fields...), fields...)
+	return context.WithValue(ctx, contextKey{}, &contextLogger{base: current.base, fields: merged})
+}
+
+// FromContext returns the logger of ctx with its fields, or the global
+// logger when none was set
+func FromContext(ctx context.Context) *zap.Logger {
+	current := fromContext(ctx)
+	base := current.base
+	if base == nil {
+		base = Root()
+	}
+	return base.With(current.fields...)
+}
+
+// NamedFromContext returns the logger of a module such as ModuleDB with the
+// fields of ctx
+func NamedFromContext(ctx context.Context, name string) *zap.Logger {
+	return Named(name).With(fromContext(ctx).fields...)
+}
+
+// fromContext returns the context logger of ctx, empty when none was set
+func fromContext(ctx context.Context) *contextLogger {
+	if current, ok := ctx.Value(contextKey{}).(*contextLogger); ok {
+		return current
+	}
+	return &contextLogger{}
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestContextLogger tests that the fields added to a context are logged by
+// its logger, whichever logger is set
+func TestContextLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	ctx := WithFields(context.Background(), zap.String("request_id", "abc"))
+	ctx = ToContext(ctx, zap.New(core))
+	ctx = WithFields(ctx, zap.Uint("user_id", 7))
+
+	FromContext(ctx).Info("handled", zap.Int("status", 200))
+
+	// The parent context is not modified
+	FromContext(WithFields(context.Background(), zap.String("other", "x"))).Info("not observed")
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "handled", entries[0].Message)
+	assert.Equal(t, map[string]interface{}{"request_id": "abc", "user_id": uint64(7), "status": int64(200)}, entries[0].ContextMap())
+}
+
+// TestContextLoggerFallback tests that contexts without a logger log with
+// the global logger
+func TestContextLoggerFallback(t *testing.T) {
+	assert.NotNil(t, FromContext(context.Background()))
+	assert.NotNil(t, NamedFromContext(WithFields(context.Background(), zap.String("request_id", "abc")), ModuleDB))
+}
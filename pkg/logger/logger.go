@@ -2,6 +2,7 @@ package logger
 
 import (
 	"os"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -11,6 +12,9 @@ var (
 	// Global logger instance
 	logger *zap.Logger
 	sugar  *zap.SugaredLogger
+
+	// level of the global logger, adjustable at runtime with SetLevel
+	level = zap.NewAtomicLevel()
 )
 
 // Config defines logger configuration
@@ -18,32 +22,87 @@ type Config struct {
 	Level  string // debug, info, warn, error
 	Format string // json, console
 	Output string // stdout, stderr, file path
+
+	// Rotation applies when Output is a file path
+	Rotation RotationConfig
+	// Sampling limits repeated debug and info entries
+	Sampling SamplingConfig
+	// Levels overrides the level of modules such as "db"; the others follow
+	// Level
+	Levels map[string]string
 }
 
-// Initialize sets up the global logger
+// SamplingConfig limits the volume of debug and info logs. Within each Tick,
+// the first Initial entries with the same level and message are logged, then
+// every Thereafter-th one. Warnings and errors are never sampled. Zero
+// Initial disables sampling.
+type SamplingConfig struct {
+	Initial    int
+	Thereafter int
+	Tick       time.Duration
+}
+
+// Initialize sets up the global logger and the module loggers
 func Initialize(config Config) error {
-	var err error
-	logger, err = NewLogger(config)
+	core, err := newCore(config)
 	if err != nil {
 		return err
 	}
+	setLevel(level, config.Level)
 
+	logger = zap.New(filterLevel(core, level), zap.AddCaller(), zap.AddCallerSkip(1))
 	sugar = logger.Sugar()
 
+	if err := initializeModules(core, config.Levels); err != nil {
+		return err
+	}
+
 	// Replace the global logger
 	zap.ReplaceGlobals(logger)
 
 	return nil
 }
 
+// SetLevel changes the level of the global logger without rebuilding it
+func SetLevel(l string) error {
+	parsed, err := zapcore.ParseLevel(l)
+	if err != nil {
+		return err
+	}
+	level.SetLevel(parsed)
+	return nil
+}
+
+// GetLevel returns the level of the global logger
+func GetLevel() string {
+	return level.String()
+}
+
 // NewLogger creates a new zap logger with the given configuration
 func NewLogger(config Config) (*zap.Logger, error) {
-	// Parse log level
-	level, err := zapcore.ParseLevel(config.Level)
+	core, err := newCore(config)
+	if err != nil {
+		return nil, err
+	}
+
+	atomicLevel := zap.NewAtomicLevel()
+	setLevel(atomicLevel, config.Level)
+
+	return zap.New(filterLevel(core, atomicLevel), zap.AddCaller(), zap.AddCallerSkip(1)), nil
+}
+
+// setLevel sets atomicLevel to l, or to info if l isn't a valid level
+func setLevel(atomicLevel zap.AtomicLevel, l string) {
+	parsed, err := zapcore.ParseLevel(l)
 	if err != nil {
-		level = zapcore.InfoLevel
+		parsed = zapcore.InfoLevel
 	}
+	atomicLevel.SetLevel(parsed)
+}
 
+// newCore creates the core shared by the global and module loggers. It
+// accepts every level; loggers filter entries with their own level.
+func newCore(config Config) (zapcore.Core, error) {
 	// Create encoder config
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.TimeKey = "timestamp"
@@ -66,21 +125,65 @@ func NewLogger(config Config) (*zap.Logger, error) {
 	case "stdout", "":
 		writeSyncer = zapcore.Lock(os.Stdout)
 	default:
-		// Assume it's a file path
-		file, err := os.OpenFile(config.Output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		// Assume it's a file path, rotated when it grows too large
+		file, err := openRotatingFile(config.Output, config.Rotation)
 		if err != nil {
 			return nil, err
 		}
-		writeSyncer = zapcore.AddSync(file)
+		writeSyncer = file
 	}
 
 	// Create core
-	core := zapcore.NewCore(encoder, writeSyncer, level)
+	core := zapcore.NewCore(encoder, writeSyncer, zapcore.DebugLevel)
+
+	return newSampledCore(core, config.Sampling), nil
+}
 
-	// Create logger
-	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+// newSampledCore samples the debug and info entries of core, or returns it
+// as is when sampling is disabled
+func newSampledCore(core zapcore.Core, config SamplingConfig) zapcore.Core {
+	if config.Initial <= 0 {
+		return core
+	}
+
+	tick := config.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	// Entries are routed by level, so each core only sees its share
+	low := levelFilter{Core: core, enabled: func(l zapcore.Level) bool { return l < zapcore.WarnLevel }}
+	high := levelFilter{Core: core, enabled: func(l zapcore.Level) bool { return l >= zapcore.WarnLevel }}
+	return zapcore.NewTee(
+		zapcore.NewSamplerWithOptions(low, tick, config.Initial, config.Thereafter),
+		high,
+	)
+}
 
-	return logger, nil
+// filterLevel restricts core to the levels enabled by enabler
+func filterLevel(core zapcore.Core, enabler zapcore.LevelEnabler) zapcore.Core {
+	return levelFilter{Core: core, enabled: enabler.Enabled}
+}
+
+// levelFilter restricts a core to the levels accepted by enabled
+type levelFilter struct {
+	zapcore.Core
+	enabled func(zapcore.Level) bool
+}
+
+func (f levelFilter) Enabled(l zapcore.Level) bool {
+	return f.enabled(l)
+}
+
+func (f levelFilter) With(fields []zapcore.Field) zapcore.Core {
+	return levelFilter{Core: f.Core.With(fields), enabled: f.enabled}
+}
+
+func (f levelFilter) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if f.enabled(entry.Level) {
+		return f.Core.Check(entry, checked)
+	}
+	return checked
 }
 
 // GetLogger returns the global logger instance
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Modules whose level can be changed separately from the global level
+const (
+	ModuleHTTP = "http"
+	ModuleDB   = "db"
+	ModuleJobs = "jobs"
+)
+
+// module is the logger of a subsystem. Until its level is set it follows
+// the global level.
+type module struct {
+	logger *zap.Logger
+	level  zap.AtomicLevel
+	custom atomic.Bool
+}
+
+// Enabled reports whether the module logs entries at l
+func (m *module) Enabled(l zapcore.Level) bool {
+	if m.custom.Load() {
+		return m.level.Enabled(l)
+	}
+	return level.Enabled(l)
+}
+
+// levelString returns the effective level of the module
+func (m *module) levelString() string {
+	if m.custom.Load() {
+		return m.level.String()
+	}
+	return level.String()
+}
+
+var (
+	modulesMu sync.RWMutex
+	modules   = map[string]*module{
+		ModuleHTTP: newModule(),
+		ModuleDB:   newModule(),
+		ModuleJobs: newModule(),
+	}
+)
+
+func newModule() *module {
+	return &module{level: zap.NewAtomicLevel()}
+}
+
+// initializeModules builds the module loggers on core and applies their
+// configured levels
+func initializeModules(core zapcore.Core, levels map[string]string) error {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	for name, m := range modules {
+		m.logger = zap.New(filterLevel(core, m), zap.AddCaller()).Named(name)
+		m.custom.Store(false)
+	}
+
+	for name, l := range levels {
+		if err := setModuleLevel(name, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Named returns the logger of a module such as ModuleDB. Its entries carry
+// the module name and are filtered by the module's level.
+func Named(name string) *zap.Logger {
+	modulesMu.RLock()
+	m, ok := modules[name]
+	modulesMu.RUnlock()
+
+	if !ok || m.logger == nil {
+		// Not a known module, or the logger isn't initialized yet
+		return GetLogger().WithOptions(zap.AddCallerSkip(-1)).Named(name)
+	}
+	return m.logger
+}
+
+// ModuleLevels returns the effective level of every module
+func ModuleLevels() map[string]string {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+
+	levels := make(map[string]string, len(modules))
+	for name, m := range modules {
+		levels[name] = m.levelString()
+	}
+	return levels
+}
+
+// ModuleNames returns the names of the modules, sorted
+func ModuleNames() []string {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetModuleLevel changes the level of a module without rebuilding its
+// logger. An empty level makes the module follow the global level again.
+func SetModuleLevel(name, l string) error {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+	return setModuleLevel(name, l)
+}
+
+func setModuleLevel(name, l string) error {
+	m, ok := modules[name]
+	if !ok {
+		return fmt.Errorf("unknown log module: %s", name)
+	}
+
+	if l == "" {
+		m.custom.Store(false)
+		return nil
+	}
+
+	parsed, err := zapcore.ParseLevel(l)
+	if err != nil {
+		return err
+	}
+	m.level.SetLevel(parsed)
+	m.custom.Store(true)
+	return nil
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readEntries returns the messages logged to path, prefixed by their logger
+// name when they have one
+func readEntries(t *testing.T, path string) []string {
+	t.Helper()
+	require.NoError(t, GetLogger().Sync())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var messages []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry struct {
+			Logger string `json:"logger"`
+			Msg    string `json:"msg"`
+		}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		if entry.Logger != "" {
+			entry.Msg = entry.Logger + ": " + entry.Msg
+		}
+		messages = append(messages, entry.Msg)
+	}
+	require.NoError(t, scanner.Err())
+	return messages
+}
+
+// TestModuleLevels tests that modules follow the global level until their
+// own level is set
+func TestModuleLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, Initialize(Config{
+		Level:  "info",
+		Output: path,
+		Levels: map[string]string{ModuleDB: "warn"},
+	}))
+	defer SetLevel("info")
+
+	Named(ModuleDB).Info("query")
+	Named(ModuleDB).Warn("slow query")
+	Named(ModuleJobs).Debug("job detail")
+	Named(ModuleJobs).Info("job done")
+	Debug("debug")
+
+	require.NoError(t, SetModuleLevel(ModuleJobs, "debug"))
+	require.NoError(t, SetModuleLevel(ModuleDB, ""))
+	require.NoError(t, SetLevel("error"))
+
+	Named(ModuleJobs).Debug("job detail")
+	Named(ModuleDB).Warn("slow query")
+	Info("info")
+
+	assert.Equal(t, []string{"db: slow query", "jobs: job done", "jobs: job detail"}, readEntries(t, path))
+	assert.Equal(t, map[string]string{ModuleHTTP: "error", ModuleDB: "error", ModuleJobs: "debug"}, ModuleLevels())
+}
+
+// TestSetModuleLevelInvalid tests that unknown modules and levels are rejected
+func TestSetModuleLevelInvalid(t *testing.T) {
+	assert.Error(t, SetModuleLevel("cache", "debug"))
+	assert.Error(t, SetModuleLevel(ModuleDB, "loud"))
+	assert.Error(t, Initialize(Config{Output: "stderr", Levels: map[string]string{"cache": "debug"}}))
+}
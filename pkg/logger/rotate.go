@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp inserted in rotated file names; it
+// sorts chronologically
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotationConfig controls log file rotation. A file is rotated once writing
+// would grow it past MaxSize megabytes; zero disables rotation.
+type RotationConfig struct {
+	MaxSize int
+	// MaxBackups is the number of rotated files kept; zero keeps all
+	MaxBackups int
+	// MaxAge removes rotated files older than this; zero keeps them
+	MaxAge time.Duration
+}
+
+// rotatingFile is a log file that is renamed, e.g. app.log to
+// app-2024-10-20T12-30-00.000.log, when it grows past its maximum size
+type rotatingFile struct {
+	mu     sync.Mutex
+	path   string
+	config RotationConfig
+	file   *os.File
+	size   int64
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// openRotatingFile opens path for appending, creating it if needed
+func openRotatingFile(path string, config RotationConfig) (*rotatingFile, error) {
+	f := &rotatingFile{
+		path:   path,
+		config: config,
+		now:    time.Now,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p to the file, rotating it first if p doesn't fit
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	maxSize := int64(f.config.MaxSize) * 1024 * 1024
+	if maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Sync commits the file to disk
+func (f *rotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Sync()
+}
+
+// Close closes the file
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// open opens the current file and records its size
+func (f *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file, starts a new one and removes old backups
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.path, f.backupName(f.now())); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.prune()
+	return nil
+}
+
+// backupName returns the name of the file rotated at t
+func (f *rotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext)
+	return base + "-" + t.UTC().Format(backupTimeFormat) + ext
+}
+
+// prune removes the backups beyond MaxBackups and those older than MaxAge.
+// Failures are ignored; the next rotation tries again.
+func (f *rotatingFile) prune() {
+	if f.config.MaxBackups == 0 && f.config.MaxAge == 0 {
+		return
+	}
+
+	ext := filepath.Ext(f.path)
+	prefix := filepath.Base(strings.TrimSuffix(f.path, ext)) + "-"
+
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return
+	}
+
+	type backup struct {
+		path string
+		at   time.Time
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		at, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(filepath.Dir(f.path), name), at: at})
+	}
+
+	// Newest first
+	sort.Slice(backups, func(i, j int) bool { return backups[i].at.After(backups[j].at) })
+
+	cutoff := f.now().Add(-f.config.MaxAge)
+	for i, b := range backups {
+		if (f.config.MaxBackups > 0 && i >= f.config.MaxBackups) || (f.config.MaxAge > 0 && b.at.Before(cutoff)) {
+			os.Remove(b.path)
+		}
+	}
+}
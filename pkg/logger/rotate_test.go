@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listLogs returns the names of the files in dir
+func listLogs(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+// TestRotatingFileRotates tests that the file is rotated when it would grow
+// past its maximum size and that only MaxBackups backups are kept
+func TestRotatingFileRotates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := openRotatingFile(path, RotationConfig{MaxSize: 1, MaxBackups: 2})
+	require.NoError(t, err)
+	defer f.Close()
+
+	now := time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return now }
+
+	line := []byte(strings.Repeat("x", 600*1024))
+	for i := 0; i < 5; i++ {
+		now = now.Add(time.Second)
+		_, err := f.Write(line)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"app-2024-10-20T12-00-04.000.log", "app-2024-10-20T12-00-05.000.log", "app.log"}, listLogs(t, dir))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(line)), info.Size())
+}
+
+// TestRotatingFileMaxAge tests that backups older than MaxAge are removed
+func TestRotatingFileMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	old := filepath.Join(dir, "app-2024-10-01T00-00-00.000.log")
+	require.NoError(t, os.WriteFile(old, []byte("old\n"), 0644))
+	other := filepath.Join(dir, "other.log")
+	require.NoError(t, os.WriteFile(other, []byte("other\n"), 0644))
+
+	f, err := openRotatingFile(path, RotationConfig{MaxSize: 1, MaxAge: 7 * 24 * time.Hour})
+	require.NoError(t, err)
+	defer f.Close()
+	f.now = func() time.Time { return time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC) }
+
+	line := []byte(strings.Repeat("x", 600*1024))
+	for i := 0; i < 2; i++ {
+		_, err := f.Write(line)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"app-2024-10-20T12-00-00.000.log", "app.log", "other.log"}, listLogs(t, dir))
+}
+
+// TestRotatingFileUnlimited tests that a zero MaxSize never rotates
+func TestRotatingFileUnlimited(t *testing.T) {
+	dir := t.TempDir()
+
+	f, err := openRotatingFile(filepath.Join(dir, "logs", "app.log"), RotationConfig{})
+	require.NoError(t, err)
+	defer f.Close()
+
+	line := []byte(strings.Repeat("x", 600*1024))
+	for i := 0; i < 3; i++ {
+		_, err := f.Write(line)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"app.log"}, listLogs(t, filepath.Join(dir, "logs")))
+}
@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ConsoleMailer writes messages to an io.Writer instead of sending them,
+// which is handy during local development
+type ConsoleMailer struct {
+	mu   sync.Mutex
+	from string
+	out  io.Writer
+}
+
+// NewConsoleMailer creates a console mailer writing to out (stdout if nil)
+func NewConsoleMailer(from string, out io.Writer) *ConsoleMailer {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &ConsoleMailer{from: from, out: out}
+}
+
+// Send prints the message
+func (m *ConsoleMailer) Send(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		msg.From = m.from
+	}
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	body := msg.TextBody
+	if body == "" {
+		body = msg.HTMLBody
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.out, "----- email -----\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n-----------------\n",
+		msg.From, strings.Join(msg.To, ", "), msg.Subject, body)
+	return err
+}
@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoRecipients is returned when a message has no recipients
+	ErrNoRecipients = errors.New("mailer: message has no recipients")
+	// ErrInvalidMessage is returned for messages that cannot be delivered
+	// as they are, such as ones without a body
+	ErrInvalidMessage = errors.New("mailer: invalid message")
+)
+
+// Config holds mailer configuration
+type Config struct {
+	Driver   string        `json:"driver" yaml:"driver"` // smtp, console, mock
+	From     string        `json:"from" yaml:"from"`
+	Host     string        `json:"host" yaml:"host"`
+	Port     int           `json:"port" yaml:"port"`
+	Username string        `json:"username" yaml:"username"`
+	Password string        `json:"password" yaml:"password"`
+	Timeout  time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// Message represents an email message
+type Message struct {
+	From     string
+	To       []string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// Mailer sends email messages
+type Mailer interface {
+	// Send delivers the message to all of its recipients
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NewMailer creates a mailer for the configured driver
+func NewMailer(cfg Config) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg)
+	case "console", "":
+		return NewConsoleMailer(cfg.From, nil), nil
+	case "mock":
+		return NewMockMailer(), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s", cfg.Driver)
+	}
+}
+
+// validate checks that a message can be delivered
+func (m *Message) validate() error {
+	if len(m.To) == 0 {
+		return ErrNoRecipients
+	}
+	if m.From == "" {
+		return fmt.Errorf("%w: no sender", ErrInvalidMessage)
+	}
+	for _, addr := range append([]string{m.From}, m.To...) {
+		if strings.ContainsAny(addr, "\r\n") {
+			return fmt.Errorf("%w: invalid address %q", ErrInvalidMessage, addr)
+		}
+	}
+	if m.TextBody == "" && m.HTMLBody == "" {
+		return fmt.Errorf("%w: no body", ErrInvalidMessage)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+// MockMailer records sent messages for use in tests
+type MockMailer struct {
+	mu   sync.Mutex
+	sent []*Message
+
+	// Err, when set, is returned by Send instead of recording the message
+	Err error
+}
+
+// NewMockMailer creates a mock mailer
+func NewMockMailer() *MockMailer {
+	return &MockMailer{}
+}
+
+// Send records the message
+func (m *MockMailer) Send(ctx context.Context, msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent returns a copy of all recorded messages
+func (m *MockMailer) Sent() []*Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]*Message(nil), m.sent...)
+}
+
+// Last returns the most recently recorded message or nil
+func (m *MockMailer) Last() *Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.sent) == 0 {
+		return nil
+	}
+	return m.sent[len(m.sent)-1]
+}
+
+// Reset clears recorded messages
+func (m *MockMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent = nil
+}
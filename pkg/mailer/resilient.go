@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"net/textproto"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/resilience"
+)
+
+// resilientMailer sends messages under a resilience policy
+type resilientMailer struct {
+	next   Mailer
+	policy *resilience.Policy
+}
+
+// NewResilientMailer retries messages the mail server failed to accept and
+// stops sending for a while when it keeps failing. Invalid messages and
+// permanent SMTP errors (5xx) are not retried.
+func NewResilientMailer(next Mailer, policy *resilience.Policy) Mailer {
+	return &resilientMailer{next: next, policy: policy}
+}
+
+func (m *resilientMailer) Send(ctx context.Context, msg *Message) error {
+	return m.policy.Do(ctx, func(ctx context.Context) error {
+		err := m.next.Send(ctx, msg)
+		if isPermanent(err) {
+			return resilience.Permanent(err)
+		}
+		return err
+	})
+}
+
+// isPermanent reports whether sending the message again cannot succeed
+func isPermanent(err error) bool {
+	if errors.Is(err, ErrNoRecipients) || errors.Is(err, ErrInvalidMessage) {
+		return true
+	}
+	var smtpErr *textproto.Error
+	return errors.As(err, &smtpErr) && smtpErr.Code >= 500
+}
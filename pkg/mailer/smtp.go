@@ -0,0 +1,215 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPMailer sends messages through an SMTP server
+type SMTPMailer struct {
+	cfg Config
+}
+
+// NewSMTPMailer creates an SMTP mailer
+func NewSMTPMailer(cfg Config) (*SMTPMailer, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("mailer: SMTP host is required")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &SMTPMailer{cfg: cfg}, nil
+}
+
+// Send delivers the message via SMTP. Port 465 uses implicit TLS, other
+// ports upgrade with STARTTLS when the server offers it
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		msg.From = m.cfg.From
+	}
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	body, err := buildMIME(msg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+
+	client, err := m.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	if err := m.deliver(client, msg, body); err != nil {
+		return fmt.Errorf("mailer: failed to send: %w", err)
+	}
+	return client.Quit()
+}
+
+// dial opens a connection and negotiates TLS
+func (m *SMTPMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: m.cfg.Host}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if m.cfg.Port == 465 {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if ok, _ := client.Extension("STARTTLS"); ok && m.cfg.Port != 465 {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// deliver authenticates and transmits the message
+func (m *SMTPMailer) deliver(client *smtp.Client, msg *Message, body []byte) error {
+	if m.cfg.Username != "" {
+		auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(extractAddress(msg.From)); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(extractAddress(to)); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// buildMIME encodes the message as multipart/alternative when both
+// text and HTML bodies are present
+func buildMIME(msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writeHeader := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+
+	writeHeader("From", msg.From)
+	writeHeader("To", strings.Join(msg.To, ", "))
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	writeHeader("Date", time.Now().Format(time.RFC1123Z))
+	writeHeader("MIME-Version", "1.0")
+
+	switch {
+	case msg.TextBody != "" && msg.HTMLBody != "":
+		boundary, err := newBoundary()
+		if err != nil {
+			return nil, err
+		}
+		writeHeader("Content-Type", `multipart/alternative; boundary="`+boundary+`"`)
+		buf.WriteString("\r\n")
+
+		for _, part := range []struct{ contentType, body string }{
+			{"text/plain", msg.TextBody},
+			{"text/html", msg.HTMLBody},
+		} {
+			buf.WriteString("--" + boundary + "\r\n")
+			writeHeader("Content-Type", part.contentType+"; charset=UTF-8")
+			writeHeader("Content-Transfer-Encoding", "quoted-printable")
+			buf.WriteString("\r\n")
+			if err := writeQuotedPrintable(&buf, part.body); err != nil {
+				return nil, err
+			}
+			buf.WriteString("\r\n")
+		}
+		buf.WriteString("--" + boundary + "--\r\n")
+	case msg.HTMLBody != "":
+		writeHeader("Content-Type", "text/html; charset=UTF-8")
+		writeHeader("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, msg.HTMLBody); err != nil {
+			return nil, err
+		}
+	default:
+		writeHeader("Content-Type", "text/plain; charset=UTF-8")
+		writeHeader("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, msg.TextBody); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeQuotedPrintable writes body using quoted-printable encoding
+func writeQuotedPrintable(buf *bytes.Buffer, body string) error {
+	w := quotedprintable.NewWriter(buf)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// newBoundary generates a random multipart boundary
+func newBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// extractAddress returns the bare address from "Name <addr>" forms
+func extractAddress(addr string) string {
+	if start := strings.LastIndex(addr, "<"); start >= 0 {
+		if end := strings.LastIndex(addr, ">"); end > start {
+			return addr[start+1 : end]
+		}
+	}
+	return strings.TrimSpace(addr)
+}
@@ -0,0 +1,102 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*
+var defaultTemplates embed.FS
+
+// Renderer renders email bodies from named templates. A template named
+// "welcome" is looked up as "welcome.html" and "welcome.txt"; either may
+// be omitted
+type Renderer struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// NewRenderer parses the *.html and *.txt templates found in fsys
+func NewRenderer(fsys fs.FS) (*Renderer, error) {
+	r := &Renderer{
+		html: htmltemplate.New(""),
+		text: texttemplate.New(""),
+	}
+
+	htmlFiles, err := fs.Glob(fsys, "*.html")
+	if err != nil {
+		return nil, err
+	}
+	if len(htmlFiles) > 0 {
+		if r.html, err = r.html.ParseFS(fsys, "*.html"); err != nil {
+			return nil, fmt.Errorf("mailer: failed to parse html templates: %w", err)
+		}
+	}
+
+	textFiles, err := fs.Glob(fsys, "*.txt")
+	if err != nil {
+		return nil, err
+	}
+	if len(textFiles) > 0 {
+		if r.text, err = r.text.ParseFS(fsys, "*.txt"); err != nil {
+			return nil, fmt.Errorf("mailer: failed to parse text templates: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// NewDefaultRenderer creates a renderer for the built-in templates
+func NewDefaultRenderer() (*Renderer, error) {
+	sub, err := fs.Sub(defaultTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	return NewRenderer(sub)
+}
+
+// Render executes the html and text variants of the named template
+func (r *Renderer) Render(name string, data interface{}) (htmlBody, textBody string, err error) {
+	found := false
+
+	if t := r.html.Lookup(name + ".html"); t != nil {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return "", "", fmt.Errorf("mailer: failed to render %s.html: %w", name, err)
+		}
+		htmlBody = buf.String()
+		found = true
+	}
+
+	if t := r.text.Lookup(name + ".txt"); t != nil {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return "", "", fmt.Errorf("mailer: failed to render %s.txt: %w", name, err)
+		}
+		textBody = buf.String()
+		found = true
+	}
+
+	if !found {
+		return "", "", fmt.Errorf("mailer: template %q not found", name)
+	}
+	return htmlBody, textBody, nil
+}
+
+// Message renders the named template into a ready-to-send message
+func (r *Renderer) Message(name string, data interface{}, subject string, to ...string) (*Message, error) {
+	htmlBody, textBody, err := r.Render(name, data)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		To:       to,
+		Subject:  subject,
+		HTMLBody: htmlBody,
+		TextBody: textBody,
+	}, nil
+}
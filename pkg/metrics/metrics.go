@@ -0,0 +1,341 @@
+// Package metrics provides counters, gauges and histograms exposed in the
+// Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Default is the registry served by the metrics endpoint
+var Default = NewRegistry()
+
+// Metric types
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// DefaultBuckets are histogram buckets in seconds suited to request and
+// query latencies
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry holds metric families
+type Registry struct {
+	mu       sync.RWMutex
+	families map[string]*family
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// family is a metric with its labeled series
+type family struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+	// buckets are the upper bounds of histogram buckets, in increasing order
+	buckets []float64
+
+	mu     sync.RWMutex
+	series map[string]*series
+}
+
+// series is the value of a family for one set of label values. For
+// histograms the value is the sum of observations, counts holds the
+// observations per bucket and count their number.
+type series struct {
+	labelValues []string
+	bits        atomic.Uint64
+	counts      []atomic.Uint64
+	count       atomic.Uint64
+}
+
+func (s *series) value() float64 {
+	return math.Float64frombits(s.bits.Load())
+}
+
+func (s *series) set(v float64) {
+	s.bits.Store(math.Float64bits(v))
+}
+
+func (s *series) add(v float64) {
+	for {
+		old := s.bits.Load()
+		if s.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+// register returns the family called name, creating it if needed. Creating
+// a family twice with a different type or labels panics, like registering a
+// duplicate expvar.
+func (r *Registry) register(name, help, kind string, labelNames []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f, ok := r.families[name]; ok {
+		if f.kind != kind || strings.Join(f.labelNames, ",") != strings.Join(labelNames, ",") ||
+			fmt.Sprint(f.buckets) != fmt.Sprint(buckets) {
+			panic(fmt.Sprintf("metrics: %s registered twice with different types or labels", name))
+		}
+		return f
+	}
+
+	f := &family{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// with returns the series for labelValues, creating it if needed
+func (f *family) with(labelValues []string) *series {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	f.mu.RLock()
+	s, ok := f.series[key]
+	f.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok := f.series[key]; ok {
+		return s
+	}
+	s = &series{labelValues: append([]string(nil), labelValues...)}
+	if f.kind == TypeHistogram {
+		s.counts = make([]atomic.Uint64, len(f.buckets))
+	}
+	f.series[key] = s
+	return s
+}
+
+// CounterVec is a counter partitioned by labels
+type CounterVec struct {
+	family *family
+}
+
+// NewCounter registers a counter. Counter names should end in _total.
+func (r *Registry) NewCounter(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{family: r.register(name, help, TypeCounter, labelNames, nil)}
+}
+
+// With returns the counter for the label values
+func (v *CounterVec) With(labelValues ...string) *Counter {
+	return &Counter{series: v.family.with(labelValues)}
+}
+
+// Counter is a value that only goes up
+type Counter struct {
+	series *series
+}
+
+// Inc adds one
+func (c *Counter) Inc() {
+	c.series.add(1)
+}
+
+// Add adds v, which must not be negative
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		panic("metrics: counters cannot decrease")
+	}
+	c.series.add(v)
+}
+
+// GaugeVec is a gauge partitioned by labels
+type GaugeVec struct {
+	family *family
+}
+
+// NewGauge registers a gauge
+func (r *Registry) NewGauge(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{family: r.register(name, help, TypeGauge, labelNames, nil)}
+}
+
+// With returns the gauge for the label values
+func (v *GaugeVec) With(labelValues ...string) *Gauge {
+	return &Gauge{series: v.family.with(labelValues)}
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	series *series
+}
+
+// Set sets the gauge to v
+func (g *Gauge) Set(v float64) {
+	g.series.set(v)
+}
+
+// Add adds v, which may be negative
+func (g *Gauge) Add(v float64) {
+	g.series.add(v)
+}
+
+// HistogramVec is a histogram partitioned by labels
+type HistogramVec struct {
+	family *family
+}
+
+// NewHistogram registers a histogram counting observations in buckets with
+// the given upper bounds, e.g. DefaultBuckets
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("metrics: %s buckets are not sorted", name))
+	}
+	return &HistogramVec{family: r.register(name, help, TypeHistogram, labelNames, buckets)}
+}
+
+// With returns the histogram for the label values
+func (v *HistogramVec) With(labelValues ...string) *Histogram {
+	return &Histogram{series: v.family.with(labelValues), buckets: v.family.buckets}
+}
+
+// Histogram counts observations, such as latencies, in buckets
+type Histogram struct {
+	series  *series
+	buckets []float64
+}
+
+// Observe records v in the first bucket it fits in, or only in the count
+// and sum when it exceeds every bucket
+func (h *Histogram) Observe(v float64) {
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		h.series.counts[i].Add(1)
+	}
+	h.series.count.Add(1)
+	h.series.add(v)
+}
+
+// WriteTo writes every metric in the Prometheus text format, sorted by name
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mu.RUnlock()
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	var b strings.Builder
+	for _, f := range families {
+		f.write(&b)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// write appends the family in the text format
+func (f *family) write(b *strings.Builder) {
+	f.mu.RLock()
+	series := make([]*series, 0, len(f.series))
+	for _, s := range f.series {
+		series = append(series, s)
+	}
+	f.mu.RUnlock()
+	if len(series) == 0 {
+		return
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return strings.Join(series[i].labelValues, "\xff") < strings.Join(series[j].labelValues, "\xff")
+	})
+
+	fmt.Fprintf(b, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(b, "# TYPE %s %s\n", f.name, f.kind)
+	for _, s := range series {
+		if f.kind != TypeHistogram {
+			f.writeSample(b, f.name, s, "", s.value())
+			continue
+		}
+
+		// Buckets are cumulative
+		var cumulative uint64
+		for i, bound := range f.buckets {
+			cumulative += s.counts[i].Load()
+			f.writeSample(b, f.name+"_bucket", s, formatValue(bound), float64(cumulative))
+		}
+		f.writeSample(b, f.name+"_bucket", s, "+Inf", float64(s.count.Load()))
+		f.writeSample(b, f.name+"_sum", s, "", s.value())
+		f.writeSample(b, f.name+"_count", s, "", float64(s.count.Load()))
+	}
+}
+
+// writeSample appends a line for the series, with the le label of a
+// histogram bucket unless le is empty
+func (f *family) writeSample(b *strings.Builder, name string, s *series, le string, value float64) {
+	b.WriteString(name)
+	if len(f.labelNames) > 0 || le != "" {
+		b.WriteByte('{')
+		for i, name := range f.labelNames {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(b, `%s="%s"`, name, labelEscaper.Replace(s.labelValues[i]))
+		}
+		if le != "" {
+			if len(f.labelNames) > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(b, `le="%s"`, le)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatValue(value))
+	b.WriteByte('\n')
+}
+
+// formatValue formats v the way Prometheus parses it
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// escapeHelp escapes backslashes and line breaks in help texts
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+// labelEscaper escapes label values
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Handler serves the registry in the Prometheus text format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteTo(w)
+	})
+}
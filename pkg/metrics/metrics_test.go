@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryWriteTo(t *testing.T) {
+	r := NewRegistry()
+	queries := r.NewCounter("db_queries_total", "Queries run.", "status")
+	open := r.NewGauge("db_open_connections", "Open connections.\nPer pool.", "pool")
+	r.NewGauge("unused", "Never set.")
+
+	queries.With("ok").Add(2)
+	queries.With("error").Inc()
+	open.With("replica_0").Set(1.5)
+	open.With("primary").Set(4)
+	open.With("primary").Add(-1)
+
+	var b strings.Builder
+	_, err := r.WriteTo(&b)
+	require.NoError(t, err)
+
+	assert.Equal(t, `# HELP db_open_connections Open connections.\nPer pool.
+# TYPE db_open_connections gauge
+db_open_connections{pool="primary"} 3
+db_open_connections{pool="replica_0"} 1.5
+# HELP db_queries_total Queries run.
+# TYPE db_queries_total counter
+db_queries_total{status="error"} 1
+db_queries_total{status="ok"} 2
+`, b.String())
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	latency := r.NewHistogram("op_duration_seconds", "Operation latency.", []float64{0.1, 1}, "op")
+
+	latency.With("get").Observe(0.05)
+	latency.With("get").Observe(0.1)
+	latency.With("get").Observe(0.5)
+	latency.With("get").Observe(2)
+
+	var b strings.Builder
+	_, err := r.WriteTo(&b)
+	require.NoError(t, err)
+
+	assert.Equal(t, `# HELP op_duration_seconds Operation latency.
+# TYPE op_duration_seconds histogram
+op_duration_seconds_bucket{op="get",le="0.1"} 2
+op_duration_seconds_bucket{op="get",le="1"} 3
+op_duration_seconds_bucket{op="get",le="+Inf"} 4
+op_duration_seconds_sum{op="get"} 2.65
+op_duration_seconds_count{op="get"} 4
+`, b.String())
+	assert.Panics(t, func() { r.NewHistogram("op_duration_seconds", "Operation latency.", DefaultBuckets, "op") })
+	assert.Panics(t, func() { r.NewHistogram("unsorted", "Unsorted.", []float64{1, 0.1}) })
+}
+
+func TestRegistryConcurrentAdd(t *testing.T) {
+	counter := NewRegistry().NewCounter("events_total", "Events.").With()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				counter.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, float64(8000), counter.series.value())
+}
+
+func TestRegistryRegisterTwice(t *testing.T) {
+	r := NewRegistry()
+	first := r.NewGauge("pool_size", "Pool size.", "pool")
+	second := r.NewGauge("pool_size", "Pool size.", "pool")
+	first.With("primary").Set(3)
+
+	assert.Equal(t, float64(3), second.With("primary").series.value())
+	assert.Panics(t, func() { r.NewCounter("pool_size", "Pool size.", "pool") })
+	assert.Panics(t, func() { first.With() })
+}
+
+func TestRegistryHandler(t *testing.T) {
+	r := NewRegistry()
+	r.NewGauge("up", "Whether the server is up.").With().Set(1)
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "up 1\n")
+}
@@ -0,0 +1,55 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported hashing algorithms
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// Config holds password hashing configuration
+type Config struct {
+	Algorithm  string       `json:"algorithm" yaml:"algorithm"` // bcrypt, argon2id
+	BcryptCost int          `json:"bcrypt_cost" yaml:"bcrypt_cost"`
+	Argon2     Argon2Params `json:"argon2" yaml:"argon2"`
+}
+
+// Hasher hashes and verifies passwords
+type Hasher interface {
+	// Hash returns an encoded hash of the password
+	Hash(password string) (string, error)
+
+	// Verify reports whether the password matches the encoded hash. Hashes
+	// of every supported algorithm are accepted, so switching algorithms
+	// does not lock out existing users.
+	Verify(hash, password string) bool
+
+	// NeedsRehash reports whether the hash was produced with a different
+	// algorithm or parameters than the hasher's current ones
+	NeedsRehash(hash string) bool
+}
+
+// NewHasher creates a hasher for the configured algorithm
+func NewHasher(cfg Config) (Hasher, error) {
+	switch cfg.Algorithm {
+	case AlgorithmBcrypt, "":
+		return NewBcryptHasher(cfg.BcryptCost)
+	case AlgorithmArgon2id:
+		return NewArgon2idHasher(cfg.Argon2)
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", cfg.Algorithm)
+	}
+}
+
+// verify checks a password against a hash of any supported algorithm,
+// using the parameters encoded in the hash
+func verify(hash, password string) bool {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		return verifyArgon2id(hash, password)
+	}
+	return verifyBcrypt(hash, password)
+}
@@ -0,0 +1,89 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testArgon2Params keeps argon2id cheap enough for tests
+var testArgon2Params = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1}
+
+// TestHashAndVerify tests that each algorithm verifies its own hashes
+func TestHashAndVerify(t *testing.T) {
+	configs := map[string]Config{
+		AlgorithmBcrypt:   {Algorithm: AlgorithmBcrypt, BcryptCost: 4},
+		AlgorithmArgon2id: {Algorithm: AlgorithmArgon2id, Argon2: testArgon2Params},
+	}
+
+	for name, cfg := range configs {
+		hasher, err := NewHasher(cfg)
+		require.NoError(t, err, name)
+
+		hash, err := hasher.Hash("password123")
+		require.NoError(t, err, name)
+
+		assert.True(t, hasher.Verify(hash, "password123"), name)
+		assert.False(t, hasher.Verify(hash, "wrong-password"), name)
+		assert.False(t, hasher.NeedsRehash(hash), name)
+	}
+}
+
+// TestSwitchAlgorithm tests that hashes of the previous algorithm still
+// verify and are flagged for rehashing
+func TestSwitchAlgorithm(t *testing.T) {
+	bcryptHasher, err := NewBcryptHasher(4)
+	require.NoError(t, err)
+	argon2Hasher, err := NewArgon2idHasher(testArgon2Params)
+	require.NoError(t, err)
+
+	bcryptHash, err := bcryptHasher.Hash("password123")
+	require.NoError(t, err)
+	argon2Hash, err := argon2Hasher.Hash("password123")
+	require.NoError(t, err)
+
+	assert.True(t, argon2Hasher.Verify(bcryptHash, "password123"))
+	assert.True(t, argon2Hasher.NeedsRehash(bcryptHash))
+
+	assert.True(t, bcryptHasher.Verify(argon2Hash, "password123"))
+	assert.True(t, bcryptHasher.NeedsRehash(argon2Hash))
+}
+
+// TestNeedsRehashOnParameterChange tests that changed costs trigger a rehash
+func TestNeedsRehashOnParameterChange(t *testing.T) {
+	bcryptHasher, err := NewBcryptHasher(4)
+	require.NoError(t, err)
+	hash, err := bcryptHasher.Hash("password123")
+	require.NoError(t, err)
+
+	strongerBcrypt, err := NewBcryptHasher(5)
+	require.NoError(t, err)
+	assert.True(t, strongerBcrypt.NeedsRehash(hash))
+
+	argon2Hasher, err := NewArgon2idHasher(testArgon2Params)
+	require.NoError(t, err)
+	hash, err = argon2Hasher.Hash("password123")
+	require.NoError(t, err)
+
+	params := testArgon2Params
+	params.Iterations = 2
+	strongerArgon2, err := NewArgon2idHasher(params)
+	require.NoError(t, err)
+	assert.True(t, strongerArgon2.NeedsRehash(hash))
+	assert.True(t, strongerArgon2.Verify(hash, "password123"))
+}
+
+// TestNewHasherInvalidConfig tests rejection of unusable configurations
+func TestNewHasherInvalidConfig(t *testing.T) {
+	_, err := NewHasher(Config{Algorithm: "md5"})
+	assert.Error(t, err)
+
+	_, err = NewHasher(Config{Algorithm: AlgorithmBcrypt, BcryptCost: 40})
+	assert.Error(t, err)
+
+	hasher, err := NewHasher(Config{Algorithm: AlgorithmArgon2id, Argon2: testArgon2Params})
+	require.NoError(t, err)
+	assert.False(t, hasher.Verify("$argon2id$v=19$m=64,t=1,p=1$bad$hash", "password123"))
+	assert.False(t, hasher.Verify("", "password123"))
+}
@@ -0,0 +1,136 @@
+// Package ratelimit counts events on a cache.Client: requests in fixed time
+// windows, and failures that lock a key out. With the Redis cache the counts
+// are shared by all instances; with the in-memory cache they are per process,
+// which suits single-node deployments.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+)
+
+// Result describes a key's standing after an event was counted
+type Result struct {
+	// Allowed reports whether the event is within the limit
+	Allowed bool
+	// Limit is the number of events allowed per window
+	Limit int
+	// Remaining is the number of events left in the window
+	Remaining int
+	// Reset is how long until the window ends
+	Reset time.Duration
+}
+
+// Limiter allows a number of events per key in each fixed time window
+type Limiter struct {
+	cache  cache.Client
+	prefix string
+	limit  int
+	window time.Duration
+	now    func() time.Time
+}
+
+// NewLimiter creates a limiter allowing limit events per window, keeping
+// its counters under the cache key prefix
+func NewLimiter(client cache.Client, prefix string, limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		cache:  client,
+		prefix: prefix,
+		limit:  limit,
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// Allow counts an event for key in the current window
+func (l *Limiter) Allow(ctx context.Context, key string) (Result, error) {
+	now := l.now()
+	start := now.Truncate(l.window)
+	reset := start.Add(l.window).Sub(now)
+
+	count, err := incr(ctx, l.cache, fmt.Sprintf("%s%s:%d", l.prefix, key, start.Unix()), l.window)
+	if err != nil {
+		return Result{}, err
+	}
+
+	remaining := l.limit - int(count)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{
+		Allowed:   count <= int64(l.limit),
+		Limit:     l.limit,
+		Remaining: remaining,
+		Reset:     reset,
+	}, nil
+}
+
+// Lockout locks a key out once it has failed a number of times in a row.
+// The lockout lasts for its duration after the first failure.
+type Lockout struct {
+	cache       cache.Client
+	prefix      string
+	maxFailures int
+	duration    time.Duration
+}
+
+// NewLockout creates a lockout after maxFailures failures, keeping its
+// counters under the cache key prefix
+func NewLockout(client cache.Client, prefix string, maxFailures int, duration time.Duration) *Lockout {
+	return &Lockout{
+		cache:       client,
+		prefix:      prefix,
+		maxFailures: maxFailures,
+		duration:    duration,
+	}
+}
+
+// Locked reports whether key is locked out
+func (l *Lockout) Locked(ctx context.Context, key string) (bool, error) {
+	value, err := l.cache.Get(ctx, l.prefix+key)
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	failures, err := strconv.Atoi(value)
+	if err != nil {
+		return false, err
+	}
+	return failures >= l.maxFailures, nil
+}
+
+// Fail counts a failure for key and reports whether it is now locked out
+func (l *Lockout) Fail(ctx context.Context, key string) (bool, error) {
+	failures, err := incr(ctx, l.cache, l.prefix+key, l.duration)
+	if err != nil {
+		return false, err
+	}
+	return failures >= int64(l.maxFailures), nil
+}
+
+// Reset clears the failures of key, as after a success
+func (l *Lockout) Reset(ctx context.Context, key string) error {
+	return l.cache.Delete(ctx, l.prefix+key)
+}
+
+// incr increments the counter at key, which expires ttl after it was created
+func incr(ctx context.Context, client cache.Client, key string, ttl time.Duration) (int64, error) {
+	count, err := client.Incr(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		if err := client.Expire(ctx, key, ttl); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clients returns the in-memory cache and a Redis cache by name, along with
+// the in-process Redis server, whose clock tests advance
+func clients(t *testing.T) (map[string]cache.Client, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	redisClient, err := cache.NewRedisClient(cache.Config{Addr: server.Addr()})
+	require.NoError(t, err)
+	t.Cleanup(func() { redisClient.Close() })
+
+	return map[string]cache.Client{
+		"memory": cache.NewMemoryClient(),
+		"redis":  redisClient,
+	}, server
+}
+
+func TestLimiter(t *testing.T) {
+	caches, _ := clients(t)
+	for name, client := range caches {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Date(2024, 1, 1, 12, 0, 10, 0, time.UTC)
+			limiter := NewLimiter(client, "rate_limit:", 2, time.Minute)
+			limiter.now = func() time.Time { return now }
+
+			for i, remaining := range []int{1, 0} {
+				result, err := limiter.Allow(ctx, "10.0.0.1")
+				require.NoError(t, err)
+				assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: remaining, Reset: 50 * time.Second}, result, "request %d", i+1)
+			}
+
+			result, err := limiter.Allow(ctx, "10.0.0.1")
+			require.NoError(t, err)
+			assert.False(t, result.Allowed)
+			assert.Equal(t, 0, result.Remaining)
+
+			// Other keys have their own counters
+			result, err = limiter.Allow(ctx, "10.0.0.2")
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+
+			// The next window starts over
+			now = now.Add(time.Minute)
+			result, err = limiter.Allow(ctx, "10.0.0.1")
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 1, result.Remaining)
+		})
+	}
+}
+
+func TestLockout(t *testing.T) {
+	caches, server := clients(t)
+	for name, client := range caches {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			// Redis expires keys in whole seconds, on a clock the test controls
+			duration := 50 * time.Millisecond
+			if name == "redis" {
+				duration = time.Second
+			}
+			lockout := NewLockout(client, "login_failures:", 3, duration)
+
+			for i := 0; i < 2; i++ {
+				locked, err := lockout.Fail(ctx, "alice@example.com")
+				require.NoError(t, err)
+				assert.False(t, locked)
+			}
+			locked, err := lockout.Locked(ctx, "alice@example.com")
+			require.NoError(t, err)
+			assert.False(t, locked)
+
+			// A success clears the failures
+			require.NoError(t, lockout.Reset(ctx, "alice@example.com"))
+			for i := 0; i < 2; i++ {
+				_, err := lockout.Fail(ctx, "alice@example.com")
+				require.NoError(t, err)
+			}
+			locked, err = lockout.Fail(ctx, "alice@example.com")
+			require.NoError(t, err)
+			assert.True(t, locked)
+			locked, err = lockout.Locked(ctx, "alice@example.com")
+			require.NoError(t, err)
+			assert.True(t, locked)
+
+			locked, err = lockout.Locked(ctx, "bob@example.com")
+			require.NoError(t, err)
+			assert.False(t, locked)
+
+			// The lockout ends with its duration
+			if name == "redis" {
+				server.FastForward(duration)
+			} else {
+				time.Sleep(2 * duration)
+			}
+			locked, err = lockout.Locked(ctx, "alice@example.com")
+			require.NoError(t, err)
+			assert.False(t, locked)
+		})
+	}
+}
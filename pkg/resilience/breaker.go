@@ -0,0 +1,184 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned for calls rejected by an open circuit breaker
+var ErrOpen = errors.New("resilience: circuit breaker is open")
+
+// State is the state of a circuit breaker
+type State int
+
+// Circuit breaker states
+const (
+	// StateClosed lets calls through and counts consecutive failures
+	StateClosed State = iota
+	// StateHalfOpen lets a single trial call through after the open timeout
+	StateHalfOpen
+	// StateOpen rejects calls until the open timeout elapses
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "open"
+	}
+}
+
+// BreakerConfig holds circuit breaker settings
+type BreakerConfig struct {
+	// FailureThreshold consecutive failures open the breaker; zero disables it
+	FailureThreshold int `json:"failure_threshold" yaml:"failure_threshold"`
+	// OpenTimeout is how long the breaker rejects calls before a trial call
+	OpenTimeout time.Duration `json:"open_timeout" yaml:"open_timeout"`
+	// IsFailure reports whether an error counts as a failure of the
+	// service; nil counts every error
+	IsFailure func(error) bool `json:"-" yaml:"-"`
+}
+
+// Breaker stops calling a failing service for a while, so that callers fail
+// fast instead of waiting on it, and the service gets time to recover. It
+// is safe for concurrent use.
+type Breaker struct {
+	name string
+	cfg  BreakerConfig
+	now  func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	// trial is set while the half-open trial call runs
+	trial bool
+}
+
+// NewBreaker creates a closed breaker; name labels its metrics
+func NewBreaker(name string, cfg BreakerConfig) *Breaker {
+	b := &Breaker{name: name, cfg: cfg, now: time.Now}
+	if cfg.FailureThreshold > 0 {
+		breakerState.With(name).Set(float64(StateClosed))
+	}
+	return b
+}
+
+// State returns the current state. An open breaker whose timeout elapsed
+// reports half-open.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// Execute calls fn unless the breaker is open, and records its result.
+// Errors from a cancelled ctx are not held against the service.
+func (b *Breaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	if b.cfg.FailureThreshold <= 0 {
+		return fn(ctx)
+	}
+
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	err := fn(ctx)
+	if ctx.Err() != nil {
+		b.release()
+		return err
+	}
+	b.record(err != nil && (b.cfg.IsFailure == nil || b.cfg.IsFailure(err)))
+	return err
+}
+
+// allow admits a call, moving an open breaker to half-open once its
+// timeout elapsed
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.setState(StateHalfOpen)
+	}
+
+	switch {
+	case b.state == StateOpen, b.state == StateHalfOpen && b.trial:
+		breakerRejectedTotal.With(b.name).Inc()
+		return ErrOpen
+	case b.state == StateHalfOpen:
+		b.trial = true
+	}
+	return nil
+}
+
+// release ends a call without recording a result
+func (b *Breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// record counts the result of a call: the threshold of consecutive failures
+// or a failed trial opens the breaker, a success closes it
+func (b *Breaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+
+	if !failed {
+		b.failures = 0
+		b.setState(StateClosed)
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.openedAt = b.now()
+		b.setState(StateOpen)
+	}
+}
+
+// setState changes the state and its gauge
+func (b *Breaker) setState(state State) {
+	b.state = state
+	breakerState.With(b.name).Set(float64(state))
+}
+
+// BreakerGroup holds a breaker per key, e.g. per host, so that one failing
+// endpoint doesn't stop calls to the others
+type BreakerGroup struct {
+	name string
+	cfg  BreakerConfig
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// NewBreakerGroup creates a group whose breakers are called name:key
+func NewBreakerGroup(name string, cfg BreakerConfig) *BreakerGroup {
+	return &BreakerGroup{name: name, cfg: cfg, breakers: make(map[string]*Breaker)}
+}
+
+// Get returns the breaker of key, creating it closed
+func (g *BreakerGroup) Get(key string) *Breaker {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b, ok := g.breakers[key]
+	if !ok {
+		b = NewBreaker(g.name+":"+key, g.cfg)
+		g.breakers[key] = b
+	}
+	return b
+}
@@ -0,0 +1,98 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestBreaker creates a breaker on a clock the test advances
+func newTestBreaker(cfg BreakerConfig) (*Breaker, *time.Time) {
+	now := time.Unix(0, 0)
+	b := NewBreaker("test", cfg)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func fail(context.Context) error    { return errors.New("unavailable") }
+func succeed(context.Context) error { return nil }
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	ctx := context.Background()
+	b, now := newTestBreaker(BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute})
+
+	assert.Error(t, b.Execute(ctx, fail))
+	assert.NoError(t, b.Execute(ctx, succeed))
+	assert.Error(t, b.Execute(ctx, fail))
+	assert.Equal(t, StateClosed, b.State())
+
+	assert.Error(t, b.Execute(ctx, fail))
+	assert.Equal(t, StateOpen, b.State())
+
+	called := false
+	err := b.Execute(ctx, func(context.Context) error { called = true; return nil })
+	assert.ErrorIs(t, err, ErrOpen)
+	assert.False(t, called)
+
+	*now = now.Add(time.Minute)
+	assert.Equal(t, StateHalfOpen, b.State())
+}
+
+func TestBreakerHalfOpenTrial(t *testing.T) {
+	ctx := context.Background()
+	b, now := newTestBreaker(BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	assert.Error(t, b.Execute(ctx, fail))
+	*now = now.Add(time.Minute)
+
+	// A failed trial opens the breaker again
+	assert.Error(t, b.Execute(ctx, fail))
+	assert.Equal(t, StateOpen, b.State())
+
+	*now = now.Add(time.Minute)
+	err := b.Execute(ctx, func(ctx context.Context) error {
+		// Other calls are rejected while the trial runs
+		assert.ErrorIs(t, b.Execute(ctx, succeed), ErrOpen)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreakerIgnoresNonFailures(t *testing.T) {
+	ctx := context.Background()
+	notFound := errors.New("not found")
+	b, _ := newTestBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Minute,
+		IsFailure:        func(err error) bool { return !errors.Is(err, notFound) },
+	})
+
+	assert.ErrorIs(t, b.Execute(ctx, func(context.Context) error { return notFound }), notFound)
+	assert.Equal(t, StateClosed, b.State())
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Error(t, b.Execute(cancelled, func(ctx context.Context) error { return ctx.Err() }))
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b, _ := newTestBreaker(BreakerConfig{})
+	for i := 0; i < 10; i++ {
+		assert.Error(t, b.Execute(context.Background(), fail))
+	}
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreakerGroup(t *testing.T) {
+	g := NewBreakerGroup("webhook", BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	assert.Error(t, g.Get("a.example.com").Execute(context.Background(), fail))
+	assert.Same(t, g.Get("a.example.com"), g.Get("a.example.com"))
+	assert.Equal(t, StateOpen, g.Get("a.example.com").State())
+	assert.Equal(t, StateClosed, g.Get("b.example.com").State())
+}
@@ -0,0 +1,75 @@
+// Package resilience protects calls to external services with circuit
+// breakers, retries with jittered backoff and timeouts. A Policy combines
+// the three and decorates any call taking a context.
+package resilience
+
+import (
+	"context"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/metrics"
+)
+
+// Metrics of breakers and retries, labeled with their names
+var (
+	breakerState = metrics.Default.NewGauge("circuit_breaker_state",
+		"Circuit breaker state: 0 closed, 1 half-open, 2 open.", "breaker")
+	breakerRejectedTotal = metrics.Default.NewCounter("circuit_breaker_rejected_total",
+		"Calls rejected by an open circuit breaker.", "breaker")
+	retriesTotal = metrics.Default.NewCounter("retries_total",
+		"Calls retried after a failed attempt.", "operation")
+)
+
+// Config holds the settings of a Policy
+type Config struct {
+	// Timeout bounds every attempt; zero leaves attempts unbounded
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+	Retry   RetryConfig   `json:"retry" yaml:"retry"`
+	Breaker BreakerConfig `json:"breaker" yaml:"breaker"`
+}
+
+// Policy retries a call, passing every attempt through a circuit breaker
+// and bounding it with a timeout. It is safe for concurrent use.
+type Policy struct {
+	name    string
+	timeout time.Duration
+	retry   RetryConfig
+	breaker *Breaker
+}
+
+// NewPolicy creates a policy whose breaker and metrics are called name
+func NewPolicy(name string, cfg Config) *Policy {
+	return &Policy{
+		name:    name,
+		timeout: cfg.Timeout,
+		retry:   cfg.Retry,
+		breaker: NewBreaker(name, cfg.Breaker),
+	}
+}
+
+// Do calls fn under the policy. Calls rejected by the open breaker return
+// ErrOpen and are not retried.
+func (p *Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return Retry(ctx, p.name, p.retry, func(ctx context.Context) error {
+		return p.breaker.Execute(ctx, func(ctx context.Context) error {
+			return Timeout(ctx, p.timeout, fn)
+		})
+	})
+}
+
+// Breaker returns the circuit breaker of the policy
+func (p *Policy) Breaker() *Breaker {
+	return p.breaker
+}
+
+// Timeout calls fn with a context cancelled after d; zero calls fn with ctx.
+// fn must honor the context for the timeout to take effect.
+func Timeout(ctx context.Context, d time.Duration, fn func(ctx context.Context) error) error {
+	if d <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	return fn(ctx)
+}
@@ -0,0 +1,88 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// RetryConfig holds retry settings
+type RetryConfig struct {
+	// MaxAttempts includes the first call; one or less disables retries
+	MaxAttempts int `json:"max_attempts" yaml:"max_attempts"`
+	// InitialBackoff is the delay before the first retry, doubling for each
+	// further one up to MaxBackoff. Delays are jittered between half and
+	// the full value.
+	InitialBackoff time.Duration `json:"initial_backoff" yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `json:"max_backoff" yaml:"max_backoff"`
+	// Retryable reports whether a failed call may succeed when retried; nil
+	// retries every error
+	Retryable func(error) bool `json:"-" yaml:"-"`
+}
+
+// permanentError marks an error that is not retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err so that Retry returns it without retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Retry calls fn until it succeeds, returns a permanent or non-retryable
+// error, the attempts run out or ctx is done. Calls rejected by an open
+// breaker are not retried. The last error is returned; operation labels
+// the retry metric.
+func Retry(ctx context.Context, operation string, cfg RetryConfig, fn func(ctx context.Context) error) error {
+	backoff := cfg.InitialBackoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	maxBackoff := max(cfg.MaxBackoff, backoff)
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= cfg.MaxAttempts || !retryable(cfg, err) {
+			var permanent *permanentError
+			if errors.As(err, &permanent) {
+				return permanent.err
+			}
+			return err
+		}
+
+		timer := time.NewTimer(jitter(backoff))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		retriesTotal.With(operation).Inc()
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// retryable reports whether err may be retried
+func retryable(cfg RetryConfig, err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) || errors.Is(err, ErrOpen) {
+		return false
+	}
+	return cfg.Retryable == nil || cfg.Retryable(err)
+}
+
+// jitter returns a random delay between half and all of d, so that clients
+// failing together don't retry together
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
@@ -0,0 +1,111 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastRetry = RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+
+	calls := 0
+	err := Retry(ctx, "test", fastRetry, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = Retry(ctx, "test", fastRetry, func(context.Context) error {
+		calls++
+		return errors.New("unavailable")
+	})
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryStopsOnPermanentErrors(t *testing.T) {
+	ctx := context.Background()
+	invalid := errors.New("invalid")
+
+	calls := 0
+	err := Retry(ctx, "test", fastRetry, func(context.Context) error {
+		calls++
+		return Permanent(invalid)
+	})
+	assert.Same(t, invalid, err)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	cfg := fastRetry
+	cfg.Retryable = func(err error) bool { return !errors.Is(err, invalid) }
+	assert.ErrorIs(t, Retry(ctx, "test", cfg, func(context.Context) error { calls++; return invalid }), invalid)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	assert.ErrorIs(t, Retry(ctx, "test", fastRetry, func(context.Context) error { calls++; return ErrOpen }), ErrOpen)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := RetryConfig{MaxAttempts: 5, InitialBackoff: time.Hour}
+
+	calls := 0
+	err := Retry(ctx, "test", cfg, func(context.Context) error {
+		calls++
+		cancel()
+		return errors.New("unavailable")
+	})
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, 1, calls)
+}
+
+func TestJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := jitter(time.Second)
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+		assert.LessOrEqual(t, d, time.Second)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	ctx := context.Background()
+	policy := NewPolicy("smtp", Config{
+		Timeout: 10 * time.Millisecond,
+		Retry:   fastRetry,
+		Breaker: BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute},
+	})
+
+	// Attempts time out, opening the breaker on the second; the third is
+	// rejected without calling
+	calls := 0
+	err := policy.Do(ctx, func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, ErrOpen)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, StateOpen, policy.Breaker().State())
+
+	var b strings.Builder
+	_, err = metrics.Default.WriteTo(&b)
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `circuit_breaker_state{breaker="smtp"} 2`)
+	assert.Contains(t, b.String(), `circuit_breaker_rejected_total{breaker="smtp"} 1`)
+	assert.Contains(t, b.String(), `retries_total{operation="smtp"} 2`)
+}
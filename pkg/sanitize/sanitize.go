@@ -0,0 +1,95 @@
+// Package sanitize normalizes request input according to struct tags, so a
+// DTO declares how its fields are cleaned next to how they are validated:
+//
+//	Email string `json:"email" sanitize:"trim,lower" validate:"required,email"`
+//
+// Rules apply in order to string fields, pointers to strings and slices of
+// strings. Nested structs, including embedded ones, are sanitized by their
+// own tags.
+package sanitize
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// TagName is the struct tag listing the rules of a field
+const TagName = "sanitize"
+
+// htmlTag matches HTML tags and comments
+var htmlTag = regexp.MustCompile(`<!--[\s\S]*?-->|</?[a-zA-Z][^<>]*>`)
+
+// rules are the supported rules by name
+var rules = map[string]func(string) string{
+	// trim removes leading and trailing white space
+	"trim": strings.TrimSpace,
+	// lower converts to lower case, as for email addresses
+	"lower": strings.ToLower,
+	// upper converts to upper case, as for country codes
+	"upper": strings.ToUpper,
+	// strip_html removes HTML tags and comments, keeping their text
+	"strip_html": func(s string) string { return htmlTag.ReplaceAllString(s, "") },
+}
+
+// Struct sanitizes the struct ptr points to in place. Other values are left
+// alone. It panics on unknown rules, which are programming errors.
+func Struct(ptr any) {
+	value := reflect.ValueOf(ptr)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return
+	}
+	sanitizeValue(value.Elem(), nil)
+}
+
+// sanitizeValue applies rules to a string value, or the fields' own rules
+// to a struct value, following pointers and slices
+func sanitizeValue(value reflect.Value, fieldRules []func(string) string) {
+	switch value.Kind() {
+	case reflect.String:
+		if len(fieldRules) == 0 || !value.CanSet() {
+			return
+		}
+		s := value.String()
+		for _, rule := range fieldRules {
+			s = rule(s)
+		}
+		value.SetString(s)
+	case reflect.Pointer:
+		if !value.IsNil() {
+			sanitizeValue(value.Elem(), fieldRules)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			sanitizeValue(value.Index(i), fieldRules)
+		}
+	case reflect.Struct:
+		t := value.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() && !field.Anonymous {
+				continue
+			}
+			sanitizeValue(value.Field(i), parseRules(t, field))
+		}
+	}
+}
+
+// parseRules looks up the rules of a struct field's tag
+func parseRules(t reflect.Type, field reflect.StructField) []func(string) string {
+	tag := field.Tag.Get(TagName)
+	if tag == "" {
+		return nil
+	}
+
+	var fieldRules []func(string) string
+	for _, name := range strings.Split(tag, ",") {
+		rule, ok := rules[strings.TrimSpace(name)]
+		if !ok {
+			panic(fmt.Sprintf("sanitize: unknown rule %q on %s.%s", name, t.Name(), field.Name))
+		}
+		fieldRules = append(fieldRules, rule)
+	}
+	return fieldRules
+}
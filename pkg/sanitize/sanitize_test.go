@@ -0,0 +1,72 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type address struct {
+	Country string `sanitize:"trim,upper"`
+}
+
+type Audit struct {
+	Note string `sanitize:"trim"`
+}
+
+type request struct {
+	Audit
+	Email    string            `sanitize:"trim,lower"`
+	Name     *string           `sanitize:"strip_html,trim"`
+	Tags     []string          `sanitize:"trim"`
+	Password string            // untagged fields are kept as sent
+	Address  address           // nested structs use their own tags
+	Meta     map[string]string `sanitize:"trim"`
+	private  string
+}
+
+func TestStruct(t *testing.T) {
+	name := " <b>Alice</b> <!-- admin --> "
+	req := &request{
+		Audit:    Audit{Note: " note "},
+		Email:    "  Alice@Example.COM ",
+		Name:     &name,
+		Tags:     []string{" a ", "b "},
+		Password: " secret ",
+		Address:  address{Country: " cn "},
+		Meta:     map[string]string{"k": " v "},
+		private:  " x ",
+	}
+	Struct(req)
+
+	assert.Equal(t, "note", req.Note)
+	assert.Equal(t, "alice@example.com", req.Email)
+	assert.Equal(t, "Alice", *req.Name)
+	assert.Equal(t, []string{"a", "b"}, req.Tags)
+	assert.Equal(t, " secret ", req.Password)
+	assert.Equal(t, "CN", req.Address.Country)
+	assert.Equal(t, " v ", req.Meta["k"], "maps are left alone")
+	assert.Equal(t, " x ", req.private)
+}
+
+func TestStructIgnoresOtherValues(t *testing.T) {
+	req := request{Email: " A@B.C "}
+	Struct(req)
+	assert.Equal(t, " A@B.C ", req.Email)
+
+	var none *request
+	assert.NotPanics(t, func() { Struct(none) })
+	assert.NotPanics(t, func() { Struct(nil) })
+}
+
+func TestStructUnknownRule(t *testing.T) {
+	assert.PanicsWithValue(t, `sanitize: unknown rule "squash" on bad.Name`, func() {
+		Struct(&struct {
+			bad
+		}{})
+	})
+}
+
+type bad struct {
+	Name string `sanitize:"trim,squash"`
+}
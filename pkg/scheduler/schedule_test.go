@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseNext tests activation times of supported expressions
+func TestParseNext(t *testing.T) {
+	// Wednesday, just before midnight at the end of January
+	base := time.Date(2024, 1, 31, 23, 59, 30, 0, time.UTC)
+
+	cases := map[string]time.Time{
+		"* * * * *":      time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		"5/15 * * * *":   time.Date(2024, 2, 1, 0, 5, 0, 0, time.UTC),
+		"30 3 * * *":     time.Date(2024, 2, 1, 3, 30, 0, 0, time.UTC),
+		"0 0 29 2 *":     time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		"0 0 * * 7":      time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC),
+		"0 9 15 * 1-2":   time.Date(2024, 2, 5, 9, 0, 0, 0, time.UTC),
+		"0,30 8-9 * 3 *": time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+		"@hourly":        time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		"@every 90s":     base.Add(90 * time.Second),
+	}
+
+	for spec, want := range cases {
+		schedule, err := Parse(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, want, schedule.Next(base), spec)
+	}
+}
+
+// TestParseNextNeverMatches tests that impossible dates yield a zero time
+func TestParseNextNeverMatches(t *testing.T) {
+	schedule, err := Parse("0 12 31 4 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+// TestParseInvalid tests rejection of malformed expressions
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every -1s"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
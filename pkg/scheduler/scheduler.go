@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ScheduledTask is a unit of background work run on a schedule
+type ScheduledTask interface {
+	// Name returns a unique, stable task name used in logs and configuration
+	Name() string
+
+	// Schedule returns the schedule specification understood by Parse
+	Schedule() string
+
+	// Run executes the task once
+	Run(ctx context.Context) error
+}
+
+// Scheduler runs registered tasks on their schedules. Runs of the same
+// task never overlap: a run that is still in progress delays the next one
+type Scheduler struct {
+	mu      sync.Mutex
+	entries []*entry
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running bool
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// entry is a registered task with its parsed schedule
+type entry struct {
+	task     ScheduledTask
+	schedule Schedule
+}
+
+// New creates an empty scheduler
+func New() *Scheduler {
+	return &Scheduler{now: time.Now}
+}
+
+// Register adds a task. Tasks must be registered before Start
+func (s *Scheduler) Register(task ScheduledTask) error {
+	schedule, err := Parse(task.Schedule())
+	if err != nil {
+		return fmt.Errorf("task %s: %w", task.Name(), err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return fmt.Errorf("task %s: scheduler already started", task.Name())
+	}
+	for _, e := range s.entries {
+		if e.task.Name() == task.Name() {
+			return fmt.Errorf("task %s: already registered", task.Name())
+		}
+	}
+
+	s.entries = append(s.entries, &entry{task: task, schedule: schedule})
+	return nil
+}
+
+// Tasks returns the names of the registered tasks
+func (s *Scheduler) Tasks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.entries))
+	for _, e := range s.entries {
+		names = append(names, e.task.Name())
+	}
+	return names
+}
+
+// Start launches one goroutine per task
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.running = true
+
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go s.loop(ctx, e)
+	}
+}
+
+// Stop cancels pending runs and waits for in-flight ones to finish or ctx to expire
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.cancel()
+	s.running = false
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loop waits for each activation time and runs the task
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	defer s.wg.Done()
+
+	for {
+		next := e.schedule.Next(s.now())
+		if next.IsZero() {
+			logger.Named(logger.ModuleJobs).Warn("scheduled task has no future activation", zap.String("task", e.task.Name()))
+			return
+		}
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.run(ctx, e.task)
+		}
+	}
+}
+
+// run executes a task once, recovering from panics
+func (s *Scheduler) run(ctx context.Context, task ScheduledTask) {
+	start := s.now()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Named(logger.ModuleJobs).Error("scheduled task panicked",
+				zap.String("task", task.Name()),
+				zap.Any("panic", r),
+				zap.String("stack", string(debug.Stack())),
+			)
+		}
+	}()
+
+	if err := task.Run(ctx); err != nil {
+		logger.Named(logger.ModuleJobs).Error("scheduled task failed",
+			zap.String("task", task.Name()),
+			zap.Duration("duration", s.now().Sub(start)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	logger.Named(logger.ModuleJobs).Debug("scheduled task completed",
+		zap.String("task", task.Name()),
+		zap.Duration("duration", s.now().Sub(start)),
+	)
+}
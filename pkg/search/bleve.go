@@ -0,0 +1,113 @@
+//go:build bleve
+
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// bleveIndex embeds one Bleve index per collection below the configured
+// directory. Indexes are opened, or created, on first use.
+type bleveIndex struct {
+	dir     string
+	mu      sync.Mutex
+	indexes map[string]bleve.Index
+}
+
+// NewBleveIndex creates an embedded index storing its data under cfg.BlevePath
+func NewBleveIndex(cfg Config) (Index, error) {
+	if err := os.MkdirAll(cfg.BlevePath, 0o755); err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+	return &bleveIndex{
+		dir:     cfg.BlevePath,
+		indexes: make(map[string]bleve.Index),
+	}, nil
+}
+
+// Index adds or replaces the document in collection
+func (b *bleveIndex) Index(_ context.Context, collection string, doc Document) error {
+	index, err := b.collection(collection)
+	if err != nil {
+		return err
+	}
+	return index.Index(doc.ID, doc.Fields)
+}
+
+// Delete removes the document from collection
+func (b *bleveIndex) Delete(_ context.Context, collection, id string) error {
+	index, err := b.collection(collection)
+	if err != nil {
+		return err
+	}
+	return index.Delete(id)
+}
+
+// Search matches query against all fields, treating every term as a prefix
+func (b *bleveIndex) Search(ctx context.Context, collection, q string, offset, limit int) ([]string, int64, error) {
+	index, err := b.collection(collection)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	queries := []query.Query{bleve.NewMatchQuery(q)}
+	for _, term := range strings.Fields(strings.ToLower(q)) {
+		queries = append(queries, bleve.NewPrefixQuery(term))
+	}
+	request := bleve.NewSearchRequestOptions(bleve.NewDisjunctionQuery(queries...), limit, offset, false)
+
+	result, err := index.SearchInContext(ctx, request)
+	if err != nil {
+		return nil, 0, fmt.Errorf("search: %w", err)
+	}
+
+	ids := make([]string, len(result.Hits))
+	for i, hit := range result.Hits {
+		ids[i] = hit.ID
+	}
+	return ids, int64(result.Total), nil
+}
+
+// Close closes every open collection
+func (b *bleveIndex) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var errs []error
+	for name, index := range b.indexes {
+		errs = append(errs, index.Close())
+		delete(b.indexes, name)
+	}
+	return errors.Join(errs...)
+}
+
+// collection returns the index of collection, opening or creating it
+func (b *bleveIndex) collection(name string) (bleve.Index, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if index, ok := b.indexes[name]; ok {
+		return index, nil
+	}
+
+	path := filepath.Join(b.dir, name+".bleve")
+	index, err := bleve.Open(path)
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		index, err = bleve.New(path, bleve.NewIndexMapping())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("search: open %s index: %w", name, err)
+	}
+
+	b.indexes[name] = index
+	return index, nil
+}
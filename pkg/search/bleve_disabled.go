@@ -0,0 +1,11 @@
+//go:build !bleve
+
+package search
+
+import "errors"
+
+// NewBleveIndex reports that the embedded index is unavailable; the server
+// must be built with the bleve tag to use it
+func NewBleveIndex(Config) (Index, error) {
+	return nil, errors.New("search: the bleve driver requires building with -tags bleve")
+}
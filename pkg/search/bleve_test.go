@@ -0,0 +1,58 @@
+//go:build bleve
+
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBleveIndex(t *testing.T) {
+	ctx := context.Background()
+	cfg := Config{Driver: "bleve", BlevePath: t.TempDir()}
+	index, err := NewIndex(cfg)
+	require.NoError(t, err)
+
+	// Searching a collection that has no documents yet finds nothing
+	ids, total, err := index.Search(ctx, "users", "john", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	assert.Zero(t, total)
+
+	require.NoError(t, index.Index(ctx, "users", Document{ID: "1", Fields: map[string]string{"name": "John Doe", "email": "john@example.com"}}))
+	require.NoError(t, index.Index(ctx, "users", Document{ID: "2", Fields: map[string]string{"name": "Jane Roe", "email": "jane@example.com"}}))
+
+	ids, total, err = index.Search(ctx, "users", "JOHN", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids)
+	assert.Equal(t, int64(1), total)
+
+	// Terms match as prefixes
+	ids, _, err = index.Search(ctx, "users", "ja", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, ids)
+
+	// Collections are separate
+	_, total, err = index.Search(ctx, "projects", "john", 0, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	// Documents are kept on disk across restarts
+	require.NoError(t, index.Close())
+	index, err = NewIndex(cfg)
+	require.NoError(t, err)
+	defer index.Close()
+	_, total, err = index.Search(ctx, "users", "john", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	// Deleting a document twice is not an error
+	require.NoError(t, index.Delete(ctx, "users", "1"))
+	require.NoError(t, index.Delete(ctx, "users", "1"))
+	_, total, err = index.Search(ctx, "users", "john", 0, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
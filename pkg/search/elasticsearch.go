@@ -0,0 +1,151 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// elasticsearchIndex talks to the Elasticsearch REST API. Each collection
+// is stored in its own index named IndexPrefix + collection.
+type elasticsearchIndex struct {
+	baseURL  string
+	username string
+	password string
+	prefix   string
+	http     *http.Client
+}
+
+// NewElasticsearchIndex creates an index backed by an Elasticsearch cluster
+func NewElasticsearchIndex(cfg Config) (Index, error) {
+	if _, err := url.ParseRequestURI(cfg.ElasticsearchURL); err != nil {
+		return nil, fmt.Errorf("search: invalid elasticsearch URL: %w", err)
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: cfg.Timeout}
+	}
+	return &elasticsearchIndex{
+		baseURL:  strings.TrimRight(cfg.ElasticsearchURL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		prefix:   cfg.IndexPrefix,
+		http:     httpClient,
+	}, nil
+}
+
+// Index adds or replaces the document, waiting until it is searchable
+func (e *elasticsearchIndex) Index(ctx context.Context, collection string, doc Document) error {
+	path := "/" + e.index(collection) + "/_doc/" + url.PathEscape(doc.ID) + "?refresh=wait_for"
+	_, err := e.do(ctx, http.MethodPut, path, doc.Fields, nil)
+	return err
+}
+
+// Delete removes the document from collection
+func (e *elasticsearchIndex) Delete(ctx context.Context, collection, id string) error {
+	path := "/" + e.index(collection) + "/_doc/" + url.PathEscape(id) + "?refresh=wait_for"
+	status, err := e.do(ctx, http.MethodDelete, path, nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// Search matches query against all fields, treating the last term as a prefix
+func (e *elasticsearchIndex) Search(ctx context.Context, collection, query string, offset, limit int) ([]string, int64, error) {
+	body := map[string]interface{}{
+		"from":             offset,
+		"size":             limit,
+		"track_total_hits": true,
+		"_source":          false,
+		"query": map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  query,
+				"type":   "bool_prefix",
+				"fields": []string{"*"},
+			},
+		},
+	}
+
+	var result struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	status, err := e.do(ctx, http.MethodPost, "/"+e.index(collection)+"/_search", body, &result)
+	if status == http.StatusNotFound {
+		// Nothing has been indexed in the collection yet
+		return []string{}, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]string, len(result.Hits.Hits))
+	for i, hit := range result.Hits.Hits {
+		ids[i] = hit.ID
+	}
+	return ids, result.Hits.Total.Value, nil
+}
+
+// Close is a no-op; the HTTP client holds no resources that need releasing
+func (e *elasticsearchIndex) Close() error {
+	return nil
+}
+
+// index returns the Elasticsearch index name of collection
+func (e *elasticsearchIndex) index(collection string) string {
+	return url.PathEscape(strings.ToLower(e.prefix + collection))
+}
+
+// do sends a JSON request and decodes the response into out. Responses
+// outside 2xx are returned as errors together with their status.
+func (e *elasticsearchIndex) do(ctx context.Context, method, path string, in, out interface{}) (int, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return 0, fmt.Errorf("search: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, body)
+	if err != nil {
+		return 0, fmt.Errorf("search: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if e.username != "" {
+		req.SetBasicAuth(e.username, e.password)
+	}
+
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("search: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("search: elasticsearch responded %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("search: decode response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,130 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeElasticsearch keeps documents in memory and matches search queries by substring
+type fakeElasticsearch struct {
+	mu      sync.Mutex
+	indexes map[string]map[string]map[string]string
+}
+
+func (f *fakeElasticsearch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if user, pass, _ := r.BasicAuth(); user != "elastic" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	docs, exists := f.indexes[parts[0]]
+	switch {
+	case r.Method == http.MethodPut && len(parts) == 3:
+		var fields map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&fields)
+		if !exists {
+			docs = make(map[string]map[string]string)
+			f.indexes[parts[0]] = docs
+		}
+		docs[parts[2]] = fields
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodDelete && len(parts) == 3:
+		if _, ok := docs[parts[2]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(docs, parts[2])
+	case r.Method == http.MethodPost && parts[len(parts)-1] == "_search":
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body struct {
+			Query struct {
+				MultiMatch struct {
+					Query string `json:"query"`
+				} `json:"multi_match"`
+			} `json:"query"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		var hits []map[string]string
+		for id, fields := range docs {
+			for _, value := range fields {
+				if strings.Contains(strings.ToLower(value), strings.ToLower(body.Query.MultiMatch.Query)) {
+					hits = append(hits, map[string]string{"_id": id})
+					break
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"hits": map[string]interface{}{
+				"total": map[string]int{"value": len(hits)},
+				"hits":  hits,
+			},
+		})
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestElasticsearchIndex(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeElasticsearch{indexes: make(map[string]map[string]map[string]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	index, err := NewIndex(Config{
+		Driver:           "elasticsearch",
+		ElasticsearchURL: server.URL + "/",
+		Username:         "elastic",
+		Password:         "secret",
+		IndexPrefix:      "test_",
+		Timeout:          time.Second,
+	})
+	require.NoError(t, err)
+	defer index.Close()
+
+	// Searching a collection that has no index yet finds nothing
+	ids, total, err := index.Search(ctx, "users", "john", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	assert.Zero(t, total)
+
+	require.NoError(t, index.Index(ctx, "users", Document{ID: "1", Fields: map[string]string{"name": "John Doe"}}))
+	require.NoError(t, index.Index(ctx, "users", Document{ID: "2", Fields: map[string]string{"name": "Jane Roe"}}))
+	assert.Contains(t, fake.indexes, "test_users")
+
+	ids, total, err = index.Search(ctx, "users", "JOHN", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids)
+	assert.Equal(t, int64(1), total)
+
+	// Deleting a document twice is not an error
+	require.NoError(t, index.Delete(ctx, "users", "1"))
+	require.NoError(t, index.Delete(ctx, "users", "1"))
+	_, total, err = index.Search(ctx, "users", "john", 0, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	// Other failures are reported
+	unauthorized, err := NewElasticsearchIndex(Config{ElasticsearchURL: server.URL, Timeout: time.Second})
+	require.NoError(t, err)
+	assert.Error(t, unauthorized.Index(ctx, "users", Document{ID: "1"}))
+
+	_, err = NewIndex(Config{Driver: "solr"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Config holds search index configuration
+type Config struct {
+	Driver           string        `json:"driver" yaml:"driver"` // bleve, elasticsearch
+	BlevePath        string        `json:"bleve_path" yaml:"bleve_path"`
+	ElasticsearchURL string        `json:"elasticsearch_url" yaml:"elasticsearch_url"`
+	Username         string        `json:"username" yaml:"username"`
+	Password         string        `json:"password" yaml:"password"`
+	IndexPrefix      string        `json:"index_prefix" yaml:"index_prefix"`
+	Timeout          time.Duration `json:"timeout" yaml:"timeout"`
+	// HTTPClient sends Elasticsearch requests; a client with Timeout is
+	// used when nil
+	HTTPClient *http.Client `json:"-" yaml:"-"`
+}
+
+// Document is an entity stored in a collection of the index. Every field
+// is searchable as text.
+type Document struct {
+	ID     string
+	Fields map[string]string
+}
+
+// Index stores documents in named collections and searches them
+type Index interface {
+	// Index adds or replaces the document in collection
+	Index(ctx context.Context, collection string, doc Document) error
+
+	// Delete removes the document from collection; missing documents are ignored
+	Delete(ctx context.Context, collection, id string) error
+
+	// Search returns the IDs of the documents matching query, best match
+	// first, and the total number of matches
+	Search(ctx context.Context, collection, query string, offset, limit int) ([]string, int64, error)
+
+	// Close releases the underlying resources
+	Close() error
+}
+
+// NewIndex creates a search index for the configured driver
+func NewIndex(cfg Config) (Index, error) {
+	switch cfg.Driver {
+	case "bleve":
+		return NewBleveIndex(cfg)
+	case "elasticsearch":
+		return NewElasticsearchIndex(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported search driver: %s", cfg.Driver)
+	}
+}
@@ -0,0 +1,142 @@
+package signing
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "billing-secret"
+
+func newVerifier() *Verifier {
+	return NewVerifier(VerifierConfig{
+		Keys:      map[string]string{"billing": testSecret},
+		Tolerance: time.Minute,
+		Nonces:    cache.NewMemoryClient(),
+	})
+}
+
+// signedRequest returns a request signed by the billing service, as the
+// receiving server sees it
+func signedRequest(t *testing.T, signer *Signer) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/internal/invoices?notify=true", strings.NewReader(`{"amount":100}`))
+	require.NoError(t, signer.Sign(req))
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":100}`, string(body), "body is kept after signing")
+	req.Body = io.NopCloser(strings.NewReader(string(body)))
+	return req
+}
+
+func TestVerifyAcceptsSignedRequest(t *testing.T) {
+	req := signedRequest(t, NewSigner("billing", testSecret))
+
+	keyID, err := newVerifier().Verify(req)
+	require.NoError(t, err)
+	assert.Equal(t, "billing", keyID)
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":100}`, string(body), "body is kept after verifying")
+}
+
+func TestVerifyRejectsInvalidRequests(t *testing.T) {
+	signer := NewSigner("billing", testSecret)
+
+	tests := []struct {
+		name   string
+		modify func(req *http.Request)
+	}{
+		{"unsigned", func(req *http.Request) {
+			req.Header.Del(HeaderSignature)
+		}},
+		{"unknown key", func(req *http.Request) {
+			req.Header.Set(HeaderKeyID, "shipping")
+		}},
+		{"tampered body", func(req *http.Request) {
+			req.Body = io.NopCloser(strings.NewReader(`{"amount":1000}`))
+		}},
+		{"tampered query", func(req *http.Request) {
+			req.URL.RawQuery = "notify=false"
+		}},
+		{"tampered method", func(req *http.Request) {
+			req.Method = http.MethodDelete
+		}},
+		{"missing nonce", func(req *http.Request) {
+			req.Header.Del(HeaderNonce)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := signedRequest(t, signer)
+			tt.modify(req)
+			_, err := newVerifier().Verify(req)
+			assert.ErrorIs(t, err, ErrInvalidSignature)
+		})
+	}
+
+	t.Run("wrong secret", func(t *testing.T) {
+		req := signedRequest(t, NewSigner("billing", "guessed-secret"))
+		_, err := newVerifier().Verify(req)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+}
+
+func TestVerifyRejectsStaleRequests(t *testing.T) {
+	signer := NewSigner("billing", testSecret)
+	verifier := newVerifier()
+
+	signer.now = func() time.Time { return time.Now().Add(-2 * time.Minute) }
+	_, err := verifier.Verify(signedRequest(t, signer))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	signer.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, err = verifier.Verify(signedRequest(t, signer))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerifyRejectsReplayedRequests(t *testing.T) {
+	verifier := newVerifier()
+	req := signedRequest(t, NewSigner("billing", testSecret))
+	body, _ := io.ReadAll(req.Body)
+
+	replay := req.Clone(req.Context())
+	replay.Body = io.NopCloser(strings.NewReader(string(body)))
+	req.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	_, err := verifier.Verify(req)
+	require.NoError(t, err)
+	_, err = verifier.Verify(replay)
+	assert.ErrorIs(t, err, ErrReplayed)
+}
+
+func TestTransportSignsEveryRequest(t *testing.T) {
+	verifier := newVerifier()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := verifier.Verify(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewSigner("billing", testSecret).Transport(http.DefaultTransport)}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Post(server.URL+"/internal/invoices", "application/json", strings.NewReader(`{"amount":100}`))
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Equal(t, `{"amount":100}`, string(body))
+	}
+}
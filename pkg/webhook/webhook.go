@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/luxixing/fx-gin-scaffold/pkg/resilience"
+)
+
+// Request headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// signaturePrefix names the signature algorithm in HeaderSignature
+const signaturePrefix = "sha256="
+
+// ErrInvalidSignature is returned when a signature does not match the payload
+var ErrInvalidSignature = errors.New("webhook: invalid signature")
+
+// Request is a signed event to post to an endpoint
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string
+	Body       []byte
+}
+
+// StatusError is returned for responses outside 2xx
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook: unexpected status %d", e.StatusCode)
+}
+
+// Client posts signed JSON payloads to webhook endpoints
+type Client struct {
+	http *http.Client
+	// breakers hold a circuit breaker per endpoint host; nil disables them
+	breakers *resilience.BreakerGroup
+}
+
+// NewClient creates a client posting with httpClient, which should time out
+// requests and not retry them: deliveries are retried by the caller
+func NewClient(httpClient *http.Client) *Client {
+	return &Client{
+		http: httpClient,
+	}
+}
+
+// WithBreakers stops posting to an endpoint host for a while once it keeps
+// failing, returning resilience.ErrOpen instead. Unreachable endpoints,
+// 429 and 5xx responses count as failures.
+func (c *Client) WithBreakers(cfg resilience.BreakerConfig) *Client {
+	cfg.IsFailure = isEndpointFailure
+	c.breakers = resilience.NewBreakerGroup("webhook", cfg)
+	return c
+}
+
+// Send posts the request body and returns the response status. Responses
+// outside 2xx are returned as *StatusError together with their status.
+func (c *Client) Send(ctx context.Context, r *Request) (int, error) {
+	endpoint, err := url.Parse(r.URL)
+	if c.breakers == nil || err != nil {
+		return c.send(ctx, r)
+	}
+
+	var status int
+	err = c.breakers.Get(endpoint.Host).Execute(ctx, func(ctx context.Context) error {
+		var err error
+		status, err = c.send(ctx, r)
+		return err
+	})
+	return status, err
+}
+
+// isEndpointFailure reports whether an error means the endpoint is down or
+// overloaded, rather than rejecting the request
+func isEndpointFailure(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	return true
+}
+
+// send posts the request
+func (c *Client) send(ctx context.Context, r *Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, fmt.Errorf("webhook: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fx-gin-scaffold-webhook/1.0")
+	req.Header.Set(HeaderEvent, r.Event)
+	req.Header.Set(HeaderDelivery, r.DeliveryID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(r.Secret, timestamp, r.Body))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain a bounded amount so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &StatusError{StatusCode: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the HeaderSignature value for a payload: the hex HMAC-SHA256
+// of "<timestamp>.<body>" keyed with the webhook secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a received payload.
+// Receivers should reject payloads signed longer than tolerance ago to
+// prevent replays.
+func Verify(secret, timestamp, signature string, body []byte, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
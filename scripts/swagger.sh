@@ -0,0 +1,28 @@
+#!/bin/sh
+
+# Generates the OpenAPI documentation in docs/swagger from the handler
+# annotations. Uses swag from PATH when installed, otherwise the version
+# pinned in go.mod, so the output matches the runtime library.
+
+set -e
+
+cd "$(dirname "$0")/.."
+
+SWAG_VERSION=$(go list -m -f '{{.Version}}' github.com/swaggo/swag)
+
+if command -v swag >/dev/null 2>&1; then
+    SWAG="swag"
+elif [ -x "$(go env GOPATH)/bin/swag" ]; then
+    SWAG="$(go env GOPATH)/bin/swag"
+else
+    SWAG="go run github.com/swaggo/swag/cmd/swag@${SWAG_VERSION}"
+fi
+
+echo "📝 Generating Swagger documentation..."
+$SWAG init \
+    --generalInfo ./cmd/server/main.go \
+    --output ./docs/swagger \
+    --outputTypes go,json,yaml \
+    --parseInternal \
+    "$@"
+echo "✅ Swagger documentation generated in docs/swagger"
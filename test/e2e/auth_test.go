@@ -0,0 +1,146 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// confirmTokenPattern finds the token of an email confirmation link
+var confirmTokenPattern = regexp.MustCompile(`token=([^\s"&<]+)`)
+
+// requireStatus asserts that err is an API error with the given status
+func requireStatus(t *testing.T, err error, status int) {
+	t.Helper()
+
+	var apiErr *client.Error
+	require.True(t, errors.As(err, &apiErr), "expected an API error, got %v", err)
+	assert.Equal(t, status, apiErr.StatusCode, apiErr.Error())
+}
+
+func TestAuthFlow(t *testing.T) {
+	ctx := context.Background()
+	app := Start(t)
+
+	// Registration signs the new user in and sends a welcome email
+	email := UniqueEmail("register")
+	c := app.Client(t)
+	auth, err := c.Register(ctx, &domain.UserCreateRequest{Email: email, Password: "first-password", Name: "New User"})
+	require.NoError(t, err)
+	assert.Equal(t, email, auth.User.Email)
+	assert.Equal(t, domain.RoleUser, auth.User.Role)
+	assert.NotEmpty(t, c.Tokens().RefreshToken)
+
+	var welcomed bool
+	for _, msg := range app.Mail(t) {
+		welcomed = welcomed || msg.To[0] == email
+	}
+	assert.True(t, welcomed, "welcome email sent")
+
+	_, err = app.Client(t).Register(ctx, &domain.UserCreateRequest{Email: email, Password: "first-password", Name: "Again"})
+	requireStatus(t, err, http.StatusConflict)
+
+	// Binding errors name the invalid fields, in the language the client
+	// accepts
+	_, err = app.Client(t).Register(ctx, &domain.UserCreateRequest{Email: "not-an-email", Password: "first-password", Name: "New User"})
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, []domain.FieldError{{Field: "email", Rule: "email", Message: "must be a valid email address"}}, apiErr.Err.Fields)
+	register := func(body string) []domain.FieldError {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, app.Server.URL+"/api/v1/auth/register", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9")
+		req.Header.Set("X-Response-Format", "envelope")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var envelope domain.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&envelope))
+		return envelope.Error.Fields
+	}
+	assert.Equal(t, []domain.FieldError{{Field: "password", Rule: "type", Message: "必须是字符串"}},
+		register(`{"email":"typed@example.com","password":12345678,"name":"Typed"}`))
+	assert.Equal(t, []domain.FieldError{{Field: "name", Rule: "min", Message: "长度不能少于 2 个字符"}},
+		register(`{"email":"short@example.com","password":"first-password","name":"A"}`))
+
+	// Profile
+	name := "Renamed User"
+	profile, err := c.UpdateProfile(ctx, &domain.UserUpdateRequest{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, name, profile.Name)
+
+	profile, err = c.GetProfile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, name, profile.Name)
+
+	// Logging in starts another session
+	_, err = app.Client(t).Login(ctx, &domain.UserLoginRequest{Email: email, Password: "wrong-password"})
+	requireStatus(t, err, http.StatusUnauthorized)
+
+	second := app.Login(t, email, "first-password")
+	sessions, err := c.ListSessions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	// Changing the password signs out every session
+	require.NoError(t, c.ChangePassword(ctx, &domain.ChangePasswordRequest{OldPassword: "first-password", NewPassword: "second-password"}))
+	_, err = second.RefreshToken(ctx)
+	requireStatus(t, err, http.StatusUnauthorized)
+	_, err = app.Client(t).Login(ctx, &domain.UserLoginRequest{Email: email, Password: "first-password"})
+	requireStatus(t, err, http.StatusUnauthorized)
+	c = app.Login(t, email, "second-password")
+
+	// Changing the email takes effect once the mailed link is followed
+	newEmail := UniqueEmail("changed")
+	profile, err = c.RequestEmailChange(ctx, &domain.EmailChangeRequest{Email: newEmail, Password: "second-password"})
+	require.NoError(t, err)
+	assert.Equal(t, newEmail, profile.PendingEmail)
+
+	mail := app.Mail(t)
+	last := mail[len(mail)-1]
+	assert.Equal(t, []string{newEmail}, last.To)
+	match := confirmTokenPattern.FindStringSubmatch(last.TextBody)
+	require.Len(t, match, 2, "confirmation link in %q", last.TextBody)
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+
+	profile, err = app.Client(t).ConfirmEmailChange(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, newEmail, profile.Email)
+	app.Login(t, newEmail, "second-password")
+
+	// Logging out revokes both tokens of the session
+	tokens := c.Tokens()
+	require.NoError(t, c.Logout(ctx))
+	stale := app.Client(t)
+	stale.SetTokens(tokens)
+	_, err = stale.GetProfile(ctx)
+	requireStatus(t, err, http.StatusUnauthorized)
+
+	// Refreshing rotates the refresh token; reusing a rotated one signs out
+	// every session of the user
+	session := app.Login(t, newEmail, "second-password")
+	before := session.Tokens()
+	after, err := session.RefreshToken(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, before.RefreshToken, after.RefreshToken)
+
+	replayed := app.Client(t)
+	replayed.SetTokens(domain.TokenPair{RefreshToken: before.RefreshToken})
+	_, err = replayed.RefreshToken(ctx)
+	requireStatus(t, err, http.StatusUnauthorized)
+	_, err = session.RefreshToken(ctx)
+	requireStatus(t, err, http.StatusUnauthorized)
+}
@@ -0,0 +1,26 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeta(t *testing.T) {
+	ctx := context.Background()
+	app := Start(t)
+	c := app.Client(t)
+
+	meta, err := c.Meta(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, meta.Version)
+	assert.Equal(t, config.RegistrationModeOpen, meta.Features.RegistrationMode)
+	assert.NotNil(t, meta.Features.FeatureFlags)
+
+	cfg, err := c.MetaConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, config.RegistrationModeOpen, cfg.RegistrationMode)
+}
@@ -0,0 +1,131 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/luxixing/fx-gin-scaffold/internal/domain"
+	"github.com/luxixing/fx-gin-scaffold/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserManagement(t *testing.T) {
+	ctx := context.Background()
+	app := Start(t)
+
+	admin, adminUser := app.LoginAs(t, domain.RoleAdmin)
+	member, memberUser := app.LoginAs(t, domain.RoleUser)
+	other := app.CreateUser(t, UserFixture{Name: "Other User"})
+
+	// Users may read themselves but nobody else
+	self, err := member.GetUser(ctx, memberUser.ID)
+	require.NoError(t, err)
+	assert.Equal(t, memberUser.Email, self.Email)
+
+	_, err = member.GetUser(ctx, other.ID)
+	requireStatus(t, err, http.StatusForbidden)
+	_, _, err = member.ListUsers(ctx, nil, nil)
+	requireStatus(t, err, http.StatusForbidden)
+	err = member.DeleteUser(ctx, other.ID)
+	requireStatus(t, err, http.StatusForbidden)
+
+	// Listing with filters and pagination
+	users, meta, err := admin.ListUsers(ctx, nil, &domain.PaginationRequest{Page: 1, Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.GreaterOrEqual(t, meta.Total, int64(3))
+
+	users, _, err = admin.ListUsers(ctx, &domain.UserListFilter{Role: domain.RoleAdmin}, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, users)
+	for _, user := range users {
+		assert.Equal(t, domain.RoleAdmin, user.Role)
+	}
+
+	// Sparse fieldsets
+	getFields := func(path string) (int, json.RawMessage) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, app.Server.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+admin.Tokens().AccessToken)
+		req.Header.Set("X-Response-Format", "envelope")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body struct {
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body.Data
+	}
+	status, data := getFields("/api/v1/users?limit=2&fields=email,id")
+	require.Equal(t, http.StatusOK, status)
+	var sparse []map[string]any
+	require.NoError(t, json.Unmarshal(data, &sparse))
+	require.Len(t, sparse, 2)
+	for _, user := range sparse {
+		assert.ElementsMatch(t, []string{"id", "email"}, keys(user))
+	}
+	status, data = getFields("/api/v1/auth/profile?fields=name")
+	require.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"name":"`+adminUser.Name+`"}`, string(data))
+	status, _ = getFields("/api/v1/users?fields=id,password")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Partial updates with JSON Merge Patch and JSON Patch
+	patched, err := admin.PatchUser(ctx, other.ID, &domain.Patch{
+		Type:     domain.PatchTypeMerge,
+		Document: []byte(`{"role":"moderator","metadata":{"team":"support"}}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleModerator, patched.Role)
+	assert.Equal(t, "Other User", patched.Name)
+
+	profile, err := member.PatchProfile(ctx, &domain.Patch{
+		Type:     domain.PatchTypeJSON,
+		Document: []byte(`[{"op":"replace","path":"/name","value":"Patched Member"},{"op":"add","path":"/metadata/theme","value":"dark"}]`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Patched Member", profile.Name)
+	assert.Equal(t, map[string]interface{}{"theme": "dark"}, profile.Metadata)
+
+	_, err = member.PatchProfile(ctx, &domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`{"role":"admin"}`)})
+	requireStatus(t, err, http.StatusBadRequest)
+	_, err = member.PatchUser(ctx, other.ID, &domain.Patch{Type: domain.PatchTypeMerge, Document: []byte(`{"name":"Mallory"}`)})
+	requireStatus(t, err, http.StatusForbidden)
+
+	// Deactivated users cannot sign in
+	inactive := false
+	updated, err := admin.UpdateUser(ctx, other.ID, &domain.UserUpdateRequest{Active: &inactive})
+	require.NoError(t, err)
+	assert.False(t, updated.Active)
+	_, err = app.Client(t).Login(ctx, &domain.UserLoginRequest{Email: other.Email, Password: DefaultPassword})
+	assert.Error(t, err)
+
+	// and are left out of the list unless included
+	users, _, err = admin.ListUsers(ctx, nil, nil)
+	require.NoError(t, err)
+	for _, user := range users {
+		assert.NotEqual(t, other.ID, user.ID)
+	}
+	users, _, err = admin.ListUsers(ctx, &domain.UserListFilter{IncludeInactive: true}, nil)
+	require.NoError(t, err)
+	assert.True(t, slices.ContainsFunc(users, func(user *domain.UserResponse) bool { return user.ID == other.ID }))
+
+	// Deleting
+	require.NoError(t, admin.DeleteUser(ctx, other.ID))
+	_, err = admin.GetUser(ctx, other.ID)
+	assert.True(t, client.IsNotFound(err))
+}
+
+// keys returns the keys of a decoded JSON object
+func keys(object map[string]any) []string {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	return names
+}